@@ -11,14 +11,13 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
-
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 )
 
 func (s *Server) AddReplicationEntries(ctx context.Context, req *idl.AddReplicationEntriesRequest) (*idl.AddReplicationEntriesReply, error) {
-	gplog.Info("agent received request to add replication entries to pg_hba.conf")
+	log.Info(ctx, "agent received request to add replication entries to pg_hba.conf")
 
 	err := AddReplicationEntriesToPgHbaConf(req.GetEntries())
 	if err != nil {
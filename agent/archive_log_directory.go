@@ -6,21 +6,20 @@ package agent
 import (
 	"context"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
-
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 )
 
 func (s *Server) ArchiveLogDirectory(ctx context.Context, in *idl.ArchiveLogDirectoryRequest) (*idl.ArchiveLogDirectoryReply, error) {
-	gplog.Info("agent starting %s", idl.Substep_ARCHIVE_LOG_DIRECTORIES)
+	log.Info(ctx, "agent starting %s", idl.Substep_ARCHIVE_LOG_DIRECTORIES)
 
 	logdir, err := utils.GetLogDir()
 	if err != nil {
 		return &idl.ArchiveLogDirectoryReply{}, err
 	}
 
-	gplog.Debug("moving directory %q to %q", logdir, in.GetNewDir())
+	log.Debug(ctx, "moving directory %q to %q", logdir, in.GetNewDir())
 	err = utils.Move(logdir, in.GetNewDir())
 	return &idl.ArchiveLogDirectoryReply{}, err
 }
@@ -5,15 +5,24 @@ package agent
 
 import (
 	"context"
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
 
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/utils/disk"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 )
 
 func (s *Server) CheckDiskSpace(ctx context.Context, in *idl.CheckSegmentDiskSpaceRequest) (*idl.CheckDiskSpaceReply, error) {
-	gplog.Info("agent received request to %s", idl.Substep_CHECK_DISK_SPACE)
+	log.Info(ctx, "agent received request to %s", idl.Substep_CHECK_DISK_SPACE)
+
+	if len(in.GetEstimates()) > 0 {
+		estimated, err := disk.EstimateUsage(step.DevNullStream, disk.Local, in.GetSafetyMargin(), in.GetEstimates()...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &idl.CheckDiskSpaceReply{Usage: estimated.Insufficient(), Estimated: estimated}, nil
+	}
 
 	usage, err := disk.CheckUsage(step.DevNullStream, disk.Local, in.GetDiskFreeRatio(), in.GetDirs()...)
 	if err != nil {
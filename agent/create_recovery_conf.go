@@ -10,14 +10,13 @@ import (
 	"path/filepath"
 	"sync"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
-
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 )
 
 func (s *Server) CreateRecoveryConf(ctx context.Context, req *idl.CreateRecoveryConfRequest) (*idl.CreateRecoveryConfReply, error) {
-	gplog.Info("agent received request to create recovery.conf")
+	log.Info(ctx, "agent received request to create recovery.conf")
 
 	err := createRecoveryConf(req.GetConnections())
 	if err != nil {
@@ -0,0 +1,29 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+)
+
+func (s *Server) GetPgUpgradeLogs(ctx context.Context, in *idl.GetPgUpgradeLogsRequest) (*idl.GetPgUpgradeLogsReply, error) {
+	log.Info(ctx, "%sagent received request to get the pg_upgrade logs", log.ContentPrefix(in.GetContentID()))
+
+	dir, err := utils.GetPgUpgradeDir(in.GetRole(), in.GetContentID())
+	if err != nil {
+		return &idl.GetPgUpgradeLogsReply{}, err
+	}
+
+	logs, err := upgrade.PgUpgradeLogs(dir)
+	if err != nil {
+		return &idl.GetPgUpgradeLogsReply{}, err
+	}
+
+	return &idl.GetPgUpgradeLogsReply{Logs: logs}, nil
+}
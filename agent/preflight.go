@@ -0,0 +1,18 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/preflight"
+)
+
+func (s *Server) Preflight(ctx context.Context, in *idl.PreflightRequest) (*idl.PreflightReply, error) {
+	log.Info(ctx, "agent received request to %s", idl.Substep_PREFLIGHT_CHECKS)
+
+	return &idl.PreflightReply{Checks: preflight.Run(ctx, in)}, nil
+}
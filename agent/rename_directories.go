@@ -6,17 +6,16 @@ package agent
 import (
 	"context"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
-
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 )
 
 var RenameDirectories = upgrade.RenameDirectories
 
 func (s *Server) RenameDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
-	gplog.Info("agent received request to rename segment data directories")
+	log.Info(ctx, "agent received request to rename segment data directories")
 
 	var mErr error
 	for _, dir := range in.GetDirs() {
@@ -28,3 +27,19 @@ func (s *Server) RenameDirectories(ctx context.Context, in *idl.RenameDirectorie
 
 	return &idl.RenameDirectoriesReply{}, mErr
 }
+
+var UndoRenameDirectories = upgrade.UndoRenameDirectories
+
+func (s *Server) UndoRenameDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
+	log.Info(ctx, "agent received request to restore renamed segment data directories")
+
+	var mErr error
+	for _, dir := range in.GetDirs() {
+		err := UndoRenameDirectories(dir.GetSource(), dir.GetTarget())
+		if err != nil {
+			mErr = errorlist.Append(mErr, err)
+		}
+	}
+
+	return &idl.RenameDirectoriesReply{}, mErr
+}
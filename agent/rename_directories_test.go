@@ -30,3 +30,21 @@ func TestRenameDirectories(t *testing.T) {
 		}
 	})
 }
+
+func TestUndoRenameDirectories(t *testing.T) {
+	testlog.SetupLogger()
+	server := agent.NewServer(agent.Config{})
+
+	t.Run("bubbles up errors", func(t *testing.T) {
+		expected := errors.New("permission denied")
+		agent.UndoRenameDirectories = func(source, target string) error {
+			return expected
+		}
+
+		_, err := server.UndoRenameDirectories(context.Background(), &idl.RenameDirectoriesRequest{Dirs: []*idl.RenameDirectories{{}}})
+
+		if !errors.Is(err, expected) {
+			t.Errorf("returned error %#v, want %#v", err, expected)
+		}
+	})
+}
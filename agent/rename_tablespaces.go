@@ -14,10 +14,11 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 )
 
 func (s *Server) RenameTablespaces(ctx context.Context, req *idl.RenameTablespacesRequest) (*idl.RenameTablespacesReply, error) {
-	gplog.Info("agent received request to rename tablespaces")
+	log.Info(ctx, "agent received request to rename tablespaces")
 
 	err := renameTablespaces(req.GetRenamePairs())
 	if err != nil {
@@ -9,17 +9,17 @@ import (
 	"os"
 	"sync"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
-
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 	"github.com/greenplum-db/gpupgrade/utils/rsync"
 )
 
 func (s *Server) RsyncDataDirectories(ctx context.Context, in *idl.RsyncRequest) (*idl.RsyncReply, error) {
-	gplog.Info("agent received request to rsync data directories")
+	log.Info(ctx, "agent received request to rsync data directories")
 
 	// verify source data directories
 	var mErr error
@@ -33,11 +33,12 @@ func (s *Server) RsyncDataDirectories(ctx context.Context, in *idl.RsyncRequest)
 		return &idl.RsyncReply{}, mErr
 	}
 
-	return &idl.RsyncReply{}, rsyncRequestDirs(in)
+	stats, err := rsyncRequestDirs(ctx, in)
+	return &idl.RsyncReply{Stats: stats}, err
 }
 
 func (s *Server) RsyncTablespaceDirectories(ctx context.Context, in *idl.RsyncRequest) (*idl.RsyncReply, error) {
-	gplog.Info("agent received request to rsync tablespace directories")
+	log.Info(ctx, "agent received request to rsync tablespace directories")
 
 	// We can only verify the source directories since the destination
 	// directories are on another host.
@@ -49,16 +50,99 @@ func (s *Server) RsyncTablespaceDirectories(ctx context.Context, in *idl.RsyncRe
 		}
 	}
 
-	return &idl.RsyncReply{}, rsyncRequestDirs(in)
+	stats, err := rsyncRequestDirs(ctx, in)
+	return &idl.RsyncReply{Stats: stats}, err
+}
+
+// ResyncMirrorDataDirectories rsyncs the upgraded primary data directories to
+// their mirrors reporting how much data was matched at the destination rather
+// than sent.
+func (s *Server) ResyncMirrorDataDirectories(ctx context.Context, in *idl.RsyncRequest) (*idl.ResyncReply, error) {
+	log.Info(ctx, "agent received request to resync mirror data directories")
+
+	var mErr error
+	for _, opts := range in.GetOptions() {
+		err := upgrade.VerifyDataDirectory(opts.GetSources()...)
+		if err != nil {
+			mErr = errorlist.Append(mErr, err)
+		}
+	}
+	if mErr != nil {
+		return &idl.ResyncReply{}, mErr
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		return &idl.ResyncReply{}, err
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan *idl.ResyncStats, len(in.GetOptions()))
+	errs := make(chan error, len(in.GetOptions()))
+
+	for _, opts := range in.GetOptions() {
+		opts := opts
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			streams := &step.BufferedStreams{}
+			err := rsync.Rsync(
+				rsync.WithContext(ctx),
+				rsync.WithSources(opts.GetSources()...),
+				rsync.WithDestinationHost(opts.GetDestinationHost()),
+				rsync.WithDestination(opts.GetDestination()),
+				rsync.WithOptions(opts.GetOptions()...),
+				rsync.WithExcludedFiles(opts.GetExcludedFiles()...),
+				rsync.WithStream(streams),
+			)
+			if err != nil {
+				errs <- fmt.Errorf("on host %q: %w: %s", hostname, err, streams.StderrBuf.String())
+				return
+			}
+
+			stats, err := rsync.ParseStats(streams.StdoutBuf.String())
+			if err != nil {
+				errs <- fmt.Errorf("on host %q: %w", hostname, err)
+				return
+			}
+
+			results <- &idl.ResyncStats{
+				DestinationHost: opts.GetDestinationHost(),
+				Destination:     opts.GetDestination(),
+				TotalBytes:      stats.TotalBytes,
+				LiteralBytes:    stats.LiteralBytes,
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+	close(errs)
+
+	for e := range errs {
+		err = errorlist.Append(err, e)
+	}
+
+	reply := &idl.ResyncReply{}
+	for stats := range results {
+		reply.Stats = append(reply.Stats, stats)
+	}
+
+	return reply, err
 }
 
-func rsyncRequestDirs(in *idl.RsyncRequest) error {
+// rsyncRequestDirs runs each rsync of the request. Those run with --stats
+// return their statistics.
+func rsyncRequestDirs(ctx context.Context, in *idl.RsyncRequest) ([]*idl.ResyncStats, error) {
 	hostname, err := os.Hostname()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var wg sync.WaitGroup
+	results := make(chan *idl.ResyncStats, len(in.GetOptions()))
 	errs := make(chan error, len(in.GetOptions()))
 
 	for _, opts := range in.GetOptions() {
@@ -68,26 +152,67 @@ func rsyncRequestDirs(in *idl.RsyncRequest) error {
 		go func() {
 			defer wg.Done()
 
-			opts := []rsync.Option{
+			options := []rsync.Option{
+				rsync.WithContext(ctx),
 				rsync.WithSources(opts.GetSources()...),
 				rsync.WithDestinationHost(opts.GetDestinationHost()),
 				rsync.WithDestination(opts.GetDestination()),
 				rsync.WithOptions(opts.GetOptions()...),
 				rsync.WithExcludedFiles(opts.GetExcludedFiles()...),
 			}
-			err := rsync.Rsync(opts...)
+
+			if !hasOption(opts.GetOptions(), "--stats") {
+				err := rsync.Rsync(options...)
+				if err != nil {
+					errs <- fmt.Errorf("on host %q: %w", hostname, err)
+				}
+				return
+			}
+
+			streams := &step.BufferedStreams{}
+			err := rsync.Rsync(append(options, rsync.WithStream(streams))...)
+			if err != nil {
+				errs <- fmt.Errorf("on host %q: %w: %s", hostname, err, streams.StderrBuf.String())
+				return
+			}
+
+			stats, err := rsync.ParseStats(streams.StdoutBuf.String())
 			if err != nil {
 				errs <- fmt.Errorf("on host %q: %w", hostname, err)
+				return
+			}
+
+			results <- &idl.ResyncStats{
+				DestinationHost: opts.GetDestinationHost(),
+				Destination:     opts.GetDestination(),
+				TotalBytes:      stats.TotalBytes,
+				LiteralBytes:    stats.LiteralBytes,
 			}
 		}()
 	}
 
 	wg.Wait()
+	close(results)
 	close(errs)
 
 	for e := range errs {
 		err = errorlist.Append(err, e)
 	}
 
-	return err
+	var stats []*idl.ResyncStats
+	for s := range results {
+		stats = append(stats, s)
+	}
+
+	return stats, err
+}
+
+func hasOption(options []string, option string) bool {
+	for _, o := range options {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
 }
@@ -19,6 +19,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 type Server struct {
@@ -60,9 +61,10 @@ func (s *Server) Start() {
 	// handlers.
 	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer log.WritePanics()
-		return handler(ctx, req)
+		ctx = log.WithFields(ctx, log.IncomingFields(ctx))
+		return trace.UnaryServerInterceptor(ctx, req, info, handler)
 	}
-	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor), grpc.StreamInterceptor(trace.StreamServerInterceptor))
 
 	s.mu.Lock()
 	s.server = server
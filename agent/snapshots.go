@@ -0,0 +1,31 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/snapshot"
+)
+
+func (s *Server) CreateSnapshots(ctx context.Context, in *idl.CreateSnapshotsRequest) (*idl.CreateSnapshotsReply, error) {
+	log.Info(ctx, "agent received request to create %s snapshots of %q", in.GetProvider(), in.GetDirs())
+
+	err := snapshot.Take(ctx, s.conf.StateDir, in.GetProvider(), in.GetName(), in.GetDirs())
+	return &idl.CreateSnapshotsReply{}, err
+}
+
+func (s *Server) RestoreSnapshots(ctx context.Context, in *idl.RestoreSnapshotsRequest) (*idl.RestoreSnapshotsReply, error) {
+	log.Info(ctx, "agent received request to restore snapshots")
+
+	return &idl.RestoreSnapshotsReply{}, snapshot.RestoreAll(ctx, s.conf.StateDir)
+}
+
+func (s *Server) DeleteSnapshots(ctx context.Context, in *idl.DeleteSnapshotsRequest) (*idl.DeleteSnapshotsReply, error) {
+	log.Info(ctx, "agent received request to delete snapshots")
+
+	return &idl.DeleteSnapshotsReply{}, snapshot.DeleteAll(ctx, s.conf.StateDir)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package agent
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/bundle"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+)
+
+func (s *Server) CreateSupportBundle(ctx context.Context, in *idl.CreateSupportBundleRequest) (*idl.CreateSupportBundleReply, error) {
+	log.Info(ctx, "agent received request to create a support bundle")
+
+	hostname, err := utils.System.Hostname()
+	if err != nil {
+		return &idl.CreateSupportBundleReply{}, err
+	}
+
+	logDir, err := utils.GetLogDir()
+	if err != nil {
+		return &idl.CreateSupportBundleReply{}, err
+	}
+
+	redactor := bundle.Redactor{Passwords: in.GetRedactPasswords(), Hostnames: in.GetHostnameAliases()}
+	sources := bundle.HostSources(s.conf.StateDir, logDir, in.GetDataDirs())
+	facts := bundle.HostFacts(in.GetGphomes(), in.GetDataDirs())
+
+	path := filepath.Join(s.conf.StateDir, bundle.HostBundleFileName)
+	err = bundle.WriteFile(path, hostname, sources, facts, redactor)
+	if err != nil {
+		return &idl.CreateSupportBundleReply{}, err
+	}
+
+	return &idl.CreateSupportBundleReply{Path: path}, nil
+}
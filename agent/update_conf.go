@@ -8,14 +8,13 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
-
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 )
 
 func (s *Server) UpdateConfiguration(ctx context.Context, req *idl.UpdateConfigurationRequest) (*idl.UpdateConfigurationReply, error) {
-	gplog.Info("agent received request to update configuration file")
+	log.Info(ctx, "agent received request to update configuration file")
 
 	hostname, err := os.Hostname()
 	if err != nil {
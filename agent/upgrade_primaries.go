@@ -11,7 +11,6 @@ import (
 	"strconv"
 	"sync"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/greenplum"
@@ -19,13 +18,14 @@ import (
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 	"github.com/greenplum-db/gpupgrade/utils/rsync"
 )
 
 func (s *Server) UpgradePrimaries(ctx context.Context, req *idl.UpgradePrimariesRequest) (*idl.UpgradePrimariesReply, error) {
-	gplog.Info("agent starting %s", req.GetAction())
+	log.Info(ctx, "agent starting %s", req.GetAction())
 
-	err := upgradePrimariesInParallel(req.GetOpts())
+	err := upgradePrimariesInParallel(ctx, req.GetOpts())
 	if err != nil {
 		return &idl.UpgradePrimariesReply{}, err
 	}
@@ -33,7 +33,7 @@ func (s *Server) UpgradePrimaries(ctx context.Context, req *idl.UpgradePrimaries
 	return &idl.UpgradePrimariesReply{}, nil
 }
 
-func upgradePrimariesInParallel(opts []*idl.PgOptions) error {
+func upgradePrimariesInParallel(ctx context.Context, opts []*idl.PgOptions) error {
 	host, err := utils.System.Hostname()
 	if err != nil {
 		return err
@@ -47,7 +47,7 @@ func upgradePrimariesInParallel(opts []*idl.PgOptions) error {
 		go func(host string, opt *idl.PgOptions) {
 			defer wg.Done()
 
-			errs <- upgradePrimarySegment(host, opt)
+			errs <- upgradePrimarySegment(ctx, host, opt)
 		}(host, opt)
 	}
 
@@ -61,20 +61,20 @@ func upgradePrimariesInParallel(opts []*idl.PgOptions) error {
 	return err
 }
 
-func upgradePrimarySegment(host string, opt *idl.PgOptions) error {
+func upgradePrimarySegment(ctx context.Context, host string, opt *idl.PgOptions) error {
 	if opt.GetAction() != idl.PgOptions_check {
-		err := restoreBackup(utils.GetCoordinatorPostUpgradeBackupDir(), opt.GetNewDataDir())
+		err := restoreBackup(ctx, utils.GetCoordinatorPostUpgradeBackupDir(), opt.GetNewDataDir())
 		if err != nil {
 			return xerrors.Errorf("restore backup of upgraded master data directory on host %s for content id %d: %w", host, opt.GetContentID(), err)
 		}
 
-		err = RestoreTablespaces(opt.GetTablespaces(), opt.GetOldDBID(), opt.GetNewDataDir())
+		err = RestoreTablespaces(ctx, opt.GetTablespaces(), opt.GetOldDBID(), opt.GetNewDataDir())
 		if err != nil {
 			return xerrors.Errorf("restore tablespace on host %s for content id %d: %w", host, opt.GetContentID(), err)
 		}
 	}
 
-	err := upgrade.Run(ioutil.Discard, ioutil.Discard, opt)
+	err := upgrade.Run(ctx, ioutil.Discard, ioutil.Discard, opt)
 	if err != nil {
 		return xerrors.Errorf("%s primary on host %s with content %d: %w", opt.GetAction(), host, opt.GetContentID(), err)
 	}
@@ -82,8 +82,9 @@ func upgradePrimarySegment(host string, opt *idl.PgOptions) error {
 	return nil
 }
 
-func restoreBackup(backupDir string, newDataDir string) error {
+func restoreBackup(ctx context.Context, backupDir string, newDataDir string) error {
 	options := []rsync.Option{
+		rsync.WithContext(ctx),
 		rsync.WithSources(backupDir + string(os.PathSeparator)),
 		rsync.WithDestination(newDataDir),
 		rsync.WithOptions("--archive", "--delete"),
@@ -100,7 +101,7 @@ func restoreBackup(backupDir string, newDataDir string) error {
 	return rsync.Rsync(options...)
 }
 
-func RestoreTablespaces(tablespaces map[int32]*idl.TablespaceInfo, oldDBID string, newDataDir string) error {
+func RestoreTablespaces(ctx context.Context, tablespaces map[int32]*idl.TablespaceInfo, oldDBID string, newDataDir string) error {
 	dbid, err := strconv.Atoi(oldDBID)
 	if err != nil {
 		return err
@@ -115,6 +116,7 @@ func RestoreTablespaces(tablespaces map[int32]*idl.TablespaceInfo, oldDBID strin
 		sourceDir := greenplum.GetCoordinatorTablespaceLocation(utils.GetTablespaceDir(), int(oid)) + string(os.PathSeparator)
 
 		options := []rsync.Option{
+			rsync.WithContext(ctx),
 			rsync.WithSources(sourceDir),
 			rsync.WithDestination(targetDir),
 			rsync.WithOptions("--archive", "--delete"),
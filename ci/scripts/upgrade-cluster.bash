@@ -37,8 +37,8 @@ time ssh -n mdw "
               --mode $MODE \
               --temp-port-range 6020-6040
 
-    gpupgrade execute --non-interactive
-    gpupgrade finalize --non-interactive
+    gpupgrade execute --non-interactive --confirm-point-of-no-return \$(gpupgrade config show --point-of-no-return-token)
+    gpupgrade finalize --non-interactive --confirm-point-of-no-return \$(gpupgrade config show --point-of-no-return-token)
 
     (source ${GPHOME_TARGET}/greenplum_path.sh && ${GPHOME_TARGET}/bin/gpstart -a -d /data/gpdata/master/gpseg-1)
 "
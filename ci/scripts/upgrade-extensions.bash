@@ -123,8 +123,8 @@ time ssh -n mdw "
               --temp-port-range 6020-6040 \
               --dynamic-library-path ${GPHOME_TARGET}/madlib/Current/ports/greenplum/6/lib:/usr/local/greenplum-db-text/lib/gpdb6:/usr/local/pxf-gp6/gpextable
 
-    gpupgrade execute --non-interactive
-    gpupgrade finalize --non-interactive
+    gpupgrade execute --non-interactive --confirm-point-of-no-return \$(gpupgrade config show --point-of-no-return-token)
+    gpupgrade finalize --non-interactive --confirm-point-of-no-return \$(gpupgrade config show --point-of-no-return-token)
 
     (source ${GPHOME_TARGET}/greenplum_path.sh && ${GPHOME_TARGET}/bin/gpstart -a -d /data/gpdata/master/gpseg-1)
 "
@@ -353,6 +353,36 @@ __gpupgrade_handle_word()
     __gpupgrade_handle_word
 }
 
+_gpupgrade_config_init()
+{
+    last_command="gpupgrade_config_init"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--file=")
+    two_word_flags+=("--file")
+    two_word_flags+=("-f")
+    local_nonpersistent_flags+=("--file")
+    local_nonpersistent_flags+=("--file=")
+    local_nonpersistent_flags+=("-f")
+    flags+=("--target-gphome=")
+    two_word_flags+=("--target-gphome")
+    local_nonpersistent_flags+=("--target-gphome")
+    local_nonpersistent_flags+=("--target-gphome=")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_config_show()
 {
     last_command="gpupgrade_config_show"
@@ -367,8 +397,14 @@ _gpupgrade_config_show()
     flags_with_completion=()
     flags_completion=()
 
+    flags+=("--format=")
+    two_word_flags+=("--format")
+    local_nonpersistent_flags+=("--format")
+    local_nonpersistent_flags+=("--format=")
     flags+=("--id")
     local_nonpersistent_flags+=("--id")
+    flags+=("--point-of-no-return-token")
+    local_nonpersistent_flags+=("--point-of-no-return-token")
     flags+=("--source-gphome")
     local_nonpersistent_flags+=("--source-gphome")
     flags+=("--target-datadir")
@@ -383,6 +419,34 @@ _gpupgrade_config_show()
     noun_aliases=()
 }
 
+_gpupgrade_config_validate()
+{
+    last_command="gpupgrade_config_validate"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--file=")
+    two_word_flags+=("--file")
+    two_word_flags+=("-f")
+    local_nonpersistent_flags+=("--file")
+    local_nonpersistent_flags+=("--file=")
+    local_nonpersistent_flags+=("-f")
+
+    must_have_one_flag=()
+    must_have_one_flag+=("--file=")
+    must_have_one_flag+=("-f")
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_config()
 {
     last_command="gpupgrade_config"
@@ -390,7 +454,9 @@ _gpupgrade_config()
     command_aliases=()
 
     commands=()
+    commands+=("init")
     commands+=("show")
+    commands+=("validate")
 
     flags=()
     two_word_flags=()
@@ -443,6 +509,14 @@ _gpupgrade_execute()
     flags+=("-?")
     local_nonpersistent_flags+=("--?")
     local_nonpersistent_flags+=("-?")
+    flags+=("--confirm-point-of-no-return=")
+    two_word_flags+=("--confirm-point-of-no-return")
+    local_nonpersistent_flags+=("--confirm-point-of-no-return")
+    local_nonpersistent_flags+=("--confirm-point-of-no-return=")
+    flags+=("--only-content=")
+    two_word_flags+=("--only-content")
+    local_nonpersistent_flags+=("--only-content")
+    local_nonpersistent_flags+=("--only-content=")
     flags+=("--verbose")
     flags+=("-v")
     local_nonpersistent_flags+=("--verbose")
@@ -492,6 +566,10 @@ _gpupgrade_finalize()
     flags+=("-?")
     local_nonpersistent_flags+=("--?")
     local_nonpersistent_flags+=("-?")
+    flags+=("--confirm-point-of-no-return=")
+    two_word_flags+=("--confirm-point-of-no-return")
+    local_nonpersistent_flags+=("--confirm-point-of-no-return")
+    local_nonpersistent_flags+=("--confirm-point-of-no-return=")
     flags+=("--verbose")
     flags+=("-v")
     local_nonpersistent_flags+=("--verbose")
@@ -569,6 +647,22 @@ _gpupgrade_initialize()
     flags+=("-a")
     local_nonpersistent_flags+=("--automatic")
     local_nonpersistent_flags+=("-a")
+    flags+=("--backup-command=")
+    two_word_flags+=("--backup-command")
+    local_nonpersistent_flags+=("--backup-command")
+    local_nonpersistent_flags+=("--backup-command=")
+    flags+=("--backup-provider=")
+    two_word_flags+=("--backup-provider")
+    local_nonpersistent_flags+=("--backup-provider")
+    local_nonpersistent_flags+=("--backup-provider=")
+    flags+=("--backup-timestamp=")
+    two_word_flags+=("--backup-timestamp")
+    local_nonpersistent_flags+=("--backup-timestamp")
+    local_nonpersistent_flags+=("--backup-timestamp=")
+    flags+=("--data-validation=")
+    two_word_flags+=("--data-validation")
+    local_nonpersistent_flags+=("--data-validation")
+    local_nonpersistent_flags+=("--data-validation=")
     flags+=("--disk-free-ratio=")
     two_word_flags+=("--disk-free-ratio")
     local_nonpersistent_flags+=("--disk-free-ratio")
@@ -587,10 +681,44 @@ _gpupgrade_initialize()
     two_word_flags+=("--hub-port")
     local_nonpersistent_flags+=("--hub-port")
     local_nonpersistent_flags+=("--hub-port=")
+    flags+=("--keep-temp-ports")
+    local_nonpersistent_flags+=("--keep-temp-ports")
+    flags+=("--log-format=")
+    two_word_flags+=("--log-format")
+    local_nonpersistent_flags+=("--log-format")
+    local_nonpersistent_flags+=("--log-format=")
+    flags+=("--metrics-port=")
+    two_word_flags+=("--metrics-port")
+    local_nonpersistent_flags+=("--metrics-port")
+    local_nonpersistent_flags+=("--metrics-port=")
+    flags+=("--mirror-resync=")
+    two_word_flags+=("--mirror-resync")
+    local_nonpersistent_flags+=("--mirror-resync")
+    local_nonpersistent_flags+=("--mirror-resync=")
     flags+=("--mode=")
     two_word_flags+=("--mode")
     local_nonpersistent_flags+=("--mode")
     local_nonpersistent_flags+=("--mode=")
+    flags+=("--notify-email=")
+    two_word_flags+=("--notify-email")
+    local_nonpersistent_flags+=("--notify-email")
+    local_nonpersistent_flags+=("--notify-email=")
+    flags+=("--notify-smtp=")
+    two_word_flags+=("--notify-smtp")
+    local_nonpersistent_flags+=("--notify-smtp")
+    local_nonpersistent_flags+=("--notify-smtp=")
+    flags+=("--notify-webhook=")
+    two_word_flags+=("--notify-webhook")
+    local_nonpersistent_flags+=("--notify-webhook")
+    local_nonpersistent_flags+=("--notify-webhook=")
+    flags+=("--port-strategy=")
+    two_word_flags+=("--port-strategy")
+    local_nonpersistent_flags+=("--port-strategy")
+    local_nonpersistent_flags+=("--port-strategy=")
+    flags+=("--snapshot-provider=")
+    two_word_flags+=("--snapshot-provider")
+    local_nonpersistent_flags+=("--snapshot-provider")
+    local_nonpersistent_flags+=("--snapshot-provider=")
     flags+=("--source-gphome=")
     two_word_flags+=("--source-gphome")
     local_nonpersistent_flags+=("--source-gphome")
@@ -599,14 +727,34 @@ _gpupgrade_initialize()
     two_word_flags+=("--source-master-port")
     local_nonpersistent_flags+=("--source-master-port")
     local_nonpersistent_flags+=("--source-master-port=")
+    flags+=("--target-datadir-layout=")
+    two_word_flags+=("--target-datadir-layout")
+    local_nonpersistent_flags+=("--target-datadir-layout")
+    local_nonpersistent_flags+=("--target-datadir-layout=")
     flags+=("--target-gphome=")
     two_word_flags+=("--target-gphome")
     local_nonpersistent_flags+=("--target-gphome")
     local_nonpersistent_flags+=("--target-gphome=")
+    flags+=("--target-host-mapping=")
+    two_word_flags+=("--target-host-mapping")
+    local_nonpersistent_flags+=("--target-host-mapping")
+    local_nonpersistent_flags+=("--target-host-mapping=")
+    flags+=("--target-port-map=")
+    two_word_flags+=("--target-port-map")
+    local_nonpersistent_flags+=("--target-port-map")
+    local_nonpersistent_flags+=("--target-port-map=")
     flags+=("--temp-port-range=")
     two_word_flags+=("--temp-port-range")
     local_nonpersistent_flags+=("--temp-port-range")
     local_nonpersistent_flags+=("--temp-port-range=")
+    flags+=("--trace-endpoint=")
+    two_word_flags+=("--trace-endpoint")
+    local_nonpersistent_flags+=("--trace-endpoint")
+    local_nonpersistent_flags+=("--trace-endpoint=")
+    flags+=("--trace-exporter=")
+    two_word_flags+=("--trace-exporter")
+    local_nonpersistent_flags+=("--trace-exporter")
+    local_nonpersistent_flags+=("--trace-exporter=")
     flags+=("--use-hba-hostnames")
     local_nonpersistent_flags+=("--use-hba-hostnames")
     flags+=("--verbose")
@@ -619,6 +767,30 @@ _gpupgrade_initialize()
     noun_aliases=()
 }
 
+_gpupgrade_inventory()
+{
+    last_command="gpupgrade_inventory"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--dir=")
+    two_word_flags+=("--dir")
+    local_nonpersistent_flags+=("--dir")
+    local_nonpersistent_flags+=("--dir=")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_kill-services()
 {
     last_command="gpupgrade_kill-services"
@@ -708,6 +880,74 @@ _gpupgrade_revert()
     noun_aliases=()
 }
 
+_gpupgrade_segments()
+{
+    last_command="gpupgrade_segments"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_support-bundle()
+{
+    last_command="gpupgrade_support-bundle"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--dir=")
+    two_word_flags+=("--dir")
+    local_nonpersistent_flags+=("--dir")
+    local_nonpersistent_flags+=("--dir=")
+    flags+=("--redact-hostnames")
+    local_nonpersistent_flags+=("--redact-hostnames")
+    flags+=("--redact-passwords")
+    local_nonpersistent_flags+=("--redact-passwords")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_gpupgrade_validate()
+{
+    last_command="gpupgrade_validate"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
 _gpupgrade_version()
 {
     last_command="gpupgrade_version"
@@ -726,6 +966,8 @@ _gpupgrade_version()
     two_word_flags+=("--format")
     local_nonpersistent_flags+=("--format")
     local_nonpersistent_flags+=("--format=")
+    flags+=("--matrix")
+    local_nonpersistent_flags+=("--matrix")
 
     must_have_one_flag=()
     must_have_one_noun=()
@@ -744,9 +986,13 @@ _gpupgrade_root_command()
     commands+=("finalize")
     commands+=("help")
     commands+=("initialize")
+    commands+=("inventory")
     commands+=("kill-services")
     commands+=("restart-services")
     commands+=("revert")
+    commands+=("segments")
+    commands+=("support-bundle")
+    commands+=("validate")
     commands+=("version")
 
     flags=()
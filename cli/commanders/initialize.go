@@ -4,6 +4,7 @@
 package commanders
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,6 +15,8 @@ import (
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 // introduce this variable to allow exec.Command to be mocked out in tests
@@ -37,7 +40,7 @@ func CreateStateDir() (err error) {
 	return nil
 }
 
-func CreateInitialClusterConfigs(hubPort int) (err error) {
+func CreateInitialClusterConfigs(hubPort int, metricsPort int, tracing trace.Config, notifications notify.Config, logFormat string) (err error) {
 	// if empty json configuration file exists, skip recreating it
 	filename := upgrade.GetConfigFile()
 	_, err = os.Stat(filename)
@@ -64,8 +67,19 @@ func CreateInitialClusterConfigs(hubPort int) (err error) {
 	// Bootstrap with the port to enable the CLI helper function connectToHub to
 	// work with both initialize and all other CLI commands. This overloads the
 	// hub's persisted configuration with that of the CLI when ideally these
-	// would be separate.
-	_, err = fmt.Fprintf(file, `{"Port": %d}`, hubPort) // the hub will fill the rest during initialization
+	// would be separate. The metrics port, tracing, notifications, and log
+	// format are needed when the hub starts.
+	tracingJSON, err := json.Marshal(tracing)
+	if err != nil {
+		return err
+	}
+
+	notificationsJSON, err := json.Marshal(notifications)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(file, `{"Port": %d, "MetricsPort": %d, "Tracing": %s, "Notifications": %s, "LogFormat": %q}`, hubPort, metricsPort, tracingJSON, notificationsJSON, logFormat) // the hub will fill the rest during initialization
 	if err != nil {
 		return err
 	}
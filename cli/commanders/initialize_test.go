@@ -16,6 +16,9 @@ import (
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 // Streams the above stdout/err constants to the corresponding standard file
@@ -255,7 +258,7 @@ func TestCreateInitialClusterConfigs(t *testing.T) {
 	t.Run("test idempotence", func(t *testing.T) {
 
 		{ // creates initial cluster config files if none exist or fails"
-			err = CreateInitialClusterConfigs(port)
+			err = CreateInitialClusterConfigs(port, 0, trace.Config{}, notify.Config{}, log.Text)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
@@ -266,7 +269,7 @@ func TestCreateInitialClusterConfigs(t *testing.T) {
 		}
 
 		{ // creating cluster config files is idempotent
-			err = CreateInitialClusterConfigs(port)
+			err = CreateInitialClusterConfigs(port, 0, trace.Config{}, notify.Config{}, log.Text)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
@@ -282,7 +285,7 @@ func TestCreateInitialClusterConfigs(t *testing.T) {
 		}
 
 		{ // creating cluster config files succeeds on multiple runs
-			err = CreateInitialClusterConfigs(port)
+			err = CreateInitialClusterConfigs(port, 0, trace.Config{}, notify.Config{}, log.Text)
 			if err != nil {
 				t.Fatalf("unexpected error %#v", err)
 			}
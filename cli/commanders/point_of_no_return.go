@@ -0,0 +1,70 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+)
+
+const pointOfNoReturnText = `
+WARNING: %s cannot be reverted.
+Once it starts "gpupgrade revert" can no longer restore the source cluster.
+Ensure you have a backup of the source cluster before proceeding.
+`
+
+// RunPastPointOfNoReturn calls run with the confirmation. When the hub stops
+// before the point of no return of the step the user is prompted to
+// acknowledge passing it, and run is called again with the confirmation token.
+// In non-interactive mode the confirmation must instead be given up front.
+func RunPastPointOfNoReturn(reader *bufio.Reader, nonInteractive bool, confirmation string, run func(confirmation string) error) error {
+	err := run(confirmation)
+
+	var pointOfNoReturnErr step.PointOfNoReturnErr
+	if nonInteractive || !errors.As(err, &pointOfNoReturnErr) {
+		return err
+	}
+
+	fmt.Printf(pointOfNoReturnText, pointOfNoReturnErr.Substep)
+
+	proceed, pErr := PromptPointOfNoReturn(reader, pointOfNoReturnErr.Substep)
+	if pErr != nil {
+		return pErr
+	}
+
+	if !proceed {
+		return err
+	}
+
+	return run(pointOfNoReturnErr.Confirmation)
+}
+
+func PromptPointOfNoReturn(reader *bufio.Reader, substep idl.Substep) (bool, error) {
+	for {
+		fmt.Printf("Continue past the point of no return and run %s?  Yy|Nn: ", substep)
+		input, err := reader.ReadString('\n')
+		if err != nil {
+			return false, err
+		}
+
+		input = strings.ToLower(strings.TrimSpace(input))
+		switch input {
+		case "y":
+			fmt.Println()
+			fmt.Print("Proceeding past the point of no return")
+			fmt.Println()
+			return true, nil
+		case "n":
+			fmt.Println()
+			fmt.Print("Stopping before the point of no return")
+			fmt.Println()
+			return false, nil
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commanders_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/cli/commanders"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+)
+
+func TestRunPastPointOfNoReturn(t *testing.T) {
+	pointOfNoReturnErr := step.PointOfNoReturnErr{Substep: idl.Substep_START_TARGET_CLUSTER, Confirmation: "token"}
+
+	// stopsOnce stops before the point of no return unless given the token.
+	stopsOnce := func(confirmations *[]string) func(string) error {
+		return func(confirmation string) error {
+			*confirmations = append(*confirmations, confirmation)
+			if confirmation != "token" {
+				return pointOfNoReturnErr
+			}
+
+			return nil
+		}
+	}
+
+	t.Run("retries with the confirmation when the user proceeds", func(t *testing.T) {
+		var confirmations []string
+		reader := bufio.NewReader(strings.NewReader("y\n"))
+
+		err := commanders.RunPastPointOfNoReturn(reader, false, "", stopsOnce(&confirmations))
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		expected := []string{"", "token"}
+		if !reflect.DeepEqual(confirmations, expected) {
+			t.Errorf("got confirmations %q want %q", confirmations, expected)
+		}
+	})
+
+	t.Run("returns the error when the user cancels", func(t *testing.T) {
+		var confirmations []string
+		reader := bufio.NewReader(strings.NewReader("n\n"))
+
+		err := commanders.RunPastPointOfNoReturn(reader, false, "", stopsOnce(&confirmations))
+		if !errors.Is(err, pointOfNoReturnErr) {
+			t.Errorf("got error %#v want %#v", err, pointOfNoReturnErr)
+		}
+
+		if len(confirmations) != 1 {
+			t.Errorf("got confirmations %q want only one", confirmations)
+		}
+	})
+
+	t.Run("does not prompt in non-interactive mode", func(t *testing.T) {
+		var confirmations []string
+		reader := bufio.NewReader(strings.NewReader(""))
+
+		err := commanders.RunPastPointOfNoReturn(reader, true, "", stopsOnce(&confirmations))
+		if !errors.Is(err, pointOfNoReturnErr) {
+			t.Errorf("got error %#v want %#v", err, pointOfNoReturnErr)
+		}
+
+		if len(confirmations) != 1 {
+			t.Errorf("got confirmations %q want only one", confirmations)
+		}
+	})
+
+	t.Run("passes the given confirmation", func(t *testing.T) {
+		var confirmations []string
+		reader := bufio.NewReader(strings.NewReader(""))
+
+		err := commanders.RunPastPointOfNoReturn(reader, true, "token", stopsOnce(&confirmations))
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		expected := []string{"token"}
+		if !reflect.DeepEqual(confirmations, expected) {
+			t.Errorf("got confirmations %q want %q", confirmations, expected)
+		}
+	})
+
+	t.Run("returns other errors without prompting", func(t *testing.T) {
+		expected := errors.New("oops")
+		reader := bufio.NewReader(strings.NewReader(""))
+
+		err := commanders.RunPastPointOfNoReturn(reader, false, "", func(string) error {
+			return expected
+		})
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("returns an error when failing to read input", func(t *testing.T) {
+		var confirmations []string
+		reader := bufio.NewReader(strings.NewReader(""))
+
+		err := commanders.RunPastPointOfNoReturn(reader, false, "", stopsOnce(&confirmations))
+		if err != io.EOF {
+			t.Errorf("got error %#v want %#v", err, io.EOF)
+		}
+	})
+}
@@ -29,7 +29,7 @@ const nextActionRunRevertText = "If you would like to return the cluster to its
 var additionalNextActions = map[idl.Step]string{
 	idl.Step_INITIALIZE: nextActionRunRevertText,
 	idl.Step_EXECUTE:    nextActionRunRevertText,
-	idl.Step_FINALIZE:   "",
+	idl.Step_FINALIZE:   "If finalize has not passed its point of no return, you may return the cluster to its original state by running \"gpupgrade revert\".\n",
 	idl.Step_REVERT:     "",
 }
 
@@ -67,6 +67,18 @@ func (s *StepStore) HasStepCompleted(step idl.Step) (bool, error) {
 	})
 }
 
+// HasNotPassedPointOfNoReturn returns false once the hub has started the
+// step's point of no return substep, after which the source cluster can no
+// longer be restored.
+func (s *StepStore) HasNotPassedPointOfNoReturn(stepName idl.Step) (bool, error) {
+	passed, err := step.HasRun(stepName, idl.Substep_POINT_OF_NO_RETURN)
+	if err != nil {
+		return false, err
+	}
+
+	return !passed, nil
+}
+
 func (s *StepStore) HasStatus(step idl.Step, check func(status idl.Status) bool) (bool, error) {
 	status, err := s.Read(step)
 	if err != nil {
@@ -88,7 +100,8 @@ var StepErr = errors.New(`gpupgrade commands must be issued in correct order
   2. execute      upgrades the master and primary segments to the target
                   Greenplum version
   3. finalize     upgrades the standby master and mirror segments to the target
-                  Greenplum version. Revert cannot be run after finalize has started.
+                  Greenplum version. Revert cannot be run once finalize has
+                  passed its point of no return.
 
 Use "gpupgrade --help" for more information`)
 
@@ -99,6 +112,9 @@ To return the cluster to its original state, run "gpupgrade revert".`
 
 const RunFinalize = `To proceed with the upgrade, run "gpupgrade finalize".`
 
+const RunFinalizePastPointOfNoReturn = `Finalize has passed its point of no return and the source cluster can no longer be restored.
+To proceed with the upgrade, run "gpupgrade finalize".`
+
 const RunRevert = `Revert is in progress. Please continue by running "gpupgrade revert".`
 
 // conditions expected to have been met for the current step. The next action
@@ -121,7 +137,7 @@ var validate = map[idl.Step][]stepCondition{
 	},
 	idl.Step_REVERT: {
 		{idl.Step_INITIALIZE, (*StepStore).HasStepStarted, RunInitialize},
-		{idl.Step_FINALIZE, (*StepStore).HasStepNotStarted, RunFinalize},
+		{idl.Step_FINALIZE, (*StepStore).HasNotPassedPointOfNoReturn, RunFinalizePastPointOfNoReturn},
 	},
 }
 
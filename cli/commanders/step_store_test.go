@@ -5,6 +5,7 @@ package commanders_test
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/utils"
 )
@@ -245,6 +247,8 @@ func TestValidateStep(t *testing.T) {
 		status idl.Status
 	}
 
+	substepStore := step.NewSubstepStoreUsingFile(filepath.Join(stateDir, step.SubstepsFileName))
+
 	errorCases := []struct {
 		name               string
 		currentStep        idl.Step
@@ -325,16 +329,6 @@ func TestValidateStep(t *testing.T) {
 			[]stepStatus{},
 			commanders.RunInitialize,
 		},
-		{
-			"fails when revert is run but finalize has already been started",
-			idl.Step_REVERT,
-			[]stepStatus{
-				{step: idl.Step_INITIALIZE, status: idl.Status_COMPLETE},
-				{step: idl.Step_EXECUTE, status: idl.Status_COMPLETE},
-				{step: idl.Step_FINALIZE, status: idl.Status_RUNNING},
-			},
-			commanders.RunFinalize,
-		},
 	}
 
 	for _, c := range errorCases {
@@ -357,6 +351,30 @@ func TestValidateStep(t *testing.T) {
 		})
 	}
 
+	for _, status := range []idl.Status{idl.Status_RUNNING, idl.Status_FAILED, idl.Status_COMPLETE} {
+		t.Run(fmt.Sprintf("fails when revert is run but finalize point of no return is %s", status), func(t *testing.T) {
+			clearStepStore(t)
+
+			mustWriteStatus(t, stepStore, idl.Step_INITIALIZE, idl.Status_COMPLETE)
+			mustWriteStatus(t, stepStore, idl.Step_EXECUTE, idl.Status_COMPLETE)
+			mustWriteStatus(t, stepStore, idl.Step_FINALIZE, idl.Status_FAILED)
+
+			if err := substepStore.Write(idl.Step_FINALIZE, idl.Substep_POINT_OF_NO_RETURN, status); err != nil {
+				t.Fatalf("substepStore.Write returned error %+v", err)
+			}
+
+			err = stepStore.ValidateStep(idl.Step_REVERT)
+			var nextActionsErr utils.NextActionErr
+			if !errors.As(err, &nextActionsErr) {
+				t.Errorf("got %T, want %T", err, nextActionsErr)
+			}
+
+			if nextActionsErr.NextAction != commanders.RunFinalizePastPointOfNoReturn {
+				t.Errorf("got %q want %q", nextActionsErr.NextAction, commanders.RunFinalizePastPointOfNoReturn)
+			}
+		})
+	}
+
 	cases := []struct {
 		name          string
 		currentStep   idl.Step
@@ -427,6 +445,15 @@ func TestValidateStep(t *testing.T) {
 				{step: idl.Step_EXECUTE, status: idl.Status_RUNNING},
 			},
 		},
+		{
+			"can run revert after finalize has started but not passed its point of no return",
+			idl.Step_REVERT,
+			[]stepStatus{
+				{step: idl.Step_INITIALIZE, status: idl.Status_COMPLETE},
+				{step: idl.Step_EXECUTE, status: idl.Status_COMPLETE},
+				{step: idl.Step_FINALIZE, status: idl.Status_FAILED},
+			},
+		},
 		{
 			"can run revert after revert has failed",
 			idl.Step_REVERT,
@@ -457,6 +484,9 @@ func clearStepStore(t *testing.T) {
 
 	path := filepath.Join(utils.GetStateDir(), commanders.StepsFileName)
 	testutils.MustWriteToFile(t, path, "{}")
+
+	path = filepath.Join(utils.GetStateDir(), step.SubstepsFileName)
+	testutils.MustWriteToFile(t, path, "{}")
 }
 
 func mustWriteStatus(t *testing.T, stepStore *commanders.StepStore, step idl.Step, status idl.Status) {
@@ -14,6 +14,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/utils"
 )
 
@@ -28,8 +29,8 @@ var indicators = map[idl.Status]string{
 	idl.Status_SKIPPED:  "[SKIPPED]",
 }
 
-func Initialize(client idl.CliToHubClient, request *idl.InitializeRequest, verbose bool) (err error) {
-	stream, err := client.Initialize(context.Background(), request)
+func Initialize(ctx context.Context, client idl.CliToHubClient, request *idl.InitializeRequest, verbose bool) (err error) {
+	stream, err := client.Initialize(ctx, request)
 	if err != nil {
 		return err
 	}
@@ -42,8 +43,8 @@ func Initialize(client idl.CliToHubClient, request *idl.InitializeRequest, verbo
 	return nil
 }
 
-func InitializeCreateCluster(client idl.CliToHubClient, request *idl.InitializeCreateClusterRequest, verbose bool) (idl.InitializeResponse, error) {
-	stream, err := client.InitializeCreateCluster(context.Background(), request)
+func InitializeCreateCluster(ctx context.Context, client idl.CliToHubClient, request *idl.InitializeCreateClusterRequest, verbose bool) (idl.InitializeResponse, error) {
+	stream, err := client.InitializeCreateCluster(ctx, request)
 	if err != nil {
 		return idl.InitializeResponse{}, err
 	}
@@ -61,8 +62,8 @@ func InitializeCreateCluster(client idl.CliToHubClient, request *idl.InitializeC
 	return *initializeResponse, nil
 }
 
-func Execute(client idl.CliToHubClient, verbose bool) (idl.ExecuteResponse, error) {
-	stream, err := client.Execute(context.Background(), &idl.ExecuteRequest{})
+func Execute(ctx context.Context, client idl.CliToHubClient, request *idl.ExecuteRequest, verbose bool) (idl.ExecuteResponse, error) {
+	stream, err := client.Execute(ctx, request)
 	if err != nil {
 		return idl.ExecuteResponse{}, err
 	}
@@ -80,8 +81,8 @@ func Execute(client idl.CliToHubClient, verbose bool) (idl.ExecuteResponse, erro
 	return *executeResponse, nil
 }
 
-func Finalize(client idl.CliToHubClient, verbose bool) (idl.FinalizeResponse, error) {
-	stream, err := client.Finalize(context.Background(), &idl.FinalizeRequest{})
+func Finalize(ctx context.Context, client idl.CliToHubClient, request *idl.FinalizeRequest, verbose bool) (idl.FinalizeResponse, error) {
+	stream, err := client.Finalize(ctx, request)
 	if err != nil {
 		return idl.FinalizeResponse{}, err
 	}
@@ -99,8 +100,8 @@ func Finalize(client idl.CliToHubClient, verbose bool) (idl.FinalizeResponse, er
 	return *finalizeResponse, nil
 }
 
-func Revert(client idl.CliToHubClient, verbose bool) (idl.RevertResponse, error) {
-	stream, err := client.Revert(context.Background(), &idl.RevertRequest{})
+func Revert(ctx context.Context, client idl.CliToHubClient, verbose bool) (idl.RevertResponse, error) {
+	stream, err := client.Revert(ctx, &idl.RevertRequest{})
 	if err != nil {
 		return idl.RevertResponse{}, err
 	}
@@ -183,8 +184,13 @@ func UILoop(stream receiver, verbose bool) (*idl.Response, error) {
 
 		var nextActions []string
 		for _, detail := range statusErr.Details() {
-			if msg, ok := detail.(*idl.NextActions); ok {
+			switch msg := detail.(type) {
+			case *idl.NextActions:
 				nextActions = append(nextActions, msg.GetNextActions())
+			case *idl.PointOfNoReturn:
+				// Surface the error as a type the caller can check to
+				// prompt for acknowledgement.
+				err = step.PointOfNoReturnErr{Substep: msg.GetSubstep(), Confirmation: msg.GetConfirmation()}
 			}
 		}
 
@@ -13,6 +13,7 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/utils"
 )
 
@@ -110,6 +111,38 @@ func TestUILoop(t *testing.T) {
 		}
 	})
 
+	t.Run("returns a point of no return error when the details contain one", func(t *testing.T) {
+		statusErr := status.New(codes.Internal, "oops")
+		statusErr, err := statusErr.WithDetails(
+			&idl.NextActions{NextActions: "rerun with the token"},
+			&idl.PointOfNoReturn{Substep: idl.Substep_START_TARGET_CLUSTER, Confirmation: "token"},
+		)
+		if err != nil {
+			t.Fatal("failed to add point of no return details")
+		}
+
+		_, err = commanders.UILoop(&errStream{statusErr.Err()}, true)
+
+		var pointOfNoReturnErr step.PointOfNoReturnErr
+		if !errors.As(err, &pointOfNoReturnErr) {
+			t.Fatalf("got type %T want %T", err, pointOfNoReturnErr)
+		}
+
+		expected := step.PointOfNoReturnErr{Substep: idl.Substep_START_TARGET_CLUSTER, Confirmation: "token"}
+		if pointOfNoReturnErr != expected {
+			t.Errorf("got %+v want %+v", pointOfNoReturnErr, expected)
+		}
+
+		var nextActionsErr utils.NextActionErr
+		if !errors.As(err, &nextActionsErr) {
+			t.Fatalf("got type %T want %T", err, nextActionsErr)
+		}
+
+		if nextActionsErr.NextAction != "rerun with the token" {
+			t.Errorf("got %q want %q", nextActionsErr.NextAction, "rerun with the token")
+		}
+	})
+
 	t.Run("does not return a next action status error has no details", func(t *testing.T) {
 		statusErr := status.New(codes.Internal, "oops")
 		_, err := commanders.UILoop(&errStream{statusErr.Err()}, true)
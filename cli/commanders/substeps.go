@@ -16,7 +16,11 @@ var SubstepDescriptions = map[idl.Substep]substepText{
 	idl.Substep_SAVING_SOURCE_CLUSTER_CONFIG:                                  substepText{"Saving source cluster configuration...", "Save source cluster configuration"},
 	idl.Substep_START_HUB:                                                     substepText{"Starting gpupgrade hub process...", "Start gpupgrade hub process"},
 	idl.Substep_START_AGENTS:                                                  substepText{"Starting gpupgrade agent processes...", "Start gpupgrade agent processes"},
+	idl.Substep_PREFLIGHT_CHECKS:                                              substepText{"Running preflight checks on all hosts...", "Run preflight checks on all hosts"},
 	idl.Substep_CHECK_DISK_SPACE:                                              substepText{"Checking disk space...", "Check disk space"},
+	idl.Substep_INVENTORY_SOURCE_CLUSTER:                                      substepText{"Taking inventory of the source cluster...", "Take inventory of the source cluster"},
+	idl.Substep_INVENTORY_TARGET_CLUSTER:                                      substepText{"Taking inventory of the target cluster...", "Take inventory of the target cluster and compare it with the source"},
+	idl.Substep_SNAPSHOT_SOURCE_DATA:                                          substepText{"Snapshotting source cluster data for validation...", "Snapshot row counts of the source cluster data for validation"},
 	idl.Substep_GENERATE_TARGET_CONFIG:                                        substepText{"Generating target cluster configuration...", "Generate target cluster configuration"},
 	idl.Substep_INIT_TARGET_CLUSTER:                                           substepText{"Creating target cluster...", "Create target cluster"},
 	idl.Substep_SETTING_DYNAMIC_LIBRARY_PATH_ON_TARGET_CLUSTER:                substepText{"Setting dynamic library path on target cluster...", "Set dynamic library path on target cluster"},
@@ -43,6 +47,14 @@ var SubstepDescriptions = map[idl.Substep]substepText{
 	idl.Substep_RESTORE_SOURCE_CLUSTER:                                        substepText{"Restoring source cluster...", "Restore source cluster"},
 	idl.Substep_START_SOURCE_CLUSTER:                                          substepText{"Starting source cluster...", "Start source cluster"},
 	idl.Substep_RESTORE_PGCONTROL:                                             substepText{"Re-enabling source cluster...", "Re-enable source cluster"},
+	idl.Substep_SNAPSHOT_SOURCE_CLUSTER:                                       substepText{"Snapshotting source cluster data directories...", "Snapshot source cluster data directories and tablespaces"},
+	idl.Substep_RESTORE_SOURCE_SNAPSHOTS:                                      substepText{"Restoring source cluster from snapshots...", "Restore source cluster from snapshots"},
+	idl.Substep_DELETE_SOURCE_SNAPSHOTS:                                       substepText{"Deleting source cluster snapshots...", "Delete source cluster snapshots"},
+	idl.Substep_RESTORE_TARGET_CONF_FILES:                                     substepText{"Restoring target configuration files...", "Restore target configuration files"},
+	idl.Substep_RESTORE_DATA_DIRECTORIES:                                      substepText{"Restoring data directories...", "Restore data directories"},
+	idl.Substep_POINT_OF_NO_RETURN:                                            substepText{"Passing point of no return...", "Pass point of no return after which revert is not possible"},
+	idl.Substep_BACKUP_SOURCE_CLUSTER:                                         substepText{"Backing up source cluster...", "Back up source cluster, if enabled"},
+	idl.Substep_TRANSFER_SOURCE_PRIMARIES:                                     substepText{"Transferring source primaries to new hosts...", "Transfer source primaries to new hosts, if enabled"},
 	idl.Substep_RECOVERSEG_SOURCE_CLUSTER:                                     substepText{"Recovering source cluster mirrors...", "Recover source cluster mirrors"},
 	idl.Substep_REMOVE_SOURCE_MIRRORS:                                         substepText{"Removing source cluster data directories and tablespaces to save space...", "Remove source cluster data directories and tablespaces to save space..."},
 	idl.Substep_WAIT_FOR_CLUSTER_TO_BE_READY_AFTER_ADDING_MIRRORS_AND_STANDBY: substepText{"Waiting for cluster to be ready...", "Wait for cluster to be ready"},
@@ -12,12 +12,15 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 func Agent() *cobra.Command {
 	var port int
 	var statedir string
 	var shouldDaemonize bool
+	var tracing trace.Config
+	var logFormat string
 
 	var cmd = &cobra.Command{
 		Use:    "agent",
@@ -30,9 +33,20 @@ func Agent() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			gplog.InitializeLogging("gpupgrade_agent", logdir)
+			if err := log.Initialize("gpupgrade_agent", logdir, logFormat, log.Fields{}); err != nil {
+				return err
+			}
 			defer log.WritePanics()
 
+			if err := trace.Setup("gpupgrade_agent", logdir, tracing); err != nil {
+				return err
+			}
+			defer func() {
+				if err := trace.Shutdown(); err != nil {
+					gplog.Debug("failed to export remaining spans: %#v", err)
+				}
+			}()
+
 			conf := agent.Config{
 				Port:     port,
 				StateDir: statedir,
@@ -51,6 +65,9 @@ func Agent() *cobra.Command {
 	}
 	cmd.Flags().IntVar(&port, "port", upgrade.DefaultAgentPort, "the port to listen for commands on")
 	cmd.Flags().StringVar(&statedir, "state-directory", utils.GetStateDir(), "Agent state directory")
+	cmd.Flags().StringVar(&tracing.Exporter, "trace-exporter", trace.None, "exports the agent spans to a file in the log directory or an OTLP endpoint. Either none, file, or otlp.")
+	cmd.Flags().StringVar(&tracing.Endpoint, "trace-endpoint", "", "the OTLP/HTTP endpoint for the otlp trace exporter")
+	cmd.Flags().StringVar(&logFormat, "log-format", log.Text, "the format of the agent log file. Either text or json.")
 
 	daemon.MakeDaemonizable(cmd, &shouldDaemonize)
 
@@ -32,9 +32,11 @@ package commands
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
@@ -50,6 +52,8 @@ import (
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 func BuildRootCommand() *cobra.Command {
@@ -83,6 +87,10 @@ func BuildRootCommand() *cobra.Command {
 
 	root.AddCommand(config)
 	root.AddCommand(version())
+	root.AddCommand(inventoryCmd())
+	root.AddCommand(validateCmd())
+	root.AddCommand(segmentsCmd())
+	root.AddCommand(supportBundleCmd())
 	root.AddCommand(initialize())
 	root.AddCommand(execute())
 	root.AddCommand(finalize())
@@ -94,6 +102,8 @@ func BuildRootCommand() *cobra.Command {
 
 	subConfigShow := createConfigShowSubcommand()
 	config.AddCommand(subConfigShow)
+	config.AddCommand(configInitCmd())
+	config.AddCommand(configValidateCmd())
 
 	return addHelpToCommand(root, GlobalHelp)
 }
@@ -107,41 +117,59 @@ var config = &cobra.Command{
 }
 
 func createConfigShowSubcommand() *cobra.Command {
+	var format string
+
 	subShow := &cobra.Command{
 		Use:   "show",
 		Short: "show configuration settings",
-		Long:  "show configuration settings",
+		Long: `Shows the configuration of the upgrade including the source, intermediate,
+and target cluster layouts and tablespace locations. Pass one or more of the
+setting flags to show only those settings.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			client, err := connectToHub()
 			if err != nil {
 				return err
 			}
 
-			// Build a list of GetConfigRequests, one for each flag. If no flags
-			// are passed, assume we want to retrieve all of them.
+			// Build a list of GetConfigRequests, one for each setting flag. If
+			// none are passed show the full configuration.
 			var requests []*idl.GetConfigRequest
-			getRequest := func(flag *pflag.Flag) {
-				if flag.Name != "help" {
+			cmd.Flags().Visit(func(flag *pflag.Flag) {
+				if flag.Name != "help" && flag.Name != "format" {
 					requests = append(requests, &idl.GetConfigRequest{
 						Name: flag.Name,
 					})
 				}
-			}
+			})
+
+			if len(requests) == 0 {
+				reply, err := client.ShowConfig(cmd.Context(), &idl.ShowConfigRequest{})
+				if err != nil {
+					return err
+				}
+
+				var view hub.ConfigView
+				if err := json.Unmarshal(reply.GetConfig(), &view); err != nil {
+					return xerrors.Errorf("decoding config: %w", err)
+				}
 
-			if cmd.Flags().NFlag() > 0 {
-				cmd.Flags().Visit(getRequest)
-			} else {
-				cmd.Flags().VisitAll(getRequest)
+				report, err := ConfigReport(view, format)
+				if err != nil {
+					return err
+				}
+
+				fmt.Print(report)
+				return nil
 			}
 
 			// Make the requests and print every response.
 			for _, request := range requests {
-				resp, err := client.GetConfig(context.Background(), request)
+				resp, err := client.GetConfig(cmd.Context(), request)
 				if err != nil {
 					return err
 				}
 
-				if cmd.Flags().NFlag() == 1 {
+				if len(requests) == 1 {
 					// Don't prefix with the setting name if the user only asked for one.
 					fmt.Println(resp.Value)
 				} else {
@@ -153,7 +181,9 @@ func createConfigShowSubcommand() *cobra.Command {
 		},
 	}
 
+	subShow.Flags().StringVar(&format, "format", "table", `specify the output format of the full configuration as either "table", "json", or "yaml". Default is table.`)
 	subShow.Flags().Bool("id", false, "show upgrade identifier")
+	subShow.Flags().Bool("point-of-no-return-token", false, "show the token acknowledging passing the point of no return, as with --confirm-point-of-no-return")
 	subShow.Flags().Bool("source-gphome", false, "show path for the source Greenplum installation")
 	subShow.Flags().Bool("target-gphome", false, "show path for the target Greenplum installation")
 	subShow.Flags().Bool("target-datadir", false, "show temporary data directory for target gpdb cluster")
@@ -164,17 +194,24 @@ func createConfigShowSubcommand() *cobra.Command {
 
 func version() *cobra.Command {
 	var format string
+	var matrix bool
 
 	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Version of gpupgrade",
 		Long:  `Version of gpupgrade`,
-		Run: func(cmd *cobra.Command, args []string) {
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if matrix {
+				return printVersionMatrix(format)
+			}
+
 			printVersion(format)
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&format, "format", "", `specify the output format as either "multiline", "oneline", or "json". Default is multiline.`)
+	cmd.Flags().BoolVar(&matrix, "matrix", false, "prints the supported source and target Greenplum versions. Set GPUPGRADE_VERSION_MATRIX to a file to override the built-in matrix.")
 
 	return cmd
 }
@@ -202,7 +239,7 @@ var restartServices = &cobra.Command{
 			return err
 		}
 
-		reply, err := client.RestartAgents(context.Background(), &idl.RestartAgentsRequest{})
+		reply, err := client.RestartAgents(cmd.Context(), &idl.RestartAgentsRequest{})
 		if err != nil {
 			return xerrors.Errorf("restarting agents: %w", err)
 		}
@@ -237,17 +274,17 @@ var killServices = &cobra.Command{
 			return nil
 		}
 
-		return stopHubAndAgents(true)
+		return stopHubAndAgents(cmd.Context(), true)
 	},
 }
 
-func stopHubAndAgents(tryDefaultPort bool) error {
+func stopHubAndAgents(ctx context.Context, tryDefaultPort bool) error {
 	client, err := connectToHubOnPort(getHubPort(tryDefaultPort))
 	if err != nil {
 		return err
 	}
 
-	_, err = client.StopServices(context.Background(), &idl.StopServicesRequest{})
+	_, err = client.StopServices(ctx, &idl.StopServicesRequest{})
 	if err != nil {
 		errCode := grpcStatus.Code(err)
 		errMsg := grpcStatus.Convert(err).Message()
@@ -275,9 +312,13 @@ func connectToHubOnPort(port int) (idl.CliToHubClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), connTimeout())
 	defer cancel()
 
+	startTracing()
+
 	// Attempt a connection.
 	address := "localhost:" + strconv.Itoa(port)
-	conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock())
+	conn, err := grpc.DialContext(ctx, address, grpc.WithInsecure(), grpc.WithBlock(),
+		grpc.WithUnaryInterceptor(trace.UnaryClientInterceptor),
+		grpc.WithStreamInterceptor(trace.StreamClientInterceptor))
 	if err != nil {
 		// Print a nicer error message if we can't connect to the hub.
 		if ctx.Err() == context.DeadlineExceeded {
@@ -289,6 +330,46 @@ func connectToHubOnPort(port int) (idl.CliToHubClient, error) {
 	return idl.NewCliToHubClient(conn), nil
 }
 
+// LogConfig returns the log format and upgrade ID of the CLI log. The CLI logs
+// as text until initialize has created the configuration file.
+func LogConfig() (format string, upgradeID string) {
+	conf := &hub.Config{}
+	if err := hub.LoadConfig(conf, upgrade.GetConfigFile()); err != nil {
+		return log.Text, ""
+	}
+
+	if conf.UpgradeID != 0 {
+		upgradeID = conf.UpgradeID.String()
+	}
+
+	return conf.LogFormat, upgradeID
+}
+
+var startTracingOnce sync.Once
+
+// startTracing exports the spans of the CLI the same way as the hub once the
+// configuration file exists. Tracing is best effort so failures are only
+// logged.
+func startTracing() {
+	startTracingOnce.Do(func() {
+		conf := &hub.Config{}
+		if err := hub.LoadConfig(conf, upgrade.GetConfigFile()); err != nil {
+			gplog.Debug("not tracing: %v", err)
+			return
+		}
+
+		logdir, err := utils.GetLogDir()
+		if err != nil {
+			gplog.Debug("not tracing: %v", err)
+			return
+		}
+
+		if err := trace.Setup("gpupgrade_cli", logdir, conf.Tracing); err != nil {
+			gplog.Debug("not tracing: %v", err)
+		}
+	})
+}
+
 // connTimeout retrieves the GPUPGRADE_CONNECTION_TIMEOUT environment variable,
 // interprets it as a (possibly fractional) number of seconds, and converts it
 // into a Duration. The default is one second if the envvar is unset or
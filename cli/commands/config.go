@@ -0,0 +1,501 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/blang/semver/v4"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+func configInitCmd() *cobra.Command {
+	var file string
+	var targetGPHome string
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "writes a config file for the running source cluster",
+		Long: `Writes a commented config file for the running source cluster, which is
+found from MASTER_DATA_DIRECTORY or PGPORT. The source installation is guessed
+from GPHOME, or else the postgres on the PATH, and a free temp_port_range that
+does not overlap the source cluster ports is proposed. Review the file, set
+target_gphome if it was not given, and check it with
+"gpupgrade config validate".`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			sourceGPHome, err := guessGPHome()
+			if err != nil {
+				return err
+			}
+
+			sourcePort, err := sourceCoordinatorPort()
+			if err != nil {
+				return err
+			}
+
+			source, err := discoverSourceCluster(sourceGPHome, sourcePort)
+			if err != nil {
+				return err
+			}
+
+			ports, err := proposeTempPortRange(source, portAvailable)
+			if err != nil {
+				return err
+			}
+
+			discovered := map[string]string{
+				"source_gphome":      sourceGPHome,
+				"source_master_port": strconv.Itoa(sourcePort),
+				"target_gphome":      targetGPHome,
+				"temp_port_range":    ports,
+			}
+
+			// Do not overwrite an existing config file.
+			out, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+			if err != nil {
+				return err
+			}
+
+			err = writeConfig(out, discovered)
+			if cErr := out.Close(); cErr != nil {
+				err = errorlist.Append(err, cErr)
+			}
+			if err != nil {
+				return xerrors.Errorf("writing config file %q: %w", file, err)
+			}
+
+			fmt.Printf("Wrote %s for the source cluster on port %d.\n", file, sourcePort)
+			fmt.Printf(`Review it and then run "gpupgrade config validate --file %s".`+"\n", file)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "gpupgrade_config", "the configuration file to write")
+	cmd.Flags().StringVar(&targetGPHome, "target-gphome", "", "path for the target Greenplum installation")
+
+	return cmd
+}
+
+func configValidateCmd() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "validates a config file without starting the hub",
+		Long: `Validates a config file the way initialize does without starting the hub. The
+parameters are parsed and checked, the source and target installations must
+exist, and their versions must be compatible. The source cluster itself is not
+checked until initialize.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			opts, err := readConfig(file)
+			if err != nil {
+				return xerrors.Errorf("in file %q: %w", file, err)
+			}
+
+			note, err := opts.checkInstallations()
+			if err != nil {
+				return xerrors.Errorf("in file %q: %w", file, err)
+			}
+
+			if note != "" {
+				fmt.Printf("\n%s\n\n", note)
+			}
+
+			fmt.Printf("The config file %s is valid.\n", file)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "the configuration file to validate")
+	cmd.MarkFlagRequired("file") //nolint
+
+	return cmd
+}
+
+// readConfig returns the validated initialize options of the config file.
+func readConfig(path string) (*initializeOptions, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := ParseConfig(bytes.NewReader(contents))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &initializeOptions{}
+	cmd := &cobra.Command{}
+	opts.addFlags(cmd.Flags())
+
+	if err := addFlags(cmd, params); err != nil {
+		return nil, err
+	}
+
+	if err := opts.validate(cmd.Flags()); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// checkInstallations checks that the source port is set and that the source
+// and target installations exist with compatible versions. It returns the
+// migration note of the upgrade path, if any.
+func (o *initializeOptions) checkInstallations() (string, error) {
+	var errs error
+	if o.sourcePort == 0 {
+		errs = errorlist.Append(errs, xerrors.New(`no value found for parameter "source_master_port"`))
+	}
+
+	installations := []struct {
+		param  string
+		gphome string
+	}{
+		{param: "source_gphome", gphome: o.sourceGPHome},
+		{param: "target_gphome", gphome: o.targetGPHome},
+	}
+
+	for _, installation := range installations {
+		if installation.gphome == "" {
+			errs = errorlist.Append(errs, xerrors.Errorf("no value found for parameter %q", installation.param))
+			continue
+		}
+
+		postgres := filepath.Join(installation.gphome, "bin", "postgres")
+		if _, err := os.Stat(postgres); err != nil {
+			errs = errorlist.Append(errs, xerrors.Errorf("%s %q is not a Greenplum installation: %w", installation.param, installation.gphome, err))
+		}
+	}
+
+	if errs != nil {
+		return "", errs
+	}
+
+	if o.skipVersionCheck {
+		return "", nil
+	}
+
+	path, err := greenplum.VerifyCompatibleGPDBVersions(o.sourceGPHome, o.targetGPHome)
+	if err != nil {
+		return "", err
+	}
+
+	return path.MigrationNote(), nil
+}
+
+// guessGPHome returns GPHOME, which greenplum_path.sh sets, or else the
+// installation of the postgres on the PATH.
+func guessGPHome() (string, error) {
+	if gphome := os.Getenv("GPHOME"); gphome != "" {
+		return filepath.Clean(gphome), nil
+	}
+
+	postgres, err := exec.LookPath("postgres")
+	if err != nil {
+		return "", xerrors.New("Could not guess the source installation. Set GPHOME or source greenplum_path.sh of the source installation.")
+	}
+
+	return filepath.Dir(filepath.Dir(postgres)), nil
+}
+
+var portRegex = regexp.MustCompile(`(?m)^\s*port\s*=\s*(\d+)`)
+
+// sourceCoordinatorPort returns PGPORT, or else the port configured in the
+// postgresql.conf of MASTER_DATA_DIRECTORY.
+func sourceCoordinatorPort() (int, error) {
+	if pgport := os.Getenv("PGPORT"); pgport != "" {
+		port, err := strconv.Atoi(pgport)
+		if err != nil {
+			return 0, xerrors.Errorf("parsing PGPORT %q: %w", pgport, err)
+		}
+
+		return port, nil
+	}
+
+	dataDir := os.Getenv("MASTER_DATA_DIRECTORY")
+	if dataDir == "" {
+		return 0, xerrors.New("Could not find the source cluster. Set MASTER_DATA_DIRECTORY or PGPORT for the source cluster.")
+	}
+
+	path := filepath.Join(dataDir, "postgresql.conf")
+	conf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// The last setting takes effect.
+	matches := portRegex.FindAllSubmatch(conf, -1)
+	if matches == nil {
+		return 0, xerrors.Errorf("no port found in %q. Set PGPORT for the source cluster.", path)
+	}
+
+	return strconv.Atoi(string(matches[len(matches)-1][1]))
+}
+
+// discoverSourceCluster returns the segments of the running source cluster.
+func discoverSourceCluster(gphome string, port int) (_ *greenplum.Cluster, err error) {
+	rawVersion, err := greenplum.Version(gphome)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := semver.Parse(rawVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := greenplum.Connection(version, version)
+	db, err := sql.Open("pgx", conn.URI(greenplum.ToSource(), greenplum.Port(port), greenplum.UtilityMode()))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	segments, err := greenplum.GetSegmentConfiguration(db, version)
+	if err != nil {
+		return nil, xerrors.Errorf("querying the segments of the source cluster on port %d: %w", port, err)
+	}
+
+	cluster, err := greenplum.NewCluster(segments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cluster, nil
+}
+
+// proposeTempPortRange returns the first range of ports starting from the
+// default temp_port_range that has enough ports for the target cluster, does
+// not overlap any port of the source cluster, and is available locally.
+func proposeTempPortRange(source *greenplum.Cluster, available func(port int) bool) (string, error) {
+	defaults, err := parsePorts(defaultTempPortRange)
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[int]bool)
+	for _, seg := range source.Primaries {
+		used[seg.Port] = true
+	}
+	for _, seg := range source.Mirrors {
+		used[seg.Port] = true
+	}
+
+	// The coordinator and standby take the first ports, and each host then
+	// takes the following ports for its primaries and mirrors.
+	needed := 0
+	segmentsByHost := make(map[string]int)
+	for _, segments := range []greenplum.ContentToSegConfig{source.Primaries, source.Mirrors} {
+		for content, seg := range segments {
+			if content == -1 {
+				needed++
+				continue
+			}
+
+			segmentsByHost[seg.Hostname]++
+		}
+	}
+
+	most := 0
+	for _, count := range segmentsByHost {
+		if count > most {
+			most = count
+		}
+	}
+	needed += most
+
+	first := int(defaults[0])
+	last := int(defaults[len(defaults)-1])
+
+	start := first
+	for port := first; port <= last; port++ {
+		if used[port] || !available(port) {
+			start = port + 1
+			continue
+		}
+
+		if port-start+1 == needed {
+			return fmt.Sprintf("%d-%d", start, port), nil
+		}
+	}
+
+	return "", xerrors.Errorf("no range of %d free ports found within %s", needed, defaultTempPortRange)
+}
+
+// portAvailable returns whether the port can be listened on locally.
+func portAvailable(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+
+	listener.Close()
+	return true
+}
+
+// writeConfig writes a config file in the gpupgrade_config format. The
+// discovered parameters are set and the others are commented out with their
+// default values.
+func writeConfig(w io.Writer, discovered map[string]string) error {
+	var opts initializeOptions
+	flags := pflag.NewFlagSet("initialize", pflag.ContinueOnError)
+	opts.addFlags(flags)
+
+	var b strings.Builder
+	b.WriteString("# gpupgrade configuration file written by \"gpupgrade config init\"\n")
+
+	for _, param := range ConfigParams {
+		fmt.Fprintf(&b, "\n# %s\n", param.Description)
+
+		if value, ok := discovered[param.Flat]; ok {
+			fmt.Fprintf(&b, "%s = %s\n", param.Flat, value)
+			continue
+		}
+
+		fmt.Fprintf(&b, "# %s = %s\n", param.Flat, flags.Lookup(param.Flag()).DefValue)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// The formats of "gpupgrade config show".
+var configFormats = []string{"table", "json", "yaml"}
+
+// ConfigReport renders the configuration as a table, json, or yaml.
+func ConfigReport(view hub.ConfigView, format string) (string, error) {
+	switch format {
+	case "table":
+		return configTable(view), nil
+	case "json":
+		out, err := json.MarshalIndent(view, "", "  ")
+		if err != nil {
+			return "", err
+		}
+
+		return string(out) + "\n", nil
+	case "yaml":
+		out, err := yaml.Marshal(view)
+		if err != nil {
+			return "", err
+		}
+
+		return string(out), nil
+	default:
+		return "", fmt.Errorf("Invalid format %q. Please specify one of %s.", format, strings.Join(configFormats, ", "))
+	}
+}
+
+func configTable(view hub.ConfigView) string {
+	var b bytes.Buffer
+
+	t := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(t, "Upgrade ID\t%s\n", view.UpgradeID)
+	fmt.Fprintf(t, "Mode\t%s\n", view.Mode)
+	fmt.Fprintf(t, "Hub Port\t%d\n", view.HubPort)
+	fmt.Fprintf(t, "Agent Port\t%d\n", view.AgentPort)
+	if view.MetricsPort != 0 {
+		fmt.Fprintf(t, "Metrics Port\t%d\n", view.MetricsPort)
+	}
+	if view.TraceExporter != "" {
+		fmt.Fprintf(t, "Trace Exporter\t%s\n", view.TraceExporter)
+	}
+	if view.TraceEndpoint != "" {
+		fmt.Fprintf(t, "Trace Endpoint\t%s\n", view.TraceEndpoint)
+	}
+	if view.NotifyWebhook != "" {
+		fmt.Fprintf(t, "Notify Webhook\t%s\n", view.NotifyWebhook)
+	}
+	if view.NotifyEmail != "" {
+		fmt.Fprintf(t, "Notify Email\t%s\n", view.NotifyEmail)
+	}
+	if view.NotifySMTP != "" {
+		fmt.Fprintf(t, "Notify SMTP\t%s\n", view.NotifySMTP)
+	}
+	fmt.Fprintf(t, "Log Format\t%s\n", view.LogFormat)
+	fmt.Fprintf(t, "Use HBA Hostnames\t%t\n", view.UseHbaHostnames)
+	fmt.Fprintf(t, "Snapshot Provider\t%s\n", view.SnapshotProvider)
+	fmt.Fprintf(t, "Mirror Resync\t%s\n", view.MirrorResync)
+	fmt.Fprintf(t, "Backup Provider\t%s\n", view.BackupProvider)
+	if view.Backup != "" {
+		fmt.Fprintf(t, "Backup\t%s\n", view.Backup)
+	}
+	fmt.Fprintf(t, "Log Archive Directory\t%s\n", view.LogArchiveDir)
+	t.Flush()
+
+	clusters := []struct {
+		name    string
+		cluster *hub.ClusterView
+	}{
+		{name: "Source", cluster: view.Source},
+		{name: "Intermediate", cluster: view.Intermediate},
+		{name: "Target", cluster: view.Target},
+	}
+
+	for _, c := range clusters {
+		fmt.Fprintf(&b, "\n%s Cluster\n", c.name)
+		if c.cluster == nil {
+			fmt.Fprintln(&b, "  not yet configured")
+			continue
+		}
+
+		fmt.Fprintf(&b, "  GPHOME   %s\n", c.cluster.GPHome)
+		fmt.Fprintf(&b, "  Version  %s\n\n", c.cluster.Version)
+
+		t := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(t, "  Content\tDbid\tRole\tHost\tPort\tData Directory")
+		for _, seg := range c.cluster.Segments {
+			fmt.Fprintf(t, "  %d\t%d\t%s\t%s\t%d\t%s\n", seg.ContentID, seg.DbID, seg.Role, seg.Hostname, seg.Port, seg.DataDir)
+		}
+		t.Flush()
+
+		if len(c.cluster.Tablespaces) == 0 {
+			continue
+		}
+
+		fmt.Fprintln(&b)
+		t = tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(t, "  Dbid\tTablespace Oid\tLocation\tTarget Location")
+		for _, ts := range c.cluster.Tablespaces {
+			target := ts.TargetLocation
+			if target == "" {
+				target = "-"
+			}
+
+			fmt.Fprintf(t, "  %d\t%d\t%s\t%s\n", ts.DbID, ts.Oid, ts.Location, target)
+		}
+		t.Flush()
+	}
+
+	return b.String()
+}
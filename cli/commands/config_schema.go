@@ -0,0 +1,156 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ParamType is the JSON schema type of a config parameter.
+type ParamType string
+
+const (
+	stringType  ParamType = "string"
+	integerType ParamType = "integer"
+	numberType  ParamType = "number"
+	booleanType ParamType = "boolean"
+)
+
+// ConfigParam is a parameter of the structured config file. Its path is the
+// dot separated sections leading to the parameter. Each parameter sets the
+// initialize flag of the same name as its flat gpupgrade_config parameter.
+type ConfigParam struct {
+	Path        string
+	Flat        string
+	Type        ParamType
+	Description string
+}
+
+// Flag returns the initialize flag set by the parameter.
+func (p ConfigParam) Flag() string {
+	return strings.ReplaceAll(p.Flat, "_", "-")
+}
+
+// ConfigParams describes the structured config file format. Keep it in sync
+// with gpupgrade_config and regenerate the schema with `go generate ./cli/schema`.
+var ConfigParams = []ConfigParam{
+	{Path: "source_gphome", Flat: "source_gphome", Type: stringType, Description: "The installation path for the source Greenplum Database."},
+	{Path: "target_gphome", Flat: "target_gphome", Type: stringType, Description: "The installation path for the target Greenplum Database."},
+	{Path: "mode", Flat: "mode", Type: stringType, Description: "The upgrade method. Either copy, link, or clone."},
+	{Path: "dynamic_library_path", Flat: "dynamic_library_path", Type: stringType, Description: "The dynamic_library_path of the target cluster for extensions installed outside of target_gphome."},
+	{Path: "use_hba_hostnames", Flat: "use_hba_hostnames", Type: booleanType, Description: "Whether to populate pg_hba.conf with host names rather than IP addresses."},
+	{Path: "ports.source_master", Flat: "source_master_port", Type: integerType, Description: "The master port of the source cluster."},
+	{Path: "ports.temp_range", Flat: "temp_port_range", Type: stringType, Description: "The comma separated ports and port ranges for the target cluster during the upgrade."},
+	{Path: "ports.strategy", Flat: "port_strategy", Type: stringType, Description: "How the ports of the target cluster are assigned during the upgrade. Either sequential, offset, or map."},
+	{Path: "ports.target_map", Flat: "target_port_map", Type: stringType, Description: "With the map port strategy a file giving the target port of each segment by its source host and port."},
+	{Path: "ports.keep_temp", Flat: "keep_temp_ports", Type: booleanType, Description: "Whether finalize keeps the temporary ports rather than reconfiguring the target cluster to use the source ports."},
+	{Path: "ports.hub", Flat: "hub_port", Type: integerType, Description: "The port of the gpupgrade hub."},
+	{Path: "ports.metrics", Flat: "metrics_port", Type: integerType, Description: "The port the gpupgrade hub serves Prometheus metrics on at /metrics, where 0 does not serve them."},
+	{Path: "ports.agent", Flat: "agent_port", Type: integerType, Description: "The port of the gpupgrade agents on all hosts."},
+	{Path: "transfer.target_datadir_layout", Flat: "target_datadir_layout", Type: stringType, Description: "In copy mode a file placing the target data directories by dbid or host path prefix rather than next to the source data directories."},
+	{Path: "transfer.target_host_mapping", Flat: "target_host_mapping", Type: stringType, Description: "In copy mode a file mapping source hosts to the new hosts that their segments are upgraded onto."},
+	{Path: "transfer.mirror_resync", Flat: "mirror_resync", Type: stringType, Description: "How link mode finalize upgrades the mirrors. Either full or incremental."},
+	{Path: "hooks.snapshot.provider", Flat: "snapshot_provider", Type: stringType, Description: "How link mode snapshots the source cluster for revert. Either none, lvm, zfs, btrfs, or reflink."},
+	{Path: "hooks.backup.provider", Flat: "backup_provider", Type: stringType, Description: "How execute backs up the source cluster. Either none, gpbackup, or command."},
+	{Path: "hooks.backup.command", Flat: "backup_command", Type: stringType, Description: "The command template run with bash by the command backup provider."},
+	{Path: "hooks.backup.timestamp", Flat: "backup_timestamp", Type: stringType, Description: "The identity of an existing backup to verify rather than taking a new one."},
+	{Path: "checks.disk_free_ratio", Flat: "disk_free_ratio", Type: numberType, Description: "The fraction of disk space that must be free on every host from 0.0 to 1.0, where 0 skips the check. By default it is estimated."},
+	{Path: "checks.data_validation", Flat: "data_validation", Type: stringType, Description: "How the upgraded data is validated. Either none, row-counts, or sampled-hashes."},
+	{Path: "tracing.exporter", Flat: "trace_exporter", Type: stringType, Description: "How the CLI, hub, and agents export their spans. Either none, file, or otlp."},
+	{Path: "tracing.endpoint", Flat: "trace_endpoint", Type: stringType, Description: "The OTLP/HTTP endpoint of the otlp trace exporter."},
+	{Path: "notifications.webhook", Flat: "notify_webhook", Type: stringType, Description: "The URL the hub posts JSON notifications of step events to."},
+	{Path: "notifications.email", Flat: "notify_email", Type: stringType, Description: "The comma separated email addresses the hub mails notifications of step events to."},
+	{Path: "notifications.smtp", Flat: "notify_smtp", Type: stringType, Description: "The host:port of the SMTP relay for notification email. By default the local sendmail is used."},
+	{Path: "logging.format", Flat: "log_format", Type: stringType, Description: "The format of the CLI, hub, and agent log files. Either text or json."},
+}
+
+// ConfigSchema returns the JSON schema of the structured config file.
+func ConfigSchema() ([]byte, error) {
+	root := newObjectSchema()
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["title"] = "gpupgrade configuration file"
+
+	for _, param := range ConfigParams {
+		object := root
+		sections := strings.Split(param.Path, ".")
+		for _, section := range sections[:len(sections)-1] {
+			properties := object["properties"].(map[string]interface{})
+			if _, ok := properties[section]; !ok {
+				properties[section] = newObjectSchema()
+			}
+
+			object = properties[section].(map[string]interface{})
+		}
+
+		properties := object["properties"].(map[string]interface{})
+		properties[sections[len(sections)-1]] = map[string]interface{}{
+			"type":        param.Type,
+			"description": param.Description,
+		}
+	}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+func newObjectSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           map[string]interface{}{},
+		"additionalProperties": false,
+	}
+}
+
+// closest returns the candidate most similar to name, or the empty string if
+// none are similar enough to suggest.
+func closest(name string, candidates []string) string {
+	best := ""
+	bestDistance := len(name)/3 + 1
+	for _, candidate := range candidates {
+		distance := levenshtein(name, candidate)
+		if distance <= bestDistance && (best == "" || distance < bestDistance) {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	return best
+}
+
+// levenshtein returns the number of single character insertions, deletions,
+// and substitutions needed to change a into b.
+func levenshtein(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
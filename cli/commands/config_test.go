@@ -0,0 +1,307 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestProposeTempPortRange(t *testing.T) {
+	source := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 5432, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: -1, DbID: 2, Port: 5432, Hostname: "scdw", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		{ContentID: 0, DbID: 3, Port: 50433, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		{ContentID: 1, DbID: 4, Port: 50434, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+		{ContentID: 2, DbID: 5, Port: 50433, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Role: greenplum.PrimaryRole},
+		{ContentID: 3, DbID: 6, Port: 50434, Hostname: "sdw2", DataDir: "/data/dbfast2/seg3", Role: greenplum.PrimaryRole},
+	})
+
+	t.Run("proposes enough free ports that do not overlap the source cluster", func(t *testing.T) {
+		available := func(port int) bool {
+			return port != 50437
+		}
+
+		ports, err := proposeTempPortRange(source, available)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		// The coordinator, standby, and two primaries per host need four ports.
+		if ports != "50438-50441" {
+			t.Errorf("got %q want %q", ports, "50438-50441")
+		}
+	})
+
+	t.Run("counts the mirrors on each host", func(t *testing.T) {
+		mirrored := greenplum.MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Port: 5432, Hostname: "cdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+			{ContentID: -1, DbID: 2, Port: 5432, Hostname: "scdw", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+			{ContentID: 0, DbID: 3, Port: 50433, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+			{ContentID: 1, DbID: 4, Port: 50434, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+			{ContentID: 2, DbID: 5, Port: 50433, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Role: greenplum.PrimaryRole},
+			{ContentID: 3, DbID: 6, Port: 50434, Hostname: "sdw2", DataDir: "/data/dbfast2/seg3", Role: greenplum.PrimaryRole},
+			{ContentID: 0, DbID: 7, Port: 50435, Hostname: "sdw2", DataDir: "/data/dbfast_mirror2/seg0", Role: greenplum.MirrorRole},
+			{ContentID: 1, DbID: 8, Port: 50436, Hostname: "sdw2", DataDir: "/data/dbfast_mirror2/seg1", Role: greenplum.MirrorRole},
+			{ContentID: 2, DbID: 9, Port: 50435, Hostname: "sdw1", DataDir: "/data/dbfast_mirror1/seg2", Role: greenplum.MirrorRole},
+			{ContentID: 3, DbID: 10, Port: 50436, Hostname: "sdw1", DataDir: "/data/dbfast_mirror1/seg3", Role: greenplum.MirrorRole},
+		})
+
+		ports, err := proposeTempPortRange(mirrored, func(port int) bool { return true })
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		// The coordinator, standby, and two primaries and two mirrors per
+		// host need six ports.
+		if ports != "50437-50442" {
+			t.Errorf("got %q want %q", ports, "50437-50442")
+		}
+	})
+
+	t.Run("errors when no range is free", func(t *testing.T) {
+		_, err := proposeTempPortRange(source, func(port int) bool { return false })
+		if err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func TestWriteConfig(t *testing.T) {
+	var config strings.Builder
+	err := writeConfig(&config, map[string]string{
+		"source_gphome":      "/usr/local/gpdb5",
+		"source_master_port": "5432",
+		"target_gphome":      "/usr/local/gpdb6",
+		"temp_port_range":    "50432-50435",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	if !strings.Contains(config.String(), "# mode = copy\n") {
+		t.Errorf("expected the other parameters to be commented out with their defaults in:\n%s", config.String())
+	}
+
+	flags, err := ParseConfig(strings.NewReader(config.String()))
+	if err != nil {
+		t.Fatalf("parsing written config: %+v", err)
+	}
+
+	expected := map[string]string{
+		"source-gphome":      "/usr/local/gpdb5",
+		"source-master-port": "5432",
+		"target-gphome":      "/usr/local/gpdb6",
+		"temp-port-range":    "50432-50435",
+	}
+	if len(flags) != len(expected) {
+		t.Errorf("got %v want %v", flags, expected)
+	}
+	for name, value := range expected {
+		if flags[name] != value {
+			t.Errorf("got %s %q want %q", name, flags[name], value)
+		}
+	}
+}
+
+func TestSourceCoordinatorPort(t *testing.T) {
+	t.Run("uses PGPORT", func(t *testing.T) {
+		defer testutils.SetEnv(t, "PGPORT", "15432")()
+
+		port, err := sourceCoordinatorPort()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if port != 15432 {
+			t.Errorf("got %d want %d", port, 15432)
+		}
+	})
+
+	t.Run("reads the port of MASTER_DATA_DIRECTORY", func(t *testing.T) {
+		dataDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dataDir)
+
+		testutils.MustWriteToFile(t, filepath.Join(dataDir, "postgresql.conf"), "#port = 5432\nport=5432\nport = 25432 # set by gpinitsystem\n")
+
+		defer testutils.MustClearEnv(t, "PGPORT")()
+		defer testutils.SetEnv(t, "MASTER_DATA_DIRECTORY", dataDir)()
+
+		port, err := sourceCoordinatorPort()
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if port != 25432 {
+			t.Errorf("got %d want %d", port, 25432)
+		}
+	})
+
+	t.Run("errors when the source cluster is not set", func(t *testing.T) {
+		defer testutils.MustClearEnv(t, "PGPORT")()
+		defer testutils.MustClearEnv(t, "MASTER_DATA_DIRECTORY")()
+
+		_, err := sourceCoordinatorPort()
+		if err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	for _, gphome := range []string{"gpdb5", "gpdb6"} {
+		testutils.MustCreateDir(t, filepath.Join(dir, gphome, "bin"))
+		testutils.MustWriteToFile(t, filepath.Join(dir, gphome, "bin", "postgres"), "")
+	}
+
+	write := func(t *testing.T, config string) string {
+		t.Helper()
+
+		path := filepath.Join(dir, "gpupgrade_config")
+		testutils.MustWriteToFile(t, path, config)
+		return path
+	}
+
+	t.Run("validates the parameters and installations", func(t *testing.T) {
+		path := write(t, strings.Join([]string{
+			"source_master_port = 5432",
+			"source_gphome = " + filepath.Join(dir, "gpdb5"),
+			"target_gphome = " + filepath.Join(dir, "gpdb6"),
+			"mode = link",
+			"temp_port_range = 50432-50440",
+			"skip_version_check = true",
+		}, "\n"))
+
+		opts, err := readConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if _, err := opts.checkInstallations(); err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		if len(opts.parsedPorts) != 9 {
+			t.Errorf("got ports %v", opts.parsedPorts)
+		}
+	})
+
+	t.Run("errors as initialize would", func(t *testing.T) {
+		cases := map[string]string{
+			"the ratio is out of range":            "disk_free_ratio = 1.5",
+			"the ports do not parse":               "temp_port_range = 1,,2",
+			"the mode is invalid":                  "mode = move",
+			"snapshots are used outside link mode": "mode = copy\nsnapshot_provider = zfs",
+			"the parameter is unknown":             "mod = copy",
+		}
+
+		for name, config := range cases {
+			t.Run(name, func(t *testing.T) {
+				_, err := readConfig(write(t, config))
+				if err == nil {
+					t.Errorf("expected error")
+				}
+			})
+		}
+	})
+
+	t.Run("reports every missing installation", func(t *testing.T) {
+		path := write(t, "source_gphome = "+filepath.Join(dir, "gpdb4"))
+
+		opts, err := readConfig(path)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		_, err = opts.checkInstallations()
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+
+		for _, expected := range []string{"source_master_port", "gpdb4", "target_gphome"} {
+			if !strings.Contains(err.Error(), expected) {
+				t.Errorf("expected error %q to mention %q", err, expected)
+			}
+		}
+	})
+}
+
+func TestConfigReport(t *testing.T) {
+	view := hub.ConfigView{
+		UpgradeID: "ABC",
+		Mode:      "link",
+		HubPort:   7527,
+		Source: &hub.ClusterView{
+			GPHome:  "/usr/local/gpdb5",
+			Version: "5.28.0",
+			Segments: []hub.SegmentView{
+				{DbID: 1, ContentID: -1, Role: "coordinator", Hostname: "cdw", Port: 15432, DataDir: "/data/qddir/seg-1"},
+			},
+			Tablespaces: []hub.TablespaceView{
+				{DbID: 1, Oid: 16386, Location: "/tmp/tblspc/1"},
+			},
+		},
+	}
+
+	t.Run("renders a table", func(t *testing.T) {
+		report, err := ConfigReport(view, "table")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for _, expected := range []string{
+			"Upgrade ID             ABC\n",
+			"Source Cluster\n  GPHOME   /usr/local/gpdb5\n",
+			"  -1       1     coordinator  cdw   15432  /data/qddir/seg-1\n",
+			"  1     16386           /tmp/tblspc/1  -\n",
+			"Target Cluster\n  not yet configured\n",
+		} {
+			if !strings.Contains(report, expected) {
+				t.Errorf("expected %q in report:\n%s", expected, report)
+			}
+		}
+	})
+
+	t.Run("renders json and yaml", func(t *testing.T) {
+		for _, format := range []string{"json", "yaml"} {
+			report, err := ConfigReport(view, format)
+			if err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+
+			var decoded hub.ConfigView
+			if format == "json" {
+				err = json.Unmarshal([]byte(report), &decoded)
+			} else {
+				err = yaml.Unmarshal([]byte(report), &decoded)
+			}
+			if err != nil {
+				t.Fatalf("decoding %s: %+v", format, err)
+			}
+
+			if !reflect.DeepEqual(decoded, view) {
+				t.Errorf("%s round trip got %+v want %+v", format, decoded, view)
+			}
+		}
+	})
+
+	t.Run("errors on unknown formats", func(t *testing.T) {
+		_, err := ConfigReport(view, "xml")
+		if err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
@@ -8,23 +8,43 @@ You are about to initialize a major-version upgrade of Greenplum.
 This should be done only during a downtime window.
 
 gpupgrade initialize will perform a series of steps, including:
+ - Run preflight checks on all hosts
  - Check disk space
+ - Snapshot the source data for validation, if enabled
  - Create the target cluster
  - Run pg_upgrade consistency checks
 
 gpupgrade log files can be found on all hosts in %s
 
 gpupgrade initialize will use these values from %s
-source_master_port:   %d
-source_gphome:        %s
-target_gphome:        %s
-mode:                 %s
-disk_free_ratio:      %.1f
-use_hba_hostnames:    %t
-dynamic_library_path: %s
-temp_port_range:      %s
-hub_port:             %d
-agent_port:           %d
+source_master_port:    %d
+source_gphome:         %s
+target_gphome:         %s
+mode:                  %s
+snapshot_provider:     %s
+mirror_resync:         %s
+backup_provider:       %s
+backup_command:        %s
+backup_timestamp:      %s
+disk_free_ratio:       %s
+data_validation:       %s
+use_hba_hostnames:     %t
+dynamic_library_path:  %s
+temp_port_range:       %s
+port_strategy:         %s
+target_port_map:       %s
+keep_temp_ports:       %t
+target_datadir_layout: %s
+target_host_mapping:   %s
+hub_port:              %d
+metrics_port:          %d
+agent_port:            %d
+trace_exporter:        %s
+trace_endpoint:        %s
+notify_webhook:        %s
+notify_email:          %s
+notify_smtp:           %s
+log_format:            %s
 
 You will still have the opportunity to revert the cluster to its original state 
 after this step.
@@ -46,6 +66,7 @@ You are about to run the "execute" command for a major-version upgrade of Greenp
 This should be done only during a downtime window.
 
 gpupgrade execute will perform a series of steps, including:
+- Back up the source cluster, if enabled
 - Upgrade master
 - Upgrade primary segments
 
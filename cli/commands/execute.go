@@ -4,8 +4,10 @@
 package commands
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
@@ -18,6 +20,8 @@ import (
 func execute() *cobra.Command {
 	var verbose bool
 	var nonInteractive bool
+	var onlyContents []int
+	var confirmPointOfNoReturn string
 
 	cmd := &cobra.Command{
 		Use:   "execute",
@@ -55,12 +59,16 @@ func execute() *cobra.Command {
 					return err
 				}
 
-				response, err = commanders.Execute(client, verbose)
-				if err != nil {
-					return err
+				request := &idl.ExecuteRequest{}
+				for _, content := range onlyContents {
+					request.OnlyContents = append(request.OnlyContents, int32(content))
 				}
 
-				return nil
+				return commanders.RunPastPointOfNoReturn(bufio.NewReader(os.Stdin), nonInteractive, confirmPointOfNoReturn, func(confirmation string) error {
+					request.PointOfNoReturnConfirmation = confirmation
+					response, err = commanders.Execute(cmd.Context(), client, request, verbose)
+					return err
+				})
 			})
 
 			return st.Complete(fmt.Sprintf(`
@@ -76,6 +84,9 @@ it will be inconsistent with the source database.
 
 NEXT ACTIONS
 ------------
+If data validation was enabled during initialize, run "gpupgrade validate" 
+to compare the upgraded data with the source cluster.
+
 If you are satisfied with the state of the cluster, run "gpupgrade finalize" 
 to proceed with the upgrade.
 
@@ -86,6 +97,8 @@ To return the cluster to its original state, run "gpupgrade revert".`,
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print the output stream from all substeps")
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt for confirmation to proceed")
+	cmd.Flags().IntSliceVar(&onlyContents, "only-content", nil, `retry upgrading only the primaries with these comma separated content ids. Run "gpupgrade segments" to see the status of each primary.`)
+	cmd.Flags().StringVar(&confirmPointOfNoReturn, "confirm-point-of-no-return", "", "acknowledge passing the point of no return with the token printed when execute stops before it, or shown by \"gpupgrade config show --point-of-no-return-token\"")
 	cmd.Flags().MarkHidden("non-interactive") //nolint
 
 	return addHelpToCommand(cmd, ExecuteHelp)
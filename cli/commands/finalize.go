@@ -4,8 +4,10 @@
 package commands
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -20,6 +22,7 @@ import (
 func finalize() *cobra.Command {
 	var verbose bool
 	var nonInteractive bool
+	var confirmPointOfNoReturn string
 
 	cmd := &cobra.Command{
 		Use:   "finalize",
@@ -56,16 +59,16 @@ func finalize() *cobra.Command {
 					return err
 				}
 
-				response, err = commanders.Finalize(client, verbose)
-				if err != nil {
+				request := &idl.FinalizeRequest{}
+				return commanders.RunPastPointOfNoReturn(bufio.NewReader(os.Stdin), nonInteractive, confirmPointOfNoReturn, func(confirmation string) error {
+					request.PointOfNoReturnConfirmation = confirmation
+					response, err = commanders.Finalize(cmd.Context(), client, request, verbose)
 					return err
-				}
-
-				return nil
+				})
 			})
 
 			st.RunCLISubstep(idl.Substep_STOP_HUB_AND_AGENTS, func(streams step.OutStreams) error {
-				return stopHubAndAgents(false)
+				return stopHubAndAgents(cmd.Context(), false)
 			})
 
 			st.RunCLISubstep(idl.Substep_DELETE_MASTER_STATEDIR, func(streams step.OutStreams) error {
@@ -91,7 +94,7 @@ To do so configure different ports to avoid conflicts.
 You may delete the source cluster to recover space from all hosts. 
 All source cluster data directories end in "%s".
 MASTER_DATA_DIRECTORY=%s
-
+%s
 The gpupgrade logs can be found on the master and segment hosts in
 %s
 
@@ -111,6 +114,7 @@ To use the upgraded cluster:
 				response.GetTargetCluster().GetCoordinatorDataDirectory(),
 				fmt.Sprintf("%s.<contentID>%s", response.GetUpgradeID(), upgrade.OldSuffix),
 				response.GetArchivedSourceCoordinatorDataDirectory(),
+				backupText(response.GetBackup()),
 				response.GetLogArchiveDirectory(),
 				filepath.Join(response.GetTargetCluster().GetGPHome(), "greenplum_path.sh"),
 				filepath.Join(filepath.Dir(response.GetTargetCluster().GetGPHome()), "greenplum-db"),
@@ -122,6 +126,21 @@ To use the upgraded cluster:
 
 	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "print the output stream from all substeps")
 	cmd.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt for confirmation to proceed")
+	cmd.Flags().StringVar(&confirmPointOfNoReturn, "confirm-point-of-no-return", "", "acknowledge passing the point of no return with the token printed when finalize stops before it, or shown by \"gpupgrade config show --point-of-no-return-token\"")
 	cmd.Flags().MarkHidden("non-interactive") //nolint
 	return addHelpToCommand(cmd, FinalizeHelp)
 }
+
+// backupText describes the backup of the source cluster taken during execute.
+func backupText(backup *idl.Backup) string {
+	if backup == nil {
+		return ""
+	}
+
+	text := fmt.Sprintf("\nThe source cluster was backed up by %s.\n", backup.GetDescription())
+	if backup.GetRestore() != "" {
+		text += fmt.Sprintf("To restore the source cluster from the backup run:\n%s\n", backup.GetRestore())
+	}
+
+	return text
+}
@@ -27,7 +27,10 @@ func init() {
 		idl.Substep_START_HUB,
 		idl.Substep_SAVING_SOURCE_CLUSTER_CONFIG,
 		idl.Substep_START_AGENTS,
+		idl.Substep_PREFLIGHT_CHECKS,
 		idl.Substep_CHECK_DISK_SPACE,
+		idl.Substep_INVENTORY_SOURCE_CLUSTER,
+		idl.Substep_SNAPSHOT_SOURCE_DATA,
 		idl.Substep_GENERATE_TARGET_CONFIG,
 		idl.Substep_INIT_TARGET_CLUSTER,
 		idl.Substep_SETTING_DYNAMIC_LIBRARY_PATH_ON_TARGET_CLUSTER,
@@ -36,11 +39,15 @@ func init() {
 		idl.Substep_CHECK_UPGRADE,
 	})
 	ExecuteHelp = GenerateHelpString(executeHelp, []idl.Substep{
+		idl.Substep_BACKUP_SOURCE_CLUSTER,
 		idl.Substep_SHUTDOWN_SOURCE_CLUSTER,
+		idl.Substep_SNAPSHOT_SOURCE_CLUSTER,
 		idl.Substep_UPGRADE_MASTER,
 		idl.Substep_COPY_MASTER,
+		idl.Substep_TRANSFER_SOURCE_PRIMARIES,
 		idl.Substep_UPGRADE_PRIMARIES,
 		idl.Substep_START_TARGET_CLUSTER,
+		idl.Substep_INVENTORY_TARGET_CLUSTER,
 	})
 	FinalizeHelp = GenerateHelpString(finalizeHelp, []idl.Substep{
 		idl.Substep_REMOVE_SOURCE_MIRRORS,
@@ -51,8 +58,11 @@ func init() {
 		idl.Substep_UPDATE_TARGET_CATALOG,
 		idl.Substep_UPDATE_DATA_DIRECTORIES,
 		idl.Substep_UPDATE_TARGET_CONF_FILES,
+		idl.Substep_POINT_OF_NO_RETURN,
 		idl.Substep_START_TARGET_CLUSTER,
 		idl.Substep_WAIT_FOR_CLUSTER_TO_BE_READY_AFTER_UPDATING_CATALOG,
+		idl.Substep_INVENTORY_TARGET_CLUSTER,
+		idl.Substep_DELETE_SOURCE_SNAPSHOTS,
 		idl.Substep_ARCHIVE_LOG_DIRECTORIES,
 		idl.Substep_DELETE_SEGMENT_STATEDIRS,
 		idl.Substep_STOP_HUB_AND_AGENTS,
@@ -60,11 +70,15 @@ func init() {
 		idl.Substep_STOP_TARGET_CLUSTER,
 	})
 	RevertHelp = GenerateHelpString(revertHelp, []idl.Substep{
+		idl.Substep_RESTORE_TARGET_CONF_FILES,
+		idl.Substep_RESTORE_DATA_DIRECTORIES,
 		idl.Substep_SHUTDOWN_TARGET_CLUSTER,
 		idl.Substep_DELETE_TARGET_CLUSTER_DATADIRS,
 		idl.Substep_DELETE_TABLESPACES,
 		idl.Substep_RESTORE_PGCONTROL,
 		idl.Substep_RESTORE_SOURCE_CLUSTER,
+		idl.Substep_RESTORE_SOURCE_SNAPSHOTS,
+		idl.Substep_DELETE_SOURCE_SNAPSHOTS,
 		idl.Substep_START_SOURCE_CLUSTER,
 		idl.Substep_RECOVERSEG_SOURCE_CLUSTER,
 		idl.Substep_ARCHIVE_LOG_DIRECTORIES,
@@ -94,7 +108,8 @@ Usage: gpupgrade initialize --file <path/to/config_file>
 Required Flags:
 
   -f, --file      config file containing upgrade parameters
-                  (e.g. gpupgrade_config)
+                  (e.g. gpupgrade_config) in either its name = value
+                  format or YAML
 
 Optional Flags:
 
@@ -113,12 +128,27 @@ Execute will carry out the following steps:
 During or after gpupgrade execute, you may revert the cluster to its
 original state by running gpupgrade revert.
 
+In link mode, when the source cluster has neither snapshots nor mirrors and
+standby, starting the target cluster cannot be reverted. Execute stops before
+it unless you acknowledge passing the point of no return, or a backup of the
+source cluster was registered within the last day. This includes running with
+--non-interactive, so automation should pass the token shown by
+"gpupgrade config show --point-of-no-return-token".
+
 Usage: gpupgrade execute
 
 Optional Flags:
 
-  -h, --help      displays help output for execute
-  -v, --verbose   outputs detailed logs for execute
+  --confirm-point-of-no-return   acknowledges passing the point of no return
+                                 with the token printed when execute stops
+                                 before it, or shown by "gpupgrade config
+                                 show --point-of-no-return-token"
+  -h, --help                     displays help output for execute
+  --only-content                 retries upgrading only the primaries with the
+                                 given comma separated content ids. Run
+                                 "gpupgrade segments" to see the status of
+                                 each primary.
+  -v, --verbose                  outputs detailed logs for execute
 
 gpupgrade log files can be found on all hosts in %s
 `
@@ -128,15 +158,26 @@ This command should be run only during a downtime window.
 
 Finalize will carry out the following steps:
 %s
-Once you run gpupgrade finalize, you may NOT revert the cluster to its
-original state.
+You may revert the cluster to its original state until finalize passes its
+point of no return, which is right before starting the target cluster. In link
+mode without snapshots of the source cluster, it is instead right before
+upgrading the mirrors when the source cluster has user defined tablespaces or
+the mirrors are resynced incrementally. Finalize stops before its point of no
+return unless you acknowledge passing it, or a backup of the source cluster was
+registered within the last day. This includes running with --non-interactive,
+so automation should pass the token shown by
+"gpupgrade config show --point-of-no-return-token".
 
 Usage: gpupgrade finalize
 
 Optional Flags:
 
-  -h, --help      displays help output for finalize
-  -v, --verbose   outputs detailed logs for finalize
+  --confirm-point-of-no-return   acknowledges passing the point of no return
+                                 with the token printed when finalize stops
+                                 before it, or shown by "gpupgrade config
+                                 show --point-of-no-return-token"
+  -h, --help                     displays help output for finalize
+  -v, --verbose                  outputs detailed logs for finalize
 
 NOTE: After running finalize, you must execute data migration scripts. 
 Refer to documentation for instructions.
@@ -145,7 +186,8 @@ gpupgrade log files can be found on all hosts in %s
 `
 const revertHelp = `
 Returns the cluster to its original state.
-This command cannot be run after gpupgrade finalize has begun.
+This command cannot be run once gpupgrade finalize has passed its point of
+no return.
 This command should be run only during a downtime window.
 
 Revert will carry out some or all of the following steps:
@@ -192,8 +234,26 @@ Required Commands: Run the three commands in this order
 
 Optional Commands:
 
+  config init     writes a config file for the running source cluster
+
+  config validate validates a config file the way initialize does without
+                  starting the hub
+
   revert          returns the cluster to its original state
-                  Note: revert cannot be used after gpupgrade finalize
+                  Note: revert cannot be used once gpupgrade finalize has
+                  passed its point of no return
+
+  inventory       compares the object counts of the source and target clusters
+                  taken during initialize, execute, and finalize
+
+  validate        compares the row counts of the upgraded target cluster with
+                  the source snapshot taken during initialize
+                  Note: validate must be run after execute and before finalize
+
+  segments        lists the upgrade status of each primary segment
+
+  support-bundle  collects the state and logs of all hosts into a single
+                  tarball to attach to a support ticket
 
 Optional Flags:
 
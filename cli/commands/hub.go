@@ -8,11 +8,11 @@ import (
 	"os"
 	"runtime/debug"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
 	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
@@ -34,9 +34,6 @@ func Hub() *cobra.Command {
 			if err != nil {
 				return err
 			}
-			gplog.InitializeLogging("gpupgrade_hub", logdir)
-			debug.SetTraceback("all")
-			defer log.WritePanics()
 
 			stateDir := utils.GetStateDir()
 			finfo, err := os.Stat(stateDir)
@@ -55,7 +52,7 @@ func Hub() *cobra.Command {
 			conf := &hub.Config{
 				Port:      port,
 				AgentPort: upgrade.DefaultAgentPort,
-				LinkMode:  false,
+				Mode:      idl.Mode_copy,
 			}
 
 			err = hub.LoadConfig(conf, upgrade.GetConfigFile())
@@ -63,6 +60,14 @@ func Hub() *cobra.Command {
 				return err
 			}
 
+			// Logging starts once the configuration has been loaded since
+			// it has the log format.
+			if err := log.Initialize("gpupgrade_hub", logdir, conf.LogFormat, conf.LogFields()); err != nil {
+				return err
+			}
+			debug.SetTraceback("all")
+			defer log.WritePanics()
+
 			// allow command line args precedence over config file values
 			if cmd.Flag("port").Changed {
 				conf.Port = port
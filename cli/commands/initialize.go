@@ -4,8 +4,10 @@
 package commands
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -17,11 +19,18 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/backup"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+	"github.com/greenplum-db/gpupgrade/utils/snapshot"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+	"github.com/greenplum-db/gpupgrade/utils/validation"
 )
 
 const InitializeWarningMessage = `
@@ -38,18 +47,9 @@ you run "gpupgrade revert" now and take a backup of the cluster.
 func initialize() *cobra.Command {
 	var file string
 	var nonInteractive bool
-	var sourceGPHome, targetGPHome string
-	var sourcePort int
-	var hubPort int
-	var agentPort int
-	var diskFreeRatio float64
 	var stopBeforeClusterCreation bool
 	var verbose bool
-	var skipVersionCheck bool
-	var ports string
-	var mode string
-	var useHbaHostnames bool
-	var dynamicLibraryPath string
+	var opts initializeOptions
 
 	subInit := &cobra.Command{
 		Use:   "initialize",
@@ -119,29 +119,7 @@ func initialize() *cobra.Command {
 				}
 			}
 
-			linkMode, err := isLinkMode(mode)
-			if err != nil {
-				return err
-			}
-
-			// if diskFreeRatio is not explicitly set, use defaults
-			if !cmd.Flag("disk-free-ratio").Changed {
-				if linkMode {
-					diskFreeRatio = 0.2
-				} else {
-					diskFreeRatio = 0.6
-				}
-			}
-
-			if diskFreeRatio < 0.0 || diskFreeRatio > 1.0 {
-				// Match Cobra's option-error format.
-				return fmt.Errorf(
-					`invalid argument %g for "--disk-free-ratio" flag: value must be between 0.0 and 1.0`,
-					diskFreeRatio,
-				)
-			}
-
-			parsedPorts, err := parsePorts(ports)
+			err = opts.validate(cmd.Flags())
 			if err != nil {
 				return err
 			}
@@ -168,8 +146,13 @@ func initialize() *cobra.Command {
 				return err
 			}
 
+			diskFreeRatioText := fmt.Sprintf("%.1f", opts.diskFreeRatio)
+			if opts.estimateDiskSpace {
+				diskFreeRatioText = "estimated from cluster size"
+			}
+
 			confirmationText := fmt.Sprintf(initializeConfirmationText, logdir, configPath,
-				sourcePort, sourceGPHome, targetGPHome, mode, diskFreeRatio, useHbaHostnames, dynamicLibraryPath, ports, hubPort, agentPort)
+				opts.sourcePort, opts.sourceGPHome, opts.targetGPHome, opts.mode, opts.snapshotProvider, opts.mirrorResync, opts.backupProvider, opts.backupCommand, opts.backupTimestamp, diskFreeRatioText, opts.dataValidation, opts.useHbaHostnames, opts.dynamicLibraryPath, opts.ports, opts.portStrategy, opts.targetPorts, opts.keepTempPorts, opts.targetLayout, opts.targetHosts, opts.hubPort, opts.metricsPort, opts.agentPort, opts.traceExporter, opts.traceEndpoint, opts.notifyWebhook, opts.notifyEmail, opts.notifySMTP, opts.logFormat)
 
 			st, err := commanders.NewStep(idl.Step_INITIALIZE,
 				&step.BufferedStreams{},
@@ -187,20 +170,24 @@ func initialize() *cobra.Command {
 			}
 
 			st.RunInternalSubstep(func() error {
-				if skipVersionCheck {
+				if opts.skipVersionCheck {
 					return nil
 				}
 
-				err := greenplum.VerifyCompatibleGPDBVersions(sourceGPHome, targetGPHome)
+				path, err := greenplum.VerifyCompatibleGPDBVersions(opts.sourceGPHome, opts.targetGPHome)
 				if err != nil {
 					return err
 				}
 
+				if note := path.MigrationNote(); note != "" {
+					fmt.Printf("\n%s\n\n", note)
+				}
+
 				return nil
 			})
 
 			st.RunInternalSubstep(func() error {
-				return commanders.CreateInitialClusterConfigs(hubPort)
+				return commanders.CreateInitialClusterConfigs(opts.hubPort, opts.metricsPort, trace.Config{Exporter: opts.traceExporter, Endpoint: opts.traceEndpoint}, opts.notifications, opts.logFormat)
 			})
 
 			st.RunCLISubstep(idl.Substep_START_HUB, func(streams step.OutStreams) error {
@@ -215,16 +202,28 @@ func initialize() *cobra.Command {
 				}
 
 				request := &idl.InitializeRequest{
-					AgentPort:       int32(agentPort),
-					SourceGPHome:    filepath.Clean(sourceGPHome),
-					TargetGPHome:    filepath.Clean(targetGPHome),
-					SourcePort:      int32(sourcePort),
-					LinkMode:        linkMode,
-					UseHbaHostnames: useHbaHostnames,
-					Ports:           parsedPorts,
-					DiskFreeRatio:   diskFreeRatio,
+					AgentPort:           int32(opts.agentPort),
+					SourceGPHome:        filepath.Clean(opts.sourceGPHome),
+					TargetGPHome:        filepath.Clean(opts.targetGPHome),
+					SourcePort:          int32(opts.sourcePort),
+					Mode:                opts.upgradeMode,
+					UseHbaHostnames:     opts.useHbaHostnames,
+					Ports:               opts.parsedPorts,
+					DiskFreeRatio:       opts.diskFreeRatio,
+					EstimateDiskSpace:   opts.estimateDiskSpace,
+					DataValidation:      opts.dataValidation,
+					SnapshotProvider:    opts.snapshotProvider,
+					MirrorResync:        opts.mirrorResync,
+					BackupProvider:      opts.backupProvider,
+					BackupCommand:       opts.backupCommand,
+					BackupTimestamp:     opts.backupTimestamp,
+					TargetDatadirLayout: opts.layout,
+					TargetHostMapping:   opts.hostMapping,
+					PortStrategy:        opts.portStrategy,
+					TargetPortMap:       opts.portMap,
+					KeepTempPorts:       opts.keepTempPorts,
 				}
-				err = commanders.Initialize(client, request, verbose)
+				err = commanders.Initialize(cmd.Context(), client, request, verbose)
 				if err != nil {
 					return err
 				}
@@ -239,9 +238,9 @@ func initialize() *cobra.Command {
 				}
 
 				request := &idl.InitializeCreateClusterRequest{
-					DynamicLibraryPath: dynamicLibraryPath,
+					DynamicLibraryPath: opts.dynamicLibraryPath,
 				}
-				response, err = commanders.InitializeCreateCluster(client, request, verbose)
+				response, err = commanders.InitializeCreateCluster(cmd.Context(), client, request, verbose)
 				if err != nil {
 					return err
 				}
@@ -269,23 +268,252 @@ To return the cluster to its original state, run "gpupgrade revert".`,
 	subInit.Flags().BoolVarP(&nonInteractive, "automatic", "a", false, "do not prompt for confirmation to proceed")
 	subInit.Flags().BoolVar(&nonInteractive, "non-interactive", false, "do not prompt for confirmation to proceed")
 	subInit.Flags().MarkHidden("non-interactive") //nolint
-	subInit.Flags().IntVar(&sourcePort, "source-master-port", 0, "master port for source gpdb cluster")
-	subInit.Flags().StringVar(&sourceGPHome, "source-gphome", "", "path for the source Greenplum installation")
-	subInit.Flags().StringVar(&targetGPHome, "target-gphome", "", "path for the target Greenplum installation")
-	subInit.Flags().StringVar(&mode, "mode", "copy", "performs upgrade in either copy or link mode. Default is copy.")
-	subInit.Flags().Float64Var(&diskFreeRatio, "disk-free-ratio", 0.60, "percentage of disk space that must be available (from 0.0 - 1.0)")
-	subInit.Flags().BoolVar(&useHbaHostnames, "use-hba-hostnames", false, "use hostnames in pg_hba.conf")
-	subInit.Flags().StringVar(&dynamicLibraryPath, "dynamic-library-path", upgrade.DefaultDynamicLibraryPath, "sets the dynamic_library_path GUC to correctly find extensions installed outside their default location. Defaults to '$dynamic_library_path'.")
-	subInit.Flags().StringVar(&ports, "temp-port-range", "50432-65535", "set of ports to use when initializing the target cluster")
-	subInit.Flags().IntVar(&hubPort, "hub-port", upgrade.DefaultHubPort, "the port gpupgrade hub uses to listen for commands on")
-	subInit.Flags().IntVar(&agentPort, "agent-port", upgrade.DefaultAgentPort, "the port gpupgrade agent uses to listen for commands on")
+	opts.addFlags(subInit.Flags())
 	subInit.Flags().BoolVar(&stopBeforeClusterCreation, "stop-before-cluster-creation", false, "only run up to pre-init")
 	subInit.Flags().MarkHidden("stop-before-cluster-creation") //nolint
-	subInit.Flags().BoolVar(&skipVersionCheck, "skip-version-check", false, "disable source and target version check")
-	subInit.Flags().MarkHidden("skip-version-check") //nolint
 	return addHelpToCommand(subInit, InitializeHelp)
 }
 
+const defaultTempPortRange = "50432-65535"
+
+// initializeOptions are the initialize parameters that are set by flags or
+// the config file.
+type initializeOptions struct {
+	sourceGPHome       string
+	targetGPHome       string
+	sourcePort         int
+	hubPort            int
+	metricsPort        int
+	agentPort          int
+	diskFreeRatio      float64
+	skipVersionCheck   bool
+	ports              string
+	mode               string
+	useHbaHostnames    bool
+	dynamicLibraryPath string
+	dataValidation     string
+	snapshotProvider   string
+	mirrorResync       string
+	backupProvider     string
+	backupCommand      string
+	backupTimestamp    string
+	targetLayout       string
+	targetHosts        string
+	portStrategy       string
+	targetPorts        string
+	keepTempPorts      bool
+	traceExporter      string
+	traceEndpoint      string
+	notifyWebhook      string
+	notifyEmail        string
+	notifySMTP         string
+	logFormat          string
+
+	// Set by validate.
+	upgradeMode       idl.Mode
+	parsedPorts       []uint32
+	estimateDiskSpace bool
+	layout            string
+	hostMapping       string
+	portMap           string
+	notifications     notify.Config
+}
+
+func (o *initializeOptions) addFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&o.sourcePort, "source-master-port", 0, "master port for source gpdb cluster")
+	flags.StringVar(&o.sourceGPHome, "source-gphome", "", "path for the source Greenplum installation")
+	flags.StringVar(&o.targetGPHome, "target-gphome", "", "path for the target Greenplum installation")
+	flags.StringVar(&o.mode, "mode", "copy", "performs upgrade in either copy, link, or clone mode. Default is copy.")
+	flags.StringVar(&o.snapshotProvider, "snapshot-provider", snapshot.None, "in link mode snapshots the source cluster before upgrading it so that revert can restore it from the snapshots. Either none, lvm, zfs, btrfs, or reflink. Default is none.")
+	flags.Float64Var(&o.diskFreeRatio, "disk-free-ratio", 0.0, "percentage of disk space that must be available (from 0.0 - 1.0). By default the required disk space is estimated from the cluster size.")
+	flags.StringVar(&o.mirrorResync, "mirror-resync", hub.FullResync, "in link mode either full or incremental. An incremental resync only sends the mirror files that differ from the source mirrors by checksum. Default is full.")
+	flags.StringVar(&o.backupProvider, "backup-provider", backup.None, "backs up the source cluster during execute before it is shut down. Either none, gpbackup, or command. Default is none.")
+	flags.StringVar(&o.backupCommand, "backup-command", "", "the command template run with bash by the command backup provider. It must print the backup identity as its last line of output.")
+	flags.StringVar(&o.backupTimestamp, "backup-timestamp", "", "verifies the existing backup with this identity, such as comma separated gpbackup timestamps, rather than taking a new one")
+	flags.StringVar(&o.dataValidation, "data-validation", validation.None, "snapshots the source data to validate the upgraded data with \"gpupgrade validate\". Either none, row-counts, or sampled-hashes. Default is none.")
+	flags.BoolVar(&o.useHbaHostnames, "use-hba-hostnames", false, "use hostnames in pg_hba.conf")
+	flags.StringVar(&o.dynamicLibraryPath, "dynamic-library-path", upgrade.DefaultDynamicLibraryPath, "sets the dynamic_library_path GUC to correctly find extensions installed outside their default location. Defaults to '$dynamic_library_path'.")
+	flags.StringVar(&o.ports, "temp-port-range", defaultTempPortRange, "set of ports to use when initializing the target cluster")
+	flags.StringVar(&o.portStrategy, "port-strategy", hub.SequentialPorts, "assigns the target ports either sequentially from the temp port range, offset from the source ports into the temp port range, or from the target port map. Either sequential, offset, or map. Default is sequential.")
+	flags.StringVar(&o.targetPorts, "target-port-map", "", "with the map port strategy a file giving the target port of each segment by its source host and port")
+	flags.BoolVar(&o.keepTempPorts, "keep-temp-ports", false, "keep the temporary ports after finalize rather than reconfiguring the target cluster to use the source ports")
+	flags.StringVar(&o.targetHosts, "target-host-mapping", "", "in copy mode a file mapping source hosts to the new hosts that their segments are upgraded onto")
+	flags.StringVar(&o.targetLayout, "target-datadir-layout", "", "in copy mode a file placing the target data directories by dbid or host path prefix rather than next to the source data directories")
+	flags.IntVar(&o.hubPort, "hub-port", upgrade.DefaultHubPort, "the port gpupgrade hub uses to listen for commands on")
+	flags.IntVar(&o.metricsPort, "metrics-port", 0, "the port gpupgrade hub serves Prometheus metrics on at /metrics. By default metrics are not served.")
+	flags.IntVar(&o.agentPort, "agent-port", upgrade.DefaultAgentPort, "the port gpupgrade agent uses to listen for commands on")
+	flags.StringVar(&o.traceExporter, "trace-exporter", trace.None, "exports spans of the CLI, hub, and agents to a file in the log directory on each host or to an OTLP endpoint. Either none, file, or otlp. Default is none.")
+	flags.StringVar(&o.traceEndpoint, "trace-endpoint", "", "the OTLP/HTTP endpoint, such as http://collector:4318, for the otlp trace exporter")
+	flags.StringVar(&o.notifyWebhook, "notify-webhook", "", "the URL the hub posts JSON notifications to when steps start and finish, substeps fail, and at the point of no return")
+	flags.StringVar(&o.notifyEmail, "notify-email", "", "comma separated email addresses the hub mails the notifications to using sendmail or the SMTP relay")
+	flags.StringVar(&o.notifySMTP, "notify-smtp", "", "the host:port of an SMTP relay to send the notification email through rather than sendmail")
+	flags.StringVar(&o.logFormat, "log-format", log.Text, "the format of the CLI, hub, and agent log files. Either text or json, which writes a JSON entry per line to .jsonl log files. Default is text.")
+	flags.BoolVar(&o.skipVersionCheck, "skip-version-check", false, "disable source and target version check")
+	flags.MarkHidden("skip-version-check") //nolint
+}
+
+// validate checks and normalizes the options without contacting the cluster.
+func (o *initializeOptions) validate(flags *pflag.FlagSet) error {
+	var err error
+
+	o.upgradeMode, err = parseMode(o.mode)
+	if err != nil {
+		return err
+	}
+
+	o.dataValidation, err = validation.ParseMode(o.dataValidation)
+	if err != nil {
+		return err
+	}
+
+	o.snapshotProvider, err = snapshot.ParseProvider(o.snapshotProvider)
+	if err != nil {
+		return err
+	}
+
+	if o.upgradeMode != idl.Mode_link && o.snapshotProvider != snapshot.None {
+		return fmt.Errorf("The snapshot provider %q requires link mode. %s mode does not modify the source cluster.", o.snapshotProvider, strings.Title(o.upgradeMode.String()))
+	}
+
+	o.mirrorResync, err = parseMirrorResync(o.mirrorResync)
+	if err != nil {
+		return err
+	}
+
+	if o.upgradeMode != idl.Mode_link && o.mirrorResync == hub.IncrementalResync {
+		return fmt.Errorf("The %s mirror resync requires link mode. In %s mode the mirrors are created with gpaddmirrors.", o.mirrorResync, o.upgradeMode)
+	}
+
+	o.backupProvider, err = backup.ParseProvider(o.backupProvider)
+	if err != nil {
+		return err
+	}
+
+	if o.backupProvider == backup.Command {
+		if _, err := backup.ParseCommand(o.backupCommand); err != nil {
+			return err
+		}
+	} else if o.backupCommand != "" {
+		return fmt.Errorf("The backup command requires the %q backup provider.", backup.Command)
+	}
+
+	if o.backupProvider == backup.None && o.backupTimestamp != "" {
+		return errors.New("The backup timestamp requires a backup provider to verify it.")
+	}
+
+	// Unless diskFreeRatio is explicitly set estimate the disk space
+	// needed from the size of the cluster. An explicit ratio of 0
+	// skips the disk space check.
+	o.estimateDiskSpace = !flags.Changed("disk-free-ratio")
+	if o.estimateDiskSpace {
+		o.diskFreeRatio = 0
+	}
+
+	if o.diskFreeRatio < 0.0 || o.diskFreeRatio > 1.0 {
+		// Match Cobra's option-error format.
+		return fmt.Errorf(
+			`invalid argument %g for "--disk-free-ratio" flag: value must be between 0.0 and 1.0`,
+			o.diskFreeRatio,
+		)
+	}
+
+	o.parsedPorts, err = parsePorts(o.ports)
+	if err != nil {
+		return err
+	}
+
+	if o.metricsPort < 0 || o.metricsPort > 65535 {
+		return fmt.Errorf("Invalid metrics port %d. Please specify a port from 1 to 65535, or 0 to not serve metrics.", o.metricsPort)
+	}
+
+	if o.metricsPort != 0 && (o.metricsPort == o.hubPort || o.metricsPort == o.agentPort) {
+		return fmt.Errorf("The metrics port %d must differ from the hub and agent ports.", o.metricsPort)
+	}
+
+	o.traceExporter, err = trace.ParseExporter(o.traceExporter)
+	if err != nil {
+		return err
+	}
+
+	if o.traceExporter == trace.OTLP {
+		if err := trace.ParseEndpoint(o.traceEndpoint); err != nil {
+			return err
+		}
+	} else if o.traceEndpoint != "" {
+		return fmt.Errorf("The trace endpoint requires the %q trace exporter.", trace.OTLP)
+	}
+
+	o.notifications, err = notify.ParseConfig(o.notifyWebhook, o.notifyEmail, o.notifySMTP)
+	if err != nil {
+		return err
+	}
+
+	o.logFormat, err = log.ParseFormat(o.logFormat)
+	if err != nil {
+		return err
+	}
+
+	o.portStrategy, err = parsePortStrategy(o.portStrategy)
+	if err != nil {
+		return err
+	}
+
+	if o.portStrategy == hub.MappedPorts {
+		if o.targetPorts == "" {
+			return fmt.Errorf("The %s port strategy requires a target port map.", o.portStrategy)
+		}
+
+		ports, err := ioutil.ReadFile(o.targetPorts)
+		if err != nil {
+			return xerrors.Errorf("reading target port map: %w", err)
+		}
+
+		if _, err := upgrade.ParsePortMap(bytes.NewReader(ports)); err != nil {
+			return xerrors.Errorf("invalid target port map %q: %w", o.targetPorts, err)
+		}
+
+		o.portMap = string(ports)
+	} else if o.targetPorts != "" {
+		return fmt.Errorf("The target port map requires the %q port strategy.", hub.MappedPorts)
+	}
+
+	if o.targetLayout != "" {
+		if o.upgradeMode != idl.Mode_copy {
+			return fmt.Errorf("The target data directory layout requires copy mode. In %s mode the target data directories must share a filesystem with the source.", o.upgradeMode)
+		}
+
+		layout, err := ioutil.ReadFile(o.targetLayout)
+		if err != nil {
+			return xerrors.Errorf("reading target data directory layout: %w", err)
+		}
+
+		if _, err := upgrade.ParseLayout(bytes.NewReader(layout)); err != nil {
+			return xerrors.Errorf("invalid target data directory layout %q: %w", o.targetLayout, err)
+		}
+
+		o.layout = string(layout)
+	}
+
+	if o.targetHosts != "" {
+		if o.upgradeMode != idl.Mode_copy {
+			return fmt.Errorf("The target host mapping requires copy mode. In %s mode the target segments must share a filesystem with the source.", o.upgradeMode)
+		}
+
+		hosts, err := ioutil.ReadFile(o.targetHosts)
+		if err != nil {
+			return xerrors.Errorf("reading target host mapping: %w", err)
+		}
+
+		if _, err := upgrade.ParseHostMapping(bytes.NewReader(hosts)); err != nil {
+			return xerrors.Errorf("invalid target host mapping %q: %w", o.targetHosts, err)
+		}
+
+		o.hostMapping = string(hosts)
+	}
+
+	return nil
+}
+
 func parsePorts(val string) ([]uint32, error) {
 	var ports []uint32
 
@@ -328,19 +556,48 @@ func parsePorts(val string) ([]uint32, error) {
 	return ports, nil
 }
 
-// isLinkMode parses the mode flag returning an error if it is not copy or link.
-// It returns true if mode is link.
-func isLinkMode(input string) (bool, error) {
-	choices := []string{"copy", "link"}
+// parseMode parses the mode flag returning an error if it is not copy, link,
+// or clone.
+func parseMode(input string) (idl.Mode, error) {
+	choices := []idl.Mode{idl.Mode_copy, idl.Mode_link, idl.Mode_clone}
 
 	mode := strings.ToLower(strings.TrimSpace(input))
+	var names []string
 	for _, choice := range choices {
-		if mode == choice {
-			return mode == "link", nil
+		if mode == choice.String() {
+			return choice, nil
 		}
+
+		names = append(names, choice.String())
 	}
 
-	return false, fmt.Errorf("Invalid input %q. Please specify either %s.", input, strings.Join(choices, " or "))
+	return idl.Mode_UNKNOWN_MODE, fmt.Errorf("Invalid input %q. Please specify either %s, or %s.", input, strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+// parseMirrorResync parses the mirror-resync flag returning an error if it is
+// not one of hub.MirrorResyncMethods.
+func parseMirrorResync(input string) (string, error) {
+	method := strings.ToLower(strings.TrimSpace(input))
+	for _, choice := range hub.MirrorResyncMethods {
+		if method == choice {
+			return method, nil
+		}
+	}
+
+	return "", fmt.Errorf("Invalid mirror resync %q. Please specify either %s.", input, strings.Join(hub.MirrorResyncMethods, " or "))
+}
+
+// parsePortStrategy parses the port-strategy flag returning an error if it is
+// not one of hub.PortStrategies.
+func parsePortStrategy(input string) (string, error) {
+	strategy := strings.ToLower(strings.TrimSpace(input))
+	for _, choice := range hub.PortStrategies {
+		if strategy == choice {
+			return strategy, nil
+		}
+	}
+
+	return "", fmt.Errorf("Invalid port strategy %q. Please specify one of %s.", input, strings.Join(hub.PortStrategies, ", "))
 }
 
 func addFlags(cmd *cobra.Command, flags map[string]string) error {
@@ -351,6 +608,10 @@ func addFlags(cmd *cobra.Command, flags map[string]string) error {
 			cmd.Flags().VisitAll(func(flag *pflag.Flag) {
 				names = append(names, flag.Name)
 			})
+			if suggestion := closest(name, names); suggestion != "" {
+				return xerrors.Errorf("The configuration parameter %q was not found in the list of supported parameters: %s. Did you mean %q?", name, strings.Join(names, ", "), suggestion)
+			}
+
 			return xerrors.Errorf("The configuration parameter %q was not found in the list of supported parameters: %s.", name, strings.Join(names, ", "))
 		}
 
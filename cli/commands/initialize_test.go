@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
@@ -64,43 +65,48 @@ func TestParsePorts(t *testing.T) {
 	}
 }
 
-func TestIsLinkMode(t *testing.T) {
+func TestParseMode(t *testing.T) {
 	cases := []struct {
 		name     string
 		mode     string
-		expected bool
+		expected idl.Mode
 	}{
 		{
 			name:     "parses copy",
 			mode:     "copy",
-			expected: false,
+			expected: idl.Mode_copy,
 		},
 		{
 			name:     "parses link",
 			mode:     "link",
-			expected: true,
+			expected: idl.Mode_link,
+		},
+		{
+			name:     "parses clone",
+			mode:     "clone",
+			expected: idl.Mode_clone,
 		},
 		{
 			name:     "parses capitalizations",
 			mode:     "LiNk",
-			expected: true,
+			expected: idl.Mode_link,
 		},
 		{
 			name:     "trims spaces",
 			mode:     " link  \t",
-			expected: true,
+			expected: idl.Mode_link,
 		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			linkMode, err := isLinkMode(c.mode)
+			mode, err := parseMode(c.mode)
 			if err != nil {
 				t.Errorf("unexpected error %#v", err)
 			}
 
-			if linkMode != c.expected {
-				t.Errorf("got %t want %t", linkMode, c.expected)
+			if mode != c.expected {
+				t.Errorf("got %s want %s", mode, c.expected)
 			}
 		})
 	}
@@ -121,22 +127,66 @@ func TestIsLinkMode(t *testing.T) {
 			name: "errors on numbers",
 			mode: "1",
 		},
+		{
+			name: "errors on the unknown mode",
+			mode: "unknown_mode",
+		},
 	}
 
 	for _, c := range errCases {
 		t.Run(c.name, func(t *testing.T) {
-			linkMode, err := isLinkMode(c.mode)
+			mode, err := parseMode(c.mode)
 			if err == nil {
-				t.Errorf("isLinkMode(%q) returned %v instead of an error", c.mode, err)
+				t.Errorf("parseMode(%q) returned %v instead of an error", c.mode, err)
 			}
 
-			if linkMode != false {
-				t.Errorf("got linkMode %t want %t", linkMode, false)
+			if mode != idl.Mode_UNKNOWN_MODE {
+				t.Errorf("got mode %s want %s", mode, idl.Mode_UNKNOWN_MODE)
 			}
 		})
 	}
 }
 
+func TestParseMirrorResync(t *testing.T) {
+	for _, input := range []string{"full", "incremental", " Incremental\t"} {
+		method, err := parseMirrorResync(input)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := strings.ToLower(strings.TrimSpace(input))
+		if method != expected {
+			t.Errorf("parseMirrorResync(%q) = %q want %q", input, method, expected)
+		}
+	}
+
+	_, err := parseMirrorResync("partial")
+	expected := `Invalid mirror resync "partial". Please specify either full or incremental.`
+	if err == nil || err.Error() != expected {
+		t.Errorf("got error %v want %q", err, expected)
+	}
+}
+
+func TestParsePortStrategy(t *testing.T) {
+	for _, input := range []string{"sequential", "offset", " Map\t"} {
+		strategy, err := parsePortStrategy(input)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := strings.ToLower(strings.TrimSpace(input))
+		if strategy != expected {
+			t.Errorf("parsePortStrategy(%q) = %q want %q", input, strategy, expected)
+		}
+	}
+
+	_, err := parsePortStrategy("random")
+	expected := `Invalid port strategy "random". Please specify one of sequential, offset, map.`
+	if err == nil || err.Error() != expected {
+		t.Errorf("got error %v want %q", err, expected)
+	}
+}
+
 func TestAddFlags(t *testing.T) {
 	t.Run("sets flags to correct value and marks them as changed", func(t *testing.T) {
 		var name string
@@ -0,0 +1,81 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
+)
+
+func inventoryCmd() *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "compares the object inventories of the source and target clusters",
+		Long: `Compares the object inventory of the source cluster taken during initialize
+with the inventories of the target cluster taken after execute and finalize.
+
+After finalize the inventories are archived with the gpupgrade logs. Use
+--dir to specify the inventory directory within the log archive.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dir == "" {
+				var err error
+				dir, err = utils.GetInventoryDir()
+				if err != nil {
+					return err
+				}
+			}
+
+			reports, err := InventoryReports(dir)
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(reports)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "the inventory directory. Defaults to the inventory directory in the gpupgrade log directory.")
+
+	return cmd
+}
+
+// InventoryReports returns the reports comparing each target inventory in dir
+// with the source inventory.
+func InventoryReports(dir string) (string, error) {
+	source, err := inventory.Load(inventory.Path(dir, inventory.Source))
+	if err != nil {
+		return "", xerrors.Errorf("loading source inventory: %w", err)
+	}
+
+	var reports []string
+	for _, name := range []string{inventory.TargetAfterExecute, inventory.TargetAfterFinalize} {
+		target, err := inventory.Load(inventory.Path(dir, name))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return "", xerrors.Errorf("loading %s inventory: %w", name, err)
+		}
+
+		reports = append(reports, inventory.Report(source, target))
+	}
+
+	if len(reports) == 0 {
+		return "", fmt.Errorf("No target cluster inventory found in %s. The target inventory is taken after execute and finalize.", dir)
+	}
+
+	return strings.Join(reports, "\n"), nil
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/cli/commands"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
+)
+
+func TestInventoryReports(t *testing.T) {
+	source := inventory.Snapshot{Name: inventory.Source, Version: "7.0.0", Roles: 3}
+	afterExecute := inventory.Snapshot{Name: inventory.TargetAfterExecute, Version: "7.1.0", Roles: 3}
+	afterFinalize := inventory.Snapshot{Name: inventory.TargetAfterFinalize, Version: "7.1.0", Roles: 2}
+
+	t.Run("returns a report for each target inventory", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		for _, snapshot := range []inventory.Snapshot{source, afterExecute, afterFinalize} {
+			if err := inventory.Save(dir, snapshot); err != nil {
+				t.Fatalf("unexpected error %#v", err)
+			}
+		}
+
+		reports, err := commands.InventoryReports(dir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := inventory.Report(source, afterExecute) + "\n" + inventory.Report(source, afterFinalize)
+		if reports != expected {
+			t.Errorf("got %q want %q", reports, expected)
+		}
+	})
+
+	t.Run("errors when the source inventory does not exist", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		_, err := commands.InventoryReports(dir)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want %#v", err, os.ErrNotExist)
+		}
+	})
+
+	t.Run("errors when no target inventory exists", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		if err := inventory.Save(dir, source); err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		_, err := commands.InventoryReports(dir)
+		expected := "No target cluster inventory found"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+}
@@ -5,10 +5,14 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
 	"io"
+	"io/ioutil"
+	"strconv"
 	"strings"
 
 	"golang.org/x/xerrors"
+	"gopkg.in/yaml.v2"
 
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
@@ -19,8 +23,19 @@ type parameter struct {
 }
 
 // ParseConfig returns a validated map of flags from a gpupgrade config file.
+// The config is either in the structured YAML format described by
+// ConfigParams, or in the flat name = value format of gpupgrade_config.
 func ParseConfig(config io.Reader) (map[string]string, error) {
-	params, err := parseParams(config)
+	contents, err := ioutil.ReadAll(config)
+	if err != nil {
+		return nil, xerrors.Errorf("reading config: %w", err)
+	}
+
+	if isStructured(contents) {
+		return parseStructured(contents)
+	}
+
+	params, err := parseParams(bytes.NewReader(contents))
 	if err != nil {
 		return nil, err
 	}
@@ -98,3 +113,200 @@ func checkConfig(flags map[string]string) error {
 	}
 	return err
 }
+
+// isStructured returns whether the config is in the structured YAML format
+// judging by its first line that is not blank or a comment. Flat parameters
+// have an equal sign before any colon, since values such as
+// dynamic_library_path can contain colons.
+func isStructured(config []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(config))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if line == "---" {
+			return true
+		}
+
+		colon := strings.Index(line, ":")
+		equal := strings.Index(line, "=")
+		return colon >= 0 && (equal < 0 || colon < equal)
+	}
+
+	return false
+}
+
+// parseStructured type checks the parameters of a structured config returning
+// the initialize flags they set. Unknown parameters are reported along with
+// the parameter they most likely meant.
+func parseStructured(config []byte) (map[string]string, error) {
+	var doc yaml.MapSlice
+	if err := yaml.Unmarshal(config, &doc); err != nil {
+		return nil, xerrors.Errorf("parsing config: %w", err)
+	}
+
+	params := make(map[string]ConfigParam)
+	sections := make(map[string]bool)
+	for _, param := range ConfigParams {
+		params[param.Path] = param
+
+		parts := strings.Split(param.Path, ".")
+		for i := 1; i < len(parts); i++ {
+			sections[strings.Join(parts[:i], ".")] = true
+		}
+	}
+
+	flags := make(map[string]string)
+	seen := make(map[string]bool)
+
+	var errs error
+	var walk func(prefix string, items yaml.MapSlice)
+	walk = func(prefix string, items yaml.MapSlice) {
+		for _, item := range items {
+			key, ok := item.Key.(string)
+			if !ok {
+				errs = errorlist.Append(errs, xerrors.Errorf("parameter name %v in %q is not a string", item.Key, prefix))
+				continue
+			}
+
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+
+			if seen[path] {
+				errs = errorlist.Append(errs, xerrors.Errorf("parameter %q declared more than once", path))
+				continue
+			}
+			seen[path] = true
+
+			if param, ok := params[path]; ok {
+				value, err := typeCheck(param, item.Value)
+				if err != nil {
+					errs = errorlist.Append(errs, err)
+					continue
+				}
+
+				flags[param.Flag()] = value
+				continue
+			}
+
+			if sections[path] {
+				// A section without any parameters is empty rather than null.
+				if item.Value == nil {
+					continue
+				}
+
+				nested, ok := item.Value.(yaml.MapSlice)
+				if !ok {
+					errs = errorlist.Append(errs, xerrors.Errorf("%q is a section of parameters not %s", path, describeValue(item.Value)))
+					continue
+				}
+
+				walk(path, nested)
+				continue
+			}
+
+			errs = errorlist.Append(errs, unknownParam(path, key))
+		}
+	}
+
+	walk("", doc)
+	if errs != nil {
+		return nil, errs
+	}
+
+	return flags, nil
+}
+
+// typeCheck returns the value of the parameter as the initialize flag value.
+// Unquoted numbers are accepted for string parameters such as
+// temp_port_range or backup timestamps.
+func typeCheck(param ConfigParam, value interface{}) (string, error) {
+	if value == nil || value == "" {
+		return "", xerrors.Errorf("no value found for parameter %q", param.Path)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if param.Type == stringType {
+			return v, nil
+		}
+	case int:
+		if param.Type == stringType || param.Type == integerType || param.Type == numberType {
+			return strconv.Itoa(v), nil
+		}
+	case float64:
+		if param.Type == numberType {
+			return strconv.FormatFloat(v, 'g', -1, 64), nil
+		}
+	case bool:
+		if param.Type == booleanType {
+			return strconv.FormatBool(v), nil
+		}
+	}
+
+	return "", xerrors.Errorf("parameter %q must be %s not %s", param.Path, describeType(param.Type), describeValue(value))
+}
+
+func describeType(kind ParamType) string {
+	switch kind {
+	case integerType:
+		return "an integer"
+	case numberType:
+		return "a number"
+	case booleanType:
+		return "a boolean"
+	default:
+		return "a string"
+	}
+}
+
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "a string"
+	case int:
+		return "an integer"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case yaml.MapSlice:
+		return "a section"
+	case []interface{}:
+		return "a list"
+	default:
+		return "a value"
+	}
+}
+
+// unknownParam suggests the parameter that was most likely meant. That is the
+// structured parameter of a flat parameter name, the parameter of the same
+// name in another section, or the parameter or section spelled most alike.
+func unknownParam(path string, key string) error {
+	var suggestion string
+	var candidates []string
+	for _, param := range ConfigParams {
+		parts := strings.Split(param.Path, ".")
+		for i := 1; i <= len(parts); i++ {
+			candidates = append(candidates, strings.Join(parts[:i], "."))
+		}
+
+		if suggestion == "" && (key == param.Flat || key == parts[len(parts)-1]) {
+			suggestion = param.Path
+		}
+	}
+
+	if suggestion == "" {
+		suggestion = closest(path, candidates)
+	}
+
+	if suggestion == "" || suggestion == path {
+		return xerrors.Errorf("unknown parameter %q", path)
+	}
+
+	return xerrors.Errorf("unknown parameter %q. Did you mean %q?", path, suggestion)
+}
@@ -4,11 +4,16 @@
 package commands_test
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/greenplum-db/gpupgrade/cli/commands"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
 func TestConfig(t *testing.T) {
@@ -148,3 +153,171 @@ func TestConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestStructuredConfig(t *testing.T) {
+	t.Run("parses the flags of nested parameters", func(t *testing.T) {
+		config := `
+# comments are allowed
+source_gphome: /usr/local/gpdb5
+target_gphome: /usr/local/gpdb6
+mode: link
+use_hba_hostnames: true
+dynamic_library_path: $libdir:/usr/local/pxf-gp6/gpextable
+ports:
+  source_master: 5432
+  temp_range: 6000,6002-6005
+  hub: 7527
+transfer:
+  mirror_resync: incremental
+hooks:
+  snapshot:
+    provider: zfs
+  backup:
+    provider: gpbackup
+    timestamp: 20220101120000
+checks:
+  disk_free_ratio: 0.6
+  data_validation: row-counts
+`
+
+		flags, err := commands.ParseConfig(strings.NewReader(config))
+		if err != nil {
+			t.Fatalf("ParseConfig returned error: %+v", err)
+		}
+
+		expected := map[string]string{
+			"source-gphome":        "/usr/local/gpdb5",
+			"target-gphome":        "/usr/local/gpdb6",
+			"mode":                 "link",
+			"use-hba-hostnames":    "true",
+			"dynamic-library-path": "$libdir:/usr/local/pxf-gp6/gpextable",
+			"source-master-port":   "5432",
+			"temp-port-range":      "6000,6002-6005",
+			"hub-port":             "7527",
+			"mirror-resync":        "incremental",
+			"snapshot-provider":    "zfs",
+			"backup-provider":      "gpbackup",
+			"backup-timestamp":     "20220101120000",
+			"disk-free-ratio":      "0.6",
+			"data-validation":      "row-counts",
+		}
+		if !reflect.DeepEqual(flags, expected) {
+			t.Errorf("got %v want %v", flags, expected)
+		}
+	})
+
+	t.Run("detects the flat format by the first parameter", func(t *testing.T) {
+		flags, err := commands.ParseConfig(strings.NewReader("# dynamic_library_path: is flat\ndynamic_library_path = $libdir:/pxf\nmode = link"))
+		if err != nil {
+			t.Fatalf("ParseConfig returned error: %+v", err)
+		}
+
+		if flags["dynamic-library-path"] != "$libdir:/pxf" || flags["mode"] != "link" {
+			t.Errorf("got %v", flags)
+		}
+	})
+
+	t.Run("every parameter sets an initialize flag", func(t *testing.T) {
+		initialize, _, err := commands.BuildRootCommand().Find([]string{"initialize"})
+		if err != nil {
+			t.Fatalf("finding initialize: %+v", err)
+		}
+
+		for _, param := range commands.ConfigParams {
+			if initialize.Flag(param.Flag()) == nil {
+				t.Errorf("parameter %q sets unknown flag %q", param.Path, param.Flag())
+			}
+		}
+	})
+
+	errorCases := []struct {
+		description string
+		config      string
+		expected    string
+	}{
+		{
+			description: "a parameter has the wrong type",
+			config:      "ports:\n  hub: seventy",
+			expected:    `parameter "ports.hub" must be an integer not a string`,
+		},
+		{
+			description: "a boolean parameter is a string",
+			config:      "use_hba_hostnames: sometimes",
+			expected:    `parameter "use_hba_hostnames" must be a boolean not a string`,
+		},
+		{
+			description: "a section is a value",
+			config:      "ports: 7527",
+			expected:    `"ports" is a section of parameters not an integer`,
+		},
+		{
+			description: "a parameter is empty",
+			config:      "mode:",
+			expected:    `no value found for parameter "mode"`,
+		},
+		{
+			description: "a parameter is misspelled",
+			config:      "ports:\n  hbu: 7527",
+			expected:    `unknown parameter "ports.hbu". Did you mean "ports.hub"?`,
+		},
+		{
+			description: "a section is misspelled",
+			config:      "port:\n  hub: 7527",
+			expected:    `unknown parameter "port". Did you mean "ports"?`,
+		},
+		{
+			description: "a flat parameter name is used",
+			config:      "hub_port: 7527",
+			expected:    `unknown parameter "hub_port". Did you mean "ports.hub"?`,
+		},
+		{
+			description: "a parameter is in the wrong section",
+			config:      "checks:\n  mirror_resync: full",
+			expected:    `unknown parameter "checks.mirror_resync". Did you mean "transfer.mirror_resync"?`,
+		},
+		{
+			description: "a parameter is unlike any other",
+			config:      "tls:\n  enabled: true",
+			expected:    `unknown parameter "tls"`,
+		},
+		{
+			description: "a parameter is specified multiple times",
+			config:      "mode: link\nmode: copy",
+			expected:    `parameter "mode" declared more than once`,
+		},
+	}
+
+	for _, c := range errorCases {
+		t.Run(fmt.Sprintf("errors when %s", c.description), func(t *testing.T) {
+			_, err := commands.ParseConfig(strings.NewReader(c.config))
+			if err == nil || err.Error() != c.expected {
+				t.Errorf("got error %v want %q", err, c.expected)
+			}
+		})
+	}
+
+	t.Run("reports every error", func(t *testing.T) {
+		_, err := commands.ParseConfig(strings.NewReader("mode: 1.5\nports:\n  hub: seventy"))
+
+		var errs errorlist.Errors
+		if !errors.As(err, &errs) || len(errs) != 2 {
+			t.Errorf("got error %v want two errors", err)
+		}
+	})
+}
+
+func TestConfigSchema(t *testing.T) {
+	schema, err := commands.ConfigSchema()
+	if err != nil {
+		t.Fatalf("ConfigSchema returned error: %+v", err)
+	}
+
+	published, err := ioutil.ReadFile(filepath.Join("..", "schema", "gpupgrade_config.schema.json"))
+	if err != nil {
+		t.Fatalf("reading published schema: %+v", err)
+	}
+
+	if string(published) != string(schema)+"\n" {
+		t.Errorf("the published schema is out of date. Run `go generate ./cli/schema` to regenerate it.")
+	}
+}
@@ -55,7 +55,7 @@ func revert() *cobra.Command {
 					return err
 				}
 
-				response, err = commanders.Revert(client, verbose)
+				response, err = commanders.Revert(cmd.Context(), client, verbose)
 				if err != nil {
 					return err
 				}
@@ -64,7 +64,7 @@ func revert() *cobra.Command {
 			})
 
 			st.RunCLISubstep(idl.Substep_STOP_HUB_AND_AGENTS, func(streams step.OutStreams) error {
-				return stopHubAndAgents(false)
+				return stopHubAndAgents(cmd.Context(), false)
 			})
 
 			st.RunCLISubstep(idl.Substep_DELETE_MASTER_STATEDIR, func(streams step.OutStreams) error {
@@ -81,7 +81,7 @@ Revert completed successfully.
 The source cluster is now running version %s.
 PGPORT=%d
 MASTER_DATA_DIRECTORY=%s
-
+%s
 The gpupgrade logs can be found on the master and segment hosts in
 %s
 
@@ -92,7 +92,7 @@ recreate any additional tables, indexes, and roles that were dropped or
 altered to resolve migration issues.
 
 To restart the upgrade, run "gpupgrade initialize" again.`,
-				response.GetSourceVersion(), response.GetSource().GetPort(), response.GetSource().GetCoordinatorDataDirectory(), response.GetLogArchiveDirectory()))
+				response.GetSourceVersion(), response.GetSource().GetPort(), response.GetSource().GetCoordinatorDataDirectory(), backupText(response.GetBackup()), response.GetLogArchiveDirectory()))
 		},
 	}
 
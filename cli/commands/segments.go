@@ -0,0 +1,82 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func segmentsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "segments",
+		Short: "lists the upgrade status of each primary segment",
+		Long: `Lists the upgrade status, host, and data directory of each primary segment
+of the target cluster, along with the pg_upgrade directory on the segment host
+and the last error.
+
+To retry upgrading only specific failed primaries, run
+"gpupgrade execute --only-content" with their comma separated content ids.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			client, err := connectToHub()
+			if err != nil {
+				return err
+			}
+
+			reply, err := client.Segments(cmd.Context(), &idl.SegmentsRequest{})
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(SegmentsReport(reply.GetSegments()))
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// SegmentsReport lists the status of each segment followed by the errors of
+// any failed segments.
+func SegmentsReport(segments []*idl.SegmentUpgradeStatus) string {
+	var b bytes.Buffer
+
+	t := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(t, "Content\tDbid\tHost\tStatus\tData Directory\tpg_upgrade Directory")
+	for _, seg := range segments {
+		status := seg.GetStatus().String()
+		if seg.GetStatus() == idl.Status_UNKNOWN_STATUS {
+			status = "NOT STARTED"
+		}
+
+		fmt.Fprintf(t, "%d\t%d\t%s\t%s\t%s\t%s\n", seg.GetContentID(), seg.GetDbID(), seg.GetHostname(), status, seg.GetDataDir(), seg.GetPgUpgradeDir())
+	}
+	t.Flush()
+
+	var failed []string
+	for _, seg := range segments {
+		if seg.GetError() == "" {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\nContent %d on host %s last failed with:\n  %s\n", seg.GetContentID(), seg.GetHostname(), seg.GetError())
+		if seg.GetStatus() == idl.Status_FAILED {
+			failed = append(failed, fmt.Sprint(seg.GetContentID()))
+		}
+	}
+
+	if len(failed) > 0 {
+		fmt.Fprintf(&b, "\nTo retry only the failed primaries, run \"gpupgrade execute --only-content %s\".\n", strings.Join(failed, ","))
+	}
+
+	return b.String()
+}
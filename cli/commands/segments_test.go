@@ -0,0 +1,35 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/cli/commands"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func TestSegmentsReport(t *testing.T) {
+	segments := []*idl.SegmentUpgradeStatus{
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/seg0", Status: idl.Status_COMPLETE, PgUpgradeDir: "/log/pg_upgrade/p0"},
+		{ContentID: 12, DbID: 14, Hostname: "sdw2", DataDir: "/data/seg12", Status: idl.Status_FAILED, PgUpgradeDir: "/log/pg_upgrade/p12", Error: "disk full"},
+		{ContentID: 37, DbID: 39, Hostname: "sdw3", DataDir: "/data/seg37", Status: idl.Status_FAILED, PgUpgradeDir: "/log/pg_upgrade/p37", Error: "corrupt file"},
+		{ContentID: 40, DbID: 42, Hostname: "sdw3", DataDir: "/data/seg40", PgUpgradeDir: "/log/pg_upgrade/p40"},
+	}
+
+	report := commands.SegmentsReport(segments)
+
+	for _, expected := range []string{
+		"Content  Dbid  Host  Status       Data Directory  pg_upgrade Directory",
+		"12       14    sdw2  FAILED       /data/seg12     /log/pg_upgrade/p12",
+		"40       42    sdw3  NOT STARTED  /data/seg40     /log/pg_upgrade/p40",
+		"Content 12 on host sdw2 last failed with:\n  disk full",
+		`run "gpupgrade execute --only-content 12,37"`,
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("got report %q want it to contain %q", report, expected)
+		}
+	}
+}
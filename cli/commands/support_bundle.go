@@ -0,0 +1,70 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func supportBundleCmd() *cobra.Command {
+	var dir string
+	var redactPasswords bool
+	var redactHostnames bool
+
+	cmd := &cobra.Command{
+		Use:   "support-bundle",
+		Short: "collects the state and logs of all hosts into a single tarball",
+		Long: `Collects into a single tarball with a manifest, from the coordinator and
+every segment host:
+  - the gpupgrade state files such as config.json and substeps.json
+  - the gpupgrade logs including the pg_upgrade working directories
+  - the gpinitsystem config and the gpAdminLogs
+  - the newest postgres logs of each data directory
+  - operating system facts such as disk usage and versions
+
+Attach the tarball to a support ticket.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			absDir, err := filepath.Abs(dir)
+			if err != nil {
+				return err
+			}
+
+			client, err := connectToHub()
+			if err != nil {
+				return err
+			}
+
+			reply, err := client.SupportBundle(cmd.Context(), &idl.SupportBundleRequest{
+				Dir:             absDir,
+				RedactPasswords: redactPasswords,
+				RedactHostnames: redactHostnames,
+				Version:         Version,
+			})
+			if err != nil {
+				return err
+			}
+
+			for _, hostErr := range reply.GetErrors() {
+				fmt.Fprintf(os.Stderr, "Warning: failed to collect the support bundle of %s\n", hostErr)
+			}
+
+			fmt.Printf("The support bundle is in %s.\n", reply.GetPath())
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", ".", "the directory on the coordinator host to write the support bundle to")
+	cmd.Flags().BoolVar(&redactPasswords, "redact-passwords", false, "replace passwords in the collected files")
+	cmd.Flags().BoolVar(&redactHostnames, "redact-hostnames", false, "replace hostnames in the collected files with host1, host2, and so on")
+
+	return cmd
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func validateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "compares the upgraded data with the source snapshot taken during initialize",
+		Long: `Compares the per segment row counts, and optionally sampled row hashes, of
+every user table in the running target cluster with the snapshot of the source
+cluster taken during initialize. Requires data_validation to be set in the
+gpupgrade config file.
+
+Run validate after execute and before finalize. Modifying the target cluster
+before validating causes mismatches.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			client, err := connectToHub()
+			if err != nil {
+				return err
+			}
+
+			reply, err := client.Validate(cmd.Context(), &idl.ValidateRequest{})
+			if err != nil {
+				return err
+			}
+
+			fmt.Print(reply.GetReport())
+			fmt.Printf("\nThe report is in %s.\n", reply.GetReportPath())
+			return ValidationResult(reply)
+		},
+	}
+
+	return cmd
+}
+
+// ValidationResult returns an error with next actions when the reply has
+// mismatches.
+func ValidationResult(reply *idl.ValidateReply) error {
+	if reply.GetMismatches() == 0 {
+		return nil
+	}
+
+	return utils.NewNextActionErr(
+		fmt.Errorf("%d mismatches found between the source and target data.", reply.GetMismatches()),
+		fmt.Sprintf(`Investigate the mismatches in %s before running "gpupgrade finalize".
+To return the cluster to its original state, run "gpupgrade revert".`, reply.GetReportPath()))
+}
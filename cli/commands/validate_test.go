@@ -0,0 +1,41 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/cli/commands"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestValidationResult(t *testing.T) {
+	t.Run("succeeds when there are no mismatches", func(t *testing.T) {
+		err := commands.ValidationResult(&idl.ValidateReply{ReportPath: "/log/validation/report.txt"})
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("returns next actions when there are mismatches", func(t *testing.T) {
+		err := commands.ValidationResult(&idl.ValidateReply{Mismatches: 2, ReportPath: "/log/validation/report.txt"})
+
+		var nextActionsErr utils.NextActionErr
+		if !errors.As(err, &nextActionsErr) {
+			t.Fatalf("got type %T want %T", err, nextActionsErr)
+		}
+
+		expected := "2 mismatches found between the source and target data."
+		if err.Error() != expected {
+			t.Errorf("got error %q want %q", err.Error(), expected)
+		}
+
+		if !strings.Contains(nextActionsErr.NextAction, "/log/validation/report.txt") {
+			t.Errorf("expected next action %q to contain the report path", nextActionsErr.NextAction)
+		}
+	})
+}
@@ -4,7 +4,11 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
 )
 
 // These variables are set during build time as specified in the Makefile.
@@ -38,3 +42,22 @@ Release: %s`
 func printVersion(format string) {
 	fmt.Println(VersionString(format))
 }
+
+func printVersionMatrix(format string) error {
+	matrix, err := greenplum.LoadVersionMatrix()
+	if err != nil {
+		return err
+	}
+
+	if format != "json" {
+		fmt.Print(matrix.String())
+		return nil
+	}
+
+	// Disable HTML escaping so that version ranges such as ">=7.0.0" are
+	// printed as is.
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(matrix)
+}
@@ -0,0 +1,29 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// This binary exists purely for the purpose of generating the JSON schema of
+// the structured config file. Run `go generate ./cli/schema` to regenerate the
+// gpupgrade_config.schema.json file.
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+
+	"github.com/greenplum-db/gpupgrade/cli/commands"
+)
+
+//go:generate go run generate.go gpupgrade_config.schema.json
+
+func main() {
+	schema, err := commands.ConfigSchema()
+	if err != nil {
+		log.Fatalf("generating config schema: %+v", err)
+	}
+
+	err = ioutil.WriteFile(os.Args[1], append(schema, '\n'), 0644)
+	if err != nil {
+		log.Fatalf("writing config schema: %+v", err)
+	}
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -13,8 +14,11 @@ import (
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
 
 	"github.com/greenplum-db/gpupgrade/cli/commands"
+	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 func main() {
@@ -24,14 +28,46 @@ func main() {
 		fmt.Printf("\n%+v\n", err)
 		os.Exit(1)
 	}
-	gplog.InitializeLogging("gpupgrade_cli", logdir)
 
 	root := commands.BuildRootCommand()
 	// Silence usage since Cobra prints usage for all errors rather than just
 	// "unknown flag" errors.
 	root.SilenceUsage = true
 
-	err = root.Execute()
+	// The hub and agent commands are hidden and log and trace themselves.
+	cmd, _, err := root.Find(os.Args[1:])
+	found := err == nil && !cmd.Hidden
+
+	// The JSON log entries of the step commands record their step.
+	format, upgradeID := commands.LogConfig()
+	fields := log.Fields{UpgradeID: upgradeID}
+	if found {
+		if _, ok := idl.Step_value[strings.ToUpper(cmd.Name())]; ok {
+			fields.Step = strings.ToUpper(cmd.Name())
+		}
+	}
+
+	if err := log.Initialize("gpupgrade_cli", logdir, format, fields); err != nil {
+		fmt.Printf("\n%+v\n", err)
+		os.Exit(1)
+	}
+
+	// Record a span for the command that parents the spans of its calls to
+	// the hub.
+	ctx := context.Background()
+	var span *trace.Span
+	if found {
+		ctx, span = trace.StartSpan(ctx, cmd.CommandPath())
+	}
+
+	err = root.ExecuteContext(ctx)
+	if span != nil {
+		span.End(err)
+		if tErr := trace.Shutdown(); tErr != nil {
+			gplog.Debug("failed to export remaining spans: %#v", tErr)
+		}
+	}
+
 	if err != nil && err != daemon.ErrSuccessfullyDaemonized {
 		if strings.HasPrefix(err.Error(), "unknown flag") {
 			cmd := os.Args[1]
@@ -36,6 +36,7 @@ mv data-migration-scripts/gpupgrade-migration-sql-executor.bash %{buildroot}%{pr
 mkdir -p %{buildroot}%{prefix}/greenplum/%{name}
 mv data-migration-scripts %{buildroot}%{prefix}/greenplum/%{name}
 mv gpupgrade_config %{buildroot}%{prefix}/greenplum/%{name}
+mv gpupgrade_config.schema.json %{buildroot}%{prefix}/greenplum/%{name}
 mv gpupgrade.bash %{buildroot}%{prefix}/greenplum/%{name}
 mv open_source_licenses.txt %{buildroot}%{prefix}/greenplum/%{name}
 
@@ -51,5 +52,6 @@ mv open_source_licenses.txt %{buildroot}%{prefix}/greenplum/%{name}
 %dir %{prefix}/greenplum/%{name}
 %{prefix}/greenplum/%{name}/data-migration-scripts
 %config %{prefix}/greenplum/%{name}/gpupgrade_config
+%{prefix}/greenplum/%{name}/gpupgrade_config.schema.json
 %{prefix}/greenplum/%{name}/gpupgrade.bash
 %{prefix}/greenplum/%{name}/open_source_licenses.txt
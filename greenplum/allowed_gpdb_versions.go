@@ -4,122 +4,254 @@
 package greenplum
 
 import (
+	_ "embed"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/blang/semver/v4"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-// Note that we represent the source and target versions separately.  Another
-// option is a matrix explicitly listing supported source/target combinations.
-// However, pg_upgrade supports upgrade from any version to any version.
-// We are not sure yet if we are doing that for gpupgrade.
+// The supported source and target version pairs are declared in
+// version_matrix.json, which is compiled into gpupgrade. Support can direct
+// users to a different matrix by pointing GPUPGRADE_VERSION_MATRIX at a file
+// of the same format.
 
-var (
-	// sourceVersionAllowed returns whether or not the given semver.Version is a
-	// valid source GPDB cluster version.
-	sourceVersionAllowed semver.Range
+//go:embed version_matrix.json
+var builtinVersionMatrix []byte
 
-	// targetVersionAllowed returns whether or not the given semver.Version is a
-	// valid target GPDB cluster version.
-	targetVersionAllowed semver.Range
-)
+const VersionMatrixEnv = "GPUPGRADE_VERSION_MATRIX"
 
-// Source and Target Versions: modify these lists to control what will be allowed
-// by the utility.  Map entries are of the form: GPDB_VERSION : MIN_ALLOWED_SEMVER
+// MigrationPhases are the data migration script phases an upgrade path may
+// require.
+var MigrationPhases = []string{"pre-initialize", "post-finalize", "post-revert"}
 
-var minSourceVersions = map[int]string{
-	7: "7.0.0",
+type VersionMatrix struct {
+	// Origin is where the matrix was loaded from and is not serialized.
+	Origin   string        `json:"-"`
+	Upgrades []UpgradePath `json:"upgrades"`
 }
 
-var minTargetVersions = map[int]string{
-	7: "7.0.0",
+// UpgradePath allows upgrading any source version in the Source range to any
+// target version in the Target range.
+type UpgradePath struct {
+	Source          string   `json:"source"`
+	Target          string   `json:"target"`
+	Notes           string   `json:"notes,omitempty"`
+	MigrationPhases []string `json:"migrationPhases,omitempty"`
+
+	sourceAllowed semver.Range
+	targetAllowed semver.Range
 }
 
-// The below boilerplate turns the source/targetRanges variables into
-// source/targetVersionAllowed. You shouldn't need to touch it.
+// LoadVersionMatrix returns the version matrix from the file named by
+// GPUPGRADE_VERSION_MATRIX if set, otherwise the built-in matrix.
+func LoadVersionMatrix() (*VersionMatrix, error) {
+	path := os.Getenv(VersionMatrixEnv)
+	if path == "" {
+		return ParseVersionMatrix(builtinVersionMatrix, "built-in")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, xerrors.Errorf("reading %s: %w", VersionMatrixEnv, err)
+	}
 
-func init() {
-	accumulateRanges(&sourceVersionAllowed, minSourceVersions)
-	accumulateRanges(&targetVersionAllowed, minTargetVersions)
+	gplog.Info("using version matrix %s from %s", path, VersionMatrixEnv)
+	return ParseVersionMatrix(data, path)
 }
 
-func accumulateRanges(a *semver.Range, minVersions map[int]string) {
-	for v, min := range minVersions {
-		// for example, 5: "5.28.0" becomes the Range string ">=5.28.0 <6.0.0"
-		str := fmt.Sprintf(">=%s <%d.0.0", min, v+1)
-		r := semver.MustParseRange(str)
+func ParseVersionMatrix(data []byte, origin string) (*VersionMatrix, error) {
+	matrix := &VersionMatrix{Origin: origin}
+	if err := json.Unmarshal(data, matrix); err != nil {
+		return nil, xerrors.Errorf("parsing version matrix %s: %w", origin, err)
+	}
+
+	if len(matrix.Upgrades) == 0 {
+		return nil, xerrors.Errorf("version matrix %s has no upgrades", origin)
+	}
+
+	var errs error
+	for i := range matrix.Upgrades {
+		path := &matrix.Upgrades[i]
+
+		var err error
+		path.sourceAllowed, err = semver.ParseRange(path.Source)
+		if err != nil {
+			errs = errorlist.Append(errs, xerrors.Errorf("version matrix %s: invalid source range %q: %w", origin, path.Source, err))
+		}
+
+		path.targetAllowed, err = semver.ParseRange(path.Target)
+		if err != nil {
+			errs = errorlist.Append(errs, xerrors.Errorf("version matrix %s: invalid target range %q: %w", origin, path.Target, err))
+		}
 
-		if *a == nil {
-			*a = r
-		} else {
-			*a = a.OR(r)
+		for _, phase := range path.MigrationPhases {
+			if !isMigrationPhase(phase) {
+				errs = errorlist.Append(errs, xerrors.Errorf("version matrix %s: invalid migration phase %q. Expected one of %s.",
+					origin, phase, strings.Join(MigrationPhases, ", ")))
+			}
 		}
 	}
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	return matrix, nil
 }
 
-// returns the version string that is the lowest of the major version of "version"
-// or the lowest version supported in minVersions otherwise
-func getMinVersion(version semver.Version, minVersions map[int]string) string {
+func isMigrationPhase(phase string) bool {
+	for _, p := range MigrationPhases {
+		if phase == p {
+			return true
+		}
+	}
+
+	return false
+}
 
-	major := version.Major
-	min, ok := minVersions[int(major)]
-	if ok {
-		return min
+// Find returns the first upgrade path that allows upgrading from source to
+// target.
+func (m *VersionMatrix) Find(source, target semver.Version) (UpgradePath, bool) {
+	for _, path := range m.Upgrades {
+		if path.sourceAllowed(source) && path.targetAllowed(target) {
+			return path, true
+		}
 	}
 
-	var lowest int
-	for major := range minVersions {
-		if lowest == 0 {
-			lowest = major
+	return UpgradePath{}, false
+}
+
+func (m *VersionMatrix) allowed(version semver.Version, destination idl.ClusterDestination) bool {
+	for _, path := range m.Upgrades {
+		allowed := path.sourceAllowed
+		if destination == idl.ClusterDestination_TARGET {
+			allowed = path.targetAllowed
 		}
-		if major < lowest {
-			lowest = major
+
+		if allowed(version) {
+			return true
 		}
 	}
-	return semver.MustParse(minVersions[lowest]).String()
+
+	return false
 }
 
-func VerifyCompatibleGPDBVersions(sourceGPHome, targetGPHome string) error {
-	var errs error
+func (m *VersionMatrix) ranges(destination idl.ClusterDestination) []string {
+	var ranges []string
+	seen := make(map[string]bool)
+	for _, path := range m.Upgrades {
+		r := path.Source
+		if destination == idl.ClusterDestination_TARGET {
+			r = path.Target
+		}
 
-	sourceVersion, err := Version(sourceGPHome)
+		if !seen[r] {
+			seen[r] = true
+			ranges = append(ranges, fmt.Sprintf("%q", r))
+		}
+	}
+
+	return ranges
+}
+
+// String returns a table of the upgrade paths in the matrix.
+func (m *VersionMatrix) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Version matrix: %s\n\n", m.Origin)
+
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(&t, "Source\tTarget\tMigration Phases\tNotes")
+	for _, path := range m.Upgrades {
+		phases := strings.Join(path.MigrationPhases, ", ")
+		if phases == "" {
+			phases = "none"
+		}
+
+		fmt.Fprintf(&t, "%s\t%s\t%s\t%s\n", path.Source, path.Target, phases, path.Notes)
+	}
+
+	t.Flush()
+	return b.String()
+}
+
+// MigrationNote returns a reminder of the data migration script phases the
+// upgrade path requires, or an empty string if none are required.
+func (p UpgradePath) MigrationNote() string {
+	if len(p.MigrationPhases) == 0 {
+		return ""
+	}
+
+	note := fmt.Sprintf("NOTE: Upgrading from %s to %s requires the %s data migration scripts.",
+		p.Source, p.Target, strings.Join(p.MigrationPhases, ", "))
+	if p.Notes != "" {
+		note += "\n" + p.Notes
+	}
+
+	return note
+}
+
+// VerifyCompatibleGPDBVersions returns the upgrade path in the version matrix
+// that allows upgrading from the source to the target installation.
+func VerifyCompatibleGPDBVersions(sourceGPHome, targetGPHome string) (UpgradePath, error) {
+	matrix, err := LoadVersionMatrix()
 	if err != nil {
-		return err
+		return UpgradePath{}, err
 	}
 
-	err = validateVersion(sourceVersion, idl.ClusterDestination_SOURCE)
-	errs = errorlist.Append(errs, err)
+	sourceVersion, err := Version(sourceGPHome)
+	if err != nil {
+		return UpgradePath{}, err
+	}
 
 	targetVersion, err := Version(targetGPHome)
 	if err != nil {
-		return err
+		return UpgradePath{}, err
 	}
 
-	err = validateVersion(targetVersion, idl.ClusterDestination_TARGET)
-	errs = errorlist.Append(errs, err)
-
-	return errs
+	return validateVersions(matrix, sourceVersion, targetVersion)
 }
 
-func validateVersion(versionStr string, destination idl.ClusterDestination) error {
-	versionsAllowed := sourceVersionAllowed
-	minVersions := minSourceVersions
-	if destination == idl.ClusterDestination_TARGET {
-		versionsAllowed = targetVersionAllowed
-		minVersions = minTargetVersions
+func validateVersions(matrix *VersionMatrix, sourceVersionStr, targetVersionStr string) (UpgradePath, error) {
+	var errs error
+
+	sourceVersion := semver.MustParse(sourceVersionStr)
+	errs = errorlist.Append(errs, validateVersion(matrix, sourceVersion, idl.ClusterDestination_SOURCE))
+
+	targetVersion := semver.MustParse(targetVersionStr)
+	errs = errorlist.Append(errs, validateVersion(matrix, targetVersion, idl.ClusterDestination_TARGET))
+
+	if errs != nil {
+		return UpgradePath{}, errs
 	}
 
-	version := semver.MustParse(versionStr)
-	if !versionsAllowed(version) {
-		min := getMinVersion(version, minVersions)
+	path, ok := matrix.Find(sourceVersion, targetVersion)
+	if !ok {
+		return UpgradePath{}, fmt.Errorf("upgrading from source cluster version %s to target cluster version %s is not supported. "+
+			`Run "gpupgrade version --matrix" to list the supported upgrades.`,
+			sourceVersion, targetVersion)
+	}
+
+	return path, nil
+}
+
+func validateVersion(matrix *VersionMatrix, version semver.Version, destination idl.ClusterDestination) error {
+	if !matrix.allowed(version, destination) {
 		return fmt.Errorf("%s cluster version %s is not supported.  "+
-			"The minimum required version is %s. "+
+			"The supported versions are %s. "+
 			"We recommend the latest version.",
-			strings.ToLower(destination.String()), version, min)
+			strings.ToLower(destination.String()), version, strings.Join(matrix.ranges(destination), ", "))
 	}
 
 	return nil
@@ -9,125 +9,85 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/blang/semver/v4"
-
-	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func TestAllowedVersions(t *testing.T) {
-	cases := []struct {
-		name          string
-		versions      []string
-		validator     semver.Range
-		validatorName string
-		expected      bool
-	}{
-		{
-			"allowed source versions",
-			[]string{
-				"5.29.1",
-				"5.29.13",
-				"5.50.0",
-				"6.18.0",
-				"6.18.1",
-				"6.50.1",
-			},
-			sourceVersionAllowed,
-			"sourceVersionAllowed",
-			true,
-		}, {
-			"disallowed source versions",
-			[]string{
-				"4.3.0",
-				"5.0.0",
-				"5.28.11",
-				"5.29.0",
-				"6.0.0",
-				"6.17.9",
-				"7.0.0",
-			},
-			sourceVersionAllowed,
-			"sourceVersionAllowed",
-			false,
-		}, {
-			"allowed target versions",
-			[]string{
-				"6.18.0",
-				"6.18.1",
-				"6.50.1",
-			},
-			targetVersionAllowed,
-			"targetVersionAllowed",
-			true,
-		}, {
-			"disallowed target versions",
-			[]string{
-				"4.3.0",
-				"5.0.0",
-				"5.27.0",
-				"5.28.0",
-				"5.50.0",
-				"6.0.0",
-				"6.17.0",
-				"7.0.0",
-			},
-			targetVersionAllowed,
-			"targetVersionAllowed",
-			false,
-		},
+func TestValidateVersions(t *testing.T) {
+	matrix, err := ParseVersionMatrix([]byte(TestVersionMatrix), "test")
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
 	}
 
-	for _, c := range cases {
-		t.Run(c.name, func(t *testing.T) {
-			for _, v := range c.versions {
-				ver := semver.MustParse(v)
-				actual := c.validator(ver)
+	t.Run("returns the upgrade path for supported versions", func(t *testing.T) {
+		path, err := validateVersions(matrix, "6.18.1", "6.50.1")
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
 
-				if actual != c.expected {
-					t.Errorf("%s(%q) = %t, want %t", c.validatorName, v, actual, c.expected)
-				}
-			}
-		})
-	}
-}
+		if path.Source != ">=6.18.0 <7.0.0" {
+			t.Errorf("got source range %q want %q", path.Source, ">=6.18.0 <7.0.0")
+		}
+	})
 
-func TestValidateVersionsErrorCases(t *testing.T) {
 	cases := []struct {
-		name             string
-		localVersion     string
-		testLocalVersion func(string) (semver.Version, error)
-		expected         error
+		name     string
+		source   string
+		target   string
+		expected string
 	}{
 		{
-			name:         "fails when GPDB version has unsupported minor versions",
-			localVersion: semver.MustParse("6.8.0").String(),
-			expected:     errors.New("source cluster version 6.8.0 is not supported.  The minimum required version is 6.18.0. We recommend the latest version."),
+			name:     "fails when GPDB version has unsupported minor versions",
+			source:   "6.8.0",
+			target:   "6.18.0",
+			expected: `source cluster version 6.8.0 is not supported.  The supported versions are ">=5.29.1 <6.0.0", ">=6.18.0 <7.0.0". We recommend the latest version.`,
+		},
+		{
+			name:     "fails when GPDB version has unsupported major versions",
+			source:   "6.18.0",
+			target:   "0.0.0",
+			expected: `target cluster version 0.0.0 is not supported.  The supported versions are ">=6.18.0 <7.0.0". We recommend the latest version.`,
 		},
 		{
-			name:         "fails when GPDB version has unsupported major versions",
-			localVersion: semver.MustParse("0.0.0").String(),
-			expected:     errors.New("source cluster version 0.0.0 is not supported.  The minimum required version is 5.29.1. We recommend the latest version."),
+			name:     "fails when the source and target versions are not a supported pair",
+			source:   "6.18.0",
+			target:   "5.29.1",
+			expected: `target cluster version 5.29.1 is not supported.  The supported versions are ">=6.18.0 <7.0.0". We recommend the latest version.`,
 		},
 	}
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			err := validateVersion(c.localVersion, idl.ClusterDestination_SOURCE)
-			if err.Error() != c.expected.Error() {
-				t.Errorf("got %s want %s", err, c.expected)
+			_, err := validateVersions(matrix, c.source, c.target)
+			if err == nil || err.Error() != c.expected {
+				t.Errorf("got %v want %s", err, c.expected)
 			}
 		})
 	}
+
+	t.Run("fails when each version is supported but the pair is not", func(t *testing.T) {
+		matrix, err := ParseVersionMatrix([]byte(`{"upgrades": [
+			{"source": ">=5.29.1 <6.0.0", "target": ">=6.18.0 <7.0.0"},
+			{"source": ">=6.18.0 <7.0.0", "target": ">=7.0.0 <8.0.0"}
+		]}`), "test")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		_, err = validateVersions(matrix, "5.29.1", "7.0.0")
+		expected := "upgrading from source cluster version 5.29.1 to target cluster version 7.0.0 is not supported"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got %v want it to contain %q", err, expected)
+		}
+	})
 }
 
 func TestVerifyCompatibleGPDBVersions(t *testing.T) {
 	testlog.SetupLogger()
 
 	t.Run("returns error when gphome is incorrect", func(t *testing.T) {
-		err := VerifyCompatibleGPDBVersions("/usr/local/greenplum-db-source-typo", "")
+		_, err := VerifyCompatibleGPDBVersions("/usr/local/greenplum-db-source-typo", "")
 		var pathError *os.PathError
 		if !errors.As(err, &pathError) {
 			t.Errorf("got type %T want %T", err, pathError)
@@ -138,7 +98,7 @@ func TestVerifyCompatibleGPDBVersions(t *testing.T) {
 		SetVersionCommand(exectest.NewCommand(PostgresGPVersion_0_0_0))
 		defer ResetVersionCommand()
 
-		err := VerifyCompatibleGPDBVersions("", "")
+		_, err := VerifyCompatibleGPDBVersions("", "")
 		var errs errorlist.Errors
 		if !errors.As(err, &errs) {
 			t.Fatalf("got error %#v, want type %T", err, errs)
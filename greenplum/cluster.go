@@ -22,6 +22,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 const CoordinatorDbid = 1
@@ -374,7 +375,7 @@ func (c *Cluster) runGreenplumCommand(streams step.OutStreams, utility string, a
 	cmd.Stderr = streams.Stderr()
 
 	gplog.Info("executing: %s", cmd.String())
-	return cmd.Run()
+	return trace.Run(step.Context(streams), utility, cmd)
 }
 
 // WaitForClusterToBeReady waits until the timeout for all segments to be up,
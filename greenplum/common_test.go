@@ -0,0 +1,13 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package greenplum
+
+// TestVersionMatrix is the version matrix of the tests of the version matrix
+// and of validating versions against it.
+const TestVersionMatrix = `{
+  "upgrades": [
+    {"source": ">=5.29.1 <6.0.0", "target": ">=6.18.0 <7.0.0", "notes": "5X to 6X", "migrationPhases": ["pre-initialize", "post-finalize", "post-revert"]},
+    {"source": ">=6.18.0 <7.0.0", "target": ">=6.18.0 <7.0.0"}
+  ]
+}`
@@ -5,6 +5,7 @@ package greenplum
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/blang/semver/v4"
 	_ "github.com/greenplum-db/gp-common-go-libs/dbconn" // used indirectly as the database driver
@@ -37,7 +38,12 @@ func (c *Conn) URI(options ...Option) string {
 		version = c.TargetVersion
 	}
 
-	connURI := fmt.Sprintf("postgresql://localhost:%d/template1?search_path=", opts.port)
+	database := opts.database
+	if database == "" {
+		database = "template1"
+	}
+
+	connURI := fmt.Sprintf("postgresql://localhost:%d/%s?search_path=", opts.port, url.PathEscape(database))
 
 	if opts.utilityMode {
 		if version.LT(semver.MustParse("7.0.0")) {
@@ -75,6 +81,13 @@ func Port(port int) Option {
 	}
 }
 
+// Database connects to the named database instead of template1.
+func Database(name string) Option {
+	return func(options *optionList) {
+		options.database = name
+	}
+}
+
 func UtilityMode() Option {
 	return func(options *optionList) {
 		options.utilityMode = true
@@ -90,6 +103,7 @@ func AllowSystemTableMods() Option {
 type optionList struct {
 	connectToTarget      bool
 	port                 int
+	database             string
 	utilityMode          bool
 	allowSystemTableMods bool
 }
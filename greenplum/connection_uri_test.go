@@ -42,6 +42,16 @@ func TestConnURI(t *testing.T) {
 			},
 			"postgresql://localhost:12345/template1?search_path=",
 		},
+		{
+			"set database to a value",
+			v6X,
+			v7X,
+			[]greenplum.Option{
+				greenplum.Port(12345),
+				greenplum.Database("my db"),
+			},
+			"postgresql://localhost:12345/my%20db?search_path=",
+		},
 		{
 			"connect to source version less than 7X",
 			v5X,
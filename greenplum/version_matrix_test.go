@@ -0,0 +1,174 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package greenplum_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+func TestBuiltinVersionMatrix(t *testing.T) {
+	resetEnv := testutils.SetEnv(t, greenplum.VersionMatrixEnv, "")
+	defer resetEnv()
+
+	matrix, err := greenplum.LoadVersionMatrix()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	cases := []struct {
+		source   string
+		target   string
+		expected bool
+	}{
+		{"7.0.0", "7.0.0", true},
+		{"7.0.0", "7.50.1", true},
+		{"7.1.0", "7.2.0", true},
+		{"6.18.0", "7.0.0", false},
+		{"7.0.0", "8.0.0", false},
+		{"5.29.1", "6.18.0", false},
+	}
+
+	for _, c := range cases {
+		_, actual := matrix.Find(semver.MustParse(c.source), semver.MustParse(c.target))
+		if actual != c.expected {
+			t.Errorf("Find(%q, %q) = %t, want %t", c.source, c.target, actual, c.expected)
+		}
+	}
+}
+
+func TestParseVersionMatrix(t *testing.T) {
+	t.Run("parses the upgrade paths", func(t *testing.T) {
+		matrix, err := greenplum.ParseVersionMatrix([]byte(greenplum.TestVersionMatrix), "test")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		path, ok := matrix.Find(semver.MustParse("5.29.13"), semver.MustParse("6.20.0"))
+		if !ok {
+			t.Fatalf("expected to find an upgrade path")
+		}
+
+		expected := []string{"pre-initialize", "post-finalize", "post-revert"}
+		if !reflect.DeepEqual(path.MigrationPhases, expected) {
+			t.Errorf("got migration phases %v want %v", path.MigrationPhases, expected)
+		}
+
+		if path.Notes != "5X to 6X" {
+			t.Errorf("got notes %q want %q", path.Notes, "5X to 6X")
+		}
+	})
+
+	errCases := []struct {
+		name     string
+		matrix   string
+		expected string
+	}{
+		{
+			name:     "errors when the matrix is not valid JSON",
+			matrix:   `{"upgrades": [`,
+			expected: "parsing version matrix test",
+		},
+		{
+			name:     "errors when the matrix has no upgrades",
+			matrix:   `{"upgrades": []}`,
+			expected: "version matrix test has no upgrades",
+		},
+		{
+			name:     "errors when a range is invalid",
+			matrix:   `{"upgrades": [{"source": ">=six", "target": ">=6.18.0"}]}`,
+			expected: `invalid source range ">=six"`,
+		},
+		{
+			name:     "errors when a migration phase is invalid",
+			matrix:   `{"upgrades": [{"source": ">=6.18.0", "target": ">=6.18.0", "migrationPhases": ["post-execute"]}]}`,
+			expected: `invalid migration phase "post-execute"`,
+		},
+	}
+
+	for _, c := range errCases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := greenplum.ParseVersionMatrix([]byte(c.matrix), "test")
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("got error %v want it to contain %q", err, c.expected)
+			}
+		})
+	}
+}
+
+func TestLoadVersionMatrix(t *testing.T) {
+	t.Run("loads the built-in matrix by default", func(t *testing.T) {
+		resetEnv := testutils.SetEnv(t, greenplum.VersionMatrixEnv, "")
+		defer resetEnv()
+
+		matrix, err := greenplum.LoadVersionMatrix()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if matrix.Origin != "built-in" {
+			t.Errorf("got origin %q want %q", matrix.Origin, "built-in")
+		}
+	})
+
+	t.Run("loads the matrix from the override file", func(t *testing.T) {
+		testlog.SetupLogger()
+
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		path := filepath.Join(dir, "version_matrix.json")
+		testutils.MustWriteToFile(t, path, greenplum.TestVersionMatrix)
+
+		resetEnv := testutils.SetEnv(t, greenplum.VersionMatrixEnv, path)
+		defer resetEnv()
+
+		matrix, err := greenplum.LoadVersionMatrix()
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if matrix.Origin != path {
+			t.Errorf("got origin %q want %q", matrix.Origin, path)
+		}
+
+		if len(matrix.Upgrades) != 2 {
+			t.Errorf("got %d upgrades want 2", len(matrix.Upgrades))
+		}
+	})
+
+	t.Run("errors when the override file does not exist", func(t *testing.T) {
+		resetEnv := testutils.SetEnv(t, greenplum.VersionMatrixEnv, "/does/not/exist")
+		defer resetEnv()
+
+		_, err := greenplum.LoadVersionMatrix()
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want %#v", err, os.ErrNotExist)
+		}
+	})
+}
+
+func TestMigrationNote(t *testing.T) {
+	path := greenplum.UpgradePath{Source: ">=5.29.1 <6.0.0", Target: ">=6.18.0 <7.0.0"}
+	if note := path.MigrationNote(); note != "" {
+		t.Errorf("got note %q want empty", note)
+	}
+
+	path.MigrationPhases = []string{"pre-initialize", "post-finalize"}
+	path.Notes = "5X to 6X"
+	expected := "NOTE: Upgrading from >=5.29.1 <6.0.0 to >=6.18.0 <7.0.0 requires the pre-initialize, post-finalize data migration scripts.\n5X to 6X"
+	if note := path.MigrationNote(); note != expected {
+		t.Errorf("got note %q want %q", note, expected)
+	}
+}
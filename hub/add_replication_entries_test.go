@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"net"
 	"os/user"
@@ -72,7 +73,7 @@ func TestAddReplicationEntriesOnPrimaries(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.AddReplicationEntriesOnPrimaries(agentConns, intermediate, false)
+		err := hub.AddReplicationEntriesOnPrimaries(context.Background(), agentConns, intermediate, false)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -124,7 +125,7 @@ func TestAddReplicationEntriesOnPrimaries(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.AddReplicationEntriesOnPrimaries(agentConns, intermediate, true)
+		err := hub.AddReplicationEntriesOnPrimaries(context.Background(), agentConns, intermediate, true)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -152,7 +153,7 @@ func TestAddReplicationEntriesOnPrimaries(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.AddReplicationEntriesOnPrimaries(agentConns, intermediate, false)
+		err := hub.AddReplicationEntriesOnPrimaries(context.Background(), agentConns, intermediate, false)
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -178,7 +179,7 @@ func TestAddReplicationEntriesOnPrimaries(t *testing.T) {
 			utils.System.Current = user.Current
 		}()
 
-		err := hub.AddReplicationEntriesOnPrimaries(nil, intermediate, true)
+		err := hub.AddReplicationEntriesOnPrimaries(context.Background(), nil, intermediate, true)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
@@ -201,7 +202,7 @@ func TestAddReplicationEntriesOnPrimaries(t *testing.T) {
 			{AgentClient: nil, Hostname: "sdw2"},
 		}
 
-		err := hub.AddReplicationEntriesOnPrimaries(agentConns, intermediate, true)
+		err := hub.AddReplicationEntriesOnPrimaries(context.Background(), agentConns, intermediate, true)
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -12,7 +12,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils"
 )
 
-func ArchiveLogDirectories(logArchiveDir string, agentConns []*idl.Connection, targetCoordinatorHost string) error {
+func ArchiveLogDirectories(ctx context.Context, logArchiveDir string, agentConns []*idl.Connection, targetCoordinatorHost string) error {
 	// Archive log directory on coordinator
 	logDir, err := utils.GetLogDir()
 	if err != nil {
@@ -25,17 +25,17 @@ func ArchiveLogDirectories(logArchiveDir string, agentConns []*idl.Connection, t
 	}
 
 	// Archive log directory on segments
-	return ArchiveSegmentLogDirectories(agentConns, targetCoordinatorHost, logArchiveDir)
+	return ArchiveSegmentLogDirectories(ctx, agentConns, targetCoordinatorHost, logArchiveDir)
 
 }
 
-func ArchiveSegmentLogDirectories(agentConns []*idl.Connection, excludeHostname, newDir string) error {
+func ArchiveSegmentLogDirectories(ctx context.Context, agentConns []*idl.Connection, excludeHostname, newDir string) error {
 	request := func(conn *idl.Connection) error {
 		if conn.Hostname == excludeHostname {
 			return nil
 		}
 
-		_, err := conn.AgentClient.ArchiveLogDirectory(context.Background(), &idl.ArchiveLogDirectoryRequest{
+		_, err := conn.AgentClient.ArchiveLogDirectory(ctx, &idl.ArchiveLogDirectoryRequest{
 			NewDir: newDir,
 		})
 		return err
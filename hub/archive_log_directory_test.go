@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -34,7 +35,7 @@ func TestArchiveLogDirectories(t *testing.T) {
 			{AgentClient: sdwClient, Hostname: "sdw"},
 		}
 
-		err := hub.ArchiveSegmentLogDirectories(agentConns, "", newDir)
+		err := hub.ArchiveSegmentLogDirectories(context.Background(), agentConns, "", newDir)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -55,7 +56,7 @@ func TestArchiveLogDirectories(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "sdw"},
 		}
 
-		err := hub.ArchiveSegmentLogDirectories(agentConns, "", newDir)
+		err := hub.ArchiveSegmentLogDirectories(context.Background(), agentConns, "", newDir)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
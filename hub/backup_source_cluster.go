@@ -0,0 +1,92 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils/backup"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
+)
+
+var NewBackupHook = backup.New
+
+// UseBackup returns whether the source cluster is backed up during execute.
+func UseBackup(provider string) bool {
+	return provider != "" && provider != backup.None
+}
+
+// BackupSourceCluster takes a backup of the running source cluster, or
+// verifies the configured backup, and registers it in the configuration.
+func BackupSourceCluster(streams step.OutStreams, conn *greenplum.Conn, config *Config, saveConfig func() error) error {
+	var databases []string
+	if config.BackupProvider == backup.GPBackup && config.BackupTimestamp == "" {
+		err := withDatabase(conn, config.Source, idl.ClusterDestination_SOURCE, "", func(db *sql.DB) error {
+			var err error
+			databases, err = backupDatabases(db)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	source := backup.Source{
+		GPHome:             config.Source.GPHome,
+		Port:               config.Source.CoordinatorPort(),
+		CoordinatorDataDir: config.Source.CoordinatorDataDir(),
+		UpgradeID:          config.UpgradeID.String(),
+	}
+
+	hook, err := NewBackupHook(config.BackupProvider, source, databases, config.BackupCommand)
+	if err != nil {
+		return err
+	}
+
+	b, err := hook.Backup(streams, config.BackupTimestamp)
+	if err != nil {
+		return err
+	}
+
+	gplog.Info("registered %s", b.String())
+	if _, err := fmt.Fprintf(streams.Stdout(), "Registered %s.\n", b.String()); err != nil {
+		return err
+	}
+
+	config.Backup = &b
+	return saveConfig()
+}
+
+// backupDatabases returns the databases to back up. template1 is recreated by
+// gpinitsystem, so it is not backed up.
+func backupDatabases(db *sql.DB) ([]string, error) {
+	all, err := inventory.QueryDatabases(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var databases []string
+	for _, database := range all {
+		if database != "template1" {
+			databases = append(databases, database)
+		}
+	}
+
+	return databases, nil
+}
+
+// BackupMessage describes the registered backup, if any, for the CLI.
+func BackupMessage(b *backup.Backup) *idl.Backup {
+	if b == nil {
+		return nil
+	}
+
+	return &idl.Backup{Description: b.String(), Restore: b.Restore}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/backup"
+)
+
+type fakeBackupHook struct {
+	backup    backup.Backup
+	err       error
+	timestamp string
+}
+
+func (f *fakeBackupHook) Backup(_ step.OutStreams, timestamp string) (backup.Backup, error) {
+	f.timestamp = timestamp
+	return f.backup, f.err
+}
+
+func TestBackupSourceCluster(t *testing.T) {
+	testlog.SetupLogger()
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Port: 15432, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Port: 25432, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+	})
+	source.GPHome = "/usr/local/gpdb5"
+
+	id := upgrade.NewID()
+
+	t.Run("registers the backup of the source cluster", func(t *testing.T) {
+		hook := &fakeBackupHook{backup: backup.Backup{Provider: backup.Command, ID: "nightly-42"}}
+
+		hub.NewBackupHook = func(provider string, src backup.Source, databases []string, command string) (backup.Hook, error) {
+			if provider != backup.Command || command != "nightly {{.Timestamp}}" {
+				t.Errorf("got provider %q and command %q", provider, command)
+			}
+
+			expected := backup.Source{GPHome: "/usr/local/gpdb5", Port: 15432, CoordinatorDataDir: "/data/qddir/seg-1", UpgradeID: id.String()}
+			if src != expected {
+				t.Errorf("got source %+v want %+v", src, expected)
+			}
+
+			return hook, nil
+		}
+		defer func() {
+			hub.NewBackupHook = backup.New
+		}()
+
+		config := &hub.Config{
+			Source:          source,
+			UpgradeID:       id,
+			BackupProvider:  backup.Command,
+			BackupCommand:   "nightly {{.Timestamp}}",
+			BackupTimestamp: "nightly-42",
+		}
+
+		var saved bool
+		err := hub.BackupSourceCluster(step.DevNullStream, nil, config, func() error {
+			saved = true
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if hook.timestamp != "nightly-42" {
+			t.Errorf("got timestamp %q want %q", hook.timestamp, "nightly-42")
+		}
+
+		if !reflect.DeepEqual(config.Backup, &hook.backup) {
+			t.Errorf("got backup %+v want %+v", config.Backup, hook.backup)
+		}
+
+		if !saved {
+			t.Errorf("expected the configuration to be saved")
+		}
+	})
+
+	t.Run("does not register a failed backup", func(t *testing.T) {
+		expected := errors.New("permission denied")
+		hub.NewBackupHook = func(string, backup.Source, []string, string) (backup.Hook, error) {
+			return &fakeBackupHook{err: expected}, nil
+		}
+		defer func() {
+			hub.NewBackupHook = backup.New
+		}()
+
+		config := &hub.Config{Source: source, UpgradeID: id, BackupProvider: backup.Command, BackupCommand: "nightly"}
+		err := hub.BackupSourceCluster(step.DevNullStream, nil, config, func() error {
+			t.Errorf("unexpected save")
+			return nil
+		})
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+
+		if config.Backup != nil {
+			t.Errorf("got backup %+v want nil", config.Backup)
+		}
+	})
+}
+
+func TestBackupMessage(t *testing.T) {
+	if msg := hub.BackupMessage(nil); msg != nil {
+		t.Errorf("got %v want nil", msg)
+	}
+
+	msg := hub.BackupMessage(&backup.Backup{Provider: backup.GPBackup, ID: "20220101120000", Restore: "gprestore --timestamp 20220101120000"})
+	if msg.GetDescription() != "gpbackup backup 20220101120000" || msg.GetRestore() != "gprestore --timestamp 20220101120000" {
+		t.Errorf("got %v", msg)
+	}
+}
@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -49,7 +50,7 @@ func TestCheckDiskSpace_OnCoordinator(t *testing.T) {
 		hub.SetCheckDiskUsage(CoordinatorHostCheckDiskUsagePasses)
 		defer hub.ResetCheckDiskUsage()
 
-		err := hub.CheckDiskSpace(step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
 		if err != nil {
 			t.Errorf("unexpected error %#v", err)
 		}
@@ -60,7 +61,7 @@ func TestCheckDiskSpace_OnCoordinator(t *testing.T) {
 		hub.SetCheckDiskUsage(CoordinatorHostErrorsWith(expected))
 		defer hub.ResetCheckDiskUsage()
 
-		err := hub.CheckDiskSpace(step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
@@ -76,7 +77,7 @@ func TestCheckDiskSpace_OnCoordinator(t *testing.T) {
 		hub.SetCheckDiskUsage(CoordinatorHostReturnsUsage(disk.FileSystemDiskUsage{&usage}))
 		defer hub.ResetCheckDiskUsage()
 
-		err := hub.CheckDiskSpace(step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, []*idl.Connection{}, 0, source, tablespaces)
 		expected := disk.NewSpaceUsageErrorFromUsage(usage)
 		if !reflect.DeepEqual(err, expected) {
 			t.Errorf("returned %v want %v", err, expected)
@@ -138,7 +139,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, diskFreeRatio, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, diskFreeRatio, source, tablespaces)
 		if err != nil {
 			t.Errorf("unexpected error %#v", err)
 		}
@@ -159,7 +160,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "sdw1"},
 		}
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, 0, source, tablespaces)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
@@ -186,7 +187,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "smdw"},
 		}
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, 0, source, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, 0, source, tablespaces)
 		expected := disk.NewSpaceUsageErrorFromUsage(usage)
 		if !reflect.DeepEqual(err, expected) {
 			t.Errorf("returned %v want %v", err, expected)
@@ -247,7 +248,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{DbID: 6, ContentID: 1, Hostname: "mirror", DataDir: "/data/dbfast_mirror2/seg2", Role: greenplum.MirrorRole},
 		})
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, 0, sourceCluster, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, 0, sourceCluster, tablespaces)
 		expected := [][]string{
 			{"Hostname", "Filesystem", "Shortfall", "Available", "Required"},
 			{"mirror", "/data", disk.FormatBytes(2024), disk.FormatBytes(2024), disk.FormatBytes(4048)},
@@ -283,7 +284,7 @@ func TestCheckDiskSpace_OnSegments(t *testing.T) {
 			{ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
 		})
 
-		err := hub.CheckDiskSpace(step.DevNullStream, agentConns, 0, coordinatorOnlyCluster, tablespaces)
+		err := hub.CheckDiskSpace(context.Background(), step.DevNullStream, agentConns, 0, coordinatorOnlyCluster, tablespaces)
 		if err != nil {
 			t.Errorf("unexpected error %#v", err)
 		}
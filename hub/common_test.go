@@ -5,13 +5,16 @@ package hub
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"testing"
 
 	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/utils/disk"
+	"github.com/greenplum-db/gpupgrade/utils/preflight"
 	"github.com/greenplum-db/gpupgrade/utils/rsync"
 )
 
@@ -74,6 +77,30 @@ func ResetCheckDiskUsage() {
 	checkDiskUsage = disk.CheckUsage
 }
 
+func SetEstimateDiskUsage(usageFunc disk.EstimateUsageType) {
+	estimateDiskUsage = usageFunc
+}
+
+func ResetEstimateDiskUsage() {
+	estimateDiskUsage = disk.EstimateUsage
+}
+
+func SetDirSize(sizeFunc func(string) (uint64, error)) {
+	dirSize = sizeFunc
+}
+
+func ResetDirSize() {
+	dirSize = disk.Local.DirSize
+}
+
+func SetLocalPreflight(preflightFunc func(context.Context, *idl.PreflightRequest) []*idl.PreflightReply_Check) {
+	localPreflight = preflightFunc
+}
+
+func ResetLocalPreflight() {
+	localPreflight = preflight.Run
+}
+
 // MustCreateCluster creates a utils.Cluster and calls t.Fatalf() if there is
 // any error.
 func MustCreateCluster(t *testing.T, segments greenplum.SegConfigs) *greenplum.Cluster {
@@ -5,9 +5,18 @@ package hub
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
 	"strconv"
+	"strings"
 
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -19,6 +28,8 @@ func (s *Server) GetConfig(ctx context.Context, in *idl.GetConfigRequest) (*idl.
 	switch in.Name {
 	case "id":
 		resp.Value = s.UpgradeID.String()
+	case "point-of-no-return-token":
+		resp.Value = s.UpgradeID.PointOfNoReturnToken()
 	case "source-gphome":
 		if s.Source != nil {
 			resp.Value = s.Source.GPHome
@@ -39,3 +50,199 @@ func (s *Server) GetConfig(ctx context.Context, in *idl.GetConfigRequest) (*idl.
 
 	return resp, nil
 }
+
+func (s *Server) ShowConfig(ctx context.Context, in *idl.ShowConfigRequest) (*idl.ShowConfigReply, error) {
+	config, err := json.Marshal(NewConfigView(s.Config))
+	if err != nil {
+		return nil, xerrors.Errorf("encoding config: %w", err)
+	}
+
+	return &idl.ShowConfigReply{Config: config}, nil
+}
+
+// ConfigView is the persisted configuration as shown by "gpupgrade config
+// show". The clusters are nil until initialize has generated them.
+type ConfigView struct {
+	UpgradeID        string `json:"upgrade_id" yaml:"upgrade_id"`
+	Mode             string `json:"mode" yaml:"mode"`
+	HubPort          int    `json:"hub_port" yaml:"hub_port"`
+	AgentPort        int    `json:"agent_port" yaml:"agent_port"`
+	MetricsPort      int    `json:"metrics_port,omitempty" yaml:"metrics_port,omitempty"`
+	TraceExporter    string `json:"trace_exporter,omitempty" yaml:"trace_exporter,omitempty"`
+	TraceEndpoint    string `json:"trace_endpoint,omitempty" yaml:"trace_endpoint,omitempty"`
+	NotifyWebhook    string `json:"notify_webhook,omitempty" yaml:"notify_webhook,omitempty"`
+	NotifyEmail      string `json:"notify_email,omitempty" yaml:"notify_email,omitempty"`
+	NotifySMTP       string `json:"notify_smtp,omitempty" yaml:"notify_smtp,omitempty"`
+	LogFormat        string `json:"log_format" yaml:"log_format"`
+	UseHbaHostnames  bool   `json:"use_hba_hostnames" yaml:"use_hba_hostnames"`
+	LogArchiveDir    string `json:"log_archive_dir" yaml:"log_archive_dir"`
+	SnapshotProvider string `json:"snapshot_provider" yaml:"snapshot_provider"`
+	MirrorResync     string `json:"mirror_resync" yaml:"mirror_resync"`
+	BackupProvider   string `json:"backup_provider" yaml:"backup_provider"`
+	Backup           string `json:"backup,omitempty" yaml:"backup,omitempty"`
+
+	Source       *ClusterView `json:"source" yaml:"source"`
+	Intermediate *ClusterView `json:"intermediate" yaml:"intermediate"`
+	Target       *ClusterView `json:"target" yaml:"target"`
+}
+
+// ClusterView is the layout of a cluster. The segments are ordered by content
+// with the mirror of each content following its primary.
+type ClusterView struct {
+	GPHome         string           `json:"gphome" yaml:"gphome"`
+	Version        string           `json:"version" yaml:"version"`
+	CatalogVersion string           `json:"catalog_version,omitempty" yaml:"catalog_version,omitempty"`
+	Segments       []SegmentView    `json:"segments" yaml:"segments"`
+	Tablespaces    []TablespaceView `json:"tablespaces,omitempty" yaml:"tablespaces,omitempty"`
+}
+
+type SegmentView struct {
+	DbID      int    `json:"dbid" yaml:"dbid"`
+	ContentID int    `json:"content" yaml:"content"`
+	Role      string `json:"role" yaml:"role"`
+	Hostname  string `json:"hostname" yaml:"hostname"`
+	Port      int    `json:"port" yaml:"port"`
+	DataDir   string `json:"datadir" yaml:"datadir"`
+}
+
+// TablespaceView maps a user defined tablespace location of a source segment to where the
+// upgraded segment keeps it. The target location is only known once the target
+// cluster has been created.
+type TablespaceView struct {
+	DbID           int    `json:"dbid" yaml:"dbid"`
+	Oid            int    `json:"oid" yaml:"oid"`
+	Location       string `json:"location" yaml:"location"`
+	TargetLocation string `json:"target_location,omitempty" yaml:"target_location,omitempty"`
+}
+
+func NewConfigView(config *Config) ConfigView {
+	view := ConfigView{
+		UpgradeID:        config.UpgradeID.String(),
+		Mode:             config.Mode.String(),
+		HubPort:          config.Port,
+		AgentPort:        config.AgentPort,
+		MetricsPort:      config.MetricsPort,
+		UseHbaHostnames:  config.UseHbaHostnames,
+		LogArchiveDir:    config.LogArchiveDir,
+		SnapshotProvider: config.SnapshotProvider,
+		MirrorResync:     config.MirrorResync,
+		BackupProvider:   config.BackupProvider,
+		Source:           newClusterView(config.Source),
+		Intermediate:     newClusterView(config.Intermediate),
+		Target:           newClusterView(config.Target),
+	}
+
+	if config.Tracing.Enabled() {
+		view.TraceExporter = config.Tracing.Exporter
+		view.TraceEndpoint = config.Tracing.Endpoint
+	}
+
+	view.NotifyWebhook = config.Notifications.Webhook
+	view.NotifyEmail = strings.Join(config.Notifications.Email, ",")
+	view.NotifySMTP = config.Notifications.SMTP
+
+	view.LogFormat = log.Text
+	if config.LogFormat == log.JSON {
+		view.LogFormat = log.JSON
+	}
+
+	if config.Backup != nil {
+		view.Backup = config.Backup.String()
+	}
+
+	if view.Source != nil {
+		view.Source.Tablespaces = newTablespaceViews(config.Source.Tablespaces, config.Intermediate)
+	}
+
+	return view
+}
+
+func newClusterView(cluster *greenplum.Cluster) *ClusterView {
+	if cluster == nil {
+		return nil
+	}
+
+	view := &ClusterView{
+		GPHome:         cluster.GPHome,
+		CatalogVersion: cluster.CatalogVersion,
+		Segments:       []SegmentView{},
+	}
+
+	if !cluster.Version.Equals(semver.Version{}) {
+		view.Version = cluster.Version.String()
+	}
+
+	for _, segments := range []greenplum.ContentToSegConfig{cluster.Primaries, cluster.Mirrors} {
+		for _, seg := range segments {
+			view.Segments = append(view.Segments, SegmentView{
+				DbID:      seg.DbID,
+				ContentID: seg.ContentID,
+				Role:      segmentRole(seg),
+				Hostname:  seg.Hostname,
+				Port:      seg.Port,
+				DataDir:   seg.DataDir,
+			})
+		}
+	}
+
+	// List the coordinator and standby first, followed by each primary and
+	// its mirror.
+	sort.Slice(view.Segments, func(i, j int) bool {
+		if view.Segments[i].ContentID != view.Segments[j].ContentID {
+			return view.Segments[i].ContentID < view.Segments[j].ContentID
+		}
+
+		return isPrimaryRole(view.Segments[i].Role) && !isPrimaryRole(view.Segments[j].Role)
+	})
+
+	return view
+}
+
+func isPrimaryRole(role string) bool {
+	return role == "coordinator" || role == "primary"
+}
+
+func segmentRole(seg greenplum.SegConfig) string {
+	switch {
+	case seg.IsCoordinator():
+		return "coordinator"
+	case seg.IsStandby():
+		return "standby"
+	case seg.IsMirror():
+		return "mirror"
+	default:
+		return "primary"
+	}
+}
+
+// newTablespaceViews maps the user defined tablespaces of the source segments
+// to their locations in the target cluster, which are known once the target
+// catalog version is.
+func newTablespaceViews(tablespaces greenplum.Tablespaces, intermediate *greenplum.Cluster) []TablespaceView {
+	var views []TablespaceView
+	for dbID, segTablespaces := range tablespaces {
+		for oid, info := range segTablespaces {
+			info := info
+			if !info.IsUserDefined() {
+				continue
+			}
+
+			view := TablespaceView{DbID: dbID, Oid: oid, Location: info.Location}
+			if intermediate != nil && intermediate.CatalogVersion != "" {
+				view.TargetLocation = upgrade.TablespacePath(info.Location, dbID, intermediate.Version.Major, intermediate.CatalogVersion)
+			}
+
+			views = append(views, view)
+		}
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		if views[i].DbID != views[j].DbID {
+			return views[i].DbID < views[j].DbID
+		}
+
+		return views[i].Oid < views[j].Oid
+	})
+
+	return views
+}
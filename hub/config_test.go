@@ -0,0 +1,93 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func TestNewConfigView(t *testing.T) {
+	t.Run("shows the configuration before initialize has generated the clusters", func(t *testing.T) {
+		view := hub.NewConfigView(&hub.Config{Port: 7527, AgentPort: 6416})
+		if view.Source != nil || view.Intermediate != nil || view.Target != nil {
+			t.Errorf("got clusters %+v", view)
+		}
+
+		if view.HubPort != 7527 || view.AgentPort != 6416 {
+			t.Errorf("got ports %d and %d", view.HubPort, view.AgentPort)
+		}
+
+		// Configurations written before the log format existed log as text.
+		if view.LogFormat != "text" {
+			t.Errorf("got log format %q want text", view.LogFormat)
+		}
+	})
+
+	t.Run("orders the segments and maps the tablespaces to the target", func(t *testing.T) {
+		source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 5, ContentID: 1, Hostname: "sdw2", Port: 25433, DataDir: "/data/dbfast2/seg2", Role: greenplum.PrimaryRole},
+			{DbID: 1, ContentID: -1, Hostname: "cdw", Port: 15432, DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw2", Port: 25432, DataDir: "/data/dbfast_mirror1/seg1", Role: greenplum.MirrorRole},
+			{DbID: 2, ContentID: 0, Hostname: "sdw1", Port: 25432, DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+			{DbID: 6, ContentID: -1, Hostname: "scdw", Port: 15432, DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		})
+		source.GPHome = "/usr/local/gpdb5"
+		source.Version = semver.MustParse("5.28.0")
+		source.Tablespaces = greenplum.Tablespaces{
+			2: {
+				1663:  {Location: "/data/dbfast1/seg1", UserDefined: 0},
+				16386: {Location: "/tmp/tblspc/2", UserDefined: 1},
+			},
+		}
+
+		intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "cdw", Port: 50432, DataDir: "/data/qddir/seg.ABC.-1", Role: greenplum.PrimaryRole},
+		})
+		intermediate.GPHome = "/usr/local/gpdb6"
+		intermediate.Version = semver.MustParse("6.20.0")
+		intermediate.CatalogVersion = "301908232"
+
+		view := hub.NewConfigView(&hub.Config{
+			Source:       source,
+			Intermediate: intermediate,
+			Mode:         idl.Mode_link,
+		})
+
+		if view.Mode != "link" {
+			t.Errorf("got mode %q want link", view.Mode)
+		}
+
+		var roles []string
+		for _, seg := range view.Source.Segments {
+			roles = append(roles, seg.Role)
+		}
+
+		expectedRoles := []string{"coordinator", "standby", "primary", "mirror", "primary"}
+		if !reflect.DeepEqual(roles, expectedRoles) {
+			t.Errorf("got roles %q want %q", roles, expectedRoles)
+		}
+
+		expectedTablespaces := []hub.TablespaceView{
+			{DbID: 2, Oid: 16386, Location: "/tmp/tblspc/2", TargetLocation: "/tmp/tblspc/2/2/GPDB_6_301908232"},
+		}
+		if !reflect.DeepEqual(view.Source.Tablespaces, expectedTablespaces) {
+			t.Errorf("got tablespaces %+v want %+v", view.Source.Tablespaces, expectedTablespaces)
+		}
+
+		if view.Intermediate.Version != "6.20.0" || view.Intermediate.Segments[0].Port != 50432 {
+			t.Errorf("got intermediate %+v", view.Intermediate)
+		}
+
+		if view.Target != nil {
+			t.Errorf("got target %+v want nil", view.Target)
+		}
+	})
+}
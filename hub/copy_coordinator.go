@@ -43,6 +43,7 @@ func Copy(streams step.OutStreams, destinationDir string, sourceDirs, hosts []st
 			stream := &step.BufferedStreams{}
 
 			options := []rsync.Option{
+				rsync.WithContext(step.Context(streams)),
 				rsync.WithSources(sourceDirs...),
 				rsync.WithDestinationHost(hostname),
 				rsync.WithDestination(destinationDir),
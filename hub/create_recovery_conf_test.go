@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"os/user"
 	"testing"
@@ -73,7 +74,7 @@ func TestCreateRecoveryConfOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.CreateRecoveryConfOnSegments(agentConns, intermediate)
+		err := hub.CreateRecoveryConfOnSegments(context.Background(), agentConns, intermediate)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -101,7 +102,7 @@ func TestCreateRecoveryConfOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.CreateRecoveryConfOnSegments(agentConns, intermediate)
+		err := hub.CreateRecoveryConfOnSegments(context.Background(), agentConns, intermediate)
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -127,7 +128,7 @@ func TestCreateRecoveryConfOnSegments(t *testing.T) {
 			utils.System.Current = user.Current
 		}()
 
-		err := hub.CreateRecoveryConfOnSegments(nil, intermediate)
+		err := hub.CreateRecoveryConfOnSegments(context.Background(), nil, intermediate)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
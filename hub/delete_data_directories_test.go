@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -68,7 +69,7 @@ func TestDeleteSegmentDataDirs(t *testing.T) {
 
 			intermediate := hub.MustCreateCluster(t, append(primarySegConfigs, greenplum.SegConfig{ContentID: -1, DbID: 0, Port: 25431, Hostname: "coordinator", DataDir: "/data/qddir", Role: greenplum.PrimaryRole}))
 
-			err := hub.DeleteCoordinatorAndPrimaryDataDirectories(step.DevNullStream, agentConns, intermediate)
+			err := hub.DeleteCoordinatorAndPrimaryDataDirectories(context.Background(), step.DevNullStream, agentConns, intermediate)
 			if err != nil {
 				t.Errorf("unexpected err %#v", err)
 			}
@@ -98,7 +99,7 @@ func TestDeleteSegmentDataDirs(t *testing.T) {
 
 			intermediate := hub.MustCreateCluster(t, append(primarySegConfigs, greenplum.SegConfig{ContentID: -1, DbID: 0, Port: 25431, Hostname: "coordinator", DataDir: "/data/qddir", Role: greenplum.PrimaryRole}))
 
-			err := hub.DeleteCoordinatorAndPrimaryDataDirectories(step.DevNullStream, agentConns, intermediate)
+			err := hub.DeleteCoordinatorAndPrimaryDataDirectories(context.Background(), step.DevNullStream, agentConns, intermediate)
 
 			if !errors.Is(err, expected) {
 				t.Errorf("got error %#v, want %#v", err, expected)
@@ -245,7 +246,7 @@ func TestDeleteTablespaceDirectories(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.DeleteTargetTablespacesOnPrimaries(agentConns, target, tablespaces, "301908232")
+		err := hub.DeleteTargetTablespacesOnPrimaries(context.Background(), agentConns, target, tablespaces, "301908232")
 		if err != nil {
 			t.Errorf("DeleteTargetTablespacesOnPrimaries returned error %+v", err)
 		}
@@ -273,7 +274,7 @@ func TestDeleteTablespaceDirectories(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "sdw2"},
 		}
 
-		err := hub.DeleteTargetTablespacesOnPrimaries(agentConns, target, nil, "")
+		err := hub.DeleteTargetTablespacesOnPrimaries(context.Background(), agentConns, target, nil, "")
 
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
@@ -292,7 +293,7 @@ func TestDeleteTablespaceDirectories(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.DeleteTargetTablespacesOnPrimaries(agentConns, nil, nil, "")
+		err := hub.DeleteTargetTablespacesOnPrimaries(context.Background(), agentConns, nil, nil, "")
 		if err != nil {
 			t.Errorf("unexpected error %#v", err)
 		}
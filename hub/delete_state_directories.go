@@ -9,13 +9,13 @@ import (
 	"github.com/greenplum-db/gpupgrade/idl"
 )
 
-func DeleteStateDirectories(agentConns []*idl.Connection, excludeHostname string) error {
+func DeleteStateDirectories(ctx context.Context, agentConns []*idl.Connection, excludeHostname string) error {
 	request := func(conn *idl.Connection) error {
 		if conn.Hostname == excludeHostname {
 			return nil
 		}
 
-		_, err := conn.AgentClient.DeleteStateDirectory(context.Background(), &idl.DeleteStateDirectoryRequest{})
+		_, err := conn.AgentClient.DeleteStateDirectory(ctx, &idl.DeleteStateDirectoryRequest{})
 		return err
 	}
 
@@ -0,0 +1,295 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// DiskSpaceSafetyMargin is the fraction added to the estimated space to account
+// for data growth and the imprecision of the estimates.
+const DiskSpaceSafetyMargin = 0.1
+
+const (
+	// pgUpgradeWorkingDirBytes is an allowance per segment for the schema
+	// dump, logs, and other files pg_upgrade writes to its working directory.
+	pgUpgradeWorkingDirBytes = 256 * 1024 * 1024
+
+	// logGrowthBytes is an allowance per host for the gpupgrade logs.
+	logGrowthBytes = 64 * 1024 * 1024
+)
+
+var estimateDiskUsage = disk.EstimateUsage
+var dirSize = disk.Local.DirSize
+
+// EstimateDiskSpace estimates the space needed on every host and filesystem
+// from the size of the source cluster's data directories and the upgrade mode.
+// It reports the required and available space, and returns a SpaceUsageErr if
+// any filesystem does not have enough.
+func EstimateDiskSpace(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster, mode idl.Mode) error {
+	coordinatorBytes, err := dirSize(source.CoordinatorDataDir())
+	if err != nil {
+		return xerrors.Errorf("determining size of coordinator data directory: %w", err)
+	}
+
+	var coordinatorTablespaceBytes uint64
+	for _, location := range source.Tablespaces.GetCoordinatorTablespaces().UserDefinedTablespacesLocations() {
+		size, err := dirSize(location)
+		if err != nil {
+			return xerrors.Errorf("determining size of coordinator tablespace: %w", err)
+		}
+
+		coordinatorTablespaceBytes += size
+	}
+
+	logDir, err := utils.GetLogDir()
+	if err != nil {
+		return err
+	}
+
+	estimates := DiskSpaceEstimates(source, mode, coordinatorBytes, coordinatorTablespaceBytes, logDir)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(agentConns)+1)
+	usages := make(chan disk.FileSystemDiskUsage, len(agentConns)+1)
+
+	// When an agent runs on the coordinator host it checks the coordinator's
+	// estimates along with the segments so that space on shared filesystems is
+	// only counted once.
+	coordinatorHasAgent := false
+	for _, conn := range agentConns {
+		if conn.Hostname == source.CoordinatorHostname() {
+			coordinatorHasAgent = true
+		}
+	}
+
+	if !coordinatorHasAgent {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			usage, err := estimateDiskUsage(streams, disk.Local, DiskSpaceSafetyMargin, estimates[source.CoordinatorHostname()]...)
+			errs <- err
+			usages <- usage
+		}()
+	}
+
+	for _, conn := range agentConns {
+		conn := conn
+
+		if len(estimates[conn.Hostname]) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			req := &idl.CheckSegmentDiskSpaceRequest{
+				Estimates:    estimates[conn.Hostname],
+				SafetyMargin: DiskSpaceSafetyMargin,
+			}
+
+			reply, err := conn.AgentClient.CheckDiskSpace(ctx, req)
+			errs <- err
+			usages <- reply.GetEstimated()
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	close(usages)
+
+	for e := range errs {
+		err = errorlist.Append(err, e)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var estimated disk.FileSystemDiskUsage
+	for usage := range usages {
+		estimated = append(estimated, usage...)
+	}
+
+	recordDiskUsage(estimated)
+
+	fmt.Fprintf(streams.Stdout(), "Estimated disk space required including a %.0f%% safety margin:\n%s",
+		DiskSpaceSafetyMargin*100, disk.Report(estimated))
+
+	insufficient := estimated.Insufficient()
+	if len(insufficient) == 0 {
+		return nil
+	}
+
+	totalUsage := make(map[disk.FilesystemHost]*idl.CheckDiskSpaceReply_DiskUsage)
+	for _, usage := range insufficient {
+		totalUsage[disk.FilesystemHost{Filesystem: usage.GetFs(), Host: usage.GetHost()}] = usage
+	}
+
+	return disk.NewSpaceUsageError(totalUsage)
+}
+
+// DiskSpaceEstimates returns the estimates of the space the upgrade will
+// consume on each host keyed by hostname. Sizes of directories local to a host
+// are computed by that host, while the sizes of the coordinator data directory
+// and tablespaces are passed in since they are copied to the segment hosts.
+func DiskSpaceEstimates(source *greenplum.Cluster, mode idl.Mode, coordinatorBytes uint64, coordinatorTablespaceBytes uint64, logDir string) map[string][]*idl.DiskSpaceEstimate {
+	estimates := make(map[string][]*idl.DiskSpaceEstimate)
+	add := func(host string, estimate *idl.DiskSpaceEstimate) {
+		if estimate.GetFactor() == 0 && estimate.GetExtraBytes() == 0 {
+			return
+		}
+
+		estimates[host] = append(estimates[host], estimate)
+	}
+
+	// In copy mode pg_upgrade copies the user data into the new data
+	// directories, while in link mode the data is hard linked and in clone
+	// mode reflinked, so only the catalog is rewritten.
+	copyFactor := 1.0
+	if mode == idl.Mode_link || mode == idl.Mode_clone {
+		copyFactor = 0
+	}
+
+	mirrorFactor := 1.0
+	if mode == idl.Mode_link {
+		mirrorFactor = 0
+	}
+
+	coordinator := source.Coordinator()
+	coordinatorTablespaces := source.Tablespaces.GetCoordinatorTablespaces().UserDefinedTablespacesLocations()
+
+	add(coordinator.Hostname, &idl.DiskSpaceEstimate{
+		Description: "target coordinator data directory",
+		Destination: filepath.Dir(coordinator.DataDir),
+		Sources:     []string{coordinator.DataDir},
+		Factor:      1,
+	})
+
+	add(coordinator.Hostname, &idl.DiskSpaceEstimate{
+		Description: "coordinator pre-upgrade backup",
+		Destination: utils.GetCoordinatorPreUpgradeBackupDir(),
+		Sources:     []string{coordinator.DataDir},
+		Factor:      1,
+	})
+
+	add(coordinator.Hostname, &idl.DiskSpaceEstimate{
+		Description: "coordinator post-upgrade backup",
+		Destination: utils.GetCoordinatorPostUpgradeBackupDir(),
+		Sources:     []string{coordinator.DataDir},
+		Factor:      1,
+	})
+
+	for _, location := range coordinatorTablespaces {
+		add(coordinator.Hostname, &idl.DiskSpaceEstimate{
+			Description: "target coordinator tablespace",
+			Destination: location,
+			Sources:     []string{location},
+			Factor:      copyFactor,
+		})
+	}
+
+	add(coordinator.Hostname, &idl.DiskSpaceEstimate{
+		Description: "pg_upgrade working directory and logs",
+		Destination: logDir,
+		ExtraBytes:  pgUpgradeWorkingDirBytes + logGrowthBytes,
+	})
+
+	if source.HasStandby() {
+		standby := source.Standby()
+		add(standby.Hostname, &idl.DiskSpaceEstimate{
+			Description: "target standby data directory",
+			Destination: filepath.Dir(standby.DataDir),
+			ExtraBytes:  coordinatorBytes,
+		})
+	}
+
+	// Each segment host receives a copy of the upgraded coordinator data
+	// directory, and for 5X sources the coordinator tablespaces, which are
+	// used to seed the target primaries.
+	for _, host := range source.PrimaryHostnames() {
+		add(host, &idl.DiskSpaceEstimate{
+			Description: "coordinator post-upgrade backup",
+			Destination: utils.GetCoordinatorPostUpgradeBackupDir(),
+			ExtraBytes:  coordinatorBytes,
+		})
+
+		if len(coordinatorTablespaces) > 0 {
+			add(host, &idl.DiskSpaceEstimate{
+				Description: "coordinator tablespaces",
+				Destination: utils.GetTablespaceDir(),
+				ExtraBytes:  coordinatorTablespaceBytes,
+			})
+		}
+	}
+
+	for _, seg := range source.ExcludingCoordinatorOrStandby() {
+		tablespaces := source.Tablespaces[seg.DbID].UserDefinedTablespacesLocations()
+
+		if seg.IsPrimary() {
+			add(seg.Hostname, &idl.DiskSpaceEstimate{
+				Description: fmt.Sprintf("target primary data directory for content %d", seg.ContentID),
+				Destination: filepath.Dir(seg.DataDir),
+				Sources:     []string{seg.DataDir},
+				Factor:      copyFactor,
+				ExtraBytes:  coordinatorBytes,
+			})
+
+			add(seg.Hostname, &idl.DiskSpaceEstimate{
+				Description: fmt.Sprintf("pg_upgrade working directory for content %d", seg.ContentID),
+				Destination: logDir,
+				ExtraBytes:  pgUpgradeWorkingDirBytes,
+			})
+		}
+
+		// In copy and clone modes new mirrors are created alongside the
+		// source mirrors, while in link mode the source mirrors are removed
+		// first.
+		if seg.IsMirror() {
+			add(seg.Hostname, &idl.DiskSpaceEstimate{
+				Description: fmt.Sprintf("target mirror data directory for content %d", seg.ContentID),
+				Destination: filepath.Dir(seg.DataDir),
+				Sources:     []string{seg.DataDir},
+				Factor:      mirrorFactor,
+			})
+		}
+
+		for _, location := range tablespaces {
+			add(seg.Hostname, &idl.DiskSpaceEstimate{
+				Description: fmt.Sprintf("target tablespace for content %d", seg.ContentID),
+				Destination: location,
+				Sources:     []string{location},
+				Factor:      copyFactor,
+			})
+		}
+	}
+
+	for _, host := range AgentHosts(source) {
+		if host == coordinator.Hostname {
+			continue
+		}
+
+		add(host, &idl.DiskSpaceEstimate{
+			Description: "gpupgrade logs",
+			Destination: logDir,
+			ExtraBytes:  logGrowthBytes,
+		})
+	}
+
+	return estimates
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+)
+
+const MiB = 1024 * 1024
+
+func DirSizeIs(size uint64) func(string) (uint64, error) {
+	return func(string) (uint64, error) {
+		return size, nil
+	}
+}
+
+func CoordinatorHostEstimateReturnsUsage(expected disk.FileSystemDiskUsage) disk.EstimateUsageType {
+	return func(streams step.OutStreams, d disk.Disk, safetyMargin float64, estimates ...*idl.DiskSpaceEstimate) (disk.FileSystemDiskUsage, error) {
+		return expected, nil
+	}
+}
+
+func TestDiskSpaceEstimates(t *testing.T) {
+	resetEnv := testutils.SetEnv(t, "GPUPGRADE_HOME", "/home/gpadmin/.gpupgrade")
+	defer resetEnv()
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "smdw", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast/seg1", Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Role: greenplum.MirrorRole},
+	})
+
+	logDir := "/home/gpadmin/gpAdminLogs/gpupgrade"
+
+	t.Run("estimates copies of the segment data in copy mode", func(t *testing.T) {
+		estimates := hub.DiskSpaceEstimates(source, idl.Mode_copy, 10*MiB, 0, logDir)
+
+		expected := map[string][]*idl.DiskSpaceEstimate{
+			"mdw": {
+				{Description: "target coordinator data directory", Destination: "/data/qddir", Sources: []string{"/data/qddir/seg-1"}, Factor: 1},
+				{Description: "coordinator pre-upgrade backup", Destination: "/home/gpadmin/.gpupgrade/coordinator-pre-upgrade-backup", Sources: []string{"/data/qddir/seg-1"}, Factor: 1},
+				{Description: "coordinator post-upgrade backup", Destination: "/home/gpadmin/.gpupgrade/coordinator-post-upgrade-backup", Sources: []string{"/data/qddir/seg-1"}, Factor: 1},
+				{Description: "pg_upgrade working directory and logs", Destination: logDir, ExtraBytes: 320 * MiB},
+			},
+			"smdw": {
+				{Description: "target standby data directory", Destination: "/data", ExtraBytes: 10 * MiB},
+				{Description: "gpupgrade logs", Destination: logDir, ExtraBytes: 64 * MiB},
+			},
+			"sdw1": {
+				{Description: "coordinator post-upgrade backup", Destination: "/home/gpadmin/.gpupgrade/coordinator-post-upgrade-backup", ExtraBytes: 10 * MiB},
+				{Description: "target primary data directory for content 0", Destination: "/data/dbfast", Sources: []string{"/data/dbfast/seg1"}, Factor: 1, ExtraBytes: 10 * MiB},
+				{Description: "pg_upgrade working directory for content 0", Destination: logDir, ExtraBytes: 256 * MiB},
+				{Description: "gpupgrade logs", Destination: logDir, ExtraBytes: 64 * MiB},
+			},
+			"sdw2": {
+				{Description: "target mirror data directory for content 0", Destination: "/data/dbfast_mirror1", Sources: []string{"/data/dbfast_mirror1/seg1"}, Factor: 1},
+				{Description: "gpupgrade logs", Destination: logDir, ExtraBytes: 64 * MiB},
+			},
+		}
+
+		if !reflect.DeepEqual(estimates, expected) {
+			t.Errorf("got %v want %v", estimates, expected)
+		}
+	})
+
+	t.Run("only estimates the coordinator backup and catalog in link mode", func(t *testing.T) {
+		estimates := hub.DiskSpaceEstimates(source, idl.Mode_link, 10*MiB, 0, logDir)
+
+		expected := []*idl.DiskSpaceEstimate{
+			{Description: "coordinator post-upgrade backup", Destination: "/home/gpadmin/.gpupgrade/coordinator-post-upgrade-backup", ExtraBytes: 10 * MiB},
+			{Description: "target primary data directory for content 0", Destination: "/data/dbfast", Sources: []string{"/data/dbfast/seg1"}, ExtraBytes: 10 * MiB},
+			{Description: "pg_upgrade working directory for content 0", Destination: logDir, ExtraBytes: 256 * MiB},
+			{Description: "gpupgrade logs", Destination: logDir, ExtraBytes: 64 * MiB},
+		}
+
+		if !reflect.DeepEqual(estimates["sdw1"], expected) {
+			t.Errorf("got %v want %v", estimates["sdw1"], expected)
+		}
+
+		expected = []*idl.DiskSpaceEstimate{
+			{Description: "gpupgrade logs", Destination: logDir, ExtraBytes: 64 * MiB},
+		}
+
+		if !reflect.DeepEqual(estimates["sdw2"], expected) {
+			t.Errorf("got %v want %v", estimates["sdw2"], expected)
+		}
+	})
+
+	t.Run("estimates copies of the mirrors but not the primaries in clone mode", func(t *testing.T) {
+		estimates := hub.DiskSpaceEstimates(source, idl.Mode_clone, 10*MiB, 0, logDir)
+
+		expected := []*idl.DiskSpaceEstimate{
+			{Description: "coordinator post-upgrade backup", Destination: "/home/gpadmin/.gpupgrade/coordinator-post-upgrade-backup", ExtraBytes: 10 * MiB},
+			{Description: "target primary data directory for content 0", Destination: "/data/dbfast", Sources: []string{"/data/dbfast/seg1"}, ExtraBytes: 10 * MiB},
+			{Description: "pg_upgrade working directory for content 0", Destination: logDir, ExtraBytes: 256 * MiB},
+			{Description: "gpupgrade logs", Destination: logDir, ExtraBytes: 64 * MiB},
+		}
+
+		if !reflect.DeepEqual(estimates["sdw1"], expected) {
+			t.Errorf("got %v want %v", estimates["sdw1"], expected)
+		}
+
+		expected = []*idl.DiskSpaceEstimate{
+			{Description: "target mirror data directory for content 0", Destination: "/data/dbfast_mirror1", Sources: []string{"/data/dbfast_mirror1/seg1"}, Factor: 1},
+			{Description: "gpupgrade logs", Destination: logDir, ExtraBytes: 64 * MiB},
+		}
+
+		if !reflect.DeepEqual(estimates["sdw2"], expected) {
+			t.Errorf("got %v want %v", estimates["sdw2"], expected)
+		}
+	})
+
+	t.Run("estimates the coordinator tablespace copies for sources with tablespaces", func(t *testing.T) {
+		source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+			{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast/seg1", Role: greenplum.PrimaryRole},
+		})
+		source.Tablespaces = greenplum.Tablespaces{
+			1: {16384: {Location: "/tmp/user_ts/m/qddir/16384", UserDefined: 1}},
+			3: {16384: {Location: "/tmp/user_ts/p1/16384", UserDefined: 1}},
+		}
+
+		estimates := hub.DiskSpaceEstimates(source, idl.Mode_copy, 10*MiB, 2*MiB, logDir)
+
+		expected := []*idl.DiskSpaceEstimate{
+			{Description: "coordinator post-upgrade backup", Destination: "/home/gpadmin/.gpupgrade/coordinator-post-upgrade-backup", ExtraBytes: 10 * MiB},
+			{Description: "coordinator tablespaces", Destination: "/home/gpadmin/.gpupgrade/tablespaces", ExtraBytes: 2 * MiB},
+			{Description: "target primary data directory for content 0", Destination: "/data/dbfast", Sources: []string{"/data/dbfast/seg1"}, Factor: 1, ExtraBytes: 10 * MiB},
+			{Description: "pg_upgrade working directory for content 0", Destination: logDir, ExtraBytes: 256 * MiB},
+			{Description: "target tablespace for content 0", Destination: "/tmp/user_ts/p1/16384", Sources: []string{"/tmp/user_ts/p1/16384"}, Factor: 1},
+			{Description: "gpupgrade logs", Destination: logDir, ExtraBytes: 64 * MiB},
+		}
+
+		if !reflect.DeepEqual(estimates["sdw1"], expected) {
+			t.Errorf("got %v want %v", estimates["sdw1"], expected)
+		}
+	})
+}
+
+func TestEstimateDiskSpace(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast/seg1", Role: greenplum.PrimaryRole},
+	})
+
+	hub.SetDirSize(DirSizeIs(10 * MiB))
+	defer hub.ResetDirSize()
+
+	t.Run("succeeds when all hosts have enough space", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetEstimateDiskUsage(CoordinatorHostEstimateReturnsUsage(disk.FileSystemDiskUsage{
+			{Fs: "/", Host: "mdw", Available: 2048, Required: 1024},
+		}))
+		defer hub.ResetEstimateDiskUsage()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CheckDiskSpace(
+			gomock.Any(),
+			gomock.Any(),
+		).DoAndReturn(func(_ interface{}, req *idl.CheckSegmentDiskSpaceRequest, _ ...interface{}) (*idl.CheckDiskSpaceReply, error) {
+			if req.GetSafetyMargin() != hub.DiskSpaceSafetyMargin {
+				t.Errorf("got safety margin %v want %v", req.GetSafetyMargin(), hub.DiskSpaceSafetyMargin)
+			}
+
+			if len(req.GetEstimates()) == 0 {
+				t.Errorf("expected estimates for sdw1")
+			}
+
+			return &idl.CheckDiskSpaceReply{Estimated: disk.FileSystemDiskUsage{
+				{Fs: "/data", Host: "sdw1", Available: 2048, Required: 1024},
+			}}, nil
+		})
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.EstimateDiskSpace(context.Background(), step.DevNullStream, agentConns, source, idl.Mode_copy)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("returns a usage error for filesystems without enough space", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetEstimateDiskUsage(CoordinatorHostEstimateReturnsUsage(disk.FileSystemDiskUsage{
+			{Fs: "/", Host: "mdw", Available: 2048, Required: 1024},
+		}))
+		defer hub.ResetEstimateDiskUsage()
+
+		insufficient := &idl.CheckDiskSpaceReply_DiskUsage{Fs: "/data", Host: "sdw1", Available: 1024, Required: 2048}
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CheckDiskSpace(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.CheckDiskSpaceReply{
+			Usage:     disk.FileSystemDiskUsage{insufficient},
+			Estimated: disk.FileSystemDiskUsage{insufficient},
+		}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.EstimateDiskSpace(context.Background(), step.DevNullStream, agentConns, source, idl.Mode_copy)
+		expected := disk.NewSpaceUsageErrorFromUsage(*insufficient)
+		if !reflect.DeepEqual(err, expected) {
+			t.Errorf("returned %v want %v", err, expected)
+		}
+	})
+
+	t.Run("checks the coordinator estimates on its agent when one is running there", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetEstimateDiskUsage(func(streams step.OutStreams, d disk.Disk, safetyMargin float64, estimates ...*idl.DiskSpaceEstimate) (disk.FileSystemDiskUsage, error) {
+			t.Errorf("unexpected call to estimate disk usage on the coordinator")
+			return nil, nil
+		})
+		defer hub.ResetEstimateDiskUsage()
+
+		source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+			{DbID: 1, ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+			{DbID: 2, ContentID: 0, Hostname: "mdw", DataDir: "/data/dbfast/seg1", Role: greenplum.PrimaryRole},
+		})
+
+		mdw := mock_idl.NewMockAgentClient(ctrl)
+		mdw.EXPECT().CheckDiskSpace(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.CheckDiskSpaceReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: mdw, Hostname: "mdw"},
+		}
+
+		err := hub.EstimateDiskSpace(context.Background(), step.DevNullStream, agentConns, source, idl.Mode_link)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("errors when agents fail to check disk space", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetEstimateDiskUsage(CoordinatorHostEstimateReturnsUsage(nil))
+		defer hub.ResetEstimateDiskUsage()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CheckDiskSpace(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, expected)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.EstimateDiskSpace(context.Background(), step.DevNullStream, agentConns, source, idl.Mode_copy)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("errors when unable to determine the coordinator data directory size", func(t *testing.T) {
+		expected := errors.New("permission denied")
+		hub.SetDirSize(func(string) (uint64, error) {
+			return 0, expected
+		})
+		defer hub.SetDirSize(DirSizeIs(10 * MiB))
+
+		err := hub.EstimateDiskSpace(context.Background(), step.DevNullStream, nil, source, idl.Mode_copy)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
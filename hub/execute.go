@@ -4,6 +4,7 @@
 package hub
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
@@ -12,10 +13,27 @@ import (
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
 )
 
 func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteServer) (err error) {
-	st, err := step.Begin(idl.Step_EXECUTE, stream, s.AgentConns)
+	var onlyContents []int
+	for _, content := range req.GetOnlyContents() {
+		onlyContents = append(onlyContents, int(content))
+	}
+
+	if len(onlyContents) > 0 {
+		upgraded, err := step.HasCompleted(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+		if err != nil {
+			return err
+		}
+
+		if upgraded {
+			return errors.New("All primaries have already been upgraded. Specific contents can only be retried before the primaries have been upgraded.")
+		}
+	}
+
+	st, err := step.Begin(stream.Context(), idl.Step_EXECUTE, stream, s.AgentConns)
 	if err != nil {
 		return err
 	}
@@ -30,12 +48,36 @@ func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteSer
 		}
 	}()
 
+	useSnapshots := UseSnapshots(s.Mode, s.SnapshotProvider)
+	st.SetPointOfNoReturn(ExecuteIrreversibleSubsteps(s.Source, s.Mode, useSnapshots),
+		AcknowledgePointOfNoReturn(idl.Step_EXECUTE, s.UpgradeID, s.registeredBackup, req.GetPointOfNoReturnConfirmation()))
+
+	// The backup is taken while the source cluster is still running.
+	st.RunConditionally(idl.Substep_BACKUP_SOURCE_CLUSTER, UseBackup(s.BackupProvider), func(streams step.OutStreams) error {
+		return BackupSourceCluster(streams, s.Connection, s.Config, s.SaveConfig)
+	})
+
 	st.Run(idl.Substep_SHUTDOWN_SOURCE_CLUSTER, func(streams step.OutStreams) error {
 		return s.Source.Stop(streams)
 	})
 
+	st.RunConditionally(idl.Substep_SNAPSHOT_SOURCE_CLUSTER, useSnapshots, func(streams step.OutStreams) error {
+		return SnapshotSourceCluster(step.Context(streams), s.agentConns, s.Source, s.StateDir, s.SnapshotProvider, SnapshotName(s.UpgradeID))
+	})
+
+	segments, err := step.NewSegmentFileStore()
+	if err != nil {
+		return err
+	}
+
 	st.Run(idl.Substep_UPGRADE_MASTER, func(streams step.OutStreams) error {
-		return UpgradeCoordinator(streams, s.Source, s.Intermediate, idl.PgOptions_upgrade, s.LinkMode)
+		// The primaries are upgraded from a backup of the upgraded coordinator,
+		// so any that completed against a previous coordinator must be redone.
+		if err := segments.Reset(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES); err != nil {
+			return err
+		}
+
+		return UpgradeCoordinator(streams, s.Source, s.Intermediate, idl.PgOptions_upgrade, s.Mode)
 	})
 
 	st.Run(idl.Substep_COPY_MASTER, func(streams step.OutStreams) error {
@@ -47,14 +89,27 @@ func (s *Server) Execute(req *idl.ExecuteRequest, stream idl.CliToHub_ExecuteSer
 		return CopyCoordinatorTablespaces(streams, s.Source.Tablespaces, utils.GetTablespaceDir(), s.Intermediate.PrimaryHostnames())
 	})
 
+	migrating := len(MigratedPrimaries(s.Source, s.Intermediate)) > 0
+	st.RunConditionally(idl.Substep_TRANSFER_SOURCE_PRIMARIES, migrating, func(streams step.OutStreams) error {
+		return TransferSourcePrimaries(step.Context(streams), s.agentConns, s.Source, s.Intermediate)
+	})
+
 	st.Run(idl.Substep_UPGRADE_PRIMARIES, func(streams step.OutStreams) error {
-		return UpgradePrimaries(s.agentConns, s.Source, s.Intermediate, idl.PgOptions_upgrade, s.LinkMode)
+		if err := UpgradePrimaries(step.Context(streams), streams, s.agentConns, s.Source, s.Intermediate, idl.PgOptions_upgrade, s.Mode, segments, onlyContents); err != nil {
+			return err
+		}
+
+		return DeleteTransferredSourcePrimaries(step.Context(streams), s.agentConns, s.Source, s.Intermediate)
 	})
 
 	st.Run(idl.Substep_START_TARGET_CLUSTER, func(streams step.OutStreams) error {
 		return s.Intermediate.Start(streams)
 	})
 
+	st.Run(idl.Substep_INVENTORY_TARGET_CLUSTER, func(streams step.OutStreams) error {
+		return InventoryTargetCluster(streams, s.Connection, s.Intermediate, inventory.TargetAfterExecute)
+	})
+
 	message := &idl.Message{Contents: &idl.Message_Response{Response: &idl.Response{Contents: &idl.Response_ExecuteResponse{
 		ExecuteResponse: &idl.ExecuteResponse{
 			Target: &idl.Cluster{
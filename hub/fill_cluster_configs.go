@@ -6,6 +6,9 @@ package hub
 import (
 	"database/sql"
 	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
@@ -56,18 +59,56 @@ func FillConfiguration(config *Config, request *idl.InitializeRequest, conn *gre
 	config.Target.Destination = idl.ClusterDestination_TARGET
 	config.Target.GPHome = request.GetTargetGPHome()
 	config.Target.Version = conn.TargetVersion
-	config.LinkMode = request.GetLinkMode()
+	config.Mode = request.GetMode()
+	config.SnapshotProvider = request.GetSnapshotProvider()
+	config.MirrorResync = request.GetMirrorResync()
+	config.BackupProvider = request.GetBackupProvider()
+	config.BackupCommand = request.GetBackupCommand()
+	config.BackupTimestamp = request.GetBackupTimestamp()
 
 	var ports []int
 	for _, p := range request.GetPorts() {
 		ports = append(ports, int(p))
 	}
 
-	config.Intermediate, err = GenerateIntermediateCluster(config.Source, ports, config.UpgradeID, conn.TargetVersion, request.GetTargetGPHome())
+	var layout *upgrade.Layout
+	if request.GetTargetDatadirLayout() != "" {
+		layout, err = upgrade.ParseLayout(strings.NewReader(request.GetTargetDatadirLayout()))
+		if err != nil {
+			return xerrors.Errorf("parse target data directory layout: %w", err)
+		}
+	}
+
+	var hosts upgrade.HostMapping
+	if request.GetTargetHostMapping() != "" {
+		hosts, err = upgrade.ParseHostMapping(strings.NewReader(request.GetTargetHostMapping()))
+		if err != nil {
+			return xerrors.Errorf("parse target host mapping: %w", err)
+		}
+	}
+
+	var portMap *upgrade.PortMap
+	if request.GetTargetPortMap() != "" {
+		portMap, err = upgrade.ParsePortMap(strings.NewReader(request.GetTargetPortMap()))
+		if err != nil {
+			return xerrors.Errorf("parse target port map: %w", err)
+		}
+	}
+
+	placement := Placement{
+		Layout:       layout,
+		Hosts:        hosts,
+		PortStrategy: request.GetPortStrategy(),
+		PortMap:      portMap,
+	}
+
+	config.Intermediate, err = GenerateIntermediateCluster(config.Source, ports, config.UpgradeID, conn.TargetVersion, request.GetTargetGPHome(), placement)
 	if err != nil {
 		return err
 	}
 
+	placeTargetSegments(config.Target, config.Intermediate, request.GetKeepTempPorts())
+
 	if err := ensureTempPortRangeDoesNotOverlapWithSourceClusterPorts(config.Source, config.Intermediate); err != nil {
 		return err
 	}
@@ -81,6 +122,10 @@ func FillConfiguration(config *Config, request *idl.InitializeRequest, conn *gre
 		if err != nil {
 			return xerrors.Errorf("extract tablespace information: %w", err)
 		}
+
+		if len(MigratedPrimaries(config.Source, config.Intermediate)) > 0 && len(config.Source.Tablespaces.GetCoordinatorTablespaces().UserDefinedTablespacesLocations()) > 0 {
+			return errors.New("Upgrading onto new hosts is not supported with user defined tablespaces.")
+		}
 	}
 
 	if err := saveConfig(); err != nil {
@@ -90,8 +135,48 @@ func FillConfiguration(config *Config, request *idl.InitializeRequest, conn *gre
 	return nil
 }
 
-func GenerateIntermediateCluster(source *greenplum.Cluster, ports []int, upgradeID upgrade.ID, version semver.Version, gphome string) (*greenplum.Cluster, error) {
+// The strategies for assigning the intermediate ports.
+const (
+	SequentialPorts = "sequential"
+	OffsetPorts     = "offset"
+	MappedPorts     = "map"
+)
+
+var PortStrategies = []string{SequentialPorts, OffsetPorts, MappedPorts}
+
+// Placement chooses where GenerateIntermediateCluster places the intermediate
+// segments. The zero value places them on their source hosts next to the
+// source data directories with ports assigned sequentially from the temp port
+// range.
+type Placement struct {
+	Layout       *upgrade.Layout
+	Hosts        upgrade.HostMapping
+	PortStrategy string
+	PortMap      *upgrade.PortMap
+}
+
+// GenerateIntermediateCluster assigns the intermediate cluster its hosts,
+// ports, and data directories. Segments other than the coordinator are placed
+// on the hosts their source hosts map to. Data directories are placed by the
+// layout when given, and otherwise next to the source data directories.
+//
+// Ports are assigned by the port strategy. The sequential strategy assigns each
+// host the next unused ports of the range. The offset strategy shifts every
+// source port by the distance from the lowest source port to the start of the
+// range keeping the port offsets of each host. The map strategy looks up the
+// source host and port in the port map.
+func GenerateIntermediateCluster(source *greenplum.Cluster, ports []int, upgradeID upgrade.ID, version semver.Version, gphome string, placement Placement) (*greenplum.Cluster, error) {
 	ports = utils.Sanitize(ports)
+	layout, hosts := placement.Layout, placement.Hosts
+
+	if err := validateHostMapping(source, hosts); err != nil {
+		return &greenplum.Cluster{}, err
+	}
+
+	port, err := portAssigner(source, ports, placement)
+	if err != nil {
+		return &greenplum.Cluster{}, err
+	}
 
 	intermediate, err := greenplum.NewCluster([]greenplum.SegConfig{})
 	if err != nil {
@@ -101,40 +186,82 @@ func GenerateIntermediateCluster(source *greenplum.Cluster, ports []int, upgrade
 	var segPrefix string
 	nextPortIndex := 0
 
+	// nextPort reserves the next port of the range that is unused on every
+	// host.
+	nextPort := func() (int, error) {
+		if nextPortIndex > len(ports)-1 {
+			return 0, errors.New("not enough ports")
+		}
+
+		nextPortIndex++
+		return ports[nextPortIndex-1], nil
+	}
+
+	// nextHostPort reserves the next port of the range that is unused on the
+	// host. The first segment of a host takes the next port unused on every
+	// host.
+	portIndexByHost := make(map[string]int)
+	nextHostPort := func(host string) (int, error) {
+		portIndex, ok := portIndexByHost[host]
+		if !ok {
+			portIndex = nextPortIndex
+		}
+
+		if portIndex > len(ports)-1 {
+			return 0, errors.New("not enough ports")
+		}
+
+		portIndexByHost[host] = portIndex + 1
+		return ports[portIndex], nil
+	}
+
+	var layoutErr error
+	dataDir := func(segment greenplum.SegConfig) string {
+		if layout == nil {
+			return upgrade.TempDataDir(segment.DataDir, segPrefix, upgradeID)
+		}
+
+		dir, err := layout.DataDir(segment.DbID, segment.Hostname, segment.DataDir)
+		if err != nil {
+			layoutErr = errorlist.Append(layoutErr, err)
+		}
+
+		return dir
+	}
+
 	// XXX we can't handle a coordinatorless cluster elsewhere in the code; we may
 	// want to remove the "ok" check here and force NewCluster to error out
 	if coordinator, ok := source.Primaries[-1]; ok {
 		// Reserve a port for the coordinator.
-		if nextPortIndex > len(ports)-1 {
-			return &greenplum.Cluster{}, errors.New("not enough ports")
+		coordinatorPort, err := port(coordinator, nextPort)
+		if err != nil {
+			return &greenplum.Cluster{}, err
 		}
 
 		// Save the segment prefix for later.
-		var err error
 		segPrefix, err = GetCoordinatorSegPrefix(coordinator.DataDir)
 		if err != nil {
 			return &greenplum.Cluster{}, err
 		}
 
-		coordinator.Port = ports[nextPortIndex]
-		coordinator.DataDir = upgrade.TempDataDir(coordinator.DataDir, segPrefix, upgradeID)
+		coordinator.Port = coordinatorPort
+		coordinator.DataDir = dataDir(coordinator)
 		intermediate.Primaries[-1] = coordinator
-		nextPortIndex++
 	}
 
 	if standby, ok := source.Mirrors[-1]; ok {
 		// Reserve a port for the standby.
-		if nextPortIndex > len(ports)-1 {
-			return &greenplum.Cluster{}, errors.New("not enough ports")
+		standbyPort, err := port(standby, nextPort)
+		if err != nil {
+			return &greenplum.Cluster{}, err
 		}
-		standby.Port = ports[nextPortIndex]
-		standby.DataDir = upgrade.TempDataDir(standby.DataDir, segPrefix, upgradeID)
+
+		standby.Hostname = hosts.Host(standby.Hostname)
+		standby.Port = standbyPort
+		standby.DataDir = dataDir(standby)
 		intermediate.Mirrors[-1] = standby
-		nextPortIndex++
 	}
 
-	portIndexByHost := make(map[string]int)
-
 	var contents []int
 	for content := range source.Primaries {
 		contents = append(contents, content)
@@ -146,53 +273,48 @@ func GenerateIntermediateCluster(source *greenplum.Cluster, ports []int, upgrade
 
 	contents = utils.Sanitize(contents)
 
-	for _, content := range contents {
-		if content == -1 {
-			continue
-		}
-
-		segment := source.Primaries[content]
+	segments := func(sourceSegments greenplum.ContentToSegConfig, intermediateSegments greenplum.ContentToSegConfig) error {
+		for _, content := range contents {
+			if content == -1 {
+				continue
+			}
 
-		if portIndex, ok := portIndexByHost[segment.Hostname]; ok {
-			if portIndex > len(ports)-1 {
-				return &greenplum.Cluster{}, errors.New("not enough ports")
+			segment, ok := sourceSegments[content]
+			if !ok {
+				continue
 			}
-			segment.Port = ports[portIndex]
-			portIndexByHost[segment.Hostname]++
-		} else {
-			if nextPortIndex > len(ports)-1 {
-				return &greenplum.Cluster{}, errors.New("not enough ports")
+
+			host := hosts.Host(segment.Hostname)
+			segmentPort, err := port(segment, func() (int, error) { return nextHostPort(host) })
+			if err != nil {
+				return err
 			}
-			segment.Port = ports[nextPortIndex]
-			portIndexByHost[segment.Hostname] = nextPortIndex + 1
+
+			segment.Hostname = host
+			segment.Port = segmentPort
+			segment.DataDir = dataDir(segment)
+
+			intermediateSegments[content] = segment
 		}
-		segment.DataDir = upgrade.TempDataDir(segment.DataDir, segPrefix, upgradeID)
 
-		intermediate.Primaries[content] = segment
+		return nil
 	}
 
-	for _, content := range contents {
-		if content == -1 {
-			continue
-		}
+	if err := segments(source.Primaries, intermediate.Primaries); err != nil {
+		return &greenplum.Cluster{}, err
+	}
 
-		if segment, ok := source.Mirrors[content]; ok {
-			if portIndex, ok := portIndexByHost[segment.Hostname]; ok {
-				if portIndex > len(ports)-1 {
-					return &greenplum.Cluster{}, errors.New("not enough ports")
-				}
-				segment.Port = ports[portIndex]
-				portIndexByHost[segment.Hostname]++
-			} else {
-				if nextPortIndex > len(ports)-1 {
-					return &greenplum.Cluster{}, errors.New("not enough ports")
-				}
-				segment.Port = ports[nextPortIndex]
-				portIndexByHost[segment.Hostname] = nextPortIndex + 1
-			}
-			segment.DataDir = upgrade.TempDataDir(segment.DataDir, segPrefix, upgradeID)
+	if err := segments(source.Mirrors, intermediate.Mirrors); err != nil {
+		return &greenplum.Cluster{}, err
+	}
+
+	if layoutErr != nil {
+		return &greenplum.Cluster{}, xerrors.Errorf("target data directory layout: %w", layoutErr)
+	}
 
-			intermediate.Mirrors[content] = segment
+	if layout != nil || len(hosts) > 0 {
+		if err := validateLayout(source, &intermediate); err != nil {
+			return &greenplum.Cluster{}, xerrors.Errorf("target data directories: %w", err)
 		}
 	}
 
@@ -203,6 +325,186 @@ func GenerateIntermediateCluster(source *greenplum.Cluster, ports []int, upgrade
 	return &intermediate, nil
 }
 
+// portAssigner returns a function assigning the intermediate port of a source
+// segment by the port strategy. The sequential strategy calls next to reserve
+// the next port of the range.
+func portAssigner(source *greenplum.Cluster, ports []int, placement Placement) (func(segment greenplum.SegConfig, next func() (int, error)) (int, error), error) {
+	switch placement.PortStrategy {
+	case "", SequentialPorts:
+		return func(_ greenplum.SegConfig, next func() (int, error)) (int, error) {
+			return next()
+		}, nil
+
+	case OffsetPorts:
+		if len(ports) == 0 {
+			return nil, errors.New("not enough ports")
+		}
+
+		lowest := 0
+		for _, seg := range source.SelectSegments(func(*greenplum.SegConfig) bool { return true }) {
+			if lowest == 0 || seg.Port < lowest {
+				lowest = seg.Port
+			}
+		}
+
+		inRange := make(map[int]bool)
+		for _, p := range ports {
+			inRange[p] = true
+		}
+
+		offset := ports[0] - lowest
+		return func(segment greenplum.SegConfig, _ func() (int, error)) (int, error) {
+			port := segment.Port + offset
+			if !inRange[port] {
+				return 0, xerrors.Errorf("port %d of dbid %d offset from source port %d is not in temp_port_range", port, segment.DbID, segment.Port)
+			}
+
+			return port, nil
+		}, nil
+
+	case MappedPorts:
+		if placement.PortMap == nil {
+			return nil, xerrors.Errorf("the %s port strategy requires a port map", MappedPorts)
+		}
+
+		return func(segment greenplum.SegConfig, _ func() (int, error)) (int, error) {
+			return placement.PortMap.Port(segment.Hostname, segment.Port)
+		}, nil
+
+	default:
+		return nil, xerrors.Errorf("unknown port strategy %q", placement.PortStrategy)
+	}
+}
+
+// validateHostMapping ensures that segments are only moved onto new hosts, so
+// that the upgrade does not touch the source segments on the hosts it maps.
+func validateHostMapping(source *greenplum.Cluster, hosts upgrade.HostMapping) error {
+	sourceHosts := make(map[string]bool)
+	for _, seg := range source.SelectSegments(func(*greenplum.SegConfig) bool { return true }) {
+		sourceHosts[seg.Hostname] = true
+	}
+
+	var sourceNames []string
+	for host := range hosts {
+		sourceNames = append(sourceNames, host)
+	}
+	sort.Strings(sourceNames)
+
+	var err error
+	for _, host := range sourceNames {
+		target := hosts[host]
+		if !sourceHosts[host] {
+			err = errorlist.Append(err, xerrors.Errorf("host mapping source host %q is not a host of the source cluster", host))
+		}
+
+		if target != host && sourceHosts[target] {
+			err = errorlist.Append(err, xerrors.Errorf("host mapping target host %q of %q is a host of the source cluster", target, host))
+		}
+	}
+
+	if err != nil {
+		return xerrors.Errorf("target host mapping: %w", err)
+	}
+
+	return nil
+}
+
+// validateLayout ensures the intermediate data directories placed by a layout
+// or on new hosts can be created by gpinitsystem without clobbering each other
+// or the source.
+func validateLayout(source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+	if _, ok := intermediate.Primaries[-1]; ok {
+		if _, err := GetCoordinatorSegPrefix(intermediate.CoordinatorDataDir()); err != nil {
+			return err
+		}
+	}
+
+	type hostDir struct {
+		host string
+		dir  string
+	}
+
+	all := func(*greenplum.SegConfig) bool { return true }
+
+	sourceDirs := make(map[hostDir]bool)
+	for _, seg := range source.SelectSegments(all) {
+		sourceDirs[hostDir{seg.Hostname, filepath.Clean(seg.DataDir)}] = true
+	}
+
+	segments := intermediate.SelectSegments(all)
+	sort.Sort(segments)
+
+	var err error
+	used := make(map[hostDir]int)
+	for _, seg := range segments {
+		key := hostDir{seg.Hostname, filepath.Clean(seg.DataDir)}
+		if sourceDirs[key] {
+			err = errorlist.Append(err, xerrors.Errorf("dbid %d data directory %q on host %q is a source data directory", seg.DbID, seg.DataDir, seg.Hostname))
+		}
+
+		if dbid, ok := used[key]; ok {
+			err = errorlist.Append(err, xerrors.Errorf("dbids %d and %d have the same data directory %q on host %q", dbid, seg.DbID, seg.DataDir, seg.Hostname))
+		}
+
+		used[key] = seg.DbID
+	}
+
+	return err
+}
+
+// placeTargetSegments moves the target segments onto the hosts of the
+// intermediate segments. It also points the target at the intermediate data
+// directories that are not renamed over the source data directories during
+// finalize since a layout placed them elsewhere. When keepTempPorts is set the
+// target keeps the intermediate ports rather than taking over the source ports
+// so that it does not conflict with the source cluster.
+func placeTargetSegments(target *greenplum.Cluster, intermediate *greenplum.Cluster, keepTempPorts bool) {
+	// The target is created from the source, so copy its segments before
+	// changing them.
+	target.Primaries = place(target.Primaries, intermediate.Primaries, keepTempPorts)
+	target.Mirrors = place(target.Mirrors, intermediate.Mirrors, keepTempPorts)
+}
+
+func place(segments greenplum.ContentToSegConfig, intermediate greenplum.ContentToSegConfig, keepTempPorts bool) greenplum.ContentToSegConfig {
+	placed := make(greenplum.ContentToSegConfig, len(segments))
+	for content, seg := range segments {
+		if i, ok := intermediate[content]; ok {
+			seg.Hostname = i.Hostname
+			if keepTempPorts {
+				seg.Port = i.Port
+			}
+			if upgrade.IsRelocated(seg.DataDir, i.DataDir) {
+				seg.DataDir = i.DataDir
+			}
+		}
+
+		placed[content] = seg
+	}
+
+	return placed
+}
+
+// MigratedPrimaries returns the intermediate primaries upgraded on a different
+// host than their source primaries. Their source data directories are
+// transferred to the new hosts for pg_upgrade.
+func MigratedPrimaries(source *greenplum.Cluster, intermediate *greenplum.Cluster) greenplum.SegConfigs {
+	return intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool {
+		return seg.IsPrimary() && IsMigrated(source, seg)
+	})
+}
+
+// IsMigrated reports whether the intermediate segment is on a different host
+// than the source segment of the same content and role.
+func IsMigrated(source *greenplum.Cluster, intermediate *greenplum.SegConfig) bool {
+	segments := source.Primaries
+	if intermediate.Role == greenplum.MirrorRole {
+		segments = source.Mirrors
+	}
+
+	seg, ok := segments[intermediate.ContentID]
+	return ok && seg.Hostname != intermediate.Hostname
+}
+
 func ensureTempPortRangeDoesNotOverlapWithSourceClusterPorts(source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	type HostPort struct {
 		Host string
@@ -230,6 +532,27 @@ func ensureTempPortRangeDoesNotOverlapWithSourceClusterPorts(source *greenplum.C
 		}
 	}
 
+	// A port map can assign two segments of a host the same port. Whether
+	// the ports are in use on each host is checked through the agents by the
+	// preflight checks once they are started.
+	segments := intermediate.SelectSegments(func(*greenplum.SegConfig) bool { return true })
+	sort.Sort(segments)
+
+	var err error
+	used := make(map[HostPort]int)
+	for _, seg := range segments {
+		key := HostPort{Host: seg.Hostname, Port: seg.Port}
+		if dbid, ok := used[key]; ok {
+			err = errorlist.Append(err, xerrors.Errorf("dbids %d and %d have the same port %d on host %s", dbid, seg.DbID, seg.Port, seg.Hostname))
+		}
+
+		used[key] = seg.DbID
+	}
+
+	if err != nil {
+		return xerrors.Errorf("target ports: %w", err)
+	}
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ package hub
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/blang/semver/v4"
@@ -153,7 +154,7 @@ func TestAssignDataDirsAndPorts(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			actual, err := GenerateIntermediateCluster(c.cluster, c.ports, upgradeID, semver.Version{}, "")
+			actual, err := GenerateIntermediateCluster(c.cluster, c.ports, upgradeID, semver.Version{}, "", Placement{})
 			if err != nil {
 				t.Errorf("returned error %+v", err)
 			}
@@ -231,7 +232,7 @@ func TestAssignDataDirsAndPorts(t *testing.T) {
 
 	for _, c := range errCases {
 		t.Run(c.name, func(t *testing.T) {
-			_, err := GenerateIntermediateCluster(c.cluster, c.ports, 0, semver.Version{}, "")
+			_, err := GenerateIntermediateCluster(c.cluster, c.ports, 0, semver.Version{}, "", Placement{})
 			if err == nil {
 				t.Errorf("GenerateIntermediateCluster(<cluster>, %v) returned nil, want error", c.ports)
 			}
@@ -239,6 +240,271 @@ func TestAssignDataDirsAndPorts(t *testing.T) {
 	}
 }
 
+func TestGenerateIntermediateClusterWithLayout(t *testing.T) {
+	source := MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		{ContentID: 1, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast2/seg1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 4, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg0", Role: greenplum.MirrorRole},
+		{ContentID: 1, DbID: 5, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg1", Role: greenplum.MirrorRole},
+	})
+
+	mustParseLayout := func(t *testing.T, layout string) *upgrade.Layout {
+		t.Helper()
+
+		parsed, err := upgrade.ParseLayout(strings.NewReader(layout))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		return parsed
+	}
+
+	t.Run("places the data directories by the layout", func(t *testing.T) {
+		layout := mustParseLayout(t, `
+dbid 1 /data/qddir/seg.upgrade.-1
+prefix * /data /mnt/upgrade
+prefix sdw2 /data/dbfast_mirror1 /mnt/sdw2/mirror
+`)
+
+		actual, err := GenerateIntermediateCluster(source, []int{1, 2, 3}, 0, semver.Version{}, "", Placement{Layout: layout})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg.upgrade.-1", Role: greenplum.PrimaryRole, Port: 1},
+			{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/mnt/upgrade/dbfast1/seg0", Role: greenplum.PrimaryRole, Port: 2},
+			{ContentID: 1, DbID: 3, Hostname: "sdw2", DataDir: "/mnt/upgrade/dbfast2/seg1", Role: greenplum.PrimaryRole, Port: 2},
+			{ContentID: 0, DbID: 4, Hostname: "sdw2", DataDir: "/mnt/sdw2/mirror/seg0", Role: greenplum.MirrorRole, Port: 3},
+			{ContentID: 1, DbID: 5, Hostname: "sdw1", DataDir: "/mnt/upgrade/dbfast_mirror2/seg1", Role: greenplum.MirrorRole, Port: 3},
+		})
+		expected.Destination = idl.ClusterDestination_INTERMEDIATE
+
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("got %v want %v", actual, expected)
+		}
+
+		target := *source
+		placeTargetSegments(&target, actual, false)
+
+		if target.CoordinatorDataDir() != "/data/qddir/seg-1" {
+			t.Errorf("got coordinator data directory %q want the renamed sibling %q", target.CoordinatorDataDir(), "/data/qddir/seg-1")
+		}
+
+		if target.Mirrors[0].DataDir != "/mnt/sdw2/mirror/seg0" {
+			t.Errorf("got mirror data directory %q want the relocated %q", target.Mirrors[0].DataDir, "/mnt/sdw2/mirror/seg0")
+		}
+
+		if source.Mirrors[0].DataDir != "/data/dbfast_mirror1/seg0" {
+			t.Errorf("relocating the target changed the source data directory to %q", source.Mirrors[0].DataDir)
+		}
+	})
+
+	errCases := []struct {
+		name     string
+		layout   string
+		expected string
+	}{{
+		name:     "errors when the layout does not cover every dbid",
+		layout:   "prefix sdw1 /data /mnt\ndbid 1 /mnt/qddir/seg-1\n",
+		expected: "does not cover dbid 3",
+	}, {
+		name:     "errors when the layout places segments in the same directory",
+		layout:   "prefix * /data /mnt\ndbid 5 /mnt/dbfast1/seg0\n",
+		expected: "dbids 2 and 5 have the same data directory",
+	}, {
+		name:     "errors when the layout places a segment in a source data directory",
+		layout:   "prefix * /data /mnt\ndbid 2 /data/dbfast_mirror2/seg1\n",
+		expected: "is a source data directory",
+	}, {
+		name:     "errors when the coordinator data directory does not end in -1",
+		layout:   "prefix * /data /mnt\ndbid 1 /mnt/coordinator\n",
+		expected: "requires a master content identifier",
+	}}
+
+	for _, c := range errCases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := GenerateIntermediateCluster(source, []int{1, 2, 3}, 0, semver.Version{}, "", Placement{Layout: mustParseLayout(t, c.layout)})
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("got error %+v want %q", err, c.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateIntermediateClusterOntoNewHosts(t *testing.T) {
+	var upgradeID upgrade.ID
+
+	source := MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: -1, DbID: 2, Hostname: "smdw", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		{ContentID: 0, DbID: 3, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		{ContentID: 1, DbID: 4, Hostname: "sdw2", DataDir: "/data/dbfast2/seg1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 5, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg0", Role: greenplum.MirrorRole},
+		{ContentID: 1, DbID: 6, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg1", Role: greenplum.MirrorRole},
+	})
+
+	t.Run("places the segments on the mapped hosts", func(t *testing.T) {
+		hosts := upgrade.HostMapping{"smdw": "new-smdw", "sdw1": "new1", "sdw2": "new2"}
+
+		actual, err := GenerateIntermediateCluster(source, []int{1, 2, 3, 4}, upgradeID, semver.Version{}, "", Placement{Hosts: hosts})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: upgrade.TempDataDir("/data/qddir/seg-1", "seg", upgradeID), Role: greenplum.PrimaryRole, Port: 1},
+			{ContentID: -1, DbID: 2, Hostname: "new-smdw", DataDir: upgrade.TempDataDir("/data/standby", "seg", upgradeID), Role: greenplum.MirrorRole, Port: 2},
+			{ContentID: 0, DbID: 3, Hostname: "new1", DataDir: upgrade.TempDataDir("/data/dbfast1/seg0", "seg", upgradeID), Role: greenplum.PrimaryRole, Port: 3},
+			{ContentID: 1, DbID: 4, Hostname: "new2", DataDir: upgrade.TempDataDir("/data/dbfast2/seg1", "seg", upgradeID), Role: greenplum.PrimaryRole, Port: 3},
+			{ContentID: 0, DbID: 5, Hostname: "new2", DataDir: upgrade.TempDataDir("/data/dbfast_mirror1/seg0", "seg", upgradeID), Role: greenplum.MirrorRole, Port: 4},
+			{ContentID: 1, DbID: 6, Hostname: "new1", DataDir: upgrade.TempDataDir("/data/dbfast_mirror2/seg1", "seg", upgradeID), Role: greenplum.MirrorRole, Port: 4},
+		})
+		expected.Destination = idl.ClusterDestination_INTERMEDIATE
+
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("got %v want %v", actual, expected)
+		}
+
+		target := *source
+		placeTargetSegments(&target, actual, false)
+
+		if target.Primaries[0].Hostname != "new1" || target.Primaries[0].DataDir != "/data/dbfast1/seg0" {
+			t.Errorf("got target primary %+v want the source data directory on the new host", target.Primaries[0])
+		}
+
+		if target.CoordinatorHostname() != "mdw" || source.Primaries[0].Hostname != "sdw1" {
+			t.Errorf("got coordinator host %q and source primary host %q want them unchanged", target.CoordinatorHostname(), source.Primaries[0].Hostname)
+		}
+	})
+
+	errCases := []struct {
+		name     string
+		hosts    upgrade.HostMapping
+		expected string
+	}{{
+		name:     "errors when a source host is not in the cluster",
+		hosts:    upgrade.HostMapping{"sdw3": "new3"},
+		expected: `source host "sdw3" is not a host of the source cluster`,
+	}, {
+		name:     "errors when a target host is in the source cluster",
+		hosts:    upgrade.HostMapping{"sdw1": "sdw2", "sdw2": "new2"},
+		expected: `target host "sdw2" of "sdw1" is a host of the source cluster`,
+	}}
+
+	for _, c := range errCases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := GenerateIntermediateCluster(source, []int{1, 2, 3, 4}, upgradeID, semver.Version{}, "", Placement{Hosts: c.hosts})
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("got error %+v want %q", err, c.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateIntermediateClusterPortStrategies(t *testing.T) {
+	var upgradeID upgrade.ID
+
+	source := MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole, Port: 15432},
+		{ContentID: -1, DbID: 2, Hostname: "smdw", DataDir: "/data/standby", Role: greenplum.MirrorRole, Port: 16432},
+		{ContentID: 0, DbID: 3, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole, Port: 25432},
+		{ContentID: 1, DbID: 4, Hostname: "sdw1", DataDir: "/data/dbfast2/seg1", Role: greenplum.PrimaryRole, Port: 25433},
+		{ContentID: 0, DbID: 5, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg0", Role: greenplum.MirrorRole, Port: 25435},
+		{ContentID: 1, DbID: 6, Hostname: "sdw2", DataDir: "/data/dbfast_mirror2/seg1", Role: greenplum.MirrorRole, Port: 25436},
+	})
+
+	ports := func(cluster *greenplum.Cluster) map[int]int {
+		byDbID := make(map[int]int)
+		for _, seg := range cluster.SelectSegments(func(*greenplum.SegConfig) bool { return true }) {
+			byDbID[seg.DbID] = seg.Port
+		}
+
+		return byDbID
+	}
+
+	t.Run("offset keeps the port offsets of the source", func(t *testing.T) {
+		var portRange []int
+		for port := 50000; port <= 60010; port++ {
+			portRange = append(portRange, port)
+		}
+
+		actual, err := GenerateIntermediateCluster(source, portRange, upgradeID, semver.Version{}, "", Placement{PortStrategy: OffsetPorts})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := map[int]int{1: 50000, 2: 51000, 3: 60000, 4: 60001, 5: 60003, 6: 60004}
+		if !reflect.DeepEqual(ports(actual), expected) {
+			t.Errorf("got ports %v want %v", ports(actual), expected)
+		}
+	})
+
+	t.Run("offset errors when a port is outside the range", func(t *testing.T) {
+		_, err := GenerateIntermediateCluster(source, []int{50000, 50001, 50002}, upgradeID, semver.Version{}, "", Placement{PortStrategy: OffsetPorts})
+		expected := "port 51000 of dbid 2 offset from source port 16432 is not in temp_port_range"
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %+v want %q", err, expected)
+		}
+	})
+
+	t.Run("map assigns the ports of the port map", func(t *testing.T) {
+		portMap, err := upgrade.ParsePortMap(strings.NewReader(`
+mdw  15432 7000
+smdw 16432 7000
+*    25432 7001
+*    25433 7002
+sdw2 25435 7003
+sdw2 25436 7004
+`))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		actual, err := GenerateIntermediateCluster(source, nil, upgradeID, semver.Version{}, "", Placement{PortStrategy: MappedPorts, PortMap: portMap})
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := map[int]int{1: 7000, 2: 7000, 3: 7001, 4: 7002, 5: 7003, 6: 7004}
+		if !reflect.DeepEqual(ports(actual), expected) {
+			t.Errorf("got ports %v want %v", ports(actual), expected)
+		}
+
+		target := *source
+		placeTargetSegments(&target, actual, true)
+		if !reflect.DeepEqual(ports(&target), expected) {
+			t.Errorf("got target ports %v want the intermediate ports %v", ports(&target), expected)
+		}
+
+		if source.CoordinatorPort() != 15432 {
+			t.Errorf("got source coordinator port %d want it unchanged", source.CoordinatorPort())
+		}
+	})
+
+	t.Run("map errors when the port map does not cover a segment", func(t *testing.T) {
+		portMap, err := upgrade.ParsePortMap(strings.NewReader("* 15432 7000\n"))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		_, err = GenerateIntermediateCluster(source, nil, upgradeID, semver.Version{}, "", Placement{PortStrategy: MappedPorts, PortMap: portMap})
+		expected := `port map does not cover source port 16432 on host "smdw"`
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %+v want %q", err, expected)
+		}
+	})
+
+	t.Run("errors on an unknown port strategy", func(t *testing.T) {
+		_, err := GenerateIntermediateCluster(source, []int{1, 2, 3}, upgradeID, semver.Version{}, "", Placement{PortStrategy: "random"})
+		expected := `unknown port strategy "random"`
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %+v want %q", err, expected)
+		}
+	})
+}
+
 func TestEnsureTempPortRangeDoesNotOverlapWithSourceClusterPorts(t *testing.T) {
 	source := MustCreateCluster(t, greenplum.SegConfigs{
 		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole, Port: 15432},
@@ -270,6 +536,20 @@ func TestEnsureTempPortRangeDoesNotOverlapWithSourceClusterPorts(t *testing.T) {
 		}
 	})
 
+	t.Run("errors when segments on a host have the same port", func(t *testing.T) {
+		intermediate := MustCreateCluster(t, greenplum.SegConfigs{
+			{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole, Port: 6000},
+			{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole, Port: 6002},
+			{ContentID: 0, DbID: 5, Hostname: "sdw1", DataDir: "/data/dbfast_mirror1/seg0", Role: greenplum.MirrorRole, Port: 6002},
+		})
+
+		err := ensureTempPortRangeDoesNotOverlapWithSourceClusterPorts(source, intermediate)
+		expected := "dbids 2 and 5 have the same port 6002 on host sdw1"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %+v want %q", err, expected)
+		}
+	})
+
 	errCases := []struct {
 		name            string
 		source          *greenplum.Cluster
@@ -13,10 +13,11 @@ import (
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
 )
 
 func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_FinalizeServer) (err error) {
-	st, err := step.Begin(idl.Step_FINALIZE, stream, s.AgentConns)
+	st, err := step.Begin(stream.Context(), idl.Step_FINALIZE, stream, s.AgentConns)
 	if err != nil {
 		return err
 	}
@@ -31,11 +32,34 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 		}
 	}()
 
-	st.RunConditionally(idl.Substep_UPGRADE_MIRRORS, s.Source.HasMirrors() && s.LinkMode, func(streams step.OutStreams) error {
-		return UpgradeMirrorsUsingRsync(s.Connection, s.agentConns, s.Source, s.Intermediate, s.UseHbaHostnames)
+	undo, err := LoadFinalizeUndo(s.StateDir)
+	if err != nil {
+		return err
+	}
+
+	restoreFromSnapshots, err := step.HasCompleted(idl.Step_EXECUTE, idl.Substep_SNAPSHOT_SOURCE_CLUSTER)
+	if err != nil {
+		return err
+	}
+
+	// Revert can roll back the finalize substeps that run before the point of
+	// no return, after which the source cluster can no longer be restored.
+	pointOfNoReturn := FinalizePointOfNoReturn(s.Source, s.Mode, s.MirrorResync, restoreFromSnapshots)
+	passPointOfNoReturn := func(streams step.OutStreams) error {
+		_, err := fmt.Fprintf(streams.Stdout(), "Revert is no longer possible once %s starts.\n", pointOfNoReturn)
+		return err
+	}
+
+	st.SetPointOfNoReturn(FinalizeIrreversibleSubsteps,
+		AcknowledgePointOfNoReturn(idl.Step_FINALIZE, s.UpgradeID, s.registeredBackup, req.GetPointOfNoReturnConfirmation()))
+
+	st.RunConditionally(idl.Substep_POINT_OF_NO_RETURN, pointOfNoReturn == idl.Substep_UPGRADE_MIRRORS, passPointOfNoReturn)
+
+	st.RunConditionally(idl.Substep_UPGRADE_MIRRORS, s.Source.HasMirrors() && s.Mode == idl.Mode_link, func(streams step.OutStreams) error {
+		return UpgradeMirrorsUsingRsync(step.Context(streams), streams, s.Connection, s.agentConns, s.Source, s.Intermediate, s.UseHbaHostnames, s.MirrorResync)
 	})
 
-	st.RunConditionally(idl.Substep_UPGRADE_MIRRORS, s.Source.HasMirrors() && !s.LinkMode, func(streams step.OutStreams) error {
+	st.RunConditionally(idl.Substep_UPGRADE_MIRRORS, s.Source.HasMirrors() && s.Mode != idl.Mode_link, func(streams step.OutStreams) error {
 		return UpgradeMirrorsUsingGpAddMirrors(streams, s.Intermediate, s.UseHbaHostnames)
 	})
 
@@ -63,18 +87,28 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 		return s.Intermediate.StopCoordinatorOnly(streams)
 	})
 
-	st.Run(idl.Substep_UPDATE_DATA_DIRECTORIES, func(_ step.OutStreams) error {
-		return RenameDataDirectories(s.agentConns, s.Source, s.Intermediate)
+	st.Run(idl.Substep_UPDATE_DATA_DIRECTORIES, func(streams step.OutStreams) error {
+		if err := undo.RecordRenames(s.Source, s.Intermediate); err != nil {
+			return err
+		}
+
+		return RenameDataDirectories(step.Context(streams), s.agentConns, s.Source, s.Intermediate)
 	})
 
 	st.Run(idl.Substep_UPDATE_TARGET_CONF_FILES, func(streams step.OutStreams) error {
-		return UpdateConfFiles(s.agentConns, streams,
+		if err := undo.RecordConfFiles(s.Target.Version, s.Intermediate, s.Target); err != nil {
+			return err
+		}
+
+		return UpdateConfFiles(step.Context(streams), s.agentConns, streams,
 			s.Target.Version,
 			s.Intermediate,
 			s.Target,
 		)
 	})
 
+	st.RunConditionally(idl.Substep_POINT_OF_NO_RETURN, pointOfNoReturn == idl.Substep_START_TARGET_CLUSTER, passPointOfNoReturn)
+
 	st.Run(idl.Substep_START_TARGET_CLUSTER, func(streams step.OutStreams) error {
 		return s.Target.Start(streams)
 	})
@@ -83,30 +117,46 @@ func (s *Server) Finalize(req *idl.FinalizeRequest, stream idl.CliToHub_Finalize
 		return s.Target.WaitForClusterToBeReady(s.Connection)
 	})
 
+	st.Run(idl.Substep_INVENTORY_TARGET_CLUSTER, func(streams step.OutStreams) error {
+		return InventoryTargetCluster(streams, s.Connection, s.Target, inventory.TargetAfterFinalize)
+	})
+
 	st.Run(idl.Substep_STOP_TARGET_CLUSTER, func(streams step.OutStreams) error {
 		return s.Target.Stop(streams)
 	})
 
+	// The source cluster can no longer be reverted to, so release the space
+	// held by its snapshots.
+	snapshotsTaken, err := step.HasRun(idl.Step_EXECUTE, idl.Substep_SNAPSHOT_SOURCE_CLUSTER)
+	if err != nil {
+		return err
+	}
+
+	st.RunConditionally(idl.Substep_DELETE_SOURCE_SNAPSHOTS, snapshotsTaken, func(streams step.OutStreams) error {
+		return DeleteSourceSnapshots(step.Context(streams), s.agentConns, s.Source, s.StateDir)
+	})
+
 	var logArchiveDir string
-	st.Run(idl.Substep_ARCHIVE_LOG_DIRECTORIES, func(_ step.OutStreams) error {
+	st.Run(idl.Substep_ARCHIVE_LOG_DIRECTORIES, func(streams step.OutStreams) error {
 		logArchiveDir, err = s.GetLogArchiveDir()
 		if err != nil {
 			return xerrors.Errorf("get log archive directory: %w", err)
 		}
 
-		return ArchiveLogDirectories(logArchiveDir, s.agentConns, s.Config.Target.CoordinatorHostname())
+		return ArchiveLogDirectories(step.Context(streams), logArchiveDir, s.agentConns, s.Config.Target.CoordinatorHostname())
 	})
 
-	st.Run(idl.Substep_DELETE_SEGMENT_STATEDIRS, func(_ step.OutStreams) error {
-		return DeleteStateDirectories(s.agentConns, s.Source.CoordinatorHostname())
+	st.Run(idl.Substep_DELETE_SEGMENT_STATEDIRS, func(streams step.OutStreams) error {
+		return DeleteStateDirectories(step.Context(streams), s.agentConns, s.Source.CoordinatorHostname())
 	})
 
 	message := &idl.Message{Contents: &idl.Message_Response{Response: &idl.Response{Contents: &idl.Response_FinalizeResponse{
 		FinalizeResponse: &idl.FinalizeResponse{
 			TargetVersion:                          s.Target.Version.String(),
 			LogArchiveDirectory:                    logArchiveDir,
-			ArchivedSourceCoordinatorDataDirectory: s.Config.Intermediate.CoordinatorDataDir() + upgrade.OldSuffix,
+			ArchivedSourceCoordinatorDataDirectory: upgrade.ArchiveDirectory(s.Config.Source.CoordinatorDataDir(), s.Config.Intermediate.CoordinatorDataDir()),
 			UpgradeID:                              s.Config.UpgradeID.String(),
+			Backup:                                 BackupMessage(s.Backup),
 			TargetCluster: &idl.Cluster{
 				GPHome:                   s.Target.GPHome,
 				Port:                     int32(s.Target.CoordinatorPort()),
@@ -0,0 +1,165 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+const FinalizeUndoFileName = "finalize_undo.json"
+
+var UndoRenameDirectories = upgrade.UndoRenameDirectories
+
+// FinalizeUndo records the changes made by the finalize substeps so that
+// revert can roll them back until finalize passes its point of no return.
+type FinalizeUndo struct {
+	path string
+
+	CoordinatorRename *idl.RenameDirectories
+	SegmentRenames    RenameMap
+
+	// CoordinatorConfFiles and SegmentConfFiles undo the configuration file
+	// updates. The segment updates are keyed by host.
+	CoordinatorConfFiles []*idl.UpdateFileConfOptions
+	SegmentConfFiles     map[string][]*idl.UpdateFileConfOptions
+}
+
+// LoadFinalizeUndo reads the undo log from the state directory. An empty log
+// is returned if finalize has not yet recorded anything.
+func LoadFinalizeUndo(stateDir string) (*FinalizeUndo, error) {
+	undo := &FinalizeUndo{path: filepath.Join(stateDir, FinalizeUndoFileName)}
+
+	data, err := ioutil.ReadFile(undo.path)
+	if os.IsNotExist(err) {
+		return undo, nil
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("read finalize undo log: %w", err)
+	}
+
+	if err := json.Unmarshal(data, undo); err != nil {
+		return nil, xerrors.Errorf("parse finalize undo log %q: %w", undo.path, err)
+	}
+
+	return undo, nil
+}
+
+func (u *FinalizeUndo) Save() error {
+	data, err := json.MarshalIndent(u, "", "  ") // pretty print JSON
+	if err != nil {
+		return err
+	}
+
+	return utils.AtomicallyWrite(u.path, data)
+}
+
+// RecordRenames records the data directories renamed by RenameDataDirectories.
+func (u *FinalizeUndo) RecordRenames(source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+	u.CoordinatorRename = &idl.RenameDirectories{
+		Source: source.CoordinatorDataDir(),
+		Target: intermediate.CoordinatorDataDir(),
+	}
+	u.SegmentRenames = getRenameMap(source, intermediate)
+
+	return u.Save()
+}
+
+// RecordConfFiles records the updates undoing UpdateConfFiles.
+func (u *FinalizeUndo) RecordConfFiles(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	u.CoordinatorConfFiles = undos(coordinatorConfEdits(version, intermediate, target))
+
+	u.SegmentConfFiles = make(map[string][]*idl.UpdateFileConfOptions)
+	for _, host := range AgentHosts(target) {
+		edits := append(postgresqlConfEdits(host, intermediate, target), recoveryConfEdits(host, version, intermediate, target)...)
+		if len(edits) > 0 {
+			u.SegmentConfFiles[host] = undos(edits)
+		}
+	}
+
+	return u.Save()
+}
+
+// RestoreConfFiles undoes the configuration file updates so that the target
+// cluster again uses the intermediate ports.
+func RestoreConfFiles(ctx context.Context, agentConns []*idl.Connection, undo *FinalizeUndo) error {
+	if err := UpdateConfigurationFile(undo.CoordinatorConfFiles); err != nil {
+		return err
+	}
+
+	request := func(conn *idl.Connection) error {
+		opts := undo.SegmentConfFiles[conn.Hostname]
+		if len(opts) == 0 {
+			return nil
+		}
+
+		_, err := conn.AgentClient.UpdateConfiguration(ctx, &idl.UpdateConfigurationRequest{Options: opts})
+		return err
+	}
+
+	return ExecuteRPC(agentConns, request)
+}
+
+// RestoreDataDirectories moves the target data directories back to their
+// intermediate locations and restores the archived source data directories.
+func RestoreDataDirectories(ctx context.Context, agentConns []*idl.Connection, undo *FinalizeUndo) error {
+	if rename := undo.CoordinatorRename; rename != nil {
+		if err := UndoRenameDirectories(rename.GetSource(), rename.GetTarget()); err != nil {
+			return xerrors.Errorf("restoring master data directories: %w", err)
+		}
+	}
+
+	request := func(conn *idl.Connection) error {
+		dirs := undo.SegmentRenames[conn.Hostname]
+		if len(dirs) == 0 {
+			return nil
+		}
+
+		_, err := conn.AgentClient.UndoRenameDirectories(ctx, &idl.RenameDirectoriesRequest{Dirs: dirs})
+		return err
+	}
+
+	if err := ExecuteRPC(agentConns, request); err != nil {
+		return xerrors.Errorf("restoring segment data directories: %w", err)
+	}
+
+	return nil
+}
+
+// FinalizePointOfNoReturn returns the finalize substep after which the source
+// cluster can no longer be restored. Starting the target cluster lets it accept
+// writes. Before then, a link mode revert without snapshots restores the
+// primaries from the source mirrors, which upgrading the mirrors overwrites
+// when copying user defined tablespaces or hard linking an incremental resync.
+func FinalizePointOfNoReturn(source *greenplum.Cluster, mode idl.Mode, mirrorResync string, restoreFromSnapshots bool) idl.Substep {
+	if mode == idl.Mode_link && !restoreFromSnapshots && source.HasMirrors() &&
+		(mirrorResync == IncrementalResync || hasUserDefinedTablespaces(source)) {
+		return idl.Substep_UPGRADE_MIRRORS
+	}
+
+	return idl.Substep_START_TARGET_CLUSTER
+}
+
+func hasUserDefinedTablespaces(cluster *greenplum.Cluster) bool {
+	for _, tablespaces := range cluster.Tablespaces {
+		for _, tablespace := range tablespaces {
+			if tablespace.IsUserDefined() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+func TestFinalizeUndo(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25433, Role: greenplum.MirrorRole},
+	})
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1_123ABC", Port: 50432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1_123ABC", Port: 50434, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1_123ABC", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25433, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("returns an empty undo log when none has been recorded", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		undo, err := hub.LoadFinalizeUndo(stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if undo.CoordinatorRename != nil || len(undo.SegmentRenames) != 0 {
+			t.Errorf("got %+v want an empty undo log", undo)
+		}
+	})
+
+	t.Run("records the renames and configuration file updates", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		undo, err := hub.LoadFinalizeUndo(stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if err := undo.RecordRenames(source, intermediate); err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if err := undo.RecordConfFiles(semver.MustParse("7.0.0"), intermediate, target); err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		undo, err = hub.LoadFinalizeUndo(stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expectedRename := &idl.RenameDirectories{Source: "/data/qddir/seg-1", Target: "/data/qddir/seg-1_123ABC"}
+		if undo.CoordinatorRename.GetSource() != expectedRename.GetSource() || undo.CoordinatorRename.GetTarget() != expectedRename.GetTarget() {
+			t.Errorf("got coordinator rename %v want %v", undo.CoordinatorRename, expectedRename)
+		}
+
+		renames := undo.SegmentRenames["sdw2"]
+		if len(renames) != 1 || renames[0].GetSource() != "/data/dbfast_mirror1/seg1" || renames[0].GetTarget() != "/data/dbfast_mirror1/seg1_123ABC" {
+			t.Errorf("got sdw2 renames %v", renames)
+		}
+
+		coordinatorConf := undo.CoordinatorConfFiles
+		if len(coordinatorConf) != 1 || coordinatorConf[0].GetPath() != "/data/qddir/seg-1/postgresql.conf" ||
+			coordinatorConf[0].GetPattern() != `(^port[ \t]*=[ \t]*)15432([^0-9]|$)` || coordinatorConf[0].GetReplacement() != `\150432\2` {
+			t.Errorf("got coordinator configuration undo %v", coordinatorConf)
+		}
+
+		var paths []string
+		for _, opt := range undo.SegmentConfFiles["sdw2"] {
+			paths = append(paths, opt.GetPath())
+		}
+
+		expectedPaths := []string{"/data/dbfast_mirror1/seg1/postgresql.conf", "/data/dbfast_mirror1/seg1/postgresql.auto.conf"}
+		if !reflect.DeepEqual(paths, expectedPaths) {
+			t.Errorf("got sdw2 configuration undo paths %q want %q", paths, expectedPaths)
+		}
+	})
+}
+
+func TestRecordRenamesOntoNewHosts(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25433, Role: greenplum.MirrorRole},
+	})
+
+	// The primary moves to a sibling directory on a new host, and the mirror
+	// moves to another mount point on a new host.
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1_123ABC", Port: 50432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "new1", DataDir: "/data/dbfast1/seg1_123ABC", Port: 50434, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Hostname: "new2", DataDir: "/mnt/dbfast_mirror1/seg1", Port: 50435, Role: greenplum.MirrorRole},
+	})
+
+	undo, err := hub.LoadFinalizeUndo(stateDir)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if err := undo.RecordRenames(source, intermediate); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expected := hub.RenameMap{
+		"new1": {{Source: "/data/dbfast1/seg1", Target: "/data/dbfast1/seg1_123ABC"}},
+	}
+
+	if len(undo.SegmentRenames) != len(expected) {
+		t.Fatalf("got renames %v want %v", undo.SegmentRenames, expected)
+	}
+
+	for host, renames := range expected {
+		actual := undo.SegmentRenames[host]
+		if len(actual) != 1 || actual[0].GetSource() != renames[0].GetSource() || actual[0].GetTarget() != renames[0].GetTarget() {
+			t.Errorf("got %s renames %v want %v", host, actual, renames)
+		}
+	}
+}
+
+func TestRestoreConfFiles(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: dir + "_123ABC", Port: 50432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1_123ABC", Port: 50434, Role: greenplum.PrimaryRole},
+	})
+
+	target := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: dir, Port: 15432, Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25432, Role: greenplum.PrimaryRole},
+	})
+
+	path := filepath.Join(dir, "postgresql.conf")
+	testutils.MustWriteToFile(t, path, "port=50432\n")
+
+	undo, err := hub.LoadFinalizeUndo(dir)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if err := undo.RecordConfFiles(semver.MustParse("7.0.0"), intermediate, target); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	err = hub.UpdateConfigurationFile([]*idl.UpdateFileConfOptions{{Path: path, Pattern: `(^port[ \t]*=[ \t]*)50432([^0-9]|$)`, Replacement: `\115432\2`}})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	t.Run("restores the configuration files on the coordinator and segments", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(
+			gomock.Any(),
+			&idl.UpdateConfigurationRequest{Options: []*idl.UpdateFileConfOptions{{
+				Path:        "/data/dbfast1/seg1/postgresql.conf",
+				Pattern:     `(^port[ \t]*=[ \t]*)25432([^0-9]|$)`,
+				Replacement: `\150434\2`,
+			}}},
+		).Return(&idl.UpdateConfigurationReply{}, nil)
+
+		err := hub.RestoreConfFiles(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, undo)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		if contents != "port=50432\n" {
+			t.Errorf("got %q want the intermediate port", contents)
+		}
+	})
+
+	t.Run("returns errors from the segments", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpdateConfiguration(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		err := hub.RestoreConfFiles(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, undo)
+		if !errors.Is(err, expected) {
+			t.Errorf("got %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestRestoreDataDirectories(t *testing.T) {
+	undo := &hub.FinalizeUndo{
+		CoordinatorRename: &idl.RenameDirectories{Source: "/data/qddir/seg-1", Target: "/data/qddir/seg-1_123ABC"},
+		SegmentRenames: hub.RenameMap{
+			"sdw1": {{Source: "/data/dbfast1/seg1", Target: "/data/dbfast1/seg1_123ABC"}},
+		},
+	}
+
+	t.Run("restores the coordinator and segment data directories", func(t *testing.T) {
+		var restored []string
+		hub.UndoRenameDirectories = func(source, target string) error {
+			restored = append(restored, source, target)
+			return nil
+		}
+		defer func() {
+			hub.UndoRenameDirectories = upgrade.UndoRenameDirectories
+		}()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UndoRenameDirectories(
+			gomock.Any(),
+			&idl.RenameDirectoriesRequest{Dirs: undo.SegmentRenames["sdw1"]},
+		).Return(&idl.RenameDirectoriesReply{}, nil)
+
+		// sdw2 has no data directories to restore
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.RestoreDataDirectories(context.Background(), agentConns, undo)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := []string{"/data/qddir/seg-1", "/data/qddir/seg-1_123ABC"}
+		if !reflect.DeepEqual(restored, expected) {
+			t.Errorf("got %q want %q", restored, expected)
+		}
+	})
+
+	t.Run("returns error when restoring the coordinator fails", func(t *testing.T) {
+		expected := errors.New("permission denied")
+		hub.UndoRenameDirectories = func(source, target string) error {
+			return expected
+		}
+		defer func() {
+			hub.UndoRenameDirectories = upgrade.UndoRenameDirectories
+		}()
+
+		err := hub.RestoreDataDirectories(context.Background(), nil, undo)
+		if !errors.Is(err, expected) {
+			t.Errorf("got %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("returns error when restoring the segments fails", func(t *testing.T) {
+		hub.UndoRenameDirectories = func(source, target string) error {
+			return nil
+		}
+		defer func() {
+			hub.UndoRenameDirectories = upgrade.UndoRenameDirectories
+		}()
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UndoRenameDirectories(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		err := hub.RestoreDataDirectories(context.Background(), []*idl.Connection{{AgentClient: sdw1, Hostname: "sdw1"}}, undo)
+		if !errors.Is(err, expected) {
+			t.Errorf("got %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestFinalizePointOfNoReturn(t *testing.T) {
+	withMirrors := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Role: greenplum.MirrorRole},
+	})
+
+	withTablespaces := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Role: greenplum.MirrorRole},
+	})
+	withTablespaces.Tablespaces = greenplum.Tablespaces{
+		2: {16386: {Location: "/tmp/user_ts/p1/16386", UserDefined: 1}},
+	}
+
+	cases := []struct {
+		name                 string
+		source               *greenplum.Cluster
+		mode                 idl.Mode
+		mirrorResync         string
+		restoreFromSnapshots bool
+		expected             idl.Substep
+	}{
+		{
+			name:     "starting the target cluster in copy mode",
+			source:   withTablespaces,
+			mode:     idl.Mode_copy,
+			expected: idl.Substep_START_TARGET_CLUSTER,
+		},
+		{
+			name:     "starting the target cluster in link mode without tablespaces",
+			source:   withMirrors,
+			mode:     idl.Mode_link,
+			expected: idl.Substep_START_TARGET_CLUSTER,
+		},
+		{
+			name:     "upgrading the mirrors in link mode with tablespaces",
+			source:   withTablespaces,
+			mode:     idl.Mode_link,
+			expected: idl.Substep_UPGRADE_MIRRORS,
+		},
+		{
+			name:         "upgrading the mirrors in link mode with an incremental resync",
+			source:       withMirrors,
+			mode:         idl.Mode_link,
+			mirrorResync: hub.IncrementalResync,
+			expected:     idl.Substep_UPGRADE_MIRRORS,
+		},
+		{
+			name:                 "starting the target cluster in link mode when restoring from snapshots",
+			source:               withTablespaces,
+			mode:                 idl.Mode_link,
+			mirrorResync:         hub.IncrementalResync,
+			restoreFromSnapshots: true,
+			expected:             idl.Substep_START_TARGET_CLUSTER,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := hub.FinalizePointOfNoReturn(c.source, c.mode, c.mirrorResync, c.restoreFromSnapshots)
+			if actual != c.expected {
+				t.Errorf("got %s want %s", actual, c.expected)
+			}
+		})
+	}
+}
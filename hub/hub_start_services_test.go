@@ -25,6 +25,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 func gpupgrade_agent() {
@@ -68,7 +69,7 @@ func TestRestartAgent(t *testing.T) {
 			return listener.Dial()
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir, trace.Config{}, "text")
 		if err != nil {
 			t.Errorf("returned %#v", err)
 		}
@@ -88,7 +89,7 @@ func TestRestartAgent(t *testing.T) {
 			return listener.Dial()
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir, trace.Config{}, "text")
 		if err != nil {
 			t.Errorf("returned %#v", err)
 		}
@@ -111,7 +112,7 @@ func TestRestartAgent(t *testing.T) {
 			return nil, immediateFailure{}
 		}
 
-		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		restartedHosts, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir, trace.Config{}, "text")
 		if err == nil {
 			t.Errorf("expected restart agents to fail")
 		}
@@ -162,7 +163,62 @@ func TestRestartAgent(t *testing.T) {
 			return listener.Dial()
 		}
 
-		_, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir)
+		_, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir, trace.Config{}, "text")
+		if err != nil {
+			t.Errorf("unexpected errr %#v", err)
+		}
+	})
+
+	t.Run("starts agents that export their spans as configured", func(t *testing.T) {
+		host := "host1"
+
+		execCmd := exectest.NewCommandWithVerifier(gpupgrade_agent, func(name string, args ...string) {
+			cmd := fmt.Sprintf("bash -c \"%s/gpupgrade agent --daemonize --port %d --state-directory %s --trace-exporter otlp --trace-endpoint http://collector:4318/\\?a=1\\&b=2\"", testutils.MustGetExecutablePath(t), port, stateDir)
+			expected := []string{host, cmd}
+			if !reflect.DeepEqual(args, expected) {
+				t.Errorf("got %q want %q", args, expected)
+			}
+		})
+		hub.SetExecCommand(execCmd)
+		defer hub.ResetExecCommand()
+
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			if strings.HasPrefix(address, host) { // fail connection attempts to host
+				return nil, immediateFailure{}
+			}
+
+			return listener.Dial()
+		}
+
+		tracing := trace.Config{Exporter: trace.OTLP, Endpoint: "http://collector:4318/?a=1&b=2"}
+		_, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir, tracing, "text")
+		if err != nil {
+			t.Errorf("unexpected errr %#v", err)
+		}
+	})
+
+	t.Run("starts agents that log as JSON", func(t *testing.T) {
+		host := "host1"
+
+		execCmd := exectest.NewCommandWithVerifier(gpupgrade_agent, func(name string, args ...string) {
+			cmd := fmt.Sprintf("bash -c \"%s/gpupgrade agent --daemonize --port %d --state-directory %s --trace-exporter file --log-format json\"", testutils.MustGetExecutablePath(t), port, stateDir)
+			expected := []string{host, cmd}
+			if !reflect.DeepEqual(args, expected) {
+				t.Errorf("got %q want %q", args, expected)
+			}
+		})
+		hub.SetExecCommand(execCmd)
+		defer hub.ResetExecCommand()
+
+		dialer := func(ctx context.Context, address string) (net.Conn, error) {
+			if strings.HasPrefix(address, host) { // fail connection attempts to host
+				return nil, immediateFailure{}
+			}
+
+			return listener.Dial()
+		}
+
+		_, err := hub.RestartAgents(ctx, dialer, hostnames, port, stateDir, trace.Config{Exporter: trace.File}, "json")
 		if err != nil {
 			t.Errorf("unexpected errr %#v", err)
 		}
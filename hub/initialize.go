@@ -9,6 +9,7 @@ import (
 
 	"github.com/blang/semver/v4"
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
@@ -16,10 +17,11 @@ import (
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/validation"
 )
 
 func (s *Server) Initialize(req *idl.InitializeRequest, stream idl.CliToHub_InitializeServer) (err error) {
-	st, err := step.Begin(idl.Step_INITIALIZE, stream, s.AgentConns)
+	st, err := step.Begin(stream.Context(), idl.Step_INITIALIZE, stream, s.AgentConns)
 	if err != nil {
 		return err
 	}
@@ -57,23 +59,55 @@ func (s *Server) Initialize(req *idl.InitializeRequest, stream idl.CliToHub_Init
 
 	// Since the agents might not be up if gpupgrade is not properly installed, check it early on using ssh.
 	st.RunInternalSubstep(func() error {
-		return upgrade.EnsureGpupgradeVersionsMatch(AgentHosts(s.Source))
+		return upgrade.EnsureGpupgradeVersionsMatch(s.AgentHosts())
 	})
 
 	st.Run(idl.Substep_START_AGENTS, func(_ step.OutStreams) error {
-		_, err := RestartAgents(context.Background(), nil, AgentHosts(s.Source), s.AgentPort, s.StateDir)
+		_, err := RestartAgents(context.Background(), nil, s.AgentHosts(), s.AgentPort, s.StateDir, s.Tracing, s.LogFormat)
 		return err
 	})
 
-	st.RunConditionally(idl.Substep_CHECK_DISK_SPACE, req.GetDiskFreeRatio() > 0, func(streams step.OutStreams) error {
-		return CheckDiskSpace(streams, s.agentConns, req.GetDiskFreeRatio(), s.Source, s.Source.Tablespaces)
+	st.Run(idl.Substep_PREFLIGHT_CHECKS, func(streams step.OutStreams) error {
+		version, err := upgrade.LocalVersion()
+		if err != nil {
+			return xerrors.Errorf("hub version: %w", err)
+		}
+
+		locales, err := s.SourceLocales()
+		if err != nil {
+			return err
+		}
+
+		requests := PreflightRequests(s.Intermediate, version, locales)
+		if UseSnapshots(s.Mode, s.SnapshotProvider) {
+			AddSnapshotChecks(requests, s.SnapshotProvider, SnapshotDirectories(s.Source))
+		}
+
+		return Preflight(step.Context(streams), streams, s.agentConns, s.Intermediate.CoordinatorHostname(), requests)
+	})
+
+	st.RunConditionally(idl.Substep_CHECK_DISK_SPACE, req.GetEstimateDiskSpace() || req.GetDiskFreeRatio() > 0, func(streams step.OutStreams) error {
+		if req.GetEstimateDiskSpace() {
+			return EstimateDiskSpace(step.Context(streams), streams, s.agentConns, s.Source, s.Mode)
+		}
+
+		return CheckDiskSpace(step.Context(streams), streams, s.agentConns, req.GetDiskFreeRatio(), s.Source, s.Source.Tablespaces)
+	})
+
+	st.Run(idl.Substep_INVENTORY_SOURCE_CLUSTER, func(_ step.OutStreams) error {
+		return InventorySourceCluster(s.Connection, s.Source)
+	})
+
+	dataValidation := req.GetDataValidation() != "" && req.GetDataValidation() != validation.None
+	st.RunConditionally(idl.Substep_SNAPSHOT_SOURCE_DATA, dataValidation, func(_ step.OutStreams) error {
+		return SnapshotSourceData(s.Connection, s.Source, req.GetDataValidation())
 	})
 
 	return st.Err()
 }
 
 func (s *Server) InitializeCreateCluster(req *idl.InitializeCreateClusterRequest, stream idl.CliToHub_InitializeCreateClusterServer) (err error) {
-	st, err := step.Begin(idl.Step_INITIALIZE, stream, s.AgentConns)
+	st, err := step.Begin(stream.Context(), idl.Step_INITIALIZE, stream, s.AgentConns)
 	if err != nil {
 		return err
 	}
@@ -93,7 +127,7 @@ func (s *Server) InitializeCreateCluster(req *idl.InitializeCreateClusterRequest
 	})
 
 	st.Run(idl.Substep_INIT_TARGET_CLUSTER, func(stream step.OutStreams) error {
-		err := s.RemoveIntermediateCluster(stream)
+		err := s.RemoveIntermediateCluster(step.Context(stream), stream)
 		if err != nil {
 			return err
 		}
@@ -136,11 +170,11 @@ func (s *Server) InitializeCreateCluster(req *idl.InitializeCreateClusterRequest
 	})
 
 	st.AlwaysRun(idl.Substep_CHECK_UPGRADE, func(stream step.OutStreams) error {
-		if err := UpgradeCoordinator(stream, s.Source, s.Intermediate, idl.PgOptions_check, s.LinkMode); err != nil {
+		if err := UpgradeCoordinator(stream, s.Source, s.Intermediate, idl.PgOptions_check, s.Mode); err != nil {
 			return err
 		}
 
-		return UpgradePrimaries(s.agentConns, s.Source, s.Intermediate, idl.PgOptions_check, s.LinkMode)
+		return UpgradePrimaries(step.Context(stream), stream, s.agentConns, s.Source, s.Intermediate, idl.PgOptions_check, s.Mode, nil, nil)
 	})
 
 	message := &idl.Message{Contents: &idl.Message_Response{Response: &idl.Response{Contents: &idl.Response_InitializeResponse{
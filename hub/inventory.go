@@ -0,0 +1,154 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
+)
+
+// InventorySourceCluster saves an inventory of the objects in the source
+// cluster to compare against the target cluster after execute and finalize.
+func InventorySourceCluster(conn *greenplum.Conn, source *greenplum.Cluster) error {
+	snapshot, err := TakeInventory(conn, source, idl.ClusterDestination_SOURCE, inventory.Source)
+	if err != nil {
+		return err
+	}
+
+	dir, err := utils.GetInventoryDir()
+	if err != nil {
+		return err
+	}
+
+	return inventory.Save(dir, snapshot)
+}
+
+// InventoryTargetCluster saves an inventory of the objects in the target
+// cluster and reports how it differs from the source inventory.
+func InventoryTargetCluster(streams step.OutStreams, conn *greenplum.Conn, target *greenplum.Cluster, name string) error {
+	snapshot, err := TakeInventory(conn, target, idl.ClusterDestination_TARGET, name)
+	if err != nil {
+		return err
+	}
+
+	dir, err := utils.GetInventoryDir()
+	if err != nil {
+		return err
+	}
+
+	if err := inventory.Save(dir, snapshot); err != nil {
+		return err
+	}
+
+	return CompareInventory(streams, dir, snapshot)
+}
+
+// CompareInventory writes a report comparing the target snapshot with the
+// source snapshot to the inventory directory and the output streams.
+// Differences are reported but do not fail the upgrade.
+func CompareInventory(streams step.OutStreams, dir string, target inventory.Snapshot) error {
+	source, err := inventory.Load(inventory.Path(dir, inventory.Source))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			gplog.Warn("skipping inventory comparison since the source inventory was not taken")
+			return nil
+		}
+
+		return err
+	}
+
+	report := inventory.Report(source, target)
+	path := filepath.Join(dir, target.Name+"-report.txt")
+	if err := utils.AtomicallyWrite(path, []byte(report)); err != nil {
+		return err
+	}
+
+	fmt.Fprint(streams.Stdout(), report)
+
+	if diffs := inventory.Diff(source, target); len(diffs) > 0 {
+		gplog.Warn("%d object counts differ between the source and %s inventories. See %s.", len(diffs), target.Name, path)
+	}
+
+	return nil
+}
+
+// TakeInventory counts the objects in every database of the running cluster.
+func TakeInventory(conn *greenplum.Conn, cluster *greenplum.Cluster, destination idl.ClusterDestination, name string) (inventory.Snapshot, error) {
+	snapshot := inventory.Snapshot{
+		Name:      name,
+		Version:   cluster.Version.String(),
+		Taken:     time.Now(),
+		Databases: make(map[string]inventory.Counts),
+	}
+
+	var databases []string
+	err := withDatabase(conn, cluster, destination, "", func(db *sql.DB) error {
+		var err error
+		databases, err = inventory.QueryDatabases(db)
+		if err != nil {
+			return err
+		}
+
+		snapshot.Roles, err = inventory.QueryRoles(db)
+		return err
+	})
+	if err != nil {
+		return inventory.Snapshot{}, err
+	}
+
+	for _, database := range databases {
+		err := withDatabase(conn, cluster, destination, database, func(db *sql.DB) error {
+			counts, err := inventory.QueryCounts(db, cluster.Version)
+			if err != nil {
+				return xerrors.Errorf("database %q: %w", database, err)
+			}
+
+			snapshot.Databases[database] = counts
+			return nil
+		})
+		if err != nil {
+			return inventory.Snapshot{}, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+func withDatabase(conn *greenplum.Conn, cluster *greenplum.Cluster, destination idl.ClusterDestination, database string, f func(*sql.DB) error) (err error) {
+	options := []greenplum.Option{
+		greenplum.ToSource(),
+		greenplum.Port(cluster.CoordinatorPort()),
+		greenplum.Database(database),
+	}
+
+	if destination == idl.ClusterDestination_TARGET {
+		options[0] = greenplum.ToTarget()
+	}
+
+	db, err := sql.Open("pgx", conn.URI(options...))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	return f(db)
+}
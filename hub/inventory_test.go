@@ -0,0 +1,79 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
+)
+
+func TestCompareInventory(t *testing.T) {
+	testlog.SetupLogger()
+
+	source := inventory.Snapshot{
+		Name:      inventory.Source,
+		Version:   "7.0.0",
+		Roles:     3,
+		Databases: map[string]inventory.Counts{"postgres": {HeapTables: 2}},
+	}
+
+	target := inventory.Snapshot{
+		Name:      inventory.TargetAfterExecute,
+		Version:   "7.1.0",
+		Roles:     3,
+		Databases: map[string]inventory.Counts{"postgres": {HeapTables: 1}},
+	}
+
+	t.Run("writes and prints the report comparing the target with the source", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		if err := inventory.Save(dir, source); err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		streams := new(step.BufferedStreams)
+		err := hub.CompareInventory(streams, dir, target)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := inventory.Report(source, target)
+		if streams.StdoutBuf.String() != expected {
+			t.Errorf("got stdout %q want %q", streams.StdoutBuf.String(), expected)
+		}
+
+		report := testutils.MustReadFile(t, filepath.Join(dir, "target-after-execute-report.txt"))
+		if report != expected {
+			t.Errorf("got report %q want %q", report, expected)
+		}
+	})
+
+	t.Run("skips the comparison when the source inventory does not exist", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		streams := new(step.BufferedStreams)
+		err := hub.CompareInventory(streams, dir, target)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if streams.StdoutBuf.Len() != 0 {
+			t.Errorf("unexpected stdout %q", streams.StdoutBuf.String())
+		}
+
+		_, err = os.Stat(filepath.Join(dir, "target-after-execute-report.txt"))
+		if !os.IsNotExist(err) {
+			t.Errorf("expected report to not exist, got error %#v", err)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+)
+
+// LogFields are the fields of the JSON log entries of the hub outside of
+// steps.
+func (c *Config) LogFields() log.Fields {
+	if c.UpgradeID == 0 {
+		return log.Fields{}
+	}
+
+	return log.Fields{UpgradeID: c.UpgradeID.String()}
+}
+
+// logObserver adds the upgrade ID, step, and substep to the context of each
+// step and substep. They are recorded in the JSON log entries of the substep
+// and sent with its agent requests.
+type logObserver struct {
+	config *Config
+}
+
+func (o logObserver) StepContext(ctx context.Context, s idl.Step) context.Context {
+	fields := o.config.LogFields()
+	fields.Step = s.String()
+	return log.WithFields(ctx, fields)
+}
+
+func (o logObserver) SubstepContext(ctx context.Context, _ idl.Step, substep idl.Substep) context.Context {
+	fields := log.ContextFields(ctx)
+	fields.Substep = substep.String()
+	return log.WithFields(ctx, fields)
+}
+
+func (o logObserver) StepStarted(idl.Step) {}
+
+func (o logObserver) SubstepStarted(idl.Step, idl.Substep) {}
+
+func (o logObserver) SubstepFinished(idl.Step, idl.Substep, idl.Status, time.Duration, error) {}
+
+func (o logObserver) PointOfNoReturn(idl.Step, idl.Substep, error) {}
+
+func (o logObserver) StepFinished(idl.Step, error) {}
@@ -0,0 +1,46 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+)
+
+func TestLogObserver(t *testing.T) {
+	id := upgrade.NewID()
+	observer := logObserver{config: &Config{UpgradeID: id}}
+
+	ctx := observer.StepContext(context.Background(), idl.Step_EXECUTE)
+	expected := log.Fields{UpgradeID: id.String(), Step: "EXECUTE"}
+	if fields := log.ContextFields(ctx); fields != expected {
+		t.Errorf("got fields %+v want %+v", fields, expected)
+	}
+
+	substepCtx := observer.SubstepContext(ctx, idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+	expected = log.Fields{UpgradeID: id.String(), Step: "EXECUTE", Substep: "UPGRADE_PRIMARIES"}
+	if fields := log.ContextFields(substepCtx); fields != expected {
+		t.Errorf("got fields %+v want %+v", fields, expected)
+	}
+
+	// The fields of the step are unchanged by its substeps.
+	expected = log.Fields{UpgradeID: id.String(), Step: "EXECUTE"}
+	if fields := log.ContextFields(ctx); fields != expected {
+		t.Errorf("got fields %+v want %+v", fields, expected)
+	}
+
+	t.Run("omits the upgrade ID before initialize generates it", func(t *testing.T) {
+		observer := logObserver{config: &Config{}}
+		ctx := observer.StepContext(context.Background(), idl.Step_INITIALIZE)
+
+		expected := log.Fields{Step: "INITIALIZE"}
+		if fields := log.ContextFields(ctx); fields != expected {
+			t.Errorf("got fields %+v want %+v", fields, expected)
+		}
+	})
+}
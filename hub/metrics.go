@@ -0,0 +1,135 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/metrics"
+)
+
+// Metrics are served by the hub on /metrics when a metrics port is configured.
+var Metrics = metrics.NewRegistry()
+
+var (
+	stepRunning          = Metrics.NewGauge("gpupgrade_step_running", "Whether the step is running.", "step")
+	substepRunning       = Metrics.NewGauge("gpupgrade_substep_running", "Whether the substep is running.", "step", "substep")
+	substepDuration      = Metrics.NewGauge("gpupgrade_substep_duration_seconds", "How long the substep took the last time it ran.", "step", "substep")
+	substepsFinished     = Metrics.NewCounter("gpupgrade_substeps_total", "The number of substeps that finished by status.", "step", "status")
+	agentConnectionState = Metrics.NewGauge("gpupgrade_agent_connection_state", "Whether the connection to the agent on the host is in the state.", "host", "state")
+	rsyncBytes           = Metrics.NewCounter("gpupgrade_rsync_bytes_transferred_total", "The bytes rsync sent from the host rather than matched at the destination.", "host")
+	pgUpgradeSegments    = Metrics.NewCounter("gpupgrade_pg_upgrade_segments_total", "The number of primaries pg_upgrade completed or failed by action.", "action", "result")
+	diskAvailable        = Metrics.NewGauge("gpupgrade_disk_available_bytes", "The available space of the filesystem on the host as reported by the last disk space check.", "host", "filesystem")
+	diskRequired         = Metrics.NewGauge("gpupgrade_disk_required_bytes", "The space required on the filesystem on the host as reported by the last disk space check.", "host", "filesystem")
+)
+
+var agentConnectionStates = []connectivity.State{
+	connectivity.Idle,
+	connectivity.Connecting,
+	connectivity.Ready,
+	connectivity.TransientFailure,
+	connectivity.Shutdown,
+}
+
+// metricsObserver tracks the running step and substeps.
+type metricsObserver struct{}
+
+func (metricsObserver) StepStarted(step idl.Step) {
+	stepRunning.Set(1, metricName(step.String()))
+}
+
+func (metricsObserver) SubstepStarted(step idl.Step, substep idl.Substep) {
+	substepRunning.Set(1, metricName(step.String()), metricName(substep.String()))
+}
+
+func (metricsObserver) SubstepFinished(step idl.Step, substep idl.Substep, status idl.Status, duration time.Duration, _ error) {
+	substepRunning.Set(0, metricName(step.String()), metricName(substep.String()))
+	substepDuration.Set(duration.Seconds(), metricName(step.String()), metricName(substep.String()))
+	substepsFinished.Add(1, metricName(step.String()), metricName(status.String()))
+}
+
+func (metricsObserver) PointOfNoReturn(idl.Step, idl.Substep, error) {}
+
+func (metricsObserver) StepFinished(step idl.Step, _ error) {
+	stepRunning.Set(0, metricName(step.String()))
+}
+
+func metricName(name string) string {
+	return strings.ToLower(name)
+}
+
+// collectAgentConnectionStates reports the state of each agent connection when
+// the metrics are scraped.
+func (s *Server) collectAgentConnectionStates() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agentConnectionState.Reset()
+	for _, conn := range s.agentConns {
+		current := conn.Conn.GetState()
+		for _, state := range agentConnectionStates {
+			value := 0.0
+			if state == current {
+				value = 1
+			}
+
+			agentConnectionState.Set(value, conn.Hostname, metricName(state.String()))
+		}
+	}
+}
+
+func recordRsyncBytes(host string, stats []*idl.ResyncStats) {
+	for _, s := range stats {
+		rsyncBytes.Add(float64(s.GetLiteralBytes()), host)
+	}
+}
+
+func recordPgUpgrade(action idl.PgOptions_Action, err error) {
+	result := "completed"
+	if err != nil {
+		result = "failed"
+	}
+
+	pgUpgradeSegments.Add(1, metricName(action.String()), result)
+}
+
+func recordDiskUsage(usages []*idl.CheckDiskSpaceReply_DiskUsage) {
+	for _, usage := range usages {
+		diskAvailable.Set(float64(usage.GetAvailable()), usage.GetHost(), usage.GetFs())
+		diskRequired.Set(float64(usage.GetRequired()), usage.GetHost(), usage.GetFs())
+	}
+}
+
+// startMetrics serves the metrics on the metrics port until the hub stops.
+func (s *Server) startMetrics() error {
+	lis, err := net.Listen("tcp", ":"+strconv.Itoa(s.MetricsPort))
+	if err != nil {
+		return xerrors.Errorf("listen on metrics port %d: %w", s.MetricsPort, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Metrics)
+	server := &http.Server{Handler: mux}
+
+	s.mu.Lock()
+	s.metricsServer = server
+	s.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			gplog.Error("serving metrics: %s", err)
+		}
+	}()
+
+	return nil
+}
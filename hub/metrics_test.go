@@ -0,0 +1,88 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Run("tracks the running step and substeps", func(t *testing.T) {
+		observer := metricsObserver{}
+
+		observer.StepStarted(idl.Step_EXECUTE)
+		observer.SubstepStarted(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+
+		if value := stepRunning.Value("execute"); value != 1 {
+			t.Errorf("got step running %v want 1", value)
+		}
+
+		if value := substepRunning.Value("execute", "upgrade_primaries"); value != 1 {
+			t.Errorf("got substep running %v want 1", value)
+		}
+
+		failed := substepsFinished.Value("execute", "failed")
+		observer.SubstepFinished(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES, idl.Status_FAILED, 90*time.Second, errors.New("oops"))
+		observer.StepFinished(idl.Step_EXECUTE, errors.New("oops"))
+
+		if value := substepRunning.Value("execute", "upgrade_primaries"); value != 0 {
+			t.Errorf("got substep running %v want 0", value)
+		}
+
+		if value := substepDuration.Value("execute", "upgrade_primaries"); value != 90 {
+			t.Errorf("got substep duration %v want 90", value)
+		}
+
+		if value := substepsFinished.Value("execute", "failed"); value != failed+1 {
+			t.Errorf("got failed substeps %v want %v", value, failed+1)
+		}
+
+		if value := stepRunning.Value("execute"); value != 0 {
+			t.Errorf("got step running %v want 0", value)
+		}
+	})
+
+	t.Run("records rsync, pg_upgrade, and disk usage", func(t *testing.T) {
+		sent := rsyncBytes.Value("metrics-host")
+		recordRsyncBytes("metrics-host", []*idl.ResyncStats{{TotalBytes: 4096, LiteralBytes: 1024}, {LiteralBytes: 1024}})
+		if value := rsyncBytes.Value("metrics-host"); value != sent+2048 {
+			t.Errorf("got rsync bytes %v want %v", value, sent+2048)
+		}
+
+		completed := pgUpgradeSegments.Value("upgrade", "completed")
+		failed := pgUpgradeSegments.Value("upgrade", "failed")
+		recordPgUpgrade(idl.PgOptions_upgrade, nil)
+		recordPgUpgrade(idl.PgOptions_upgrade, errors.New("oops"))
+		if value := pgUpgradeSegments.Value("upgrade", "completed"); value != completed+1 {
+			t.Errorf("got completed %v want %v", value, completed+1)
+		}
+		if value := pgUpgradeSegments.Value("upgrade", "failed"); value != failed+1 {
+			t.Errorf("got failed %v want %v", value, failed+1)
+		}
+
+		recordDiskUsage([]*idl.CheckDiskSpaceReply_DiskUsage{{Fs: "/data", Host: "metrics-host", Available: 100, Required: 200}})
+		if value := diskAvailable.Value("metrics-host", "/data"); value != 100 {
+			t.Errorf("got available %v want 100", value)
+		}
+		if value := diskRequired.Value("metrics-host", "/data"); value != 200 {
+			t.Errorf("got required %v want 200", value)
+		}
+
+		var buf bytes.Buffer
+		if err := Metrics.Write(&buf); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := `gpupgrade_disk_required_bytes{host="metrics-host",filesystem="/data"} 200`
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("got metrics %q want them to contain %q", buf.String(), expected)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+)
+
+// notifier is implemented by notify.Notifier.
+type notifier interface {
+	Notify(event notify.Event)
+}
+
+// notificationObserver notifies of steps starting and finishing, substeps
+// failing, and the point of no return being passed or stopping the step.
+type notificationObserver struct {
+	notifier notifier
+	config   *Config
+	host     string
+
+	mu      sync.Mutex
+	started map[idl.Step]time.Time
+}
+
+func newNotificationObserver(n notifier, config *Config) *notificationObserver {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+
+	return &notificationObserver{notifier: n, config: config, host: host, started: make(map[idl.Step]time.Time)}
+}
+
+func (o *notificationObserver) event(name string, s idl.Step, err error) notify.Event {
+	event := notify.Event{
+		Event:       name,
+		UpgradeID:   o.config.UpgradeID.String(),
+		Host:        o.host,
+		Step:        s.String(),
+		Time:        time.Now(),
+		NextActions: step.NextActions(err),
+	}
+
+	if err != nil {
+		event.Error = err.Error()
+	}
+
+	return event
+}
+
+func (o *notificationObserver) StepStarted(s idl.Step) {
+	o.mu.Lock()
+	o.started[s] = time.Now()
+	o.mu.Unlock()
+
+	o.notifier.Notify(o.event(notify.StepStarted, s, nil))
+}
+
+func (o *notificationObserver) SubstepStarted(idl.Step, idl.Substep) {}
+
+func (o *notificationObserver) SubstepFinished(s idl.Step, substep idl.Substep, status idl.Status, duration time.Duration, err error) {
+	if status != idl.Status_FAILED {
+		return
+	}
+
+	event := o.event(notify.SubstepFailed, s, err)
+	event.Substep = substep.String()
+	event.Status = status.String()
+	event.Duration = duration.Seconds()
+	o.notifier.Notify(event)
+}
+
+func (o *notificationObserver) PointOfNoReturn(s idl.Step, substep idl.Substep, err error) {
+	event := o.event(notify.PointOfNoReturn, s, err)
+	event.Substep = substep.String()
+	o.notifier.Notify(event)
+}
+
+func (o *notificationObserver) StepFinished(s idl.Step, err error) {
+	event := o.event(notify.StepFinished, s, err)
+
+	event.Status = idl.Status_COMPLETE.String()
+	if err != nil {
+		event.Status = idl.Status_FAILED.String()
+	}
+
+	o.mu.Lock()
+	if started, ok := o.started[s]; ok {
+		event.Duration = time.Since(started).Seconds()
+		delete(o.started, s)
+	}
+	o.mu.Unlock()
+
+	o.notifier.Notify(event)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+)
+
+type eventRecorder []notify.Event
+
+func (r *eventRecorder) Notify(event notify.Event) {
+	*r = append(*r, event)
+}
+
+func TestNotificationObserver(t *testing.T) {
+	id := upgrade.NewID()
+	events := &eventRecorder{}
+	observer := newNotificationObserver(events, &Config{UpgradeID: id})
+
+	nextAction := utils.NewNextActionErr(errors.New("oops"), "Please retry.")
+
+	observer.StepStarted(idl.Step_FINALIZE)
+	observer.SubstepStarted(idl.Step_FINALIZE, idl.Substep_SHUTDOWN_TARGET_CLUSTER)
+	observer.SubstepFinished(idl.Step_FINALIZE, idl.Substep_SHUTDOWN_TARGET_CLUSTER, idl.Status_COMPLETE, time.Second, nil)
+	observer.PointOfNoReturn(idl.Step_FINALIZE, idl.Substep_UPGRADE_MIRRORS, nil)
+	observer.SubstepStarted(idl.Step_FINALIZE, idl.Substep_UPGRADE_MIRRORS)
+	observer.SubstepFinished(idl.Step_FINALIZE, idl.Substep_UPGRADE_MIRRORS, idl.Status_FAILED, time.Minute, nextAction)
+	observer.StepFinished(idl.Step_FINALIZE, nextAction)
+
+	// Completed substeps are not notified.
+	if len(*events) != 4 {
+		t.Fatalf("got events %+v want 4", *events)
+	}
+
+	for _, event := range *events {
+		if event.UpgradeID != id.String() || event.Host != observer.host || event.Step != "FINALIZE" || event.Time.IsZero() {
+			t.Errorf("got event %+v want the upgrade ID, host, step, and time", event)
+		}
+	}
+
+	started, pointOfNoReturn, failed, finished := (*events)[0], (*events)[1], (*events)[2], (*events)[3]
+	if started.Event != notify.StepStarted || started.Substep != "" || started.Error != "" {
+		t.Errorf("got step started event %+v", started)
+	}
+
+	if pointOfNoReturn.Event != notify.PointOfNoReturn || pointOfNoReturn.Substep != "UPGRADE_MIRRORS" || pointOfNoReturn.Error != "" {
+		t.Errorf("got point of no return event %+v", pointOfNoReturn)
+	}
+
+	if failed.Event != notify.SubstepFailed || failed.Substep != "UPGRADE_MIRRORS" || failed.Status != "FAILED" ||
+		failed.Duration != 60 || failed.Error != "oops" || failed.NextActions != "Please retry." {
+		t.Errorf("got substep failed event %+v", failed)
+	}
+
+	if finished.Event != notify.StepFinished || finished.Status != "FAILED" || finished.Duration <= 0 ||
+		finished.Error != "oops" || finished.NextActions != "Please retry." {
+		t.Errorf("got step finished event %+v", finished)
+	}
+
+	t.Run("notifies of a completed step", func(t *testing.T) {
+		*events = nil
+
+		observer.StepStarted(idl.Step_EXECUTE)
+		observer.StepFinished(idl.Step_EXECUTE, nil)
+
+		finished := (*events)[1]
+		if finished.Event != notify.StepFinished || finished.Status != "COMPLETE" || finished.Error != "" || finished.NextActions != "" {
+			t.Errorf("got step finished event %+v", finished)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// maxPgUpgradeErrorLines limits the error lines from the pg_upgrade logs that
+// are included in the error of a failed segment.
+const maxPgUpgradeErrorLines = 10
+
+// CollectPgUpgradeLogs copies the pg_upgrade logs of a failed segment to dir,
+// replacing any logs collected from a previous failure, and returns them.
+// Nothing is copied when the segment has no logs.
+func CollectPgUpgradeLogs(ctx context.Context, conn *idl.Connection, role string, contentID int32, dir string) ([]*idl.PgUpgradeLog, error) {
+	req := &idl.GetPgUpgradeLogsRequest{Role: role, ContentID: contentID}
+	reply, err := conn.AgentClient.GetPgUpgradeLogs(ctx, req)
+	if err != nil {
+		return nil, xerrors.Errorf("getting pg_upgrade logs of content %d on host %s: %w", contentID, conn.Hostname, err)
+	}
+
+	if len(reply.GetLogs()) == 0 {
+		return nil, nil
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+
+	for _, log := range reply.GetLogs() {
+		path := filepath.Join(dir, filepath.Clean(string(filepath.Separator)+log.GetName()))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return nil, err
+		}
+
+		if err := utils.AtomicallyWrite(path, log.GetContents()); err != nil {
+			return nil, err
+		}
+	}
+
+	return reply.GetLogs(), nil
+}
+
+// pgUpgradeLogDetails collects the pg_upgrade logs of a failed segment into the
+// log directory of the coordinator, and returns the lines to append to its
+// error. Failures to collect the logs are reported rather than returned so
+// that the original error is not lost.
+func pgUpgradeLogDetails(ctx context.Context, conn *idl.Connection, role string, contentID int32) string {
+	dir, err := utils.GetCollectedPgUpgradeDir(conn.Hostname, role, contentID)
+	if err != nil {
+		return fmt.Sprintf("\nCould not collect the pg_upgrade logs: %v", err)
+	}
+
+	logs, err := CollectPgUpgradeLogs(ctx, conn, role, contentID, dir)
+	if err != nil {
+		return fmt.Sprintf("\nCould not collect the pg_upgrade logs: %v", err)
+	}
+
+	if len(logs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	for _, line := range upgrade.PgUpgradeErrorLines(logs, maxPgUpgradeErrorLines) {
+		b.WriteString("\n  " + line)
+	}
+	fmt.Fprintf(&b, "\nThe pg_upgrade logs of content %d on host %s are in %s.", contentID, conn.Hostname, dir)
+
+	return b.String()
+}
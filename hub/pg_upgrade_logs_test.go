@@ -0,0 +1,101 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestCollectPgUpgradeLogs(t *testing.T) {
+	t.Run("copies the logs of the segment replacing previously collected logs", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		testutils.MustWriteToFile(t, filepath.Join(dir, "stale.log"), "stale")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		logs := []*idl.PgUpgradeLog{
+			{Name: "pg_upgrade_internal.log", Contents: []byte("*failure*")},
+			{Name: "pg_upgrade_output.d/log/pg_upgrade_server.log", Contents: []byte("FATAL: out of disk")},
+		}
+
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().GetPgUpgradeLogs(
+			gomock.Any(),
+			&idl.GetPgUpgradeLogsRequest{Role: greenplum.PrimaryRole, ContentID: 3},
+		).Return(&idl.GetPgUpgradeLogsReply{Logs: logs}, nil)
+
+		conn := &idl.Connection{AgentClient: client, Hostname: "sdw1"}
+		collected, err := hub.CollectPgUpgradeLogs(context.Background(), conn, greenplum.PrimaryRole, 3, dir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if !reflect.DeepEqual(collected, logs) {
+			t.Errorf("got %v want %v", collected, logs)
+		}
+
+		testutils.PathMustNotExist(t, filepath.Join(dir, "stale.log"))
+		for _, log := range logs {
+			contents := testutils.MustReadFile(t, filepath.Join(dir, log.GetName()))
+			if contents != string(log.GetContents()) {
+				t.Errorf("got %q want %q", contents, log.GetContents())
+			}
+		}
+	})
+
+	t.Run("does not copy anything when the segment has no logs", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		testutils.MustWriteToFile(t, filepath.Join(dir, "previous.log"), "previous")
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().GetPgUpgradeLogs(gomock.Any(), gomock.Any()).Return(&idl.GetPgUpgradeLogsReply{}, nil)
+
+		conn := &idl.Connection{AgentClient: client, Hostname: "sdw1"}
+		collected, err := hub.CollectPgUpgradeLogs(context.Background(), conn, greenplum.PrimaryRole, 3, dir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		if len(collected) != 0 {
+			t.Errorf("got %v want no logs", collected)
+		}
+
+		testutils.PathMustExist(t, filepath.Join(dir, "previous.log"))
+	})
+
+	t.Run("errors when failing to get the logs", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		client := mock_idl.NewMockAgentClient(ctrl)
+		client.EXPECT().GetPgUpgradeLogs(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		conn := &idl.Connection{AgentClient: client, Hostname: "sdw1"}
+		_, err := hub.CollectPgUpgradeLogs(context.Background(), conn, greenplum.PrimaryRole, 3, "/does/not/matter")
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/backup"
+)
+
+// ExecuteIrreversibleSubsteps returns the execute substeps after which revert
+// can no longer restore the source cluster. Starting the target cluster
+// modifies the source data files hard linked in link mode, which revert can
+// only restore from the mirrors and standby or from snapshots.
+func ExecuteIrreversibleSubsteps(source *greenplum.Cluster, mode idl.Mode, useSnapshots bool) []idl.Substep {
+	if mode == idl.Mode_link && !source.HasAllMirrorsAndStandby() && !useSnapshots {
+		return []idl.Substep{idl.Substep_START_TARGET_CLUSTER}
+	}
+
+	return nil
+}
+
+// FinalizeIrreversibleSubsteps are the finalize substeps after which revert
+// can no longer restore the source cluster.
+var FinalizeIrreversibleSubsteps = []idl.Substep{
+	idl.Substep_POINT_OF_NO_RETURN,
+	idl.Substep_START_TARGET_CLUSTER,
+	idl.Substep_DELETE_SOURCE_SNAPSHOTS,
+	idl.Substep_DELETE_SEGMENT_STATEDIRS,
+}
+
+// AcknowledgePointOfNoReturn returns a function that allows the step to pass
+// its point of no return when the confirmation matches the token of the
+// upgrade, or when a fresh backup of the source cluster has been registered.
+// The backup is looked up when the point of no return is reached since the
+// step may register it beforehand.
+func AcknowledgePointOfNoReturn(stepName idl.Step, id upgrade.ID, registered func() *backup.Backup, confirmation string) func(idl.Substep) error {
+	return func(substep idl.Substep) error {
+		token := id.PointOfNoReturnToken()
+		if confirmation == token {
+			gplog.Info("passing the point of no return of %s before %s as acknowledged by the user", stepName, substep)
+			return nil
+		}
+
+		if b := registered(); b.IsFresh(time.Now()) {
+			gplog.Info("passing the point of no return of %s before %s with %s", stepName, substep, b)
+			return nil
+		}
+
+		err := step.PointOfNoReturnErr{Substep: substep, Confirmation: token}
+		if confirmation != "" {
+			return utils.NewNextActionErr(fmt.Errorf("%w: incorrect confirmation %q", err, confirmation), pointOfNoReturnNextAction(stepName, token))
+		}
+
+		return utils.NewNextActionErr(err, pointOfNoReturnNextAction(stepName, token))
+	}
+}
+
+func (s *Server) registeredBackup() *backup.Backup {
+	return s.Backup
+}
+
+func pointOfNoReturnNextAction(stepName idl.Step, token string) string {
+	return fmt.Sprintf(`The source cluster can no longer be restored by "gpupgrade revert" once %[1]s passes its point of no return.
+Take a backup of the source cluster if you do not already have one.
+To proceed with the upgrade, run "gpupgrade %[1]s --confirm-point-of-no-return %[2]s".
+To return the cluster to its original state, run "gpupgrade revert".`, strings.ToLower(stepName.String()), token)
+}
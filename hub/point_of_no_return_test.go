@@ -0,0 +1,142 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/backup"
+)
+
+func TestExecuteIrreversibleSubsteps(t *testing.T) {
+	withoutMirrors := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+	})
+
+	withMirrorsAndStandby := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{ContentID: -1, DbID: 1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{ContentID: -1, DbID: 4, Hostname: "smdw", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+		{ContentID: 0, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Role: greenplum.MirrorRole},
+	})
+
+	cases := []struct {
+		name         string
+		source       *greenplum.Cluster
+		mode         idl.Mode
+		useSnapshots bool
+		expected     []idl.Substep
+	}{
+		{
+			name:     "starting the target cluster in link mode without mirrors and standby",
+			source:   withoutMirrors,
+			mode:     idl.Mode_link,
+			expected: []idl.Substep{idl.Substep_START_TARGET_CLUSTER},
+		},
+		{
+			name:   "nothing in link mode with mirrors and standby",
+			source: withMirrorsAndStandby,
+			mode:   idl.Mode_link,
+		},
+		{
+			name:         "nothing in link mode with snapshots",
+			source:       withoutMirrors,
+			mode:         idl.Mode_link,
+			useSnapshots: true,
+		},
+		{
+			name:   "nothing in copy mode",
+			source: withoutMirrors,
+			mode:   idl.Mode_copy,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			substeps := hub.ExecuteIrreversibleSubsteps(c.source, c.mode, c.useSnapshots)
+			if !reflect.DeepEqual(substeps, c.expected) {
+				t.Errorf("got %v want %v", substeps, c.expected)
+			}
+		})
+	}
+}
+
+func TestAcknowledgePointOfNoReturn(t *testing.T) {
+	testlog.SetupLogger()
+
+	id := upgrade.NewID()
+	token := id.PointOfNoReturnToken()
+
+	t.Run("passes when the confirmation matches the token of the upgrade", func(t *testing.T) {
+		acknowledge := hub.AcknowledgePointOfNoReturn(idl.Step_FINALIZE, id, registered(nil), token)
+		if err := acknowledge(idl.Substep_POINT_OF_NO_RETURN); err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("passes when a fresh backup has been registered", func(t *testing.T) {
+		fresh := &backup.Backup{ID: "20220101120000", Time: time.Now().Add(-time.Hour)}
+
+		acknowledge := hub.AcknowledgePointOfNoReturn(idl.Step_FINALIZE, id, registered(fresh), "")
+		if err := acknowledge(idl.Substep_POINT_OF_NO_RETURN); err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	cases := []struct {
+		name         string
+		backup       *backup.Backup
+		confirmation string
+	}{
+		{name: "without a confirmation or backup"},
+		{name: "with an incorrect confirmation", confirmation: "1234"},
+		{name: "with a stale backup", backup: &backup.Backup{ID: "20220101120000", Time: time.Now().Add(-backup.MaxAge - time.Hour)}},
+		{name: "with a backup that has no time", backup: &backup.Backup{ID: "20220101120000"}},
+	}
+
+	for _, c := range cases {
+		t.Run("requires acknowledgement "+c.name, func(t *testing.T) {
+			acknowledge := hub.AcknowledgePointOfNoReturn(idl.Step_EXECUTE, id, registered(c.backup), c.confirmation)
+			err := acknowledge(idl.Substep_START_TARGET_CLUSTER)
+
+			var pointOfNoReturnErr step.PointOfNoReturnErr
+			if !errors.As(err, &pointOfNoReturnErr) {
+				t.Fatalf("got error %#v want type %T", err, pointOfNoReturnErr)
+			}
+
+			expected := step.PointOfNoReturnErr{Substep: idl.Substep_START_TARGET_CLUSTER, Confirmation: token}
+			if pointOfNoReturnErr != expected {
+				t.Errorf("got %+v want %+v", pointOfNoReturnErr, expected)
+			}
+
+			var nextActionErr utils.NextActionErr
+			if !errors.As(err, &nextActionErr) {
+				t.Fatalf("got error %#v want type %T", err, nextActionErr)
+			}
+
+			expectedCommand := "gpupgrade execute --confirm-point-of-no-return " + token
+			if !strings.Contains(nextActionErr.NextAction, expectedCommand) {
+				t.Errorf("got next action %q want it to contain %q", nextActionErr.NextAction, expectedCommand)
+			}
+		})
+	}
+}
+
+func registered(b *backup.Backup) func() *backup.Backup {
+	return func() *backup.Backup {
+		return b
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/preflight"
+)
+
+// The minimum resource limits recommended for Greenplum hosts. Lower limits
+// are reported as preflight warnings rather than failures.
+const (
+	MinOpenFiles = 65536
+	MinProcesses = 131072
+)
+
+var RequiredTools = []string{"rsync", "ssh"}
+
+var localPreflight = preflight.Run
+
+type PreflightResults map[string][]*idl.PreflightReply_Check
+
+// Preflight checks that every host is ready for the upgrade. It runs all
+// checks on all hosts before reporting so that every problem is found in one
+// pass, and returns an error listing the failed checks per host.
+func Preflight(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, coordinatorHost string, requests map[string]*idl.PreflightRequest) error {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	errs := make(chan error, len(agentConns))
+	results := make(PreflightResults)
+
+	// Run the coordinator checks locally when no agent runs on the
+	// coordinator host.
+	coordinatorHasAgent := false
+	for _, conn := range agentConns {
+		if conn.Hostname == coordinatorHost {
+			coordinatorHasAgent = true
+		}
+	}
+
+	if !coordinatorHasAgent {
+		results[coordinatorHost] = localPreflight(ctx, requests[coordinatorHost])
+	}
+
+	for _, conn := range agentConns {
+		conn := conn
+
+		req, ok := requests[conn.Hostname]
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			reply, err := conn.AgentClient.Preflight(ctx, req)
+			if err != nil {
+				errs <- xerrors.Errorf("preflight checks on host %s: %w", conn.Hostname, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[conn.Hostname] = reply.GetChecks()
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	var err error
+	for e := range errs {
+		err = errorlist.Append(err, e)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(streams.Stdout(), results.Report(false))
+
+	if !results.Failed() {
+		return nil
+	}
+
+	return utils.NewNextActionErr(
+		xerrors.Errorf("Preflight checks failed.\n\n%s", results.Report(true)),
+		"Correct the failed checks on each host and re-run gpupgrade initialize.")
+}
+
+// PreflightRequests returns the preflight request for each host of the
+// intermediate cluster keyed by hostname.
+func PreflightRequests(intermediate *greenplum.Cluster, gpupgradeVersion string, locales []string) map[string]*idl.PreflightRequest {
+	requests := make(map[string]*idl.PreflightRequest)
+	parents := make(map[string]map[string]bool)
+
+	segments := intermediate.SelectSegments(func(*greenplum.SegConfig) bool { return true })
+	sort.Sort(segments)
+
+	for _, seg := range segments {
+		req, ok := requests[seg.Hostname]
+		if !ok {
+			req = &idl.PreflightRequest{
+				TargetGPHome:     intermediate.GPHome,
+				TargetVersion:    intermediate.Version.String(),
+				GpupgradeVersion: gpupgradeVersion,
+				Tools:            RequiredTools,
+				Locales:          locales,
+				MinOpenFiles:     MinOpenFiles,
+				MinProcesses:     MinProcesses,
+			}
+			requests[seg.Hostname] = req
+			parents[seg.Hostname] = make(map[string]bool)
+		}
+
+		req.Ports = append(req.Ports, uint32(seg.Port))
+
+		parent := filepath.Dir(seg.DataDir)
+		if !parents[seg.Hostname][parent] {
+			parents[seg.Hostname][parent] = true
+			req.DataDirParents = append(req.DataDirParents, parent)
+		}
+	}
+
+	return requests
+}
+
+// AddSnapshotChecks asks each host to check that the provider can snapshot
+// its directories, keyed by hostname as returned by SnapshotDirectories.
+func AddSnapshotChecks(requests map[string]*idl.PreflightRequest, provider string, dirs map[string][]string) {
+	for host, hostDirs := range dirs {
+		req, ok := requests[host]
+		if !ok {
+			continue
+		}
+
+		req.SnapshotProvider = provider
+		req.SnapshotDirs = hostDirs
+	}
+}
+
+// Failed returns true if any check failed on any host.
+func (p PreflightResults) Failed() bool {
+	for _, checks := range p {
+		for _, check := range checks {
+			if !check.GetPassed() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Report returns a table of the checks for each host sorted by hostname.
+// When onlyFailures is set passed checks, including those with warnings, are
+// omitted.
+func (p PreflightResults) Report(onlyFailures bool) string {
+	var hosts []string
+	for host := range p {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	var b strings.Builder
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(&t, "Hostname\tCheck\tResult\tDetails")
+	for _, host := range hosts {
+		for _, check := range p[host] {
+			if onlyFailures && check.GetPassed() {
+				continue
+			}
+
+			result := "passed"
+			switch {
+			case !check.GetPassed():
+				result = "FAILED"
+			case check.GetWarning():
+				result = "WARNING"
+			}
+
+			fmt.Fprintf(&t, "%s\t%s\t%s\t%s\n", host, check.GetName(), result, check.GetMessage())
+		}
+	}
+
+	t.Flush()
+	return b.String()
+}
+
+func (s *Server) SourceLocales() (locales []string, err error) {
+	options := []greenplum.Option{
+		greenplum.ToSource(),
+		greenplum.Port(s.Source.CoordinatorPort()),
+		greenplum.UtilityMode(),
+	}
+
+	db, err := sql.Open("pgx", s.Connection.URI(options...))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	return QueryLocales(db)
+}
+
+// QueryLocales returns the distinct collation and character classification
+// locales used by the databases in the cluster.
+func QueryLocales(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT datcollate FROM pg_database UNION SELECT datctype FROM pg_database ORDER BY 1`)
+	if err != nil {
+		return nil, xerrors.Errorf("querying locales: %w", err)
+	}
+	defer rows.Close()
+
+	var locales []string
+	for rows.Next() {
+		var locale string
+		if err := rows.Scan(&locale); err != nil {
+			return nil, xerrors.Errorf("scanning locales: %w", err)
+		}
+
+		// The C and POSIX locales are always available.
+		if locale == "C" || locale == "POSIX" {
+			continue
+		}
+
+		locales = append(locales, locale)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iterating locales: %w", err)
+	}
+
+	return locales, nil
+}
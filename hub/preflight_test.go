@@ -0,0 +1,324 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blang/semver/v4"
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func PreflightPasses(context.Context, *idl.PreflightRequest) []*idl.PreflightReply_Check {
+	return []*idl.PreflightReply_Check{{Name: "check", Passed: true}}
+}
+
+func TestPreflightRequests(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "mdw", Port: 50432, DataDir: "/data/qddir/seg.AAAAAAAAAAA.-1", Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "smdw", Port: 50433, DataDir: "/data/standby.AAAAAAAAAAA", Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", Port: 50434, DataDir: "/data/dbfast1/seg.AAAAAAAAAAA.0", Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 1, Hostname: "sdw1", Port: 50435, DataDir: "/data/dbfast2/seg.AAAAAAAAAAA.1", Role: greenplum.PrimaryRole},
+		{DbID: 5, ContentID: 0, Hostname: "sdw2", Port: 50436, DataDir: "/data/dbfast_mirror1/seg.AAAAAAAAAAA.0", Role: greenplum.MirrorRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw2", Port: 50437, DataDir: "/data/dbfast_mirror1/seg.AAAAAAAAAAA.1", Role: greenplum.MirrorRole},
+	})
+	intermediate.GPHome = "/usr/local/greenplum-db-target"
+	intermediate.Version = semver.MustParse("6.20.0")
+
+	requests := hub.PreflightRequests(intermediate, "1.0.0", []string{"en_US.UTF-8"})
+
+	base := func(ports []uint32, parents []string) *idl.PreflightRequest {
+		return &idl.PreflightRequest{
+			TargetGPHome:     "/usr/local/greenplum-db-target",
+			TargetVersion:    "6.20.0",
+			GpupgradeVersion: "1.0.0",
+			Ports:            ports,
+			Tools:            hub.RequiredTools,
+			Locales:          []string{"en_US.UTF-8"},
+			DataDirParents:   parents,
+			MinOpenFiles:     hub.MinOpenFiles,
+			MinProcesses:     hub.MinProcesses,
+		}
+	}
+
+	expected := map[string]*idl.PreflightRequest{
+		"mdw":  base([]uint32{50432}, []string{"/data/qddir"}),
+		"smdw": base([]uint32{50433}, []string{"/data"}),
+		"sdw1": base([]uint32{50434, 50435}, []string{"/data/dbfast1", "/data/dbfast2"}),
+		"sdw2": base([]uint32{50436, 50437}, []string{"/data/dbfast_mirror1"}),
+	}
+
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("got %v want %v", requests, expected)
+	}
+}
+
+func TestAddSnapshotChecks(t *testing.T) {
+	requests := map[string]*idl.PreflightRequest{
+		"mdw":  {},
+		"sdw1": {},
+	}
+
+	dirs := map[string][]string{
+		"mdw":  {"/data/qddir/seg-1"},
+		"sdw1": {"/data/dbfast1/seg0", "/data/dbfast2/seg1"},
+		"sdw2": {"/data/dbfast3/seg2"},
+	}
+
+	hub.AddSnapshotChecks(requests, "lvm", dirs)
+
+	expected := map[string]*idl.PreflightRequest{
+		"mdw":  {SnapshotProvider: "lvm", SnapshotDirs: []string{"/data/qddir/seg-1"}},
+		"sdw1": {SnapshotProvider: "lvm", SnapshotDirs: []string{"/data/dbfast1/seg0", "/data/dbfast2/seg1"}},
+	}
+
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("got %v want %v", requests, expected)
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "mdw", Port: 50432, DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", Port: 50433, DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+	})
+
+	t.Run("runs the coordinator checks locally and the segment checks on the agents", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		called := false
+		hub.SetLocalPreflight(func(_ context.Context, req *idl.PreflightRequest) []*idl.PreflightReply_Check {
+			called = true
+
+			expected := []uint32{50432}
+			if !reflect.DeepEqual(req.GetPorts(), expected) {
+				t.Errorf("got ports %v want %v", req.GetPorts(), expected)
+			}
+
+			return PreflightPasses(context.Background(), req)
+		})
+		defer hub.ResetLocalPreflight()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().Preflight(
+			gomock.Any(),
+			gomock.Any(),
+		).DoAndReturn(func(_ interface{}, req *idl.PreflightRequest, _ ...interface{}) (*idl.PreflightReply, error) {
+			expected := []uint32{50433}
+			if !reflect.DeepEqual(req.GetPorts(), expected) {
+				t.Errorf("got ports %v want %v", req.GetPorts(), expected)
+			}
+
+			return &idl.PreflightReply{Checks: PreflightPasses(context.Background(), req)}, nil
+		})
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.Preflight(context.Background(), step.DevNullStream, agentConns, intermediate.CoordinatorHostname(), hub.PreflightRequests(intermediate, "1.0.0", nil))
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if !called {
+			t.Errorf("expected the coordinator checks to run locally")
+		}
+	})
+
+	t.Run("runs the coordinator checks on its agent when one is running there", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetLocalPreflight(func(_ context.Context, req *idl.PreflightRequest) []*idl.PreflightReply_Check {
+			t.Errorf("unexpected call to run preflight checks locally")
+			return nil
+		})
+		defer hub.ResetLocalPreflight()
+
+		mdw := mock_idl.NewMockAgentClient(ctrl)
+		mdw.EXPECT().Preflight(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.PreflightReply{Checks: PreflightPasses(context.Background(), nil)}, nil)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().Preflight(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.PreflightReply{Checks: PreflightPasses(context.Background(), nil)}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: mdw, Hostname: "mdw"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.Preflight(context.Background(), step.DevNullStream, agentConns, intermediate.CoordinatorHostname(), hub.PreflightRequests(intermediate, "1.0.0", nil))
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("returns a next action error listing the failed checks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetLocalPreflight(PreflightPasses)
+		defer hub.ResetLocalPreflight()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().Preflight(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(&idl.PreflightReply{Checks: []*idl.PreflightReply_Check{
+			{Name: "required tools", Passed: false, Message: "not found in PATH: rsync"},
+		}}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.Preflight(context.Background(), step.DevNullStream, agentConns, intermediate.CoordinatorHostname(), hub.PreflightRequests(intermediate, "1.0.0", nil))
+
+		var nextActionErr utils.NextActionErr
+		if !errors.As(err, &nextActionErr) {
+			t.Fatalf("got type %T want %T", err, nextActionErr)
+		}
+
+		if !strings.Contains(err.Error(), "not found in PATH: rsync") {
+			t.Errorf("expected error %q to contain the failed check", err.Error())
+		}
+
+		if strings.Contains(err.Error(), "passed") {
+			t.Errorf("expected error %q to omit passed checks", err.Error())
+		}
+	})
+
+	t.Run("errors when agents fail to run the checks", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		hub.SetLocalPreflight(PreflightPasses)
+		defer hub.ResetLocalPreflight()
+
+		expected := errors.New("connection refused")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().Preflight(
+			gomock.Any(),
+			gomock.Any(),
+		).Return(nil, expected)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.Preflight(context.Background(), step.DevNullStream, agentConns, intermediate.CoordinatorHostname(), hub.PreflightRequests(intermediate, "1.0.0", nil))
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestPreflightResults(t *testing.T) {
+	results := hub.PreflightResults{
+		"sdw1": {
+			{Name: "required tools", Passed: true, Message: "2 tools found"},
+			{Name: "locales", Passed: false, Message: "not installed: de_DE.UTF-8"},
+		},
+		"mdw": {
+			{Name: "required tools", Passed: true, Message: "2 tools found"},
+			{Name: "open files limit", Passed: true, Warning: true, Message: "1024 is less than the recommended 65536"},
+		},
+	}
+
+	t.Run("reports every check sorted by host", func(t *testing.T) {
+		expected := `Hostname  Check             Result   Details
+mdw       required tools    passed   2 tools found
+mdw       open files limit  WARNING  1024 is less than the recommended 65536
+sdw1      required tools    passed   2 tools found
+sdw1      locales           FAILED   not installed: de_DE.UTF-8
+`
+		actual := results.Report(false)
+		if actual != expected {
+			t.Errorf("got %q want %q", actual, expected)
+		}
+	})
+
+	t.Run("reports only failures", func(t *testing.T) {
+		expected := `Hostname  Check    Result  Details
+sdw1      locales  FAILED  not installed: de_DE.UTF-8
+`
+		actual := results.Report(true)
+		if actual != expected {
+			t.Errorf("got %q want %q", actual, expected)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		if !results.Failed() {
+			t.Errorf("expected results to have failed")
+		}
+
+		passed := hub.PreflightResults{"mdw": results["mdw"]}
+		if passed.Failed() {
+			t.Errorf("expected results with warnings to have passed")
+		}
+	})
+}
+
+func TestQueryLocales(t *testing.T) {
+	t.Run("returns the locales excluding C and POSIX", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		mock.ExpectQuery(`SELECT datcollate FROM pg_database UNION SELECT datctype FROM pg_database`).
+			WillReturnRows(sqlmock.NewRows([]string{"datcollate"}).
+				AddRow("C").
+				AddRow("POSIX").
+				AddRow("en_US.UTF-8"))
+
+		locales, err := hub.QueryLocales(db)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := []string{"en_US.UTF-8"}
+		if !reflect.DeepEqual(locales, expected) {
+			t.Errorf("got %v want %v", locales, expected)
+		}
+	})
+
+	t.Run("errors when the query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		expected := errors.New("permission denied")
+		mock.ExpectQuery(`SELECT datcollate FROM pg_database`).WillReturnError(expected)
+
+		_, err = hub.QueryLocales(db)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
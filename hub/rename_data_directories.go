@@ -17,7 +17,7 @@ var RenameDirectories = upgrade.RenameDirectories
 
 type RenameMap = map[string][]*idl.RenameDirectories
 
-func RenameDataDirectories(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+func RenameDataDirectories(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	src := source.CoordinatorDataDir()
 	dst := intermediate.CoordinatorDataDir()
 	if err := RenameDirectories(src, dst); err != nil {
@@ -25,7 +25,7 @@ func RenameDataDirectories(agentConns []*idl.Connection, source *greenplum.Clust
 	}
 
 	renameMap := getRenameMap(source, intermediate)
-	if err := RenameSegmentDataDirs(agentConns, renameMap); err != nil {
+	if err := RenameSegmentDataDirs(ctx, agentConns, renameMap); err != nil {
 		return xerrors.Errorf("renaming segment data directories: %w", err)
 	}
 
@@ -37,25 +37,34 @@ func RenameDataDirectories(agentConns []*idl.Connection, source *greenplum.Clust
 // the mirrors have been deleted to save disk space, so exclude them from the map.
 // Since the upgraded mirrors will be added later to the correct directory there
 // is no need to rename target to source, so only archive the source directory.
+//
+// Segments upgraded onto new hosts are renamed on the new host, where there is
+// no source to archive. Those that a layout also relocated keep their
+// intermediate data directories, so are not renamed at all.
 func getRenameMap(source *greenplum.Cluster, intermediate *greenplum.Cluster) RenameMap {
 	m := make(RenameMap)
 
+	add := func(seg greenplum.SegConfig, intermediateSeg greenplum.SegConfig) {
+		if seg.Hostname != intermediateSeg.Hostname && upgrade.IsRelocated(seg.DataDir, intermediateSeg.DataDir) {
+			return
+		}
+
+		m[intermediateSeg.Hostname] = append(m[intermediateSeg.Hostname], &idl.RenameDirectories{
+			Source: seg.DataDir,
+			Target: intermediateSeg.DataDir,
+		})
+	}
+
 	for _, seg := range source.Primaries {
 		if seg.IsCoordinator() {
 			continue
 		}
 
-		m[seg.Hostname] = append(m[seg.Hostname], &idl.RenameDirectories{
-			Source: seg.DataDir,
-			Target: intermediate.Primaries[seg.ContentID].DataDir,
-		})
+		add(seg, intermediate.Primaries[seg.ContentID])
 	}
 
 	for _, seg := range source.Mirrors {
-		m[seg.Hostname] = append(m[seg.Hostname], &idl.RenameDirectories{
-			Source: seg.DataDir,
-			Target: intermediate.Mirrors[seg.ContentID].DataDir,
-		})
+		add(seg, intermediate.Mirrors[seg.ContentID])
 	}
 
 	return m
@@ -63,14 +72,14 @@ func getRenameMap(source *greenplum.Cluster, intermediate *greenplum.Cluster) Re
 
 // e.g. for source /data/dbfast1/demoDataDir0 becomes /data/dbfast1/demoDataDir0_old
 // e.g. for target /data/dbfast1/demoDataDir0_123ABC becomes /data/dbfast1/demoDataDir0
-func RenameSegmentDataDirs(agentConns []*idl.Connection, renames RenameMap) error {
+func RenameSegmentDataDirs(ctx context.Context, agentConns []*idl.Connection, renames RenameMap) error {
 	request := func(conn *idl.Connection) error {
 		if len(renames[conn.Hostname]) == 0 {
 			return nil
 		}
 
 		req := &idl.RenameDirectoriesRequest{Dirs: renames[conn.Hostname]}
-		_, err := conn.AgentClient.RenameDirectories(context.Background(), req)
+		_, err := conn.AgentClient.RenameDirectories(ctx, req)
 		return err
 	}
 
@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -88,7 +89,7 @@ func TestRenameSegmentDataDirs(t *testing.T) {
 			{AgentClient: client3, Hostname: "standby"},
 		}
 
-		err := hub.RenameSegmentDataDirs(agentConns, m)
+		err := hub.RenameSegmentDataDirs(context.Background(), agentConns, m)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -116,7 +117,7 @@ func TestRenameSegmentDataDirs(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "sdw2"},
 		}
 
-		err := hub.RenameSegmentDataDirs(agentConns, m)
+		err := hub.RenameSegmentDataDirs(context.Background(), agentConns, m)
 
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
@@ -187,7 +188,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 			}
 		}()
 
-		err := hub.RenameDataDirectories(nil, conf.Source, conf.Intermediate)
+		err := hub.RenameDataDirectories(context.Background(), nil, conf.Source, conf.Intermediate)
 		if err != nil {
 			t.Errorf("UpdateDataDirectories() returned error: %+v", err)
 		}
@@ -206,7 +207,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 			}
 		}()
 
-		err := hub.RenameDataDirectories(nil, conf.Source, conf.Intermediate)
+		err := hub.RenameDataDirectories(context.Background(), nil, conf.Source, conf.Intermediate)
 		if !errors.Is(err, expected) {
 			t.Errorf("got %#v want %#v", err, expected)
 		}
@@ -216,7 +217,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		conf.LinkMode = false
+		conf.Mode = idl.Mode_copy
 
 		// We want the source's primaries and mirrors to be archived, but only
 		// the target's upgraded primaries should be moved back to the source
@@ -263,7 +264,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.RenameDataDirectories(agentConns, conf.Source, conf.Intermediate)
+		err := hub.RenameDataDirectories(context.Background(), agentConns, conf.Source, conf.Intermediate)
 		if err != nil {
 			t.Errorf("RenameDataDirectories() returned error: %+v", err)
 		}
@@ -273,7 +274,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
-		conf.LinkMode = true
+		conf.Mode = idl.Mode_link
 
 		// Similar to copy mode, but we want deletion requests on the mirrors
 		// and standby as opposed to archive requests.
@@ -319,7 +320,7 @@ func TestUpdateDataDirectories(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.RenameDataDirectories(agentConns, conf.Source, conf.Intermediate)
+		err := hub.RenameDataDirectories(context.Background(), agentConns, conf.Source, conf.Intermediate)
 		if err != nil {
 			t.Errorf("RenameDataDirectories() returned error: %+v", err)
 		}
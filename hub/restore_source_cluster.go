@@ -18,6 +18,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 	"github.com/greenplum-db/gpupgrade/utils/rsync"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 var RecoversegCmd = exec.Command
@@ -29,7 +30,7 @@ var Excludes = []string{
 	"gp_dbid", "postgresql.conf", "backup_label.old", "postmaster.pid", "recovery.conf",
 }
 
-func RsyncCoordinatorAndPrimaries(stream step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func RsyncCoordinatorAndPrimaries(ctx context.Context, stream step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 
 	var wg sync.WaitGroup
 	errs := make(chan error, 2)
@@ -40,7 +41,7 @@ func RsyncCoordinatorAndPrimaries(stream step.OutStreams, agentConns []*idl.Conn
 		errs <- RsyncCoordinator(stream, source.Standby(), source.Coordinator())
 	}()
 
-	errs <- RsyncPrimaries(agentConns, source)
+	errs <- RsyncPrimaries(ctx, agentConns, source)
 
 	wg.Wait()
 	close(errs)
@@ -53,7 +54,7 @@ func RsyncCoordinatorAndPrimaries(stream step.OutStreams, agentConns []*idl.Conn
 	return err
 }
 
-func RsyncCoordinatorAndPrimariesTablespaces(stream step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func RsyncCoordinatorAndPrimariesTablespaces(ctx context.Context, stream step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	var wg sync.WaitGroup
 	errs := make(chan error, 2)
 
@@ -63,7 +64,7 @@ func RsyncCoordinatorAndPrimariesTablespaces(stream step.OutStreams, agentConns
 		errs <- RsyncCoordinatorTablespaces(stream, source.StandbyHostname(), source.Tablespaces[source.Coordinator().DbID], source.Tablespaces[source.Standby().DbID])
 	}()
 
-	errs <- RsyncPrimariesTablespaces(agentConns, source, source.Tablespaces)
+	errs <- RsyncPrimariesTablespaces(ctx, agentConns, source, source.Tablespaces)
 
 	wg.Wait()
 	close(errs)
@@ -90,7 +91,7 @@ func Recoverseg(stream step.OutStreams, cluster *greenplum.Cluster, useHbaHostna
 	cmd.Stderr = stream.Stderr()
 
 	gplog.Info("running command: %q", cmd)
-	return cmd.Run()
+	return trace.Run(step.Context(stream), "gprecoverseg", cmd)
 }
 
 func RsyncCoordinator(stream step.OutStreams, standby greenplum.SegConfig, coordinator greenplum.SegConfig) error {
@@ -129,7 +130,7 @@ func RsyncCoordinatorTablespaces(stream step.OutStreams, standbyHostname string,
 	return nil
 }
 
-func RsyncPrimaries(agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func RsyncPrimaries(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		mirrors := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsMirror()
@@ -152,14 +153,14 @@ func RsyncPrimaries(agentConns []*idl.Connection, source *greenplum.Cluster) err
 		}
 
 		req := &idl.RsyncRequest{Options: opts}
-		_, err := conn.AgentClient.RsyncDataDirectories(context.Background(), req)
+		_, err := conn.AgentClient.RsyncDataDirectories(ctx, req)
 		return err
 	}
 
 	return ExecuteRPC(agentConns, request)
 }
 
-func RsyncPrimariesTablespaces(agentConns []*idl.Connection, source *greenplum.Cluster, tablespaces greenplum.Tablespaces) error {
+func RsyncPrimariesTablespaces(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, tablespaces greenplum.Tablespaces) error {
 	request := func(conn *idl.Connection) error {
 		mirrors := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsMirror()
@@ -192,14 +193,14 @@ func RsyncPrimariesTablespaces(agentConns []*idl.Connection, source *greenplum.C
 		}
 
 		req := &idl.RsyncRequest{Options: opts}
-		_, err := conn.AgentClient.RsyncTablespaceDirectories(context.Background(), req)
+		_, err := conn.AgentClient.RsyncTablespaceDirectories(ctx, req)
 		return err
 	}
 
 	return ExecuteRPC(agentConns, request)
 }
 
-func RestoreCoordinatorAndPrimariesPgControl(streams step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func RestoreCoordinatorAndPrimariesPgControl(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	var wg sync.WaitGroup
 	errs := make(chan error, 2)
 
@@ -209,7 +210,7 @@ func RestoreCoordinatorAndPrimariesPgControl(streams step.OutStreams, agentConns
 		errs <- upgrade.RestorePgControl(source.CoordinatorDataDir(), streams)
 	}()
 
-	errs <- restorePrimariesPgControl(agentConns, source)
+	errs <- restorePrimariesPgControl(ctx, agentConns, source)
 
 	wg.Wait()
 	close(errs)
@@ -222,7 +223,7 @@ func RestoreCoordinatorAndPrimariesPgControl(streams step.OutStreams, agentConns
 	return err
 }
 
-func restorePrimariesPgControl(agentConns []*idl.Connection, source *greenplum.Cluster) error {
+func restorePrimariesPgControl(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		primaries := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsPrimary()
@@ -241,7 +242,7 @@ func restorePrimariesPgControl(agentConns []*idl.Connection, source *greenplum.C
 			Datadirs: dataDirs,
 		}
 
-		_, err := conn.AgentClient.RestorePrimariesPgControl(context.Background(), req)
+		_, err := conn.AgentClient.RestorePrimariesPgControl(ctx, req)
 		return err
 	}
 
@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -177,7 +178,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.RsyncPrimaries(agentConns, cluster)
+		err := hub.RsyncPrimaries(context.Background(), agentConns, cluster)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -223,7 +224,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			{AgentClient: standby, Hostname: "standby"},
 		}
 
-		err := hub.RsyncPrimariesTablespaces(agentConns, cluster, tablespaces)
+		err := hub.RsyncPrimariesTablespaces(context.Background(), agentConns, cluster, tablespaces)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -282,7 +283,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "msdw2"},
 		}
 
-		err := hub.RsyncPrimaries(agentConns, cluster)
+		err := hub.RsyncPrimaries(context.Background(), agentConns, cluster)
 
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
@@ -311,7 +312,7 @@ func TestRsyncCoordinatorAndPrimaries(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "msdw2"},
 		}
 
-		err := hub.RsyncPrimariesTablespaces(agentConns, cluster, tablespaces)
+		err := hub.RsyncPrimariesTablespaces(context.Background(), agentConns, cluster, tablespaces)
 
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
@@ -360,7 +361,7 @@ func TestRestoreCoordinatorAndPrimariesPgControl(t *testing.T) {
 			{AgentClient: failedClient, Hostname: "sdw2"},
 		}
 
-		err := hub.RestoreCoordinatorAndPrimariesPgControl(step.DevNullStream, agentConns, cluster)
+		err := hub.RestoreCoordinatorAndPrimariesPgControl(context.Background(), step.DevNullStream, agentConns, cluster)
 
 		var errs errorlist.Errors
 		if !errors.As(err, &errs) {
@@ -429,7 +430,7 @@ func TestRestoreCoordinatorAndPrimariesPgControl(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err = hub.RestoreCoordinatorAndPrimariesPgControl(step.DevNullStream, agentConns, cluster)
+		err = hub.RestoreCoordinatorAndPrimariesPgControl(context.Background(), step.DevNullStream, agentConns, cluster)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
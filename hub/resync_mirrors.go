@@ -0,0 +1,126 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+	"github.com/greenplum-db/gpupgrade/utils/rsync"
+)
+
+// The mirror resync methods used to upgrade the mirrors in link mode.
+const (
+	FullResync        = "full"
+	IncrementalResync = "incremental"
+)
+
+var MirrorResyncMethods = []string{FullResync, IncrementalResync}
+
+// ResyncMirrorDataDirsOnSegments rsyncs each upgraded primary data directory
+// to its mirror, comparing files by checksum against the source mirror data
+// directory. Files that match are hard linked from the source mirror rather
+// than sent, and changed files are sent as deltas against them. It returns the
+// rsync statistics for each mirror.
+func ResyncMirrorDataDirsOnSegments(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) ([]*idl.ResyncStats, error) {
+	var mutex sync.Mutex
+	var stats []*idl.ResyncStats
+
+	request := func(conn *idl.Connection) error {
+		sourcePrimaries := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
+			return seg.IsOnHost(conn.Hostname) && !seg.IsCoordinator() && seg.IsPrimary()
+		})
+
+		var opts []*idl.RsyncRequest_RsyncOptions
+		for _, sourcePrimary := range sourcePrimaries {
+			intermediatePrimary := intermediate.Primaries[sourcePrimary.ContentID]
+			intermediateMirror := intermediate.Mirrors[sourcePrimary.ContentID]
+
+			options := []string{"--archive", "--delete", "--checksum", "--hard-links", "--no-inc-recursive", "--stats"}
+
+			// The source mirror can only seed the upgraded mirror when it is
+			// on the same host.
+			if sourceMirror, ok := source.Mirrors[sourcePrimary.ContentID]; ok && sourceMirror.Hostname == intermediateMirror.Hostname {
+				options = append(options, "--link-dest="+sourceMirror.DataDir)
+			}
+
+			opts = append(opts, &idl.RsyncRequest_RsyncOptions{
+				Sources:         []string{intermediatePrimary.DataDir + "/"},
+				Destination:     intermediateMirror.DataDir,
+				DestinationHost: intermediateMirror.Hostname,
+				Options:         options,
+			})
+		}
+
+		if len(opts) == 0 {
+			return nil
+		}
+
+		reply, err := conn.AgentClient.ResyncMirrorDataDirectories(ctx, &idl.RsyncRequest{Options: opts})
+		if err != nil {
+			return err
+		}
+
+		recordRsyncBytes(conn.Hostname, reply.GetStats())
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		stats = append(stats, reply.GetStats()...)
+
+		return nil
+	}
+
+	err := ExecuteRPC(agentConns, request)
+	return stats, err
+}
+
+// MirrorResyncReport summarizes the data sent to and avoided on each mirror
+// host.
+func MirrorResyncReport(stats []*idl.ResyncStats) string {
+	hosts := make(map[string]*rsync.Stats)
+	var total rsync.Stats
+	for _, s := range stats {
+		host, ok := hosts[s.GetDestinationHost()]
+		if !ok {
+			host = &rsync.Stats{}
+			hosts[s.GetDestinationHost()] = host
+		}
+
+		host.TotalBytes += s.GetTotalBytes()
+		host.LiteralBytes += s.GetLiteralBytes()
+		total.TotalBytes += s.GetTotalBytes()
+		total.LiteralBytes += s.GetLiteralBytes()
+	}
+
+	var names []string
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "Resynced %s of mirror data sending %s and avoiding %s.\n\n",
+		formatBytes(total.TotalBytes), formatBytes(total.LiteralBytes), formatBytes(total.AvoidedBytes()))
+
+	t := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(t, "Mirror Host\tSize\tSent\tAvoided")
+	for _, name := range names {
+		host := hosts[name]
+		fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", name, formatBytes(host.TotalBytes), formatBytes(host.LiteralBytes), formatBytes(host.AvoidedBytes()))
+	}
+	t.Flush()
+
+	return b.String()
+}
+
+func formatBytes(bytes uint64) string {
+	return disk.FormatBytes(bytes / 1024)
+}
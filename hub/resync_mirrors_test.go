@@ -0,0 +1,121 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+)
+
+func TestResyncMirrorDataDirsOnSegments(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg1", Port: 25434, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25435, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast_mirror2/seg2", Port: 25436, Role: greenplum.MirrorRole},
+	})
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50434, Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", Port: 50435, Role: greenplum.MirrorRole},
+		{DbID: 5, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50436, Role: greenplum.PrimaryRole},
+		{DbID: 6, ContentID: 1, Hostname: "sdw3", DataDir: "/data/dbfast_mirror2/seg.HqtFHX54y0o.2", Port: 50437, Role: greenplum.MirrorRole},
+	})
+
+	t.Run("seeds the upgraded mirrors from the source mirrors on the same host", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1Stats := &idl.ResyncStats{DestinationHost: "sdw2", Destination: "/data/dbfast_mirror1/seg.HqtFHX54y0o.1", TotalBytes: 1000, LiteralBytes: 10}
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().ResyncMirrorDataDirectories(
+			gomock.Any(),
+			&idl.RsyncRequest{
+				Options: []*idl.RsyncRequest_RsyncOptions{{
+					Sources:         []string{"/data/dbfast1/seg.HqtFHX54y0o.1/"},
+					Destination:     "/data/dbfast_mirror1/seg.HqtFHX54y0o.1",
+					DestinationHost: "sdw2",
+					Options:         []string{"--archive", "--delete", "--checksum", "--hard-links", "--no-inc-recursive", "--stats", "--link-dest=/data/dbfast_mirror1/seg1"},
+				}},
+			},
+		).Return(&idl.ResyncReply{Stats: []*idl.ResyncStats{sdw1Stats}}, nil)
+
+		// The mirror of content 1 moved hosts so it cannot be seeded.
+		sdw2Stats := &idl.ResyncStats{DestinationHost: "sdw3", Destination: "/data/dbfast_mirror2/seg.HqtFHX54y0o.2", TotalBytes: 1000, LiteralBytes: 1000}
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().ResyncMirrorDataDirectories(
+			gomock.Any(),
+			&idl.RsyncRequest{
+				Options: []*idl.RsyncRequest_RsyncOptions{{
+					Sources:         []string{"/data/dbfast2/seg.HqtFHX54y0o.2/"},
+					Destination:     "/data/dbfast_mirror2/seg.HqtFHX54y0o.2",
+					DestinationHost: "sdw3",
+					Options:         []string{"--archive", "--delete", "--checksum", "--hard-links", "--no-inc-recursive", "--stats"},
+				}},
+			},
+		).Return(&idl.ResyncReply{Stats: []*idl.ResyncStats{sdw2Stats}}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		stats, err := hub.ResyncMirrorDataDirsOnSegments(context.Background(), agentConns, source, intermediate)
+		if err != nil {
+			t.Fatalf("unexpected err %#v", err)
+		}
+
+		if len(stats) != 2 {
+			t.Errorf("got stats %v want those of both mirrors", stats)
+		}
+	})
+
+	t.Run("returns errors when failing on segments", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().ResyncMirrorDataDirectories(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		_, err := hub.ResyncMirrorDataDirsOnSegments(context.Background(), agentConns, source, intermediate)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestMirrorResyncReport(t *testing.T) {
+	stats := []*idl.ResyncStats{
+		{DestinationHost: "sdw2", TotalBytes: 4_000_000_000, LiteralBytes: 1_024_000},
+		{DestinationHost: "sdw1", TotalBytes: 2_048_000_000, LiteralBytes: 2_048_000},
+		{DestinationHost: "sdw2", TotalBytes: 1_024_000_000, LiteralBytes: 0},
+	}
+
+	report := hub.MirrorResyncReport(stats)
+
+	expected := `Resynced 6.906 GB of mirror data sending 3 MB and avoiding 6.903 GB.
+
+Mirror Host  Size      Sent  Avoided
+sdw1         2 GB      2 MB  1.998 GB
+sdw2         4.906 GB  1 MB  4.905 GB
+`
+	if report != expected {
+		t.Errorf("got report %q want %q", report, expected)
+	}
+}
@@ -19,7 +19,7 @@ import (
 var ErrMissingMirrorsAndStandby = errors.New("Source cluster does not have mirrors and/or standby. Cannot restore source cluster. Please contact support.")
 
 func (s *Server) Revert(_ *idl.RevertRequest, stream idl.CliToHub_RevertServer) (err error) {
-	st, err := step.Begin(idl.Step_REVERT, stream, s.AgentConns)
+	st, err := step.Begin(stream.Context(), idl.Step_REVERT, stream, s.AgentConns)
 	if err != nil {
 		return err
 	}
@@ -39,10 +39,48 @@ func (s *Server) Revert(_ *idl.RevertRequest, stream idl.CliToHub_RevertServer)
 		return err
 	}
 
-	if hasExecuteStarted && !s.Source.HasAllMirrorsAndStandby() {
+	// Snapshots taken before the source cluster was upgraded in link mode
+	// restore it without needing its mirrors and standby.
+	restoreFromSnapshots, err := step.HasCompleted(idl.Step_EXECUTE, idl.Substep_SNAPSHOT_SOURCE_CLUSTER)
+	if err != nil {
+		return err
+	}
+
+	if hasExecuteStarted && !s.Source.HasAllMirrorsAndStandby() && !restoreFromSnapshots {
 		return errors.New("Source cluster does not have mirrors and/or standby. Cannot restore source cluster. Please contact support.")
 	}
 
+	// Roll back the finalize substeps that ran before the point of no return,
+	// which the CLI verifies has not been passed, so that the target cluster
+	// is again in its intermediate locations.
+	finalizeStarted, err := step.HasStarted(idl.Step_FINALIZE)
+	if err != nil {
+		return err
+	}
+
+	undo, err := LoadFinalizeUndo(s.StateDir)
+	if err != nil {
+		return err
+	}
+
+	confFilesUpdated, err := step.HasRun(idl.Step_FINALIZE, idl.Substep_UPDATE_TARGET_CONF_FILES)
+	if err != nil {
+		return err
+	}
+
+	st.RunConditionally(idl.Substep_RESTORE_TARGET_CONF_FILES, confFilesUpdated, func(streams step.OutStreams) error {
+		return RestoreConfFiles(step.Context(streams), s.agentConns, undo)
+	})
+
+	dataDirsRenamed, err := step.HasRun(idl.Step_FINALIZE, idl.Substep_UPDATE_DATA_DIRECTORIES)
+	if err != nil {
+		return err
+	}
+
+	st.RunConditionally(idl.Substep_RESTORE_DATA_DIRECTORIES, dataDirsRenamed, func(streams step.OutStreams) error {
+		return RestoreDataDirectories(step.Context(streams), s.agentConns, undo)
+	})
+
 	// If the intermediate target cluster is started, it must be stopped.
 	if s.Intermediate != nil {
 		st.AlwaysRun(idl.Substep_SHUTDOWN_TARGET_CLUSTER, func(streams step.OutStreams) error {
@@ -62,13 +100,26 @@ func (s *Server) Revert(_ *idl.RevertRequest, stream idl.CliToHub_RevertServer)
 	st.RunConditionally(idl.Substep_DELETE_TARGET_CLUSTER_DATADIRS,
 		s.Intermediate.Primaries != nil && s.Intermediate.CoordinatorDataDir() != "",
 		func(streams step.OutStreams) error {
-			return DeleteCoordinatorAndPrimaryDataDirectories(streams, s.agentConns, s.Intermediate)
+			if err := DeleteCoordinatorAndPrimaryDataDirectories(step.Context(streams), streams, s.agentConns, s.Intermediate); err != nil {
+				return err
+			}
+
+			if err := DeleteTransferredSourcePrimaries(step.Context(streams), s.agentConns, s.Source, s.Intermediate); err != nil {
+				return err
+			}
+
+			// Finalize adds the mirrors and standby to the intermediate cluster.
+			if finalizeStarted {
+				return DeleteMirrorAndStandbyDataDirectories(step.Context(streams), s.agentConns, s.Intermediate)
+			}
+
+			return nil
 		})
 
 	st.RunConditionally(idl.Substep_DELETE_TABLESPACES,
 		s.Intermediate.Primaries != nil && s.Intermediate.CoordinatorDataDir() != "",
 		func(streams step.OutStreams) error {
-			return DeleteTargetTablespaces(streams, s.agentConns, s.Config.Intermediate, s.Intermediate.CatalogVersion, s.Source.Tablespaces)
+			return DeleteTargetTablespaces(step.Context(streams), streams, s.agentConns, s.Config.Intermediate, s.Intermediate.CatalogVersion, s.Source.Tablespaces)
 		})
 
 	// For any of the link-mode cases described in the "Reverting to old
@@ -77,8 +128,8 @@ func (s *Server) Revert(_ *idl.RevertRequest, stream idl.CliToHub_RevertServer)
 	// we're going to perform a full rsync restoration, we rely on this
 	// substep to clean up the pg_control.old file, since the rsync will not
 	// remove it.
-	st.RunConditionally(idl.Substep_RESTORE_PGCONTROL, s.LinkMode, func(streams step.OutStreams) error {
-		return RestoreCoordinatorAndPrimariesPgControl(streams, s.agentConns, s.Source)
+	st.RunConditionally(idl.Substep_RESTORE_PGCONTROL, s.Mode == idl.Mode_link && !restoreFromSnapshots, func(streams step.OutStreams) error {
+		return RestoreCoordinatorAndPrimariesPgControl(step.Context(streams), streams, s.agentConns, s.Source)
 	})
 
 	// if the target cluster has been started at any point, we must restore the source
@@ -88,12 +139,27 @@ func (s *Server) Revert(_ *idl.RevertRequest, stream idl.CliToHub_RevertServer)
 		return err
 	}
 
-	st.RunConditionally(idl.Substep_RESTORE_SOURCE_CLUSTER, s.LinkMode && targetStarted, func(stream step.OutStreams) error {
-		if err := RsyncCoordinatorAndPrimaries(stream, s.agentConns, s.Source); err != nil {
+	st.RunConditionally(idl.Substep_RESTORE_SOURCE_CLUSTER, s.Mode == idl.Mode_link && targetStarted && !restoreFromSnapshots, func(stream step.OutStreams) error {
+		if err := RsyncCoordinatorAndPrimaries(step.Context(stream), stream, s.agentConns, s.Source); err != nil {
 			return err
 		}
 
-		return RsyncCoordinatorAndPrimariesTablespaces(stream, s.agentConns, s.Source)
+		return RsyncCoordinatorAndPrimariesTablespaces(step.Context(stream), stream, s.agentConns, s.Source)
+	})
+
+	// Restoring from the snapshots also removes the pg_control.old files and
+	// any target files left in the source tablespaces.
+	st.RunConditionally(idl.Substep_RESTORE_SOURCE_SNAPSHOTS, restoreFromSnapshots, func(streams step.OutStreams) error {
+		return RestoreSourceSnapshots(step.Context(streams), s.agentConns, s.Source, s.StateDir)
+	})
+
+	snapshotsTaken, err := step.HasRun(idl.Step_EXECUTE, idl.Substep_SNAPSHOT_SOURCE_CLUSTER)
+	if err != nil {
+		return err
+	}
+
+	st.RunConditionally(idl.Substep_DELETE_SOURCE_SNAPSHOTS, snapshotsTaken, func(streams step.OutStreams) error {
+		return DeleteSourceSnapshots(step.Context(streams), s.agentConns, s.Source, s.StateDir)
 	})
 
 	handleMirrorStartupFailure, err := s.expectMirrorFailure()
@@ -137,23 +203,24 @@ func (s *Server) Revert(_ *idl.RevertRequest, stream idl.CliToHub_RevertServer)
 	})
 
 	var logArchiveDir string
-	st.Run(idl.Substep_ARCHIVE_LOG_DIRECTORIES, func(_ step.OutStreams) error {
+	st.Run(idl.Substep_ARCHIVE_LOG_DIRECTORIES, func(streams step.OutStreams) error {
 		logArchiveDir, err = s.GetLogArchiveDir()
 		if err != nil {
 			return xerrors.Errorf("get log archive directory: %w", err)
 		}
 
-		return ArchiveLogDirectories(logArchiveDir, s.agentConns, s.Config.Source.CoordinatorHostname())
+		return ArchiveLogDirectories(step.Context(streams), logArchiveDir, s.agentConns, s.Config.Source.CoordinatorHostname())
 	})
 
-	st.Run(idl.Substep_DELETE_SEGMENT_STATEDIRS, func(_ step.OutStreams) error {
-		return DeleteStateDirectories(s.agentConns, s.Source.CoordinatorHostname())
+	st.Run(idl.Substep_DELETE_SEGMENT_STATEDIRS, func(streams step.OutStreams) error {
+		return DeleteStateDirectories(step.Context(streams), s.agentConns, s.Source.CoordinatorHostname())
 	})
 
 	message := &idl.Message{Contents: &idl.Message_Response{Response: &idl.Response{Contents: &idl.Response_RevertResponse{
 		RevertResponse: &idl.RevertResponse{
 			SourceVersion:       s.Source.Version.String(),
 			LogArchiveDirectory: logArchiveDir,
+			Backup:              BackupMessage(s.Backup),
 			Source: &idl.Cluster{
 				Port:                     int32(s.Source.CoordinatorPort()),
 				CoordinatorDataDirectory: s.Source.CoordinatorDataDir(),
@@ -173,22 +240,34 @@ func (s *Server) Revert(_ *idl.RevertRequest, stream idl.CliToHub_RevertServer)
 // - Rsync from the corresponding mirrors
 // or
 // - Running recoverseg
-// If the former hasn't run yet, then we do expect mirror failure upon start, so return true.
+// If the former hasn't run yet, and the primaries were not restored from
+// snapshots, then we do expect mirror failure upon start, so return true.
 func (s *Server) expectMirrorFailure() (bool, error) {
 	// mirror startup failure is expected only for GPDB 5x
 	if s.Source.Version.Major != 5 {
 		return false, nil
 	}
 
+	// In clone mode pg_upgrade runs on clones of the primaries, so the
+	// primaries themselves are never started by it.
+	if s.Mode == idl.Mode_clone {
+		return false, nil
+	}
+
 	hasRestoreRun, err := step.HasRun(idl.Step_REVERT, idl.Substep_RESTORE_SOURCE_CLUSTER)
 	if err != nil {
 		return false, err
 	}
 
+	hasSnapshotRestoreRun, err := step.HasRun(idl.Step_REVERT, idl.Substep_RESTORE_SOURCE_SNAPSHOTS)
+	if err != nil {
+		return false, err
+	}
+
 	primariesUpgraded, err := step.HasRun(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
 	if err != nil {
 		return false, err
 	}
 
-	return !hasRestoreRun && primariesUpgraded, nil
+	return !hasRestoreRun && !hasSnapshotRestoreRun && primariesUpgraded, nil
 }
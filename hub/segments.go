@@ -0,0 +1,68 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// Segments returns the upgrade status of each primary segment of the target
+// cluster.
+func (s *Server) Segments(ctx context.Context, in *idl.SegmentsRequest) (*idl.SegmentsReply, error) {
+	if s.Intermediate == nil || len(s.Intermediate.Primaries) == 0 {
+		return nil, errors.New(`The target cluster has not been initialized. Run "gpupgrade initialize" first.`)
+	}
+
+	store, err := step.NewSegmentFileStore()
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := SegmentStatuses(s.Intermediate, store)
+	if err != nil {
+		return nil, err
+	}
+
+	return &idl.SegmentsReply{Segments: segments}, nil
+}
+
+// SegmentStatuses returns the status of upgrading each primary segment ordered
+// by content id. The pg_upgrade directory is on the segment host.
+func SegmentStatuses(intermediate *greenplum.Cluster, store *step.SegmentFileStore) ([]*idl.SegmentUpgradeStatus, error) {
+	statuses, err := store.Read(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments []*idl.SegmentUpgradeStatus
+	for content, seg := range intermediate.Primaries.ExcludingCoordinator() {
+		pgUpgradeDir, err := utils.GetPgUpgradeDir(greenplum.PrimaryRole, int32(content))
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, &idl.SegmentUpgradeStatus{
+			ContentID:    int32(content),
+			DbID:         int32(seg.DbID),
+			Hostname:     seg.Hostname,
+			DataDir:      seg.DataDir,
+			Status:       statuses[seg.DbID].Status,
+			PgUpgradeDir: pgUpgradeDir,
+			Error:        statuses[seg.DbID].Error,
+		})
+	}
+
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].GetContentID() < segments[j].GetContentID()
+	})
+
+	return segments, nil
+}
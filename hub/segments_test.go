@@ -0,0 +1,61 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestSegmentStatuses(t *testing.T) {
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	path := filepath.Join(stateDir, step.SegmentsFileName)
+	testutils.MustWriteToFile(t, path, "{}")
+	store := step.NewSegmentStoreUsingFile(path)
+
+	err := store.Write(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES, 4, step.SegmentStatus{Status: idl.Status_FAILED, Error: "disk full"})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 2, Hostname: "sdw1", DataDir: "/data/dbfast3/seg3", Role: greenplum.PrimaryRole},
+	})
+
+	segments, err := hub.SegmentStatuses(intermediate, store)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	pgUpgradeDir := func(content int32) string {
+		dir, err := utils.GetPgUpgradeDir(greenplum.PrimaryRole, content)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		return dir
+	}
+
+	expected := []*idl.SegmentUpgradeStatus{
+		{ContentID: 0, DbID: 2, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", PgUpgradeDir: pgUpgradeDir(0)},
+		{ContentID: 1, DbID: 3, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", PgUpgradeDir: pgUpgradeDir(1)},
+		{ContentID: 2, DbID: 4, Hostname: "sdw1", DataDir: "/data/dbfast3/seg3", Status: idl.Status_FAILED, PgUpgradeDir: pgUpgradeDir(2), Error: "disk full"},
+	}
+	if !reflect.DeepEqual(segments, expected) {
+		t.Errorf("got %v want %v", segments, expected)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -18,6 +19,7 @@ import (
 	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/kballard/go-shellquote"
 	"github.com/pkg/errors"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc"
@@ -28,11 +30,15 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/upgrade"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/backup"
 	"github.com/greenplum-db/gpupgrade/utils/daemon"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 var DialTimeout = 3 * time.Second
@@ -50,9 +56,12 @@ type Server struct {
 	agentConns []*idl.Connection
 	grpcDialer Dialer
 
-	mu     sync.Mutex
-	server *grpc.Server
-	lis    net.Listener
+	mu            sync.Mutex
+	server        *grpc.Server
+	lis           net.Listener
+	metricsServer *http.Server
+	notifier      *notify.Notifier
+	notifications *notificationObserver
 
 	// This is used both as a channel to communicate from Start() to
 	// Stop() to indicate to Stop() that it can finally terminate
@@ -93,9 +102,9 @@ func (s *Server) Start() error {
 	// handlers.
 	interceptor := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
 		defer log.WritePanics()
-		return handler(ctx, req)
+		return trace.UnaryServerInterceptor(ctx, req, info, handler)
 	}
-	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor))
+	server := grpc.NewServer(grpc.UnaryInterceptor(interceptor), grpc.StreamInterceptor(trace.StreamServerInterceptor))
 
 	s.mu.Lock()
 	if s.stopped == nil {
@@ -110,6 +119,38 @@ func (s *Server) Start() error {
 	idl.RegisterCliToHubServer(server, s)
 	reflection.Register(server)
 
+	if s.MetricsPort != 0 {
+		step.AddObserver(metricsObserver{})
+		Metrics.OnCollect(s.collectAgentConnectionStates)
+
+		if err := s.startMetrics(); err != nil {
+			lis.Close()
+			return err
+		}
+	}
+
+	if s.Tracing.Enabled() {
+		if err := s.startTracing(); err != nil {
+			lis.Close()
+			return err
+		}
+	}
+
+	if s.Notifications.Enabled() {
+		n := notify.New(s.Notifications)
+		observer := newNotificationObserver(n, s.Config)
+		s.mu.Lock()
+		s.notifier = n
+		s.notifications = observer
+		s.mu.Unlock()
+
+		step.AddObserver(observer)
+	}
+
+	if s.LogFormat == log.JSON {
+		step.AddObserver(logObserver{config: s.Config})
+	}
+
 	if s.daemon {
 		fmt.Printf("Hub started on port %d (pid %d)\n", s.Port, os.Getpid())
 		daemon.Daemonize()
@@ -173,18 +214,35 @@ func (s *Server) Stop(closeAgentConns bool) {
 		s.closeAgentConns()
 	}
 
+	if s.metricsServer != nil {
+		if err := s.metricsServer.Close(); err != nil {
+			gplog.Debug("failed to stop metrics server: %#v", err)
+		}
+	}
+
 	if s.server != nil {
 		s.server.Stop()
 		<-s.stopped // block until it is OK to stop
 	}
 
+	if err := trace.Shutdown(); err != nil {
+		gplog.Debug("failed to export remaining spans: %#v", err)
+	}
+
+	if s.notifier != nil {
+		step.RemoveObserver(s.notifications)
+		s.notifier.Close()
+		s.notifier = nil
+		s.notifications = nil
+	}
+
 	// Mark this server stopped so that a concurrent Start() doesn't try to
 	// start things up again.
 	s.stopped = nil
 }
 
 func (s *Server) RestartAgents(ctx context.Context, in *idl.RestartAgentsRequest) (*idl.RestartAgentsReply, error) {
-	restartedHosts, err := RestartAgents(ctx, nil, AgentHosts(s.Source), s.AgentPort, s.StateDir)
+	restartedHosts, err := RestartAgents(ctx, nil, s.AgentHosts(), s.AgentPort, s.StateDir, s.Tracing, s.LogFormat)
 	if err != nil {
 		return &idl.RestartAgentsReply{}, err
 	}
@@ -201,7 +259,9 @@ func RestartAgents(ctx context.Context,
 	dialer func(context.Context, string) (net.Conn, error),
 	hostnames []string,
 	port int,
-	stateDir string) ([]string, error) {
+	stateDir string,
+	tracing trace.Config,
+	logFormat string) ([]string, error) {
 
 	var wg sync.WaitGroup
 	restartedHosts := make(chan string, len(hostnames))
@@ -240,8 +300,16 @@ func RestartAgents(ctx context.Context,
 				errs <- err
 				return
 			}
+			args := tracing.Args()
+			if logFormat == log.JSON {
+				args = append(args, "--log-format", log.JSON)
+			}
+			agentArgs := ""
+			if len(args) > 0 {
+				agentArgs = " " + shellquote.Join(args...)
+			}
 			cmd := ExecCommand("ssh", host,
-				fmt.Sprintf("bash -c \"%s agent --daemonize --port %d --state-directory %s\"", path, port, stateDir))
+				fmt.Sprintf("bash -c \"%s agent --daemonize --port %d --state-directory %s%s\"", path, port, stateDir, agentArgs))
 			stdout, err := cmd.Output()
 			if err != nil {
 				errs <- err
@@ -270,6 +338,12 @@ func RestartAgents(ctx context.Context,
 	return hosts, err
 }
 
+// agentInterceptor sends the log fields and trace context of the hub with each
+// request to an agent.
+func agentInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return trace.UnaryClientInterceptor(log.OutgoingContext(ctx), method, req, reply, cc, invoker, opts...)
+}
+
 func (s *Server) AgentConns() ([]*idl.Connection, error) {
 	// Lock the mutex to protect against races with Server.Stop().
 	// XXX This is a *ridiculously* broad lock. Have fun waiting for the dial
@@ -289,12 +363,14 @@ func (s *Server) AgentConns() ([]*idl.Connection, error) {
 		return s.agentConns, nil
 	}
 
-	hostnames := AgentHosts(s.Source)
+	hostnames := s.AgentHosts()
 	for _, host := range hostnames {
 		ctx, cancelFunc := context.WithTimeout(context.Background(), DialTimeout)
 		conn, err := s.grpcDialer(ctx,
 			host+":"+strconv.Itoa(s.AgentPort),
-			grpc.WithInsecure(), grpc.WithBlock())
+			grpc.WithInsecure(), grpc.WithBlock(),
+			grpc.WithUnaryInterceptor(agentInterceptor),
+			grpc.WithStreamInterceptor(trace.StreamClientInterceptor))
 		if err != nil {
 			err = xerrors.Errorf("grpcDialer failed: %w", err)
 			gplog.Error(err.Error())
@@ -368,9 +444,41 @@ type Config struct {
 
 	Port            int
 	AgentPort       int
-	LinkMode        bool
+	MetricsPort     int // serves the hub metrics on /metrics unless zero
+	Mode            idl.Mode
 	UseHbaHostnames bool
 	UpgradeID       upgrade.ID
+
+	// Tracing is how the hub and agents export the spans of the upgrade.
+	Tracing trace.Config
+
+	// Notifications is where the hub sends notifications of step events.
+	Notifications notify.Config
+
+	// LogFormat is how the hub and agents write their log files. It is either
+	// log.Text or log.JSON.
+	LogFormat string
+
+	// SnapshotProvider snapshots the source cluster before it is upgraded in
+	// link mode so that revert can restore it from the snapshots. It is empty
+	// or "none" when snapshots are not taken.
+	SnapshotProvider string
+
+	// MirrorResync is how the mirrors are upgraded in link mode. It is either
+	// FullResync or IncrementalResync.
+	MirrorResync string
+
+	// BackupProvider backs up the source cluster during execute before it is
+	// shut down. It is empty or "none" when no backup is taken. BackupCommand
+	// is the template run by the command provider, and BackupTimestamp
+	// identifies an existing backup to verify rather than taking a new one.
+	BackupProvider  string
+	BackupCommand   string
+	BackupTimestamp string
+
+	// Backup is the backup of the source cluster registered for the upgrade.
+	// It is nil when no backup has been registered.
+	Backup *backup.Backup
 }
 
 func (c *Config) Load(r io.Reader) error {
@@ -428,6 +536,31 @@ func LoadConfig(conf *Config, path string) error {
 	return nil
 }
 
+// AgentHosts returns the hosts of the source and intermediate clusters that
+// run agents. They differ when the upgrade moves segments onto new hosts.
+func (c *Config) AgentHosts() []string {
+	hosts := AgentHosts(c.Source)
+	if c.Intermediate == nil {
+		return hosts
+	}
+
+	for _, host := range AgentHosts(c.Intermediate) {
+		found := false
+		for _, existing := range hosts {
+			if existing == host {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			hosts = append(hosts, host)
+		}
+	}
+
+	return hosts
+}
+
 func AgentHosts(c *greenplum.Cluster) []string {
 	uniqueHosts := make(map[string]bool)
 
@@ -7,10 +7,15 @@ import (
 	"bytes"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/backup"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 func TestConfig(t *testing.T) {
@@ -18,6 +23,8 @@ func TestConfig(t *testing.T) {
 	t.Run("saves itself to the provided stream", func(t *testing.T) {
 		source, target := testutils.CreateMultinodeSampleClusterPair("/tmp")
 		intermediate := source
+		tracing := trace.Config{Exporter: trace.OTLP, Endpoint: "http://collector:4318"}
+		notifications := notify.Config{Webhook: "https://hooks.example.com/gpupgrade", Email: []string{"dba@example.com"}, SMTP: "localhost:25"}
 
 		// NOTE: we explicitly do not name the struct members here, to ensure
 		// that the test fails to compile if you add new members to Config but
@@ -29,11 +36,21 @@ func TestConfig(t *testing.T) {
 			intermediate,
 			target,
 			&greenplum.Conn{},
-			12345,           // Port
-			54321,           // AgentPort
-			false,           // LinkMode
-			false,           // UseHbaHostnames
-			upgrade.NewID(), // UpgradeID
+			12345,             // Port
+			54321,             // AgentPort
+			9187,              // MetricsPort
+			idl.Mode_link,     // Mode
+			false,             // UseHbaHostnames
+			upgrade.NewID(),   // UpgradeID
+			tracing,           // Tracing
+			notifications,     // Notifications
+			"json",            // LogFormat
+			"zfs",             // SnapshotProvider
+			IncrementalResync, // MirrorResync
+			backup.GPBackup,   // BackupProvider
+			"",                // BackupCommand
+			"20220101120000",  // BackupTimestamp
+			&backup.Backup{Provider: backup.GPBackup, ID: "20220101120000", Time: time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)}, // Backup
 		}
 
 		buf := new(bytes.Buffer)
@@ -0,0 +1,140 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"sort"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/snapshot"
+)
+
+// SnapshotName returns the name of the source cluster snapshots, which is
+// unique to the upgrade.
+func SnapshotName(id upgrade.ID) string {
+	return "gpupgrade-" + id.String()
+}
+
+// UseSnapshots returns whether the source cluster is snapshotted before it is
+// upgraded. Snapshots are only needed in link mode since copy and clone modes
+// do not modify the source cluster.
+func UseSnapshots(mode idl.Mode, provider string) bool {
+	return mode == idl.Mode_link && provider != "" && provider != snapshot.None
+}
+
+// SnapshotDirectories returns the data directories and user defined
+// tablespace locations of the coordinator and primaries on each host.
+func SnapshotDirectories(source *greenplum.Cluster) map[string][]string {
+	dirs := make(map[string][]string)
+
+	segments := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
+		return seg.IsCoordinator() || seg.IsPrimary()
+	})
+
+	for _, seg := range segments {
+		dirs[seg.Hostname] = append(dirs[seg.Hostname], seg.DataDir)
+		dirs[seg.Hostname] = append(dirs[seg.Hostname], source.Tablespaces[seg.DbID].UserDefinedTablespacesLocations()...)
+	}
+
+	for host := range dirs {
+		sort.Strings(dirs[host])
+	}
+
+	return dirs
+}
+
+// SnapshotSourceCluster snapshots the coordinator and primary data directories
+// and tablespaces of the stopped source cluster.
+func SnapshotSourceCluster(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, stateDir string, provider string, name string) error {
+	dirs := SnapshotDirectories(source)
+
+	local := func() error {
+		return snapshot.Take(ctx, stateDir, provider, name, dirs[source.CoordinatorHostname()])
+	}
+
+	request := func(conn *idl.Connection) error {
+		hostDirs, ok := dirs[conn.Hostname]
+		if !ok {
+			return nil
+		}
+
+		req := &idl.CreateSnapshotsRequest{Provider: provider, Name: name, Dirs: hostDirs}
+		_, err := conn.AgentClient.CreateSnapshots(ctx, req)
+		if err != nil {
+			return xerrors.Errorf("snapshotting on host %s: %w", conn.Hostname, err)
+		}
+
+		return nil
+	}
+
+	return onCoordinatorAndAgents(agentConns, source.CoordinatorHostname(), local, request)
+}
+
+// RestoreSourceSnapshots restores the coordinator and primary data directories
+// and tablespaces of the source cluster from their snapshots.
+func RestoreSourceSnapshots(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, stateDir string) error {
+	dirs := SnapshotDirectories(source)
+
+	local := func() error {
+		return snapshot.RestoreAll(ctx, stateDir)
+	}
+
+	request := func(conn *idl.Connection) error {
+		if _, ok := dirs[conn.Hostname]; !ok {
+			return nil
+		}
+
+		_, err := conn.AgentClient.RestoreSnapshots(ctx, &idl.RestoreSnapshotsRequest{})
+		if err != nil {
+			return xerrors.Errorf("restoring snapshots on host %s: %w", conn.Hostname, err)
+		}
+
+		return nil
+	}
+
+	return onCoordinatorAndAgents(agentConns, source.CoordinatorHostname(), local, request)
+}
+
+// DeleteSourceSnapshots deletes the snapshots of the source cluster on all
+// hosts to release the space they hold.
+func DeleteSourceSnapshots(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, stateDir string) error {
+	local := func() error {
+		return snapshot.DeleteAll(ctx, stateDir)
+	}
+
+	request := func(conn *idl.Connection) error {
+		_, err := conn.AgentClient.DeleteSnapshots(ctx, &idl.DeleteSnapshotsRequest{})
+		if err != nil {
+			return xerrors.Errorf("deleting snapshots on host %s: %w", conn.Hostname, err)
+		}
+
+		return nil
+	}
+
+	return onCoordinatorAndAgents(agentConns, source.CoordinatorHostname(), local, request)
+}
+
+// onCoordinatorAndAgents executes request on every agent, and runs local on
+// the hub when no agent runs on the coordinator host.
+func onCoordinatorAndAgents(agentConns []*idl.Connection, coordinatorHost string, local func() error, request func(conn *idl.Connection) error) error {
+	coordinatorHasAgent := false
+	for _, conn := range agentConns {
+		if conn.Hostname == coordinatorHost {
+			coordinatorHasAgent = true
+		}
+	}
+
+	if !coordinatorHasAgent {
+		if err := local(); err != nil {
+			return err
+		}
+	}
+
+	return ExecuteRPC(agentConns, request)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+func TestSnapshotSourceCluster(t *testing.T) {
+	testlog.SetupLogger()
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: -1, Hostname: "smdw", DataDir: "/data/standby", Role: greenplum.MirrorRole},
+		{DbID: 3, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg0", Role: greenplum.PrimaryRole},
+		{DbID: 4, ContentID: 1, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+		{DbID: 5, ContentID: 0, Hostname: "sdw2", DataDir: "/data/dbfast_mirror1/seg0", Role: greenplum.MirrorRole},
+	})
+	source.Tablespaces = greenplum.Tablespaces{
+		1: {16386: {Location: "/tablespaces/1/16386", UserDefined: 1}, 1663: {Location: "/data/qddir/seg-1", UserDefined: 0}},
+		3: {16386: {Location: "/tablespaces/3/16386", UserDefined: 1}},
+	}
+
+	t.Run("SnapshotDirectories returns the coordinator and primary directories per host", func(t *testing.T) {
+		expected := map[string][]string{
+			"mdw":  {"/data/qddir/seg-1", "/tablespaces/1/16386"},
+			"sdw1": {"/data/dbfast1/seg0", "/data/dbfast1/seg1", "/tablespaces/3/16386"},
+		}
+
+		dirs := hub.SnapshotDirectories(source)
+		if !reflect.DeepEqual(dirs, expected) {
+			t.Errorf("got %v want %v", dirs, expected)
+		}
+	})
+
+	t.Run("snapshots the directories on each host with a primary", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mdw := mock_idl.NewMockAgentClient(ctrl)
+		mdw.EXPECT().CreateSnapshots(gomock.Any(), &idl.CreateSnapshotsRequest{
+			Provider: "zfs",
+			Name:     "gpupgrade-abc",
+			Dirs:     []string{"/data/qddir/seg-1", "/tablespaces/1/16386"},
+		}).Return(&idl.CreateSnapshotsReply{}, nil)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().CreateSnapshots(gomock.Any(), &idl.CreateSnapshotsRequest{
+			Provider: "zfs",
+			Name:     "gpupgrade-abc",
+			Dirs:     []string{"/data/dbfast1/seg0", "/data/dbfast1/seg1", "/tablespaces/3/16386"},
+		}).Return(&idl.CreateSnapshotsReply{}, nil)
+
+		// NOTE: we expect no call to the mirror only hosts
+		smdw := mock_idl.NewMockAgentClient(ctrl)
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: mdw, Hostname: "mdw"},
+			{AgentClient: smdw, Hostname: "smdw"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.SnapshotSourceCluster(context.Background(), agentConns, source, "/state", "zfs", "gpupgrade-abc")
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("returns the error from restoring snapshots", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mdw := mock_idl.NewMockAgentClient(ctrl)
+		mdw.EXPECT().RestoreSnapshots(gomock.Any(), gomock.Any()).Return(&idl.RestoreSnapshotsReply{}, nil)
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().RestoreSnapshots(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: mdw, Hostname: "mdw"},
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: mock_idl.NewMockAgentClient(ctrl), Hostname: "sdw2"},
+		}
+
+		err := hub.RestoreSourceSnapshots(context.Background(), agentConns, source, "/state")
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("deletes snapshots on all hosts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var agentConns []*idl.Connection
+		for _, host := range []string{"mdw", "smdw", "sdw1", "sdw2"} {
+			client := mock_idl.NewMockAgentClient(ctrl)
+			client.EXPECT().DeleteSnapshots(gomock.Any(), &idl.DeleteSnapshotsRequest{}).Return(&idl.DeleteSnapshotsReply{}, nil)
+			agentConns = append(agentConns, &idl.Connection{AgentClient: client, Hostname: host})
+		}
+
+		err := hub.DeleteSourceSnapshots(context.Background(), agentConns, source, "/state")
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+	})
+}
+
+func TestUseSnapshots(t *testing.T) {
+	cases := []struct {
+		mode     idl.Mode
+		provider string
+		expected bool
+	}{
+		{idl.Mode_link, "zfs", true},
+		{idl.Mode_link, "none", false},
+		{idl.Mode_link, "", false},
+		{idl.Mode_copy, "zfs", false},
+		{idl.Mode_clone, "zfs", false},
+	}
+
+	for _, c := range cases {
+		if actual := hub.UseSnapshots(c.mode, c.provider); actual != c.expected {
+			t.Errorf("UseSnapshots(%s, %q) = %t want %t", c.mode, c.provider, actual, c.expected)
+		}
+	}
+
+	name := hub.SnapshotName(upgrade.ID(0))
+	if name != "gpupgrade-"+upgrade.ID(0).String() {
+		t.Errorf("got snapshot name %q", name)
+	}
+}
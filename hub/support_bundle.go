@@ -0,0 +1,202 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/bundle"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/rsync"
+)
+
+func (s *Server) SupportBundle(ctx context.Context, in *idl.SupportBundleRequest) (*idl.SupportBundleReply, error) {
+	// Collect what we can from the coordinator even when the agents cannot
+	// be reached.
+	agentConns, err := s.AgentConns()
+	if err != nil {
+		gplog.Warn("collecting the support bundle without agents: %v", err)
+	}
+
+	return CreateSupportBundle(ctx, agentConns, s.Source, s.Intermediate, s.StateDir, in)
+}
+
+// CreateSupportBundle collects the state, logs, and facts of the coordinator
+// host and of each agent host into a single tarball in the requested
+// directory. Hosts that fail are listed in the manifest and reply rather than
+// failing the bundle.
+func CreateSupportBundle(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, stateDir string, in *idl.SupportBundleRequest) (_ *idl.SupportBundleReply, err error) {
+	dataDirs, gphomes := supportBundleDirs(source, intermediate)
+
+	var hostnames []string
+	for host := range dataDirs {
+		hostnames = append(hostnames, host)
+	}
+	sort.Strings(hostnames)
+
+	redactor := bundle.Redactor{Passwords: in.GetRedactPasswords()}
+	if in.GetRedactHostnames() {
+		redactor.Hostnames = bundle.HostnameAliases(hostnames)
+	}
+
+	staging, err := ioutil.TempDir("", "gpupgrade-support-bundle")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if rErr := os.RemoveAll(staging); rErr != nil {
+			err = errorlist.Append(err, rErr)
+		}
+	}()
+
+	coordinatorHost := source.CoordinatorHostname()
+	logDir, err := utils.GetLogDir()
+	if err != nil {
+		return nil, err
+	}
+
+	coordinatorBundle := filepath.Join(staging, coordinatorHost+".tar.gz")
+	err = bundle.WriteFile(coordinatorBundle, coordinatorHost,
+		bundle.HostSources(stateDir, logDir, dataDirs[coordinatorHost]),
+		bundle.HostFacts(gphomes, dataDirs[coordinatorHost]),
+		redactor)
+	if err != nil {
+		return nil, xerrors.Errorf("collecting support bundle on coordinator host %s: %w", coordinatorHost, err)
+	}
+
+	var mutex sync.Mutex
+	hostBundles := []string{coordinatorBundle}
+	var hostErrs []string
+
+	request := func(conn *idl.Connection) error {
+		// The coordinator host was collected by the hub.
+		if conn.Hostname == coordinatorHost {
+			return nil
+		}
+
+		hostBundle, err := collectHostSupportBundle(ctx, conn, staging, dataDirs[conn.Hostname], gphomes, redactor)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if err != nil {
+			gplog.Error("collecting support bundle on host %s: %v", conn.Hostname, err)
+			hostErrs = append(hostErrs, redactor.RedactName(fmt.Sprintf("%s: %v", conn.Hostname, err)))
+			return nil
+		}
+
+		hostBundles = append(hostBundles, hostBundle)
+		return nil
+	}
+
+	if err := ExecuteRPC(agentConns, request); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(hostBundles)
+	sort.Strings(hostErrs)
+
+	var hosts []string
+	for _, host := range hostnames {
+		hosts = append(hosts, redactor.RedactName(host))
+	}
+
+	manifest := bundle.Manifest{
+		Version:           in.GetVersion(),
+		Created:           time.Now(),
+		RedactedPasswords: in.GetRedactPasswords(),
+		RedactedHostnames: in.GetRedactHostnames(),
+		Hosts:             hosts,
+		Errors:            hostErrs,
+	}
+
+	path := filepath.Join(in.GetDir(), fmt.Sprintf("gpupgrade-support-bundle-%s.tar.gz", manifest.Created.Format("20060102T150405")))
+	if err := writeSupportBundle(path, hostBundles, manifest); err != nil {
+		return nil, err
+	}
+
+	return &idl.SupportBundleReply{Path: path, Errors: hostErrs}, nil
+}
+
+func collectHostSupportBundle(ctx context.Context, conn *idl.Connection, staging string, dataDirs []string, gphomes []string, redactor bundle.Redactor) (string, error) {
+	req := &idl.CreateSupportBundleRequest{
+		DataDirs:        dataDirs,
+		Gphomes:         gphomes,
+		RedactPasswords: redactor.Passwords,
+		HostnameAliases: redactor.Hostnames,
+	}
+
+	reply, err := conn.AgentClient.CreateSupportBundle(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	hostBundle := filepath.Join(staging, conn.Hostname+".tar.gz")
+	err = rsync.Rsync(
+		rsync.WithContext(ctx),
+		rsync.WithSourceHost(conn.Hostname),
+		rsync.WithSources(reply.GetPath()),
+		rsync.WithDestination(hostBundle),
+		rsync.WithOptions("--archive"),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return hostBundle, nil
+}
+
+func writeSupportBundle(path string, hostBundles []string, manifest bundle.Manifest) (err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := f.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	return bundle.Merge(f, hostBundles, manifest)
+}
+
+// supportBundleDirs returns the data directories of the source and
+// intermediate clusters on each host, and their GPHOMEs.
+func supportBundleDirs(source *greenplum.Cluster, intermediate *greenplum.Cluster) (map[string][]string, []string) {
+	dataDirs := make(map[string][]string)
+	var gphomes []string
+
+	for _, cluster := range []*greenplum.Cluster{source, intermediate} {
+		if cluster == nil {
+			continue
+		}
+
+		for _, seg := range cluster.SelectSegments(func(*greenplum.SegConfig) bool { return true }) {
+			dataDirs[seg.Hostname] = append(dataDirs[seg.Hostname], seg.DataDir)
+		}
+
+		if cluster.GPHome != "" && (len(gphomes) == 0 || gphomes[0] != cluster.GPHome) {
+			gphomes = append(gphomes, cluster.GPHome)
+		}
+	}
+
+	for host := range dataDirs {
+		sort.Strings(dataDirs[host])
+	}
+
+	return dataDirs, gphomes
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/bundle"
+	"github.com/greenplum-db/gpupgrade/utils/rsync"
+)
+
+// RsyncHostBundle writes a host bundle with a single log to the rsync
+// destination.
+func RsyncHostBundle() {
+	f, err := os.Create(os.Args[len(os.Args)-1])
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	contents := []byte("agent log")
+	if err := tw.WriteHeader(&tar.Header{Name: "sdw1/gpupgrade_logs/agent.log", Mode: 0600, Size: int64(len(contents))}); err != nil {
+		panic(err)
+	}
+
+	if _, err := tw.Write(contents); err != nil {
+		panic(err)
+	}
+}
+
+func init() {
+	exectest.RegisterMains(
+		RsyncHostBundle,
+	)
+}
+
+func TestCreateSupportBundle(t *testing.T) {
+	testlog.SetupLogger()
+
+	home := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, home)
+
+	utils.System.Current = func() (*user.User, error) {
+		return &user.User{Username: "gpadmin", HomeDir: home}, nil
+	}
+	defer func() {
+		utils.System.Current = user.Current
+	}()
+
+	logDir, err := utils.GetLogDir()
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	testutils.MustCreateDir(t, logDir)
+	testutils.MustWriteToFile(t, filepath.Join(logDir, "hub.log"), "hub log on mdw")
+
+	stateDir := filepath.Join(home, ".gpupgrade")
+	testutils.MustCreateDir(t, stateDir)
+
+	bundle.SetFactCommand(exectest.NewCommand(hub.Success))
+	defer bundle.ResetFactCommand()
+
+	var rsyncCalls [][]string
+	rsync.SetRsyncCommand(exectest.NewCommandWithVerifier(RsyncHostBundle, func(name string, args ...string) {
+		rsyncCalls = append(rsyncCalls, args)
+	}))
+	defer rsync.ResetRsyncCommand()
+
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "mdw", DataDir: "/data/qddir/seg-1", Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Role: greenplum.PrimaryRole},
+	})
+	source.GPHome = "/usr/local/gpdb6"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	sdw1 := mock_idl.NewMockAgentClient(ctrl)
+	sdw1.EXPECT().CreateSupportBundle(
+		gomock.Any(),
+		&idl.CreateSupportBundleRequest{
+			DataDirs:        []string{"/data/dbfast1/seg1"},
+			Gphomes:         []string{"/usr/local/gpdb6"},
+			RedactPasswords: true,
+			HostnameAliases: map[string]string{"mdw": "host1", "sdw1": "host2", "sdw2": "host3"},
+		},
+	).Return(&idl.CreateSupportBundleReply{Path: "/home/gpadmin/.gpupgrade/support-bundle.tar.gz"}, nil)
+
+	sdw2 := mock_idl.NewMockAgentClient(ctrl)
+	sdw2.EXPECT().CreateSupportBundle(gomock.Any(), gomock.Any()).Return(nil, errors.New("sdw2 is unreachable"))
+
+	agentConns := []*idl.Connection{
+		{AgentClient: sdw1, Hostname: "sdw1"},
+		{AgentClient: sdw2, Hostname: "sdw2"},
+	}
+
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	req := &idl.SupportBundleRequest{Dir: dir, RedactPasswords: true, RedactHostnames: true, Version: "1.2.3"}
+	reply, err := hub.CreateSupportBundle(context.Background(), agentConns, source, nil, stateDir, req)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expectedErrs := []string{"host3: host3 is unreachable"}
+	if !reflect.DeepEqual(reply.GetErrors(), expectedErrs) {
+		t.Errorf("got errors %q want %q", reply.GetErrors(), expectedErrs)
+	}
+
+	if len(rsyncCalls) != 1 || rsyncCalls[0][len(rsyncCalls[0])-2] != "sdw1:/home/gpadmin/.gpupgrade/support-bundle.tar.gz" {
+		t.Errorf("got rsync calls %q want one from sdw1", rsyncCalls)
+	}
+
+	if filepath.Dir(reply.GetPath()) != dir {
+		t.Errorf("got path %q want it in %q", reply.GetPath(), dir)
+	}
+
+	contents := readBundle(t, reply.GetPath())
+	if contents["host1/gpupgrade_logs/hub.log"] != "hub log on host1" {
+		t.Errorf("got %v want the redacted hub log", contents)
+	}
+
+	if contents["sdw1/gpupgrade_logs/agent.log"] != "agent log" {
+		t.Errorf("got %v want the agent log", contents)
+	}
+
+	var manifest bundle.Manifest
+	if err := json.Unmarshal([]byte(contents[bundle.ManifestFileName]), &manifest); err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if manifest.Version != "1.2.3" || !reflect.DeepEqual(manifest.Hosts, []string{"host1", "host2", "host3"}) || !reflect.DeepEqual(manifest.Errors, expectedErrs) {
+		t.Errorf("got manifest %+v", manifest)
+	}
+}
+
+func readBundle(t *testing.T, path string) map[string]string {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	contents := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return contents
+		}
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		buf, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		contents[header.Name] = string(buf)
+	}
+}
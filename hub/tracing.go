@@ -0,0 +1,105 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+)
+
+// traceObserver records a span for each step with a child span for each of
+// its substeps. The context of each substep carries its span so that the agent
+// calls and commands it runs are its children.
+type traceObserver struct {
+	mu       sync.Mutex
+	steps    map[idl.Step]*trace.Span
+	substeps map[idl.Step]*trace.Span
+}
+
+func newTraceObserver() *traceObserver {
+	return &traceObserver{
+		steps:    make(map[idl.Step]*trace.Span),
+		substeps: make(map[idl.Step]*trace.Span),
+	}
+}
+
+func (t *traceObserver) StepContext(ctx context.Context, s idl.Step) context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ctx, t.steps[s] = trace.StartSpan(ctx, s.String())
+	return ctx
+}
+
+func (t *traceObserver) SubstepContext(ctx context.Context, s idl.Step, substep idl.Substep) context.Context {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ctx, t.substeps[s] = trace.StartSpan(ctx, substep.String())
+	return ctx
+}
+
+func (t *traceObserver) StepStarted(_ idl.Step) {}
+
+func (t *traceObserver) SubstepStarted(_ idl.Step, _ idl.Substep) {}
+
+func (t *traceObserver) SubstepFinished(s idl.Step, _ idl.Substep, status idl.Status, _ time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span, ok := t.substeps[s]
+	if !ok {
+		return
+	}
+
+	span.SetAttribute("status", status.String())
+	span.End(err)
+	delete(t.substeps, s)
+}
+
+func (t *traceObserver) PointOfNoReturn(s idl.Step, substep idl.Substep, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span, ok := t.steps[s]
+	if !ok || err != nil {
+		return
+	}
+
+	span.SetAttribute("point_of_no_return", substep.String())
+}
+
+func (t *traceObserver) StepFinished(s idl.Step, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span, ok := t.steps[s]
+	if !ok {
+		return
+	}
+
+	span.End(err)
+	delete(t.steps, s)
+}
+
+// startTracing exports the spans of the hub, including those of the steps.
+func (s *Server) startTracing() error {
+	logdir, err := utils.GetLogDir()
+	if err != nil {
+		return err
+	}
+
+	if err := trace.Setup("gpupgrade_hub", logdir, s.Tracing); err != nil {
+		return err
+	}
+
+	step.AddObserver(newTraceObserver())
+	return nil
+}
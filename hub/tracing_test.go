@@ -0,0 +1,58 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+)
+
+type spanRecorder []trace.SpanData
+
+func (r *spanRecorder) Export(span trace.SpanData) {
+	*r = append(*r, span)
+}
+
+func (r *spanRecorder) Shutdown() error {
+	return nil
+}
+
+func TestTraceObserver(t *testing.T) {
+	spans := &spanRecorder{}
+	trace.SetExporter(spans)
+	defer trace.SetExporter(nil)
+
+	observer := newTraceObserver()
+	ctx := observer.StepContext(context.Background(), idl.Step_EXECUTE)
+	ctx = observer.SubstepContext(ctx, idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+
+	// Agent calls during the substep are passed the context of the substep.
+	_, call := trace.StartSpan(ctx, "/idl.Agent/UpgradePrimaries")
+	call.End(nil)
+
+	observer.SubstepFinished(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES, idl.Status_FAILED, time.Minute, errors.New("oops"))
+	observer.StepFinished(idl.Step_EXECUTE, errors.New("oops"))
+
+	if len(*spans) != 3 {
+		t.Fatalf("got spans %+v want 3", *spans)
+	}
+
+	callSpan, substep, step := (*spans)[0], (*spans)[1], (*spans)[2]
+	if step.Name != "EXECUTE" || step.ParentSpanID != "" || step.Error != "oops" {
+		t.Errorf("got step span %+v", step)
+	}
+
+	if substep.Name != "UPGRADE_PRIMARIES" || substep.ParentSpanID != step.SpanID || substep.Attributes["status"] != "FAILED" {
+		t.Errorf("got substep span %+v", substep)
+	}
+
+	if callSpan.ParentSpanID != substep.SpanID || callSpan.TraceID != step.TraceID {
+		t.Errorf("got agent call span %+v want it to be a child of the substep", callSpan)
+	}
+}
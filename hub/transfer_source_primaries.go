@@ -0,0 +1,70 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+// TransferSourcePrimaries rsyncs the data directories of the source primaries
+// upgraded on new hosts from their source hosts to the new hosts, where
+// pg_upgrade reads them.
+func TransferSourcePrimaries(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+	migrated := MigratedPrimaries(source, intermediate)
+
+	request := func(conn *idl.Connection) error {
+		var opts []*idl.RsyncRequest_RsyncOptions
+		for _, intermediatePrimary := range migrated {
+			sourcePrimary := source.Primaries[intermediatePrimary.ContentID]
+			if !sourcePrimary.IsOnHost(conn.Hostname) {
+				continue
+			}
+
+			// The trailing slash transfers the contents of the source data
+			// directory rather than the directory itself.
+			opts = append(opts, &idl.RsyncRequest_RsyncOptions{
+				Sources:         []string{filepath.Clean(sourcePrimary.DataDir) + string(filepath.Separator)},
+				DestinationHost: intermediatePrimary.Hostname,
+				Destination:     upgrade.TransferredDataDir(intermediatePrimary.DataDir),
+				Options:         []string{"--archive", "--compress", "--delete", "--hard-links", "--stats"},
+				ExcludedFiles:   []string{"postmaster.pid"},
+			})
+		}
+
+		if len(opts) == 0 {
+			return nil
+		}
+
+		reply, err := conn.AgentClient.RsyncDataDirectories(ctx, &idl.RsyncRequest{Options: opts})
+		if err != nil {
+			return err
+		}
+
+		recordRsyncBytes(conn.Hostname, reply.GetStats())
+		return nil
+	}
+
+	return ExecuteRPC(agentConns, request)
+}
+
+// DeleteTransferredSourcePrimaries deletes the source data directories
+// transferred to the new hosts once they are no longer needed.
+func DeleteTransferredSourcePrimaries(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+	var transferred greenplum.SegConfigs
+	for _, seg := range MigratedPrimaries(source, intermediate) {
+		seg.DataDir = upgrade.TransferredDataDir(seg.DataDir)
+		transferred = append(transferred, seg)
+	}
+
+	if len(transferred) == 0 {
+		return nil
+	}
+
+	return deleteDataDirectories(ctx, agentConns, transferred)
+}
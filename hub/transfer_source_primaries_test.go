@@ -0,0 +1,110 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+)
+
+func TestTransferSourcePrimaries(t *testing.T) {
+	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "sdw1", DataDir: "/data/dbfast1/seg1", Port: 25433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg2", Port: 25434, Role: greenplum.PrimaryRole},
+	})
+
+	// Only the segments of sdw1 move to the new host.
+	intermediate := hub.MustCreateCluster(t, greenplum.SegConfigs{
+		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg.HqtFHX54y0o.-1", Port: 50432, Role: greenplum.PrimaryRole},
+		{DbID: 2, ContentID: 0, Hostname: "new1", DataDir: "/data/dbfast1/seg.HqtFHX54y0o.1", Port: 50433, Role: greenplum.PrimaryRole},
+		{DbID: 3, ContentID: 1, Hostname: "sdw2", DataDir: "/data/dbfast2/seg.HqtFHX54y0o.2", Port: 50433, Role: greenplum.PrimaryRole},
+	})
+
+	if migrated := hub.MigratedPrimaries(source, intermediate); len(migrated) != 1 || migrated[0].DbID != 2 {
+		t.Errorf("got migrated primaries %v want dbid 2", migrated)
+	}
+
+	t.Run("transfers the migrated primaries from their source hosts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().RsyncDataDirectories(
+			gomock.Any(),
+			&idl.RsyncRequest{
+				Options: []*idl.RsyncRequest_RsyncOptions{{
+					Sources:         []string{"/data/dbfast1/seg1/"},
+					DestinationHost: "new1",
+					Destination:     "/data/dbfast1/seg.HqtFHX54y0o.1.source",
+					Options:         []string{"--archive", "--compress", "--delete", "--hard-links", "--stats"},
+					ExcludedFiles:   []string{"postmaster.pid"},
+				}},
+			},
+		).Return(&idl.RsyncReply{}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		new1 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+			{AgentClient: new1, Hostname: "new1"},
+		}
+
+		err := hub.TransferSourcePrimaries(context.Background(), agentConns, source, intermediate)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+	})
+
+	t.Run("returns errors when the transfer fails", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := errors.New("permission denied")
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().RsyncDataDirectories(gomock.Any(), gomock.Any()).Return(nil, expected)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+		}
+
+		err := hub.TransferSourcePrimaries(context.Background(), agentConns, source, intermediate)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("deletes the transferred primaries on the new hosts", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		new1 := mock_idl.NewMockAgentClient(ctrl)
+		new1.EXPECT().DeleteDataDirectories(
+			gomock.Any(),
+			&idl.DeleteDataDirectoriesRequest{Datadirs: []string{"/data/dbfast1/seg.HqtFHX54y0o.1.source"}},
+		).Return(&idl.DeleteDataDirectoriesReply{}, nil)
+
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: new1, Hostname: "new1"},
+		}
+
+		err := hub.DeleteTransferredSourcePrimaries(context.Background(), agentConns, source, intermediate)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+	})
+}
@@ -19,143 +19,181 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func UpdateConfFiles(agentConns []*idl.Connection, _ step.OutStreams, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
-	if version.Major < 7 {
-		// update gpperfmon.conf on coordinator
-		err := UpdateConfigurationFile([]*idl.UpdateFileConfOptions{{
-			Path:        filepath.Join(target.CoordinatorDataDir(), "gpperfmon", "conf", "gpperfmon.conf"),
-			Pattern:     `^log_location = .*$`,
-			Replacement: fmt.Sprintf("log_location = %s", filepath.Join(target.CoordinatorDataDir(), "gpperfmon", "logs")),
-		}})
-		if err != nil {
-			return err
-		}
-	}
-
-	// update postgresql.conf on coordinator
-	err := UpdateConfigurationFile([]*idl.UpdateFileConfOptions{{
-		Path:        filepath.Join(target.CoordinatorDataDir(), "postgresql.conf"),
-		Pattern:     fmt.Sprintf(`(^port[ \t]*=[ \t]*)%d([^0-9]|$)`, intermediate.CoordinatorPort()),
-		Replacement: fmt.Sprintf(`\1%d\2`, target.CoordinatorPort()),
-	}})
+func UpdateConfFiles(ctx context.Context, agentConns []*idl.Connection, _ step.OutStreams, version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	err := UpdateConfigurationFile(updates(coordinatorConfEdits(version, intermediate, target)))
 	if err != nil {
 		return err
 	}
 
-	if err := UpdatePostgresqlConfOnSegments(agentConns, intermediate, target); err != nil {
+	if err := UpdatePostgresqlConfOnSegments(ctx, agentConns, intermediate, target); err != nil {
 		return err
 	}
 
-	if err := UpdateRecoveryConfOnSegments(agentConns, version, intermediate, target); err != nil {
+	if err := UpdateRecoveryConfOnSegments(ctx, agentConns, version, intermediate, target); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func UpdatePostgresqlConfOnSegments(agentConns []*idl.Connection, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
-	pattern := `(^port[ \t]*=[ \t]*)%d([^0-9]|$)`
+// confFileEdit is an update to a configuration file along with the update
+// that undoes it.
+type confFileEdit struct {
+	update *idl.UpdateFileConfOptions
+	undo   *idl.UpdateFileConfOptions
+}
+
+// portEdit replaces the from port matched by the pattern with the to port.
+// The pattern must capture the text before and after the port.
+func portEdit(path string, pattern string, from int, to int) confFileEdit {
 	replacement := `\1%d\2`
 
-	request := func(conn *idl.Connection) error {
-		var opts []*idl.UpdateFileConfOptions
+	return confFileEdit{
+		update: &idl.UpdateFileConfOptions{
+			Path:        path,
+			Pattern:     fmt.Sprintf(pattern, from),
+			Replacement: fmt.Sprintf(replacement, to),
+		},
+		undo: &idl.UpdateFileConfOptions{
+			Path:        path,
+			Pattern:     fmt.Sprintf(pattern, to),
+			Replacement: fmt.Sprintf(replacement, from),
+		},
+	}
+}
 
-		// add standby
-		if target.Standby().Hostname == conn.Hostname {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(target.StandbyDataDir(), "postgresql.conf"),
-				Pattern:     fmt.Sprintf(pattern, intermediate.StandbyPort()),
-				Replacement: fmt.Sprintf(replacement, target.StandbyPort()),
-			}
+func updates(edits []confFileEdit) []*idl.UpdateFileConfOptions {
+	var opts []*idl.UpdateFileConfOptions
+	for _, edit := range edits {
+		opts = append(opts, edit.update)
+	}
 
-			opts = append(opts, opt)
-		}
+	return opts
+}
 
-		// add mirrors
-		mirrors := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
-			return seg.IsOnHost(conn.Hostname) && seg.IsMirror()
-		})
+func undos(edits []confFileEdit) []*idl.UpdateFileConfOptions {
+	var opts []*idl.UpdateFileConfOptions
+	for _, edit := range edits {
+		opts = append(opts, edit.undo)
+	}
 
-		for _, mirror := range mirrors {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(mirror.DataDir, "postgresql.conf"),
-				Pattern:     fmt.Sprintf(pattern, intermediate.Primaries[mirror.ContentID].Port),
-				Replacement: fmt.Sprintf(replacement, mirror.Port),
-			}
+	return opts
+}
 
-			opts = append(opts, opt)
-		}
+func coordinatorConfEdits(version semver.Version, intermediate *greenplum.Cluster, target *greenplum.Cluster) []confFileEdit {
+	var edits []confFileEdit
 
-		// add primaries
-		primaries := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
-			return seg.IsOnHost(conn.Hostname) && seg.IsPrimary()
+	if version.Major < 7 {
+		// update gpperfmon.conf on coordinator
+		path := filepath.Join(target.CoordinatorDataDir(), "gpperfmon", "conf", "gpperfmon.conf")
+		edits = append(edits, confFileEdit{
+			update: &idl.UpdateFileConfOptions{
+				Path:        path,
+				Pattern:     `^log_location = .*$`,
+				Replacement: fmt.Sprintf("log_location = %s", filepath.Join(target.CoordinatorDataDir(), "gpperfmon", "logs")),
+			},
+			undo: &idl.UpdateFileConfOptions{
+				Path:        path,
+				Pattern:     `^log_location = .*$`,
+				Replacement: fmt.Sprintf("log_location = %s", filepath.Join(intermediate.CoordinatorDataDir(), "gpperfmon", "logs")),
+			},
 		})
+	}
 
-		for _, primary := range primaries {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(primary.DataDir, "postgresql.conf"),
-				Pattern:     fmt.Sprintf(pattern, intermediate.Primaries[primary.ContentID].Port),
-				Replacement: fmt.Sprintf(replacement, primary.Port),
-			}
+	// update postgresql.conf on coordinator
+	edits = append(edits, portEdit(
+		filepath.Join(target.CoordinatorDataDir(), "postgresql.conf"),
+		`(^port[ \t]*=[ \t]*)%d([^0-9]|$)`,
+		intermediate.CoordinatorPort(),
+		target.CoordinatorPort(),
+	))
+
+	return edits
+}
 
-			opts = append(opts, opt)
-		}
+func UpdatePostgresqlConfOnSegments(ctx context.Context, agentConns []*idl.Connection, intermediate *greenplum.Cluster, target *greenplum.Cluster) error {
+	request := func(conn *idl.Connection) error {
+		opts := updates(postgresqlConfEdits(conn.Hostname, intermediate, target))
 
 		req := &idl.UpdateConfigurationRequest{Options: opts}
-		_, err := conn.AgentClient.UpdateConfiguration(context.Background(), req)
+		_, err := conn.AgentClient.UpdateConfiguration(ctx, req)
 		return err
 	}
 
 	return ExecuteRPC(agentConns, request)
 }
 
-func UpdateRecoveryConfOnSegments(agentConns []*idl.Connection, version semver.Version, intermediateCluster *greenplum.Cluster, target *greenplum.Cluster) error {
-	file := "postgresql.auto.conf"
-	if version.Major == 6 {
-		file = "recovery.conf"
-	}
+func postgresqlConfEdits(hostname string, intermediate *greenplum.Cluster, target *greenplum.Cluster) []confFileEdit {
+	pattern := `(^port[ \t]*=[ \t]*)%d([^0-9]|$)`
 
-	pattern := `(primary_conninfo .* port[ \t]*=[ \t]*)%d([^0-9]|$)`
-	replacement := `\1%d\2`
+	var edits []confFileEdit
 
-	request := func(conn *idl.Connection) error {
-		var opts []*idl.UpdateFileConfOptions
+	// add standby
+	if target.Standby().Hostname == hostname {
+		edits = append(edits, portEdit(filepath.Join(target.StandbyDataDir(), "postgresql.conf"), pattern, intermediate.StandbyPort(), target.StandbyPort()))
+	}
 
-		// add standby
-		if target.Standby().Hostname == conn.Hostname {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(target.StandbyDataDir(), file),
-				Pattern:     fmt.Sprintf(pattern, intermediateCluster.CoordinatorPort()),
-				Replacement: fmt.Sprintf(replacement, target.CoordinatorPort()),
-			}
+	// add mirrors
+	mirrors := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && seg.IsMirror()
+	})
 
-			opts = append(opts, opt)
-		}
+	for _, mirror := range mirrors {
+		edits = append(edits, portEdit(filepath.Join(mirror.DataDir, "postgresql.conf"), pattern, intermediate.Primaries[mirror.ContentID].Port, mirror.Port))
+	}
 
-		// add mirrors
-		mirrors := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
-			return seg.IsOnHost(conn.Hostname) && seg.IsMirror()
-		})
+	// add primaries
+	primaries := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && seg.IsPrimary()
+	})
 
-		for _, mirror := range mirrors {
-			opt := &idl.UpdateFileConfOptions{
-				Path:        filepath.Join(mirror.DataDir, file),
-				Pattern:     fmt.Sprintf(pattern, intermediateCluster.Primaries[mirror.ContentID].Port),
-				Replacement: fmt.Sprintf(replacement, target.Primaries[mirror.ContentID].Port),
-			}
+	for _, primary := range primaries {
+		edits = append(edits, portEdit(filepath.Join(primary.DataDir, "postgresql.conf"), pattern, intermediate.Primaries[primary.ContentID].Port, primary.Port))
+	}
 
-			opts = append(opts, opt)
-		}
+	return edits
+}
+
+func UpdateRecoveryConfOnSegments(ctx context.Context, agentConns []*idl.Connection, version semver.Version, intermediateCluster *greenplum.Cluster, target *greenplum.Cluster) error {
+	request := func(conn *idl.Connection) error {
+		opts := updates(recoveryConfEdits(conn.Hostname, version, intermediateCluster, target))
 
 		req := &idl.UpdateConfigurationRequest{Options: opts}
-		_, err := conn.AgentClient.UpdateConfiguration(context.Background(), req)
+		_, err := conn.AgentClient.UpdateConfiguration(ctx, req)
 		return err
 	}
 
 	return ExecuteRPC(agentConns, request)
 }
 
-func UpdateInternalAutoConfOnMirrors(agentConns []*idl.Connection, intermediate *greenplum.Cluster) error {
+func recoveryConfEdits(hostname string, version semver.Version, intermediateCluster *greenplum.Cluster, target *greenplum.Cluster) []confFileEdit {
+	file := "postgresql.auto.conf"
+	if version.Major == 6 {
+		file = "recovery.conf"
+	}
+
+	pattern := `(primary_conninfo .* port[ \t]*=[ \t]*)%d([^0-9]|$)`
+
+	var edits []confFileEdit
+
+	// add standby
+	if target.Standby().Hostname == hostname {
+		edits = append(edits, portEdit(filepath.Join(target.StandbyDataDir(), file), pattern, intermediateCluster.CoordinatorPort(), target.CoordinatorPort()))
+	}
+
+	// add mirrors
+	mirrors := target.SelectSegments(func(seg *greenplum.SegConfig) bool {
+		return seg.IsOnHost(hostname) && seg.IsMirror()
+	})
+
+	for _, mirror := range mirrors {
+		edits = append(edits, portEdit(filepath.Join(mirror.DataDir, file), pattern, intermediateCluster.Primaries[mirror.ContentID].Port, target.Primaries[mirror.ContentID].Port))
+	}
+
+	return edits
+}
+
+func UpdateInternalAutoConfOnMirrors(ctx context.Context, agentConns []*idl.Connection, intermediate *greenplum.Cluster) error {
 	pattern := `(^gp_dbid=)%d([^0-9]|$)`
 	replacement := `\1%d\2`
 
@@ -181,7 +219,7 @@ func UpdateInternalAutoConfOnMirrors(agentConns []*idl.Connection, intermediate
 		}
 
 		req := &idl.UpdateConfigurationRequest{Options: opts}
-		_, err := conn.AgentClient.UpdateConfiguration(context.Background(), req)
+		_, err := conn.AgentClient.UpdateConfiguration(ctx, req)
 		return err
 	}
 
@@ -25,7 +25,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils/rsync"
 )
 
-func UpgradeCoordinator(streams step.OutStreams, source *greenplum.Cluster, intermediate *greenplum.Cluster, action idl.PgOptions_Action, linkMode bool) error {
+func UpgradeCoordinator(streams step.OutStreams, source *greenplum.Cluster, intermediate *greenplum.Cluster, action idl.PgOptions_Action, mode idl.Mode) error {
 	oldOptions := ""
 	// When upgrading from 5 the coordinator must be provided with its standby's dbid to allow WAL to sync.
 	if source.Version.Major == 5 && source.HasStandby() {
@@ -38,7 +38,7 @@ func UpgradeCoordinator(streams step.OutStreams, source *greenplum.Cluster, inte
 		ContentID:     int32(intermediate.Coordinator().ContentID),
 		Mode:          idl.PgOptions_Dispatcher,
 		OldOptions:    oldOptions,
-		LinkMode:      linkMode,
+		UpgradeMode:   mode,
 		TargetVersion: intermediate.Version.String(),
 		OldBinDir:     filepath.Join(source.GPHome, "bin"),
 		OldDataDir:    source.CoordinatorDataDir(),
@@ -59,7 +59,7 @@ func UpgradeCoordinator(streams step.OutStreams, source *greenplum.Cluster, inte
 	stdout := new(bytes.Buffer)
 	tee := io.MultiWriter(streams.Stdout(), stdout)
 
-	runErr := upgrade.Run(tee, streams.Stderr(), opts)
+	runErr := upgrade.Run(step.Context(streams), tee, streams.Stderr(), opts)
 	if runErr != nil {
 		// For "fatal" errors add additional error context. This is useful for customers to see and understand
 		// pg_upgrade --check errors.
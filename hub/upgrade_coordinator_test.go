@@ -114,7 +114,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		defer rsync.ResetRsyncCommand()
 
 		streams := new(step.BufferedStreams)
-		err := hub.UpgradeCoordinator(streams, source, intermediate, idl.PgOptions_check, false)
+		err := hub.UpgradeCoordinator(streams, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -144,7 +144,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 
 		source.Version = semver.MustParse("5.28.0")
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_check, false)
+		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -165,7 +165,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 
 		source.Version = semver.MustParse("6.10.0")
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_check, false)
+		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -181,7 +181,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		}))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_check, false)
+		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -198,7 +198,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		rsync.SetRsyncCommand(exectest.NewCommand(hub.Failure))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_upgrade, false)
+		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
 		var actual *exec.ExitError
 		if !errors.As(err, &actual) {
 			t.Fatalf("got %#v want ExitError", err)
@@ -249,7 +249,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		}))
 		defer rsync.ResetRsyncCommand()
 
-		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_upgrade, false)
+		err := hub.UpgradeCoordinator(step.DevNullStream, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -262,7 +262,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(hub.Failure))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := hub.UpgradeCoordinator(new(step.BufferedStreams), source, intermediate, idl.PgOptions_upgrade, false)
+		err := hub.UpgradeCoordinator(new(step.BufferedStreams), source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
 		expected := "upgrade master: exit status 1"
 		if err.Error() != expected {
 			t.Errorf("got %q want %q", err.Error(), expected)
@@ -292,7 +292,7 @@ func TestUpgradeCoordinator(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(PgCheckFailure))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := hub.UpgradeCoordinator(new(step.BufferedStreams), source, intermediate, idl.PgOptions_check, false)
+		err := hub.UpgradeCoordinator(new(step.BufferedStreams), source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 		var nextActionsErr utils.NextActionErr
 		if !errors.As(err, &nextActionsErr) {
 			t.Fatalf("got type %T want %T", err, nextActionsErr)
@@ -323,7 +323,7 @@ Failure, exiting`, pgUpgradeDir)
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(BlindlyWritingMain))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := hub.UpgradeCoordinator(testutils.FailingStreams{Err: errors.New("write failed")}, source, intermediate, idl.PgOptions_upgrade, false)
+		err := hub.UpgradeCoordinator(testutils.FailingStreams{Err: errors.New("write failed")}, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy)
 		expected := "upgrade master: write failed"
 		if err.Error() != expected {
 			t.Errorf("got %q want %q", err.Error(), expected)
@@ -387,7 +387,7 @@ Failure, exiting
 				upgrade.SetPgUpgradeCommand(exectest.NewCommand(c.main))
 				defer upgrade.ResetPgUpgradeCommand()
 
-				err := hub.UpgradeCoordinator(new(step.BufferedStreams), source, intermediate, idl.PgOptions_check, false)
+				err := hub.UpgradeCoordinator(new(step.BufferedStreams), source, intermediate, idl.PgOptions_check, idl.Mode_copy)
 				if err == nil {
 					t.Errorf("expected error, returned nil")
 				}
@@ -6,17 +6,23 @@ package hub
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
 
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func UpgradeMirrorsUsingRsync(conn *greenplum.Conn, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, useHbaHostnames bool) error {
+// UpgradeMirrorsUsingRsync upgrades the mirrors by rsyncing the upgraded
+// primaries to them. An incremental resync sends only the files that differ
+// from the source mirrors and reports how much data was avoided.
+func UpgradeMirrorsUsingRsync(ctx context.Context, streams step.OutStreams, conn *greenplum.Conn, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, useHbaHostnames bool, resync string) error {
 	options := []greenplum.Option{
 		greenplum.ToTarget(),
 		greenplum.Port(intermediate.CoordinatorPort()),
@@ -40,27 +46,40 @@ func UpgradeMirrorsUsingRsync(conn *greenplum.Conn, agentConns []*idl.Connection
 		return err
 	}
 
-	if err := RsyncMirrorDataDirsOnSegments(agentConns, source, intermediate); err != nil {
-		return err
+	if resync == IncrementalResync {
+		stats, err := ResyncMirrorDataDirsOnSegments(ctx, agentConns, source, intermediate)
+		if err != nil {
+			return err
+		}
+
+		report := MirrorResyncReport(stats)
+		gplog.Info(report)
+		if _, err := fmt.Fprint(streams.Stdout(), report); err != nil {
+			return err
+		}
+	} else {
+		if err := RsyncMirrorDataDirsOnSegments(ctx, agentConns, source, intermediate); err != nil {
+			return err
+		}
 	}
 
-	if err := RsyncMirrorTablespacesOnSegments(agentConns, source, intermediate); err != nil {
+	if err := RsyncMirrorTablespacesOnSegments(ctx, agentConns, source, intermediate); err != nil {
 		return err
 	}
 
-	if err := RenameMirrorTablespacesOnSegments(agentConns, source, intermediate); err != nil {
+	if err := RenameMirrorTablespacesOnSegments(ctx, agentConns, source, intermediate); err != nil {
 		return err
 	}
 
-	if err := CreateRecoveryConfOnSegments(agentConns, intermediate); err != nil {
+	if err := CreateRecoveryConfOnSegments(ctx, agentConns, intermediate); err != nil {
 		return err
 	}
 
-	if err := AddReplicationEntriesOnPrimaries(agentConns, intermediate, useHbaHostnames); err != nil {
+	if err := AddReplicationEntriesOnPrimaries(ctx, agentConns, intermediate, useHbaHostnames); err != nil {
 		return err
 	}
 
-	if err := UpdateInternalAutoConfOnMirrors(agentConns, intermediate); err != nil {
+	if err := UpdateInternalAutoConfOnMirrors(ctx, agentConns, intermediate); err != nil {
 		return err
 	}
 
@@ -83,7 +102,7 @@ func UpgradeMirrorsUsingRsync(conn *greenplum.Conn, agentConns []*idl.Connection
 	return nil
 }
 
-func RsyncMirrorDataDirsOnSegments(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+func RsyncMirrorDataDirsOnSegments(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		sourcePrimaries := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsCoordinator() && seg.IsPrimary()
@@ -100,21 +119,26 @@ func RsyncMirrorDataDirsOnSegments(agentConns []*idl.Connection, source *greenpl
 				Sources:         []string{sourcePrimary.DataDir, intermediatePrimary.DataDir},
 				Destination:     filepath.Dir(intermediateMirror.DataDir), // FIXME: Do we really want filepath.Dir here
 				DestinationHost: intermediateMirror.Hostname,
-				Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive"},
+				Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive", "--stats"},
 			}
 
 			opts = append(opts, opt)
 		}
 
 		req := &idl.RsyncRequest{Options: opts}
-		_, err := conn.AgentClient.RsyncDataDirectories(context.Background(), req)
-		return err
+		reply, err := conn.AgentClient.RsyncDataDirectories(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		recordRsyncBytes(conn.Hostname, reply.GetStats())
+		return nil
 	}
 
 	return ExecuteRPC(agentConns, request)
 }
 
-func RsyncMirrorTablespacesOnSegments(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+func RsyncMirrorTablespacesOnSegments(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		sourcePrimaries := source.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsCoordinator() && seg.IsPrimary()
@@ -144,14 +168,14 @@ func RsyncMirrorTablespacesOnSegments(agentConns []*idl.Connection, source *gree
 			}
 		}
 
-		_, err := conn.AgentClient.RsyncTablespaceDirectories(context.Background(), &idl.RsyncRequest{Options: opts})
+		_, err := conn.AgentClient.RsyncTablespaceDirectories(ctx, &idl.RsyncRequest{Options: opts})
 		return err
 	}
 
 	return ExecuteRPC(agentConns, request)
 }
 
-func RenameMirrorTablespacesOnSegments(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
+func RenameMirrorTablespacesOnSegments(ctx context.Context, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster) error {
 	request := func(conn *idl.Connection) error {
 		intermediateMirrors := intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && !seg.IsStandby() && seg.IsMirror()
@@ -182,7 +206,7 @@ func RenameMirrorTablespacesOnSegments(agentConns []*idl.Connection, source *gre
 			}
 		}
 
-		_, err := conn.AgentClient.RenameTablespaces(context.Background(), &idl.RenameTablespacesRequest{RenamePairs: pairs})
+		_, err := conn.AgentClient.RenameTablespaces(ctx, &idl.RenameTablespacesRequest{RenamePairs: pairs})
 		return err
 	}
 
@@ -4,6 +4,7 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -50,7 +51,7 @@ func TestRsyncMirrorDataDirsOnSegments(t *testing.T) {
 						Sources:         []string{"/data/dbfast1/seg.HqtFHX54y0o.1", "/data/dbfast1/seg1"},
 						Destination:     "/data/dbfast_mirror1",
 						DestinationHost: "sdw2",
-						Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive"},
+						Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive", "--stats"},
 					}},
 			},
 		).Return(&idl.RsyncReply{}, nil)
@@ -64,7 +65,7 @@ func TestRsyncMirrorDataDirsOnSegments(t *testing.T) {
 						Sources:         []string{"/data/dbfast2/seg.HqtFHX54y0o.2", "/data/dbfast2/seg2"},
 						Destination:     "/data/dbfast_mirror2",
 						DestinationHost: "sdw1",
-						Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive"},
+						Options:         []string{"--archive", "--delete", "--hard-links", "--size-only", "--no-inc-recursive", "--stats"},
 					}},
 			},
 		).Return(&idl.RsyncReply{}, nil)
@@ -74,7 +75,7 @@ func TestRsyncMirrorDataDirsOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.RsyncMirrorDataDirsOnSegments(agentConns, intermediate, source)
+		err := hub.RsyncMirrorDataDirsOnSegments(context.Background(), agentConns, intermediate, source)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -102,7 +103,7 @@ func TestRsyncMirrorDataDirsOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.RsyncMirrorDataDirsOnSegments(agentConns, intermediate, source)
+		err := hub.RsyncMirrorDataDirsOnSegments(context.Background(), agentConns, intermediate, source)
 		var errs errorlist.Errors
 		if !xerrors.As(err, &errs) {
 			t.Fatalf("error %#v does not contain type %T", err, errs)
@@ -177,7 +178,7 @@ func TestRsyncAndRenameMirrorTablespacesOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.RsyncMirrorTablespacesOnSegments(agentConns, source, intermediate)
+		err := hub.RsyncMirrorTablespacesOnSegments(context.Background(), agentConns, source, intermediate)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -213,7 +214,7 @@ func TestRsyncAndRenameMirrorTablespacesOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.RsyncMirrorTablespacesOnSegments(agentConns, source, intermediate)
+		err := hub.RsyncMirrorTablespacesOnSegments(context.Background(), agentConns, source, intermediate)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
@@ -260,7 +261,7 @@ func TestRenameMirrorTablespacesOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.RenameMirrorTablespacesOnSegments(agentConns, source, intermediate)
+		err := hub.RenameMirrorTablespacesOnSegments(context.Background(), agentConns, source, intermediate)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
@@ -287,7 +288,7 @@ func TestRenameMirrorTablespacesOnSegments(t *testing.T) {
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.RenameMirrorTablespacesOnSegments(agentConns, source, intermediate)
+		err := hub.RenameMirrorTablespacesOnSegments(context.Background(), agentConns, source, intermediate)
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v, want %#v", err, expected)
 		}
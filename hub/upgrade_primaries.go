@@ -5,56 +5,208 @@ package hub
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
 	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/greenplum"
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
-func UpgradePrimaries(agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, action idl.PgOptions_Action, linkMode bool) error {
+// UpgradePrimaries upgrades each primary segment with its own request so that
+// its status can be tracked by dbid. When store is not nil primaries that have
+// already completed are skipped, such that rerunning a failed upgrade only
+// redoes the failed and remaining primaries. When onlyContents is set only the
+// primaries with those content ids are upgraded, whether or not they have
+// completed.
+func UpgradePrimaries(ctx context.Context, streams step.OutStreams, agentConns []*idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, action idl.PgOptions_Action, mode idl.Mode, store *step.SegmentFileStore, onlyContents []int) error {
+	primaries := intermediate.Primaries.ExcludingCoordinator()
+
+	only := make(map[int]bool)
+	for _, content := range onlyContents {
+		if _, ok := primaries[content]; !ok {
+			return xerrors.Errorf("Content %d is not a primary segment of the cluster.", content)
+		}
+
+		only[content] = true
+	}
+
+	statuses := make(map[int]step.SegmentStatus)
+	if store != nil {
+		var err error
+		statuses, err = store.Read(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+		if err != nil {
+			return err
+		}
+	}
+
+	selected := func(seg greenplum.SegConfig) bool {
+		if len(only) > 0 {
+			return only[seg.ContentID]
+		}
+
+		return statuses[seg.DbID].Status != idl.Status_COMPLETE
+	}
+
+	var mutex sync.Mutex
+	report := func(format string, args ...interface{}) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		msg := fmt.Sprintf(format, args...)
+		gplog.Info(msg)
+		fmt.Fprintln(streams.Stdout(), msg)
+	}
+
+	setStatus := func(dbid int, status step.SegmentStatus) error {
+		if store == nil {
+			return nil
+		}
+
+		return store.Write(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES, dbid, status)
+	}
+
 	request := func(conn *idl.Connection) error {
 		intermediatePrimaries := intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool {
 			return seg.IsOnHost(conn.Hostname) && seg.IsPrimary() && !seg.IsCoordinator()
 		})
 
-		var opts []*idl.PgOptions
+		var wg sync.WaitGroup
+		errs := make(chan error, len(intermediatePrimaries))
+
 		for _, intermediatePrimary := range intermediatePrimaries {
-			sourcePrimary := source.Primaries[intermediatePrimary.ContentID]
-
-			opt := &idl.PgOptions{
-				Action:        action,
-				Role:          intermediatePrimary.Role,
-				ContentID:     int32(intermediatePrimary.ContentID),
-				Mode:          idl.PgOptions_Segment,
-				LinkMode:      linkMode,
-				TargetVersion: intermediate.Version.String(),
-				OldBinDir:     filepath.Join(source.GPHome, "bin"),
-				OldDataDir:    sourcePrimary.DataDir,
-				OldPort:       strconv.Itoa(sourcePrimary.Port),
-				OldDBID:       strconv.Itoa(sourcePrimary.DbID),
-				NewBinDir:     filepath.Join(intermediate.GPHome, "bin"),
-				NewDataDir:    intermediatePrimary.DataDir,
-				NewPort:       strconv.Itoa(intermediatePrimary.Port),
-				NewDBID:       strconv.Itoa(intermediatePrimary.DbID),
-				Tablespaces:   getProtoBufSegmentTablespaces(source.Tablespaces, intermediatePrimary.DbID),
+			if !selected(intermediatePrimary) {
+				report("Skipping primary content %d dbid %d on host %s.", intermediatePrimary.ContentID, intermediatePrimary.DbID, conn.Hostname)
+				continue
+			}
+
+			// The source data of primaries upgraded on new hosts is only
+			// transferred to them during execute.
+			if action == idl.PgOptions_check && IsMigrated(source, &intermediatePrimary) {
+				report("Skipping check of primary content %d dbid %d on new host %s.", intermediatePrimary.ContentID, intermediatePrimary.DbID, conn.Hostname)
+				continue
 			}
 
-			opts = append(opts, opt)
+			wg.Add(1)
+			go func(intermediatePrimary greenplum.SegConfig) {
+				defer wg.Done()
+
+				errs <- upgradePrimary(ctx, conn, source, intermediate, intermediatePrimary, action, mode, setStatus, report)
+			}(intermediatePrimary)
 		}
 
-		req := &idl.UpgradePrimariesRequest{Action: action, Opts: opts}
-		_, err := conn.AgentClient.UpgradePrimaries(context.Background(), req)
-		if err != nil {
-			return xerrors.Errorf("%s primary segment on host %s: %w", action, conn.Hostname, err)
+		wg.Wait()
+		close(errs)
+
+		var err error
+		for e := range errs {
+			err = errorlist.Append(err, e)
 		}
 
-		return nil
+		return err
+	}
+
+	err := ExecuteRPC(agentConns, request)
+	if store == nil {
+		return err
+	}
+
+	if err != nil {
+		var errs errorlist.Errors
+		failed := 1
+		if xerrors.As(err, &errs) {
+			failed = len(errs)
+		}
+
+		attempted := 0
+		for _, seg := range primaries {
+			if selected(seg) {
+				attempted++
+			}
+		}
+
+		return xerrors.Errorf("%d of %d primaries failed to %s. Rerunning will only %s the failed and remaining primaries: %w",
+			failed, attempted, action, action, err)
+	}
+
+	// When only some contents were retried others may still need upgrading.
+	statuses, err = store.Read(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+	if err != nil {
+		return err
+	}
+
+	var incomplete []int
+	for content, seg := range primaries {
+		if statuses[seg.DbID].Status != idl.Status_COMPLETE {
+			incomplete = append(incomplete, content)
+		}
+	}
+
+	if len(incomplete) > 0 {
+		sort.Ints(incomplete)
+		return xerrors.Errorf("The primaries with content ids %s have not completed %s. Rerunning will %s them.",
+			strings.Trim(fmt.Sprint(incomplete), "[]"), action, action)
+	}
+
+	return nil
+}
+
+func upgradePrimary(ctx context.Context, conn *idl.Connection, source *greenplum.Cluster, intermediate *greenplum.Cluster, intermediatePrimary greenplum.SegConfig, action idl.PgOptions_Action, mode idl.Mode,
+	setStatus func(dbid int, status step.SegmentStatus) error, report func(format string, args ...interface{})) error {
+
+	sourcePrimary := source.Primaries[intermediatePrimary.ContentID]
+	if IsMigrated(source, &intermediatePrimary) {
+		sourcePrimary.DataDir = upgrade.TransferredDataDir(intermediatePrimary.DataDir)
+	}
+
+	opt := &idl.PgOptions{
+		Action:        action,
+		Role:          intermediatePrimary.Role,
+		ContentID:     int32(intermediatePrimary.ContentID),
+		Mode:          idl.PgOptions_Segment,
+		UpgradeMode:   mode,
+		TargetVersion: intermediate.Version.String(),
+		OldBinDir:     filepath.Join(source.GPHome, "bin"),
+		OldDataDir:    sourcePrimary.DataDir,
+		OldPort:       strconv.Itoa(sourcePrimary.Port),
+		OldDBID:       strconv.Itoa(sourcePrimary.DbID),
+		NewBinDir:     filepath.Join(intermediate.GPHome, "bin"),
+		NewDataDir:    intermediatePrimary.DataDir,
+		NewPort:       strconv.Itoa(intermediatePrimary.Port),
+		NewDBID:       strconv.Itoa(intermediatePrimary.DbID),
+		Tablespaces:   getProtoBufSegmentTablespaces(source.Tablespaces, intermediatePrimary.DbID),
+	}
+
+	if err := setStatus(intermediatePrimary.DbID, step.SegmentStatus{Status: idl.Status_RUNNING}); err != nil {
+		return err
+	}
+
+	req := &idl.UpgradePrimariesRequest{Action: action, Opts: []*idl.PgOptions{opt}}
+	_, err := conn.AgentClient.UpgradePrimaries(ctx, req)
+	recordPgUpgrade(action, err)
+	if err != nil {
+		report("Failed to %s primary content %d dbid %d on host %s.", action, intermediatePrimary.ContentID, intermediatePrimary.DbID, conn.Hostname)
+		details := pgUpgradeLogDetails(ctx, conn, intermediatePrimary.Role, int32(intermediatePrimary.ContentID))
+		err = fmt.Errorf("%s primary segment dbid %d on host %s: %w%s", action, intermediatePrimary.DbID, conn.Hostname, err, details)
+
+		if sErr := setStatus(intermediatePrimary.DbID, step.SegmentStatus{Status: idl.Status_FAILED, Error: err.Error()}); sErr != nil {
+			err = errorlist.Append(err, sErr)
+		}
+
+		return err
 	}
 
-	return ExecuteRPC(agentConns, request)
+	report("Completed %s of primary content %d dbid %d on host %s.", action, intermediatePrimary.ContentID, intermediatePrimary.DbID, conn.Hostname)
+	return setStatus(intermediatePrimary.DbID, step.SegmentStatus{Status: idl.Status_COMPLETE})
 }
 
 // TODO: remove greenplum.TablespaceInfo in favor of idl.TablespaceInfo, and create a helper function if needed
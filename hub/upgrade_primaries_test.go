@@ -4,11 +4,14 @@
 package hub_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"strconv"
 	"testing"
 
 	"github.com/blang/semver/v4"
@@ -19,10 +22,15 @@ import (
 	"github.com/greenplum-db/gpupgrade/hub"
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
 )
 
 func TestUpgradePrimaries(t *testing.T) {
+	testlog.SetupLogger()
+
 	source := hub.MustCreateCluster(t, greenplum.SegConfigs{
 		{DbID: 1, ContentID: -1, Hostname: "coordinator", DataDir: "/data/qddir/seg-1", Port: 15432, Role: greenplum.PrimaryRole},
 		{DbID: 2, ContentID: -1, Hostname: "standby", DataDir: "/data/standby", Port: 16432, Role: greenplum.MirrorRole},
@@ -56,107 +64,171 @@ func TestUpgradePrimaries(t *testing.T) {
 	intermediate.GPHome = "/usr/local/gpdb6"
 	intermediate.Version = semver.MustParse("6.0.0")
 
-	t.Run("calls upgrades primaries on segments", func(t *testing.T) {
+	// request returns the single segment request expected for the dbid.
+	request := func(action idl.PgOptions_Action, dbid int) *idl.UpgradePrimariesRequest {
+		intermediatePrimary := intermediate.SelectSegments(func(seg *greenplum.SegConfig) bool {
+			return seg.DbID == dbid
+		})[0]
+		sourcePrimary := source.Primaries[intermediatePrimary.ContentID]
+
+		return &idl.UpgradePrimariesRequest{
+			Action: action,
+			Opts: []*idl.PgOptions{{
+				Action:        action,
+				Role:          greenplum.PrimaryRole,
+				ContentID:     int32(intermediatePrimary.ContentID),
+				Mode:          idl.PgOptions_Segment,
+				UpgradeMode:   idl.Mode_copy,
+				TargetVersion: "6.0.0",
+				OldBinDir:     "/usr/local/gpdb5/bin",
+				OldDataDir:    sourcePrimary.DataDir,
+				OldPort:       strconv.Itoa(sourcePrimary.Port),
+				OldDBID:       strconv.Itoa(sourcePrimary.DbID),
+				NewBinDir:     "/usr/local/gpdb6/bin",
+				NewDataDir:    intermediatePrimary.DataDir,
+				NewPort:       strconv.Itoa(intermediatePrimary.Port),
+				NewDBID:       strconv.Itoa(intermediatePrimary.DbID),
+				Tablespaces:   nil,
+			}},
+		}
+	}
+
+	t.Run("upgrades each primary on segments with its own request", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		sdw1 := mock_idl.NewMockAgentClient(ctrl)
-		sdw1.EXPECT().UpgradePrimaries(
-			gomock.Any(),
-			equivalentUpgradePrimariesRequest(&idl.UpgradePrimariesRequest{
-				Action: idl.PgOptions_check,
-				Opts: []*idl.PgOptions{
-					{
-						Action:        idl.PgOptions_check,
-						Role:          greenplum.PrimaryRole,
-						ContentID:     0,
-						Mode:          idl.PgOptions_Segment,
-						LinkMode:      false,
-						TargetVersion: "6.0.0",
-						OldBinDir:     "/usr/local/gpdb5/bin",
-						OldDataDir:    "/data/dbfast1/seg1",
-						OldPort:       "25433",
-						OldDBID:       "3",
-						NewBinDir:     "/usr/local/gpdb6/bin",
-						NewDataDir:    "/data/dbfast1/seg.HqtFHX54y0o.1",
-						NewPort:       "60434",
-						NewDBID:       "3",
-						Tablespaces:   nil,
-					},
-					{
-						Action:        idl.PgOptions_check,
-						Role:          greenplum.PrimaryRole,
-						ContentID:     2,
-						Mode:          idl.PgOptions_Segment,
-						LinkMode:      false,
-						TargetVersion: "6.0.0",
-						OldBinDir:     "/usr/local/gpdb5/bin",
-						OldDataDir:    "/data/dbfast3/seg3",
-						OldPort:       "25437",
-						OldDBID:       "7",
-						NewBinDir:     "/usr/local/gpdb6/bin",
-						NewDataDir:    "/data/dbfast3/seg.HqtFHX54y0o.3",
-						NewPort:       "60438",
-						NewDBID:       "7",
-						Tablespaces:   nil,
-					},
-				},
-			}),
-		).Return(&idl.UpgradePrimariesReply{}, nil)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_check, 3))).Return(&idl.UpgradePrimariesReply{}, nil)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_check, 7))).Return(&idl.UpgradePrimariesReply{}, nil)
 
 		sdw2 := mock_idl.NewMockAgentClient(ctrl)
-		sdw2.EXPECT().UpgradePrimaries(
-			gomock.Any(),
-			equivalentUpgradePrimariesRequest(&idl.UpgradePrimariesRequest{
-				Action: idl.PgOptions_check,
-				Opts: []*idl.PgOptions{
-					{
-						Action:        idl.PgOptions_check,
-						Role:          greenplum.PrimaryRole,
-						ContentID:     1,
-						Mode:          idl.PgOptions_Segment,
-						LinkMode:      false,
-						TargetVersion: "6.0.0",
-						OldBinDir:     "/usr/local/gpdb5/bin",
-						OldDataDir:    "/data/dbfast2/seg2",
-						OldPort:       "25435",
-						OldDBID:       "5",
-						NewBinDir:     "/usr/local/gpdb6/bin",
-						NewDataDir:    "/data/dbfast2/seg.HqtFHX54y0o.2",
-						NewPort:       "60436",
-						NewDBID:       "5",
-						Tablespaces:   nil,
-					},
-					{
-						Action:        idl.PgOptions_check,
-						Role:          greenplum.PrimaryRole,
-						ContentID:     3,
-						Mode:          idl.PgOptions_Segment,
-						LinkMode:      false,
-						TargetVersion: "6.0.0",
-						OldBinDir:     "/usr/local/gpdb5/bin",
-						OldDataDir:    "/data/dbfast4/seg4",
-						OldPort:       "25439",
-						OldDBID:       "9",
-						NewBinDir:     "/usr/local/gpdb6/bin",
-						NewDataDir:    "/data/dbfast4/seg.HqtFHX54y0o.4",
-						NewPort:       "60440",
-						NewDBID:       "9",
-						Tablespaces:   nil,
-					},
-				},
-			}),
-		).Return(&idl.UpgradePrimariesReply{}, nil)
+		sdw2.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_check, 5))).Return(&idl.UpgradePrimariesReply{}, nil)
+		sdw2.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_check, 9))).Return(&idl.UpgradePrimariesReply{}, nil)
 
 		agentConns := []*idl.Connection{
 			{AgentClient: sdw1, Hostname: "sdw1"},
 			{AgentClient: sdw2, Hostname: "sdw2"},
 		}
 
-		err := hub.UpgradePrimaries(agentConns, source, intermediate, idl.PgOptions_check, false)
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, agentConns, source, intermediate, idl.PgOptions_check, idl.Mode_copy, nil, nil)
+		if err != nil {
+			t.Errorf("unexpected err %#v", err)
+		}
+	})
+
+	t.Run("records the status of each primary and skips completed primaries when rerun", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		path := filepath.Join(stateDir, step.SegmentsFileName)
+		testutils.MustWriteToFile(t, path, "{}")
+		store := step.NewSegmentStoreUsingFile(path)
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		expected := os.ErrPermission
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_upgrade, 3))).Return(&idl.UpgradePrimariesReply{}, nil)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_upgrade, 7))).Return(nil, expected)
+		sdw1.EXPECT().GetPgUpgradeLogs(gomock.Any(), gomock.Any()).Return(&idl.GetPgUpgradeLogsReply{}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+		sdw2.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_upgrade, 5))).Return(&idl.UpgradePrimariesReply{}, nil)
+		sdw2.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_upgrade, 9))).Return(&idl.UpgradePrimariesReply{}, nil)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, agentConns, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy, store, nil)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+
+		expectedMsg := "1 of 4 primaries failed to upgrade. Rerunning will only upgrade the failed and remaining primaries: upgrade primary segment dbid 7 on host sdw1: permission denied"
+		if err == nil || err.Error() != expectedMsg {
+			t.Errorf("got error %q want %q", err, expectedMsg)
+		}
+
+		statuses, err := store.Read(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+		if err != nil {
+			t.Fatalf("unexpected err %#v", err)
+		}
+
+		expectedStatuses := map[int]step.SegmentStatus{
+			3: {Status: idl.Status_COMPLETE},
+			5: {Status: idl.Status_COMPLETE},
+			7: {Status: idl.Status_FAILED, Error: "upgrade primary segment dbid 7 on host sdw1: permission denied"},
+			9: {Status: idl.Status_COMPLETE},
+		}
+		if !reflect.DeepEqual(statuses, expectedStatuses) {
+			t.Errorf("got statuses %v want %v", statuses, expectedStatuses)
+		}
+
+		// Rerunning only upgrades the failed primary.
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_upgrade, 7))).Return(&idl.UpgradePrimariesReply{}, nil)
+
+		err = hub.UpgradePrimaries(context.Background(), step.DevNullStream, agentConns, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy, store, nil)
 		if err != nil {
 			t.Errorf("unexpected err %#v", err)
 		}
+
+		statuses, err = store.Read(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES)
+		if err != nil {
+			t.Fatalf("unexpected err %#v", err)
+		}
+
+		if statuses[7] != (step.SegmentStatus{Status: idl.Status_COMPLETE}) {
+			t.Errorf("got status %v for dbid 7 want %s", statuses[7], idl.Status_COMPLETE)
+		}
+	})
+
+	t.Run("only upgrades the requested contents and errors when others remain", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		path := filepath.Join(stateDir, step.SegmentsFileName)
+		testutils.MustWriteToFile(t, path, "{}")
+		store := step.NewSegmentStoreUsingFile(path)
+
+		for _, dbid := range []int{3, 5} {
+			err := store.Write(idl.Step_EXECUTE, idl.Substep_UPGRADE_PRIMARIES, dbid, step.SegmentStatus{Status: idl.Status_COMPLETE})
+			if err != nil {
+				t.Fatalf("unexpected err %#v", err)
+			}
+		}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// content 0 (dbid 3) is retried even though it completed, and content
+		// 3 (dbid 9) is not retried since it was not requested.
+		sdw1 := mock_idl.NewMockAgentClient(ctrl)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_upgrade, 3))).Return(&idl.UpgradePrimariesReply{}, nil)
+		sdw1.EXPECT().UpgradePrimaries(gomock.Any(), equivalentUpgradePrimariesRequest(request(idl.PgOptions_upgrade, 7))).Return(&idl.UpgradePrimariesReply{}, nil)
+
+		sdw2 := mock_idl.NewMockAgentClient(ctrl)
+
+		agentConns := []*idl.Connection{
+			{AgentClient: sdw1, Hostname: "sdw1"},
+			{AgentClient: sdw2, Hostname: "sdw2"},
+		}
+
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, agentConns, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy, store, []int{0, 2})
+		expected := "The primaries with content ids 3 have not completed upgrade. Rerunning will upgrade them."
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("errors when a requested content is not a primary segment", func(t *testing.T) {
+		err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, nil, source, intermediate, idl.PgOptions_upgrade, idl.Mode_copy, nil, []int{-1})
+		expected := "Content -1 is not a primary segment of the cluster."
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
 	})
 
 	errCases := []struct {
@@ -186,30 +258,36 @@ func TestUpgradePrimaries(t *testing.T) {
 			sdw1.EXPECT().UpgradePrimaries(
 				gomock.Any(),
 				gomock.Any(),
-			).Return(nil, expected)
+			).Return(nil, expected).Times(2)
+			sdw1.EXPECT().GetPgUpgradeLogs(gomock.Any(), gomock.Any()).Return(&idl.GetPgUpgradeLogsReply{}, nil).Times(2)
 
 			sdw2 := mock_idl.NewMockAgentClient(ctrl)
 			sdw2.EXPECT().UpgradePrimaries(
 				gomock.Any(),
 				gomock.Any(),
-			).Return(nil, expected)
+			).Return(nil, expected).Times(2)
+			sdw2.EXPECT().GetPgUpgradeLogs(gomock.Any(), gomock.Any()).Return(&idl.GetPgUpgradeLogsReply{}, nil).Times(2)
 
 			agentConns := []*idl.Connection{
 				{AgentClient: sdw1, Hostname: "sdw1"},
 				{AgentClient: sdw2, Hostname: "sdw2"},
 			}
 
-			err := hub.UpgradePrimaries(agentConns, source, intermediate, c.Action, true)
+			err := hub.UpgradePrimaries(context.Background(), step.DevNullStream, agentConns, source, intermediate, c.Action, idl.Mode_link, nil, nil)
 			var errs errorlist.Errors
 			if !xerrors.As(err, &errs) {
 				t.Fatalf("error %#v does not contain type %T", err, errs)
 			}
 
-			if len(errs) != 2 {
-				t.Fatalf("got error count %d, want %d", len(errs), 2)
+			if len(errs) != 4 {
+				t.Fatalf("got error count %d, want %d", len(errs), 4)
 			}
 
 			sort.Sort(errs)
+			expectedErrs := []struct {
+				dbid int
+				host string
+			}{{3, "sdw1"}, {5, "sdw2"}, {7, "sdw1"}, {9, "sdw2"}}
 			for i, err := range errs {
 				if !errors.Is(err, expected) {
 					t.Errorf("got error %#v, want %#v", err, expected)
@@ -217,7 +295,7 @@ func TestUpgradePrimaries(t *testing.T) {
 
 				// XXX it'd be nice if we didn't couple against a hardcoded string here,
 				// but it's difficult to unwrap multiple errors with the new xerrors interface.
-				expectedErrMsg := fmt.Errorf("%s primary segment on host sdw%d: %w", c.action, i+1, expected)
+				expectedErrMsg := fmt.Errorf("%s primary segment dbid %d on host %s: %w", c.action, expectedErrs[i].dbid, expectedErrs[i].host, expected)
 				if err.Error() != expectedErrMsg.Error() {
 					t.Errorf("got %q want %q", err.Error(), expectedErrMsg)
 				}
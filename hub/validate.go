@@ -0,0 +1,154 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
+	"github.com/greenplum-db/gpupgrade/utils/validation"
+)
+
+// SnapshotSourceData saves the row counts, and optionally the sampled row
+// hashes, of the source cluster to validate the target cluster against.
+func SnapshotSourceData(conn *greenplum.Conn, source *greenplum.Cluster, mode string) error {
+	snapshot, err := SnapshotData(conn, source, idl.ClusterDestination_SOURCE, validation.Source, mode)
+	if err != nil {
+		return err
+	}
+
+	dir, err := utils.GetValidationDir()
+	if err != nil {
+		return err
+	}
+
+	return validation.Save(dir, snapshot)
+}
+
+// Validate compares the data of the running target cluster with the source
+// snapshot taken during initialize. Mismatches are returned in the reply
+// rather than as an error so that the CLI can display the report.
+func (s *Server) Validate(ctx context.Context, in *idl.ValidateRequest) (*idl.ValidateReply, error) {
+	targetStarted, err := step.HasCompleted(idl.Step_EXECUTE, idl.Substep_START_TARGET_CLUSTER)
+	if err != nil {
+		return nil, err
+	}
+
+	if !targetStarted {
+		return nil, errors.New(`The target cluster has not been started. Run "gpupgrade execute" before validating the upgraded data.`)
+	}
+
+	finalizeStarted, err := step.HasStarted(idl.Step_FINALIZE)
+	if err != nil {
+		return nil, err
+	}
+
+	if finalizeStarted {
+		return nil, errors.New(`Finalize has already started. The upgraded data can only be validated between execute and finalize.`)
+	}
+
+	dir, err := utils.GetValidationDir()
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := validation.Load(validation.Path(dir, validation.Source))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New(`No source data snapshot was taken during initialize. Set data_validation in the gpupgrade config file to validate the upgraded data.`)
+		}
+
+		return nil, xerrors.Errorf("loading source data snapshot: %w", err)
+	}
+
+	target, err := SnapshotData(s.Connection, s.Intermediate, idl.ClusterDestination_TARGET, validation.Target, source.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateTarget(dir, source, target)
+}
+
+// ValidateTarget saves the target snapshot and writes the report comparing it
+// with the source snapshot to the validation directory.
+func ValidateTarget(dir string, source, target validation.Snapshot) (*idl.ValidateReply, error) {
+	if err := validation.Save(dir, target); err != nil {
+		return nil, err
+	}
+
+	report := validation.Report(source, target)
+	path := filepath.Join(dir, "report.txt")
+	if err := utils.AtomicallyWrite(path, []byte(report)); err != nil {
+		return nil, err
+	}
+
+	mismatches := validation.Compare(source, target)
+	if len(mismatches) > 0 {
+		gplog.Warn("%d mismatches found between the source and target data. See %s.", len(mismatches), path)
+	}
+
+	return &idl.ValidateReply{
+		Report:     report,
+		Mismatches: int32(len(mismatches)),
+		ReportPath: path,
+	}, nil
+}
+
+// SnapshotData tallies the rows of every user table in every database of the
+// running cluster.
+func SnapshotData(conn *greenplum.Conn, cluster *greenplum.Cluster, destination idl.ClusterDestination, name string, mode string) (validation.Snapshot, error) {
+	snapshot := validation.Snapshot{
+		Name:    name,
+		Mode:    mode,
+		Version: cluster.Version.String(),
+		Taken:   time.Now(),
+	}
+
+	var databases []string
+	err := withDatabase(conn, cluster, destination, "", func(db *sql.DB) error {
+		var err error
+		databases, err = inventory.QueryDatabases(db)
+		return err
+	})
+	if err != nil {
+		return validation.Snapshot{}, err
+	}
+
+	for _, database := range databases {
+		err := withDatabase(conn, cluster, destination, database, func(db *sql.DB) error {
+			tables, err := validation.QueryTables(db, cluster.Version)
+			if err != nil {
+				return xerrors.Errorf("database %q: %w", database, err)
+			}
+
+			for _, table := range tables {
+				tallies, err := validation.QueryTallies(db, table, mode)
+				if err != nil {
+					return xerrors.Errorf("database %q: %w", database, err)
+				}
+
+				snapshot.Tables = append(snapshot.Tables, validation.Table{Database: database, Name: table, Segments: tallies})
+			}
+
+			return nil
+		})
+		if err != nil {
+			return validation.Snapshot{}, err
+		}
+	}
+
+	return snapshot, nil
+}
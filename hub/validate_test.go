@@ -0,0 +1,72 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package hub_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/hub"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/validation"
+)
+
+func TestValidateTarget(t *testing.T) {
+	testlog.SetupLogger()
+
+	source := validation.Snapshot{
+		Name:    validation.Source,
+		Mode:    validation.RowCounts,
+		Version: "7.0.0",
+		Tables: []validation.Table{
+			{Database: "postgres", Name: "public.orders", Segments: []validation.Tally{{Segment: 0, Rows: 10}}},
+		},
+	}
+
+	target := validation.Snapshot{
+		Name:    validation.Target,
+		Version: "7.1.0",
+		Tables: []validation.Table{
+			{Database: "postgres", Name: "public.orders", Segments: []validation.Tally{{Segment: 0, Rows: 9}}},
+		},
+	}
+
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	reply, err := hub.ValidateTarget(dir, source, target)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expected := validation.Report(source, target)
+	if reply.GetReport() != expected {
+		t.Errorf("got report %q want %q", reply.GetReport(), expected)
+	}
+
+	if reply.GetMismatches() != 1 {
+		t.Errorf("got %d mismatches want %d", reply.GetMismatches(), 1)
+	}
+
+	path := filepath.Join(dir, "report.txt")
+	if reply.GetReportPath() != path {
+		t.Errorf("got report path %q want %q", reply.GetReportPath(), path)
+	}
+
+	report := testutils.MustReadFile(t, path)
+	if report != expected {
+		t.Errorf("got report %q want %q", report, expected)
+	}
+
+	saved, err := validation.Load(validation.Path(dir, validation.Target))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if !reflect.DeepEqual(saved, target) {
+		t.Errorf("got saved target %+v want %+v", saved, target)
+	}
+}
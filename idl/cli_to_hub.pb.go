@@ -128,6 +128,18 @@ const (
 	Substep_WAIT_FOR_CLUSTER_TO_BE_READY_AFTER_UPDATING_CATALOG           Substep = 33
 	Substep_STOP_TARGET_CLUSTER                                           Substep = 34
 	Substep_SETTING_DYNAMIC_LIBRARY_PATH_ON_TARGET_CLUSTER                Substep = 35
+	Substep_PREFLIGHT_CHECKS                                              Substep = 36
+	Substep_INVENTORY_SOURCE_CLUSTER                                      Substep = 37
+	Substep_INVENTORY_TARGET_CLUSTER                                      Substep = 38
+	Substep_SNAPSHOT_SOURCE_DATA                                          Substep = 39
+	Substep_SNAPSHOT_SOURCE_CLUSTER                                       Substep = 40
+	Substep_RESTORE_SOURCE_SNAPSHOTS                                      Substep = 41
+	Substep_DELETE_SOURCE_SNAPSHOTS                                       Substep = 42
+	Substep_RESTORE_TARGET_CONF_FILES                                     Substep = 43
+	Substep_RESTORE_DATA_DIRECTORIES                                      Substep = 44
+	Substep_POINT_OF_NO_RETURN                                            Substep = 46
+	Substep_BACKUP_SOURCE_CLUSTER                                         Substep = 47
+	Substep_TRANSFER_SOURCE_PRIMARIES                                     Substep = 48
 )
 
 var Substep_name = map[int32]string{
@@ -167,6 +179,18 @@ var Substep_name = map[int32]string{
 	33: "WAIT_FOR_CLUSTER_TO_BE_READY_AFTER_UPDATING_CATALOG",
 	34: "STOP_TARGET_CLUSTER",
 	35: "SETTING_DYNAMIC_LIBRARY_PATH_ON_TARGET_CLUSTER",
+	36: "PREFLIGHT_CHECKS",
+	37: "INVENTORY_SOURCE_CLUSTER",
+	38: "INVENTORY_TARGET_CLUSTER",
+	39: "SNAPSHOT_SOURCE_DATA",
+	40: "SNAPSHOT_SOURCE_CLUSTER",
+	41: "RESTORE_SOURCE_SNAPSHOTS",
+	42: "DELETE_SOURCE_SNAPSHOTS",
+	43: "RESTORE_TARGET_CONF_FILES",
+	44: "RESTORE_DATA_DIRECTORIES",
+	46: "POINT_OF_NO_RETURN",
+	47: "BACKUP_SOURCE_CLUSTER",
+	48: "TRANSFER_SOURCE_PRIMARIES",
 }
 
 var Substep_value = map[string]int32{
@@ -206,6 +230,18 @@ var Substep_value = map[string]int32{
 	"WAIT_FOR_CLUSTER_TO_BE_READY_AFTER_UPDATING_CATALOG":           33,
 	"STOP_TARGET_CLUSTER":                            34,
 	"SETTING_DYNAMIC_LIBRARY_PATH_ON_TARGET_CLUSTER": 35,
+	"PREFLIGHT_CHECKS":                               36,
+	"INVENTORY_SOURCE_CLUSTER":                       37,
+	"INVENTORY_TARGET_CLUSTER":                       38,
+	"SNAPSHOT_SOURCE_DATA":                           39,
+	"SNAPSHOT_SOURCE_CLUSTER":                        40,
+	"RESTORE_SOURCE_SNAPSHOTS":                       41,
+	"DELETE_SOURCE_SNAPSHOTS":                        42,
+	"RESTORE_TARGET_CONF_FILES":                      43,
+	"RESTORE_DATA_DIRECTORIES":                       44,
+	"POINT_OF_NO_RETURN":                             46,
+	"BACKUP_SOURCE_CLUSTER":                          47,
+	"TRANSFER_SOURCE_PRIMARIES":                      48,
 }
 
 func (x Substep) String() string {
@@ -275,7 +311,7 @@ func (x Chunk_Type) String() string {
 }
 
 func (Chunk_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{12, 0}
+	return fileDescriptor_631e66a01873be02, []int{19, 0}
 }
 
 type InitializeRequest struct {
@@ -283,10 +319,22 @@ type InitializeRequest struct {
 	SourceGPHome         string   `protobuf:"bytes,2,opt,name=sourceGPHome,proto3" json:"sourceGPHome,omitempty"`
 	TargetGPHome         string   `protobuf:"bytes,3,opt,name=targetGPHome,proto3" json:"targetGPHome,omitempty"`
 	SourcePort           int32    `protobuf:"varint,4,opt,name=sourcePort,proto3" json:"sourcePort,omitempty"`
-	LinkMode             bool     `protobuf:"varint,5,opt,name=linkMode,proto3" json:"linkMode,omitempty"`
 	UseHbaHostnames      bool     `protobuf:"varint,6,opt,name=useHbaHostnames,proto3" json:"useHbaHostnames,omitempty"`
 	Ports                []uint32 `protobuf:"varint,7,rep,packed,name=ports,proto3" json:"ports,omitempty"`
 	DiskFreeRatio        float64  `protobuf:"fixed64,8,opt,name=diskFreeRatio,proto3" json:"diskFreeRatio,omitempty"`
+	EstimateDiskSpace    bool     `protobuf:"varint,9,opt,name=estimateDiskSpace,proto3" json:"estimateDiskSpace,omitempty"`
+	DataValidation       string   `protobuf:"bytes,10,opt,name=dataValidation,proto3" json:"dataValidation,omitempty"`
+	SnapshotProvider     string   `protobuf:"bytes,11,opt,name=snapshotProvider,proto3" json:"snapshotProvider,omitempty"`
+	Mode                 Mode     `protobuf:"varint,12,opt,name=mode,proto3,enum=idl.Mode" json:"mode,omitempty"`
+	MirrorResync         string   `protobuf:"bytes,13,opt,name=mirrorResync,proto3" json:"mirrorResync,omitempty"`
+	BackupProvider       string   `protobuf:"bytes,14,opt,name=backupProvider,proto3" json:"backupProvider,omitempty"`
+	BackupCommand        string   `protobuf:"bytes,15,opt,name=backupCommand,proto3" json:"backupCommand,omitempty"`
+	BackupTimestamp      string   `protobuf:"bytes,16,opt,name=backupTimestamp,proto3" json:"backupTimestamp,omitempty"`
+	TargetDatadirLayout  string   `protobuf:"bytes,17,opt,name=targetDatadirLayout,proto3" json:"targetDatadirLayout,omitempty"`
+	TargetHostMapping    string   `protobuf:"bytes,18,opt,name=targetHostMapping,proto3" json:"targetHostMapping,omitempty"`
+	PortStrategy         string   `protobuf:"bytes,19,opt,name=portStrategy,proto3" json:"portStrategy,omitempty"`
+	TargetPortMap        string   `protobuf:"bytes,20,opt,name=targetPortMap,proto3" json:"targetPortMap,omitempty"`
+	KeepTempPorts        bool     `protobuf:"varint,21,opt,name=keepTempPorts,proto3" json:"keepTempPorts,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -345,13 +393,6 @@ func (m *InitializeRequest) GetSourcePort() int32 {
 	return 0
 }
 
-func (m *InitializeRequest) GetLinkMode() bool {
-	if m != nil {
-		return m.LinkMode
-	}
-	return false
-}
-
 func (m *InitializeRequest) GetUseHbaHostnames() bool {
 	if m != nil {
 		return m.UseHbaHostnames
@@ -373,6 +414,97 @@ func (m *InitializeRequest) GetDiskFreeRatio() float64 {
 	return 0
 }
 
+func (m *InitializeRequest) GetEstimateDiskSpace() bool {
+	if m != nil {
+		return m.EstimateDiskSpace
+	}
+	return false
+}
+
+func (m *InitializeRequest) GetDataValidation() string {
+	if m != nil {
+		return m.DataValidation
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetSnapshotProvider() string {
+	if m != nil {
+		return m.SnapshotProvider
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetMode() Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return Mode_UNKNOWN_MODE
+}
+
+func (m *InitializeRequest) GetMirrorResync() string {
+	if m != nil {
+		return m.MirrorResync
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetBackupProvider() string {
+	if m != nil {
+		return m.BackupProvider
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetBackupCommand() string {
+	if m != nil {
+		return m.BackupCommand
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetBackupTimestamp() string {
+	if m != nil {
+		return m.BackupTimestamp
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetTargetDatadirLayout() string {
+	if m != nil {
+		return m.TargetDatadirLayout
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetTargetHostMapping() string {
+	if m != nil {
+		return m.TargetHostMapping
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetPortStrategy() string {
+	if m != nil {
+		return m.PortStrategy
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetTargetPortMap() string {
+	if m != nil {
+		return m.TargetPortMap
+	}
+	return ""
+}
+
+func (m *InitializeRequest) GetKeepTempPorts() bool {
+	if m != nil {
+		return m.KeepTempPorts
+	}
+	return false
+}
+
 type InitializeCreateClusterRequest struct {
 	DynamicLibraryPath   string   `protobuf:"bytes,1,opt,name=dynamicLibraryPath,proto3" json:"dynamicLibraryPath,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -413,9 +545,15 @@ func (m *InitializeCreateClusterRequest) GetDynamicLibraryPath() string {
 }
 
 type ExecuteRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// onlyContents retries upgrading only the primaries with these content
+	// ids when set.
+	OnlyContents []int32 `protobuf:"varint,1,rep,packed,name=onlyContents,proto3" json:"onlyContents,omitempty"`
+	// pointOfNoReturnConfirmation acknowledges passing the point of no return
+	// of the step. It must match the token derived from the upgrade ID.
+	PointOfNoReturnConfirmation string   `protobuf:"bytes,2,opt,name=pointOfNoReturnConfirmation,proto3" json:"pointOfNoReturnConfirmation,omitempty"`
+	XXX_NoUnkeyedLiteral        struct{} `json:"-"`
+	XXX_unrecognized            []byte   `json:"-"`
+	XXX_sizecache               int32    `json:"-"`
 }
 
 func (m *ExecuteRequest) Reset()         { *m = ExecuteRequest{} }
@@ -443,10 +581,25 @@ func (m *ExecuteRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ExecuteRequest proto.InternalMessageInfo
 
+func (m *ExecuteRequest) GetOnlyContents() []int32 {
+	if m != nil {
+		return m.OnlyContents
+	}
+	return nil
+}
+
+func (m *ExecuteRequest) GetPointOfNoReturnConfirmation() string {
+	if m != nil {
+		return m.PointOfNoReturnConfirmation
+	}
+	return ""
+}
+
 type FinalizeRequest struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	PointOfNoReturnConfirmation string   `protobuf:"bytes,1,opt,name=pointOfNoReturnConfirmation,proto3" json:"pointOfNoReturnConfirmation,omitempty"`
+	XXX_NoUnkeyedLiteral        struct{} `json:"-"`
+	XXX_unrecognized            []byte   `json:"-"`
+	XXX_sizecache               int32    `json:"-"`
 }
 
 func (m *FinalizeRequest) Reset()         { *m = FinalizeRequest{} }
@@ -474,6 +627,13 @@ func (m *FinalizeRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_FinalizeRequest proto.InternalMessageInfo
 
+func (m *FinalizeRequest) GetPointOfNoReturnConfirmation() string {
+	if m != nil {
+		return m.PointOfNoReturnConfirmation
+	}
+	return ""
+}
+
 type RevertRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -637,6 +797,362 @@ func (m *StopServicesReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StopServicesReply proto.InternalMessageInfo
 
+type ValidateRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidateRequest) Reset()         { *m = ValidateRequest{} }
+func (m *ValidateRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidateRequest) ProtoMessage()    {}
+func (*ValidateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{9}
+}
+
+func (m *ValidateRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateRequest.Unmarshal(m, b)
+}
+func (m *ValidateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateRequest.Merge(m, src)
+}
+func (m *ValidateRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidateRequest.Size(m)
+}
+func (m *ValidateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateRequest proto.InternalMessageInfo
+
+type ValidateReply struct {
+	Report               string   `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	Mismatches           int32    `protobuf:"varint,2,opt,name=mismatches,proto3" json:"mismatches,omitempty"`
+	ReportPath           string   `protobuf:"bytes,3,opt,name=reportPath,proto3" json:"reportPath,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidateReply) Reset()         { *m = ValidateReply{} }
+func (m *ValidateReply) String() string { return proto.CompactTextString(m) }
+func (*ValidateReply) ProtoMessage()    {}
+func (*ValidateReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{10}
+}
+
+func (m *ValidateReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateReply.Unmarshal(m, b)
+}
+func (m *ValidateReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateReply.Marshal(b, m, deterministic)
+}
+func (m *ValidateReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateReply.Merge(m, src)
+}
+func (m *ValidateReply) XXX_Size() int {
+	return xxx_messageInfo_ValidateReply.Size(m)
+}
+func (m *ValidateReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateReply proto.InternalMessageInfo
+
+func (m *ValidateReply) GetReport() string {
+	if m != nil {
+		return m.Report
+	}
+	return ""
+}
+
+func (m *ValidateReply) GetMismatches() int32 {
+	if m != nil {
+		return m.Mismatches
+	}
+	return 0
+}
+
+func (m *ValidateReply) GetReportPath() string {
+	if m != nil {
+		return m.ReportPath
+	}
+	return ""
+}
+
+type SupportBundleRequest struct {
+	// dir is the directory on the coordinator host to write the bundle to.
+	Dir             string `protobuf:"bytes,1,opt,name=dir,proto3" json:"dir,omitempty"`
+	RedactPasswords bool   `protobuf:"varint,2,opt,name=redactPasswords,proto3" json:"redactPasswords,omitempty"`
+	RedactHostnames bool   `protobuf:"varint,3,opt,name=redactHostnames,proto3" json:"redactHostnames,omitempty"`
+	// version is the gpupgrade version recorded in the manifest.
+	Version              string   `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SupportBundleRequest) Reset()         { *m = SupportBundleRequest{} }
+func (m *SupportBundleRequest) String() string { return proto.CompactTextString(m) }
+func (*SupportBundleRequest) ProtoMessage()    {}
+func (*SupportBundleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{11}
+}
+
+func (m *SupportBundleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SupportBundleRequest.Unmarshal(m, b)
+}
+func (m *SupportBundleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SupportBundleRequest.Marshal(b, m, deterministic)
+}
+func (m *SupportBundleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupportBundleRequest.Merge(m, src)
+}
+func (m *SupportBundleRequest) XXX_Size() int {
+	return xxx_messageInfo_SupportBundleRequest.Size(m)
+}
+func (m *SupportBundleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupportBundleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupportBundleRequest proto.InternalMessageInfo
+
+func (m *SupportBundleRequest) GetDir() string {
+	if m != nil {
+		return m.Dir
+	}
+	return ""
+}
+
+func (m *SupportBundleRequest) GetRedactPasswords() bool {
+	if m != nil {
+		return m.RedactPasswords
+	}
+	return false
+}
+
+func (m *SupportBundleRequest) GetRedactHostnames() bool {
+	if m != nil {
+		return m.RedactHostnames
+	}
+	return false
+}
+
+func (m *SupportBundleRequest) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+type SupportBundleReply struct {
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// errors are the hosts whose files could not be collected.
+	Errors               []string `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SupportBundleReply) Reset()         { *m = SupportBundleReply{} }
+func (m *SupportBundleReply) String() string { return proto.CompactTextString(m) }
+func (*SupportBundleReply) ProtoMessage()    {}
+func (*SupportBundleReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{12}
+}
+
+func (m *SupportBundleReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SupportBundleReply.Unmarshal(m, b)
+}
+func (m *SupportBundleReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SupportBundleReply.Marshal(b, m, deterministic)
+}
+func (m *SupportBundleReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SupportBundleReply.Merge(m, src)
+}
+func (m *SupportBundleReply) XXX_Size() int {
+	return xxx_messageInfo_SupportBundleReply.Size(m)
+}
+func (m *SupportBundleReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SupportBundleReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SupportBundleReply proto.InternalMessageInfo
+
+func (m *SupportBundleReply) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *SupportBundleReply) GetErrors() []string {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
+type SegmentsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SegmentsRequest) Reset()         { *m = SegmentsRequest{} }
+func (m *SegmentsRequest) String() string { return proto.CompactTextString(m) }
+func (*SegmentsRequest) ProtoMessage()    {}
+func (*SegmentsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{13}
+}
+
+func (m *SegmentsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentsRequest.Unmarshal(m, b)
+}
+func (m *SegmentsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentsRequest.Marshal(b, m, deterministic)
+}
+func (m *SegmentsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentsRequest.Merge(m, src)
+}
+func (m *SegmentsRequest) XXX_Size() int {
+	return xxx_messageInfo_SegmentsRequest.Size(m)
+}
+func (m *SegmentsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentsRequest proto.InternalMessageInfo
+
+type SegmentsReply struct {
+	Segments             []*SegmentUpgradeStatus `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *SegmentsReply) Reset()         { *m = SegmentsReply{} }
+func (m *SegmentsReply) String() string { return proto.CompactTextString(m) }
+func (*SegmentsReply) ProtoMessage()    {}
+func (*SegmentsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{14}
+}
+
+func (m *SegmentsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentsReply.Unmarshal(m, b)
+}
+func (m *SegmentsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentsReply.Marshal(b, m, deterministic)
+}
+func (m *SegmentsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentsReply.Merge(m, src)
+}
+func (m *SegmentsReply) XXX_Size() int {
+	return xxx_messageInfo_SegmentsReply.Size(m)
+}
+func (m *SegmentsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentsReply proto.InternalMessageInfo
+
+func (m *SegmentsReply) GetSegments() []*SegmentUpgradeStatus {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+type SegmentUpgradeStatus struct {
+	ContentID            int32    `protobuf:"varint,1,opt,name=contentID,proto3" json:"contentID,omitempty"`
+	DbID                 int32    `protobuf:"varint,2,opt,name=dbID,proto3" json:"dbID,omitempty"`
+	Hostname             string   `protobuf:"bytes,3,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	DataDir              string   `protobuf:"bytes,4,opt,name=dataDir,proto3" json:"dataDir,omitempty"`
+	Status               Status   `protobuf:"varint,5,opt,name=status,proto3,enum=idl.Status" json:"status,omitempty"`
+	PgUpgradeDir         string   `protobuf:"bytes,6,opt,name=pgUpgradeDir,proto3" json:"pgUpgradeDir,omitempty"`
+	Error                string   `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SegmentUpgradeStatus) Reset()         { *m = SegmentUpgradeStatus{} }
+func (m *SegmentUpgradeStatus) String() string { return proto.CompactTextString(m) }
+func (*SegmentUpgradeStatus) ProtoMessage()    {}
+func (*SegmentUpgradeStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{15}
+}
+
+func (m *SegmentUpgradeStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentUpgradeStatus.Unmarshal(m, b)
+}
+func (m *SegmentUpgradeStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentUpgradeStatus.Marshal(b, m, deterministic)
+}
+func (m *SegmentUpgradeStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentUpgradeStatus.Merge(m, src)
+}
+func (m *SegmentUpgradeStatus) XXX_Size() int {
+	return xxx_messageInfo_SegmentUpgradeStatus.Size(m)
+}
+func (m *SegmentUpgradeStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentUpgradeStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentUpgradeStatus proto.InternalMessageInfo
+
+func (m *SegmentUpgradeStatus) GetContentID() int32 {
+	if m != nil {
+		return m.ContentID
+	}
+	return 0
+}
+
+func (m *SegmentUpgradeStatus) GetDbID() int32 {
+	if m != nil {
+		return m.DbID
+	}
+	return 0
+}
+
+func (m *SegmentUpgradeStatus) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *SegmentUpgradeStatus) GetDataDir() string {
+	if m != nil {
+		return m.DataDir
+	}
+	return ""
+}
+
+func (m *SegmentUpgradeStatus) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_UNKNOWN_STATUS
+}
+
+func (m *SegmentUpgradeStatus) GetPgUpgradeDir() string {
+	if m != nil {
+		return m.PgUpgradeDir
+	}
+	return ""
+}
+
+func (m *SegmentUpgradeStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type SubstepStatus struct {
 	Step                 Substep  `protobuf:"varint,1,opt,name=step,proto3,enum=idl.Substep" json:"step,omitempty"`
 	Status               Status   `protobuf:"varint,2,opt,name=status,proto3,enum=idl.Status" json:"status,omitempty"`
@@ -649,7 +1165,7 @@ func (m *SubstepStatus) Reset()         { *m = SubstepStatus{} }
 func (m *SubstepStatus) String() string { return proto.CompactTextString(m) }
 func (*SubstepStatus) ProtoMessage()    {}
 func (*SubstepStatus) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{9}
+	return fileDescriptor_631e66a01873be02, []int{16}
 }
 
 func (m *SubstepStatus) XXX_Unmarshal(b []byte) error {
@@ -694,7 +1210,7 @@ func (m *PrepareInitClusterRequest) Reset()         { *m = PrepareInitClusterReq
 func (m *PrepareInitClusterRequest) String() string { return proto.CompactTextString(m) }
 func (*PrepareInitClusterRequest) ProtoMessage()    {}
 func (*PrepareInitClusterRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{10}
+	return fileDescriptor_631e66a01873be02, []int{17}
 }
 
 func (m *PrepareInitClusterRequest) XXX_Unmarshal(b []byte) error {
@@ -725,7 +1241,7 @@ func (m *PrepareInitClusterReply) Reset()         { *m = PrepareInitClusterReply
 func (m *PrepareInitClusterReply) String() string { return proto.CompactTextString(m) }
 func (*PrepareInitClusterReply) ProtoMessage()    {}
 func (*PrepareInitClusterReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{11}
+	return fileDescriptor_631e66a01873be02, []int{18}
 }
 
 func (m *PrepareInitClusterReply) XXX_Unmarshal(b []byte) error {
@@ -758,7 +1274,7 @@ func (m *Chunk) Reset()         { *m = Chunk{} }
 func (m *Chunk) String() string { return proto.CompactTextString(m) }
 func (*Chunk) ProtoMessage()    {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{12}
+	return fileDescriptor_631e66a01873be02, []int{19}
 }
 
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
@@ -808,7 +1324,7 @@ func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{13}
+	return fileDescriptor_631e66a01873be02, []int{20}
 }
 
 func (m *Message) XXX_Unmarshal(b []byte) error {
@@ -904,7 +1420,7 @@ func (m *Response) Reset()         { *m = Response{} }
 func (m *Response) String() string { return proto.CompactTextString(m) }
 func (*Response) ProtoMessage()    {}
 func (*Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{14}
+	return fileDescriptor_631e66a01873be02, []int{21}
 }
 
 func (m *Response) XXX_Unmarshal(b []byte) error {
@@ -1010,7 +1526,7 @@ func (m *InitializeResponse) Reset()         { *m = InitializeResponse{} }
 func (m *InitializeResponse) String() string { return proto.CompactTextString(m) }
 func (*InitializeResponse) ProtoMessage()    {}
 func (*InitializeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{15}
+	return fileDescriptor_631e66a01873be02, []int{22}
 }
 
 func (m *InitializeResponse) XXX_Unmarshal(b []byte) error {
@@ -1058,7 +1574,7 @@ func (m *Cluster) Reset()         { *m = Cluster{} }
 func (m *Cluster) String() string { return proto.CompactTextString(m) }
 func (*Cluster) ProtoMessage()    {}
 func (*Cluster) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{16}
+	return fileDescriptor_631e66a01873be02, []int{23}
 }
 
 func (m *Cluster) XXX_Unmarshal(b []byte) error {
@@ -1111,7 +1627,7 @@ func (m *ExecuteResponse) Reset()         { *m = ExecuteResponse{} }
 func (m *ExecuteResponse) String() string { return proto.CompactTextString(m) }
 func (*ExecuteResponse) ProtoMessage()    {}
 func (*ExecuteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{17}
+	return fileDescriptor_631e66a01873be02, []int{24}
 }
 
 func (m *ExecuteResponse) XXX_Unmarshal(b []byte) error {
@@ -1145,6 +1661,7 @@ type FinalizeResponse struct {
 	LogArchiveDirectory                    string   `protobuf:"bytes,3,opt,name=LogArchiveDirectory,proto3" json:"LogArchiveDirectory,omitempty"`
 	ArchivedSourceCoordinatorDataDirectory string   `protobuf:"bytes,4,opt,name=ArchivedSourceCoordinatorDataDirectory,proto3" json:"ArchivedSourceCoordinatorDataDirectory,omitempty"`
 	UpgradeID                              string   `protobuf:"bytes,5,opt,name=UpgradeID,proto3" json:"UpgradeID,omitempty"`
+	Backup                                 *Backup  `protobuf:"bytes,6,opt,name=backup,proto3" json:"backup,omitempty"`
 	XXX_NoUnkeyedLiteral                   struct{} `json:"-"`
 	XXX_unrecognized                       []byte   `json:"-"`
 	XXX_sizecache                          int32    `json:"-"`
@@ -1154,7 +1671,7 @@ func (m *FinalizeResponse) Reset()         { *m = FinalizeResponse{} }
 func (m *FinalizeResponse) String() string { return proto.CompactTextString(m) }
 func (*FinalizeResponse) ProtoMessage()    {}
 func (*FinalizeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{18}
+	return fileDescriptor_631e66a01873be02, []int{25}
 }
 
 func (m *FinalizeResponse) XXX_Unmarshal(b []byte) error {
@@ -1210,10 +1727,18 @@ func (m *FinalizeResponse) GetUpgradeID() string {
 	return ""
 }
 
+func (m *FinalizeResponse) GetBackup() *Backup {
+	if m != nil {
+		return m.Backup
+	}
+	return nil
+}
+
 type RevertResponse struct {
 	Source               *Cluster `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"`
 	SourceVersion        string   `protobuf:"bytes,2,opt,name=SourceVersion,proto3" json:"SourceVersion,omitempty"`
 	LogArchiveDirectory  string   `protobuf:"bytes,3,opt,name=LogArchiveDirectory,proto3" json:"LogArchiveDirectory,omitempty"`
+	Backup               *Backup  `protobuf:"bytes,4,opt,name=backup,proto3" json:"backup,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1223,7 +1748,7 @@ func (m *RevertResponse) Reset()         { *m = RevertResponse{} }
 func (m *RevertResponse) String() string { return proto.CompactTextString(m) }
 func (*RevertResponse) ProtoMessage()    {}
 func (*RevertResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{19}
+	return fileDescriptor_631e66a01873be02, []int{26}
 }
 
 func (m *RevertResponse) XXX_Unmarshal(b []byte) error {
@@ -1265,6 +1790,61 @@ func (m *RevertResponse) GetLogArchiveDirectory() string {
 	return ""
 }
 
+func (m *RevertResponse) GetBackup() *Backup {
+	if m != nil {
+		return m.Backup
+	}
+	return nil
+}
+
+// Backup describes the backup of the source cluster taken during execute.
+type Backup struct {
+	Description          string   `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Restore              string   `protobuf:"bytes,2,opt,name=restore,proto3" json:"restore,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Backup) Reset()         { *m = Backup{} }
+func (m *Backup) String() string { return proto.CompactTextString(m) }
+func (*Backup) ProtoMessage()    {}
+func (*Backup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{27}
+}
+
+func (m *Backup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Backup.Unmarshal(m, b)
+}
+func (m *Backup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Backup.Marshal(b, m, deterministic)
+}
+func (m *Backup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Backup.Merge(m, src)
+}
+func (m *Backup) XXX_Size() int {
+	return xxx_messageInfo_Backup.Size(m)
+}
+func (m *Backup) XXX_DiscardUnknown() {
+	xxx_messageInfo_Backup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Backup proto.InternalMessageInfo
+
+func (m *Backup) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Backup) GetRestore() string {
+	if m != nil {
+		return m.Restore
+	}
+	return ""
+}
+
 type GetConfigRequest struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1276,7 +1856,7 @@ func (m *GetConfigRequest) Reset()         { *m = GetConfigRequest{} }
 func (m *GetConfigRequest) String() string { return proto.CompactTextString(m) }
 func (*GetConfigRequest) ProtoMessage()    {}
 func (*GetConfigRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{20}
+	return fileDescriptor_631e66a01873be02, []int{28}
 }
 
 func (m *GetConfigRequest) XXX_Unmarshal(b []byte) error {
@@ -1315,7 +1895,7 @@ func (m *GetConfigReply) Reset()         { *m = GetConfigReply{} }
 func (m *GetConfigReply) String() string { return proto.CompactTextString(m) }
 func (*GetConfigReply) ProtoMessage()    {}
 func (*GetConfigReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{21}
+	return fileDescriptor_631e66a01873be02, []int{29}
 }
 
 func (m *GetConfigReply) XXX_Unmarshal(b []byte) error {
@@ -1343,6 +1923,78 @@ func (m *GetConfigReply) GetValue() string {
 	return ""
 }
 
+type ShowConfigRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShowConfigRequest) Reset()         { *m = ShowConfigRequest{} }
+func (m *ShowConfigRequest) String() string { return proto.CompactTextString(m) }
+func (*ShowConfigRequest) ProtoMessage()    {}
+func (*ShowConfigRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{30}
+}
+
+func (m *ShowConfigRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShowConfigRequest.Unmarshal(m, b)
+}
+func (m *ShowConfigRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShowConfigRequest.Marshal(b, m, deterministic)
+}
+func (m *ShowConfigRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShowConfigRequest.Merge(m, src)
+}
+func (m *ShowConfigRequest) XXX_Size() int {
+	return xxx_messageInfo_ShowConfigRequest.Size(m)
+}
+func (m *ShowConfigRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShowConfigRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShowConfigRequest proto.InternalMessageInfo
+
+type ShowConfigReply struct {
+	// config is the JSON encoding of the hub.ConfigView of the persisted
+	// configuration.
+	Config               []byte   `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ShowConfigReply) Reset()         { *m = ShowConfigReply{} }
+func (m *ShowConfigReply) String() string { return proto.CompactTextString(m) }
+func (*ShowConfigReply) ProtoMessage()    {}
+func (*ShowConfigReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{31}
+}
+
+func (m *ShowConfigReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ShowConfigReply.Unmarshal(m, b)
+}
+func (m *ShowConfigReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ShowConfigReply.Marshal(b, m, deterministic)
+}
+func (m *ShowConfigReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ShowConfigReply.Merge(m, src)
+}
+func (m *ShowConfigReply) XXX_Size() int {
+	return xxx_messageInfo_ShowConfigReply.Size(m)
+}
+func (m *ShowConfigReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ShowConfigReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ShowConfigReply proto.InternalMessageInfo
+
+func (m *ShowConfigReply) GetConfig() []byte {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
 // Used to set the gRPC status details that the CLI converts to a NextActions
 // error type to be displayed to the user.
 type NextActions struct {
@@ -1356,7 +2008,7 @@ func (m *NextActions) Reset()         { *m = NextActions{} }
 func (m *NextActions) String() string { return proto.CompactTextString(m) }
 func (*NextActions) ProtoMessage()    {}
 func (*NextActions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_631e66a01873be02, []int{22}
+	return fileDescriptor_631e66a01873be02, []int{32}
 }
 
 func (m *NextActions) XXX_Unmarshal(b []byte) error {
@@ -1384,6 +2036,55 @@ func (m *NextActions) GetNextActions() string {
 	return ""
 }
 
+// Used to set the gRPC status details when a step stops before its point of
+// no return to require acknowledgement from the user.
+type PointOfNoReturn struct {
+	Substep              Substep  `protobuf:"varint,1,opt,name=substep,proto3,enum=idl.Substep" json:"substep,omitempty"`
+	Confirmation         string   `protobuf:"bytes,2,opt,name=confirmation,proto3" json:"confirmation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PointOfNoReturn) Reset()         { *m = PointOfNoReturn{} }
+func (m *PointOfNoReturn) String() string { return proto.CompactTextString(m) }
+func (*PointOfNoReturn) ProtoMessage()    {}
+func (*PointOfNoReturn) Descriptor() ([]byte, []int) {
+	return fileDescriptor_631e66a01873be02, []int{33}
+}
+
+func (m *PointOfNoReturn) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PointOfNoReturn.Unmarshal(m, b)
+}
+func (m *PointOfNoReturn) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PointOfNoReturn.Marshal(b, m, deterministic)
+}
+func (m *PointOfNoReturn) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PointOfNoReturn.Merge(m, src)
+}
+func (m *PointOfNoReturn) XXX_Size() int {
+	return xxx_messageInfo_PointOfNoReturn.Size(m)
+}
+func (m *PointOfNoReturn) XXX_DiscardUnknown() {
+	xxx_messageInfo_PointOfNoReturn.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PointOfNoReturn proto.InternalMessageInfo
+
+func (m *PointOfNoReturn) GetSubstep() Substep {
+	if m != nil {
+		return m.Substep
+	}
+	return Substep_UNKNOWN_SUBSTEP
+}
+
+func (m *PointOfNoReturn) GetConfirmation() string {
+	if m != nil {
+		return m.Confirmation
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("idl.ClusterDestination", ClusterDestination_name, ClusterDestination_value)
 	proto.RegisterEnum("idl.Step", Step_name, Step_value)
@@ -1399,6 +2100,13 @@ func init() {
 	proto.RegisterType((*RestartAgentsReply)(nil), "idl.RestartAgentsReply")
 	proto.RegisterType((*StopServicesRequest)(nil), "idl.StopServicesRequest")
 	proto.RegisterType((*StopServicesReply)(nil), "idl.StopServicesReply")
+	proto.RegisterType((*ValidateRequest)(nil), "idl.ValidateRequest")
+	proto.RegisterType((*ValidateReply)(nil), "idl.ValidateReply")
+	proto.RegisterType((*SupportBundleRequest)(nil), "idl.SupportBundleRequest")
+	proto.RegisterType((*SupportBundleReply)(nil), "idl.SupportBundleReply")
+	proto.RegisterType((*SegmentsRequest)(nil), "idl.SegmentsRequest")
+	proto.RegisterType((*SegmentsReply)(nil), "idl.SegmentsReply")
+	proto.RegisterType((*SegmentUpgradeStatus)(nil), "idl.SegmentUpgradeStatus")
 	proto.RegisterType((*SubstepStatus)(nil), "idl.SubstepStatus")
 	proto.RegisterType((*PrepareInitClusterRequest)(nil), "idl.PrepareInitClusterRequest")
 	proto.RegisterType((*PrepareInitClusterReply)(nil), "idl.PrepareInitClusterReply")
@@ -1410,122 +2118,176 @@ func init() {
 	proto.RegisterType((*ExecuteResponse)(nil), "idl.ExecuteResponse")
 	proto.RegisterType((*FinalizeResponse)(nil), "idl.FinalizeResponse")
 	proto.RegisterType((*RevertResponse)(nil), "idl.RevertResponse")
+	proto.RegisterType((*Backup)(nil), "idl.Backup")
 	proto.RegisterType((*GetConfigRequest)(nil), "idl.GetConfigRequest")
 	proto.RegisterType((*GetConfigReply)(nil), "idl.GetConfigReply")
+	proto.RegisterType((*ShowConfigRequest)(nil), "idl.ShowConfigRequest")
+	proto.RegisterType((*ShowConfigReply)(nil), "idl.ShowConfigReply")
 	proto.RegisterType((*NextActions)(nil), "idl.NextActions")
+	proto.RegisterType((*PointOfNoReturn)(nil), "idl.PointOfNoReturn")
 }
 
 func init() { proto.RegisterFile("cli_to_hub.proto", fileDescriptor_631e66a01873be02) }
 
 var fileDescriptor_631e66a01873be02 = []byte{
-	// 1711 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0x5f, 0x6f, 0xdb, 0xc8,
-	0x11, 0x97, 0x6c, 0x59, 0x96, 0x47, 0x96, 0xb4, 0x5e, 0xf9, 0x8f, 0xec, 0xe4, 0x52, 0x95, 0x39,
-	0x04, 0x46, 0xae, 0x70, 0x03, 0x5d, 0xd1, 0x43, 0x0b, 0x1c, 0x50, 0x8a, 0x5c, 0x49, 0x84, 0x25,
-	0x92, 0x58, 0x52, 0x4e, 0xdd, 0x17, 0x82, 0x96, 0x36, 0x0e, 0x11, 0x45, 0x54, 0x48, 0x2a, 0x38,
-	0xf7, 0x43, 0xf4, 0xe9, 0xde, 0xfa, 0x01, 0xfa, 0xf5, 0xfa, 0xd4, 0xe7, 0x62, 0x97, 0x4b, 0x59,
-	0xa2, 0x65, 0x20, 0xf7, 0x46, 0xce, 0x6f, 0xf6, 0xb7, 0xb3, 0xbf, 0x99, 0x9d, 0xdd, 0x05, 0x34,
-	0x99, 0x05, 0x5e, 0x12, 0x7a, 0x1f, 0x97, 0x77, 0x57, 0x8b, 0x28, 0x4c, 0x42, 0xbc, 0x1b, 0x4c,
-	0x67, 0xca, 0xbf, 0x77, 0xe0, 0xc8, 0x98, 0x07, 0x49, 0xe0, 0xcf, 0x82, 0x7f, 0x32, 0xca, 0xbe,
-	0x2c, 0x59, 0x9c, 0xe0, 0x97, 0x70, 0xe0, 0xdf, 0xb3, 0x79, 0x62, 0x87, 0x51, 0xd2, 0x2a, 0xb6,
-	0x8b, 0x97, 0x7b, 0xf4, 0xd1, 0x80, 0x15, 0x38, 0x8c, 0xc3, 0x65, 0x34, 0x61, 0x7d, 0x7b, 0x10,
-	0x7e, 0x66, 0xad, 0x9d, 0x76, 0xf1, 0xf2, 0x80, 0x6e, 0xd8, 0xb8, 0x4f, 0xe2, 0x47, 0xf7, 0x2c,
-	0x91, 0x3e, 0xbb, 0xa9, 0xcf, 0xba, 0x0d, 0xbf, 0x02, 0x48, 0xc7, 0x88, 0x69, 0x4a, 0x62, 0x9a,
-	0x35, 0x0b, 0xbe, 0x80, 0xca, 0x2c, 0x98, 0x7f, 0x1a, 0x85, 0x53, 0xd6, 0xda, 0x6b, 0x17, 0x2f,
-	0x2b, 0x74, 0xf5, 0x8f, 0x2f, 0xa1, 0xb1, 0x8c, 0xd9, 0xe0, 0xce, 0x1f, 0x84, 0x71, 0x32, 0xf7,
-	0x3f, 0xb3, 0xb8, 0x55, 0x16, 0x2e, 0x79, 0x33, 0x3e, 0x86, 0xbd, 0x45, 0x18, 0x25, 0x71, 0x6b,
-	0xbf, 0xbd, 0x7b, 0x59, 0xa3, 0xe9, 0x0f, 0xfe, 0x1e, 0x6a, 0xd3, 0x20, 0xfe, 0xd4, 0x8b, 0x18,
-	0xa3, 0x7e, 0x12, 0x84, 0xad, 0x4a, 0xbb, 0x78, 0x59, 0xa4, 0x9b, 0x46, 0xc5, 0x86, 0x57, 0x8f,
-	0xe2, 0x68, 0x11, 0xf3, 0x13, 0xa6, 0xcd, 0x96, 0x71, 0xc2, 0xa2, 0x4c, 0xa9, 0x2b, 0xc0, 0xd3,
-	0x87, 0xb9, 0xff, 0x39, 0x98, 0x0c, 0x83, 0xbb, 0xc8, 0x8f, 0x1e, 0x6c, 0x3f, 0xf9, 0x28, 0x24,
-	0x3b, 0xa0, 0x5b, 0x10, 0x05, 0x41, 0x9d, 0xfc, 0xc2, 0x26, 0xcb, 0x24, 0xd3, 0x5a, 0x39, 0x82,
-	0x46, 0x2f, 0x98, 0xaf, 0xcb, 0xaf, 0x34, 0xa0, 0x46, 0xd9, 0x57, 0x16, 0x25, 0x99, 0xe1, 0x14,
-	0x8e, 0x29, 0x8b, 0x13, 0x3f, 0x4a, 0x54, 0x9e, 0x85, 0x38, 0xb3, 0xff, 0x09, 0x70, 0xce, 0xbe,
-	0x98, 0x3d, 0x70, 0x5d, 0x45, 0xb2, 0xb8, 0x06, 0x71, 0xab, 0xd8, 0xde, 0xbd, 0x3c, 0xa0, 0x6b,
-	0x16, 0xe5, 0x04, 0x9a, 0x4e, 0x12, 0x2e, 0x1c, 0x16, 0x7d, 0x0d, 0x26, 0x6c, 0x45, 0xd6, 0x84,
-	0xa3, 0x4d, 0xf3, 0x62, 0xf6, 0xa0, 0xdc, 0x40, 0xcd, 0x59, 0xde, 0xc5, 0x09, 0x5b, 0x38, 0x89,
-	0x9f, 0x2c, 0x63, 0xdc, 0x86, 0x12, 0xff, 0x13, 0x4b, 0xac, 0x77, 0x0e, 0xaf, 0x82, 0xe9, 0xec,
-	0x4a, 0x7a, 0x50, 0x81, 0xe0, 0xd7, 0x50, 0x8e, 0x85, 0xaf, 0x28, 0x8c, 0x7a, 0xa7, 0x9a, 0xfa,
-	0x08, 0x13, 0x95, 0x90, 0xf2, 0x02, 0xce, 0xed, 0x88, 0x2d, 0xfc, 0x88, 0x71, 0x81, 0x37, 0x45,
-	0x55, 0xce, 0xe1, 0x6c, 0x1b, 0xc8, 0xe3, 0xf9, 0x02, 0x7b, 0xda, 0xc7, 0xe5, 0xfc, 0x13, 0x3e,
-	0x85, 0xf2, 0xdd, 0xf2, 0xc3, 0x07, 0x16, 0x89, 0x48, 0x0e, 0xa9, 0xfc, 0xc3, 0xaf, 0xa1, 0x94,
-	0x3c, 0x2c, 0x98, 0x9c, 0xbb, 0x21, 0xe6, 0x16, 0x23, 0xae, 0xdc, 0x87, 0x05, 0xa3, 0x02, 0x54,
-	0x7e, 0x80, 0x12, 0xff, 0xc3, 0x55, 0xd8, 0x1f, 0x9b, 0xd7, 0xa6, 0xf5, 0xde, 0x44, 0x05, 0x0c,
-	0x50, 0x76, 0x5c, 0xdd, 0x1a, 0xbb, 0xa8, 0x28, 0xbf, 0x09, 0xa5, 0x68, 0x47, 0xf9, 0xb5, 0x08,
-	0xfb, 0x23, 0x16, 0xc7, 0xfe, 0x3d, 0x2f, 0xeb, 0xbd, 0x09, 0x27, 0x13, 0x93, 0x56, 0x3b, 0xf0,
-	0x48, 0x3f, 0x28, 0xd0, 0x14, 0xc2, 0x7f, 0xd8, 0x58, 0x7f, 0xb5, 0x83, 0xd7, 0x35, 0x4a, 0x65,
-	0x18, 0x14, 0x32, 0x21, 0xf0, 0x0f, 0x50, 0x89, 0x58, 0xbc, 0x08, 0xe7, 0x71, 0xba, 0x49, 0xaa,
-	0x9d, 0x9a, 0xf0, 0xa7, 0xd2, 0x38, 0x28, 0xd0, 0x95, 0x43, 0x17, 0xa0, 0x32, 0x09, 0xe7, 0x09,
-	0x4f, 0xb5, 0xf2, 0x9f, 0x1d, 0xa8, 0x64, 0x4e, 0xd8, 0x00, 0x1c, 0xac, 0xed, 0xe2, 0x0d, 0xbe,
-	0x33, 0xc1, 0x67, 0x3c, 0x81, 0x07, 0x05, 0xba, 0x65, 0x10, 0xfe, 0x1b, 0x34, 0x58, 0x56, 0xa1,
-	0x92, 0xa7, 0x24, 0x78, 0x8e, 0x05, 0x0f, 0xd9, 0xc4, 0x06, 0x05, 0x9a, 0x77, 0xc7, 0x1a, 0xa0,
-	0x0f, 0xab, 0x8a, 0x96, 0x14, 0x7b, 0x82, 0xe2, 0x44, 0x50, 0xf4, 0x72, 0xe0, 0xa0, 0x40, 0x9f,
-	0x0c, 0xc0, 0x3f, 0x43, 0x3d, 0x92, 0x7b, 0x40, 0x52, 0x94, 0x05, 0x45, 0x53, 0xaa, 0xb3, 0x0e,
-	0x0d, 0x0a, 0x34, 0xe7, 0xbc, 0xa1, 0x94, 0x0b, 0xf8, 0xe9, 0xea, 0xf9, 0x2e, 0x19, 0xf8, 0xf1,
-	0x28, 0x88, 0xa2, 0x30, 0x8a, 0x45, 0x3e, 0x2b, 0x74, 0xcd, 0x22, 0x71, 0x27, 0xf1, 0xe7, 0xd3,
-	0xbb, 0x07, 0x91, 0xca, 0x14, 0x97, 0x16, 0xe5, 0x0b, 0xec, 0xcb, 0xca, 0xe4, 0xb5, 0x28, 0xdb,
-	0x5c, 0xba, 0xf1, 0xe5, 0x1f, 0xc6, 0x50, 0x12, 0xad, 0x6d, 0x47, 0xb4, 0x36, 0xf1, 0x8d, 0xff,
-	0x0a, 0x2d, 0x2d, 0x0c, 0xa3, 0x69, 0x30, 0xf7, 0x93, 0x30, 0xd2, 0xfd, 0xc4, 0xd7, 0x83, 0x88,
-	0x4d, 0x92, 0x30, 0x7a, 0x90, 0x4d, 0xf2, 0x59, 0x5c, 0xf9, 0x09, 0x1a, 0x39, 0xf9, 0xf1, 0xf7,
-	0x50, 0x4e, 0x7b, 0xaa, 0xac, 0xc8, 0x74, 0x43, 0x66, 0x5b, 0x46, 0x62, 0xca, 0xaf, 0x3b, 0x80,
-	0xf2, 0xaa, 0xe3, 0x0e, 0xd4, 0x5c, 0x01, 0x4b, 0xef, 0xad, 0x0c, 0x9b, 0x2e, 0xbc, 0x6d, 0xa6,
-	0x86, 0x1b, 0x16, 0xc5, 0x41, 0x38, 0x97, 0xbd, 0x7f, 0xd3, 0x88, 0xdf, 0x41, 0x73, 0x18, 0xde,
-	0xab, 0xd1, 0xe4, 0x63, 0xf0, 0x95, 0xe5, 0x97, 0xb7, 0x0d, 0xc2, 0x37, 0xf0, 0x46, 0xda, 0xa6,
-	0x8e, 0x38, 0x00, 0x9e, 0xd5, 0xa8, 0x24, 0x48, 0xbe, 0xd1, 0x9b, 0x1f, 0x64, 0xe3, 0xc5, 0x7d,
-	0xe4, 0x4f, 0x99, 0xa1, 0x8b, 0x1a, 0x3c, 0xa0, 0x8f, 0x06, 0xe5, 0x5f, 0x45, 0xa8, 0x6f, 0x56,
-	0x12, 0xd7, 0x33, 0x3d, 0x81, 0xb6, 0xeb, 0x99, 0x62, 0x5c, 0x86, 0x74, 0xe2, 0x9c, 0x0c, 0x1b,
-	0xc6, 0xdf, 0x2e, 0x83, 0xf2, 0x06, 0x50, 0x9f, 0x25, 0x5a, 0x38, 0xff, 0x10, 0xdc, 0x67, 0x27,
-	0x0c, 0x86, 0x12, 0x3f, 0xc8, 0x64, 0x69, 0x89, 0x6f, 0xe5, 0x0d, 0xd4, 0xd7, 0xfc, 0x78, 0xcf,
-	0x3f, 0x86, 0xbd, 0xaf, 0xfe, 0x6c, 0x99, 0xb9, 0xa5, 0x3f, 0xca, 0x1f, 0xa1, 0x6a, 0xb2, 0x5f,
-	0x12, 0x75, 0x92, 0x04, 0xe1, 0x9c, 0xf7, 0xee, 0xea, 0xfc, 0xf1, 0x57, 0xba, 0xae, 0x9b, 0xde,
-	0xbe, 0x07, 0x2c, 0xd7, 0xaa, 0xb3, 0x38, 0xe1, 0x8a, 0xf2, 0x85, 0x9c, 0x41, 0x53, 0xb6, 0x49,
-	0x4f, 0x27, 0x8e, 0x6b, 0x98, 0xaa, 0x6b, 0x58, 0x59, 0xcb, 0xb4, 0xc6, 0x54, 0x23, 0xa8, 0x88,
-	0x11, 0x1c, 0x1a, 0xa6, 0x4b, 0xe8, 0x88, 0xe8, 0x86, 0xea, 0x12, 0xb4, 0xc3, 0x51, 0x57, 0xa5,
-	0x7d, 0xe2, 0xa2, 0xdd, 0xb7, 0x16, 0x94, 0x1c, 0x7e, 0x38, 0x20, 0x38, 0xcc, 0xa8, 0x1c, 0x97,
-	0xd8, 0xa8, 0x80, 0xeb, 0x00, 0x86, 0x69, 0xb8, 0x86, 0x3a, 0x34, 0xfe, 0xc1, 0x79, 0xaa, 0xb0,
-	0x4f, 0xfe, 0x4e, 0xb4, 0xb1, 0xa0, 0x38, 0x84, 0x4a, 0xcf, 0x30, 0x53, 0x68, 0x97, 0x13, 0x52,
-	0x72, 0x43, 0xa8, 0x8b, 0x4a, 0x6f, 0xff, 0xb7, 0x0f, 0xfb, 0xb2, 0xa7, 0xe2, 0x26, 0x34, 0x56,
-	0xa4, 0xe3, 0xae, 0xe4, 0x6d, 0xc3, 0x4b, 0x47, 0xbd, 0x31, 0xcc, 0xbe, 0x97, 0x86, 0xe8, 0x69,
-	0xc3, 0xb1, 0xe3, 0x12, 0xea, 0x69, 0x96, 0xd9, 0x33, 0xfa, 0xa8, 0x88, 0x6b, 0x70, 0xe0, 0xb8,
-	0x2a, 0x75, 0xbd, 0xc1, 0xb8, 0x8b, 0x76, 0x78, 0x68, 0xe9, 0xaf, 0xda, 0x27, 0xa6, 0xeb, 0xa0,
-	0x5d, 0x7c, 0x0c, 0x48, 0x1b, 0x10, 0xed, 0xda, 0xd3, 0x0d, 0xe7, 0xda, 0x73, 0x6c, 0x55, 0x23,
-	0xa8, 0x84, 0x2f, 0xe0, 0xb4, 0x4f, 0x4c, 0x42, 0x55, 0x97, 0x78, 0xe9, 0xfa, 0x32, 0xca, 0x3d,
-	0xae, 0x14, 0x5f, 0xcc, 0xca, 0x9e, 0x4e, 0x89, 0xca, 0xf8, 0x05, 0x9c, 0x39, 0x83, 0xb1, 0xab,
-	0xf3, 0x18, 0x73, 0xe0, 0x3e, 0x6e, 0xc1, 0x71, 0x57, 0xd5, 0xae, 0xc7, 0x76, 0x06, 0x8d, 0x54,
-	0x81, 0x54, 0xf0, 0x11, 0xd4, 0xd2, 0x08, 0xc6, 0x76, 0x9f, 0xaa, 0x3a, 0x41, 0x07, 0x1b, 0x4c,
-	0x9b, 0x2b, 0x43, 0x80, 0x31, 0xd4, 0xa5, 0x67, 0xc6, 0x51, 0xc5, 0x0d, 0xa8, 0x6a, 0x96, 0x7d,
-	0x9b, 0x19, 0x0e, 0xf1, 0x09, 0x1c, 0x65, 0x4e, 0x36, 0x35, 0x46, 0x2a, 0x35, 0x88, 0x83, 0x6a,
-	0x3c, 0x8a, 0x74, 0xfd, 0xb9, 0xf8, 0xea, 0xf8, 0x1c, 0x4e, 0xc6, 0xb6, 0xbe, 0xbe, 0x5e, 0xd5,
-	0x55, 0x87, 0x56, 0x1f, 0x35, 0x78, 0x34, 0x12, 0xd2, 0x55, 0x57, 0xf5, 0x74, 0x83, 0x12, 0xcd,
-	0xb5, 0x04, 0x23, 0xc2, 0x2f, 0xa1, 0x95, 0x1b, 0x67, 0x99, 0x3d, 0xaf, 0x67, 0x0c, 0x89, 0x83,
-	0x8e, 0x44, 0xd6, 0x64, 0x18, 0x8e, 0xab, 0x9a, 0x7a, 0xf7, 0x16, 0xe1, 0x75, 0xe3, 0xc8, 0xa0,
-	0xd4, 0xa2, 0x0e, 0x6a, 0xe2, 0x53, 0xc0, 0x3a, 0x19, 0x12, 0xc1, 0xd3, 0x1d, 0x12, 0x91, 0x08,
-	0x07, 0x1d, 0x63, 0x05, 0x5e, 0xad, 0xec, 0xeb, 0x21, 0x8b, 0x58, 0x74, 0x83, 0x3a, 0xe8, 0x84,
-	0xc7, 0x20, 0x7d, 0x1c, 0xd2, 0x1f, 0x11, 0xd3, 0xe5, 0x93, 0xb9, 0x44, 0xa0, 0xa7, 0x3c, 0x5f,
-	0x8e, 0x6b, 0xd9, 0xbc, 0x02, 0x3c, 0xd5, 0xd4, 0xb3, 0xd4, 0x9f, 0xf1, 0x24, 0xcb, 0x61, 0xa9,
-	0x6c, 0xab, 0x51, 0xa8, 0xc5, 0xd7, 0xac, 0x52, 0x6d, 0x60, 0xdc, 0x10, 0x6f, 0x68, 0xf5, 0x37,
-	0xd6, 0x7c, 0xce, 0x07, 0x52, 0xe2, 0xb8, 0x16, 0x25, 0xf9, 0xec, 0x5c, 0x3c, 0x2a, 0x9c, 0x43,
-	0x5e, 0xf0, 0x94, 0x64, 0xa3, 0xec, 0xbe, 0x66, 0x99, 0x2e, 0xb5, 0x86, 0xe8, 0x25, 0xfe, 0x0e,
-	0xce, 0x29, 0xd1, 0xac, 0x1b, 0x42, 0x1d, 0x92, 0xaf, 0x63, 0xf4, 0x1d, 0xcf, 0x2c, 0x2f, 0x76,
-	0x11, 0xdb, 0xd8, 0x41, 0xaf, 0x78, 0xa2, 0x28, 0x19, 0x59, 0x37, 0xab, 0xb9, 0x33, 0x0d, 0x7f,
-	0x87, 0x55, 0xf8, 0xf9, 0xbd, 0x6a, 0xb8, 0x5e, 0xcf, 0xa2, 0x2b, 0x99, 0x5c, 0xcb, 0xeb, 0x12,
-	0x8f, 0x12, 0x55, 0xbf, 0xf5, 0xd4, 0x1e, 0xb7, 0xa8, 0xba, 0xce, 0x77, 0x8c, 0x1c, 0x26, 0x24,
-	0xc9, 0x72, 0xd3, 0xc6, 0x3f, 0xc1, 0x8f, 0xdf, 0x40, 0x21, 0x32, 0xce, 0x49, 0xb2, 0x22, 0xf9,
-	0xfd, 0x4a, 0xe5, 0x5c, 0x61, 0x29, 0xb8, 0x03, 0x57, 0x0e, 0x71, 0x85, 0xb7, 0x7e, 0x6b, 0xaa,
-	0x23, 0x43, 0xf3, 0x86, 0x46, 0x97, 0xaa, 0xf4, 0xd6, 0xb3, 0x55, 0x77, 0xe0, 0x59, 0x4f, 0x36,
-	0xcb, 0xeb, 0xb7, 0x36, 0x94, 0xe5, 0x55, 0x94, 0x17, 0xfb, 0xaa, 0x97, 0x08, 0x05, 0x0a, 0xbc,
-	0x7b, 0xd0, 0xb1, 0x69, 0x1a, 0x26, 0xdf, 0xe0, 0x87, 0x50, 0xd1, 0xac, 0x91, 0xcd, 0xd3, 0x98,
-	0xb6, 0xa3, 0x9e, 0x6a, 0x0c, 0x89, 0x8e, 0x76, 0xb9, 0x9b, 0x73, 0x6d, 0xd8, 0x36, 0xd1, 0x51,
-	0xa9, 0xf3, 0xdf, 0x5d, 0xa8, 0x68, 0xb3, 0xc0, 0x0d, 0x07, 0xcb, 0x3b, 0xfc, 0x67, 0x80, 0xc7,
-	0xcb, 0x02, 0x3e, 0x7d, 0x72, 0x77, 0x12, 0x4d, 0xf9, 0x22, 0x3d, 0x16, 0xe4, 0xad, 0x50, 0x29,
-	0xbc, 0x2b, 0x62, 0x1b, 0xce, 0x9e, 0x79, 0x2a, 0xe0, 0xd7, 0x39, 0x92, 0x6d, 0x0f, 0x89, 0x2d,
-	0x8c, 0xef, 0x60, 0x5f, 0x9e, 0xf6, 0xb8, 0xb9, 0x79, 0xf5, 0x7a, 0x6e, 0x44, 0x07, 0x2a, 0xd9,
-	0x29, 0x8f, 0x8f, 0x73, 0x57, 0xad, 0xe7, 0xc6, 0x5c, 0x41, 0x39, 0x3d, 0x02, 0x31, 0xde, 0xb8,
-	0x59, 0x3d, 0xe7, 0xff, 0x17, 0x38, 0x58, 0x1d, 0x3d, 0x38, 0xbd, 0xcf, 0xe5, 0x8f, 0xac, 0x8b,
-	0x66, 0xde, 0xcc, 0x6f, 0xee, 0x05, 0x4c, 0xf8, 0xb3, 0x66, 0xed, 0xb5, 0x82, 0xcf, 0xb3, 0x9b,
-	0xee, 0x93, 0x97, 0xcd, 0xc5, 0xd9, 0x36, 0x28, 0xa5, 0xe9, 0xc2, 0xe1, 0xfa, 0x3b, 0x05, 0xb7,
-	0xe4, 0xfb, 0xe2, 0xc9, 0x8b, 0xe6, 0xe2, 0x74, 0x0b, 0x22, 0x38, 0xee, 0xca, 0xe2, 0x09, 0xfc,
-	0xe3, 0xff, 0x03, 0x00, 0x00, 0xff, 0xff, 0x88, 0xec, 0xc5, 0xed, 0x16, 0x0f, 0x00, 0x00,
+	// 2497 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x58, 0xdd, 0x6e, 0xe3, 0xc6,
+	0xf5, 0xb7, 0x6c, 0x59, 0x96, 0x8f, 0x2c, 0x8b, 0x1e, 0x7b, 0x6d, 0xda, 0xbb, 0xd9, 0xbf, 0xff,
+	0xdc, 0x74, 0xeb, 0x38, 0x89, 0xb3, 0x70, 0xda, 0x04, 0x2d, 0x1a, 0x20, 0xb4, 0x48, 0x49, 0xc4,
+	0x4a, 0x24, 0x31, 0xa4, 0x9c, 0x6e, 0x81, 0x82, 0xa0, 0xa5, 0x59, 0x9b, 0x58, 0x4b, 0x64, 0x48,
+	0xca, 0x89, 0xfb, 0x0c, 0xbd, 0x2c, 0xfa, 0x0a, 0x7d, 0x80, 0x5e, 0xf5, 0x61, 0x7a, 0x59, 0xf4,
+	0x35, 0x8a, 0xf9, 0xa0, 0x44, 0x52, 0x72, 0x13, 0xf4, 0x8e, 0xf3, 0x3b, 0x67, 0xce, 0x9c, 0xef,
+	0x39, 0x1c, 0x90, 0x46, 0xf7, 0x81, 0x97, 0x86, 0xde, 0xdd, 0xec, 0xe6, 0x22, 0x8a, 0xc3, 0x34,
+	0x44, 0x1b, 0xc1, 0xf8, 0xfe, 0x04, 0xdd, 0xcd, 0x6e, 0x28, 0xec, 0xdf, 0x92, 0x69, 0xca, 0x09,
+	0xca, 0x9f, 0x6b, 0xb0, 0x67, 0x4c, 0x83, 0x34, 0xf0, 0xef, 0x83, 0x3f, 0x11, 0x4c, 0xbe, 0x9f,
+	0x91, 0x24, 0x45, 0x2f, 0x60, 0x9b, 0x31, 0xd9, 0x61, 0x9c, 0xca, 0x95, 0xd3, 0xca, 0xd9, 0x26,
+	0x5e, 0x00, 0x48, 0x81, 0x9d, 0x24, 0x9c, 0xc5, 0x23, 0xd2, 0xb5, 0x7b, 0xe1, 0x84, 0xc8, 0xeb,
+	0xa7, 0x95, 0xb3, 0x6d, 0x5c, 0xc0, 0x28, 0x4f, 0xea, 0xc7, 0xb7, 0x24, 0x15, 0x3c, 0x1b, 0x9c,
+	0x27, 0x8f, 0xa1, 0x97, 0x00, 0x7c, 0x0f, 0x3b, 0xa6, 0xca, 0x8e, 0xc9, 0x21, 0xe8, 0x0c, 0x5a,
+	0xb3, 0x84, 0xf4, 0x6e, 0xfc, 0x5e, 0x98, 0xa4, 0x53, 0x7f, 0x42, 0x12, 0xb9, 0x76, 0x5a, 0x39,
+	0xab, 0xe3, 0x32, 0x8c, 0x0e, 0x60, 0x33, 0x0a, 0xe3, 0x34, 0x91, 0xb7, 0x4e, 0x37, 0xce, 0x9a,
+	0x98, 0x2f, 0xd0, 0xc7, 0xd0, 0x1c, 0x07, 0xc9, 0x87, 0x4e, 0x4c, 0x08, 0xf6, 0xd3, 0x20, 0x94,
+	0xeb, 0xa7, 0x95, 0xb3, 0x0a, 0x2e, 0x82, 0xe8, 0x33, 0xd8, 0x23, 0x49, 0x1a, 0x4c, 0xfc, 0x94,
+	0x68, 0x41, 0xf2, 0xc1, 0x89, 0xfc, 0x11, 0x91, 0xb7, 0xd9, 0x39, 0xcb, 0x04, 0xf4, 0x1a, 0x76,
+	0xc7, 0x7e, 0xea, 0x5f, 0xfb, 0xf7, 0xc1, 0x98, 0x6e, 0x9f, 0xca, 0xc0, 0x2c, 0x2b, 0xa1, 0xe8,
+	0x1c, 0xa4, 0x64, 0xea, 0x47, 0xc9, 0x5d, 0x98, 0xda, 0x71, 0xf8, 0x10, 0x8c, 0x49, 0x2c, 0x37,
+	0x18, 0xe7, 0x12, 0x8e, 0x3e, 0x82, 0xea, 0x24, 0x1c, 0x13, 0x79, 0xe7, 0xb4, 0x72, 0xb6, 0x7b,
+	0xb9, 0x7d, 0x11, 0x8c, 0xef, 0x2f, 0x06, 0xe1, 0x98, 0x60, 0x06, 0x53, 0x57, 0x4e, 0x82, 0x38,
+	0x0e, 0x63, 0x4c, 0x92, 0xc7, 0xe9, 0x48, 0x6e, 0x72, 0x57, 0xe6, 0x31, 0xaa, 0xd6, 0x8d, 0x3f,
+	0xfa, 0x30, 0x8b, 0xe6, 0x87, 0xed, 0x72, 0xb5, 0x8a, 0x28, 0x75, 0x09, 0x47, 0xda, 0xe1, 0x64,
+	0xe2, 0x4f, 0xc7, 0x72, 0x8b, 0xb1, 0x15, 0x41, 0xea, 0x78, 0x0e, 0xb8, 0xc1, 0x84, 0x24, 0xa9,
+	0x3f, 0x89, 0x64, 0x89, 0xf1, 0x95, 0x61, 0xf4, 0x06, 0xf6, 0x79, 0x48, 0x35, 0x3f, 0xf5, 0xc7,
+	0x41, 0xdc, 0xf7, 0x1f, 0xc3, 0x59, 0x2a, 0xef, 0x31, 0xee, 0x55, 0x24, 0xea, 0x6e, 0x0e, 0xd3,
+	0xe8, 0x0d, 0xfc, 0x28, 0x0a, 0xa6, 0xb7, 0x32, 0x62, 0xfc, 0xcb, 0x04, 0x6a, 0x3b, 0x8d, 0xa5,
+	0x93, 0xc6, 0x7e, 0x4a, 0x6e, 0x1f, 0xe5, 0x7d, 0x6e, 0x7b, 0x1e, 0xa3, 0x36, 0xf1, 0x8d, 0x34,
+	0x69, 0x06, 0x7e, 0x24, 0x1f, 0x70, 0x9b, 0x0a, 0x20, 0xe5, 0xfa, 0x40, 0x48, 0xe4, 0x92, 0x49,
+	0x64, 0xb3, 0x54, 0x79, 0xc6, 0x42, 0x5c, 0x04, 0x15, 0x1b, 0x5e, 0x2e, 0xaa, 0xa1, 0x1d, 0x13,
+	0x3f, 0x25, 0xed, 0xfb, 0x59, 0x92, 0x92, 0x38, 0x2b, 0x8d, 0x0b, 0x40, 0xe3, 0xc7, 0xa9, 0x3f,
+	0x09, 0x46, 0xfd, 0xe0, 0x26, 0xf6, 0xe3, 0x47, 0xdb, 0x4f, 0xef, 0x58, 0x8d, 0x6c, 0xe3, 0x15,
+	0x14, 0xe5, 0x01, 0x76, 0xf5, 0x1f, 0xc9, 0x68, 0x96, 0xce, 0x8b, 0x4b, 0x81, 0x9d, 0x70, 0x7a,
+	0xff, 0xd8, 0x0e, 0xa7, 0x29, 0x99, 0xa6, 0x89, 0x5c, 0x39, 0xdd, 0x38, 0xdb, 0xc4, 0x05, 0x0c,
+	0x7d, 0x0b, 0xcf, 0xa3, 0x30, 0x98, 0xa6, 0xd6, 0x7b, 0x33, 0xc4, 0x24, 0x9d, 0xc5, 0xd3, 0x76,
+	0x38, 0x7d, 0x1f, 0xc4, 0x13, 0x9e, 0x73, 0xbc, 0xe2, 0xfe, 0x1b, 0x8b, 0xe2, 0x40, 0xab, 0x13,
+	0x4c, 0x0b, 0x55, 0xfd, 0x13, 0x42, 0x2b, 0x3f, 0x2d, 0xb4, 0x05, 0x4d, 0x4c, 0x1e, 0x48, 0x9c,
+	0x0a, 0x91, 0xca, 0x21, 0x1c, 0x60, 0x9a, 0x0a, 0x71, 0xaa, 0xd2, 0xf6, 0x90, 0x64, 0xf8, 0xaf,
+	0x00, 0x95, 0xf0, 0xe8, 0xfe, 0x91, 0x16, 0x3c, 0xeb, 0x22, 0x34, 0xc2, 0xdc, 0xee, 0x6d, 0x9c,
+	0x43, 0x94, 0x67, 0xb0, 0xef, 0xa4, 0x61, 0xe4, 0x90, 0xf8, 0x21, 0x18, 0x91, 0xb9, 0xb0, 0x7d,
+	0xd8, 0x2b, 0xc2, 0xd1, 0xfd, 0xa3, 0xb2, 0x07, 0x2d, 0x51, 0x6e, 0x99, 0x7d, 0xca, 0x2d, 0x34,
+	0x17, 0x10, 0x3d, 0xef, 0x10, 0x6a, 0x31, 0x89, 0xb2, 0x1e, 0xb6, 0x8d, 0xc5, 0x8a, 0xea, 0x31,
+	0x09, 0x92, 0x89, 0x9f, 0x8e, 0xee, 0x48, 0xc2, 0x9c, 0xb9, 0x89, 0x73, 0x08, 0xa5, 0x73, 0x4e,
+	0x16, 0x5b, 0xde, 0xba, 0x72, 0x88, 0xf2, 0xd7, 0x0a, 0x1c, 0x38, 0xb3, 0x88, 0xae, 0xaf, 0x66,
+	0xd3, 0xf1, 0xfd, 0xdc, 0xc3, 0x12, 0x6c, 0x8c, 0x83, 0x58, 0x9c, 0x46, 0x3f, 0x69, 0x29, 0xc5,
+	0x64, 0xec, 0x8f, 0x52, 0xdb, 0x4f, 0x92, 0x1f, 0xc2, 0x78, 0xcc, 0xcf, 0xab, 0xe3, 0x32, 0xbc,
+	0xe0, 0x5c, 0x74, 0xbb, 0x8d, 0x3c, 0xe7, 0x1c, 0x46, 0x32, 0x6c, 0x3d, 0x90, 0x38, 0xa1, 0x31,
+	0xab, 0xb2, 0x93, 0xb2, 0xa5, 0xf2, 0x2d, 0xa0, 0x92, 0x5e, 0xd4, 0x0d, 0x08, 0xaa, 0xd1, 0x22,
+	0x49, 0xd9, 0x37, 0x75, 0x0d, 0xa1, 0xfd, 0x83, 0xaa, 0x43, 0xc3, 0x20, 0x56, 0xd4, 0xad, 0x0e,
+	0xb9, 0x9d, 0xe4, 0x63, 0xd9, 0x81, 0xe6, 0x02, 0xa2, 0xf2, 0x7e, 0x0d, 0xf5, 0x44, 0x00, 0x2c,
+	0x88, 0x8d, 0xcb, 0x63, 0xd6, 0xb3, 0x04, 0xd7, 0x30, 0xba, 0x8d, 0xfd, 0x31, 0x71, 0x52, 0x3f,
+	0x9d, 0x25, 0x78, 0xce, 0xaa, 0xfc, 0x93, 0x7a, 0x6d, 0x05, 0x0b, 0xbd, 0x6d, 0x46, 0x3c, 0xf1,
+	0x0d, 0x2d, 0xbb, 0x6d, 0xe6, 0x00, 0xd5, 0x7e, 0x7c, 0x63, 0x68, 0x22, 0x4c, 0xec, 0x1b, 0x9d,
+	0x40, 0xfd, 0x4e, 0xb8, 0x43, 0x84, 0x67, 0xbe, 0xa6, 0xde, 0xa1, 0xbd, 0x58, 0x0b, 0xe2, 0xcc,
+	0x3b, 0x62, 0x89, 0x5e, 0x41, 0x2d, 0x61, 0x27, 0xca, 0x9b, 0xac, 0xd3, 0x36, 0xb8, 0xd6, 0x5c,
+	0x4f, 0x41, 0x62, 0x1d, 0xe7, 0x56, 0xe8, 0x47, 0x65, 0xd4, 0x44, 0xc7, 0xc9, 0x61, 0xf4, 0xba,
+	0x61, 0xee, 0x92, 0xb7, 0x18, 0x91, 0x2f, 0x94, 0x6b, 0x68, 0x3a, 0xb3, 0x9b, 0x24, 0x25, 0x91,
+	0xb0, 0xeb, 0x14, 0xaa, 0x74, 0xc5, 0x4c, 0xda, 0xbd, 0xdc, 0xe1, 0xa7, 0x71, 0x0e, 0xcc, 0x28,
+	0x39, 0x8d, 0xd6, 0x9f, 0xd4, 0x48, 0x79, 0x0e, 0xc7, 0x76, 0x4c, 0x22, 0x3f, 0x26, 0xb4, 0x35,
+	0x15, 0xdb, 0x91, 0x72, 0x0c, 0x47, 0xab, 0x88, 0xb4, 0x42, 0xbe, 0x87, 0xcd, 0xf6, 0xdd, 0x6c,
+	0xfa, 0x81, 0xc6, 0xfa, 0x66, 0xf6, 0xfe, 0x3d, 0xe1, 0x89, 0xb9, 0x83, 0xc5, 0x0a, 0xbd, 0x82,
+	0x6a, 0xfa, 0x18, 0x11, 0x71, 0x76, 0x8b, 0x9d, 0xcd, 0x76, 0x5c, 0xb8, 0x8f, 0x11, 0xc1, 0x8c,
+	0xa8, 0x7c, 0x0a, 0x55, 0xba, 0x42, 0x0d, 0xd8, 0x1a, 0x9a, 0x6f, 0x4d, 0xeb, 0x3b, 0x53, 0x5a,
+	0x43, 0x00, 0x35, 0xc7, 0xd5, 0xac, 0xa1, 0x2b, 0x55, 0xc4, 0xb7, 0x8e, 0xb1, 0xb4, 0xae, 0xfc,
+	0xa5, 0x02, 0x5b, 0x03, 0x92, 0x24, 0xfe, 0x2d, 0xbd, 0xb6, 0x36, 0x47, 0x54, 0x18, 0x3b, 0xb4,
+	0x71, 0x09, 0x0b, 0xf1, 0xbd, 0x35, 0xcc, 0x49, 0xe8, 0xb3, 0x82, 0xfd, 0x8d, 0x4b, 0x94, 0xf7,
+	0x11, 0x77, 0x43, 0x6f, 0x6d, 0x1e, 0x9a, 0x4f, 0xa1, 0x1e, 0x93, 0x24, 0x0a, 0xa7, 0x09, 0x8f,
+	0x7a, 0xe3, 0xb2, 0xc9, 0xf8, 0xb1, 0x00, 0x7b, 0x6b, 0x78, 0xce, 0x70, 0x05, 0x50, 0x17, 0x39,
+	0x94, 0x28, 0x7f, 0x5b, 0x87, 0x7a, 0xc6, 0x84, 0x0c, 0x40, 0x41, 0x6e, 0xe0, 0x29, 0xc8, 0x3b,
+	0x62, 0xf2, 0x8c, 0x25, 0x72, 0x6f, 0x0d, 0xaf, 0xd8, 0x84, 0xbe, 0x85, 0x16, 0xc9, 0x7a, 0xbb,
+	0x90, 0x53, 0x65, 0x72, 0x0e, 0x98, 0x1c, 0xbd, 0x48, 0xeb, 0xad, 0xe1, 0x32, 0x3b, 0x6a, 0x83,
+	0xf4, 0x7e, 0xde, 0xa5, 0x85, 0x88, 0x4d, 0x26, 0xe2, 0x19, 0x13, 0xd1, 0x29, 0x11, 0x7b, 0x6b,
+	0x78, 0x69, 0x03, 0xfa, 0x06, 0x76, 0x63, 0xd1, 0x95, 0x85, 0x88, 0x1a, 0x13, 0xb1, 0x2f, 0xbc,
+	0x93, 0x27, 0xf5, 0xd6, 0x70, 0x89, 0xb9, 0xe0, 0x29, 0x17, 0xd0, 0xb2, 0xf5, 0xb4, 0x1f, 0xf6,
+	0xfc, 0x64, 0x10, 0xf0, 0x86, 0x51, 0x61, 0x5d, 0x29, 0x87, 0x08, 0xba, 0x93, 0xfa, 0xd3, 0xf1,
+	0xcd, 0xa3, 0xe8, 0x6f, 0x39, 0x44, 0xf9, 0x1e, 0xb6, 0x44, 0x66, 0xd2, 0x5c, 0x14, 0x13, 0xa1,
+	0x68, 0xc9, 0x7c, 0x45, 0xab, 0x9c, 0x4d, 0x81, 0xa2, 0xca, 0xe9, 0x37, 0xfa, 0x2d, 0xc8, 0xed,
+	0x30, 0x8c, 0xc7, 0xc1, 0xd4, 0x4f, 0xc3, 0x58, 0xe3, 0x55, 0x4c, 0x46, 0x69, 0x18, 0x3f, 0x8a,
+	0xaa, 0x7f, 0x92, 0xae, 0x7c, 0x0d, 0xad, 0x92, 0xfb, 0xd1, 0xc7, 0x50, 0xe3, 0x23, 0x81, 0xc8,
+	0x48, 0x5e, 0x90, 0x59, 0xc9, 0x08, 0x9a, 0xf2, 0x8f, 0x75, 0x90, 0xca, 0x5e, 0x47, 0x97, 0xd0,
+	0x74, 0x19, 0x59, 0x70, 0xaf, 0x94, 0x50, 0x64, 0xa1, 0x03, 0x07, 0x07, 0xae, 0x45, 0xaf, 0xe6,
+	0x97, 0x76, 0x11, 0xa4, 0x03, 0x54, 0x3f, 0xbc, 0x55, 0xe3, 0xd1, 0x5d, 0xf0, 0x40, 0xca, 0xe6,
+	0xad, 0x22, 0xa1, 0x6b, 0x78, 0x2d, 0xb0, 0xb1, 0xc3, 0x66, 0xe5, 0x27, 0x7d, 0xc4, 0xdb, 0xdf,
+	0xcf, 0xe4, 0xa6, 0x5d, 0x58, 0xb4, 0x38, 0x43, 0x63, 0x39, 0xb8, 0x8d, 0x17, 0x00, 0xed, 0x54,
+	0x7c, 0xf6, 0x13, 0xb9, 0xc5, 0x3b, 0xd5, 0x15, 0x83, 0xb0, 0x20, 0x29, 0x7f, 0xaf, 0xc0, 0x6e,
+	0x31, 0xdd, 0xa8, 0xd3, 0xf9, 0x44, 0xbf, 0xda, 0xe9, 0x9c, 0x46, 0x7d, 0xc5, 0xb5, 0x2b, 0xf9,
+	0xaa, 0x00, 0xfe, 0x0f, 0xbe, 0x5a, 0x68, 0x5d, 0x7d, 0x5a, 0x6b, 0x0d, 0x6a, 0x1c, 0x41, 0xa7,
+	0xd0, 0x18, 0x93, 0x64, 0x14, 0x07, 0x51, 0x6e, 0x20, 0xca, 0x43, 0xf4, 0x72, 0x89, 0x49, 0x92,
+	0x86, 0x71, 0xf6, 0xd7, 0x93, 0x2d, 0x95, 0xd7, 0x20, 0x75, 0x49, 0xca, 0xa6, 0xa5, 0xdb, 0x6c,
+	0x1c, 0x40, 0x50, 0x65, 0x57, 0x94, 0xb8, 0x78, 0xe9, 0xb7, 0xf2, 0x1a, 0x76, 0x73, 0x7c, 0xf4,
+	0x3a, 0x3d, 0x80, 0xcd, 0x07, 0xff, 0x7e, 0x96, 0xb1, 0xf1, 0x05, 0x1b, 0x7a, 0xee, 0xc2, 0x1f,
+	0x0a, 0x02, 0x95, 0x4f, 0xa0, 0x95, 0x07, 0xc5, 0x8c, 0x33, 0x62, 0xcb, 0xac, 0xb9, 0xf3, 0x95,
+	0xf2, 0x05, 0x34, 0x4c, 0xf2, 0x63, 0xaa, 0x8e, 0xa8, 0xde, 0xf4, 0x2e, 0x6a, 0x4c, 0x17, 0xcb,
+	0xcc, 0xb4, 0x1c, 0xa4, 0xfc, 0x11, 0x5a, 0x76, 0x71, 0xf4, 0x43, 0xaf, 0x61, 0x2b, 0xe1, 0xbd,
+	0x78, 0xe5, 0x1d, 0x96, 0x11, 0xe9, 0x9d, 0x39, 0x5a, 0x1e, 0x4f, 0x0b, 0xd8, 0xf9, 0x77, 0x80,
+	0x44, 0xd4, 0x35, 0xfa, 0x57, 0x35, 0x65, 0x28, 0x3a, 0x82, 0x7d, 0x71, 0xab, 0x78, 0x9a, 0xee,
+	0xb8, 0x86, 0xa9, 0xba, 0x86, 0x95, 0xdd, 0x30, 0xd6, 0x10, 0xb7, 0x75, 0xa9, 0x82, 0x24, 0xd8,
+	0x31, 0x4c, 0x57, 0xc7, 0x03, 0x5d, 0x33, 0x54, 0x57, 0x97, 0xd6, 0x29, 0xd5, 0x55, 0x71, 0x57,
+	0x77, 0xa5, 0x8d, 0x73, 0x0b, 0xaa, 0x0e, 0x55, 0x42, 0x82, 0x9d, 0x4c, 0x94, 0xe3, 0xea, 0xb6,
+	0xb4, 0x86, 0x76, 0x01, 0x0c, 0xd3, 0x70, 0x0d, 0xb5, 0x6f, 0xfc, 0x81, 0xca, 0x69, 0xc0, 0x96,
+	0xfe, 0x7b, 0xbd, 0x3d, 0x64, 0x22, 0x76, 0xa0, 0xde, 0x31, 0x4c, 0x4e, 0xda, 0xa0, 0x02, 0xb1,
+	0x7e, 0xad, 0x63, 0x57, 0xaa, 0x9e, 0xff, 0x1b, 0x60, 0x4b, 0x98, 0x88, 0xf6, 0xa1, 0x35, 0x17,
+	0x3a, 0xbc, 0x12, 0x72, 0x4f, 0xe1, 0x85, 0xa3, 0x5e, 0x1b, 0x66, 0xd7, 0xe3, 0x2a, 0x7a, 0xed,
+	0xfe, 0xd0, 0x71, 0x75, 0xec, 0xb5, 0x2d, 0xb3, 0x63, 0x74, 0xa5, 0x0a, 0x6a, 0xc2, 0xb6, 0xe3,
+	0xaa, 0xd8, 0xf5, 0x7a, 0xc3, 0x2b, 0x69, 0x9d, 0xaa, 0xc6, 0x97, 0x6a, 0x57, 0x37, 0x5d, 0x47,
+	0xda, 0x40, 0x07, 0x20, 0xb5, 0x7b, 0x7a, 0xfb, 0xad, 0xa7, 0x19, 0xce, 0x5b, 0xcf, 0xb1, 0xd5,
+	0xb6, 0x2e, 0x55, 0xd1, 0x09, 0x1c, 0x76, 0x75, 0x53, 0xc7, 0xaa, 0xab, 0x7b, 0xdc, 0xbe, 0x4c,
+	0xe4, 0x26, 0xf5, 0x14, 0x35, 0x66, 0x8e, 0xf3, 0x23, 0xa5, 0x1a, 0x7a, 0x0e, 0x47, 0x4e, 0x6f,
+	0xe8, 0x6a, 0x54, 0xc7, 0x12, 0x71, 0x0b, 0xc9, 0x70, 0x70, 0xa5, 0xb6, 0xdf, 0x0e, 0xed, 0x8c,
+	0x34, 0x50, 0x19, 0xa5, 0x8e, 0xf6, 0xa0, 0xc9, 0x35, 0x18, 0xda, 0x5d, 0xac, 0x6a, 0xba, 0xb4,
+	0x5d, 0x90, 0x54, 0xb4, 0x4c, 0x02, 0x84, 0x60, 0x57, 0x70, 0x66, 0x32, 0x1a, 0xa8, 0x05, 0x8d,
+	0xb6, 0x65, 0xbf, 0xcb, 0x80, 0x1d, 0xf4, 0x0c, 0xf6, 0x32, 0x26, 0x1b, 0x1b, 0x03, 0x15, 0x1b,
+	0xba, 0x23, 0x35, 0xa9, 0x16, 0xdc, 0xfe, 0x92, 0x7e, 0xbb, 0xe8, 0x18, 0x9e, 0x0d, 0x6d, 0x2d,
+	0x6f, 0xaf, 0xea, 0xaa, 0x7d, 0xab, 0x2b, 0xb5, 0xa8, 0x36, 0x82, 0xa4, 0xa9, 0xae, 0xea, 0x69,
+	0x06, 0xd6, 0xdb, 0xae, 0xc5, 0x24, 0x4a, 0xe8, 0x05, 0xc8, 0xa5, 0x7d, 0x96, 0xd9, 0xf1, 0x3a,
+	0x46, 0x5f, 0x77, 0xa4, 0x3d, 0x16, 0x35, 0xa1, 0x86, 0xe3, 0xaa, 0xa6, 0x76, 0xf5, 0x4e, 0x42,
+	0x79, 0x70, 0x60, 0x60, 0x6c, 0x61, 0x47, 0xda, 0x47, 0x87, 0x80, 0x34, 0xbd, 0xaf, 0x33, 0x39,
+	0x57, 0x7d, 0x9d, 0x05, 0xc2, 0x91, 0x0e, 0x90, 0x02, 0x2f, 0xe7, 0x78, 0x5e, 0x65, 0xa6, 0x8b,
+	0x66, 0x60, 0x47, 0x7a, 0x46, 0x75, 0x10, 0x3c, 0x8e, 0xde, 0x1d, 0xe8, 0xa6, 0x4b, 0x0f, 0x73,
+	0x75, 0x46, 0x3d, 0xa4, 0xf1, 0x72, 0x5c, 0xcb, 0xa6, 0x19, 0xe0, 0xa9, 0xa6, 0x96, 0x85, 0xfe,
+	0x88, 0x06, 0x59, 0x6c, 0xe3, 0x6e, 0x9b, 0xef, 0x92, 0x64, 0x6a, 0xb3, 0x8a, 0xdb, 0x3d, 0xe3,
+	0x5a, 0xf7, 0xfa, 0x56, 0xb7, 0x60, 0xf3, 0x31, 0xdd, 0x88, 0x75, 0xc7, 0xb5, 0xb0, 0x5e, 0x8e,
+	0xce, 0xc9, 0xc2, 0xc3, 0x25, 0xca, 0x73, 0x1a, 0x92, 0x6c, 0x97, 0xdd, 0x6d, 0x5b, 0xa6, 0x8b,
+	0xad, 0xbe, 0xf4, 0x02, 0x7d, 0x04, 0xc7, 0x58, 0x6f, 0x5b, 0xd7, 0x3a, 0x76, 0xf4, 0x72, 0x1e,
+	0x4b, 0x1f, 0xd1, 0xc8, 0xd2, 0x64, 0x67, 0xba, 0x0d, 0x1d, 0xe9, 0x25, 0x0d, 0x14, 0xd6, 0x07,
+	0xd6, 0xf5, 0xfc, 0xec, 0xcc, 0x87, 0xff, 0x87, 0x54, 0xf8, 0xe6, 0x3b, 0xd5, 0x70, 0xbd, 0x8e,
+	0x85, 0xe7, 0x6e, 0x72, 0x2d, 0xef, 0x4a, 0xf7, 0xb0, 0xae, 0x6a, 0xef, 0x3c, 0xb5, 0x43, 0x11,
+	0x55, 0xd3, 0x68, 0xc5, 0x88, 0x6d, 0xcc, 0x25, 0x59, 0x6c, 0x4e, 0xd1, 0xd7, 0xf0, 0xe5, 0xcf,
+	0x10, 0xc1, 0x22, 0x4e, 0x85, 0x64, 0x49, 0xf2, 0xff, 0x73, 0x2f, 0x97, 0x12, 0x4b, 0x41, 0x97,
+	0x70, 0xe1, 0xe8, 0x2e, 0xe3, 0xd6, 0xde, 0x99, 0xea, 0xc0, 0x68, 0x7b, 0x7d, 0xe3, 0x0a, 0xab,
+	0xf8, 0x9d, 0x67, 0xab, 0x6e, 0xcf, 0xb3, 0x96, 0x8a, 0xe5, 0x15, 0x2d, 0x4a, 0x1b, 0xeb, 0x9d,
+	0xbe, 0xd1, 0xed, 0xb9, 0x1e, 0x2b, 0x0e, 0x47, 0xfa, 0x98, 0x86, 0xd9, 0x30, 0xaf, 0x75, 0xd3,
+	0xb5, 0xf0, 0xbb, 0xb2, 0xa3, 0x7e, 0x51, 0xa4, 0x96, 0x24, 0xbe, 0x66, 0x61, 0x31, 0x55, 0xdb,
+	0xe9, 0x59, 0xf3, 0xc8, 0xd0, 0x04, 0x92, 0x7e, 0xc9, 0x6a, 0xad, 0x44, 0xc9, 0xb6, 0x9d, 0x51,
+	0xa1, 0xa5, 0x48, 0x67, 0xbc, 0x8e, 0xf4, 0x09, 0xdd, 0x9a, 0xe5, 0x5d, 0x99, 0x78, 0xce, 0xe3,
+	0xca, 0xb7, 0x2e, 0x57, 0xc6, 0xa7, 0x79, 0xc9, 0x4b, 0x55, 0xf5, 0x19, 0xad, 0x06, 0xdb, 0x32,
+	0x4c, 0xd7, 0xb3, 0x3a, 0x9e, 0x69, 0x79, 0x58, 0x77, 0x87, 0xd8, 0x94, 0x2e, 0x68, 0xf0, 0x45,
+	0x17, 0x29, 0xa9, 0xfa, 0x05, 0x3d, 0xcf, 0xc5, 0xaa, 0xe9, 0x74, 0x74, 0x9c, 0x11, 0x17, 0x95,
+	0xff, 0x46, 0xa9, 0xd6, 0x3f, 0x97, 0x3e, 0x3f, 0x3f, 0x2c, 0xab, 0xc4, 0xe3, 0x77, 0x6e, 0x43,
+	0x4d, 0xfc, 0x2a, 0xd1, 0xee, 0x32, 0x6f, 0xde, 0x2c, 0xe5, 0xd6, 0x68, 0xbb, 0xc6, 0x43, 0xd3,
+	0x34, 0x4c, 0xda, 0x51, 0x77, 0xa0, 0xde, 0xb6, 0x06, 0x76, 0x5f, 0xcf, 0xfa, 0x7f, 0x47, 0x35,
+	0xfa, 0xba, 0x26, 0x6d, 0x50, 0x36, 0xe7, 0xad, 0x61, 0xdb, 0xba, 0x26, 0x55, 0x2f, 0xff, 0xb5,
+	0x09, 0xf5, 0xf6, 0x7d, 0xe0, 0x86, 0xbd, 0xd9, 0x0d, 0xfa, 0x0a, 0x60, 0x31, 0xcc, 0xa2, 0xc3,
+	0xa5, 0xd9, 0x9e, 0xdd, 0xa9, 0x27, 0xfc, 0x4e, 0x13, 0x7f, 0x2d, 0xca, 0xda, 0x9b, 0x0a, 0xb2,
+	0xe1, 0xe8, 0x89, 0x47, 0x20, 0xf4, 0xaa, 0x24, 0x64, 0xd5, 0x13, 0xd1, 0x0a, 0x89, 0x6f, 0x60,
+	0x4b, 0x4c, 0xa3, 0x68, 0xbf, 0xf8, 0x6b, 0xf0, 0xd4, 0x8e, 0x4b, 0xa8, 0x67, 0x53, 0x28, 0x3a,
+	0x28, 0xfd, 0x0a, 0x3c, 0xb5, 0xe7, 0x02, 0x6a, 0x7c, 0xfa, 0x42, 0xa8, 0x30, 0xf9, 0x3f, 0xc5,
+	0xff, 0x1b, 0xd8, 0x9e, 0x8f, 0x22, 0x88, 0xff, 0x6f, 0x94, 0x47, 0x98, 0x93, 0xfd, 0x32, 0x4c,
+	0xff, 0x2c, 0xd7, 0xd0, 0xef, 0x00, 0x16, 0x83, 0x88, 0x70, 0xed, 0xd2, 0xb8, 0x72, 0x72, 0xb0,
+	0x84, 0xf3, 0xdd, 0x3a, 0x34, 0x0b, 0xaf, 0x43, 0xe8, 0x38, 0xfb, 0x8f, 0x5b, 0x7a, 0x49, 0x3a,
+	0x39, 0x5a, 0x45, 0xe2, 0x62, 0xae, 0x60, 0x27, 0xff, 0x2e, 0x84, 0x64, 0x7e, 0xdc, 0xf2, 0x0b,
+	0xd2, 0xc9, 0xe1, 0x0a, 0x0a, 0x97, 0xf1, 0x15, 0xd4, 0xb3, 0x37, 0x23, 0xe1, 0xe7, 0xd2, 0xab,
+	0xd2, 0x09, 0x2a, 0xa1, 0xf3, 0x7d, 0xd9, 0xa3, 0x88, 0xd8, 0x57, 0x7a, 0x36, 0x39, 0x41, 0x25,
+	0x74, 0x6e, 0x7a, 0xe1, 0x85, 0x46, 0x98, 0xbe, 0xea, 0x35, 0xe9, 0xe4, 0x68, 0x15, 0x89, 0x89,
+	0xb9, 0xa9, 0xb1, 0xd7, 0xfb, 0x2f, 0xff, 0x33, 0x00, 0x28, 0x0c, 0x0f, 0x5a, 0xea, 0x17, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1546,8 +2308,12 @@ type CliToHubClient interface {
 	Finalize(ctx context.Context, in *FinalizeRequest, opts ...grpc.CallOption) (CliToHub_FinalizeClient, error)
 	Revert(ctx context.Context, in *RevertRequest, opts ...grpc.CallOption) (CliToHub_RevertClient, error)
 	GetConfig(ctx context.Context, in *GetConfigRequest, opts ...grpc.CallOption) (*GetConfigReply, error)
+	ShowConfig(ctx context.Context, in *ShowConfigRequest, opts ...grpc.CallOption) (*ShowConfigReply, error)
 	RestartAgents(ctx context.Context, in *RestartAgentsRequest, opts ...grpc.CallOption) (*RestartAgentsReply, error)
 	StopServices(ctx context.Context, in *StopServicesRequest, opts ...grpc.CallOption) (*StopServicesReply, error)
+	Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateReply, error)
+	Segments(ctx context.Context, in *SegmentsRequest, opts ...grpc.CallOption) (*SegmentsReply, error)
+	SupportBundle(ctx context.Context, in *SupportBundleRequest, opts ...grpc.CallOption) (*SupportBundleReply, error)
 }
 
 type cliToHubClient struct {
@@ -1727,6 +2493,15 @@ func (c *cliToHubClient) GetConfig(ctx context.Context, in *GetConfigRequest, op
 	return out, nil
 }
 
+func (c *cliToHubClient) ShowConfig(ctx context.Context, in *ShowConfigRequest, opts ...grpc.CallOption) (*ShowConfigReply, error) {
+	out := new(ShowConfigReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/ShowConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cliToHubClient) RestartAgents(ctx context.Context, in *RestartAgentsRequest, opts ...grpc.CallOption) (*RestartAgentsReply, error) {
 	out := new(RestartAgentsReply)
 	err := c.cc.Invoke(ctx, "/idl.CliToHub/RestartAgents", in, out, opts...)
@@ -1745,6 +2520,33 @@ func (c *cliToHubClient) StopServices(ctx context.Context, in *StopServicesReque
 	return out, nil
 }
 
+func (c *cliToHubClient) Validate(ctx context.Context, in *ValidateRequest, opts ...grpc.CallOption) (*ValidateReply, error) {
+	out := new(ValidateReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Validate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) Segments(ctx context.Context, in *SegmentsRequest, opts ...grpc.CallOption) (*SegmentsReply, error) {
+	out := new(SegmentsReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/Segments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cliToHubClient) SupportBundle(ctx context.Context, in *SupportBundleRequest, opts ...grpc.CallOption) (*SupportBundleReply, error) {
+	out := new(SupportBundleReply)
+	err := c.cc.Invoke(ctx, "/idl.CliToHub/SupportBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CliToHubServer is the server API for CliToHub service.
 type CliToHubServer interface {
 	Initialize(*InitializeRequest, CliToHub_InitializeServer) error
@@ -1753,8 +2555,12 @@ type CliToHubServer interface {
 	Finalize(*FinalizeRequest, CliToHub_FinalizeServer) error
 	Revert(*RevertRequest, CliToHub_RevertServer) error
 	GetConfig(context.Context, *GetConfigRequest) (*GetConfigReply, error)
+	ShowConfig(context.Context, *ShowConfigRequest) (*ShowConfigReply, error)
 	RestartAgents(context.Context, *RestartAgentsRequest) (*RestartAgentsReply, error)
 	StopServices(context.Context, *StopServicesRequest) (*StopServicesReply, error)
+	Validate(context.Context, *ValidateRequest) (*ValidateReply, error)
+	Segments(context.Context, *SegmentsRequest) (*SegmentsReply, error)
+	SupportBundle(context.Context, *SupportBundleRequest) (*SupportBundleReply, error)
 }
 
 // UnimplementedCliToHubServer can be embedded to have forward compatible implementations.
@@ -1779,12 +2585,24 @@ func (*UnimplementedCliToHubServer) Revert(req *RevertRequest, srv CliToHub_Reve
 func (*UnimplementedCliToHubServer) GetConfig(ctx context.Context, req *GetConfigRequest) (*GetConfigReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetConfig not implemented")
 }
+func (*UnimplementedCliToHubServer) ShowConfig(ctx context.Context, req *ShowConfigRequest) (*ShowConfigReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ShowConfig not implemented")
+}
 func (*UnimplementedCliToHubServer) RestartAgents(ctx context.Context, req *RestartAgentsRequest) (*RestartAgentsReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RestartAgents not implemented")
 }
 func (*UnimplementedCliToHubServer) StopServices(ctx context.Context, req *StopServicesRequest) (*StopServicesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopServices not implemented")
 }
+func (*UnimplementedCliToHubServer) Validate(ctx context.Context, req *ValidateRequest) (*ValidateReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Validate not implemented")
+}
+func (*UnimplementedCliToHubServer) Segments(ctx context.Context, req *SegmentsRequest) (*SegmentsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Segments not implemented")
+}
+func (*UnimplementedCliToHubServer) SupportBundle(ctx context.Context, req *SupportBundleRequest) (*SupportBundleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SupportBundle not implemented")
+}
 
 func RegisterCliToHubServer(s *grpc.Server, srv CliToHubServer) {
 	s.RegisterService(&_CliToHub_serviceDesc, srv)
@@ -1913,6 +2731,24 @@ func _CliToHub_GetConfig_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_ShowConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShowConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).ShowConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/ShowConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).ShowConfig(ctx, req.(*ShowConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _CliToHub_RestartAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RestartAgentsRequest)
 	if err := dec(in); err != nil {
@@ -1949,6 +2785,60 @@ func _CliToHub_StopServices_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CliToHub_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Validate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Validate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Validate(ctx, req.(*ValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_Segments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SegmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).Segments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/Segments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).Segments(ctx, req.(*SegmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CliToHub_SupportBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SupportBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CliToHubServer).SupportBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.CliToHub/SupportBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CliToHubServer).SupportBundle(ctx, req.(*SupportBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CliToHub_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.CliToHub",
 	HandlerType: (*CliToHubServer)(nil),
@@ -1957,6 +2847,10 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetConfig",
 			Handler:    _CliToHub_GetConfig_Handler,
 		},
+		{
+			MethodName: "ShowConfig",
+			Handler:    _CliToHub_ShowConfig_Handler,
+		},
 		{
 			MethodName: "RestartAgents",
 			Handler:    _CliToHub_RestartAgents_Handler,
@@ -1965,6 +2859,18 @@ var _CliToHub_serviceDesc = grpc.ServiceDesc{
 			MethodName: "StopServices",
 			Handler:    _CliToHub_StopServices_Handler,
 		},
+		{
+			MethodName: "Validate",
+			Handler:    _CliToHub_Validate_Handler,
+		},
+		{
+			MethodName: "Segments",
+			Handler:    _CliToHub_Segments_Handler,
+		},
+		{
+			MethodName: "SupportBundle",
+			Handler:    _CliToHub_SupportBundle_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -24,6 +24,40 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// Mode is how pg_upgrade transfers the user data from the source data
+// directories to the target. Clone uses reflinks so that the target shares
+// the source's extents without modifying the source.
+type Mode int32
+
+const (
+	Mode_UNKNOWN_MODE Mode = 0
+	Mode_copy         Mode = 1
+	Mode_link         Mode = 2
+	Mode_clone        Mode = 3
+)
+
+var Mode_name = map[int32]string{
+	0: "UNKNOWN_MODE",
+	1: "copy",
+	2: "link",
+	3: "clone",
+}
+
+var Mode_value = map[string]int32{
+	"UNKNOWN_MODE": 0,
+	"copy":         1,
+	"link":         2,
+	"clone":        3,
+}
+
+func (x Mode) String() string {
+	return proto.EnumName(Mode_name, int32(x))
+}
+
+func (Mode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{0}
+}
+
 type PgOptions_Mode int32
 
 const (
@@ -86,7 +120,7 @@ type PgOptions struct {
 	ContentID            int32                     `protobuf:"varint,3,opt,name=ContentID,proto3" json:"ContentID,omitempty"`
 	Mode                 PgOptions_Mode            `protobuf:"varint,4,opt,name=mode,proto3,enum=idl.PgOptions_Mode" json:"mode,omitempty"`
 	OldOptions           string                    `protobuf:"bytes,5,opt,name=OldOptions,proto3" json:"OldOptions,omitempty"`
-	LinkMode             bool                      `protobuf:"varint,6,opt,name=LinkMode,proto3" json:"LinkMode,omitempty"`
+	UpgradeMode          Mode                      `protobuf:"varint,17,opt,name=UpgradeMode,proto3,enum=idl.Mode" json:"UpgradeMode,omitempty"`
 	TargetVersion        string                    `protobuf:"bytes,7,opt,name=TargetVersion,proto3" json:"TargetVersion,omitempty"`
 	OldBinDir            string                    `protobuf:"bytes,8,opt,name=OldBinDir,proto3" json:"OldBinDir,omitempty"`
 	OldDataDir           string                    `protobuf:"bytes,9,opt,name=OldDataDir,proto3" json:"OldDataDir,omitempty"`
@@ -162,11 +196,11 @@ func (m *PgOptions) GetOldOptions() string {
 	return ""
 }
 
-func (m *PgOptions) GetLinkMode() bool {
+func (m *PgOptions) GetUpgradeMode() Mode {
 	if m != nil {
-		return m.LinkMode
+		return m.UpgradeMode
 	}
-	return false
+	return Mode_UNKNOWN_MODE
 }
 
 func (m *PgOptions) GetTargetVersion() string {
@@ -824,11 +858,13 @@ func (m *StopAgentReply) XXX_DiscardUnknown() {
 var xxx_messageInfo_StopAgentReply proto.InternalMessageInfo
 
 type CheckSegmentDiskSpaceRequest struct {
-	DiskFreeRatio        float64  `protobuf:"fixed64,1,opt,name=diskFreeRatio,proto3" json:"diskFreeRatio,omitempty"`
-	Dirs                 []string `protobuf:"bytes,2,rep,name=dirs,proto3" json:"dirs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DiskFreeRatio        float64              `protobuf:"fixed64,1,opt,name=diskFreeRatio,proto3" json:"diskFreeRatio,omitempty"`
+	Dirs                 []string             `protobuf:"bytes,2,rep,name=dirs,proto3" json:"dirs,omitempty"`
+	Estimates            []*DiskSpaceEstimate `protobuf:"bytes,3,rep,name=estimates,proto3" json:"estimates,omitempty"`
+	SafetyMargin         float64              `protobuf:"fixed64,4,opt,name=safetyMargin,proto3" json:"safetyMargin,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *CheckSegmentDiskSpaceRequest) Reset()         { *m = CheckSegmentDiskSpaceRequest{} }
@@ -870,8 +906,97 @@ func (m *CheckSegmentDiskSpaceRequest) GetDirs() []string {
 	return nil
 }
 
+func (m *CheckSegmentDiskSpaceRequest) GetEstimates() []*DiskSpaceEstimate {
+	if m != nil {
+		return m.Estimates
+	}
+	return nil
+}
+
+func (m *CheckSegmentDiskSpaceRequest) GetSafetyMargin() float64 {
+	if m != nil {
+		return m.SafetyMargin
+	}
+	return 0
+}
+
+// DiskSpaceEstimate describes space the upgrade will consume on the filesystem
+// holding destination. The amount is factor times the combined size of the
+// sources directories plus extraBytes.
+type DiskSpaceEstimate struct {
+	Description          string   `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	Destination          string   `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Sources              []string `protobuf:"bytes,3,rep,name=sources,proto3" json:"sources,omitempty"`
+	Factor               float64  `protobuf:"fixed64,4,opt,name=factor,proto3" json:"factor,omitempty"`
+	ExtraBytes           uint64   `protobuf:"varint,5,opt,name=extraBytes,proto3" json:"extraBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DiskSpaceEstimate) Reset()         { *m = DiskSpaceEstimate{} }
+func (m *DiskSpaceEstimate) String() string { return proto.CompactTextString(m) }
+func (*DiskSpaceEstimate) ProtoMessage()    {}
+func (*DiskSpaceEstimate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{18}
+}
+
+func (m *DiskSpaceEstimate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DiskSpaceEstimate.Unmarshal(m, b)
+}
+func (m *DiskSpaceEstimate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DiskSpaceEstimate.Marshal(b, m, deterministic)
+}
+func (m *DiskSpaceEstimate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DiskSpaceEstimate.Merge(m, src)
+}
+func (m *DiskSpaceEstimate) XXX_Size() int {
+	return xxx_messageInfo_DiskSpaceEstimate.Size(m)
+}
+func (m *DiskSpaceEstimate) XXX_DiscardUnknown() {
+	xxx_messageInfo_DiskSpaceEstimate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DiskSpaceEstimate proto.InternalMessageInfo
+
+func (m *DiskSpaceEstimate) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *DiskSpaceEstimate) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *DiskSpaceEstimate) GetSources() []string {
+	if m != nil {
+		return m.Sources
+	}
+	return nil
+}
+
+func (m *DiskSpaceEstimate) GetFactor() float64 {
+	if m != nil {
+		return m.Factor
+	}
+	return 0
+}
+
+func (m *DiskSpaceEstimate) GetExtraBytes() uint64 {
+	if m != nil {
+		return m.ExtraBytes
+	}
+	return 0
+}
+
 type CheckDiskSpaceReply struct {
 	Usage                []*CheckDiskSpaceReply_DiskUsage `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
+	Estimated            []*CheckDiskSpaceReply_DiskUsage `protobuf:"bytes,2,rep,name=estimated,proto3" json:"estimated,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
 	XXX_unrecognized     []byte                           `json:"-"`
 	XXX_sizecache        int32                            `json:"-"`
@@ -881,7 +1006,7 @@ func (m *CheckDiskSpaceReply) Reset()         { *m = CheckDiskSpaceReply{} }
 func (m *CheckDiskSpaceReply) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceReply) ProtoMessage()    {}
 func (*CheckDiskSpaceReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{18}
+	return fileDescriptor_9e73bb06acc917d8, []int{19}
 }
 
 func (m *CheckDiskSpaceReply) XXX_Unmarshal(b []byte) error {
@@ -909,6 +1034,13 @@ func (m *CheckDiskSpaceReply) GetUsage() []*CheckDiskSpaceReply_DiskUsage {
 	return nil
 }
 
+func (m *CheckDiskSpaceReply) GetEstimated() []*CheckDiskSpaceReply_DiskUsage {
+	if m != nil {
+		return m.Estimated
+	}
+	return nil
+}
+
 type CheckDiskSpaceReply_DiskUsage struct {
 	Fs                   string   `protobuf:"bytes,1,opt,name=fs,proto3" json:"fs,omitempty"`
 	Host                 string   `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
@@ -923,7 +1055,7 @@ func (m *CheckDiskSpaceReply_DiskUsage) Reset()         { *m = CheckDiskSpaceRep
 func (m *CheckDiskSpaceReply_DiskUsage) String() string { return proto.CompactTextString(m) }
 func (*CheckDiskSpaceReply_DiskUsage) ProtoMessage()    {}
 func (*CheckDiskSpaceReply_DiskUsage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{18, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{19, 0}
 }
 
 func (m *CheckDiskSpaceReply_DiskUsage) XXX_Unmarshal(b []byte) error {
@@ -983,7 +1115,7 @@ func (m *RsyncRequest) Reset()         { *m = RsyncRequest{} }
 func (m *RsyncRequest) String() string { return proto.CompactTextString(m) }
 func (*RsyncRequest) ProtoMessage()    {}
 func (*RsyncRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{19}
+	return fileDescriptor_9e73bb06acc917d8, []int{20}
 }
 
 func (m *RsyncRequest) XXX_Unmarshal(b []byte) error {
@@ -1026,7 +1158,7 @@ func (m *RsyncRequest_RsyncOptions) Reset()         { *m = RsyncRequest_RsyncOpt
 func (m *RsyncRequest_RsyncOptions) String() string { return proto.CompactTextString(m) }
 func (*RsyncRequest_RsyncOptions) ProtoMessage()    {}
 func (*RsyncRequest_RsyncOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{19, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{20, 0}
 }
 
 func (m *RsyncRequest_RsyncOptions) XXX_Unmarshal(b []byte) error {
@@ -1082,17 +1214,19 @@ func (m *RsyncRequest_RsyncOptions) GetExcludedFiles() []string {
 	return nil
 }
 
+// RsyncReply has the statistics of each rsync run with --stats.
 type RsyncReply struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Stats                []*ResyncStats `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *RsyncReply) Reset()         { *m = RsyncReply{} }
 func (m *RsyncReply) String() string { return proto.CompactTextString(m) }
 func (*RsyncReply) ProtoMessage()    {}
 func (*RsyncReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{20}
+	return fileDescriptor_9e73bb06acc917d8, []int{21}
 }
 
 func (m *RsyncReply) XXX_Unmarshal(b []byte) error {
@@ -1113,6 +1247,117 @@ func (m *RsyncReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_RsyncReply proto.InternalMessageInfo
 
+func (m *RsyncReply) GetStats() []*ResyncStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+// ResyncStats reports how much of a directory rsync sent in full rather than
+// matching against the existing files at the destination.
+type ResyncStats struct {
+	DestinationHost      string   `protobuf:"bytes,1,opt,name=destinationHost,proto3" json:"destinationHost,omitempty"`
+	Destination          string   `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	TotalBytes           uint64   `protobuf:"varint,3,opt,name=totalBytes,proto3" json:"totalBytes,omitempty"`
+	LiteralBytes         uint64   `protobuf:"varint,4,opt,name=literalBytes,proto3" json:"literalBytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResyncStats) Reset()         { *m = ResyncStats{} }
+func (m *ResyncStats) String() string { return proto.CompactTextString(m) }
+func (*ResyncStats) ProtoMessage()    {}
+func (*ResyncStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{22}
+}
+
+func (m *ResyncStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResyncStats.Unmarshal(m, b)
+}
+func (m *ResyncStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResyncStats.Marshal(b, m, deterministic)
+}
+func (m *ResyncStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResyncStats.Merge(m, src)
+}
+func (m *ResyncStats) XXX_Size() int {
+	return xxx_messageInfo_ResyncStats.Size(m)
+}
+func (m *ResyncStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResyncStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResyncStats proto.InternalMessageInfo
+
+func (m *ResyncStats) GetDestinationHost() string {
+	if m != nil {
+		return m.DestinationHost
+	}
+	return ""
+}
+
+func (m *ResyncStats) GetDestination() string {
+	if m != nil {
+		return m.Destination
+	}
+	return ""
+}
+
+func (m *ResyncStats) GetTotalBytes() uint64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
+}
+
+func (m *ResyncStats) GetLiteralBytes() uint64 {
+	if m != nil {
+		return m.LiteralBytes
+	}
+	return 0
+}
+
+type ResyncReply struct {
+	Stats                []*ResyncStats `protobuf:"bytes,1,rep,name=stats,proto3" json:"stats,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ResyncReply) Reset()         { *m = ResyncReply{} }
+func (m *ResyncReply) String() string { return proto.CompactTextString(m) }
+func (*ResyncReply) ProtoMessage()    {}
+func (*ResyncReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{23}
+}
+
+func (m *ResyncReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResyncReply.Unmarshal(m, b)
+}
+func (m *ResyncReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResyncReply.Marshal(b, m, deterministic)
+}
+func (m *ResyncReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResyncReply.Merge(m, src)
+}
+func (m *ResyncReply) XXX_Size() int {
+	return xxx_messageInfo_ResyncReply.Size(m)
+}
+func (m *ResyncReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResyncReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResyncReply proto.InternalMessageInfo
+
+func (m *ResyncReply) GetStats() []*ResyncStats {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
 type RestorePgControlRequest struct {
 	Datadirs             []string `protobuf:"bytes,1,rep,name=datadirs,proto3" json:"datadirs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -1124,7 +1369,7 @@ func (m *RestorePgControlRequest) Reset()         { *m = RestorePgControlRequest
 func (m *RestorePgControlRequest) String() string { return proto.CompactTextString(m) }
 func (*RestorePgControlRequest) ProtoMessage()    {}
 func (*RestorePgControlRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{21}
+	return fileDescriptor_9e73bb06acc917d8, []int{24}
 }
 
 func (m *RestorePgControlRequest) XXX_Unmarshal(b []byte) error {
@@ -1162,7 +1407,7 @@ func (m *RestorePgControlReply) Reset()         { *m = RestorePgControlReply{} }
 func (m *RestorePgControlReply) String() string { return proto.CompactTextString(m) }
 func (*RestorePgControlReply) ProtoMessage()    {}
 func (*RestorePgControlReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{22}
+	return fileDescriptor_9e73bb06acc917d8, []int{25}
 }
 
 func (m *RestorePgControlReply) XXX_Unmarshal(b []byte) error {
@@ -1196,7 +1441,7 @@ func (m *UpdateFileConfOptions) Reset()         { *m = UpdateFileConfOptions{} }
 func (m *UpdateFileConfOptions) String() string { return proto.CompactTextString(m) }
 func (*UpdateFileConfOptions) ProtoMessage()    {}
 func (*UpdateFileConfOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{23}
+	return fileDescriptor_9e73bb06acc917d8, []int{26}
 }
 
 func (m *UpdateFileConfOptions) XXX_Unmarshal(b []byte) error {
@@ -1249,7 +1494,7 @@ func (m *UpdateConfigurationRequest) Reset()         { *m = UpdateConfigurationR
 func (m *UpdateConfigurationRequest) String() string { return proto.CompactTextString(m) }
 func (*UpdateConfigurationRequest) ProtoMessage()    {}
 func (*UpdateConfigurationRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{24}
+	return fileDescriptor_9e73bb06acc917d8, []int{27}
 }
 
 func (m *UpdateConfigurationRequest) XXX_Unmarshal(b []byte) error {
@@ -1287,7 +1532,7 @@ func (m *UpdateConfigurationReply) Reset()         { *m = UpdateConfigurationRep
 func (m *UpdateConfigurationReply) String() string { return proto.CompactTextString(m) }
 func (*UpdateConfigurationReply) ProtoMessage()    {}
 func (*UpdateConfigurationReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{25}
+	return fileDescriptor_9e73bb06acc917d8, []int{28}
 }
 
 func (m *UpdateConfigurationReply) XXX_Unmarshal(b []byte) error {
@@ -1319,7 +1564,7 @@ func (m *RenameTablespacesRequest) Reset()         { *m = RenameTablespacesReque
 func (m *RenameTablespacesRequest) String() string { return proto.CompactTextString(m) }
 func (*RenameTablespacesRequest) ProtoMessage()    {}
 func (*RenameTablespacesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{26}
+	return fileDescriptor_9e73bb06acc917d8, []int{29}
 }
 
 func (m *RenameTablespacesRequest) XXX_Unmarshal(b []byte) error {
@@ -1359,7 +1604,7 @@ func (m *RenameTablespacesRequest_RenamePair) Reset()         { *m = RenameTable
 func (m *RenameTablespacesRequest_RenamePair) String() string { return proto.CompactTextString(m) }
 func (*RenameTablespacesRequest_RenamePair) ProtoMessage()    {}
 func (*RenameTablespacesRequest_RenamePair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{26, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{29, 0}
 }
 
 func (m *RenameTablespacesRequest_RenamePair) XXX_Unmarshal(b []byte) error {
@@ -1404,7 +1649,7 @@ func (m *RenameTablespacesReply) Reset()         { *m = RenameTablespacesReply{}
 func (m *RenameTablespacesReply) String() string { return proto.CompactTextString(m) }
 func (*RenameTablespacesReply) ProtoMessage()    {}
 func (*RenameTablespacesReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{27}
+	return fileDescriptor_9e73bb06acc917d8, []int{30}
 }
 
 func (m *RenameTablespacesReply) XXX_Unmarshal(b []byte) error {
@@ -1436,7 +1681,7 @@ func (m *CreateRecoveryConfRequest) Reset()         { *m = CreateRecoveryConfReq
 func (m *CreateRecoveryConfRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRecoveryConfRequest) ProtoMessage()    {}
 func (*CreateRecoveryConfRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{28}
+	return fileDescriptor_9e73bb06acc917d8, []int{31}
 }
 
 func (m *CreateRecoveryConfRequest) XXX_Unmarshal(b []byte) error {
@@ -1478,7 +1723,7 @@ func (m *CreateRecoveryConfRequest_Connection) Reset()         { *m = CreateReco
 func (m *CreateRecoveryConfRequest_Connection) String() string { return proto.CompactTextString(m) }
 func (*CreateRecoveryConfRequest_Connection) ProtoMessage()    {}
 func (*CreateRecoveryConfRequest_Connection) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{28, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{31, 0}
 }
 
 func (m *CreateRecoveryConfRequest_Connection) XXX_Unmarshal(b []byte) error {
@@ -1537,7 +1782,7 @@ func (m *CreateRecoveryConfReply) Reset()         { *m = CreateRecoveryConfReply
 func (m *CreateRecoveryConfReply) String() string { return proto.CompactTextString(m) }
 func (*CreateRecoveryConfReply) ProtoMessage()    {}
 func (*CreateRecoveryConfReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{29}
+	return fileDescriptor_9e73bb06acc917d8, []int{32}
 }
 
 func (m *CreateRecoveryConfReply) XXX_Unmarshal(b []byte) error {
@@ -1569,7 +1814,7 @@ func (m *AddReplicationEntriesRequest) Reset()         { *m = AddReplicationEntr
 func (m *AddReplicationEntriesRequest) String() string { return proto.CompactTextString(m) }
 func (*AddReplicationEntriesRequest) ProtoMessage()    {}
 func (*AddReplicationEntriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{30}
+	return fileDescriptor_9e73bb06acc917d8, []int{33}
 }
 
 func (m *AddReplicationEntriesRequest) XXX_Unmarshal(b []byte) error {
@@ -1610,7 +1855,7 @@ func (m *AddReplicationEntriesRequest_Entry) Reset()         { *m = AddReplicati
 func (m *AddReplicationEntriesRequest_Entry) String() string { return proto.CompactTextString(m) }
 func (*AddReplicationEntriesRequest_Entry) ProtoMessage()    {}
 func (*AddReplicationEntriesRequest_Entry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{30, 0}
+	return fileDescriptor_9e73bb06acc917d8, []int{33, 0}
 }
 
 func (m *AddReplicationEntriesRequest_Entry) XXX_Unmarshal(b []byte) error {
@@ -1662,7 +1907,7 @@ func (m *AddReplicationEntriesReply) Reset()         { *m = AddReplicationEntrie
 func (m *AddReplicationEntriesReply) String() string { return proto.CompactTextString(m) }
 func (*AddReplicationEntriesReply) ProtoMessage()    {}
 func (*AddReplicationEntriesReply) Descriptor() ([]byte, []int) {
-	return fileDescriptor_9e73bb06acc917d8, []int{31}
+	return fileDescriptor_9e73bb06acc917d8, []int{34}
 }
 
 func (m *AddReplicationEntriesReply) XXX_Unmarshal(b []byte) error {
@@ -1683,185 +1928,931 @@ func (m *AddReplicationEntriesReply) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AddReplicationEntriesReply proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterEnum("idl.PgOptions_Mode", PgOptions_Mode_name, PgOptions_Mode_value)
-	proto.RegisterEnum("idl.PgOptions_Action", PgOptions_Action_name, PgOptions_Action_value)
-	proto.RegisterType((*PgOptions)(nil), "idl.PgOptions")
-	proto.RegisterMapType((map[int32]*TablespaceInfo)(nil), "idl.PgOptions.TablespacesEntry")
-	proto.RegisterType((*TablespaceInfo)(nil), "idl.TablespaceInfo")
-	proto.RegisterType((*UpgradePrimariesRequest)(nil), "idl.UpgradePrimariesRequest")
-	proto.RegisterType((*UpgradePrimariesReply)(nil), "idl.UpgradePrimariesReply")
-	proto.RegisterType((*DeleteDataDirectoriesRequest)(nil), "idl.DeleteDataDirectoriesRequest")
-	proto.RegisterType((*DeleteDataDirectoriesReply)(nil), "idl.DeleteDataDirectoriesReply")
-	proto.RegisterType((*DeleteStateDirectoryRequest)(nil), "idl.DeleteStateDirectoryRequest")
-	proto.RegisterType((*DeleteStateDirectoryReply)(nil), "idl.DeleteStateDirectoryReply")
-	proto.RegisterType((*DeleteTablespaceRequest)(nil), "idl.DeleteTablespaceRequest")
-	proto.RegisterType((*DeleteTablespaceReply)(nil), "idl.DeleteTablespaceReply")
-	proto.RegisterType((*ArchiveLogDirectoryRequest)(nil), "idl.ArchiveLogDirectoryRequest")
-	proto.RegisterType((*ArchiveLogDirectoryReply)(nil), "idl.ArchiveLogDirectoryReply")
-	proto.RegisterType((*RenameDirectories)(nil), "idl.RenameDirectories")
-	proto.RegisterType((*RenameDirectoriesRequest)(nil), "idl.RenameDirectoriesRequest")
-	proto.RegisterType((*RenameDirectoriesReply)(nil), "idl.RenameDirectoriesReply")
-	proto.RegisterType((*StopAgentRequest)(nil), "idl.StopAgentRequest")
-	proto.RegisterType((*StopAgentReply)(nil), "idl.StopAgentReply")
-	proto.RegisterType((*CheckSegmentDiskSpaceRequest)(nil), "idl.CheckSegmentDiskSpaceRequest")
-	proto.RegisterType((*CheckDiskSpaceReply)(nil), "idl.CheckDiskSpaceReply")
-	proto.RegisterType((*CheckDiskSpaceReply_DiskUsage)(nil), "idl.CheckDiskSpaceReply.DiskUsage")
-	proto.RegisterType((*RsyncRequest)(nil), "idl.RsyncRequest")
-	proto.RegisterType((*RsyncRequest_RsyncOptions)(nil), "idl.RsyncRequest.RsyncOptions")
-	proto.RegisterType((*RsyncReply)(nil), "idl.RsyncReply")
-	proto.RegisterType((*RestorePgControlRequest)(nil), "idl.RestorePgControlRequest")
-	proto.RegisterType((*RestorePgControlReply)(nil), "idl.RestorePgControlReply")
-	proto.RegisterType((*UpdateFileConfOptions)(nil), "idl.UpdateFileConfOptions")
-	proto.RegisterType((*UpdateConfigurationRequest)(nil), "idl.UpdateConfigurationRequest")
-	proto.RegisterType((*UpdateConfigurationReply)(nil), "idl.UpdateConfigurationReply")
-	proto.RegisterType((*RenameTablespacesRequest)(nil), "idl.RenameTablespacesRequest")
-	proto.RegisterType((*RenameTablespacesRequest_RenamePair)(nil), "idl.RenameTablespacesRequest.RenamePair")
-	proto.RegisterType((*RenameTablespacesReply)(nil), "idl.RenameTablespacesReply")
-	proto.RegisterType((*CreateRecoveryConfRequest)(nil), "idl.CreateRecoveryConfRequest")
-	proto.RegisterType((*CreateRecoveryConfRequest_Connection)(nil), "idl.CreateRecoveryConfRequest.Connection")
-	proto.RegisterType((*CreateRecoveryConfReply)(nil), "idl.CreateRecoveryConfReply")
-	proto.RegisterType((*AddReplicationEntriesRequest)(nil), "idl.AddReplicationEntriesRequest")
-	proto.RegisterType((*AddReplicationEntriesRequest_Entry)(nil), "idl.AddReplicationEntriesRequest.Entry")
-	proto.RegisterType((*AddReplicationEntriesReply)(nil), "idl.AddReplicationEntriesReply")
+type PreflightRequest struct {
+	TargetGPHome         string   `protobuf:"bytes,1,opt,name=targetGPHome,proto3" json:"targetGPHome,omitempty"`
+	TargetVersion        string   `protobuf:"bytes,2,opt,name=targetVersion,proto3" json:"targetVersion,omitempty"`
+	GpupgradeVersion     string   `protobuf:"bytes,3,opt,name=gpupgradeVersion,proto3" json:"gpupgradeVersion,omitempty"`
+	Ports                []uint32 `protobuf:"varint,4,rep,packed,name=ports,proto3" json:"ports,omitempty"`
+	Tools                []string `protobuf:"bytes,5,rep,name=tools,proto3" json:"tools,omitempty"`
+	Locales              []string `protobuf:"bytes,6,rep,name=locales,proto3" json:"locales,omitempty"`
+	DataDirParents       []string `protobuf:"bytes,7,rep,name=dataDirParents,proto3" json:"dataDirParents,omitempty"`
+	MinOpenFiles         uint64   `protobuf:"varint,8,opt,name=minOpenFiles,proto3" json:"minOpenFiles,omitempty"`
+	MinProcesses         uint64   `protobuf:"varint,9,opt,name=minProcesses,proto3" json:"minProcesses,omitempty"`
+	SnapshotProvider     string   `protobuf:"bytes,10,opt,name=snapshotProvider,proto3" json:"snapshotProvider,omitempty"`
+	SnapshotDirs         []string `protobuf:"bytes,11,rep,name=snapshotDirs,proto3" json:"snapshotDirs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("hub_to_agent.proto", fileDescriptor_9e73bb06acc917d8) }
-
-var fileDescriptor_9e73bb06acc917d8 = []byte{
-	// 1512 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x18, 0x5d, 0x6f, 0xd3, 0x56,
-	0xbb, 0x4e, 0x93, 0xa6, 0x79, 0x52, 0x42, 0x38, 0x05, 0xea, 0x9a, 0x50, 0xf2, 0x5a, 0x48, 0x94,
-	0x57, 0xa2, 0x17, 0x1d, 0x48, 0x0c, 0xed, 0x26, 0x34, 0xab, 0xc6, 0x60, 0x69, 0xe7, 0xd0, 0xb1,
-	0x4d, 0x9a, 0x90, 0x6b, 0x9f, 0xa6, 0x56, 0x5d, 0xdb, 0x1c, 0x3b, 0x65, 0xf9, 0x0b, 0xfb, 0x31,
-	0xbb, 0x9a, 0x76, 0xb1, 0x5f, 0xb3, 0xfd, 0x88, 0x5d, 0x6f, 0x7a, 0xce, 0x87, 0x7d, 0x92, 0xd8,
-	0x15, 0xbb, 0xf3, 0xf3, 0xfd, 0x71, 0x9e, 0x2f, 0x19, 0xc8, 0xf9, 0xf4, 0xf4, 0x7d, 0x16, 0xbf,
-	0x77, 0x27, 0x34, 0xca, 0xf6, 0x12, 0x16, 0x67, 0x31, 0x59, 0x0d, 0xfc, 0xd0, 0xfe, 0xb3, 0x01,
-	0xad, 0xe3, 0xc9, 0x51, 0x92, 0x05, 0x71, 0x94, 0x92, 0x27, 0xb0, 0xe6, 0x7a, 0xf8, 0x69, 0x1a,
-	0x7d, 0x63, 0xb7, 0xb3, 0x7f, 0x67, 0x2f, 0xf0, 0xc3, 0xbd, 0x9c, 0xbe, 0x37, 0xe0, 0x44, 0x47,
-	0x32, 0x11, 0x02, 0x75, 0x27, 0x0e, 0xa9, 0x59, 0xeb, 0x1b, 0xbb, 0x2d, 0x87, 0x7f, 0x93, 0x1e,
-	0xb4, 0x0e, 0xe2, 0x28, 0xa3, 0x51, 0xf6, 0x6a, 0x68, 0xae, 0xf6, 0x8d, 0xdd, 0x86, 0x53, 0x20,
-	0xc8, 0x23, 0xa8, 0x5f, 0xc6, 0x3e, 0x35, 0xeb, 0x5c, 0xfd, 0xe6, 0x82, 0xfa, 0x6f, 0x62, 0x9f,
-	0x3a, 0x9c, 0x81, 0xec, 0x00, 0x1c, 0x85, 0xbe, 0x24, 0x98, 0x0d, 0x6e, 0x40, 0xc3, 0x10, 0x0b,
-	0xd6, 0xdf, 0x04, 0xd1, 0x05, 0x4a, 0x98, 0x6b, 0x7d, 0x63, 0x77, 0xdd, 0xc9, 0x61, 0xf2, 0x10,
-	0x6e, 0xbc, 0x75, 0xd9, 0x84, 0x66, 0xdf, 0x51, 0x96, 0x62, 0x30, 0x4d, 0x2e, 0x3e, 0x8f, 0x44,
-	0x47, 0x8f, 0x42, 0xff, 0x65, 0x10, 0x0d, 0x03, 0x66, 0xae, 0x73, 0x8e, 0x02, 0x21, 0xed, 0x0f,
-	0xdd, 0xcc, 0x45, 0x72, 0x2b, 0xb7, 0x2f, 0x31, 0xc4, 0x84, 0xe6, 0x51, 0xe8, 0x1f, 0xc7, 0x2c,
-	0x33, 0x81, 0x13, 0x15, 0x28, 0x29, 0xc3, 0x97, 0xaf, 0x86, 0x66, 0x3b, 0xa7, 0x20, 0x88, 0x16,
-	0x47, 0xf4, 0xa3, 0xb4, 0xb8, 0x21, 0x2c, 0xe6, 0x08, 0xb4, 0x38, 0xa2, 0x1f, 0x95, 0xc5, 0x1b,
-	0xc2, 0x62, 0x81, 0x41, 0xbd, 0x23, 0xfa, 0x91, 0x5b, 0xec, 0x08, 0xbd, 0x12, 0x94, 0x14, 0x6e,
-	0xf1, 0x66, 0x4e, 0xe1, 0x16, 0x07, 0xd0, 0x7e, 0xeb, 0x9e, 0x86, 0x34, 0x4d, 0x5c, 0x8f, 0xa6,
-	0x66, 0xb7, 0xbf, 0xba, 0xdb, 0xde, 0x7f, 0xb0, 0x90, 0x75, 0x8d, 0xe3, 0xcb, 0x28, 0x63, 0x33,
-	0x47, 0x97, 0xb1, 0xc6, 0xd0, 0x5d, 0x64, 0x20, 0x5d, 0x58, 0xbd, 0xa0, 0x33, 0x5e, 0x23, 0x0d,
-	0x07, 0x3f, 0xc9, 0x63, 0x68, 0x5c, 0xb9, 0xe1, 0x54, 0x94, 0x42, 0x5b, 0x3e, 0x6c, 0x21, 0xf7,
-	0x2a, 0x3a, 0x8b, 0x1d, 0xc1, 0xf1, 0xa2, 0xf6, 0xdc, 0xb0, 0x9f, 0x41, 0x9d, 0xbf, 0x54, 0x17,
-	0x36, 0x4e, 0x46, 0xaf, 0x47, 0x47, 0xef, 0x46, 0xef, 0x11, 0xee, 0xae, 0x90, 0x0e, 0xc0, 0x30,
-	0x48, 0x13, 0x37, 0xf3, 0xce, 0x29, 0xeb, 0x1a, 0xa4, 0x0d, 0xcd, 0x31, 0x9d, 0x5c, 0xd2, 0x28,
-	0xeb, 0xd6, 0xec, 0xa7, 0xb0, 0x36, 0x50, 0x95, 0xd7, 0x51, 0x82, 0x02, 0xd3, 0x5d, 0x41, 0xd6,
-	0x69, 0x32, 0x61, 0xae, 0x4f, 0xbb, 0x06, 0x69, 0x41, 0xc3, 0x3b, 0xa7, 0xde, 0x45, 0xb7, 0x66,
-	0x9f, 0x42, 0x67, 0xde, 0x13, 0xac, 0xdb, 0x91, 0x7b, 0x49, 0x79, 0x79, 0xb6, 0x1c, 0xfe, 0xcd,
-	0x0b, 0x2a, 0xf6, 0x5c, 0x5e, 0xfc, 0x75, 0x8e, 0xcf, 0x61, 0xd2, 0x87, 0xf6, 0x49, 0x4a, 0xd9,
-	0x90, 0x9e, 0x05, 0x11, 0xf5, 0x79, 0x35, 0xae, 0x3b, 0x3a, 0xca, 0x0e, 0x61, 0xeb, 0x44, 0xd8,
-	0x3e, 0x66, 0xc1, 0xa5, 0xcb, 0x02, 0x9a, 0x3a, 0xf4, 0xc3, 0x94, 0xa6, 0xd9, 0x7f, 0xed, 0x29,
-	0x1b, 0xea, 0x71, 0x92, 0xa5, 0x66, 0x8d, 0xbf, 0x55, 0x67, 0x9e, 0xd9, 0xe1, 0x34, 0x7b, 0x0b,
-	0xee, 0x2c, 0x5b, 0x4b, 0xc2, 0x99, 0xfd, 0x02, 0x7a, 0x43, 0x1a, 0xd2, 0x8c, 0xca, 0xa2, 0xa1,
-	0x5e, 0x16, 0xeb, 0xbe, 0x58, 0xb0, 0xee, 0xbb, 0x99, 0xeb, 0x07, 0x2c, 0x35, 0x8d, 0xfe, 0x2a,
-	0x06, 0xa9, 0x60, 0xbb, 0x07, 0x56, 0x85, 0x2c, 0x6a, 0xbe, 0x0f, 0xf7, 0x04, 0x75, 0x9c, 0xb9,
-	0x19, 0x55, 0xe4, 0x99, 0x54, 0x6c, 0xdf, 0x83, 0xed, 0x72, 0x32, 0xca, 0x3e, 0x81, 0x2d, 0x41,
-	0x2c, 0x9e, 0x41, 0x39, 0x44, 0xa0, 0xae, 0x39, 0xc3, 0xbf, 0x31, 0xba, 0x65, 0x76, 0xd4, 0xf3,
-	0x14, 0xac, 0x01, 0xf3, 0xce, 0x83, 0x2b, 0xfa, 0x26, 0x9e, 0x2c, 0xba, 0x40, 0xee, 0xc2, 0x1a,
-	0x96, 0x7d, 0xc0, 0x78, 0x9e, 0x5b, 0x8e, 0x84, 0x6c, 0x0b, 0xcc, 0x52, 0x29, 0xd4, 0x78, 0x00,
-	0xb7, 0x1c, 0x1a, 0xb9, 0x97, 0x54, 0x8b, 0x17, 0x15, 0x8d, 0xe3, 0x29, 0xf3, 0xa8, 0x52, 0x24,
-	0x20, 0xc4, 0x8b, 0x09, 0x22, 0xe7, 0x9d, 0x84, 0xec, 0x43, 0x30, 0x97, 0x94, 0x28, 0xa7, 0xfe,
-	0x0f, 0xf5, 0xa1, 0x8a, 0xaf, 0xbd, 0x7f, 0x97, 0xbf, 0xe6, 0x32, 0x33, 0xe7, 0xb1, 0x4d, 0xb8,
-	0x5b, 0xa2, 0x07, 0xdd, 0x24, 0xd0, 0x1d, 0x67, 0x71, 0x32, 0xc0, 0xe1, 0xad, 0x32, 0xde, 0x85,
-	0x8e, 0x86, 0x43, 0xae, 0xef, 0xa1, 0x77, 0x80, 0x25, 0x2f, 0xfb, 0x65, 0x18, 0xa4, 0x17, 0x63,
-	0x3d, 0xd7, 0x0f, 0xe1, 0x86, 0x1f, 0xa4, 0x17, 0x87, 0x8c, 0x52, 0x07, 0xeb, 0x9a, 0x87, 0x67,
-	0x38, 0xf3, 0xc8, 0xfc, 0x45, 0x6a, 0xda, 0x8b, 0xfc, 0x61, 0xc0, 0x26, 0x57, 0xad, 0xe9, 0x4c,
-	0xc2, 0x19, 0x79, 0x0e, 0x8d, 0x69, 0xea, 0x4e, 0xa8, 0x0c, 0xcf, 0xe6, 0xe1, 0x95, 0x30, 0xee,
-	0x21, 0x78, 0x82, 0x9c, 0x8e, 0x10, 0xb0, 0x02, 0x68, 0xe5, 0x38, 0xd2, 0x81, 0xda, 0x59, 0x2a,
-	0x93, 0x5d, 0x3b, 0x4b, 0xd1, 0x85, 0xf3, 0x38, 0x55, 0x69, 0xe6, 0xdf, 0x38, 0x3b, 0xdd, 0x2b,
-	0x37, 0x08, 0xb1, 0x24, 0x78, 0xdf, 0xd6, 0x9d, 0x02, 0x81, 0x75, 0xcd, 0xe8, 0x87, 0x69, 0xc0,
-	0xa8, 0xcf, 0x9b, 0xb7, 0xee, 0xe4, 0xb0, 0xfd, 0x8f, 0x01, 0x1b, 0x4e, 0x3a, 0x8b, 0x3c, 0x95,
-	0x87, 0xe7, 0xd0, 0x8c, 0xe5, 0x5e, 0x11, 0x7e, 0xef, 0x88, 0x67, 0xd1, 0x78, 0x04, 0xa0, 0x9a,
-	0x4e, 0xb1, 0x5b, 0xbf, 0x29, 0x55, 0x6a, 0x0b, 0x99, 0xd0, 0x4c, 0x79, 0x71, 0xa8, 0x0a, 0x56,
-	0x20, 0xd9, 0x85, 0x9b, 0x3e, 0x4d, 0xb3, 0x20, 0xe2, 0x13, 0xe4, 0xab, 0x22, 0x9c, 0x45, 0x34,
-	0x0e, 0x17, 0x0d, 0x25, 0x67, 0x92, 0x8e, 0x42, 0x2b, 0xca, 0xe1, 0xba, 0xb0, 0x22, 0x41, 0x7c,
-	0x52, 0xfa, 0xb3, 0x17, 0x4e, 0x7d, 0xea, 0x1f, 0x06, 0x21, 0xc5, 0x45, 0x89, 0xf4, 0x79, 0xa4,
-	0xbd, 0x01, 0x20, 0x83, 0xc3, 0x32, 0x79, 0x06, 0x5b, 0x0e, 0x4d, 0xb3, 0x98, 0xd1, 0xe3, 0x09,
-	0x2e, 0x66, 0x16, 0x87, 0x9f, 0x32, 0x1e, 0xb6, 0xe0, 0xce, 0xb2, 0x18, 0xea, 0x9b, 0xe0, 0x30,
-	0xf2, 0xdd, 0x8c, 0xa2, 0xb1, 0x83, 0x38, 0x3a, 0x53, 0xc9, 0x21, 0x50, 0x4f, 0xdc, 0xec, 0x5c,
-	0x3e, 0x2c, 0xff, 0xc6, 0x50, 0x12, 0x37, 0xcb, 0x28, 0x8b, 0x64, 0x3a, 0x14, 0x88, 0x69, 0x60,
-	0x34, 0x09, 0x5d, 0x8f, 0x62, 0xf1, 0xaa, 0x34, 0x68, 0x28, 0xdb, 0x01, 0x4b, 0x18, 0x42, 0x23,
-	0xc1, 0x64, 0xca, 0x78, 0x76, 0x94, 0xef, 0x4f, 0x17, 0x5f, 0xd5, 0xe2, 0xaf, 0x5a, 0xea, 0x5a,
-	0x9e, 0x40, 0x1c, 0x0e, 0xa5, 0x3a, 0x31, 0xb0, 0x5f, 0x0d, 0xd5, 0xd8, 0xda, 0x02, 0x54, 0xe6,
-	0xbe, 0x46, 0x77, 0x91, 0x76, 0xec, 0x16, 0xfd, 0xbd, 0xab, 0xf5, 0xf7, 0xb2, 0x8c, 0x24, 0xa0,
-	0x80, 0xa3, 0x0b, 0x5b, 0x87, 0x00, 0x05, 0x09, 0xc7, 0x4c, 0x3a, 0x37, 0x7e, 0x04, 0xb4, 0x58,
-	0x27, 0xb5, 0xa5, 0x3a, 0x29, 0x06, 0xc8, 0x9c, 0x6d, 0x0c, 0xe5, 0x6f, 0x03, 0xb6, 0x0f, 0x18,
-	0x75, 0x33, 0xea, 0x50, 0x2f, 0xbe, 0xa2, 0x6c, 0x86, 0xf1, 0xaa, 0x58, 0x5e, 0x43, 0xdb, 0x8b,
-	0xa3, 0x88, 0x7a, 0x7a, 0xfa, 0x1e, 0x8b, 0x66, 0xae, 0x12, 0xda, 0x3b, 0xc8, 0x25, 0x1c, 0x5d,
-	0xda, 0xfa, 0xc5, 0x00, 0x28, 0x68, 0x58, 0xa1, 0x97, 0x01, 0x63, 0x31, 0x53, 0x87, 0x8d, 0xf0,
-	0x7b, 0x1e, 0x89, 0xa5, 0x32, 0x4d, 0xa9, 0x9a, 0xdc, 0xfc, 0x1b, 0xe3, 0x4d, 0xf8, 0x76, 0x9b,
-	0xf1, 0xee, 0x91, 0x05, 0xa1, 0xa1, 0x34, 0x0e, 0x7e, 0x15, 0xd5, 0xf9, 0x39, 0xa2, 0xa3, 0xec,
-	0x6d, 0xd8, 0x2a, 0x8b, 0x00, 0x53, 0xf2, 0xbb, 0x01, 0xbd, 0x81, 0xef, 0x23, 0x10, 0x88, 0x35,
-	0x8f, 0xb7, 0x8d, 0x36, 0xba, 0x07, 0xd0, 0xa4, 0x02, 0x23, 0x33, 0xf2, 0x88, 0x67, 0xe4, 0x3a,
-	0x99, 0x3d, 0x71, 0x3f, 0x29, 0x39, 0x6b, 0x0c, 0x0d, 0x71, 0x30, 0x99, 0xd0, 0x54, 0xf1, 0x8b,
-	0x10, 0x9b, 0x5a, 0xe4, 0x78, 0x47, 0xa8, 0x59, 0x87, 0xdf, 0x38, 0xeb, 0x30, 0xbe, 0x81, 0xef,
-	0xb3, 0xd4, 0x5c, 0xe5, 0x7d, 0x58, 0x20, 0x70, 0x4f, 0x57, 0xf8, 0x90, 0x84, 0xb3, 0xfd, 0xbf,
-	0x5a, 0xd0, 0xe0, 0x3b, 0x81, 0x1c, 0x41, 0x67, 0x7e, 0x14, 0x93, 0xff, 0x15, 0xf3, 0xb9, 0x62,
-	0x47, 0x58, 0x66, 0xd5, 0x08, 0xb7, 0x57, 0xc8, 0x08, 0xba, 0x8b, 0x57, 0x07, 0xe9, 0xc9, 0x26,
-	0x2b, 0x3d, 0x7d, 0x2c, 0xab, 0x82, 0x2a, 0xf4, 0x7d, 0x5b, 0xb6, 0x7c, 0xef, 0x57, 0xac, 0x48,
-	0xa9, 0xf1, 0x5e, 0x15, 0x59, 0xa8, 0xfc, 0x1c, 0x5a, 0xf9, 0x52, 0x24, 0xe2, 0xd0, 0x5a, 0x5c,
-	0x9c, 0xd6, 0xe6, 0x22, 0x5a, 0x88, 0xfe, 0xa4, 0xae, 0x8e, 0x85, 0xf3, 0x47, 0x66, 0xed, 0xba,
-	0xb3, 0xca, 0x7a, 0x70, 0x1d, 0x8b, 0x50, 0xff, 0x23, 0xdc, 0x2e, 0x3b, 0x90, 0x48, 0x5f, 0x13,
-	0x2d, 0x3d, 0xad, 0xac, 0x9d, 0x6b, 0x38, 0x84, 0xee, 0x1f, 0xd4, 0x6d, 0x56, 0xf4, 0xbd, 0x1e,
-	0x40, 0x4f, 0x53, 0xb0, 0x74, 0x81, 0xc9, 0x37, 0x2a, 0x3f, 0xb8, 0x56, 0xc8, 0x3b, 0xd8, 0x2c,
-	0x39, 0x9e, 0x88, 0x08, 0xb8, 0xfa, 0x18, 0xb3, 0xee, 0x57, 0x33, 0x08, 0xc5, 0x5f, 0xc0, 0x6d,
-	0xbe, 0x93, 0x16, 0xb3, 0x7d, 0x6b, 0x69, 0x17, 0x5b, 0x37, 0x75, 0x94, 0x90, 0x7e, 0x09, 0x16,
-	0x87, 0xcb, 0x03, 0xfe, 0x34, 0x1d, 0xef, 0x60, 0x5b, 0x2d, 0x34, 0x55, 0x99, 0xf9, 0x66, 0x93,
-	0x39, 0xab, 0xd8, 0x93, 0x32, 0x67, 0xe5, 0xeb, 0x90, 0xe7, 0xac, 0x64, 0xa7, 0xc8, 0x9c, 0x55,
-	0x6f, 0x30, 0x99, 0xb3, 0xca, 0x75, 0xa4, 0x35, 0x8c, 0x36, 0xdf, 0xe7, 0x1a, 0x66, 0x79, 0xe7,
-	0xcc, 0x35, 0xcc, 0xd2, 0x5a, 0x58, 0x21, 0x6f, 0x81, 0x2c, 0x0f, 0x48, 0xb2, 0x73, 0xfd, 0xec,
-	0xb7, 0x7a, 0x95, 0xf4, 0xbc, 0x97, 0x4a, 0x47, 0x94, 0xec, 0xa5, 0xeb, 0x46, 0xa8, 0xec, 0xa5,
-	0xea, 0x09, 0x67, 0xaf, 0x9c, 0xae, 0xf1, 0xff, 0x17, 0x9f, 0xfd, 0x1b, 0x00, 0x00, 0xff, 0xff,
-	0xb2, 0x14, 0x00, 0x58, 0xd5, 0x10, 0x00, 0x00,
+func (m *PreflightRequest) Reset()         { *m = PreflightRequest{} }
+func (m *PreflightRequest) String() string { return proto.CompactTextString(m) }
+func (*PreflightRequest) ProtoMessage()    {}
+func (*PreflightRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{35}
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *PreflightRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreflightRequest.Unmarshal(m, b)
+}
+func (m *PreflightRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreflightRequest.Marshal(b, m, deterministic)
+}
+func (m *PreflightRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreflightRequest.Merge(m, src)
+}
+func (m *PreflightRequest) XXX_Size() int {
+	return xxx_messageInfo_PreflightRequest.Size(m)
+}
+func (m *PreflightRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreflightRequest.DiscardUnknown(m)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+var xxx_messageInfo_PreflightRequest proto.InternalMessageInfo
 
-// AgentClient is the client API for Agent service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AgentClient interface {
-	CheckDiskSpace(ctx context.Context, in *CheckSegmentDiskSpaceRequest, opts ...grpc.CallOption) (*CheckDiskSpaceReply, error)
-	UpgradePrimaries(ctx context.Context, in *UpgradePrimariesRequest, opts ...grpc.CallOption) (*UpgradePrimariesReply, error)
-	RenameDirectories(ctx context.Context, in *RenameDirectoriesRequest, opts ...grpc.CallOption) (*RenameDirectoriesReply, error)
-	StopAgent(ctx context.Context, in *StopAgentRequest, opts ...grpc.CallOption) (*StopAgentReply, error)
-	DeleteDataDirectories(ctx context.Context, in *DeleteDataDirectoriesRequest, opts ...grpc.CallOption) (*DeleteDataDirectoriesReply, error)
-	DeleteStateDirectory(ctx context.Context, in *DeleteStateDirectoryRequest, opts ...grpc.CallOption) (*DeleteStateDirectoryReply, error)
-	DeleteTablespaceDirectories(ctx context.Context, in *DeleteTablespaceRequest, opts ...grpc.CallOption) (*DeleteTablespaceReply, error)
-	ArchiveLogDirectory(ctx context.Context, in *ArchiveLogDirectoryRequest, opts ...grpc.CallOption) (*ArchiveLogDirectoryReply, error)
-	RsyncDataDirectories(ctx context.Context, in *RsyncRequest, opts ...grpc.CallOption) (*RsyncReply, error)
-	RsyncTablespaceDirectories(ctx context.Context, in *RsyncRequest, opts ...grpc.CallOption) (*RsyncReply, error)
-	RestorePrimariesPgControl(ctx context.Context, in *RestorePgControlRequest, opts ...grpc.CallOption) (*RestorePgControlReply, error)
-	UpdateConfiguration(ctx context.Context, in *UpdateConfigurationRequest, opts ...grpc.CallOption) (*UpdateConfigurationReply, error)
-	RenameTablespaces(ctx context.Context, in *RenameTablespacesRequest, opts ...grpc.CallOption) (*RenameTablespacesReply, error)
-	CreateRecoveryConf(ctx context.Context, in *CreateRecoveryConfRequest, opts ...grpc.CallOption) (*CreateRecoveryConfReply, error)
-	AddReplicationEntries(ctx context.Context, in *AddReplicationEntriesRequest, opts ...grpc.CallOption) (*AddReplicationEntriesReply, error)
+func (m *PreflightRequest) GetTargetGPHome() string {
+	if m != nil {
+		return m.TargetGPHome
+	}
+	return ""
 }
 
-type agentClient struct {
-	cc *grpc.ClientConn
+func (m *PreflightRequest) GetTargetVersion() string {
+	if m != nil {
+		return m.TargetVersion
+	}
+	return ""
 }
 
-func NewAgentClient(cc *grpc.ClientConn) AgentClient {
-	return &agentClient{cc}
+func (m *PreflightRequest) GetGpupgradeVersion() string {
+	if m != nil {
+		return m.GpupgradeVersion
+	}
+	return ""
+}
+
+func (m *PreflightRequest) GetPorts() []uint32 {
+	if m != nil {
+		return m.Ports
+	}
+	return nil
+}
+
+func (m *PreflightRequest) GetTools() []string {
+	if m != nil {
+		return m.Tools
+	}
+	return nil
+}
+
+func (m *PreflightRequest) GetLocales() []string {
+	if m != nil {
+		return m.Locales
+	}
+	return nil
+}
+
+func (m *PreflightRequest) GetDataDirParents() []string {
+	if m != nil {
+		return m.DataDirParents
+	}
+	return nil
+}
+
+func (m *PreflightRequest) GetMinOpenFiles() uint64 {
+	if m != nil {
+		return m.MinOpenFiles
+	}
+	return 0
+}
+
+func (m *PreflightRequest) GetMinProcesses() uint64 {
+	if m != nil {
+		return m.MinProcesses
+	}
+	return 0
+}
+
+func (m *PreflightRequest) GetSnapshotProvider() string {
+	if m != nil {
+		return m.SnapshotProvider
+	}
+	return ""
+}
+
+func (m *PreflightRequest) GetSnapshotDirs() []string {
+	if m != nil {
+		return m.SnapshotDirs
+	}
+	return nil
+}
+
+type PreflightReply struct {
+	Checks               []*PreflightReply_Check `protobuf:"bytes,1,rep,name=checks,proto3" json:"checks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *PreflightReply) Reset()         { *m = PreflightReply{} }
+func (m *PreflightReply) String() string { return proto.CompactTextString(m) }
+func (*PreflightReply) ProtoMessage()    {}
+func (*PreflightReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{36}
+}
+
+func (m *PreflightReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreflightReply.Unmarshal(m, b)
+}
+func (m *PreflightReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreflightReply.Marshal(b, m, deterministic)
+}
+func (m *PreflightReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreflightReply.Merge(m, src)
+}
+func (m *PreflightReply) XXX_Size() int {
+	return xxx_messageInfo_PreflightReply.Size(m)
+}
+func (m *PreflightReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreflightReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreflightReply proto.InternalMessageInfo
+
+func (m *PreflightReply) GetChecks() []*PreflightReply_Check {
+	if m != nil {
+		return m.Checks
+	}
+	return nil
+}
+
+type PreflightReply_Check struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Passed               bool     `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`
+	Message              string   `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	Warning              bool     `protobuf:"varint,4,opt,name=warning,proto3" json:"warning,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PreflightReply_Check) Reset()         { *m = PreflightReply_Check{} }
+func (m *PreflightReply_Check) String() string { return proto.CompactTextString(m) }
+func (*PreflightReply_Check) ProtoMessage()    {}
+func (*PreflightReply_Check) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{36, 0}
+}
+
+func (m *PreflightReply_Check) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PreflightReply_Check.Unmarshal(m, b)
+}
+func (m *PreflightReply_Check) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PreflightReply_Check.Marshal(b, m, deterministic)
+}
+func (m *PreflightReply_Check) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PreflightReply_Check.Merge(m, src)
+}
+func (m *PreflightReply_Check) XXX_Size() int {
+	return xxx_messageInfo_PreflightReply_Check.Size(m)
+}
+func (m *PreflightReply_Check) XXX_DiscardUnknown() {
+	xxx_messageInfo_PreflightReply_Check.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PreflightReply_Check proto.InternalMessageInfo
+
+func (m *PreflightReply_Check) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PreflightReply_Check) GetPassed() bool {
+	if m != nil {
+		return m.Passed
+	}
+	return false
+}
+
+func (m *PreflightReply_Check) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *PreflightReply_Check) GetWarning() bool {
+	if m != nil {
+		return m.Warning
+	}
+	return false
+}
+
+type CreateSnapshotsRequest struct {
+	Provider             string   `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Dirs                 []string `protobuf:"bytes,3,rep,name=dirs,proto3" json:"dirs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSnapshotsRequest) Reset()         { *m = CreateSnapshotsRequest{} }
+func (m *CreateSnapshotsRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSnapshotsRequest) ProtoMessage()    {}
+func (*CreateSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{37}
+}
+
+func (m *CreateSnapshotsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSnapshotsRequest.Unmarshal(m, b)
+}
+func (m *CreateSnapshotsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSnapshotsRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateSnapshotsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSnapshotsRequest.Merge(m, src)
+}
+func (m *CreateSnapshotsRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateSnapshotsRequest.Size(m)
+}
+func (m *CreateSnapshotsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSnapshotsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSnapshotsRequest proto.InternalMessageInfo
+
+func (m *CreateSnapshotsRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *CreateSnapshotsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateSnapshotsRequest) GetDirs() []string {
+	if m != nil {
+		return m.Dirs
+	}
+	return nil
+}
+
+type CreateSnapshotsReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSnapshotsReply) Reset()         { *m = CreateSnapshotsReply{} }
+func (m *CreateSnapshotsReply) String() string { return proto.CompactTextString(m) }
+func (*CreateSnapshotsReply) ProtoMessage()    {}
+func (*CreateSnapshotsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{38}
+}
+
+func (m *CreateSnapshotsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSnapshotsReply.Unmarshal(m, b)
+}
+func (m *CreateSnapshotsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSnapshotsReply.Marshal(b, m, deterministic)
+}
+func (m *CreateSnapshotsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSnapshotsReply.Merge(m, src)
+}
+func (m *CreateSnapshotsReply) XXX_Size() int {
+	return xxx_messageInfo_CreateSnapshotsReply.Size(m)
+}
+func (m *CreateSnapshotsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSnapshotsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSnapshotsReply proto.InternalMessageInfo
+
+type RestoreSnapshotsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreSnapshotsRequest) Reset()         { *m = RestoreSnapshotsRequest{} }
+func (m *RestoreSnapshotsRequest) String() string { return proto.CompactTextString(m) }
+func (*RestoreSnapshotsRequest) ProtoMessage()    {}
+func (*RestoreSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{39}
+}
+
+func (m *RestoreSnapshotsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreSnapshotsRequest.Unmarshal(m, b)
+}
+func (m *RestoreSnapshotsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreSnapshotsRequest.Marshal(b, m, deterministic)
+}
+func (m *RestoreSnapshotsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreSnapshotsRequest.Merge(m, src)
+}
+func (m *RestoreSnapshotsRequest) XXX_Size() int {
+	return xxx_messageInfo_RestoreSnapshotsRequest.Size(m)
+}
+func (m *RestoreSnapshotsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreSnapshotsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreSnapshotsRequest proto.InternalMessageInfo
+
+type RestoreSnapshotsReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RestoreSnapshotsReply) Reset()         { *m = RestoreSnapshotsReply{} }
+func (m *RestoreSnapshotsReply) String() string { return proto.CompactTextString(m) }
+func (*RestoreSnapshotsReply) ProtoMessage()    {}
+func (*RestoreSnapshotsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{40}
+}
+
+func (m *RestoreSnapshotsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RestoreSnapshotsReply.Unmarshal(m, b)
+}
+func (m *RestoreSnapshotsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RestoreSnapshotsReply.Marshal(b, m, deterministic)
+}
+func (m *RestoreSnapshotsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RestoreSnapshotsReply.Merge(m, src)
+}
+func (m *RestoreSnapshotsReply) XXX_Size() int {
+	return xxx_messageInfo_RestoreSnapshotsReply.Size(m)
+}
+func (m *RestoreSnapshotsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_RestoreSnapshotsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RestoreSnapshotsReply proto.InternalMessageInfo
+
+type DeleteSnapshotsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSnapshotsRequest) Reset()         { *m = DeleteSnapshotsRequest{} }
+func (m *DeleteSnapshotsRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteSnapshotsRequest) ProtoMessage()    {}
+func (*DeleteSnapshotsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{41}
+}
+
+func (m *DeleteSnapshotsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSnapshotsRequest.Unmarshal(m, b)
+}
+func (m *DeleteSnapshotsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSnapshotsRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteSnapshotsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSnapshotsRequest.Merge(m, src)
+}
+func (m *DeleteSnapshotsRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteSnapshotsRequest.Size(m)
+}
+func (m *DeleteSnapshotsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSnapshotsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSnapshotsRequest proto.InternalMessageInfo
+
+type DeleteSnapshotsReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteSnapshotsReply) Reset()         { *m = DeleteSnapshotsReply{} }
+func (m *DeleteSnapshotsReply) String() string { return proto.CompactTextString(m) }
+func (*DeleteSnapshotsReply) ProtoMessage()    {}
+func (*DeleteSnapshotsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{42}
+}
+
+func (m *DeleteSnapshotsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteSnapshotsReply.Unmarshal(m, b)
+}
+func (m *DeleteSnapshotsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteSnapshotsReply.Marshal(b, m, deterministic)
+}
+func (m *DeleteSnapshotsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteSnapshotsReply.Merge(m, src)
+}
+func (m *DeleteSnapshotsReply) XXX_Size() int {
+	return xxx_messageInfo_DeleteSnapshotsReply.Size(m)
+}
+func (m *DeleteSnapshotsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteSnapshotsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteSnapshotsReply proto.InternalMessageInfo
+
+type GetPgUpgradeLogsRequest struct {
+	Role                 string   `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	ContentID            int32    `protobuf:"varint,2,opt,name=contentID,proto3" json:"contentID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetPgUpgradeLogsRequest) Reset()         { *m = GetPgUpgradeLogsRequest{} }
+func (m *GetPgUpgradeLogsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetPgUpgradeLogsRequest) ProtoMessage()    {}
+func (*GetPgUpgradeLogsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{43}
+}
+
+func (m *GetPgUpgradeLogsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPgUpgradeLogsRequest.Unmarshal(m, b)
+}
+func (m *GetPgUpgradeLogsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPgUpgradeLogsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetPgUpgradeLogsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPgUpgradeLogsRequest.Merge(m, src)
+}
+func (m *GetPgUpgradeLogsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetPgUpgradeLogsRequest.Size(m)
+}
+func (m *GetPgUpgradeLogsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPgUpgradeLogsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPgUpgradeLogsRequest proto.InternalMessageInfo
+
+func (m *GetPgUpgradeLogsRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *GetPgUpgradeLogsRequest) GetContentID() int32 {
+	if m != nil {
+		return m.ContentID
+	}
+	return 0
+}
+
+// PgUpgradeLog is a log file relative to the pg_upgrade directory of a
+// segment.
+type PgUpgradeLog struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Contents             []byte   `protobuf:"bytes,2,opt,name=contents,proto3" json:"contents,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PgUpgradeLog) Reset()         { *m = PgUpgradeLog{} }
+func (m *PgUpgradeLog) String() string { return proto.CompactTextString(m) }
+func (*PgUpgradeLog) ProtoMessage()    {}
+func (*PgUpgradeLog) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{44}
+}
+
+func (m *PgUpgradeLog) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PgUpgradeLog.Unmarshal(m, b)
+}
+func (m *PgUpgradeLog) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PgUpgradeLog.Marshal(b, m, deterministic)
+}
+func (m *PgUpgradeLog) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PgUpgradeLog.Merge(m, src)
+}
+func (m *PgUpgradeLog) XXX_Size() int {
+	return xxx_messageInfo_PgUpgradeLog.Size(m)
+}
+func (m *PgUpgradeLog) XXX_DiscardUnknown() {
+	xxx_messageInfo_PgUpgradeLog.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PgUpgradeLog proto.InternalMessageInfo
+
+func (m *PgUpgradeLog) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PgUpgradeLog) GetContents() []byte {
+	if m != nil {
+		return m.Contents
+	}
+	return nil
+}
+
+type GetPgUpgradeLogsReply struct {
+	Logs                 []*PgUpgradeLog `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GetPgUpgradeLogsReply) Reset()         { *m = GetPgUpgradeLogsReply{} }
+func (m *GetPgUpgradeLogsReply) String() string { return proto.CompactTextString(m) }
+func (*GetPgUpgradeLogsReply) ProtoMessage()    {}
+func (*GetPgUpgradeLogsReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{45}
+}
+
+func (m *GetPgUpgradeLogsReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetPgUpgradeLogsReply.Unmarshal(m, b)
+}
+func (m *GetPgUpgradeLogsReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetPgUpgradeLogsReply.Marshal(b, m, deterministic)
+}
+func (m *GetPgUpgradeLogsReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetPgUpgradeLogsReply.Merge(m, src)
+}
+func (m *GetPgUpgradeLogsReply) XXX_Size() int {
+	return xxx_messageInfo_GetPgUpgradeLogsReply.Size(m)
+}
+func (m *GetPgUpgradeLogsReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetPgUpgradeLogsReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetPgUpgradeLogsReply proto.InternalMessageInfo
+
+func (m *GetPgUpgradeLogsReply) GetLogs() []*PgUpgradeLog {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+type CreateSupportBundleRequest struct {
+	DataDirs        []string `protobuf:"bytes,1,rep,name=dataDirs,proto3" json:"dataDirs,omitempty"`
+	Gphomes         []string `protobuf:"bytes,2,rep,name=gphomes,proto3" json:"gphomes,omitempty"`
+	RedactPasswords bool     `protobuf:"varint,3,opt,name=redactPasswords,proto3" json:"redactPasswords,omitempty"`
+	// hostnameAliases replaces each hostname when redacting hostnames.
+	HostnameAliases      map[string]string `protobuf:"bytes,4,rep,name=hostnameAliases,proto3" json:"hostnameAliases,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *CreateSupportBundleRequest) Reset()         { *m = CreateSupportBundleRequest{} }
+func (m *CreateSupportBundleRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateSupportBundleRequest) ProtoMessage()    {}
+func (*CreateSupportBundleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{46}
+}
+
+func (m *CreateSupportBundleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSupportBundleRequest.Unmarshal(m, b)
+}
+func (m *CreateSupportBundleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSupportBundleRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateSupportBundleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSupportBundleRequest.Merge(m, src)
+}
+func (m *CreateSupportBundleRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateSupportBundleRequest.Size(m)
+}
+func (m *CreateSupportBundleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSupportBundleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSupportBundleRequest proto.InternalMessageInfo
+
+func (m *CreateSupportBundleRequest) GetDataDirs() []string {
+	if m != nil {
+		return m.DataDirs
+	}
+	return nil
+}
+
+func (m *CreateSupportBundleRequest) GetGphomes() []string {
+	if m != nil {
+		return m.Gphomes
+	}
+	return nil
+}
+
+func (m *CreateSupportBundleRequest) GetRedactPasswords() bool {
+	if m != nil {
+		return m.RedactPasswords
+	}
+	return false
+}
+
+func (m *CreateSupportBundleRequest) GetHostnameAliases() map[string]string {
+	if m != nil {
+		return m.HostnameAliases
+	}
+	return nil
+}
+
+type CreateSupportBundleReply struct {
+	// path is the host bundle on the agent host.
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateSupportBundleReply) Reset()         { *m = CreateSupportBundleReply{} }
+func (m *CreateSupportBundleReply) String() string { return proto.CompactTextString(m) }
+func (*CreateSupportBundleReply) ProtoMessage()    {}
+func (*CreateSupportBundleReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_9e73bb06acc917d8, []int{47}
+}
+
+func (m *CreateSupportBundleReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateSupportBundleReply.Unmarshal(m, b)
+}
+func (m *CreateSupportBundleReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateSupportBundleReply.Marshal(b, m, deterministic)
+}
+func (m *CreateSupportBundleReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateSupportBundleReply.Merge(m, src)
+}
+func (m *CreateSupportBundleReply) XXX_Size() int {
+	return xxx_messageInfo_CreateSupportBundleReply.Size(m)
+}
+func (m *CreateSupportBundleReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateSupportBundleReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateSupportBundleReply proto.InternalMessageInfo
+
+func (m *CreateSupportBundleReply) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("idl.Mode", Mode_name, Mode_value)
+	proto.RegisterEnum("idl.PgOptions_Mode", PgOptions_Mode_name, PgOptions_Mode_value)
+	proto.RegisterEnum("idl.PgOptions_Action", PgOptions_Action_name, PgOptions_Action_value)
+	proto.RegisterType((*PgOptions)(nil), "idl.PgOptions")
+	proto.RegisterMapType((map[int32]*TablespaceInfo)(nil), "idl.PgOptions.TablespacesEntry")
+	proto.RegisterType((*TablespaceInfo)(nil), "idl.TablespaceInfo")
+	proto.RegisterType((*UpgradePrimariesRequest)(nil), "idl.UpgradePrimariesRequest")
+	proto.RegisterType((*UpgradePrimariesReply)(nil), "idl.UpgradePrimariesReply")
+	proto.RegisterType((*DeleteDataDirectoriesRequest)(nil), "idl.DeleteDataDirectoriesRequest")
+	proto.RegisterType((*DeleteDataDirectoriesReply)(nil), "idl.DeleteDataDirectoriesReply")
+	proto.RegisterType((*DeleteStateDirectoryRequest)(nil), "idl.DeleteStateDirectoryRequest")
+	proto.RegisterType((*DeleteStateDirectoryReply)(nil), "idl.DeleteStateDirectoryReply")
+	proto.RegisterType((*DeleteTablespaceRequest)(nil), "idl.DeleteTablespaceRequest")
+	proto.RegisterType((*DeleteTablespaceReply)(nil), "idl.DeleteTablespaceReply")
+	proto.RegisterType((*ArchiveLogDirectoryRequest)(nil), "idl.ArchiveLogDirectoryRequest")
+	proto.RegisterType((*ArchiveLogDirectoryReply)(nil), "idl.ArchiveLogDirectoryReply")
+	proto.RegisterType((*RenameDirectories)(nil), "idl.RenameDirectories")
+	proto.RegisterType((*RenameDirectoriesRequest)(nil), "idl.RenameDirectoriesRequest")
+	proto.RegisterType((*RenameDirectoriesReply)(nil), "idl.RenameDirectoriesReply")
+	proto.RegisterType((*StopAgentRequest)(nil), "idl.StopAgentRequest")
+	proto.RegisterType((*StopAgentReply)(nil), "idl.StopAgentReply")
+	proto.RegisterType((*CheckSegmentDiskSpaceRequest)(nil), "idl.CheckSegmentDiskSpaceRequest")
+	proto.RegisterType((*DiskSpaceEstimate)(nil), "idl.DiskSpaceEstimate")
+	proto.RegisterType((*CheckDiskSpaceReply)(nil), "idl.CheckDiskSpaceReply")
+	proto.RegisterType((*CheckDiskSpaceReply_DiskUsage)(nil), "idl.CheckDiskSpaceReply.DiskUsage")
+	proto.RegisterType((*RsyncRequest)(nil), "idl.RsyncRequest")
+	proto.RegisterType((*RsyncRequest_RsyncOptions)(nil), "idl.RsyncRequest.RsyncOptions")
+	proto.RegisterType((*RsyncReply)(nil), "idl.RsyncReply")
+	proto.RegisterType((*ResyncStats)(nil), "idl.ResyncStats")
+	proto.RegisterType((*ResyncReply)(nil), "idl.ResyncReply")
+	proto.RegisterType((*RestorePgControlRequest)(nil), "idl.RestorePgControlRequest")
+	proto.RegisterType((*RestorePgControlReply)(nil), "idl.RestorePgControlReply")
+	proto.RegisterType((*UpdateFileConfOptions)(nil), "idl.UpdateFileConfOptions")
+	proto.RegisterType((*UpdateConfigurationRequest)(nil), "idl.UpdateConfigurationRequest")
+	proto.RegisterType((*UpdateConfigurationReply)(nil), "idl.UpdateConfigurationReply")
+	proto.RegisterType((*RenameTablespacesRequest)(nil), "idl.RenameTablespacesRequest")
+	proto.RegisterType((*RenameTablespacesRequest_RenamePair)(nil), "idl.RenameTablespacesRequest.RenamePair")
+	proto.RegisterType((*RenameTablespacesReply)(nil), "idl.RenameTablespacesReply")
+	proto.RegisterType((*CreateRecoveryConfRequest)(nil), "idl.CreateRecoveryConfRequest")
+	proto.RegisterType((*CreateRecoveryConfRequest_Connection)(nil), "idl.CreateRecoveryConfRequest.Connection")
+	proto.RegisterType((*CreateRecoveryConfReply)(nil), "idl.CreateRecoveryConfReply")
+	proto.RegisterType((*AddReplicationEntriesRequest)(nil), "idl.AddReplicationEntriesRequest")
+	proto.RegisterType((*AddReplicationEntriesRequest_Entry)(nil), "idl.AddReplicationEntriesRequest.Entry")
+	proto.RegisterType((*AddReplicationEntriesReply)(nil), "idl.AddReplicationEntriesReply")
+	proto.RegisterType((*PreflightRequest)(nil), "idl.PreflightRequest")
+	proto.RegisterType((*PreflightReply)(nil), "idl.PreflightReply")
+	proto.RegisterType((*PreflightReply_Check)(nil), "idl.PreflightReply.Check")
+	proto.RegisterType((*CreateSnapshotsRequest)(nil), "idl.CreateSnapshotsRequest")
+	proto.RegisterType((*CreateSnapshotsReply)(nil), "idl.CreateSnapshotsReply")
+	proto.RegisterType((*RestoreSnapshotsRequest)(nil), "idl.RestoreSnapshotsRequest")
+	proto.RegisterType((*RestoreSnapshotsReply)(nil), "idl.RestoreSnapshotsReply")
+	proto.RegisterType((*DeleteSnapshotsRequest)(nil), "idl.DeleteSnapshotsRequest")
+	proto.RegisterType((*DeleteSnapshotsReply)(nil), "idl.DeleteSnapshotsReply")
+	proto.RegisterType((*GetPgUpgradeLogsRequest)(nil), "idl.GetPgUpgradeLogsRequest")
+	proto.RegisterType((*PgUpgradeLog)(nil), "idl.PgUpgradeLog")
+	proto.RegisterType((*GetPgUpgradeLogsReply)(nil), "idl.GetPgUpgradeLogsReply")
+	proto.RegisterType((*CreateSupportBundleRequest)(nil), "idl.CreateSupportBundleRequest")
+	proto.RegisterMapType((map[string]string)(nil), "idl.CreateSupportBundleRequest.HostnameAliasesEntry")
+	proto.RegisterType((*CreateSupportBundleReply)(nil), "idl.CreateSupportBundleReply")
+}
+
+func init() { proto.RegisterFile("hub_to_agent.proto", fileDescriptor_9e73bb06acc917d8) }
+
+var fileDescriptor_9e73bb06acc917d8 = []byte{
+	// 2294 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x19, 0x5d, 0x6f, 0xdc, 0x58,
+	0xb5, 0x9e, 0xcc, 0x24, 0x99, 0x33, 0xe9, 0x74, 0x7a, 0x9b, 0x26, 0x8e, 0x93, 0xb6, 0xc1, 0x5a,
+	0x76, 0xb3, 0x45, 0x1b, 0x89, 0x92, 0x8a, 0xb2, 0x42, 0x2b, 0x92, 0x4c, 0xbb, 0x2d, 0x6d, 0x93,
+	0xc1, 0x69, 0x58, 0x81, 0x80, 0xca, 0xb5, 0x6f, 0x26, 0x56, 0x3d, 0xb6, 0xf7, 0xfa, 0x4e, 0xbb,
+	0xf3, 0x17, 0x78, 0xe0, 0x1f, 0x20, 0xf1, 0x8a, 0x84, 0x78, 0x40, 0x88, 0x5f, 0xc1, 0x9f, 0xe1,
+	0x81, 0x37, 0x24, 0xd0, 0xb9, 0x1f, 0xf6, 0x1d, 0x8f, 0x1d, 0xba, 0xd2, 0xbe, 0xf9, 0x9e, 0xaf,
+	0x7b, 0xbe, 0xee, 0xb9, 0xe7, 0x5c, 0x03, 0xb9, 0x9c, 0xbe, 0x79, 0xcd, 0xd3, 0xd7, 0xfe, 0x98,
+	0x26, 0x7c, 0x3f, 0x63, 0x29, 0x4f, 0xc9, 0x52, 0x14, 0xc6, 0xee, 0xbf, 0x3a, 0xd0, 0x1d, 0x8d,
+	0x4f, 0x33, 0x1e, 0xa5, 0x49, 0x4e, 0x3e, 0x83, 0x65, 0x3f, 0xc0, 0x4f, 0xdb, 0xda, 0xb5, 0xf6,
+	0xfa, 0x0f, 0x6e, 0xef, 0x47, 0x61, 0xbc, 0x5f, 0xe0, 0xf7, 0x0f, 0x05, 0xd2, 0x53, 0x44, 0x84,
+	0x40, 0xdb, 0x4b, 0x63, 0x6a, 0xb7, 0x76, 0xad, 0xbd, 0xae, 0x27, 0xbe, 0xc9, 0x0e, 0x74, 0x8f,
+	0xd3, 0x84, 0xd3, 0x84, 0x3f, 0x1b, 0xda, 0x4b, 0xbb, 0xd6, 0x5e, 0xc7, 0x2b, 0x01, 0xe4, 0x13,
+	0x68, 0x4f, 0xd2, 0x90, 0xda, 0x6d, 0x21, 0xfe, 0x56, 0x45, 0xfc, 0xcb, 0x34, 0xa4, 0x9e, 0x20,
+	0x20, 0x77, 0x01, 0x4e, 0xe3, 0x50, 0x21, 0xec, 0x8e, 0xd8, 0xc0, 0x80, 0x90, 0x1f, 0x40, 0xef,
+	0x3c, 0x1b, 0x33, 0x3f, 0xa4, 0xc8, 0x64, 0xdf, 0x14, 0xf2, 0xba, 0x42, 0x9e, 0x90, 0x62, 0x62,
+	0xc9, 0x47, 0x70, 0xfd, 0x95, 0xcf, 0xc6, 0x94, 0xff, 0x92, 0xb2, 0x1c, 0xad, 0x5b, 0x11, 0xf2,
+	0xe6, 0x81, 0xa8, 0xf9, 0x69, 0x1c, 0x1e, 0x45, 0xc9, 0x30, 0x62, 0xf6, 0xaa, 0xa0, 0x28, 0x01,
+	0x4a, 0xa1, 0xa1, 0xcf, 0x7d, 0x44, 0x77, 0x0b, 0x85, 0x14, 0x84, 0xd8, 0xb0, 0x72, 0x1a, 0x87,
+	0xa3, 0x94, 0x71, 0x1b, 0x04, 0x52, 0x2f, 0x15, 0x66, 0x78, 0xf4, 0x6c, 0x68, 0xf7, 0x0a, 0x0c,
+	0x2e, 0x71, 0xc7, 0x13, 0xfa, 0x5e, 0xed, 0xb8, 0x26, 0x77, 0x2c, 0x00, 0xb8, 0xe3, 0x09, 0x7d,
+	0xaf, 0x77, 0xbc, 0x2e, 0x77, 0x2c, 0x21, 0x28, 0xf7, 0x84, 0xbe, 0x17, 0x3b, 0xf6, 0xa5, 0x5c,
+	0xb5, 0x54, 0x18, 0xb1, 0xe3, 0x8d, 0x02, 0x23, 0x76, 0x3c, 0x84, 0xde, 0x2b, 0xff, 0x4d, 0x4c,
+	0xf3, 0xcc, 0x0f, 0x68, 0x6e, 0x0f, 0x76, 0x97, 0xf6, 0x7a, 0x0f, 0xee, 0x55, 0xc2, 0x60, 0x50,
+	0x3c, 0x4e, 0x38, 0x9b, 0x79, 0x26, 0x8f, 0x73, 0x06, 0x83, 0x2a, 0x01, 0x19, 0xc0, 0xd2, 0x5b,
+	0x3a, 0x13, 0x49, 0xd3, 0xf1, 0xf0, 0x93, 0x7c, 0x0a, 0x9d, 0x77, 0x7e, 0x3c, 0x95, 0xb9, 0xd1,
+	0x53, 0x91, 0x2e, 0xf9, 0x9e, 0x25, 0x17, 0xa9, 0x27, 0x29, 0x3e, 0x6f, 0x3d, 0xb2, 0xdc, 0x87,
+	0xd0, 0x16, 0x91, 0x1a, 0xc0, 0xda, 0xf9, 0xc9, 0xf3, 0x93, 0xd3, 0xaf, 0x4e, 0x5e, 0xe3, 0x7a,
+	0x70, 0x8d, 0xf4, 0x01, 0x86, 0x51, 0x9e, 0xf9, 0x3c, 0xb8, 0xa4, 0x6c, 0x60, 0x91, 0x1e, 0xac,
+	0x9c, 0xd1, 0xf1, 0x84, 0x26, 0x7c, 0xd0, 0x72, 0x0f, 0x60, 0xf9, 0x50, 0xa7, 0x62, 0x5f, 0x33,
+	0x4a, 0xc8, 0xe0, 0x1a, 0x92, 0x4e, 0x65, 0x16, 0x0c, 0x2c, 0xd2, 0x85, 0x4e, 0x70, 0x49, 0x83,
+	0xb7, 0x83, 0x96, 0xfb, 0x06, 0xfa, 0xf3, 0x9a, 0x60, 0x22, 0x9f, 0xf8, 0x13, 0x2a, 0xf2, 0xb5,
+	0xeb, 0x89, 0x6f, 0xe2, 0xc0, 0xea, 0x8b, 0x34, 0xf0, 0xc5, 0x69, 0x68, 0x0b, 0x78, 0xb1, 0x26,
+	0xbb, 0xd0, 0x3b, 0xcf, 0x29, 0x1b, 0xd2, 0x8b, 0x28, 0xa1, 0xa1, 0x48, 0xcf, 0x55, 0xcf, 0x04,
+	0xb9, 0x31, 0x6c, 0xaa, 0x0c, 0x1c, 0xb1, 0x68, 0xe2, 0xb3, 0x88, 0xe6, 0x1e, 0xfd, 0x7a, 0x4a,
+	0x73, 0xfe, 0x6d, 0x0f, 0x99, 0x0b, 0xed, 0x34, 0xe3, 0xb9, 0xdd, 0x12, 0xb1, 0xea, 0xcf, 0x13,
+	0x7b, 0x02, 0xe7, 0x6e, 0xc2, 0xed, 0xc5, 0xdd, 0xb2, 0x78, 0xe6, 0x7e, 0x0e, 0x3b, 0x43, 0x1a,
+	0x53, 0x4e, 0x55, 0xd2, 0xd0, 0x80, 0xa7, 0xa6, 0x2e, 0x0e, 0xac, 0x86, 0x3e, 0xf7, 0xc3, 0x88,
+	0xe5, 0xb6, 0xb5, 0xbb, 0x84, 0x46, 0xea, 0xb5, 0xbb, 0x03, 0x4e, 0x03, 0x2f, 0x4a, 0xbe, 0x03,
+	0xdb, 0x12, 0x7b, 0xc6, 0x7d, 0x4e, 0x35, 0x7a, 0xa6, 0x04, 0xbb, 0xdb, 0xb0, 0x55, 0x8f, 0x46,
+	0xde, 0xcf, 0x60, 0x53, 0x22, 0xcb, 0x30, 0x68, 0x85, 0x08, 0xb4, 0x0d, 0x65, 0xc4, 0x37, 0x5a,
+	0xb7, 0x48, 0x8e, 0x72, 0x0e, 0xc0, 0x39, 0x64, 0xc1, 0x65, 0xf4, 0x8e, 0xbe, 0x48, 0xc7, 0x55,
+	0x15, 0xc8, 0x06, 0x2c, 0x63, 0xda, 0x47, 0x4c, 0xf8, 0xb9, 0xeb, 0xa9, 0x95, 0xeb, 0x80, 0x5d,
+	0xcb, 0x85, 0x12, 0x8f, 0xe1, 0xa6, 0x47, 0x13, 0x7f, 0x42, 0x0d, 0x7b, 0x51, 0xd0, 0x59, 0x3a,
+	0x65, 0x01, 0xd5, 0x82, 0xe4, 0x0a, 0xe1, 0xb2, 0x82, 0xa8, 0x02, 0xa8, 0x56, 0xee, 0x13, 0xb0,
+	0x17, 0x84, 0x68, 0xa5, 0xee, 0x43, 0x7b, 0xa8, 0xed, 0xeb, 0x3d, 0xd8, 0x10, 0xd1, 0x5c, 0x24,
+	0x16, 0x34, 0xae, 0x0d, 0x1b, 0x8b, 0x28, 0xa1, 0x26, 0x81, 0xc1, 0x19, 0x4f, 0xb3, 0x43, 0xac,
+	0xe6, 0xda, 0xe3, 0x03, 0xe8, 0x1b, 0x30, 0xa4, 0xfa, 0x9b, 0x05, 0x3b, 0xc7, 0x98, 0xf3, 0xea,
+	0xc0, 0x0c, 0xa3, 0xfc, 0xed, 0x99, 0xe9, 0xec, 0x8f, 0xe0, 0x7a, 0x18, 0xe5, 0x6f, 0x9f, 0x30,
+	0x4a, 0x3d, 0x4c, 0x6c, 0x61, 0x9f, 0xe5, 0xcd, 0x03, 0x8b, 0x90, 0xb4, 0xca, 0x90, 0x90, 0x03,
+	0xe8, 0xd2, 0x9c, 0x47, 0x13, 0x9f, 0xd3, 0xdc, 0x5e, 0x32, 0x6c, 0x29, 0xf6, 0x78, 0xac, 0xd0,
+	0x5e, 0x49, 0x48, 0x5c, 0x58, 0xcb, 0xfd, 0x0b, 0xca, 0x67, 0x2f, 0x7d, 0x36, 0x8e, 0xe4, 0xb1,
+	0xb2, 0xbc, 0x39, 0x98, 0xfb, 0x67, 0x0b, 0x6e, 0x2e, 0x08, 0xc1, 0x03, 0x17, 0xd2, 0x3c, 0x60,
+	0x51, 0x56, 0x1c, 0x9c, 0xae, 0x67, 0x82, 0x14, 0x05, 0x8f, 0x12, 0x79, 0x62, 0x5b, 0x05, 0x85,
+	0x06, 0x61, 0x55, 0xcc, 0x45, 0xe0, 0xa4, 0xc6, 0x5d, 0x4f, 0x2f, 0x31, 0x90, 0x17, 0x3e, 0x3a,
+	0x58, 0x69, 0xa4, 0x56, 0x58, 0x81, 0xe9, 0x37, 0x9c, 0xf9, 0x47, 0x33, 0x34, 0x13, 0x4f, 0x79,
+	0xdb, 0x33, 0x20, 0xee, 0x7f, 0x2c, 0xb8, 0x25, 0x1c, 0x6c, 0x78, 0x36, 0x8b, 0x67, 0xe4, 0x11,
+	0x74, 0xa6, 0xb9, 0x3f, 0xa6, 0x2a, 0xca, 0xae, 0xf0, 0x4c, 0x0d, 0xa1, 0xf0, 0xd6, 0x39, 0x52,
+	0x7a, 0x92, 0x81, 0xfc, 0xac, 0xf4, 0x6b, 0x68, 0xb7, 0x3e, 0x98, 0xbb, 0x64, 0x72, 0x22, 0xe8,
+	0x16, 0x70, 0xd2, 0x87, 0xd6, 0x45, 0xae, 0xbc, 0xd5, 0xba, 0xc8, 0x31, 0x94, 0x97, 0x69, 0xae,
+	0xf3, 0x55, 0x7c, 0xe3, 0x25, 0xe4, 0xbf, 0xf3, 0xa3, 0x18, 0xcf, 0x96, 0x28, 0x80, 0x6d, 0xaf,
+	0x04, 0x60, 0x81, 0x60, 0xf4, 0xeb, 0x69, 0xc4, 0x68, 0x28, 0x9c, 0xd3, 0xf6, 0x8a, 0xb5, 0xfb,
+	0x5f, 0x0b, 0xd6, 0xbc, 0x7c, 0x96, 0x04, 0x3a, 0x9f, 0x1e, 0xc1, 0x4a, 0xaa, 0x6e, 0x6c, 0x69,
+	0xf9, 0x5d, 0x99, 0xdf, 0x06, 0x8d, 0x5c, 0xe8, 0xea, 0xa5, 0xc9, 0x9d, 0xbf, 0x6b, 0x51, 0x0a,
+	0x63, 0x06, 0xcb, 0x9a, 0x0f, 0xd6, 0x1e, 0xdc, 0x30, 0xa2, 0xfa, 0xb4, 0x34, 0xa7, 0x0a, 0xae,
+	0xa6, 0xc4, 0x52, 0x6d, 0x4a, 0x68, 0x85, 0xdb, 0x72, 0x17, 0xb5, 0xc4, 0xa3, 0x41, 0xbf, 0x09,
+	0xe2, 0x69, 0x48, 0xc3, 0x27, 0x51, 0x2c, 0xa2, 0x8f, 0xf8, 0x79, 0xa0, 0x7b, 0x00, 0xa0, 0x8c,
+	0xc3, 0xb0, 0x7f, 0x0c, 0x9d, 0x9c, 0xfb, 0x5c, 0x1b, 0x3f, 0x50, 0x87, 0x1b, 0x09, 0xb0, 0x0a,
+	0xe6, 0x9e, 0x44, 0xbb, 0x7f, 0xb4, 0xa0, 0x67, 0x80, 0xeb, 0x2c, 0xb2, 0x3e, 0xc8, 0xa2, 0x9a,
+	0x24, 0xbf, 0x0b, 0xc0, 0x53, 0xee, 0xc7, 0x32, 0x65, 0x65, 0x38, 0x0d, 0x08, 0x1e, 0xc1, 0x38,
+	0xe2, 0x94, 0x69, 0x0a, 0x19, 0xd3, 0x39, 0x98, 0xfb, 0x50, 0xab, 0xf7, 0xed, 0xcc, 0x7a, 0x08,
+	0x9b, 0x1e, 0xcd, 0x79, 0xca, 0xe8, 0x68, 0x8c, 0x1d, 0x1f, 0x4b, 0xe3, 0x0f, 0xb9, 0x66, 0x36,
+	0xe1, 0xf6, 0x22, 0x1b, 0x96, 0xaf, 0x31, 0x5e, 0x6a, 0xa1, 0xcf, 0x29, 0xfa, 0xfa, 0x38, 0x4d,
+	0x2e, 0x74, 0x6e, 0x10, 0x68, 0x67, 0x3e, 0xbf, 0x54, 0x4e, 0x12, 0xdf, 0x18, 0xc9, 0xcc, 0xe7,
+	0x9c, 0x32, 0xed, 0x15, 0xbd, 0x44, 0x9f, 0x31, 0x9a, 0xc5, 0x7e, 0x40, 0xb1, 0x06, 0xea, 0x2c,
+	0x30, 0x40, 0xae, 0x07, 0x8e, 0xdc, 0x08, 0x37, 0x89, 0xc6, 0x53, 0x26, 0x5c, 0xa9, 0x75, 0x3f,
+	0xa8, 0x26, 0xb5, 0x23, 0x1c, 0x50, 0xab, 0x5a, 0x91, 0x3f, 0x78, 0xc9, 0xd4, 0xca, 0x44, 0xc3,
+	0xfe, 0x6a, 0xe9, 0x0b, 0xc2, 0x68, 0xa4, 0xf4, 0x76, 0x3f, 0x47, 0x75, 0x11, 0x37, 0xf2, 0xcb,
+	0x7b, 0x62, 0xcf, 0xb8, 0x27, 0x16, 0x79, 0xf6, 0xbd, 0x82, 0xc1, 0x33, 0x99, 0x9d, 0x27, 0x00,
+	0x25, 0x0a, 0xab, 0x5c, 0x3e, 0x77, 0x8d, 0xc9, 0xd5, 0xff, 0x4f, 0xaa, 0xf2, 0x22, 0x9a, 0xdb,
+	0x1b, 0x4d, 0xf9, 0xb7, 0x05, 0x5b, 0xc7, 0x8c, 0x62, 0x9d, 0xa7, 0x41, 0xfa, 0x8e, 0xb2, 0x19,
+	0xda, 0xab, 0x6d, 0x79, 0x0e, 0xbd, 0x20, 0x4d, 0x12, 0x1a, 0x98, 0xee, 0xfb, 0x54, 0xd6, 0xb3,
+	0x26, 0xa6, 0xfd, 0xe3, 0x82, 0xc3, 0x33, 0xb9, 0x9d, 0xdf, 0x5b, 0x00, 0x25, 0x0e, 0x0f, 0xe8,
+	0x24, 0x62, 0x2c, 0x65, 0xba, 0x41, 0x96, 0x7a, 0xcf, 0x03, 0x31, 0x55, 0xa6, 0x39, 0xd5, 0x1d,
+	0x80, 0xf8, 0x46, 0x7b, 0x33, 0xd1, 0x25, 0xcd, 0xc4, 0x51, 0x53, 0x09, 0x61, 0x80, 0x0c, 0x0a,
+	0xd1, 0x5d, 0xb7, 0x45, 0x5b, 0x6b, 0x82, 0xdc, 0x2d, 0xd8, 0xac, 0xb3, 0x00, 0x5d, 0xf2, 0x0f,
+	0x0b, 0x76, 0x0e, 0xc3, 0x10, 0x17, 0x91, 0x6c, 0x17, 0xb1, 0x47, 0x36, 0x5a, 0x80, 0x43, 0x58,
+	0xa1, 0x12, 0xa2, 0x3c, 0xf2, 0x89, 0xf0, 0xc8, 0x55, 0x3c, 0xfb, 0xb2, 0x0f, 0xd7, 0x7c, 0xce,
+	0x19, 0x74, 0x04, 0x04, 0xd3, 0x5e, 0xdb, 0x2f, 0x4d, 0x5c, 0x31, 0x2c, 0xc7, 0x7e, 0x54, 0x97,
+	0x7a, 0xfc, 0xc6, 0x52, 0x8f, 0xf6, 0x1d, 0x86, 0x21, 0xd3, 0x77, 0x60, 0x09, 0xc0, 0x7e, 0xaf,
+	0x41, 0x07, 0x34, 0xeb, 0x0f, 0x4b, 0x30, 0x18, 0x31, 0x7a, 0x11, 0x47, 0xe3, 0x4b, 0xdd, 0x73,
+	0x60, 0x35, 0xe1, 0xa2, 0xe7, 0xf9, 0x72, 0xf4, 0x34, 0x9d, 0xe8, 0xc4, 0x9a, 0x83, 0x61, 0xa0,
+	0xf8, 0xdc, 0xf0, 0xa5, 0x02, 0x35, 0x07, 0x24, 0xf7, 0x61, 0x30, 0xce, 0x54, 0xb7, 0xae, 0x09,
+	0x65, 0x64, 0x16, 0xe0, 0x64, 0x1d, 0x3a, 0x59, 0xca, 0xb8, 0xac, 0xd9, 0xd7, 0x3d, 0xb9, 0x40,
+	0x28, 0x4f, 0xd3, 0x58, 0x57, 0x6a, 0xb9, 0x40, 0x07, 0xc5, 0x69, 0xe0, 0x63, 0x05, 0x5f, 0x96,
+	0x15, 0x5e, 0x2d, 0xc9, 0xc7, 0xd0, 0x0f, 0xa5, 0xaf, 0x46, 0x3e, 0xa3, 0x09, 0xcf, 0xed, 0x15,
+	0x41, 0x50, 0x81, 0xa2, 0x8d, 0x93, 0x28, 0x39, 0xcd, 0x68, 0x22, 0x2f, 0x82, 0x55, 0x59, 0x31,
+	0x4d, 0x98, 0xa2, 0x19, 0xb1, 0x34, 0xa0, 0x79, 0x4e, 0x73, 0xbb, 0x5b, 0xd0, 0x14, 0x30, 0xb4,
+	0x30, 0x4f, 0xfc, 0x2c, 0xbf, 0x4c, 0xf9, 0x88, 0xa5, 0xef, 0xa2, 0x90, 0x32, 0x35, 0x29, 0x2e,
+	0xc0, 0x51, 0x9e, 0x86, 0x89, 0x6e, 0xb1, 0x27, 0x34, 0x9b, 0x83, 0xb9, 0x7f, 0xb1, 0xa0, 0x6f,
+	0x04, 0x04, 0x2b, 0xf5, 0x0f, 0x61, 0x59, 0xcc, 0x38, 0x3a, 0xb1, 0xb6, 0xe4, 0xb0, 0x30, 0x47,
+	0x24, 0x3b, 0x09, 0x4f, 0x11, 0x3a, 0x63, 0xe8, 0x08, 0x00, 0xe6, 0x4b, 0xe2, 0x17, 0x21, 0x14,
+	0xdf, 0x58, 0x31, 0x32, 0x3f, 0xcf, 0x45, 0x2b, 0x82, 0x13, 0x8e, 0x5a, 0xa1, 0x53, 0x27, 0x34,
+	0x17, 0x1d, 0x8e, 0x8c, 0x91, 0x5e, 0x22, 0xe6, 0xbd, 0xcf, 0x92, 0x28, 0x19, 0x8b, 0x53, 0xb3,
+	0xea, 0xe9, 0xa5, 0xfb, 0x1b, 0xd8, 0x90, 0x27, 0xe6, 0x4c, 0x19, 0x61, 0xce, 0x20, 0x99, 0x76,
+	0x88, 0xdc, 0xbd, 0x58, 0x17, 0x5a, 0xb5, 0x0c, 0xad, 0x74, 0x3f, 0xba, 0x64, 0x8c, 0x08, 0x1b,
+	0xb0, 0xbe, 0x20, 0x1d, 0xb3, 0x76, 0xab, 0xb8, 0x93, 0xaa, 0xdb, 0x1a, 0xf7, 0x4e, 0x85, 0xc7,
+	0x86, 0x0d, 0x35, 0xba, 0x54, 0x59, 0x36, 0x60, 0x7d, 0x01, 0x83, 0x1c, 0xcf, 0x61, 0xf3, 0x4b,
+	0xca, 0x47, 0x63, 0x35, 0x83, 0xbd, 0x48, 0xc7, 0xb9, 0x31, 0xcf, 0x30, 0x7c, 0x22, 0x51, 0x6e,
+	0x65, 0xea, 0x89, 0x24, 0x28, 0x9e, 0x48, 0x5a, 0xf2, 0x89, 0xa4, 0x00, 0xb8, 0x5f, 0xc0, 0x9a,
+	0x29, 0xa9, 0x36, 0x30, 0x0e, 0xac, 0x2a, 0x86, 0x5c, 0x08, 0x58, 0xf3, 0x8a, 0xb5, 0xfb, 0x05,
+	0xdc, 0x5e, 0x54, 0x06, 0xb3, 0xe3, 0xfb, 0xd0, 0x8e, 0xd3, 0xb1, 0xce, 0x8d, 0x9b, 0x6a, 0x90,
+	0x2c, 0xc9, 0x3c, 0x81, 0x76, 0xff, 0xd4, 0x02, 0x47, 0xf9, 0x72, 0x9a, 0xe1, 0xd1, 0x3a, 0x9a,
+	0x26, 0x61, 0x4c, 0x2b, 0x57, 0xf9, 0xb0, 0x72, 0x95, 0xe3, 0x1a, 0xa3, 0x3f, 0xce, 0x2e, 0xd3,
+	0x09, 0xd5, 0xc3, 0x82, 0x5e, 0x62, 0x8b, 0xc3, 0x68, 0xe8, 0x07, 0x7c, 0xe4, 0xe7, 0xf9, 0xfb,
+	0x94, 0x85, 0xb2, 0x37, 0x59, 0xf5, 0xaa, 0x60, 0xf2, 0x3b, 0xb8, 0x81, 0x6d, 0x29, 0x9a, 0x79,
+	0x18, 0x47, 0x7e, 0x4e, 0xe5, 0x31, 0xef, 0x3d, 0x38, 0x30, 0xee, 0x8d, 0x3a, 0xcd, 0xf6, 0x9f,
+	0xce, 0xb3, 0xc9, 0x92, 0x59, 0x15, 0xe6, 0x1c, 0xc1, 0x7a, 0x1d, 0xa1, 0xf9, 0x84, 0xd1, 0x95,
+	0x4f, 0x18, 0xeb, 0xe6, 0x13, 0x46, 0xd7, 0x7c, 0xad, 0xd8, 0x07, 0xbb, 0x56, 0x0f, 0xf4, 0x72,
+	0x4d, 0x73, 0x72, 0xff, 0xc7, 0x35, 0xaf, 0x1b, 0xa7, 0xc3, 0xc7, 0x83, 0x6b, 0x64, 0x15, 0xda,
+	0x41, 0x9a, 0xcd, 0x06, 0x16, 0x7e, 0xc5, 0x51, 0xf2, 0x76, 0xd0, 0x12, 0x2f, 0x15, 0x71, 0x9a,
+	0xd0, 0xc1, 0xd2, 0x83, 0x7f, 0xf6, 0xa1, 0x23, 0x06, 0x3a, 0x72, 0x0a, 0xfd, 0xf9, 0x11, 0x80,
+	0x7c, 0xaf, 0x9c, 0x0b, 0x1a, 0xe6, 0x3b, 0xc7, 0x6e, 0x1a, 0x1d, 0xdc, 0x6b, 0xe4, 0x04, 0x06,
+	0xd5, 0x27, 0x03, 0xb2, 0xa3, 0x3a, 0x9b, 0xda, 0x77, 0x0b, 0xc7, 0x69, 0xc0, 0x4a, 0x79, 0xbf,
+	0xa8, 0x9b, 0x9c, 0xef, 0x34, 0xcc, 0xb7, 0x4a, 0xe2, 0x76, 0x13, 0x5a, 0x8a, 0x3c, 0x87, 0xdb,
+	0xe7, 0x49, 0x98, 0x7e, 0xd7, 0x62, 0x7f, 0x02, 0xdd, 0x62, 0x50, 0x26, 0xf2, 0xf1, 0xa5, 0x3a,
+	0x4c, 0x3b, 0xb7, 0xaa, 0x60, 0xc9, 0xfa, 0x5b, 0xfd, 0x12, 0x51, 0x79, 0x12, 0x51, 0xc1, 0xb8,
+	0xea, 0xa9, 0xc5, 0xb9, 0x77, 0x15, 0x89, 0x14, 0xff, 0x6b, 0x58, 0xaf, 0x7b, 0x34, 0x21, 0xbb,
+	0x06, 0x6b, 0xed, 0x73, 0x8b, 0x73, 0xf7, 0x0a, 0x0a, 0x29, 0xfb, 0x57, 0xfa, 0xbd, 0xa6, 0xec,
+	0xe1, 0x4c, 0x03, 0x76, 0x0c, 0x01, 0x0b, 0xaf, 0x32, 0x8e, 0xd3, 0x80, 0x95, 0xa2, 0xbf, 0x82,
+	0x5b, 0x35, 0x0f, 0x2a, 0x44, 0x1a, 0xdc, 0xfc, 0x40, 0xe3, 0xdc, 0x69, 0x26, 0x90, 0x82, 0x7f,
+	0x0a, 0xeb, 0x62, 0xbc, 0xaa, 0x7a, 0xfb, 0xe6, 0xc2, 0x58, 0xe9, 0xdc, 0x30, 0x41, 0x92, 0xfb,
+	0x08, 0x1c, 0xb1, 0xae, 0x37, 0xf8, 0xc3, 0x64, 0x0c, 0x61, 0x5b, 0x4e, 0x3a, 0x2f, 0xcd, 0xb6,
+	0xf2, 0x2a, 0x21, 0xe6, 0x78, 0x54, 0x3a, 0x68, 0x4b, 0x8f, 0x38, 0xfa, 0xd8, 0x14, 0xb3, 0x8e,
+	0xf2, 0x7c, 0xc3, 0xe4, 0xe4, 0x38, 0x0d, 0xd8, 0xc2, 0xf3, 0x35, 0x53, 0x86, 0xf2, 0x7c, 0xf3,
+	0x4c, 0xe3, 0xdc, 0x69, 0x26, 0xa8, 0x9c, 0xe6, 0xd2, 0x79, 0xf3, 0xc7, 0x6e, 0x71, 0x0a, 0x71,
+	0xb6, 0x9b, 0xd0, 0x52, 0xe4, 0x2b, 0x20, 0x8b, 0x2d, 0x33, 0xb9, 0x7b, 0xf5, 0x34, 0xe0, 0xec,
+	0x34, 0xe2, 0x8b, 0x13, 0x59, 0xdb, 0xb4, 0xaa, 0x13, 0x79, 0x55, 0x53, 0xed, 0xdc, 0xbb, 0x8a,
+	0xa4, 0xa8, 0x15, 0x45, 0xfb, 0xa4, 0x6a, 0x45, 0xb5, 0x09, 0x76, 0x6e, 0x55, 0xc1, 0x92, 0xf5,
+	0x39, 0xdc, 0xa8, 0xb4, 0x24, 0x64, 0xdb, 0xbc, 0xc2, 0x2a, 0xcd, 0x85, 0xb3, 0x55, 0x8f, 0x2c,
+	0xaa, 0x75, 0xb5, 0x59, 0x99, 0x4f, 0x9c, 0x05, 0x71, 0x4e, 0x03, 0xb6, 0x50, 0xae, 0xd2, 0xc9,
+	0x28, 0xe5, 0xea, 0x3b, 0x1f, 0x67, 0xab, 0x1e, 0x59, 0x28, 0x57, 0xed, 0x38, 0x94, 0x72, 0x0d,
+	0x5d, 0x91, 0xe3, 0x34, 0x60, 0x8b, 0xac, 0xae, 0xb9, 0x5e, 0x55, 0x56, 0x37, 0x37, 0x00, 0xce,
+	0x9d, 0x66, 0x02, 0x21, 0xf8, 0xcd, 0xb2, 0xf8, 0xf1, 0xf5, 0xa3, 0xff, 0x0d, 0x00, 0x4c, 0x14,
+	0x99, 0xfa, 0x0e, 0x1b, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AgentClient is the client API for Agent service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AgentClient interface {
+	CheckDiskSpace(ctx context.Context, in *CheckSegmentDiskSpaceRequest, opts ...grpc.CallOption) (*CheckDiskSpaceReply, error)
+	UpgradePrimaries(ctx context.Context, in *UpgradePrimariesRequest, opts ...grpc.CallOption) (*UpgradePrimariesReply, error)
+	RenameDirectories(ctx context.Context, in *RenameDirectoriesRequest, opts ...grpc.CallOption) (*RenameDirectoriesReply, error)
+	UndoRenameDirectories(ctx context.Context, in *RenameDirectoriesRequest, opts ...grpc.CallOption) (*RenameDirectoriesReply, error)
+	StopAgent(ctx context.Context, in *StopAgentRequest, opts ...grpc.CallOption) (*StopAgentReply, error)
+	DeleteDataDirectories(ctx context.Context, in *DeleteDataDirectoriesRequest, opts ...grpc.CallOption) (*DeleteDataDirectoriesReply, error)
+	DeleteStateDirectory(ctx context.Context, in *DeleteStateDirectoryRequest, opts ...grpc.CallOption) (*DeleteStateDirectoryReply, error)
+	DeleteTablespaceDirectories(ctx context.Context, in *DeleteTablespaceRequest, opts ...grpc.CallOption) (*DeleteTablespaceReply, error)
+	ArchiveLogDirectory(ctx context.Context, in *ArchiveLogDirectoryRequest, opts ...grpc.CallOption) (*ArchiveLogDirectoryReply, error)
+	RsyncDataDirectories(ctx context.Context, in *RsyncRequest, opts ...grpc.CallOption) (*RsyncReply, error)
+	RsyncTablespaceDirectories(ctx context.Context, in *RsyncRequest, opts ...grpc.CallOption) (*RsyncReply, error)
+	ResyncMirrorDataDirectories(ctx context.Context, in *RsyncRequest, opts ...grpc.CallOption) (*ResyncReply, error)
+	RestorePrimariesPgControl(ctx context.Context, in *RestorePgControlRequest, opts ...grpc.CallOption) (*RestorePgControlReply, error)
+	UpdateConfiguration(ctx context.Context, in *UpdateConfigurationRequest, opts ...grpc.CallOption) (*UpdateConfigurationReply, error)
+	RenameTablespaces(ctx context.Context, in *RenameTablespacesRequest, opts ...grpc.CallOption) (*RenameTablespacesReply, error)
+	CreateRecoveryConf(ctx context.Context, in *CreateRecoveryConfRequest, opts ...grpc.CallOption) (*CreateRecoveryConfReply, error)
+	AddReplicationEntries(ctx context.Context, in *AddReplicationEntriesRequest, opts ...grpc.CallOption) (*AddReplicationEntriesReply, error)
+	Preflight(ctx context.Context, in *PreflightRequest, opts ...grpc.CallOption) (*PreflightReply, error)
+	CreateSnapshots(ctx context.Context, in *CreateSnapshotsRequest, opts ...grpc.CallOption) (*CreateSnapshotsReply, error)
+	RestoreSnapshots(ctx context.Context, in *RestoreSnapshotsRequest, opts ...grpc.CallOption) (*RestoreSnapshotsReply, error)
+	DeleteSnapshots(ctx context.Context, in *DeleteSnapshotsRequest, opts ...grpc.CallOption) (*DeleteSnapshotsReply, error)
+	GetPgUpgradeLogs(ctx context.Context, in *GetPgUpgradeLogsRequest, opts ...grpc.CallOption) (*GetPgUpgradeLogsReply, error)
+	CreateSupportBundle(ctx context.Context, in *CreateSupportBundleRequest, opts ...grpc.CallOption) (*CreateSupportBundleReply, error)
+}
+
+type agentClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAgentClient(cc *grpc.ClientConn) AgentClient {
+	return &agentClient{cc}
 }
 
 func (c *agentClient) CheckDiskSpace(ctx context.Context, in *CheckSegmentDiskSpaceRequest, opts ...grpc.CallOption) (*CheckDiskSpaceReply, error) {
@@ -1891,6 +2882,15 @@ func (c *agentClient) RenameDirectories(ctx context.Context, in *RenameDirectori
 	return out, nil
 }
 
+func (c *agentClient) UndoRenameDirectories(ctx context.Context, in *RenameDirectoriesRequest, opts ...grpc.CallOption) (*RenameDirectoriesReply, error) {
+	out := new(RenameDirectoriesReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/UndoRenameDirectories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentClient) StopAgent(ctx context.Context, in *StopAgentRequest, opts ...grpc.CallOption) (*StopAgentReply, error) {
 	out := new(StopAgentReply)
 	err := c.cc.Invoke(ctx, "/idl.Agent/StopAgent", in, out, opts...)
@@ -1954,6 +2954,15 @@ func (c *agentClient) RsyncTablespaceDirectories(ctx context.Context, in *RsyncR
 	return out, nil
 }
 
+func (c *agentClient) ResyncMirrorDataDirectories(ctx context.Context, in *RsyncRequest, opts ...grpc.CallOption) (*ResyncReply, error) {
+	out := new(ResyncReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/ResyncMirrorDataDirectories", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *agentClient) RestorePrimariesPgControl(ctx context.Context, in *RestorePgControlRequest, opts ...grpc.CallOption) (*RestorePgControlReply, error) {
 	out := new(RestorePgControlReply)
 	err := c.cc.Invoke(ctx, "/idl.Agent/RestorePrimariesPgControl", in, out, opts...)
@@ -1999,11 +3008,66 @@ func (c *agentClient) AddReplicationEntries(ctx context.Context, in *AddReplicat
 	return out, nil
 }
 
+func (c *agentClient) Preflight(ctx context.Context, in *PreflightRequest, opts ...grpc.CallOption) (*PreflightReply, error) {
+	out := new(PreflightReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/Preflight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) CreateSnapshots(ctx context.Context, in *CreateSnapshotsRequest, opts ...grpc.CallOption) (*CreateSnapshotsReply, error) {
+	out := new(CreateSnapshotsReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/CreateSnapshots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) RestoreSnapshots(ctx context.Context, in *RestoreSnapshotsRequest, opts ...grpc.CallOption) (*RestoreSnapshotsReply, error) {
+	out := new(RestoreSnapshotsReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/RestoreSnapshots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) DeleteSnapshots(ctx context.Context, in *DeleteSnapshotsRequest, opts ...grpc.CallOption) (*DeleteSnapshotsReply, error) {
+	out := new(DeleteSnapshotsReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/DeleteSnapshots", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) GetPgUpgradeLogs(ctx context.Context, in *GetPgUpgradeLogsRequest, opts ...grpc.CallOption) (*GetPgUpgradeLogsReply, error) {
+	out := new(GetPgUpgradeLogsReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/GetPgUpgradeLogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentClient) CreateSupportBundle(ctx context.Context, in *CreateSupportBundleRequest, opts ...grpc.CallOption) (*CreateSupportBundleReply, error) {
+	out := new(CreateSupportBundleReply)
+	err := c.cc.Invoke(ctx, "/idl.Agent/CreateSupportBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServer is the server API for Agent service.
 type AgentServer interface {
 	CheckDiskSpace(context.Context, *CheckSegmentDiskSpaceRequest) (*CheckDiskSpaceReply, error)
 	UpgradePrimaries(context.Context, *UpgradePrimariesRequest) (*UpgradePrimariesReply, error)
 	RenameDirectories(context.Context, *RenameDirectoriesRequest) (*RenameDirectoriesReply, error)
+	UndoRenameDirectories(context.Context, *RenameDirectoriesRequest) (*RenameDirectoriesReply, error)
 	StopAgent(context.Context, *StopAgentRequest) (*StopAgentReply, error)
 	DeleteDataDirectories(context.Context, *DeleteDataDirectoriesRequest) (*DeleteDataDirectoriesReply, error)
 	DeleteStateDirectory(context.Context, *DeleteStateDirectoryRequest) (*DeleteStateDirectoryReply, error)
@@ -2011,11 +3075,18 @@ type AgentServer interface {
 	ArchiveLogDirectory(context.Context, *ArchiveLogDirectoryRequest) (*ArchiveLogDirectoryReply, error)
 	RsyncDataDirectories(context.Context, *RsyncRequest) (*RsyncReply, error)
 	RsyncTablespaceDirectories(context.Context, *RsyncRequest) (*RsyncReply, error)
+	ResyncMirrorDataDirectories(context.Context, *RsyncRequest) (*ResyncReply, error)
 	RestorePrimariesPgControl(context.Context, *RestorePgControlRequest) (*RestorePgControlReply, error)
 	UpdateConfiguration(context.Context, *UpdateConfigurationRequest) (*UpdateConfigurationReply, error)
 	RenameTablespaces(context.Context, *RenameTablespacesRequest) (*RenameTablespacesReply, error)
 	CreateRecoveryConf(context.Context, *CreateRecoveryConfRequest) (*CreateRecoveryConfReply, error)
 	AddReplicationEntries(context.Context, *AddReplicationEntriesRequest) (*AddReplicationEntriesReply, error)
+	Preflight(context.Context, *PreflightRequest) (*PreflightReply, error)
+	CreateSnapshots(context.Context, *CreateSnapshotsRequest) (*CreateSnapshotsReply, error)
+	RestoreSnapshots(context.Context, *RestoreSnapshotsRequest) (*RestoreSnapshotsReply, error)
+	DeleteSnapshots(context.Context, *DeleteSnapshotsRequest) (*DeleteSnapshotsReply, error)
+	GetPgUpgradeLogs(context.Context, *GetPgUpgradeLogsRequest) (*GetPgUpgradeLogsReply, error)
+	CreateSupportBundle(context.Context, *CreateSupportBundleRequest) (*CreateSupportBundleReply, error)
 }
 
 // UnimplementedAgentServer can be embedded to have forward compatible implementations.
@@ -2031,6 +3102,9 @@ func (*UnimplementedAgentServer) UpgradePrimaries(ctx context.Context, req *Upgr
 func (*UnimplementedAgentServer) RenameDirectories(ctx context.Context, req *RenameDirectoriesRequest) (*RenameDirectoriesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RenameDirectories not implemented")
 }
+func (*UnimplementedAgentServer) UndoRenameDirectories(ctx context.Context, req *RenameDirectoriesRequest) (*RenameDirectoriesReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndoRenameDirectories not implemented")
+}
 func (*UnimplementedAgentServer) StopAgent(ctx context.Context, req *StopAgentRequest) (*StopAgentReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StopAgent not implemented")
 }
@@ -2052,6 +3126,9 @@ func (*UnimplementedAgentServer) RsyncDataDirectories(ctx context.Context, req *
 func (*UnimplementedAgentServer) RsyncTablespaceDirectories(ctx context.Context, req *RsyncRequest) (*RsyncReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RsyncTablespaceDirectories not implemented")
 }
+func (*UnimplementedAgentServer) ResyncMirrorDataDirectories(ctx context.Context, req *RsyncRequest) (*ResyncReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResyncMirrorDataDirectories not implemented")
+}
 func (*UnimplementedAgentServer) RestorePrimariesPgControl(ctx context.Context, req *RestorePgControlRequest) (*RestorePgControlReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RestorePrimariesPgControl not implemented")
 }
@@ -2067,6 +3144,24 @@ func (*UnimplementedAgentServer) CreateRecoveryConf(ctx context.Context, req *Cr
 func (*UnimplementedAgentServer) AddReplicationEntries(ctx context.Context, req *AddReplicationEntriesRequest) (*AddReplicationEntriesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddReplicationEntries not implemented")
 }
+func (*UnimplementedAgentServer) Preflight(ctx context.Context, req *PreflightRequest) (*PreflightReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Preflight not implemented")
+}
+func (*UnimplementedAgentServer) CreateSnapshots(ctx context.Context, req *CreateSnapshotsRequest) (*CreateSnapshotsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSnapshots not implemented")
+}
+func (*UnimplementedAgentServer) RestoreSnapshots(ctx context.Context, req *RestoreSnapshotsRequest) (*RestoreSnapshotsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSnapshots not implemented")
+}
+func (*UnimplementedAgentServer) DeleteSnapshots(ctx context.Context, req *DeleteSnapshotsRequest) (*DeleteSnapshotsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSnapshots not implemented")
+}
+func (*UnimplementedAgentServer) GetPgUpgradeLogs(ctx context.Context, req *GetPgUpgradeLogsRequest) (*GetPgUpgradeLogsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPgUpgradeLogs not implemented")
+}
+func (*UnimplementedAgentServer) CreateSupportBundle(ctx context.Context, req *CreateSupportBundleRequest) (*CreateSupportBundleReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSupportBundle not implemented")
+}
 
 func RegisterAgentServer(s *grpc.Server, srv AgentServer) {
 	s.RegisterService(&_Agent_serviceDesc, srv)
@@ -2126,6 +3221,24 @@ func _Agent_RenameDirectories_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_UndoRenameDirectories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameDirectoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).UndoRenameDirectories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/UndoRenameDirectories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).UndoRenameDirectories(ctx, req.(*RenameDirectoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Agent_StopAgent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StopAgentRequest)
 	if err := dec(in); err != nil {
@@ -2252,6 +3365,24 @@ func _Agent_RsyncTablespaceDirectories_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_ResyncMirrorDataDirectories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RsyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).ResyncMirrorDataDirectories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/ResyncMirrorDataDirectories",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).ResyncMirrorDataDirectories(ctx, req.(*RsyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Agent_RestorePrimariesPgControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RestorePgControlRequest)
 	if err := dec(in); err != nil {
@@ -2342,6 +3473,114 @@ func _Agent_AddReplicationEntries_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Agent_Preflight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PreflightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).Preflight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/Preflight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).Preflight(ctx, req.(*PreflightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_CreateSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CreateSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/CreateSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CreateSnapshots(ctx, req.(*CreateSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_RestoreSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).RestoreSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/RestoreSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).RestoreSnapshots(ctx, req.(*RestoreSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_DeleteSnapshots_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSnapshotsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).DeleteSnapshots(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/DeleteSnapshots",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).DeleteSnapshots(ctx, req.(*DeleteSnapshotsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_GetPgUpgradeLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPgUpgradeLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).GetPgUpgradeLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/GetPgUpgradeLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).GetPgUpgradeLogs(ctx, req.(*GetPgUpgradeLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Agent_CreateSupportBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSupportBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServer).CreateSupportBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/idl.Agent/CreateSupportBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServer).CreateSupportBundle(ctx, req.(*CreateSupportBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Agent_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "idl.Agent",
 	HandlerType: (*AgentServer)(nil),
@@ -2358,6 +3597,10 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RenameDirectories",
 			Handler:    _Agent_RenameDirectories_Handler,
 		},
+		{
+			MethodName: "UndoRenameDirectories",
+			Handler:    _Agent_UndoRenameDirectories_Handler,
+		},
 		{
 			MethodName: "StopAgent",
 			Handler:    _Agent_StopAgent_Handler,
@@ -2386,6 +3629,10 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RsyncTablespaceDirectories",
 			Handler:    _Agent_RsyncTablespaceDirectories_Handler,
 		},
+		{
+			MethodName: "ResyncMirrorDataDirectories",
+			Handler:    _Agent_ResyncMirrorDataDirectories_Handler,
+		},
 		{
 			MethodName: "RestorePrimariesPgControl",
 			Handler:    _Agent_RestorePrimariesPgControl_Handler,
@@ -2406,6 +3653,30 @@ var _Agent_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AddReplicationEntries",
 			Handler:    _Agent_AddReplicationEntries_Handler,
 		},
+		{
+			MethodName: "Preflight",
+			Handler:    _Agent_Preflight_Handler,
+		},
+		{
+			MethodName: "CreateSnapshots",
+			Handler:    _Agent_CreateSnapshots_Handler,
+		},
+		{
+			MethodName: "RestoreSnapshots",
+			Handler:    _Agent_RestoreSnapshots_Handler,
+		},
+		{
+			MethodName: "DeleteSnapshots",
+			Handler:    _Agent_DeleteSnapshots_Handler,
+		},
+		{
+			MethodName: "GetPgUpgradeLogs",
+			Handler:    _Agent_GetPgUpgradeLogs_Handler,
+		},
+		{
+			MethodName: "CreateSupportBundle",
+			Handler:    _Agent_CreateSupportBundle_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "hub_to_agent.proto",
@@ -177,6 +177,46 @@ func (mr *MockCliToHubClientMockRecorder) Revert(arg0, arg1 interface{}, arg2 ..
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockCliToHubClient)(nil).Revert), varargs...)
 }
 
+// Segments mocks base method.
+func (m *MockCliToHubClient) Segments(arg0 context.Context, arg1 *idl.SegmentsRequest, arg2 ...grpc.CallOption) (*idl.SegmentsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Segments", varargs...)
+	ret0, _ := ret[0].(*idl.SegmentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Segments indicates an expected call of Segments.
+func (mr *MockCliToHubClientMockRecorder) Segments(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Segments", reflect.TypeOf((*MockCliToHubClient)(nil).Segments), varargs...)
+}
+
+// ShowConfig mocks base method.
+func (m *MockCliToHubClient) ShowConfig(arg0 context.Context, arg1 *idl.ShowConfigRequest, arg2 ...grpc.CallOption) (*idl.ShowConfigReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ShowConfig", varargs...)
+	ret0, _ := ret[0].(*idl.ShowConfigReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowConfig indicates an expected call of ShowConfig.
+func (mr *MockCliToHubClientMockRecorder) ShowConfig(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowConfig", reflect.TypeOf((*MockCliToHubClient)(nil).ShowConfig), varargs...)
+}
+
 // StopServices mocks base method.
 func (m *MockCliToHubClient) StopServices(arg0 context.Context, arg1 *idl.StopServicesRequest, arg2 ...grpc.CallOption) (*idl.StopServicesReply, error) {
 	m.ctrl.T.Helper()
@@ -197,6 +237,46 @@ func (mr *MockCliToHubClientMockRecorder) StopServices(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopServices", reflect.TypeOf((*MockCliToHubClient)(nil).StopServices), varargs...)
 }
 
+// SupportBundle mocks base method.
+func (m *MockCliToHubClient) SupportBundle(arg0 context.Context, arg1 *idl.SupportBundleRequest, arg2 ...grpc.CallOption) (*idl.SupportBundleReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SupportBundle", varargs...)
+	ret0, _ := ret[0].(*idl.SupportBundleReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SupportBundle indicates an expected call of SupportBundle.
+func (mr *MockCliToHubClientMockRecorder) SupportBundle(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupportBundle", reflect.TypeOf((*MockCliToHubClient)(nil).SupportBundle), varargs...)
+}
+
+// Validate mocks base method.
+func (m *MockCliToHubClient) Validate(arg0 context.Context, arg1 *idl.ValidateRequest, arg2 ...grpc.CallOption) (*idl.ValidateReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Validate", varargs...)
+	ret0, _ := ret[0].(*idl.ValidateReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockCliToHubClientMockRecorder) Validate(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockCliToHubClient)(nil).Validate), varargs...)
+}
+
 // MockCliToHubServer is a mock of CliToHubServer interface.
 type MockCliToHubServer struct {
 	ctrl     *gomock.Controller
@@ -320,6 +400,36 @@ func (mr *MockCliToHubServerMockRecorder) Revert(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revert", reflect.TypeOf((*MockCliToHubServer)(nil).Revert), arg0, arg1)
 }
 
+// Segments mocks base method.
+func (m *MockCliToHubServer) Segments(arg0 context.Context, arg1 *idl.SegmentsRequest) (*idl.SegmentsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Segments", arg0, arg1)
+	ret0, _ := ret[0].(*idl.SegmentsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Segments indicates an expected call of Segments.
+func (mr *MockCliToHubServerMockRecorder) Segments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Segments", reflect.TypeOf((*MockCliToHubServer)(nil).Segments), arg0, arg1)
+}
+
+// ShowConfig mocks base method.
+func (m *MockCliToHubServer) ShowConfig(arg0 context.Context, arg1 *idl.ShowConfigRequest) (*idl.ShowConfigReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ShowConfig", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ShowConfigReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ShowConfig indicates an expected call of ShowConfig.
+func (mr *MockCliToHubServerMockRecorder) ShowConfig(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ShowConfig", reflect.TypeOf((*MockCliToHubServer)(nil).ShowConfig), arg0, arg1)
+}
+
 // StopServices mocks base method.
 func (m *MockCliToHubServer) StopServices(arg0 context.Context, arg1 *idl.StopServicesRequest) (*idl.StopServicesReply, error) {
 	m.ctrl.T.Helper()
@@ -335,6 +445,36 @@ func (mr *MockCliToHubServerMockRecorder) StopServices(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopServices", reflect.TypeOf((*MockCliToHubServer)(nil).StopServices), arg0, arg1)
 }
 
+// SupportBundle mocks base method.
+func (m *MockCliToHubServer) SupportBundle(arg0 context.Context, arg1 *idl.SupportBundleRequest) (*idl.SupportBundleReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SupportBundle", arg0, arg1)
+	ret0, _ := ret[0].(*idl.SupportBundleReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SupportBundle indicates an expected call of SupportBundle.
+func (mr *MockCliToHubServerMockRecorder) SupportBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupportBundle", reflect.TypeOf((*MockCliToHubServer)(nil).SupportBundle), arg0, arg1)
+}
+
+// Validate mocks base method.
+func (m *MockCliToHubServer) Validate(arg0 context.Context, arg1 *idl.ValidateRequest) (*idl.ValidateReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ValidateReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockCliToHubServerMockRecorder) Validate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockCliToHubServer)(nil).Validate), arg0, arg1)
+}
+
 // MockCliToHub_ExecuteServer is a mock of CliToHub_ExecuteServer interface.
 type MockCliToHub_ExecuteServer struct {
 	ctrl     *gomock.Controller
@@ -116,6 +116,46 @@ func (mr *MockAgentClientMockRecorder) CreateRecoveryConf(ctx, in interface{}, o
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryConf", reflect.TypeOf((*MockAgentClient)(nil).CreateRecoveryConf), varargs...)
 }
 
+// CreateSnapshots mocks base method.
+func (m *MockAgentClient) CreateSnapshots(ctx context.Context, in *idl.CreateSnapshotsRequest, opts ...grpc.CallOption) (*idl.CreateSnapshotsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateSnapshots", varargs...)
+	ret0, _ := ret[0].(*idl.CreateSnapshotsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnapshots indicates an expected call of CreateSnapshots.
+func (mr *MockAgentClientMockRecorder) CreateSnapshots(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshots", reflect.TypeOf((*MockAgentClient)(nil).CreateSnapshots), varargs...)
+}
+
+// CreateSupportBundle mocks base method.
+func (m *MockAgentClient) CreateSupportBundle(ctx context.Context, in *idl.CreateSupportBundleRequest, opts ...grpc.CallOption) (*idl.CreateSupportBundleReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CreateSupportBundle", varargs...)
+	ret0, _ := ret[0].(*idl.CreateSupportBundleReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSupportBundle indicates an expected call of CreateSupportBundle.
+func (mr *MockAgentClientMockRecorder) CreateSupportBundle(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSupportBundle", reflect.TypeOf((*MockAgentClient)(nil).CreateSupportBundle), varargs...)
+}
+
 // DeleteDataDirectories mocks base method.
 func (m *MockAgentClient) DeleteDataDirectories(ctx context.Context, in *idl.DeleteDataDirectoriesRequest, opts ...grpc.CallOption) (*idl.DeleteDataDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -136,6 +176,26 @@ func (mr *MockAgentClientMockRecorder) DeleteDataDirectories(ctx, in interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataDirectories", reflect.TypeOf((*MockAgentClient)(nil).DeleteDataDirectories), varargs...)
 }
 
+// DeleteSnapshots mocks base method.
+func (m *MockAgentClient) DeleteSnapshots(ctx context.Context, in *idl.DeleteSnapshotsRequest, opts ...grpc.CallOption) (*idl.DeleteSnapshotsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteSnapshots", varargs...)
+	ret0, _ := ret[0].(*idl.DeleteSnapshotsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSnapshots indicates an expected call of DeleteSnapshots.
+func (mr *MockAgentClientMockRecorder) DeleteSnapshots(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnapshots", reflect.TypeOf((*MockAgentClient)(nil).DeleteSnapshots), varargs...)
+}
+
 // DeleteStateDirectory mocks base method.
 func (m *MockAgentClient) DeleteStateDirectory(ctx context.Context, in *idl.DeleteStateDirectoryRequest, opts ...grpc.CallOption) (*idl.DeleteStateDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -176,6 +236,46 @@ func (mr *MockAgentClientMockRecorder) DeleteTablespaceDirectories(ctx, in inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTablespaceDirectories", reflect.TypeOf((*MockAgentClient)(nil).DeleteTablespaceDirectories), varargs...)
 }
 
+// GetPgUpgradeLogs mocks base method.
+func (m *MockAgentClient) GetPgUpgradeLogs(ctx context.Context, in *idl.GetPgUpgradeLogsRequest, opts ...grpc.CallOption) (*idl.GetPgUpgradeLogsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetPgUpgradeLogs", varargs...)
+	ret0, _ := ret[0].(*idl.GetPgUpgradeLogsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPgUpgradeLogs indicates an expected call of GetPgUpgradeLogs.
+func (mr *MockAgentClientMockRecorder) GetPgUpgradeLogs(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPgUpgradeLogs", reflect.TypeOf((*MockAgentClient)(nil).GetPgUpgradeLogs), varargs...)
+}
+
+// Preflight mocks base method.
+func (m *MockAgentClient) Preflight(ctx context.Context, in *idl.PreflightRequest, opts ...grpc.CallOption) (*idl.PreflightReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Preflight", varargs...)
+	ret0, _ := ret[0].(*idl.PreflightReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preflight indicates an expected call of Preflight.
+func (mr *MockAgentClientMockRecorder) Preflight(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preflight", reflect.TypeOf((*MockAgentClient)(nil).Preflight), varargs...)
+}
+
 // RenameDirectories mocks base method.
 func (m *MockAgentClient) RenameDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest, opts ...grpc.CallOption) (*idl.RenameDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -236,6 +336,46 @@ func (mr *MockAgentClientMockRecorder) RestorePrimariesPgControl(ctx, in interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePrimariesPgControl", reflect.TypeOf((*MockAgentClient)(nil).RestorePrimariesPgControl), varargs...)
 }
 
+// RestoreSnapshots mocks base method.
+func (m *MockAgentClient) RestoreSnapshots(ctx context.Context, in *idl.RestoreSnapshotsRequest, opts ...grpc.CallOption) (*idl.RestoreSnapshotsReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RestoreSnapshots", varargs...)
+	ret0, _ := ret[0].(*idl.RestoreSnapshotsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSnapshots indicates an expected call of RestoreSnapshots.
+func (mr *MockAgentClientMockRecorder) RestoreSnapshots(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshots", reflect.TypeOf((*MockAgentClient)(nil).RestoreSnapshots), varargs...)
+}
+
+// ResyncMirrorDataDirectories mocks base method.
+func (m *MockAgentClient) ResyncMirrorDataDirectories(ctx context.Context, in *idl.RsyncRequest, opts ...grpc.CallOption) (*idl.ResyncReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResyncMirrorDataDirectories", varargs...)
+	ret0, _ := ret[0].(*idl.ResyncReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResyncMirrorDataDirectories indicates an expected call of ResyncMirrorDataDirectories.
+func (mr *MockAgentClientMockRecorder) ResyncMirrorDataDirectories(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResyncMirrorDataDirectories", reflect.TypeOf((*MockAgentClient)(nil).ResyncMirrorDataDirectories), varargs...)
+}
+
 // RsyncDataDirectories mocks base method.
 func (m *MockAgentClient) RsyncDataDirectories(ctx context.Context, in *idl.RsyncRequest, opts ...grpc.CallOption) (*idl.RsyncReply, error) {
 	m.ctrl.T.Helper()
@@ -296,6 +436,26 @@ func (mr *MockAgentClientMockRecorder) StopAgent(ctx, in interface{}, opts ...in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopAgent", reflect.TypeOf((*MockAgentClient)(nil).StopAgent), varargs...)
 }
 
+// UndoRenameDirectories mocks base method.
+func (m *MockAgentClient) UndoRenameDirectories(ctx context.Context, in *idl.RenameDirectoriesRequest, opts ...grpc.CallOption) (*idl.RenameDirectoriesReply, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UndoRenameDirectories", varargs...)
+	ret0, _ := ret[0].(*idl.RenameDirectoriesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndoRenameDirectories indicates an expected call of UndoRenameDirectories.
+func (mr *MockAgentClientMockRecorder) UndoRenameDirectories(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoRenameDirectories", reflect.TypeOf((*MockAgentClient)(nil).UndoRenameDirectories), varargs...)
+}
+
 // UpdateConfiguration mocks base method.
 func (m *MockAgentClient) UpdateConfiguration(ctx context.Context, in *idl.UpdateConfigurationRequest, opts ...grpc.CallOption) (*idl.UpdateConfigurationReply, error) {
 	m.ctrl.T.Helper()
@@ -419,6 +579,36 @@ func (mr *MockAgentServerMockRecorder) CreateRecoveryConf(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryConf", reflect.TypeOf((*MockAgentServer)(nil).CreateRecoveryConf), arg0, arg1)
 }
 
+// CreateSnapshots mocks base method.
+func (m *MockAgentServer) CreateSnapshots(arg0 context.Context, arg1 *idl.CreateSnapshotsRequest) (*idl.CreateSnapshotsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSnapshots", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CreateSnapshotsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSnapshots indicates an expected call of CreateSnapshots.
+func (mr *MockAgentServerMockRecorder) CreateSnapshots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSnapshots", reflect.TypeOf((*MockAgentServer)(nil).CreateSnapshots), arg0, arg1)
+}
+
+// CreateSupportBundle mocks base method.
+func (m *MockAgentServer) CreateSupportBundle(arg0 context.Context, arg1 *idl.CreateSupportBundleRequest) (*idl.CreateSupportBundleReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSupportBundle", arg0, arg1)
+	ret0, _ := ret[0].(*idl.CreateSupportBundleReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSupportBundle indicates an expected call of CreateSupportBundle.
+func (mr *MockAgentServerMockRecorder) CreateSupportBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSupportBundle", reflect.TypeOf((*MockAgentServer)(nil).CreateSupportBundle), arg0, arg1)
+}
+
 // DeleteDataDirectories mocks base method.
 func (m *MockAgentServer) DeleteDataDirectories(arg0 context.Context, arg1 *idl.DeleteDataDirectoriesRequest) (*idl.DeleteDataDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -434,6 +624,21 @@ func (mr *MockAgentServerMockRecorder) DeleteDataDirectories(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDataDirectories", reflect.TypeOf((*MockAgentServer)(nil).DeleteDataDirectories), arg0, arg1)
 }
 
+// DeleteSnapshots mocks base method.
+func (m *MockAgentServer) DeleteSnapshots(arg0 context.Context, arg1 *idl.DeleteSnapshotsRequest) (*idl.DeleteSnapshotsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSnapshots", arg0, arg1)
+	ret0, _ := ret[0].(*idl.DeleteSnapshotsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSnapshots indicates an expected call of DeleteSnapshots.
+func (mr *MockAgentServerMockRecorder) DeleteSnapshots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSnapshots", reflect.TypeOf((*MockAgentServer)(nil).DeleteSnapshots), arg0, arg1)
+}
+
 // DeleteStateDirectory mocks base method.
 func (m *MockAgentServer) DeleteStateDirectory(arg0 context.Context, arg1 *idl.DeleteStateDirectoryRequest) (*idl.DeleteStateDirectoryReply, error) {
 	m.ctrl.T.Helper()
@@ -464,6 +669,36 @@ func (mr *MockAgentServerMockRecorder) DeleteTablespaceDirectories(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTablespaceDirectories", reflect.TypeOf((*MockAgentServer)(nil).DeleteTablespaceDirectories), arg0, arg1)
 }
 
+// GetPgUpgradeLogs mocks base method.
+func (m *MockAgentServer) GetPgUpgradeLogs(arg0 context.Context, arg1 *idl.GetPgUpgradeLogsRequest) (*idl.GetPgUpgradeLogsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPgUpgradeLogs", arg0, arg1)
+	ret0, _ := ret[0].(*idl.GetPgUpgradeLogsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPgUpgradeLogs indicates an expected call of GetPgUpgradeLogs.
+func (mr *MockAgentServerMockRecorder) GetPgUpgradeLogs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPgUpgradeLogs", reflect.TypeOf((*MockAgentServer)(nil).GetPgUpgradeLogs), arg0, arg1)
+}
+
+// Preflight mocks base method.
+func (m *MockAgentServer) Preflight(arg0 context.Context, arg1 *idl.PreflightRequest) (*idl.PreflightReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Preflight", arg0, arg1)
+	ret0, _ := ret[0].(*idl.PreflightReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preflight indicates an expected call of Preflight.
+func (mr *MockAgentServerMockRecorder) Preflight(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preflight", reflect.TypeOf((*MockAgentServer)(nil).Preflight), arg0, arg1)
+}
+
 // RenameDirectories mocks base method.
 func (m *MockAgentServer) RenameDirectories(arg0 context.Context, arg1 *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
 	m.ctrl.T.Helper()
@@ -509,6 +744,36 @@ func (mr *MockAgentServerMockRecorder) RestorePrimariesPgControl(arg0, arg1 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePrimariesPgControl", reflect.TypeOf((*MockAgentServer)(nil).RestorePrimariesPgControl), arg0, arg1)
 }
 
+// RestoreSnapshots mocks base method.
+func (m *MockAgentServer) RestoreSnapshots(arg0 context.Context, arg1 *idl.RestoreSnapshotsRequest) (*idl.RestoreSnapshotsReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSnapshots", arg0, arg1)
+	ret0, _ := ret[0].(*idl.RestoreSnapshotsReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreSnapshots indicates an expected call of RestoreSnapshots.
+func (mr *MockAgentServerMockRecorder) RestoreSnapshots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshots", reflect.TypeOf((*MockAgentServer)(nil).RestoreSnapshots), arg0, arg1)
+}
+
+// ResyncMirrorDataDirectories mocks base method.
+func (m *MockAgentServer) ResyncMirrorDataDirectories(arg0 context.Context, arg1 *idl.RsyncRequest) (*idl.ResyncReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResyncMirrorDataDirectories", arg0, arg1)
+	ret0, _ := ret[0].(*idl.ResyncReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResyncMirrorDataDirectories indicates an expected call of ResyncMirrorDataDirectories.
+func (mr *MockAgentServerMockRecorder) ResyncMirrorDataDirectories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResyncMirrorDataDirectories", reflect.TypeOf((*MockAgentServer)(nil).ResyncMirrorDataDirectories), arg0, arg1)
+}
+
 // RsyncDataDirectories mocks base method.
 func (m *MockAgentServer) RsyncDataDirectories(arg0 context.Context, arg1 *idl.RsyncRequest) (*idl.RsyncReply, error) {
 	m.ctrl.T.Helper()
@@ -554,6 +819,21 @@ func (mr *MockAgentServerMockRecorder) StopAgent(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopAgent", reflect.TypeOf((*MockAgentServer)(nil).StopAgent), arg0, arg1)
 }
 
+// UndoRenameDirectories mocks base method.
+func (m *MockAgentServer) UndoRenameDirectories(arg0 context.Context, arg1 *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoRenameDirectories", arg0, arg1)
+	ret0, _ := ret[0].(*idl.RenameDirectoriesReply)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndoRenameDirectories indicates an expected call of UndoRenameDirectories.
+func (mr *MockAgentServerMockRecorder) UndoRenameDirectories(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoRenameDirectories", reflect.TypeOf((*MockAgentServer)(nil).UndoRenameDirectories), arg0, arg1)
+}
+
 // UpdateConfiguration mocks base method.
 func (m *MockAgentServer) UpdateConfiguration(arg0 context.Context, arg1 *idl.UpdateConfigurationRequest) (*idl.UpdateConfigurationReply, error) {
 	m.ctrl.T.Helper()
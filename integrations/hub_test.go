@@ -18,6 +18,9 @@ import (
 	"github.com/greenplum-db/gpupgrade/cli/commanders"
 	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 func TestHub(t *testing.T) {
@@ -115,7 +118,7 @@ func TestHub(t *testing.T) {
 			t.Errorf("unexpected error got %+v", err)
 		}
 
-		err = commanders.CreateInitialClusterConfigs(upgrade.DefaultHubPort)
+		err = commanders.CreateInitialClusterConfigs(upgrade.DefaultHubPort, 0, trace.Config{}, notify.Config{}, log.Text)
 		if err != nil {
 			t.Errorf("unexpected error got %+v", err)
 		}
@@ -0,0 +1,74 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// Observer is notified as steps and their substeps run, such as to export
+// metrics. Substeps skipped because they already completed are not observed.
+// PointOfNoReturn is called when the step asks to pass its point of no return
+// before the substep, with the error if it may not. Observers are called
+// synchronously and must not block.
+type Observer interface {
+	StepStarted(step idl.Step)
+	SubstepStarted(step idl.Step, substep idl.Substep)
+	SubstepFinished(step idl.Step, substep idl.Substep, status idl.Status, duration time.Duration, err error)
+	PointOfNoReturn(step idl.Step, substep idl.Substep, err error)
+	StepFinished(step idl.Step, err error)
+}
+
+// ContextObserver is an Observer that also derives the context of each step
+// and substep, such as to carry their trace spans. The context of a substep is
+// derived from that of its step and given to the substep through its streams.
+type ContextObserver interface {
+	Observer
+	StepContext(ctx context.Context, step idl.Step) context.Context
+	SubstepContext(ctx context.Context, step idl.Step, substep idl.Substep) context.Context
+}
+
+var observersMu sync.Mutex
+var observers []Observer
+
+// AddObserver registers an observer for all steps begun after it is added.
+func AddObserver(observer Observer) {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+
+	observers = append(observers, observer)
+}
+
+// RemoveObserver unregisters the observer from steps begun after it is
+// removed. Steps already running still notify it.
+func RemoveObserver(observer Observer) {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+
+	for i, o := range observers {
+		if o == observer {
+			observers = append(observers[:i:i], observers[i+1:]...)
+			return
+		}
+	}
+}
+
+// ResetObservers removes all observers.
+func ResetObservers() {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+
+	observers = nil
+}
+
+func currentObservers() []Observer {
+	observersMu.Lock()
+	defer observersMu.Unlock()
+
+	return append([]Observer(nil), observers...)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+)
+
+type recordingObserver struct {
+	events []string
+}
+
+func (r *recordingObserver) StepStarted(step idl.Step) {
+	r.events = append(r.events, fmt.Sprintf("start %s", step))
+}
+
+func (r *recordingObserver) SubstepStarted(step idl.Step, substep idl.Substep) {
+	r.events = append(r.events, fmt.Sprintf("start %s %s", step, substep))
+}
+
+func (r *recordingObserver) SubstepFinished(step idl.Step, substep idl.Substep, status idl.Status, duration time.Duration, err error) {
+	r.events = append(r.events, fmt.Sprintf("finish %s %s %s %v", step, substep, status, err))
+}
+
+func (r *recordingObserver) PointOfNoReturn(step idl.Step, substep idl.Substep, err error) {
+	r.events = append(r.events, fmt.Sprintf("point of no return %s %s %v", step, substep, err))
+}
+
+func (r *recordingObserver) StepFinished(step idl.Step, err error) {
+	r.events = append(r.events, fmt.Sprintf("finish %s %v", step, err != nil))
+}
+
+type contextKey struct{}
+
+// contextObserver adds the names of the step and substep to their contexts.
+type contextObserver struct {
+	recordingObserver
+}
+
+func (c *contextObserver) StepContext(ctx context.Context, step idl.Step) context.Context {
+	return context.WithValue(ctx, contextKey{}, step.String())
+}
+
+func (c *contextObserver) SubstepContext(ctx context.Context, step idl.Step, substep idl.Substep) context.Context {
+	return context.WithValue(ctx, contextKey{}, ctx.Value(contextKey{}).(string)+" "+substep.String())
+}
+
+type substepStatuses map[idl.Substep]idl.Status
+
+func (s substepStatuses) Read(_ idl.Step, substep idl.Substep) (idl.Status, error) {
+	return s[substep], nil
+}
+
+func (s substepStatuses) Write(_ idl.Step, substep idl.Substep, status idl.Status) error {
+	s[substep] = status
+	return nil
+}
+
+type discardSender struct{}
+
+func (discardSender) Send(*idl.Message) error { return nil }
+
+func TestObserver(t *testing.T) {
+	testlog.SetupLogger()
+
+	observer := &recordingObserver{}
+	step.AddObserver(observer)
+	defer step.ResetObservers()
+
+	s := step.New(idl.Step_EXECUTE, discardSender{}, substepStatuses{}, &testutils.DevNullWithClose{})
+
+	s.Run(idl.Substep_SHUTDOWN_SOURCE_CLUSTER, func(streams step.OutStreams) error {
+		return nil
+	})
+
+	s.Run(idl.Substep_UPGRADE_MASTER, func(streams step.OutStreams) error {
+		return step.Skip
+	})
+
+	s.Run(idl.Substep_UPGRADE_PRIMARIES, func(streams step.OutStreams) error {
+		return errors.New("oops")
+	})
+
+	// Substeps after a failure do not run and are not observed.
+	s.Run(idl.Substep_START_TARGET_CLUSTER, func(streams step.OutStreams) error {
+		return nil
+	})
+
+	if err := s.Finish(); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	expected := []string{
+		"start EXECUTE",
+		"start EXECUTE SHUTDOWN_SOURCE_CLUSTER",
+		"finish EXECUTE SHUTDOWN_SOURCE_CLUSTER COMPLETE <nil>",
+		"start EXECUTE UPGRADE_MASTER",
+		"finish EXECUTE UPGRADE_MASTER SKIPPED <nil>",
+		"start EXECUTE UPGRADE_PRIMARIES",
+		"finish EXECUTE UPGRADE_PRIMARIES FAILED oops",
+		"finish EXECUTE true",
+	}
+
+	if !reflect.DeepEqual(observer.events, expected) {
+		t.Errorf("got events %q want %q", observer.events, expected)
+	}
+}
+
+func TestRemoveObserver(t *testing.T) {
+	testlog.SetupLogger()
+
+	removed := &recordingObserver{}
+	kept := &recordingObserver{}
+	step.AddObserver(removed)
+	step.AddObserver(kept)
+	defer step.ResetObservers()
+
+	step.RemoveObserver(removed)
+
+	s := step.New(idl.Step_EXECUTE, discardSender{}, substepStatuses{}, &testutils.DevNullWithClose{})
+	if err := s.Finish(); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	if len(removed.events) != 0 {
+		t.Errorf("removed observer got events %q", removed.events)
+	}
+
+	expected := []string{"start EXECUTE", "finish EXECUTE false"}
+	if !reflect.DeepEqual(kept.events, expected) {
+		t.Errorf("got events %q want %q", kept.events, expected)
+	}
+}
+
+func TestObserverPointOfNoReturn(t *testing.T) {
+	testlog.SetupLogger()
+
+	observer := &recordingObserver{}
+	step.AddObserver(observer)
+	defer step.ResetObservers()
+
+	irreversible := []idl.Substep{idl.Substep_UPGRADE_MIRRORS}
+
+	t.Run("observes passing the point of no return", func(t *testing.T) {
+		observer.events = nil
+
+		s := step.New(idl.Step_FINALIZE, discardSender{}, substepStatuses{}, &testutils.DevNullWithClose{})
+		s.SetPointOfNoReturn(irreversible, func(idl.Substep) error {
+			return nil
+		})
+
+		s.Run(idl.Substep_UPGRADE_MIRRORS, func(streams step.OutStreams) error {
+			return nil
+		})
+
+		expected := []string{
+			"start FINALIZE",
+			"point of no return FINALIZE UPGRADE_MIRRORS <nil>",
+			"start FINALIZE UPGRADE_MIRRORS",
+			"finish FINALIZE UPGRADE_MIRRORS COMPLETE <nil>",
+		}
+
+		if !reflect.DeepEqual(observer.events, expected) {
+			t.Errorf("got events %q want %q", observer.events, expected)
+		}
+	})
+
+	t.Run("observes stopping at the point of no return", func(t *testing.T) {
+		observer.events = nil
+
+		s := step.New(idl.Step_FINALIZE, discardSender{}, substepStatuses{}, &testutils.DevNullWithClose{})
+		s.SetPointOfNoReturn(irreversible, func(idl.Substep) error {
+			return errors.New("not acknowledged")
+		})
+
+		s.Run(idl.Substep_UPGRADE_MIRRORS, func(streams step.OutStreams) error {
+			t.Errorf("expected substep to not run")
+			return nil
+		})
+
+		expected := []string{
+			"start FINALIZE",
+			"point of no return FINALIZE UPGRADE_MIRRORS not acknowledged",
+		}
+
+		if !reflect.DeepEqual(observer.events, expected) {
+			t.Errorf("got events %q want %q", observer.events, expected)
+		}
+	})
+}
+
+func TestContextObserver(t *testing.T) {
+	testlog.SetupLogger()
+
+	step.AddObserver(&contextObserver{})
+	defer step.ResetObservers()
+
+	s := step.New(idl.Step_EXECUTE, discardSender{}, substepStatuses{}, &testutils.DevNullWithClose{})
+
+	var got []interface{}
+	for _, substep := range []idl.Substep{idl.Substep_SHUTDOWN_SOURCE_CLUSTER, idl.Substep_UPGRADE_MASTER} {
+		s.Run(substep, func(streams step.OutStreams) error {
+			got = append(got, step.Context(streams).Value(contextKey{}))
+			return nil
+		})
+	}
+
+	expected := []interface{}{"EXECUTE SHUTDOWN_SOURCE_CLUSTER", "EXECUTE UPGRADE_MASTER"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("got substep contexts %q want %q", got, expected)
+	}
+
+	if ctx := step.Context(step.DevNullStream); ctx != context.Background() {
+		t.Errorf("got context %v for streams not given to a substep want the background context", ctx)
+	}
+}
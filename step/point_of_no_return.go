@@ -0,0 +1,71 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"fmt"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// PointOfNoReturnErr is returned when a step stops before its first
+// irreversible substep because the user has not acknowledged passing the
+// point of no return. Confirmation is the token acknowledging it.
+type PointOfNoReturnErr struct {
+	Substep      idl.Substep
+	Confirmation string
+}
+
+func (e PointOfNoReturnErr) Error() string {
+	return fmt.Sprintf("%s cannot be reverted and requires acknowledgement to proceed", e.Substep)
+}
+
+type pointOfNoReturn struct {
+	substeps    map[idl.Substep]bool
+	acknowledge func(idl.Substep) error
+	passed      bool
+}
+
+// SetPointOfNoReturn marks the substeps that cannot be reverted. Before the
+// first of them runs acknowledge is called, and the step stops with its error
+// rather than running the substep. Once any of the substeps has run the step
+// has passed its point of no return and acknowledge is no longer called.
+func (s *Step) SetPointOfNoReturn(substeps []idl.Substep, acknowledge func(idl.Substep) error) {
+	irreversible := make(map[idl.Substep]bool)
+	for _, substep := range substeps {
+		irreversible[substep] = true
+	}
+
+	s.pointOfNoReturn = &pointOfNoReturn{substeps: irreversible, acknowledge: acknowledge}
+}
+
+func (s *Step) acknowledgePointOfNoReturn(substep idl.Substep) error {
+	p := s.pointOfNoReturn
+	if p == nil || p.passed || !p.substeps[substep] {
+		return nil
+	}
+
+	for irreversible := range p.substeps {
+		status, err := s.substepStore.Read(s.name, irreversible)
+		if err != nil {
+			return err
+		}
+
+		if status != idl.Status_UNKNOWN_STATUS {
+			p.passed = true
+			return nil
+		}
+	}
+
+	err := p.acknowledge(substep)
+	for _, o := range s.observers {
+		o.PointOfNoReturn(s.name, substep, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	p.passed = true
+	return nil
+}
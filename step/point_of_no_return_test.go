@@ -0,0 +1,150 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"google.golang.org/grpc/status"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/idl/mock_idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+func TestPointOfNoReturn(t *testing.T) {
+	testlog.SetupLogger()
+
+	tmpDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, tmpDir)
+
+	path := filepath.Join(tmpDir, step.SubstepsFileName)
+	store := step.NewSubstepStoreUsingFile(path)
+
+	const finalize = idl.Step_FINALIZE
+	irreversible := []idl.Substep{idl.Substep_POINT_OF_NO_RETURN, idl.Substep_START_TARGET_CLUSTER}
+
+	run := func(t *testing.T, acknowledge func(idl.Substep) error) (*step.Step, []idl.Substep) {
+		t.Helper()
+
+		ctrl := gomock.NewController(t)
+		t.Cleanup(ctrl.Finish)
+
+		server := mock_idl.NewMockCliToHub_ExecuteServer(ctrl)
+		server.EXPECT().Send(gomock.Any()).AnyTimes()
+
+		s := step.New(finalize, server, store, &testutils.DevNullWithClose{})
+		s.SetPointOfNoReturn(irreversible, acknowledge)
+
+		var ran []idl.Substep
+		for _, substep := range []idl.Substep{idl.Substep_UPDATE_DATA_DIRECTORIES, idl.Substep_POINT_OF_NO_RETURN, idl.Substep_START_TARGET_CLUSTER} {
+			substep := substep
+			s.Run(substep, func(streams step.OutStreams) error {
+				ran = append(ran, substep)
+				return nil
+			})
+		}
+
+		return s, ran
+	}
+
+	t.Run("stops before the first irreversible substep when not acknowledged", func(t *testing.T) {
+		clear(t, path)
+
+		var acknowledged []idl.Substep
+		expected := step.PointOfNoReturnErr{Substep: idl.Substep_POINT_OF_NO_RETURN, Confirmation: "token"}
+		s, ran := run(t, func(substep idl.Substep) error {
+			acknowledged = append(acknowledged, substep)
+			return utils.NewNextActionErr(expected, "rerun with the token")
+		})
+
+		if len(ran) != 1 || ran[0] != idl.Substep_UPDATE_DATA_DIRECTORIES {
+			t.Errorf("ran substeps %v want only %v", ran, idl.Substep_UPDATE_DATA_DIRECTORIES)
+		}
+
+		if len(acknowledged) != 1 || acknowledged[0] != idl.Substep_POINT_OF_NO_RETURN {
+			t.Errorf("acknowledged substeps %v want only %v", acknowledged, idl.Substep_POINT_OF_NO_RETURN)
+		}
+
+		substepStatus, err := store.Read(finalize, idl.Substep_POINT_OF_NO_RETURN)
+		if err != nil {
+			t.Fatalf("Read() returned error %+v", err)
+		}
+
+		if substepStatus != idl.Status_UNKNOWN_STATUS {
+			t.Errorf("got status %s want %s", substepStatus, idl.Status_UNKNOWN_STATUS)
+		}
+
+		statusErr, ok := status.FromError(s.Err())
+		if !ok {
+			t.Fatalf("got error %#v want a gRPC status", s.Err())
+		}
+
+		var found bool
+		for _, detail := range statusErr.Details() {
+			if msg, ok := detail.(*idl.PointOfNoReturn); ok {
+				found = true
+				if msg.GetSubstep() != expected.Substep || msg.GetConfirmation() != expected.Confirmation {
+					t.Errorf("got details %v want %v", msg, expected)
+				}
+			}
+		}
+
+		if !found {
+			t.Errorf("expected details %v to contain PointOfNoReturn", statusErr.Details())
+		}
+	})
+
+	t.Run("runs the irreversible substeps once acknowledged", func(t *testing.T) {
+		clear(t, path)
+
+		var acknowledged []idl.Substep
+		s, ran := run(t, func(substep idl.Substep) error {
+			acknowledged = append(acknowledged, substep)
+			return nil
+		})
+
+		if s.Err() != nil {
+			t.Errorf("unexpected error %+v", s.Err())
+		}
+
+		if len(ran) != 3 {
+			t.Errorf("ran substeps %v want 3", ran)
+		}
+
+		if len(acknowledged) != 1 {
+			t.Errorf("acknowledged substeps %v want only one", acknowledged)
+		}
+	})
+
+	t.Run("does not require acknowledgement once the point of no return has passed", func(t *testing.T) {
+		clear(t, path)
+
+		if err := store.Write(finalize, idl.Substep_POINT_OF_NO_RETURN, idl.Status_COMPLETE); err != nil {
+			t.Fatalf("Write() returned error %+v", err)
+		}
+
+		if err := store.Write(finalize, idl.Substep_START_TARGET_CLUSTER, idl.Status_FAILED); err != nil {
+			t.Fatalf("Write() returned error %+v", err)
+		}
+
+		s, ran := run(t, func(substep idl.Substep) error {
+			t.Errorf("unexpected acknowledgement of %s", substep)
+			return nil
+		})
+
+		if s.Err() != nil {
+			t.Errorf("unexpected error %+v", s.Err())
+		}
+
+		if len(ran) != 2 || ran[1] != idl.Substep_START_TARGET_CLUSTER {
+			t.Errorf("ran substeps %v want %v to be retried", ran, idl.Substep_START_TARGET_CLUSTER)
+		}
+	})
+}
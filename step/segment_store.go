@@ -0,0 +1,145 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"sync"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+const SegmentsFileName = "segments.json"
+
+// SegmentFileStore persists the status of each segment, by dbid, within
+// substeps that operate on many segments. This allows a rerun of a failed
+// substep to only redo the segments that did not complete.
+type SegmentFileStore struct {
+	mutex sync.Mutex
+	path  string
+}
+
+func NewSegmentFileStore() (*SegmentFileStore, error) {
+	path, err := utils.GetJSONFile(utils.GetStateDir(), SegmentsFileName)
+	if err != nil {
+		return &SegmentFileStore{}, xerrors.Errorf("read %q: %w", SegmentsFileName, err)
+	}
+
+	return &SegmentFileStore{path: path}, nil
+}
+
+func NewSegmentStoreUsingFile(path string) *SegmentFileStore {
+	return &SegmentFileStore{path: path}
+}
+
+// SegmentStatus is the status of a segment along with the error of its last
+// failure.
+type SegmentStatus struct {
+	Status idl.Status
+	Error  string
+}
+
+type prettySegmentStatus struct {
+	Status PrettyStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// segmentsMap is keyed by step, substep, and dbid.
+type segmentsMap = map[string]map[string]map[string]prettySegmentStatus
+
+func (f *SegmentFileStore) load() (segmentsMap, error) {
+	data, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var segments segmentsMap
+	err = json.Unmarshal(data, &segments)
+	if err != nil {
+		return nil, err
+	}
+
+	return segments, nil
+}
+
+func (f *SegmentFileStore) save(segments segmentsMap) error {
+	data, err := json.MarshalIndent(segments, "", "  ") // pretty print JSON
+	if err != nil {
+		return err
+	}
+
+	return utils.AtomicallyWrite(f.path, data)
+}
+
+// Read returns the status of each segment by dbid for the substep. Segments
+// that have not been written are absent.
+func (f *SegmentFileStore) Read(step idl.Step, substep idl.Substep) (map[int]SegmentStatus, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	segments, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make(map[int]SegmentStatus)
+	for key, status := range segments[step.String()][substep.String()] {
+		dbid, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, xerrors.Errorf("parse dbid %q: %w", key, err)
+		}
+
+		statuses[dbid] = SegmentStatus{Status: status.Status.Status, Error: status.Error}
+	}
+
+	return statuses, nil
+}
+
+// Write atomically updates the status of the segment. It is safe to call
+// concurrently.
+func (f *SegmentFileStore) Write(step idl.Step, substep idl.Substep, dbid int, status SegmentStatus) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	segments, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := segments[step.String()]; !ok {
+		segments[step.String()] = make(map[string]map[string]prettySegmentStatus)
+	}
+
+	if _, ok := segments[step.String()][substep.String()]; !ok {
+		segments[step.String()][substep.String()] = make(map[string]prettySegmentStatus)
+	}
+
+	segments[step.String()][substep.String()][strconv.Itoa(dbid)] = prettySegmentStatus{Status: PrettyStatus{status.Status}, Error: status.Error}
+
+	return f.save(segments)
+}
+
+// Reset removes the status of all segments for the substep.
+func (f *SegmentFileStore) Reset(step idl.Step, substep idl.Substep) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	segments, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := segments[step.String()]; !ok {
+		return nil
+	}
+
+	delete(segments[step.String()], substep.String())
+
+	return f.save(segments)
+}
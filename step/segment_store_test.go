@@ -0,0 +1,122 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package step_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+)
+
+func TestSegmentFileStore(t *testing.T) {
+	tmpDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, tmpDir)
+
+	path := filepath.Join(tmpDir, step.SegmentsFileName)
+	store := step.NewSegmentStoreUsingFile(path)
+
+	const execute = idl.Step_EXECUTE
+	const substep = idl.Substep_UPGRADE_PRIMARIES
+
+	t.Run("returns no statuses when none have been written", func(t *testing.T) {
+		clear(t, path)
+
+		statuses, err := store.Read(execute, substep)
+		if err != nil {
+			t.Fatalf("Read() returned error %+v", err)
+		}
+
+		if len(statuses) != 0 {
+			t.Errorf("got %v want no statuses", statuses)
+		}
+	})
+
+	t.Run("reads the statuses that were written concurrently", func(t *testing.T) {
+		clear(t, path)
+
+		expected := map[int]step.SegmentStatus{}
+		var wg sync.WaitGroup
+		for dbid := 2; dbid < 12; dbid++ {
+			status := step.SegmentStatus{Status: idl.Status_COMPLETE}
+			if dbid%3 == 0 {
+				status = step.SegmentStatus{Status: idl.Status_FAILED, Error: "permission denied"}
+			}
+			expected[dbid] = status
+
+			wg.Add(1)
+			go func(dbid int, status step.SegmentStatus) {
+				defer wg.Done()
+				if err := store.Write(execute, substep, dbid, status); err != nil {
+					t.Errorf("Write() returned error %+v", err)
+				}
+			}(dbid, status)
+		}
+		wg.Wait()
+
+		statuses, err := store.Read(execute, substep)
+		if err != nil {
+			t.Fatalf("Read() returned error %+v", err)
+		}
+
+		if !reflect.DeepEqual(statuses, expected) {
+			t.Errorf("got %v want %v", statuses, expected)
+		}
+	})
+
+	t.Run("reset only removes the statuses of the substep", func(t *testing.T) {
+		clear(t, path)
+
+		if err := store.Write(execute, substep, 2, step.SegmentStatus{Status: idl.Status_COMPLETE}); err != nil {
+			t.Fatalf("Write() returned error %+v", err)
+		}
+
+		if err := store.Write(idl.Step_INITIALIZE, substep, 2, step.SegmentStatus{Status: idl.Status_COMPLETE}); err != nil {
+			t.Fatalf("Write() returned error %+v", err)
+		}
+
+		if err := store.Reset(execute, substep); err != nil {
+			t.Fatalf("Reset() returned error %+v", err)
+		}
+
+		statuses, err := store.Read(execute, substep)
+		if err != nil {
+			t.Fatalf("Read() returned error %+v", err)
+		}
+
+		if len(statuses) != 0 {
+			t.Errorf("got %v want no statuses", statuses)
+		}
+
+		statuses, err = store.Read(idl.Step_INITIALIZE, substep)
+		if err != nil {
+			t.Fatalf("Read() returned error %+v", err)
+		}
+
+		expected := map[int]step.SegmentStatus{2: {Status: idl.Status_COMPLETE}}
+		if !reflect.DeepEqual(statuses, expected) {
+			t.Errorf("got %v want %v", statuses, expected)
+		}
+	})
+
+	t.Run("uses human-readable serialization", func(t *testing.T) {
+		clear(t, path)
+
+		if err := store.Write(execute, substep, 3, step.SegmentStatus{Status: idl.Status_FAILED, Error: "disk full"}); err != nil {
+			t.Fatalf("Write() returned error %+v", err)
+		}
+
+		contents := testutils.MustReadFile(t, path)
+		for _, expected := range []string{`"EXECUTE"`, `"UPGRADE_PRIMARIES"`, `"status": "FAILED"`, `"error": "disk full"`} {
+			if !strings.Contains(contents, expected) {
+				t.Errorf("got %q want it to contain %q", contents, expected)
+			}
+		}
+	})
+}
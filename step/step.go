@@ -4,15 +4,19 @@
 package step
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/golang/protobuf/proto"
 	"github.com/greenplum-db/gp-common-go-libs/operating"
 	"golang.org/x/xerrors"
 	"google.golang.org/grpc/codes"
@@ -21,6 +25,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/utils"
 	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
 	"github.com/greenplum-db/gpupgrade/utils/stopwatch"
 )
 
@@ -32,18 +37,46 @@ type Step struct {
 	substepStore SubstepStore      // persistent substep status storage
 	streams      OutStreamsCloser  // writes substep stdout/err
 	err          error
+	observers    []Observer
+	ctx          context.Context // carries the context of the step to its substeps
+
+	logMu  sync.Mutex
+	logCtx context.Context // the context of the running substep, or else the step
+
+	pointOfNoReturn *pointOfNoReturn
 }
 
 func New(name idl.Step, sender idl.MessageSender, substepStore SubstepStore, streams OutStreamsCloser) *Step {
-	return &Step{
+	return newStep(context.Background(), name, sender, substepStore, streams)
+}
+
+func newStep(ctx context.Context, name idl.Step, sender idl.MessageSender, substepStore SubstepStore, streams OutStreamsCloser) *Step {
+	s := &Step{
 		name:         name,
 		sender:       sender,
 		substepStore: substepStore,
 		streams:      streams,
+		observers:    currentObservers(),
+	}
+
+	for _, o := range s.observers {
+		if c, ok := o.(ContextObserver); ok {
+			ctx = c.StepContext(ctx, name)
+		}
+	}
+	s.ctx = ctx
+	s.logCtx = ctx
+
+	for _, o := range s.observers {
+		o.StepStarted(name)
 	}
+
+	return s
 }
 
-func Begin(step idl.Step, sender idl.MessageSender, agentConns func() ([]*idl.Connection, error)) (*Step, error) {
+// Begin begins the step with the context of its call, which is given to its
+// substeps through their streams.
+func Begin(ctx context.Context, step idl.Step, sender idl.MessageSender, agentConns func() ([]*idl.Connection, error)) (*Step, error) {
 	// FIXME: Having s.agentConns() in the step framework is a heavy indication of
 	//  tech debt that needs to be addressed. However, for the time being ensure
 	//  agentConns are properly populated at the start of each step, otherwise
@@ -65,26 +98,63 @@ func Begin(step idl.Step, sender idl.MessageSender, agentConns func() ([]*idl.Co
 		return nil, err
 	}
 
-	path := filepath.Join(logdir, fmt.Sprintf("%s_%s.log", strings.ToLower(step.String()), operating.System.Now().Format("20060102")))
-	log, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	ext := ".log"
+	if log.CurrentFormat() == log.JSON {
+		ext = ".jsonl"
+	}
+
+	path := filepath.Join(logdir, fmt.Sprintf("%s_%s%s", strings.ToLower(step.String()), operating.System.Now().Format("20060102"), ext))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
 	if err != nil {
 		return nil, xerrors.Errorf(`step "%s": %w`, step, err)
 	}
 
-	_, err = fmt.Fprintf(log, "\n%s in progress.\n", cases.Title(language.English).String(step.String()))
-	if err != nil {
-		log.Close()
-		return nil, xerrors.Errorf(`logging step "%s": %w`, step, err)
+	// In the JSON format each line of substep output is a log entry with the
+	// fields of the running substep.
+	var s *Step
+	var stepLog io.WriteCloser = file
+	if log.CurrentFormat() == log.JSON {
+		stepLog = log.NewJSONLineWriter(file, "gpupgrade_hub", func() log.Fields {
+			return s.logFields()
+		})
 	}
 
 	substepStore, err := NewSubstepFileStore()
 	if err != nil {
+		stepLog.Close()
 		return nil, err
 	}
 
-	streams := newMultiplexedStream(sender, log)
+	streams := newMultiplexedStream(sender, stepLog)
+	s = newStep(ctx, step, sender, substepStore, streams)
+
+	_, err = fmt.Fprintf(stepLog, "\n%s in progress.\n", cases.Title(language.English).String(step.String()))
+	if err != nil {
+		s.err = xerrors.Errorf(`logging step "%s": %w`, step, err)
+		if fErr := s.Finish(); fErr != nil {
+			return nil, errorlist.Append(s.err, fErr)
+		}
+
+		return nil, s.err
+	}
+
+	return s, nil
+}
 
-	return New(step, sender, substepStore, streams), nil
+// logFields returns the fields of the running substep, or else the step, for
+// the JSON step log.
+func (s *Step) logFields() log.Fields {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	return log.ContextFields(s.logCtx)
+}
+
+func (s *Step) setLogContext(ctx context.Context) {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	s.logCtx = ctx
 }
 
 func HasStarted(step idl.Step) (bool, error) {
@@ -136,6 +206,10 @@ func (s *Step) Streams() OutStreams {
 }
 
 func (s *Step) Finish() error {
+	for _, o := range s.observers {
+		o.StepFinished(s.name, s.err)
+	}
+
 	if err := s.streams.Close(); err != nil {
 		return xerrors.Errorf(`step "%s": %w`, s.name, err)
 	}
@@ -148,14 +222,45 @@ func (s *Step) Err() error {
 		return nil
 	}
 
+	text := NextActions(s.err)
+
+	var details []proto.Message
+	if text != "" {
+		details = append(details, &idl.NextActions{NextActions: text})
+	}
+
+	var pointOfNoReturnErr PointOfNoReturnErr
+	if errors.As(s.err, &pointOfNoReturnErr) {
+		details = append(details, &idl.PointOfNoReturn{
+			Substep:      pointOfNoReturnErr.Substep,
+			Confirmation: pointOfNoReturnErr.Confirmation,
+		})
+	}
+
+	if len(details) == 0 {
+		return s.err
+	}
+
+	statusErr := status.New(codes.Internal, s.err.Error())
+	statusErr, err := statusErr.WithDetails(details...)
+	if err != nil {
+		return s.err
+	}
+
+	return statusErr.Err()
+}
+
+// NextActions returns the actions suggested to the user by the error, or by
+// each of the errors in an errorlist, separated by newlines.
+func NextActions(err error) string {
 	text := ""
 	var nextActionErr utils.NextActionErr
-	if errors.As(s.err, &nextActionErr) {
+	if errors.As(err, &nextActionErr) {
 		text += nextActionErr.NextAction
 	}
 
 	var errs errorlist.Errors
-	if errors.As(s.err, &errs) {
+	if errors.As(err, &errs) {
 		var nextActions []string
 		for _, err := range errs {
 			if errors.As(err, &nextActionErr) {
@@ -166,17 +271,7 @@ func (s *Step) Err() error {
 		text = strings.Join(nextActions, "\n")
 	}
 
-	if text == "" {
-		return s.err
-	}
-
-	statusErr := status.New(codes.Internal, s.err.Error())
-	statusErr, err := statusErr.WithDetails(&idl.NextActions{NextActions: text})
-	if err != nil {
-		return s.err
-	}
-
-	return statusErr.Err()
+	return text
 }
 
 func (s *Step) RunInternalSubstep(f func() error) {
@@ -196,7 +291,7 @@ func (s *Step) AlwaysRun(substep idl.Substep, f func(OutStreams) error) {
 
 func (s *Step) RunConditionally(substep idl.Substep, shouldRun bool, f func(OutStreams) error) {
 	if !shouldRun {
-		gplog.Debug("skipping %s", substep)
+		log.Debug(s.ctx, "skipping %s", substep)
 		return
 	}
 
@@ -238,11 +333,22 @@ func (s *Step) run(substep idl.Substep, f func(OutStreams) error, alwaysRun bool
 		return
 	}
 
+	err = s.acknowledgePointOfNoReturn(substep)
+	if err != nil {
+		return
+	}
+
 	timer := stopwatch.Start()
+	started := false
+	finalStatus := idl.Status_FAILED
 	defer func() {
 		if pErr := s.printDuration(substep, timer.Stop()); pErr != nil {
 			err = errorlist.Append(err, pErr)
 		}
+
+		if started {
+			s.substepFinished(substep, finalStatus, timer.Elapsed(), err)
+		}
 	}()
 
 	_, err = fmt.Fprintf(s.streams.Stdout(), "\nStarting %s...\n\n", substep)
@@ -255,12 +361,29 @@ func (s *Step) run(substep idl.Substep, f func(OutStreams) error, alwaysRun bool
 		return
 	}
 
-	err = f(s.streams)
+	started = true
+	ctx := s.ctx
+	for _, o := range s.observers {
+		if c, ok := o.(ContextObserver); ok {
+			ctx = c.SubstepContext(ctx, s.name, substep)
+		}
+	}
+
+	for _, o := range s.observers {
+		o.SubstepStarted(s.name, substep)
+	}
+
+	s.setLogContext(ctx)
+	err = f(contextStreams{OutStreams: s.streams, ctx: ctx})
+	s.setLogContext(s.ctx)
 
 	switch {
 	case errors.Is(err, Skip):
 		// The substep has requested a manual skip; this isn't really an error.
 		err = s.write(substep, idl.Status_SKIPPED)
+		if err == nil {
+			finalStatus = idl.Status_SKIPPED
+		}
 		return
 
 	case err != nil:
@@ -271,6 +394,15 @@ func (s *Step) run(substep idl.Substep, f func(OutStreams) error, alwaysRun bool
 	}
 
 	err = s.write(substep, idl.Status_COMPLETE)
+	if err == nil {
+		finalStatus = idl.Status_COMPLETE
+	}
+}
+
+func (s *Step) substepFinished(substep idl.Substep, status idl.Status, duration time.Duration, err error) {
+	for _, o := range s.observers {
+		o.SubstepFinished(s.name, substep, status, duration, err)
+	}
 }
 
 func (s *Step) write(substep idl.Substep, status idl.Status) error {
@@ -5,6 +5,7 @@ package step
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"io/ioutil"
 	"os"
@@ -25,6 +26,23 @@ type OutStreamsCloser interface {
 	Close() error
 }
 
+// contextStreams are the streams given to a substep, which carry its context.
+type contextStreams struct {
+	OutStreams
+	ctx context.Context
+}
+
+// Context returns the context of the substep given the streams, such as for
+// its agent calls and commands to carry its trace span. Other streams carry
+// the background context.
+func Context(streams OutStreams) context.Context {
+	if s, ok := streams.(contextStreams); ok {
+		return s.ctx
+	}
+
+	return context.Background()
+}
+
 // DevNullStream provides an implementation of OutStreams that drops
 //   all writes to it.
 var DevNullStream = devNullStream{}
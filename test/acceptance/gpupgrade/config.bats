@@ -46,9 +46,19 @@ teardown() {
 @test "configuration can be dumped as a whole" {
     run gpupgrade config show
     [ "$status" -eq 0 ]
-    [[ "${lines[0]}" = "id - "* ]] # this is randomly generated; we could replace * with a base64 regex matcher
-    [ "${lines[1]}" = "source-gphome - $GPHOME_SOURCE" ]
-    [[ "${lines[2]}" = "target-datadir - "* ]]
-    [ "${lines[3]}" = "target-gphome - $GPHOME_TARGET" ]
-    [ "${lines[4]}" = "target-port - $TARGET_PGPORT" ]
+    [[ "${lines[0]}" = "Upgrade ID "* ]] # this is randomly generated; we could replace * with a base64 regex matcher
+    [[ "$output" = *"Source Cluster"*"GPHOME   $GPHOME_SOURCE"* ]]
+    [[ "$output" = *"Intermediate Cluster"*"GPHOME   $GPHOME_TARGET"* ]]
+
+    run gpupgrade config show --format json
+    [ "$status" -eq 0 ]
+    [ "$(echo "$output" | jq -r .source.gphome)" = "$GPHOME_SOURCE" ]
+    [ "$(echo "$output" | jq -r '.intermediate.segments[0].port')" = "$TARGET_PGPORT" ]
+}
+
+@test "settings can be combined" {
+    run gpupgrade config show --source-gphome --target-port
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "source-gphome - $GPHOME_SOURCE" ]
+    [ "${lines[1]}" = "target-port - $TARGET_PGPORT" ]
 }
@@ -67,13 +67,13 @@ upgrade_cluster() {
             --mode "$MODE" \
             "$HBA_HOSTNAMES" \
             --verbose 3>&-
-        gpupgrade execute --non-interactive --verbose
+        gpupgrade execute --non-interactive --verbose --confirm-point-of-no-return "$(gpupgrade config show --point-of-no-return-token)"
 
         # do before gpupgrade finalize shuts down the hub
         local upgradeID
         upgradeID=$(gpupgrade config show --id)
 
-        gpupgrade finalize --non-interactive --verbose
+        gpupgrade finalize --non-interactive --verbose --confirm-point-of-no-return "$(gpupgrade config show --point-of-no-return-token)"
 
         # unset LD_LIBRARY_PATH due to https://web.archive.org/web/20220506055918/https://groups.google.com/a/greenplum.org/g/gpdb-dev/c/JN-YwjCCReY/m/0L9wBOvlAQAJ
         (unset LD_LIBRARY_PATH; source "${GPHOME_TARGET}"/greenplum_path.sh && "${GPHOME_TARGET}"/bin/gpstart -a)
@@ -138,6 +138,31 @@ upgrade_cluster() {
     upgrade_cluster "link" "--use-hba-hostnames"
 }
 
+@test "gpupgrade finalize --non-interactive stops before the point of no return without the token" {
+    gpupgrade initialize \
+        --source-gphome="$GPHOME_SOURCE" \
+        --target-gphome="$GPHOME_TARGET" \
+        --source-master-port="${PGPORT}" \
+        --temp-port-range 6020-6040 \
+        --disk-free-ratio 0 \
+        --mode copy \
+        --automatic \
+        --verbose 3>&-
+    register_teardown gpupgrade revert --non-interactive --verbose
+
+    gpupgrade execute --non-interactive --verbose
+
+    local token
+    token=$(gpupgrade config show --point-of-no-return-token)
+
+    run gpupgrade finalize --non-interactive --verbose
+    echo "$output"   # run swallows the output...log it explicitly to allow debugging.
+    [ "$status" -ne 0 ] || fail "expected finalize to stop before the point of no return"
+    [[ "$output" == *"POINT_OF_NO_RETURN cannot be reverted"* ]] || fail "expected output to report stopping before POINT_OF_NO_RETURN"
+    [[ "$output" == *"--confirm-point-of-no-return ${token}"* ]] || fail "expected output to contain the token shown by config show"
+    [[ "$output" != *"Passing point of no return..."* ]] || fail "expected finalize to not pass the point of no return"
+}
+
 get_standby_status() {
     local standby_status=$1
     echo "$standby_status" | grep 'Standby master state'
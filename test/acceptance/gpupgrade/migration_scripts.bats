@@ -81,7 +81,7 @@ teardown() {
         --automatic \
         --verbose
     gpupgrade execute --non-interactive --verbose
-    gpupgrade finalize --non-interactive --verbose
+    gpupgrade finalize --non-interactive --verbose --confirm-point-of-no-return "$(gpupgrade config show --point-of-no-return-token)"
 
     # unset LD_LIBRARY_PATH due to https://web.archive.org/web/20220506055918/https://groups.google.com/a/greenplum.org/g/gpdb-dev/c/JN-YwjCCReY/m/0L9wBOvlAQAJ
     (unset LD_LIBRARY_PATH; source "${GPHOME_TARGET}"/greenplum_path.sh && "${GPHOME_TARGET}"/bin/gpstart -a)
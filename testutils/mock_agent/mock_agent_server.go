@@ -88,6 +88,11 @@ func (m *MockAgentServer) RenameDirectories(context.Context, *idl.RenameDirector
 	return &idl.RenameDirectoriesReply{}, nil
 }
 
+func (m *MockAgentServer) UndoRenameDirectories(context.Context, *idl.RenameDirectoriesRequest) (*idl.RenameDirectoriesReply, error) {
+	m.increaseCalls()
+	return &idl.RenameDirectoriesReply{}, nil
+}
+
 func (m *MockAgentServer) DeleteDataDirectories(context.Context, *idl.DeleteDataDirectoriesRequest) (*idl.DeleteDataDirectoriesReply, error) {
 	m.increaseCalls()
 	return &idl.DeleteDataDirectoriesReply{}, nil
@@ -160,3 +165,31 @@ func (m *MockAgentServer) CreateRecoveryConf(context context.Context, in *idl.Cr
 func (m *MockAgentServer) AddReplicationEntries(context context.Context, in *idl.AddReplicationEntriesRequest) (*idl.AddReplicationEntriesReply, error) {
 	return &idl.AddReplicationEntriesReply{}, nil
 }
+
+func (m *MockAgentServer) Preflight(context context.Context, in *idl.PreflightRequest) (*idl.PreflightReply, error) {
+	return &idl.PreflightReply{}, nil
+}
+
+func (m *MockAgentServer) ResyncMirrorDataDirectories(context context.Context, in *idl.RsyncRequest) (*idl.ResyncReply, error) {
+	return &idl.ResyncReply{}, nil
+}
+
+func (m *MockAgentServer) CreateSnapshots(context context.Context, in *idl.CreateSnapshotsRequest) (*idl.CreateSnapshotsReply, error) {
+	return &idl.CreateSnapshotsReply{}, nil
+}
+
+func (m *MockAgentServer) RestoreSnapshots(context context.Context, in *idl.RestoreSnapshotsRequest) (*idl.RestoreSnapshotsReply, error) {
+	return &idl.RestoreSnapshotsReply{}, nil
+}
+
+func (m *MockAgentServer) DeleteSnapshots(context context.Context, in *idl.DeleteSnapshotsRequest) (*idl.DeleteSnapshotsReply, error) {
+	return &idl.DeleteSnapshotsReply{}, nil
+}
+
+func (m *MockAgentServer) GetPgUpgradeLogs(context context.Context, in *idl.GetPgUpgradeLogsRequest) (*idl.GetPgUpgradeLogsReply, error) {
+	return &idl.GetPgUpgradeLogsReply{}, nil
+}
+
+func (m *MockAgentServer) CreateSupportBundle(context context.Context, in *idl.CreateSupportBundleRequest) (*idl.CreateSupportBundleReply, error) {
+	return &idl.CreateSupportBundleReply{}, nil
+}
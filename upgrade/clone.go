@@ -0,0 +1,99 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+)
+
+var reflinkCmd = exec.Command
+
+// supportsClone returns whether the target pg_upgrade supports --clone, which
+// was added in PostgreSQL 12 and thus GPDB 7.
+func supportsClone(targetVersion string) bool {
+	return semver.MustParse(targetVersion).Major >= 7
+}
+
+// CloneDataDir returns the reflinked copy of the source data directory that
+// is upgraded in clone mode when the target pg_upgrade does not support
+// --clone.
+func CloneDataDir(dataDir string) string {
+	return filepath.Clean(dataDir) + ".gpupgrade-clone"
+}
+
+// checkCloneFallback checks that the source data directory can be upgraded by
+// linking to a reflinked copy of it. User defined tablespaces are outside the
+// data directory and would be linked rather than cloned, modifying the source.
+func checkCloneFallback(ctx context.Context, opts *idl.PgOptions) error {
+	for _, tablespace := range opts.GetTablespaces() {
+		if tablespace.GetUserDefined() {
+			return xerrors.Errorf("Clone mode cannot upgrade the user defined tablespace %q since pg_upgrade %s does not support --clone. Use copy or link mode instead.",
+				tablespace.GetName(), opts.GetTargetVersion())
+		}
+	}
+
+	// Probe for reflink support by cloning a small file next to the data
+	// directory.
+	probe := CloneDataDir(opts.GetOldDataDir()) + ".check"
+	err := reflink(ctx, filepath.Join(opts.GetOldDataDir(), "PG_VERSION"), probe)
+	if rErr := os.RemoveAll(probe); rErr != nil {
+		err = errorlist.Append(err, rErr)
+	}
+	if err != nil {
+		return xerrors.Errorf("Clone mode requires a filesystem that supports reflinks such as XFS or btrfs: %w", err)
+	}
+
+	return nil
+}
+
+// cloneDataDir reflinks the source data directory to CloneDataDir, removing
+// any partial clone from a previous attempt first.
+func cloneDataDir(ctx context.Context, dataDir string) (string, error) {
+	clone := CloneDataDir(dataDir)
+	if err := os.RemoveAll(clone); err != nil {
+		return "", err
+	}
+
+	if err := reflink(ctx, dataDir, clone); err != nil {
+		return "", xerrors.Errorf("cloning data directory %q: %w", dataDir, err)
+	}
+
+	return clone, nil
+}
+
+func reflink(ctx context.Context, src, dst string) error {
+	cmd := reflinkCmd("cp", "--archive", "--reflink=always", src, dst)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	gplog.Info(cmd.String())
+	if err := trace.Run(ctx, "cp", cmd); err != nil {
+		return xerrors.Errorf("%q: %w: %s", cmd.String(), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+func SetReflinkCommand(cmdFunc exectest.Command) {
+	reflinkCmd = cmdFunc
+}
+
+func ResetReflinkCommand() {
+	reflinkCmd = nil
+}
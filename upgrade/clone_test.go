@@ -0,0 +1,162 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade_test
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+func TestRunCloneMode(t *testing.T) {
+	testlog.SetupLogger()
+
+	dataDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dataDir)
+
+	clone := upgrade.CloneDataDir(dataDir)
+
+	// record captures each command as a single string.
+	record := func(calls *[]string) exectest.Command {
+		return exectest.NewCommandWithVerifier(upgrade.Success, func(name string, args ...string) {
+			*calls = append(*calls, strings.Join(append([]string{filepath.Base(name)}, args...), " "))
+		})
+	}
+
+	t.Run("upgrades a reflinked clone of the data directory in link mode when the target does not support --clone", func(t *testing.T) {
+		var cpCalls []string
+		upgrade.SetReflinkCommand(record(&cpCalls))
+		defer upgrade.ResetReflinkCommand()
+
+		var pgUpgradeCalls []string
+		upgrade.SetPgUpgradeCommand(record(&pgUpgradeCalls))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		testutils.MustCreateDir(t, clone)
+
+		opts := &idl.PgOptions{
+			Action:        idl.PgOptions_upgrade,
+			Role:          greenplum.PrimaryRole,
+			ContentID:     0,
+			UpgradeMode:   idl.Mode_clone,
+			TargetVersion: "6.20.0",
+			OldDataDir:    dataDir,
+		}
+
+		err := upgrade.Run(context.Background(), nil, nil, opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := []string{
+			"cp --archive --reflink=always " + filepath.Join(dataDir, "PG_VERSION") + " " + clone + ".check",
+			"cp --archive --reflink=always " + dataDir + " " + clone,
+		}
+		if !reflect.DeepEqual(cpCalls, expected) {
+			t.Errorf("got cp calls %q want %q", cpCalls, expected)
+		}
+
+		if len(pgUpgradeCalls) != 1 {
+			t.Fatalf("got pg_upgrade calls %q want one", pgUpgradeCalls)
+		}
+
+		if !strings.Contains(pgUpgradeCalls[0], "--old-datadir "+clone+" ") {
+			t.Errorf("expected %q to upgrade the clone %q", pgUpgradeCalls[0], clone)
+		}
+
+		if !strings.Contains(pgUpgradeCalls[0], " --link ") {
+			t.Errorf("expected %q to contain --link", pgUpgradeCalls[0])
+		}
+
+		testutils.PathMustNotExist(t, clone)
+	})
+
+	t.Run("checks the source data directory itself", func(t *testing.T) {
+		var cpCalls []string
+		upgrade.SetReflinkCommand(record(&cpCalls))
+		defer upgrade.ResetReflinkCommand()
+
+		var pgUpgradeCalls []string
+		upgrade.SetPgUpgradeCommand(record(&pgUpgradeCalls))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := &idl.PgOptions{
+			Action:        idl.PgOptions_check,
+			Role:          greenplum.PrimaryRole,
+			ContentID:     0,
+			UpgradeMode:   idl.Mode_clone,
+			TargetVersion: "6.20.0",
+			OldDataDir:    dataDir,
+		}
+
+		err := upgrade.Run(context.Background(), nil, nil, opts)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if len(cpCalls) != 1 {
+			t.Errorf("got cp calls %q want only the reflink probe", cpCalls)
+		}
+
+		if len(pgUpgradeCalls) != 1 || !strings.Contains(pgUpgradeCalls[0], "--old-datadir "+dataDir+" ") {
+			t.Errorf("got pg_upgrade calls %q want one checking %q", pgUpgradeCalls, dataDir)
+		}
+	})
+
+	t.Run("errors when the filesystem does not support reflinks", func(t *testing.T) {
+		upgrade.SetReflinkCommand(exectest.NewCommand(upgrade.Failure))
+		defer upgrade.ResetReflinkCommand()
+
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(upgrade.Success))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := &idl.PgOptions{
+			Action:        idl.PgOptions_check,
+			Role:          greenplum.PrimaryRole,
+			UpgradeMode:   idl.Mode_clone,
+			TargetVersion: "6.20.0",
+			OldDataDir:    dataDir,
+		}
+
+		err := upgrade.Run(context.Background(), nil, nil, opts)
+		expected := "Clone mode requires a filesystem that supports reflinks"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+
+	t.Run("errors when there are user defined tablespaces", func(t *testing.T) {
+		upgrade.SetReflinkCommand(exectest.NewCommand(upgrade.Success))
+		defer upgrade.ResetReflinkCommand()
+
+		upgrade.SetPgUpgradeCommand(exectest.NewCommand(upgrade.Success))
+		defer upgrade.ResetPgUpgradeCommand()
+
+		opts := &idl.PgOptions{
+			Action:        idl.PgOptions_check,
+			Role:          greenplum.PrimaryRole,
+			UpgradeMode:   idl.Mode_clone,
+			TargetVersion: "6.20.0",
+			OldDataDir:    dataDir,
+			Tablespaces: map[int32]*idl.TablespaceInfo{
+				16384: {Name: "batman", Location: "/tmp/batman/16384", UserDefined: true},
+			},
+		}
+
+		err := upgrade.Run(context.Background(), nil, nil, opts)
+		expected := `Clone mode cannot upgrade the user defined tablespace "batman"`
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
 
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/utils"
@@ -66,14 +67,48 @@ func GetArchiveDirectoryName(id ID, t time.Time) string {
 	return fmt.Sprintf("gpupgrade-%s-%s", id.String(), t.Format("2006-01-02T15:04"))
 }
 
+// IsRelocated reports whether the target data directory was placed in a
+// different directory than the source, such as on another mount point by a
+// target layout. A relocated target cannot be renamed over the source, so the
+// upgraded cluster keeps its target data directory.
+func IsRelocated(source, target string) bool {
+	return filepath.Dir(filepath.Clean(source)) != filepath.Dir(filepath.Clean(target))
+}
+
+// ArchiveDirectory returns where RenameDirectories archives the source data
+// directory.
+func ArchiveDirectory(source, target string) string {
+	if IsRelocated(source, target) {
+		return filepath.Clean(source) + OldSuffix
+	}
+
+	// Instead of manipulating the source to create the archive we append the
+	// old suffix to the target to achieve the same result.
+	return target + OldSuffix
+}
+
 // RenameDirectories archives the source directory, and renames
 // source to target. For example:
 //   source '/data/dbfast1/demoDataDir0' becomes archive '/data/dbfast1/demoDataDir.123ABC.0.old'
 //   target '/data/dbfast1/demoDataDir.123ABC.0' becomes source '/data/dbfast1/demoDataDir0'
+//
+// A relocated target stays in place and only the source is archived. For example:
+//   source '/data/dbfast1/demoDataDir0' becomes archive '/data/dbfast1/demoDataDir0.old'
 func RenameDirectories(source, target string) error {
-	// Instead of manipulating the source to create the archive we append the
-	// old suffix to the target to achieve the same result.
-	archive := target + OldSuffix
+	archive := ArchiveDirectory(source, target)
+
+	if IsRelocated(source, target) {
+		alreadyRenamed, err := AlreadyRenamed(source, archive)
+		if err != nil {
+			return err
+		}
+
+		if alreadyRenamed {
+			return nil
+		}
+
+		return renameDataDirectory(source, archive)
+	}
 
 	alreadyRenamed, err := AlreadyRenamed(target, archive)
 	if err != nil {
@@ -106,6 +141,48 @@ func RenameDirectories(source, target string) error {
 	return nil
 }
 
+// UndoRenameDirectories reverses RenameDirectories by moving the target back
+// out of the source directory and restoring the source from its archive. Each
+// rename is only done if still needed, so that a partially renamed or
+// partially restored directory can be reverted by re-running.
+func UndoRenameDirectories(source, target string) error {
+	archive := ArchiveDirectory(source, target)
+
+	if !IsRelocated(source, target) {
+		targetExist, err := PathExist(target)
+		if err != nil {
+			return err
+		}
+
+		if !targetExist {
+			sourceExist, err := PathExist(source)
+			if err != nil {
+				return err
+			}
+
+			if !sourceExist {
+				return xerrors.Errorf("Neither source directory %q nor target directory %q exist.", source, target)
+			}
+
+			if err := renameDataDirectory(source, target); err != nil {
+				return err
+			}
+		}
+	}
+
+	archiveExist, err := PathExist(archive)
+	if err != nil {
+		return err
+	}
+
+	if !archiveExist {
+		gplog.Debug("Archive directory %q not found when restoring %q. The source directory was either never archived or already restored.", archive, source)
+		return nil
+	}
+
+	return renameDataDirectory(archive, source)
+}
+
 func renameDataDirectory(src, dst string) error {
 	if err := VerifyDataDirectory(src); err != nil {
 		return err
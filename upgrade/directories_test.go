@@ -293,6 +293,202 @@ func TestArchiveSource(t *testing.T) {
 	})
 }
 
+func TestUndoRenameDirectories(t *testing.T) {
+	testlog.SetupLogger()
+
+	// mustCreateDataDirs marks the source and target so that it can be
+	// verified which directory ends up where.
+	mustCreateDataDirs := func(t *testing.T) (string, string, func(*testing.T)) {
+		source, target, cleanup := testutils.MustCreateDataDirs(t)
+		testutils.MustWriteToFile(t, filepath.Join(source, "marker"), "source")
+		testutils.MustWriteToFile(t, filepath.Join(target, "marker"), "target")
+		return source, target, cleanup
+	}
+
+	verifyUndo := func(t *testing.T, source, target string) {
+		t.Helper()
+
+		testutils.PathMustNotExist(t, target+upgrade.OldSuffix)
+
+		if marker := testutils.MustReadFile(t, filepath.Join(source, "marker")); marker != "source" {
+			t.Errorf("got %q in source directory want %q", marker, "source")
+		}
+
+		if marker := testutils.MustReadFile(t, filepath.Join(target, "marker")); marker != "target" {
+			t.Errorf("got %q in target directory want %q", marker, "target")
+		}
+	}
+
+	t.Run("restores renamed directories", func(t *testing.T) {
+		source, target, cleanup := mustCreateDataDirs(t)
+		defer cleanup(t)
+
+		err := upgrade.RenameDirectories(source, target)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		err = upgrade.UndoRenameDirectories(source, target)
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+
+		verifyUndo(t, source, target)
+	})
+
+	t.Run("does nothing when the directories were not renamed", func(t *testing.T) {
+		source, target, cleanup := mustCreateDataDirs(t)
+		defer cleanup(t)
+
+		err := upgrade.UndoRenameDirectories(source, target)
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+
+		verifyUndo(t, source, target)
+	})
+
+	t.Run("restores the source when only the source was archived", func(t *testing.T) {
+		source, target, cleanup := mustCreateDataDirs(t)
+		defer cleanup(t)
+
+		err := os.Rename(source, target+upgrade.OldSuffix)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		err = upgrade.UndoRenameDirectories(source, target)
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+
+		verifyUndo(t, source, target)
+	})
+
+	t.Run("moves the target back when the source was never archived", func(t *testing.T) {
+		source, target, cleanup := mustCreateDataDirs(t)
+		defer cleanup(t)
+		testutils.MustRemoveAll(t, source)
+
+		err := upgrade.RenameDirectories(source, target)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		err = upgrade.UndoRenameDirectories(source, target)
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+
+		testutils.PathMustNotExist(t, source)
+		testutils.PathMustExist(t, target)
+	})
+
+	t.Run("when restoring the source fails then a re-run succeeds", func(t *testing.T) {
+		source, target, cleanup := mustCreateDataDirs(t)
+		defer cleanup(t)
+
+		err := upgrade.RenameDirectories(source, target)
+		if err != nil {
+			t.Fatalf("unexpected error: %#v", err)
+		}
+
+		expected := errors.New("permission denied")
+		utils.System.Rename = func(old, new string) error {
+			if old == target+upgrade.OldSuffix {
+				return expected
+			}
+			return os.Rename(old, new)
+		}
+		defer func() {
+			utils.System.Rename = os.Rename
+		}()
+
+		err = upgrade.UndoRenameDirectories(source, target)
+		if !errors.Is(err, expected) {
+			t.Errorf("got %#v want %#v", err, expected)
+		}
+
+		testutils.PathMustNotExist(t, source)
+		testutils.PathMustExist(t, target)
+
+		utils.System.Rename = os.Rename
+
+		err = upgrade.UndoRenameDirectories(source, target)
+		if err != nil {
+			t.Errorf("unexpected error: %#v", err)
+		}
+
+		verifyUndo(t, source, target)
+	})
+
+	t.Run("errors when neither directory exists", func(t *testing.T) {
+		source, target, cleanup := mustCreateDataDirs(t)
+		defer cleanup(t)
+		testutils.MustRemoveAll(t, source)
+		testutils.MustRemoveAll(t, target)
+
+		err := upgrade.UndoRenameDirectories(source, target)
+		if err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func TestRenameRelocatedDirectories(t *testing.T) {
+	testlog.SetupLogger()
+
+	// mustCreateRelocatedDataDirs places the target in a subdirectory of the
+	// source parent, as when a layout puts it on another mount point.
+	mustCreateRelocatedDataDirs := func(t *testing.T) (string, string, func(*testing.T)) {
+		source, target, cleanup := testutils.MustCreateDataDirs(t)
+		relocated := filepath.Join(target, "mnt", "seg0")
+		testutils.MustCreateDir(t, relocated)
+		for _, f := range upgrade.PostgresFiles {
+			testutils.MustWriteToFile(t, filepath.Join(relocated, f), "")
+		}
+
+		return source, relocated, cleanup
+	}
+
+	t.Run("archives the source and leaves a relocated target in place", func(t *testing.T) {
+		source, target, cleanup := mustCreateRelocatedDataDirs(t)
+		defer cleanup(t)
+		defer testutils.MustRemoveAll(t, source+upgrade.OldSuffix)
+
+		if !upgrade.IsRelocated(source, target) {
+			t.Fatalf("expected %q to be relocated from %q", target, source)
+		}
+
+		archive := upgrade.ArchiveDirectory(source, target)
+		if archive != source+upgrade.OldSuffix {
+			t.Errorf("got archive %q want %q", archive, source+upgrade.OldSuffix)
+		}
+
+		for i := 0; i < 2; i++ {
+			err := upgrade.RenameDirectories(source, target)
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+
+			testutils.PathMustNotExist(t, source)
+			testutils.PathMustExist(t, archive)
+			testutils.PathMustExist(t, target)
+		}
+
+		for i := 0; i < 2; i++ {
+			err := upgrade.UndoRenameDirectories(source, target)
+			if err != nil {
+				t.Errorf("unexpected error: %#v", err)
+			}
+
+			testutils.PathMustExist(t, source)
+			testutils.PathMustNotExist(t, archive)
+			testutils.PathMustExist(t, target)
+		}
+	})
+}
+
 func setup(t *testing.T) (teardown func(), directories []string, requiredPaths []string) {
 	requiredPaths = []string{"pg_file1", "pg_file2"}
 	var dataDirectories = []string{"/data/dbfast_mirror1/seg1", "/data/dbfast_mirror2/seg2"}
@@ -0,0 +1,89 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// TransferSuffix is appended to the intermediate data directory of a primary
+// upgraded on a new host to name where its source data directory is
+// transferred for pg_upgrade.
+const TransferSuffix = ".source"
+
+// HostMapping maps source hosts to the new hosts that their segments are
+// upgraded onto. Each line of a host mapping file is either blank, a comment
+// starting with #, or "<source host> <target host>".
+type HostMapping map[string]string
+
+func ParseHostMapping(r io.Reader) (HostMapping, error) {
+	hosts := make(HostMapping)
+	targets := make(map[string]string)
+
+	var err error
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) != 2 {
+			err = errorlist.Append(err, xerrors.Errorf(`line %d: expected "<source host> <target host>"`, lineNum))
+			continue
+		}
+
+		source, target := fields[0], fields[1]
+		if _, ok := hosts[source]; ok {
+			err = errorlist.Append(err, xerrors.Errorf("line %d: duplicate source host %q", lineNum, source))
+			continue
+		}
+
+		if other, ok := targets[target]; ok {
+			err = errorlist.Append(err, xerrors.Errorf("line %d: target host %q is already mapped from %q", lineNum, target, other))
+			continue
+		}
+
+		hosts[source] = target
+		targets[target] = source
+	}
+
+	if sErr := scanner.Err(); sErr != nil {
+		return nil, xerrors.Errorf("reading host mapping: %w", sErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hosts) == 0 {
+		return nil, xerrors.New("host mapping has no hosts")
+	}
+
+	return hosts, nil
+}
+
+// Host returns the host that segments on the source host are upgraded onto.
+// Unmapped hosts are upgraded in place.
+func (m HostMapping) Host(source string) string {
+	if target, ok := m[source]; ok {
+		return target
+	}
+
+	return source
+}
+
+// TransferredDataDir returns where the source data directory of a primary
+// upgraded on a new host is transferred to next to its intermediate data
+// directory.
+func TransferredDataDir(intermediateDataDir string) string {
+	return filepath.Clean(intermediateDataDir) + TransferSuffix
+}
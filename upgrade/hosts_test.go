@@ -0,0 +1,58 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+func TestParseHostMapping(t *testing.T) {
+	t.Run("maps source hosts to target hosts", func(t *testing.T) {
+		hosts, err := upgrade.ParseHostMapping(strings.NewReader(`
+# source target
+sdw1 new1
+sdw2	new2
+`))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := upgrade.HostMapping{"sdw1": "new1", "sdw2": "new2"}
+		if !reflect.DeepEqual(hosts, expected) {
+			t.Errorf("got %v want %v", hosts, expected)
+		}
+
+		if host := hosts.Host("sdw1"); host != "new1" {
+			t.Errorf("got host %q want %q", host, "new1")
+		}
+
+		if host := hosts.Host("sdw3"); host != "sdw3" {
+			t.Errorf("got host %q want the unmapped host", host)
+		}
+	})
+
+	t.Run("errors on invalid host mappings", func(t *testing.T) {
+		cases := []struct {
+			hosts    string
+			expected string
+		}{
+			{"# nothing\n", "no hosts"},
+			{"sdw1\n", "line 1: expected"},
+			{"sdw1 new1 new2\n", "line 1: expected"},
+			{"sdw1 new1\nsdw1 new2\n", `line 2: duplicate source host "sdw1"`},
+			{"sdw1 new1\nsdw2 new1\n", `line 2: target host "new1" is already mapped from "sdw1"`},
+		}
+
+		for _, c := range cases {
+			_, err := upgrade.ParseHostMapping(strings.NewReader(c.hosts))
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("ParseHostMapping(%q) returned error %+v want %q", c.hosts, err, c.expected)
+			}
+		}
+	})
+}
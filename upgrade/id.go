@@ -5,6 +5,7 @@ package upgrade
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/binary"
 	"fmt"
@@ -51,3 +52,11 @@ func (id ID) String() string {
 	// filesystem-safe character set.
 	return base64.RawURLEncoding.EncodeToString(bytes[:])
 }
+
+// PointOfNoReturnToken returns the token acknowledging that the upgrade may
+// pass its point of no return. Deriving it from the ID means the token differs
+// for each upgrade, so it cannot be hard coded into automation.
+func (id ID) PointOfNoReturnToken() string {
+	sum := sha256.Sum256([]byte("point-of-no-return:" + id.String()))
+	return fmt.Sprintf("%x", sum[:6])
+}
@@ -31,6 +31,24 @@ func TestID(t *testing.T) {
 			t.Errorf("String() returned %q, want %q", id.String(), expected)
 		}
 	})
+
+	t.Run("PointOfNoReturnToken is derived from the ID", func(t *testing.T) {
+		var id upgrade.ID
+
+		token := id.PointOfNoReturnToken()
+		if token != id.PointOfNoReturnToken() {
+			t.Errorf("PointOfNoReturnToken() returned %q, then %q", token, id.PointOfNoReturnToken())
+		}
+
+		if len(token) != 12 {
+			t.Errorf("got token %q with length %d want 12", token, len(token))
+		}
+
+		other := upgrade.ID(1)
+		if other.PointOfNoReturnToken() == token {
+			t.Errorf("got the same token %q for IDs %q and %q", token, id, other)
+		}
+	})
 }
 
 // TestNewIDCrossProcess ensures that NewID returns different results across
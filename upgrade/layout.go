@@ -0,0 +1,157 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"bufio"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// AllHosts matches every host in a layout prefix rule.
+const AllHosts = "*"
+
+// Layout places the intermediate data directories rather than deriving them
+// next to the source data directories with TempDataDir. Each line of a layout
+// file is either blank, a comment starting with #, or one of:
+//
+//   dbid <dbid> <datadir>
+//   prefix <host|*> <source prefix> <target prefix>
+//
+// A dbid entry places that segment explicitly. Otherwise the source data
+// directory has the longest matching source prefix replaced with its target
+// prefix, preferring rules for the segment host over rules for all hosts.
+type Layout struct {
+	dirs     map[int]string
+	prefixes []prefixRule
+}
+
+type prefixRule struct {
+	host   string
+	source string
+	target string
+}
+
+func ParseLayout(r io.Reader) (*Layout, error) {
+	layout := &Layout{dirs: make(map[int]string)}
+
+	var err error
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if lErr := layout.parseLine(fields); lErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("line %d: %w", lineNum, lErr))
+		}
+	}
+
+	if sErr := scanner.Err(); sErr != nil {
+		return nil, xerrors.Errorf("reading layout: %w", sErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(layout.dirs) == 0 && len(layout.prefixes) == 0 {
+		return nil, xerrors.New("layout has no dbid or prefix entries")
+	}
+
+	return layout, nil
+}
+
+func (l *Layout) parseLine(fields []string) error {
+	switch fields[0] {
+	case "dbid":
+		if len(fields) != 3 {
+			return xerrors.New(`expected "dbid <dbid> <datadir>"`)
+		}
+
+		dbid, err := strconv.Atoi(fields[1])
+		if err != nil || dbid < 1 {
+			return xerrors.Errorf("invalid dbid %q", fields[1])
+		}
+
+		if _, ok := l.dirs[dbid]; ok {
+			return xerrors.Errorf("duplicate dbid %d", dbid)
+		}
+
+		if !filepath.IsAbs(fields[2]) {
+			return xerrors.Errorf("data directory %q is not an absolute path", fields[2])
+		}
+
+		l.dirs[dbid] = filepath.Clean(fields[2])
+
+	case "prefix":
+		if len(fields) != 4 {
+			return xerrors.New(`expected "prefix <host|*> <source prefix> <target prefix>"`)
+		}
+
+		for _, prefix := range fields[2:] {
+			if !filepath.IsAbs(prefix) {
+				return xerrors.Errorf("prefix %q is not an absolute path", prefix)
+			}
+		}
+
+		rule := prefixRule{host: fields[1], source: filepath.Clean(fields[2]), target: filepath.Clean(fields[3])}
+		for _, existing := range l.prefixes {
+			if existing.host == rule.host && existing.source == rule.source {
+				return xerrors.Errorf("duplicate prefix %q for host %q", rule.source, rule.host)
+			}
+		}
+
+		l.prefixes = append(l.prefixes, rule)
+
+	default:
+		return xerrors.Errorf("unknown entry %q. Expected dbid or prefix.", fields[0])
+	}
+
+	return nil
+}
+
+// DataDir returns the intermediate data directory of the segment, or an error
+// if the layout does not cover it.
+func (l *Layout) DataDir(dbid int, host string, sourceDataDir string) (string, error) {
+	if dir, ok := l.dirs[dbid]; ok {
+		return dir, nil
+	}
+
+	sourceDataDir = filepath.Clean(sourceDataDir)
+	for _, hosts := range []string{host, AllHosts} {
+		var best *prefixRule
+		for i, rule := range l.prefixes {
+			if rule.host != hosts || !hasPathPrefix(sourceDataDir, rule.source) {
+				continue
+			}
+
+			if best == nil || len(rule.source) > len(best.source) {
+				best = &l.prefixes[i]
+			}
+		}
+
+		if best != nil {
+			return filepath.Join(best.target, strings.TrimPrefix(sourceDataDir, best.source)), nil
+		}
+	}
+
+	return "", xerrors.Errorf("layout does not cover dbid %d with data directory %q on host %q", dbid, sourceDataDir, host)
+}
+
+// hasPathPrefix reports whether prefix is path or one of its parents.
+func hasPathPrefix(path string, prefix string) bool {
+	if path == prefix || prefix == string(filepath.Separator) {
+		return true
+	}
+
+	return strings.HasPrefix(path, prefix+string(filepath.Separator))
+}
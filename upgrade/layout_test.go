@@ -0,0 +1,97 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+func TestParseLayout(t *testing.T) {
+	t.Run("places segments by dbid then by host and longest prefix", func(t *testing.T) {
+		layout, err := upgrade.ParseLayout(strings.NewReader(`
+# explicit placements win
+dbid 1 /mnt/coordinator/seg-1
+
+prefix *    /data         /mnt/all
+prefix sdw1 /data         /mnt/sdw1
+prefix sdw1 /data/primary /mnt/sdw1_primary
+prefix *    /data/mirror/ /mnt/mirror
+`))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		cases := []struct {
+			dbid     int
+			host     string
+			datadir  string
+			expected string
+		}{
+			{1, "cdw", "/data/qddir/seg-1", "/mnt/coordinator/seg-1"},
+			{2, "sdw1", "/data/primary/seg0/", "/mnt/sdw1_primary/seg0"},
+			{3, "sdw1", "/data/mirror/seg1", "/mnt/sdw1/mirror/seg1"},
+			{4, "sdw2", "/data/mirror/seg0", "/mnt/mirror/seg0"},
+			{5, "sdw2", "/data/primary/seg1", "/mnt/all/primary/seg1"},
+		}
+
+		for _, c := range cases {
+			actual, err := layout.DataDir(c.dbid, c.host, c.datadir)
+			if err != nil {
+				t.Errorf("DataDir(%d, %q, %q) returned error %+v", c.dbid, c.host, c.datadir, err)
+				continue
+			}
+
+			if actual != c.expected {
+				t.Errorf("DataDir(%d, %q, %q) = %q want %q", c.dbid, c.host, c.datadir, actual, c.expected)
+			}
+		}
+	})
+
+	t.Run("errors when a segment is not covered", func(t *testing.T) {
+		layout, err := upgrade.ParseLayout(strings.NewReader("prefix sdw1 /data /mnt\n"))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		for _, c := range []struct {
+			host    string
+			datadir string
+		}{
+			{"sdw2", "/data/primary/seg0"},
+			{"sdw1", "/database/primary/seg0"},
+		} {
+			_, err := layout.DataDir(2, c.host, c.datadir)
+			if err == nil || !strings.Contains(err.Error(), "does not cover dbid 2") {
+				t.Errorf("DataDir(2, %q, %q) returned error %+v", c.host, c.datadir, err)
+			}
+		}
+	})
+
+	t.Run("errors on invalid layouts", func(t *testing.T) {
+		cases := []struct {
+			layout   string
+			expected string
+		}{
+			{"# nothing\n", "no dbid or prefix entries"},
+			{"dbid 2\n", "expected"},
+			{"dbid two /mnt/seg0\n", `invalid dbid "two"`},
+			{"dbid 2 mnt/seg0\n", "not an absolute path"},
+			{"dbid 2 /mnt/seg0\ndbid 2 /mnt/seg1\n", "line 2: duplicate dbid 2"},
+			{"prefix * /data\n", "expected"},
+			{"prefix * /data mnt\n", "not an absolute path"},
+			{"prefix * /data /mnt\nprefix * /data/ /mnt2\n", "duplicate prefix"},
+			{"host sdw1 /mnt\n", `unknown entry "host"`},
+		}
+
+		for _, c := range cases {
+			_, err := upgrade.ParseLayout(strings.NewReader(c.layout))
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("ParseLayout(%q) returned error %+v want %q", c.layout, err, c.expected)
+			}
+		}
+	})
+}
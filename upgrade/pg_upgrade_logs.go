@@ -0,0 +1,127 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+)
+
+// MaxPgUpgradeLogSize limits the size of each log file returned by
+// PgUpgradeLogs. Larger files are truncated to their end, which is where
+// pg_upgrade reports its failure.
+const MaxPgUpgradeLogSize = 256 * 1024
+
+// MaxPgUpgradeLogsSize limits the total size of the logs returned by
+// PgUpgradeLogs to stay within the gRPC message size limit.
+const MaxPgUpgradeLogsSize = 3 * 1024 * 1024
+
+// PgUpgradeLogs returns the log and text files that pg_upgrade wrote within
+// dir, such as pg_upgrade_internal.log, pg_upgrade_server.log, and
+// loadable_libraries.txt, ordered by name. Newer versions of pg_upgrade write
+// them to subdirectories so dir is walked. Files beyond MaxPgUpgradeLogsSize
+// are omitted.
+func PgUpgradeLogs(dir string) ([]*idl.PgUpgradeLog, error) {
+	var logs []*idl.PgUpgradeLog
+	var total int
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		ext := filepath.Ext(path)
+		if ext != ".log" && ext != ".txt" {
+			return nil
+		}
+
+		contents, err := readTail(path, info.Size())
+		if err != nil {
+			return err
+		}
+
+		total += len(contents)
+		if total > MaxPgUpgradeLogsSize {
+			gplog.Warn("omitting pg_upgrade log %q since the logs exceed %d bytes", path, MaxPgUpgradeLogsSize)
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		logs = append(logs, &idl.PgUpgradeLog{Name: name, Contents: contents})
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("reading pg_upgrade logs in %q: %w", dir, err)
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].GetName() < logs[j].GetName()
+	})
+
+	return logs, nil
+}
+
+func readTail(path string, size int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if size > MaxPgUpgradeLogSize {
+		if _, err := f.Seek(size-MaxPgUpgradeLogSize, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	return ioutil.ReadAll(f)
+}
+
+// PgUpgradeErrorLines returns up to max lines from the logs that report
+// errors, prefixed with the name of their log.
+func PgUpgradeErrorLines(logs []*idl.PgUpgradeLog, max int) []string {
+	var lines []string
+	for _, log := range logs {
+		for _, line := range strings.Split(string(log.GetContents()), "\n") {
+			line = strings.TrimSpace(line)
+			if !isErrorLine(line) {
+				continue
+			}
+
+			lines = append(lines, log.GetName()+": "+line)
+			if len(lines) == max {
+				return lines
+			}
+		}
+	}
+
+	return lines
+}
+
+func isErrorLine(line string) bool {
+	for _, prefix := range []string{"FATAL", "ERROR", "PANIC", "could not", "*failure*", "Failure"} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+
+	return strings.Contains(line, "ERROR:") || strings.Contains(line, "FATAL:")
+}
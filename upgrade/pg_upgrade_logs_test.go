@@ -0,0 +1,91 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+func TestPgUpgradeLogs(t *testing.T) {
+	t.Run("returns the log and text files ordered by name", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		testutils.MustWriteToFile(t, filepath.Join(dir, "pg_upgrade_server.log"), "server")
+		testutils.MustWriteToFile(t, filepath.Join(dir, "loadable_libraries.txt"), "libraries")
+		testutils.MustWriteToFile(t, filepath.Join(dir, "pg_upgrade_dump_globals.sql"), "globals")
+		testutils.MustCreateDir(t, filepath.Join(dir, "pg_upgrade_output.d", "log"))
+		testutils.MustWriteToFile(t, filepath.Join(dir, "pg_upgrade_output.d", "log", "pg_upgrade_internal.log"), "internal")
+
+		logs, err := upgrade.PgUpgradeLogs(dir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []*idl.PgUpgradeLog{
+			{Name: "loadable_libraries.txt", Contents: []byte("libraries")},
+			{Name: "pg_upgrade_output.d/log/pg_upgrade_internal.log", Contents: []byte("internal")},
+			{Name: "pg_upgrade_server.log", Contents: []byte("server")},
+		}
+		if !reflect.DeepEqual(logs, expected) {
+			t.Errorf("got %v want %v", logs, expected)
+		}
+	})
+
+	t.Run("truncates large logs to their end", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		contents := strings.Repeat("a", upgrade.MaxPgUpgradeLogSize) + "FATAL: out of disk"
+		testutils.MustWriteToFile(t, filepath.Join(dir, "pg_upgrade_server.log"), contents)
+
+		logs, err := upgrade.PgUpgradeLogs(dir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		actual := string(logs[0].GetContents())
+		if len(actual) != upgrade.MaxPgUpgradeLogSize || !strings.HasSuffix(actual, "FATAL: out of disk") {
+			t.Errorf("got %d bytes ending in %q want the last %d bytes", len(actual), actual[len(actual)-20:], upgrade.MaxPgUpgradeLogSize)
+		}
+	})
+
+	t.Run("errors when the directory does not exist", func(t *testing.T) {
+		_, err := upgrade.PgUpgradeLogs("/does/not/exist")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %#v want ErrNotExist", err)
+		}
+	})
+}
+
+func TestPgUpgradeErrorLines(t *testing.T) {
+	logs := []*idl.PgUpgradeLog{
+		{Name: "pg_upgrade_internal.log", Contents: []byte("Performing Consistency Checks\n  *failure*\nConsult the last few lines\n")},
+		{Name: "pg_upgrade_server.log", Contents: []byte("LOG:  starting\n2022-01-01 FATAL:  could not write to file\nERROR:  relation does not exist\n")},
+	}
+
+	lines := upgrade.PgUpgradeErrorLines(logs, 10)
+	expected := []string{
+		"pg_upgrade_internal.log: *failure*",
+		"pg_upgrade_server.log: 2022-01-01 FATAL:  could not write to file",
+		"pg_upgrade_server.log: ERROR:  relation does not exist",
+	}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("got %q want %q", lines, expected)
+	}
+
+	lines = upgrade.PgUpgradeErrorLines(logs, 2)
+	if !reflect.DeepEqual(lines, expected[:2]) {
+		t.Errorf("got %q want %q", lines, expected[:2])
+	}
+}
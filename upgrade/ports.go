@@ -0,0 +1,96 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// PortMap assigns the intermediate port of each segment from its source host
+// and port rather than from the temp port range. Each line of a port map file
+// is either blank, a comment starting with #, or
+// "<source host|*> <source port> <port>". Entries for the source host are
+// preferred over entries for all hosts.
+type PortMap struct {
+	ports map[hostPort]int
+}
+
+type hostPort struct {
+	host string
+	port int
+}
+
+func ParsePortMap(r io.Reader) (*PortMap, error) {
+	m := &PortMap{ports: make(map[hostPort]int)}
+
+	var err error
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if pErr := m.parseLine(fields); pErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("line %d: %w", lineNum, pErr))
+		}
+	}
+
+	if sErr := scanner.Err(); sErr != nil {
+		return nil, xerrors.Errorf("reading port map: %w", sErr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(m.ports) == 0 {
+		return nil, xerrors.New("port map has no ports")
+	}
+
+	return m, nil
+}
+
+func (m *PortMap) parseLine(fields []string) error {
+	if len(fields) != 3 {
+		return xerrors.New(`expected "<source host|*> <source port> <port>"`)
+	}
+
+	var ports []int
+	for _, field := range fields[1:] {
+		port, err := strconv.Atoi(field)
+		if err != nil || port < 1 || port > 65535 {
+			return xerrors.Errorf("invalid port %q", field)
+		}
+
+		ports = append(ports, port)
+	}
+
+	key := hostPort{host: fields[0], port: ports[0]}
+	if _, ok := m.ports[key]; ok {
+		return xerrors.Errorf("duplicate source port %d for host %q", key.port, key.host)
+	}
+
+	m.ports[key] = ports[1]
+	return nil
+}
+
+// Port returns the intermediate port of the segment listening on the source
+// port of the source host, or an error if the port map does not cover it.
+func (m *PortMap) Port(sourceHost string, sourcePort int) (int, error) {
+	for _, host := range []string{sourceHost, AllHosts} {
+		if port, ok := m.ports[hostPort{host: host, port: sourcePort}]; ok {
+			return port, nil
+		}
+	}
+
+	return 0, xerrors.Errorf("port map does not cover source port %d on host %q", sourcePort, sourceHost)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package upgrade_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/upgrade"
+)
+
+func TestParsePortMap(t *testing.T) {
+	t.Run("maps source ports to intermediate ports preferring the host", func(t *testing.T) {
+		ports, err := upgrade.ParsePortMap(strings.NewReader(`
+# host source-port port
+*    25432 50432
+sdw1 25432 60432
+sdw1	25433	60433
+`))
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		cases := []struct {
+			host     string
+			port     int
+			expected int
+		}{
+			{"sdw1", 25432, 60432},
+			{"sdw1", 25433, 60433},
+			{"sdw2", 25432, 50432},
+		}
+
+		for _, c := range cases {
+			port, err := ports.Port(c.host, c.port)
+			if err != nil {
+				t.Errorf("Port(%q, %d) returned error %+v", c.host, c.port, err)
+			}
+
+			if port != c.expected {
+				t.Errorf("Port(%q, %d) got %d want %d", c.host, c.port, port, c.expected)
+			}
+		}
+
+		_, err = ports.Port("sdw2", 25433)
+		expected := `port map does not cover source port 25433 on host "sdw2"`
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %+v want %q", err, expected)
+		}
+	})
+
+	t.Run("errors on invalid port maps", func(t *testing.T) {
+		cases := []struct {
+			ports    string
+			expected string
+		}{
+			{"# nothing\n", "no ports"},
+			{"sdw1 25432\n", "line 1: expected"},
+			{"sdw1 25432 abc\n", `line 1: invalid port "abc"`},
+			{"sdw1 0 50432\n", `line 1: invalid port "0"`},
+			{"sdw1 25432 65536\n", `line 1: invalid port "65536"`},
+			{"sdw1 25432 50432\nsdw1 25432 50433\n", `line 2: duplicate source port 25432 for host "sdw1"`},
+		}
+
+		for _, c := range cases {
+			_, err := upgrade.ParsePortMap(strings.NewReader(c.ports))
+			if err == nil || !strings.Contains(err.Error(), c.expected) {
+				t.Errorf("ParsePortMap(%q) returned error %+v want %q", c.ports, err, c.expected)
+			}
+		}
+	})
+}
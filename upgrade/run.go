@@ -4,7 +4,9 @@
 package upgrade
 
 import (
+	"context"
 	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -15,6 +17,9 @@ import (
 	"github.com/greenplum-db/gpupgrade/idl"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
 	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 const DefaultHubPort = 7527
@@ -23,7 +28,7 @@ const DefaultDynamicLibraryPath = "$libdir"
 
 var pgupgradeCmd = exec.Command
 
-func Run(stdout, stderr io.Writer, opts *idl.PgOptions) error {
+func Run(ctx context.Context, stdout, stderr io.Writer, opts *idl.PgOptions) (err error) {
 	upgradeDir, err := utils.GetPgUpgradeDir(opts.GetRole(), opts.GetContentID())
 	if err != nil {
 		return err
@@ -34,11 +39,32 @@ func Run(stdout, stderr io.Writer, opts *idl.PgOptions) error {
 		return err
 	}
 
+	oldDataDir := opts.GetOldDataDir()
+	if opts.GetUpgradeMode() == idl.Mode_clone && !supportsClone(opts.TargetVersion) {
+		if err := checkCloneFallback(ctx, opts); err != nil {
+			return err
+		}
+
+		if opts.Action == idl.PgOptions_upgrade {
+			oldDataDir, err = cloneDataDir(ctx, opts.GetOldDataDir())
+			if err != nil {
+				return err
+			}
+			defer func() {
+				// The target hard links the files of the clone, so removing
+				// it only removes its own links.
+				if rErr := os.RemoveAll(oldDataDir); rErr != nil {
+					err = errorlist.Append(err, rErr)
+				}
+			}()
+		}
+	}
+
 	args := []string{
 		"--retain",
 		"--old-bindir", opts.GetOldBinDir(),
 		"--new-bindir", opts.GetNewBinDir(),
-		"--old-datadir", opts.GetOldDataDir(),
+		"--old-datadir", oldDataDir,
 		"--new-datadir", opts.GetNewDataDir(),
 		"--old-port", opts.GetOldPort(),
 		"--new-port", opts.GetNewPort(),
@@ -49,8 +75,17 @@ func Run(stdout, stderr io.Writer, opts *idl.PgOptions) error {
 		args = append(args, "--check")
 	}
 
-	if opts.LinkMode {
+	switch opts.GetUpgradeMode() {
+	case idl.Mode_link:
 		args = append(args, "--link")
+	case idl.Mode_clone:
+		if supportsClone(opts.TargetVersion) {
+			args = append(args, "--clone")
+		} else {
+			// Link the target to the reflinked clone of the source, which
+			// leaves the source untouched.
+			args = append(args, "--link")
+		}
 	}
 
 	if opts.OldOptions != "" {
@@ -74,9 +109,9 @@ func Run(stdout, stderr io.Writer, opts *idl.PgOptions) error {
 	// like PATH and PGPORT which are explicitly forbidden to be set.
 	cmd.Env = []string{}
 
-	gplog.Info(cmd.String())
+	gplog.Info("%s%s", log.ContentPrefix(opts.GetContentID()), cmd.String())
 
-	return cmd.Run()
+	return trace.Run(ctx, "pg_upgrade", cmd)
 }
 
 func SetPgUpgradeCommand(cmdFunc exectest.Command) {
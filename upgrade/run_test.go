@@ -6,6 +6,7 @@ package upgrade_test
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -87,7 +88,7 @@ func TestRun(t *testing.T) {
 			TargetVersion: "6.20.0",
 		}
 
-		err := upgrade.Run(nil, nil, opts)
+		err := upgrade.Run(context.Background(), nil, nil, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -114,7 +115,7 @@ func TestRun(t *testing.T) {
 			TargetVersion: "6.20.0",
 		}
 
-		err = upgrade.Run(nil, nil, opts)
+		err = upgrade.Run(context.Background(), nil, nil, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -130,7 +131,7 @@ func TestRun(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(upgrade.Success))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := upgrade.Run(nil, nil, &idl.PgOptions{})
+		err := upgrade.Run(context.Background(), nil, nil, &idl.PgOptions{})
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v want %#v", err, expected)
 		}
@@ -146,7 +147,7 @@ func TestRun(t *testing.T) {
 		upgrade.SetPgUpgradeCommand(exectest.NewCommand(upgrade.Success))
 		defer upgrade.ResetPgUpgradeCommand()
 
-		err := upgrade.Run(nil, nil, &idl.PgOptions{})
+		err := upgrade.Run(context.Background(), nil, nil, &idl.PgOptions{})
 		if !errors.Is(err, expected) {
 			t.Errorf("got error %#v want %#v", err, expected)
 		}
@@ -164,7 +165,7 @@ func TestRun(t *testing.T) {
 			ContentID:     3,
 			TargetVersion: "6.20.0",
 		}
-		err := upgrade.Run(stdout, stderr, opts)
+		err := upgrade.Run(context.Background(), stdout, stderr, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -192,7 +193,7 @@ func TestRun(t *testing.T) {
 			ContentID:     3,
 			TargetVersion: "6.20.0",
 		}
-		err := upgrade.Run(stdout, nil, opts)
+		err := upgrade.Run(context.Background(), stdout, nil, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -227,7 +228,7 @@ func TestRun(t *testing.T) {
 			ContentID:     3,
 			TargetVersion: "6.20.0",
 		}
-		err := upgrade.Run(stdout, nil, opts)
+		err := upgrade.Run(context.Background(), stdout, nil, opts)
 		if err != nil {
 			t.Fatalf("unexpected error %+v", err)
 		}
@@ -258,7 +259,7 @@ func TestRun(t *testing.T) {
 			TargetVersion: "6.20.0",
 		}
 
-		err := upgrade.Run(nil, nil, opts)
+		err := upgrade.Run(context.Background(), nil, nil, opts)
 		var exitErr *exec.ExitError
 		if !errors.As(err, &exitErr) {
 			t.Fatalf("got error %#v, want type *exec.ExitError", err)
@@ -296,7 +297,7 @@ func TestRun(t *testing.T) {
 				Mode:          idl.PgOptions_Dispatcher,
 				OldOptions:    "-x 2",
 				Action:        idl.PgOptions_check,
-				LinkMode:      true,
+				UpgradeMode:   idl.Mode_link,
 				TargetVersion: "6.20.0",
 				OldBinDir:     "/usr/local/old/bin/dir",
 				OldDataDir:    "/old/data/dir",
@@ -354,7 +355,7 @@ func TestRun(t *testing.T) {
 			},
 		},
 		{
-			name:        "sets --link when LinkMode is true",
+			name:        "sets --link in link mode",
 			expectedCmd: "pg_upgrade",
 			expectedArgs: []string{"--retain", "--progress",
 				"--old-bindir", "",
@@ -371,12 +372,12 @@ func TestRun(t *testing.T) {
 			opts: idl.PgOptions{
 				Role:          greenplum.PrimaryRole,
 				ContentID:     3,
-				LinkMode:      true,
+				UpgradeMode:   idl.Mode_link,
 				TargetVersion: "6.20.0",
 			},
 		},
 		{
-			name:        "does not set --link when LinkMode is true",
+			name:        "does not set --link in copy mode",
 			expectedCmd: "pg_upgrade",
 			expectedArgs: []string{"--retain", "--progress",
 				"--old-bindir", "",
@@ -392,10 +393,30 @@ func TestRun(t *testing.T) {
 			opts: idl.PgOptions{
 				Role:          greenplum.PrimaryRole,
 				ContentID:     3,
-				LinkMode:      false,
+				UpgradeMode:   idl.Mode_copy,
 				TargetVersion: "6.20.0",
 			},
 		},
+		{
+			name:        "sets --clone in clone mode when the target supports it",
+			expectedCmd: "pg_upgrade",
+			expectedArgs: []string{"--retain", "--progress",
+				"--old-bindir", "",
+				"--new-bindir", "",
+				"--old-datadir", "",
+				"--new-datadir", "",
+				"--old-port", "",
+				"--new-port", "",
+				"--mode", "unknown_mode",
+				"--clone",
+				"--old-tablespaces-file", utils.GetTablespaceMappingFile()},
+			opts: idl.PgOptions{
+				Role:          greenplum.PrimaryRole,
+				ContentID:     3,
+				UpgradeMode:   idl.Mode_clone,
+				TargetVersion: "7.1.0",
+			},
+		},
 		{
 			name:        "does not set --old-tablespaces-file when --check is passed",
 			expectedCmd: "pg_upgrade",
@@ -455,7 +476,7 @@ func TestRun(t *testing.T) {
 			opts: idl.PgOptions{
 				Role:          greenplum.PrimaryRole,
 				ContentID:     3,
-				LinkMode:      false,
+				UpgradeMode:   idl.Mode_copy,
 				TargetVersion: "6.20.0",
 				OldDBID:       "0",
 				NewDBID:       "1",
@@ -476,7 +497,7 @@ func TestRun(t *testing.T) {
 			opts: idl.PgOptions{
 				Role:          greenplum.PrimaryRole,
 				ContentID:     3,
-				LinkMode:      false,
+				UpgradeMode:   idl.Mode_copy,
 				TargetVersion: "7.1.0",
 				OldDBID:       "0",
 				NewDBID:       "1",
@@ -519,7 +540,7 @@ func TestRun(t *testing.T) {
 			}))
 			defer upgrade.ResetPgUpgradeCommand()
 
-			err := upgrade.Run(nil, nil, &c.opts)
+			err := upgrade.Run(context.Background(), nil, nil, &c.opts)
 			if err != nil {
 				t.Fatalf("unexpected error %+v", err)
 			}
@@ -0,0 +1,116 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package backup takes or verifies a backup of the source cluster before
+// execute shuts it down, so that the source data can be restored even once
+// the upgrade has passed its point of no return.
+package backup
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/step"
+)
+
+// The backup providers.
+const (
+	None     = "none"
+	GPBackup = "gpbackup"
+	Command  = "command"
+)
+
+var Providers = []string{None, GPBackup, Command}
+
+// MaxAge is how long after a backup is taken that it allows passing the point
+// of no return without acknowledgement.
+const MaxAge = 24 * time.Hour
+
+// TimestampLayout is the layout of gpbackup timestamps. The backup time is
+// parsed from identities in this layout.
+const TimestampLayout = "20060102150405"
+
+var command = exec.Command
+
+// Backup is the identity of a backup of the source cluster.
+type Backup struct {
+	Provider string
+	ID       string
+
+	// Time is when the backup was taken. It is zero when unknown.
+	Time time.Time
+
+	// Restore describes how to restore the source cluster from the backup.
+	// It is empty when unknown.
+	Restore string
+}
+
+// IsFresh returns whether the backup was taken recently enough to restore the
+// source cluster in place of revert.
+func (b *Backup) IsFresh(now time.Time) bool {
+	return b != nil && !b.Time.IsZero() && now.Sub(b.Time) <= MaxAge
+}
+
+func (b *Backup) String() string {
+	if b.Time.IsZero() {
+		return fmt.Sprintf("%s backup %s", b.Provider, b.ID)
+	}
+
+	return fmt.Sprintf("%s backup %s taken at %s", b.Provider, b.ID, b.Time.Format(time.RFC1123))
+}
+
+// Hook backs up the source cluster before it is upgraded.
+type Hook interface {
+	// Backup takes a backup of the running source cluster. When timestamp is
+	// set the existing backup it identifies is verified instead.
+	Backup(streams step.OutStreams, timestamp string) (Backup, error)
+}
+
+// Source describes the source cluster to back up.
+type Source struct {
+	GPHome             string
+	Port               int
+	CoordinatorDataDir string
+	UpgradeID          string
+}
+
+// ParseProvider returns the backup provider name or an error if it is not one
+// of Providers.
+func ParseProvider(input string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(input))
+	for _, choice := range Providers {
+		if name == choice {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("Invalid backup provider %q. Please specify one of %s.", input, strings.Join(Providers, ", "))
+}
+
+// New returns the hook of the named provider. The gpbackup provider backs up
+// each of the databases, and the command provider runs the command template.
+func New(provider string, source Source, databases []string, commandTemplate string) (Hook, error) {
+	switch provider {
+	case GPBackup:
+		return NewGPBackup(source, databases), nil
+	case Command:
+		return NewCommand(source, commandTemplate)
+	default:
+		return nil, xerrors.Errorf("unknown backup provider %q", provider)
+	}
+}
+
+// parseTime returns the time of a backup identified by a timestamp, or the
+// zero time if the identity is not a timestamp.
+func parseTime(id string) time.Time {
+	t, err := time.ParseInLocation(TimestampLayout, id, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
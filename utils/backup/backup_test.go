@@ -0,0 +1,261 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package backup_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/utils/backup"
+)
+
+func GPBackup() {
+	// The timestamp varies with the database, which is the last argument.
+	if os.Args[len(os.Args)-1] == "postgres" {
+		fmt.Println("20220101120000:-Backup Timestamp = 20220101120000")
+	} else {
+		fmt.Println("20220101120500:-Backup Timestamp = 20220101120500")
+	}
+}
+
+func GPBackupFailure() {
+	os.Stderr.WriteString("gpbackup failed")
+	os.Exit(1)
+}
+
+func BackupCommand() {
+	fmt.Println("backing up")
+	fmt.Println("nightly-42")
+}
+
+func BackupCommandWithoutIdentity() {}
+
+func init() {
+	exectest.RegisterMains(
+		GPBackup,
+		GPBackupFailure,
+		BackupCommand,
+		BackupCommandWithoutIdentity,
+	)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(exectest.Run(m))
+}
+
+func TestParseProvider(t *testing.T) {
+	for _, input := range []string{"none", "GPBACKUP", " command "} {
+		if _, err := backup.ParseProvider(input); err != nil {
+			t.Errorf("ParseProvider(%q) returned error %+v", input, err)
+		}
+	}
+
+	if _, err := backup.ParseProvider("gpcrondump"); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestIsFresh(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name     string
+		backup   *backup.Backup
+		expected bool
+	}{
+		{name: "no backup", backup: nil},
+		{name: "a backup of unknown time", backup: &backup.Backup{ID: "nightly-42"}},
+		{name: "a recent backup", backup: &backup.Backup{ID: "nightly-42", Time: now.Add(-time.Hour)}, expected: true},
+		{name: "a stale backup", backup: &backup.Backup{ID: "nightly-42", Time: now.Add(-backup.MaxAge - time.Hour)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if fresh := c.backup.IsFresh(now); fresh != c.expected {
+				t.Errorf("got %t want %t", fresh, c.expected)
+			}
+		})
+	}
+}
+
+func TestGPBackup(t *testing.T) {
+	source := backup.Source{GPHome: "/usr/local/gpdb5", Port: 5432, CoordinatorDataDir: testutils.GetTempDir(t, "")}
+	defer testutils.MustRemoveAll(t, source.CoordinatorDataDir)
+
+	t.Run("backs up each database with the gpbackup of the source installation", func(t *testing.T) {
+		var databases []string
+		backup.SetCommand(exectest.NewCommandWithVerifier(GPBackup, func(name string, args ...string) {
+			if name != "/usr/local/gpdb5/bin/gpbackup" {
+				t.Errorf("got %q want gpbackup of the source installation", name)
+			}
+
+			databases = append(databases, args[len(args)-1])
+		}))
+		defer backup.ResetCommand()
+
+		hook := backup.NewGPBackup(source, []string{"postgres", "db1"})
+		result, err := hook.Backup(step.DevNullStream, "")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(databases, []string{"postgres", "db1"}) {
+			t.Errorf("backed up databases %q", databases)
+		}
+
+		expected := backup.Backup{
+			Provider: backup.GPBackup,
+			ID:       "20220101120000,20220101120500",
+			Time:     time.Date(2022, 1, 1, 12, 0, 0, 0, time.Local),
+			Restore: "/usr/local/gpdb5/bin/gprestore --timestamp 20220101120000 --with-globals\n" +
+				"/usr/local/gpdb5/bin/gprestore --timestamp 20220101120500\n" +
+				"Add --create-db to each gprestore when restoring into a cluster without the backed up databases.",
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("got %+v want %+v", result, expected)
+		}
+	})
+
+	t.Run("returns an error when gpbackup fails", func(t *testing.T) {
+		backup.SetCommand(exectest.NewCommand(GPBackupFailure))
+		defer backup.ResetCommand()
+
+		hook := backup.NewGPBackup(source, []string{"postgres"})
+		_, err := hook.Backup(step.DevNullStream, "")
+		if err == nil || !strings.Contains(err.Error(), `backing up database "postgres"`) {
+			t.Errorf("got error %+v", err)
+		}
+	})
+
+	t.Run("verifies the reports of existing backups", func(t *testing.T) {
+		backup.SetCommand(exectest.NewCommandWithVerifier(GPBackup, func(name string, args ...string) {
+			t.Errorf("unexpected command %q %q", name, args)
+		}))
+		defer backup.ResetCommand()
+
+		path := backup.ReportPath(source.CoordinatorDataDir, "20220101120000")
+		testutils.MustCreateDir(t, filepath.Dir(path))
+		testutils.MustWriteToFile(t, path, "backup status:          Success\n")
+
+		hook := backup.NewGPBackup(source, nil)
+		result, err := hook.Backup(step.DevNullStream, "20220101120000")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if result.ID != "20220101120000" {
+			t.Errorf("got ID %q want %q", result.ID, "20220101120000")
+		}
+
+		testutils.MustWriteToFile(t, path, "backup status:          Failure\n")
+		_, err = hook.Backup(step.DevNullStream, "20220101120000")
+		if err == nil || !strings.Contains(err.Error(), "did not succeed") {
+			t.Errorf("got error %+v", err)
+		}
+
+		_, err = hook.Backup(step.DevNullStream, "20220102120000")
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("got error %+v want not exist", err)
+		}
+
+		_, err = hook.Backup(step.DevNullStream, "yesterday")
+		if err == nil || !strings.Contains(err.Error(), "invalid gpbackup timestamp") {
+			t.Errorf("got error %+v", err)
+		}
+	})
+}
+
+func TestCommand(t *testing.T) {
+	source := backup.Source{GPHome: "/usr/local/gpdb5", Port: 5432, CoordinatorDataDir: "/data/qddir/seg-1", UpgradeID: "ABC"}
+
+	t.Run("runs the expanded command template", func(t *testing.T) {
+		backup.SetCommand(exectest.NewCommandWithVerifier(BackupCommand, func(name string, args ...string) {
+			expected := []string{"-c", "nightly --port 5432 --dir /data/qddir/seg-1 --tag ABC"}
+			if name != "bash" || !reflect.DeepEqual(args, expected) {
+				t.Errorf("got %q %q want bash %q", name, args, expected)
+			}
+		}))
+		defer backup.ResetCommand()
+
+		hook, err := backup.NewCommand(source, "nightly --port {{.Port}} --dir {{.CoordinatorDataDir}} --tag {{.UpgradeID}}")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		before := time.Now()
+		result, err := hook.Backup(step.DevNullStream, "")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if result.Provider != backup.Command || result.ID != "nightly-42" {
+			t.Errorf("got %+v want the last line of output as the ID", result)
+		}
+
+		if result.Time.Before(before) {
+			t.Errorf("got time %s want the time of the backup", result.Time)
+		}
+	})
+
+	t.Run("the time of a verified backup is unknown unless identified by a timestamp", func(t *testing.T) {
+		backup.SetCommand(exectest.NewCommandWithVerifier(BackupCommand, func(name string, args ...string) {
+			expected := []string{"-c", "nightly --verify nightly-42"}
+			if !reflect.DeepEqual(args, expected) {
+				t.Errorf("got args %q want %q", args, expected)
+			}
+		}))
+		defer backup.ResetCommand()
+
+		hook, err := backup.NewCommand(source, "nightly --verify {{.Timestamp}}")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		result, err := hook.Backup(step.DevNullStream, "nightly-42")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !result.Time.IsZero() {
+			t.Errorf("got time %s want zero", result.Time)
+		}
+	})
+
+	t.Run("returns an error when the command prints no identity", func(t *testing.T) {
+		backup.SetCommand(exectest.NewCommand(BackupCommandWithoutIdentity))
+		defer backup.ResetCommand()
+
+		hook, err := backup.NewCommand(source, "nightly")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		_, err = hook.Backup(step.DevNullStream, "")
+		if err == nil {
+			t.Errorf("expected error")
+		}
+	})
+
+	t.Run("errors on invalid templates", func(t *testing.T) {
+		for _, text := range []string{"", "nightly {{.Port", "nightly {{.Unknown}}"} {
+			hook, err := backup.NewCommand(source, text)
+			if err == nil {
+				_, err = hook.Backup(step.DevNullStream, "")
+			}
+
+			if err == nil {
+				t.Errorf("expected error for %q", text)
+			}
+		}
+	})
+}
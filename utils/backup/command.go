@@ -0,0 +1,94 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+)
+
+// CommandData is available to the command template.
+type CommandData struct {
+	Source
+
+	// Timestamp identifies an existing backup for the command to verify
+	// rather than taking a new one. It is empty otherwise.
+	Timestamp string
+}
+
+// commandHook runs a user provided command template with bash. The command
+// must print the identity of the backup as the last line of its output.
+type commandHook struct {
+	source   Source
+	template *template.Template
+}
+
+func NewCommand(source Source, text string) (Hook, error) {
+	tmpl, err := ParseCommand(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commandHook{source: source, template: tmpl}, nil
+}
+
+// ParseCommand parses the backup command template.
+func ParseCommand(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, xerrors.New("The command backup provider requires a backup command.")
+	}
+
+	tmpl, err := template.New("backup_command").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, xerrors.Errorf("parsing backup command: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+func (c *commandHook) Backup(streams step.OutStreams, timestamp string) (Backup, error) {
+	var script strings.Builder
+	err := c.template.Execute(&script, CommandData{Source: c.source, Timestamp: timestamp})
+	if err != nil {
+		return Backup{}, xerrors.Errorf("expanding backup command: %w", err)
+	}
+
+	started := time.Now()
+
+	var stdout bytes.Buffer
+	cmd := command("bash", "-c", script.String())
+	cmd.Stdout = io.MultiWriter(streams.Stdout(), &stdout)
+	cmd.Stderr = streams.Stderr()
+
+	if err := trace.Run(step.Context(streams), "backup command", cmd); err != nil {
+		return Backup{}, xerrors.Errorf("running backup command: %w", err)
+	}
+
+	id := lastLine(stdout.String())
+	if id == "" {
+		return Backup{}, xerrors.New("backup command did not print the backup identity as the last line of its output")
+	}
+
+	// A new backup is taken now. The time of a verified backup is only known
+	// when it is identified by a timestamp.
+	taken := parseTime(id)
+	if taken.IsZero() && timestamp == "" {
+		taken = started
+	}
+
+	return Backup{Provider: Command, ID: id, Time: taken}, nil
+}
+
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
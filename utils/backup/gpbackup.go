@@ -0,0 +1,138 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+)
+
+var timestampRegex = regexp.MustCompile(`Backup Timestamp = (\d{14})`)
+
+var reportStatusRegex = regexp.MustCompile(`(?m)^backup status:\s+(\S+)`)
+
+// gpbackup backs up each database of the source cluster with the gpbackup of
+// the source installation. Its identity is the comma separated timestamps of
+// the database backups, which gprestore restores from.
+type gpbackup struct {
+	source    Source
+	databases []string
+}
+
+func NewGPBackup(source Source, databases []string) Hook {
+	return &gpbackup{source: source, databases: databases}
+}
+
+func (g *gpbackup) Backup(streams step.OutStreams, timestamp string) (Backup, error) {
+	var timestamps []string
+	if timestamp != "" {
+		timestamps = strings.Split(timestamp, ",")
+		for _, ts := range timestamps {
+			if err := g.verify(ts); err != nil {
+				return Backup{}, err
+			}
+		}
+	} else {
+		for _, database := range g.databases {
+			ts, err := g.backup(streams, database)
+			if err != nil {
+				return Backup{}, err
+			}
+
+			timestamps = append(timestamps, ts)
+		}
+	}
+
+	if len(timestamps) == 0 {
+		return Backup{}, xerrors.New("no databases to back up")
+	}
+
+	// The backup is as old as its earliest database backup.
+	var taken time.Time
+	var restores []string
+	for i, ts := range timestamps {
+		t := parseTime(ts)
+		if taken.IsZero() || t.Before(taken) {
+			taken = t
+		}
+
+		// Every database backup includes the global objects such as roles
+		// and tablespaces, so they are restored only once with the first.
+		restore := fmt.Sprintf("%s --timestamp %s", filepath.Join(g.source.GPHome, "bin", "gprestore"), ts)
+		if i == 0 {
+			restore += " --with-globals"
+		}
+
+		restores = append(restores, restore)
+	}
+
+	restores = append(restores, "Add --create-db to each gprestore when restoring into a cluster without the backed up databases.")
+
+	return Backup{
+		Provider: GPBackup,
+		ID:       strings.Join(timestamps, ","),
+		Time:     taken,
+		Restore:  strings.Join(restores, "\n"),
+	}, nil
+}
+
+// backup runs gpbackup on the database returning the timestamp of the backup.
+func (g *gpbackup) backup(streams step.OutStreams, database string) (string, error) {
+	var stdout bytes.Buffer
+
+	cmd := command(filepath.Join(g.source.GPHome, "bin", "gpbackup"), "--dbname", database)
+	cmd.Env = append(os.Environ(), fmt.Sprintf("PGPORT=%d", g.source.Port))
+	cmd.Stdout = io.MultiWriter(streams.Stdout(), &stdout)
+	cmd.Stderr = streams.Stderr()
+
+	if err := trace.Run(step.Context(streams), "gpbackup", cmd); err != nil {
+		return "", xerrors.Errorf("backing up database %q: %w", database, err)
+	}
+
+	matches := timestampRegex.FindStringSubmatch(stdout.String())
+	if matches == nil {
+		return "", xerrors.Errorf("backing up database %q: gpbackup did not report a backup timestamp", database)
+	}
+
+	return matches[1], nil
+}
+
+// verify checks that the gpbackup report of the timestamp records a
+// successful backup.
+func (g *gpbackup) verify(timestamp string) error {
+	if parseTime(timestamp).IsZero() {
+		return xerrors.Errorf("invalid gpbackup timestamp %q. Timestamps have the format YYYYMMDDHHMMSS.", timestamp)
+	}
+
+	path := ReportPath(g.source.CoordinatorDataDir, timestamp)
+	report, err := ioutil.ReadFile(path)
+	if err != nil {
+		return xerrors.Errorf("verifying gpbackup timestamp %s: %w", timestamp, err)
+	}
+
+	matches := reportStatusRegex.FindSubmatch(report)
+	if matches == nil || string(matches[1]) != "Success" {
+		return xerrors.Errorf("gpbackup timestamp %s did not succeed according to %s", timestamp, path)
+	}
+
+	return nil
+}
+
+// ReportPath returns the path of the gpbackup report of the timestamp within
+// the coordinator data directory.
+func ReportPath(coordinatorDataDir string, timestamp string) string {
+	return filepath.Join(coordinatorDataDir, "backups", timestamp[:8], timestamp, fmt.Sprintf("gpbackup_%s_report", timestamp))
+}
@@ -0,0 +1,366 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bundle collects the state, logs, and facts of each host into a
+// single support bundle.
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// MaxFileSize limits the size of each collected file. Larger files are
+// truncated to their end which has the most recent entries.
+const MaxFileSize = 16 * 1024 * 1024
+
+// ErrorsFileName lists the files of a host that could not be collected.
+const ErrorsFileName = "collection_errors.txt"
+
+const ManifestFileName = "manifest.json"
+
+// HostBundleFileName is the bundle of a single host written to the state
+// directory of the host before it is merged on the coordinator.
+const HostBundleFileName = "support-bundle.tar.gz"
+
+var factCommand = exec.Command
+
+// Source is a file or directory to collect into the bundle.
+type Source struct {
+	// Name is the directory within the host directory of the bundle.
+	Name string
+	Path string
+
+	// Patterns are the glob patterns of the file names to collect from a
+	// directory. All files are collected when empty.
+	Patterns []string
+
+	Recursive bool
+
+	// Newest limits the files collected from a directory to the most
+	// recently modified ones. All files are collected when zero.
+	Newest int
+}
+
+// Fact is the output of a command describing the host.
+type Fact struct {
+	Name string
+	Args []string
+}
+
+type file struct {
+	name    string
+	path    string
+	modTime time.Time
+}
+
+// Write writes a gzipped tarball of the sources and facts of the host to w.
+// Files are placed within a directory named after the host, which is
+// redacted along with the contents. Missing sources are skipped, and files
+// that fail to be collected are listed in ErrorsFileName rather than failing
+// the bundle.
+func Write(w io.Writer, host string, sources []Source, facts []Fact, redactor Redactor) (err error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	defer func() {
+		if cErr := tw.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+
+		if cErr := gz.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	hostDir := redactor.RedactName(host)
+	var failures []string
+
+	for _, source := range sources {
+		files, err := list(source)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", source.Path, err))
+			continue
+		}
+
+		for _, f := range files {
+			contents, err := readTail(f.path)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", f.path, err))
+				continue
+			}
+
+			name := path.Join(hostDir, source.Name, redactor.RedactName(f.name))
+			if err := writeEntry(tw, name, f.modTime, redactor.Redact(contents)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, fact := range facts {
+		cmd := factCommand(fact.Args[0], fact.Args[1:]...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			output = append(output, fmt.Sprintf("\n%q: %v\n", cmd.String(), err)...)
+		}
+
+		name := path.Join(hostDir, "facts", fact.Name+".txt")
+		if err := writeEntry(tw, name, time.Now(), redactor.Redact(output)); err != nil {
+			return err
+		}
+	}
+
+	if len(failures) > 0 {
+		gplog.Warn("failed to collect %d files into the support bundle", len(failures))
+
+		contents := []byte(strings.Join(failures, "\n") + "\n")
+		if err := writeEntry(tw, path.Join(hostDir, ErrorsFileName), time.Now(), redactor.Redact(contents)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteFile writes the bundle of the host to path.
+func WriteFile(path string, host string, sources []Source, facts []Fact, redactor Redactor) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := f.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	return Write(f, host, sources, facts, redactor)
+}
+
+// list returns the files of the source with names relative to it. A missing
+// source has no files.
+func list(source Source) ([]file, error) {
+	info, err := os.Stat(source.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return []file{{name: filepath.Base(source.Path), path: source.Path, modTime: info.ModTime()}}, nil
+	}
+
+	var files []file
+	err = filepath.Walk(source.Path, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if p != source.Path && !source.Recursive {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !info.Mode().IsRegular() || !matches(source.Patterns, info.Name()) {
+			return nil
+		}
+
+		name, err := filepath.Rel(source.Path, p)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, file{name: filepath.ToSlash(name), path: p, modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Newest > 0 && len(files) > source.Newest {
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].modTime.After(files[j].modTime)
+		})
+		files = files[:source.Newest]
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].name < files[j].name
+	})
+
+	return files, nil
+}
+
+func matches(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func readTail(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Size() > MaxFileSize {
+		if _, err := f.Seek(info.Size()-MaxFileSize, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	return ioutil.ReadAll(io.LimitReader(f, MaxFileSize))
+}
+
+func writeEntry(tw *tar.Writer, name string, modTime time.Time, contents []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(contents)),
+		ModTime: modTime,
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return xerrors.Errorf("writing %q to support bundle: %w", name, err)
+	}
+
+	if _, err := tw.Write(contents); err != nil {
+		return xerrors.Errorf("writing %q to support bundle: %w", name, err)
+	}
+
+	return nil
+}
+
+// Manifest describes the contents of a support bundle.
+type Manifest struct {
+	Version           string
+	Created           time.Time
+	RedactedPasswords bool
+	RedactedHostnames bool
+	Hosts             []string
+	Files             []File
+
+	// Errors are the hosts whose files could not be collected.
+	Errors []string
+}
+
+type File struct {
+	Name string
+	Size int64
+}
+
+// Merge combines the host bundles into a single gzipped tarball written to w
+// along with the manifest, which lists every file.
+func Merge(w io.Writer, hostBundles []string, manifest Manifest) (err error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	defer func() {
+		if cErr := tw.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+
+		if cErr := gz.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	for _, hostBundle := range hostBundles {
+		files, err := copyEntries(tw, hostBundle)
+		if err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, files...)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return writeEntry(tw, ManifestFileName, manifest.Created, append(data, '\n'))
+}
+
+func copyEntries(tw *tar.Writer, hostBundle string) (_ []File, err error) {
+	f, err := os.Open(hostBundle)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cErr := f.Close(); cErr != nil {
+			err = errorlist.Append(err, cErr)
+		}
+	}()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, xerrors.Errorf("reading %q: %w", hostBundle, err)
+	}
+
+	var files []File
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, xerrors.Errorf("reading %q: %w", hostBundle, err)
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, err
+		}
+
+		if _, err := io.Copy(tw, tr); err != nil {
+			return nil, err
+		}
+
+		files = append(files, File{Name: header.Name, Size: header.Size})
+	}
+
+	return files, nil
+}
+
+func SetFactCommand(command exectest.Command) {
+	factCommand = command
+}
+
+func ResetFactCommand() {
+	factCommand = exec.Command
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const Redacted = "REDACTED"
+
+var (
+	passwordPattern = regexp.MustCompile(`(?i)(password\s*[=:]\s*)('[^']*'|"[^"]*"|\S+)`)
+	uriPattern      = regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`)
+
+	// sqlPasswordPattern matches the password literals of statements such as
+	// CREATE ROLE ... ENCRYPTED PASSWORD 'secret' recorded in the postgres
+	// logs.
+	sqlPasswordPattern = regexp.MustCompile(`(?i)(\bpassword\s+)'(?:[^']|'')*'`)
+)
+
+// Redactor removes passwords and replaces hostnames in the collected files.
+type Redactor struct {
+	Passwords bool
+
+	// Hostnames maps each hostname to its replacement.
+	Hostnames map[string]string
+}
+
+// HostnameAliases returns replacements for the hostnames that are stable
+// across hosts, such as host1 and host2 in sorted order.
+func HostnameAliases(hostnames []string) map[string]string {
+	sorted := append([]string(nil), hostnames...)
+	sort.Strings(sorted)
+
+	aliases := make(map[string]string)
+	for _, hostname := range sorted {
+		if _, ok := aliases[hostname]; !ok {
+			aliases[hostname] = fmt.Sprintf("host%d", len(aliases)+1)
+		}
+	}
+
+	return aliases
+}
+
+func (r Redactor) Redact(contents []byte) []byte {
+	if r.Passwords {
+		contents = passwordPattern.ReplaceAll(contents, []byte("${1}"+Redacted))
+		contents = sqlPasswordPattern.ReplaceAll(contents, []byte("${1}'"+Redacted+"'"))
+		contents = uriPattern.ReplaceAll(contents, []byte("${1}"+Redacted+"@"))
+	}
+
+	if len(r.Hostnames) > 0 {
+		contents = []byte(r.replacer().Replace(string(contents)))
+	}
+
+	return contents
+}
+
+// RedactName replaces the hostnames in a file name.
+func (r Redactor) RedactName(name string) string {
+	if len(r.Hostnames) == 0 {
+		return name
+	}
+
+	return r.replacer().Replace(name)
+}
+
+// replacer replaces longer hostnames first so that sdw10 is not replaced as
+// sdw1 followed by 0.
+func (r Redactor) replacer() *strings.Replacer {
+	var hostnames []string
+	for hostname := range r.Hostnames {
+		hostnames = append(hostnames, hostname)
+	}
+
+	sort.Slice(hostnames, func(i, j int) bool {
+		if len(hostnames[i]) != len(hostnames[j]) {
+			return len(hostnames[i]) > len(hostnames[j])
+		}
+		return hostnames[i] < hostnames[j]
+	})
+
+	var oldnew []string
+	for _, hostname := range hostnames {
+		oldnew = append(oldnew, hostname, r.Hostnames[hostname])
+	}
+
+	return strings.NewReplacer(oldnew...)
+}
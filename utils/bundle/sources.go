@@ -0,0 +1,60 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package bundle
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// newestPostgresLogs limits the postgres logs collected from each data
+// directory.
+const newestPostgresLogs = 5
+
+// HostSources returns the gpupgrade state files, the gpupgrade logs including
+// the pg_upgrade working directories, the management utility logs, and the
+// newest postgres logs of the data directories on a host.
+func HostSources(stateDir string, logDir string, dataDirs []string) []Source {
+	sources := []Source{
+		{Name: "state", Path: stateDir, Patterns: []string{"*.json", "gpinitsystem_config"}},
+		{Name: "gpupgrade_logs", Path: logDir, Recursive: true},
+		// The management utility logs such as gpinitsystem and gpstart are
+		// in the parent of the gpupgrade log directory.
+		{Name: "gpAdminLogs", Path: filepath.Dir(logDir), Patterns: []string{"*.log"}},
+	}
+
+	for i, dataDir := range dataDirs {
+		name := fmt.Sprintf("postgres_logs/%d_%s", i, filepath.Base(dataDir))
+
+		// GPDB 7 writes to log rather than pg_log.
+		for _, dir := range []string{"pg_log", "log"} {
+			sources = append(sources, Source{Name: name, Path: filepath.Join(dataDir, dir), Newest: newestPostgresLogs})
+		}
+
+		sources = append(sources, Source{Name: name, Path: filepath.Join(dataDir, "postgresql.conf")})
+	}
+
+	return sources
+}
+
+// HostFacts returns the commands describing the disk usage of the data
+// directories, the operating system, and the Greenplum versions on a host.
+func HostFacts(gphomes []string, dataDirs []string) []Fact {
+	facts := []Fact{
+		{Name: "uname", Args: []string{"uname", "-a"}},
+		{Name: "os_release", Args: []string{"cat", "/etc/os-release"}},
+		{Name: "disk_usage", Args: append([]string{"df", "-h"}, dataDirs...)},
+		{Name: "memory", Args: []string{"free", "-m"}},
+		{Name: "ulimit", Args: []string{"sh", "-c", "ulimit -a"}},
+	}
+
+	for i, gphome := range gphomes {
+		facts = append(facts, Fact{
+			Name: fmt.Sprintf("postgres_version_%d", i),
+			Args: []string{filepath.Join(gphome, "bin", "postgres"), "--gp-version"},
+		})
+	}
+
+	return facts
+}
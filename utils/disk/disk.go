@@ -5,6 +5,8 @@ package disk
 
 import (
 	"os"
+	"path/filepath"
+	"syscall"
 
 	"golang.org/x/sys/unix"
 	"golang.org/x/xerrors"
@@ -21,6 +23,7 @@ type Disk interface {
 	Filesystems() (sigar.FileSystemList, error)
 	Usage(string) (sigar.FileSystemUsage, error)
 	Stat(string) (*unix.Stat_t, error)
+	DirSize(string) (uint64, error)
 }
 
 type FilesystemHost struct {
@@ -67,26 +70,9 @@ func CheckUsage(streams step.OutStreams, d Disk, diskFreeRatio float64, paths ..
 
 	failures := make(map[string]*idl.CheckDiskSpaceReply_DiskUsage)
 
-	// Find the device ID for every filesystem. We'll use these to map data
-	// directories to filesystems later.
-	fs, err := d.Filesystems()
+	fsByID, err := filesystemsByID(d, hostname)
 	if err != nil {
-		return nil, xerrors.Errorf("enumerating filesystems: %w", err)
-	}
-
-	fsByID := make(map[uint64]string)
-	for _, f := range fs.List {
-		stat, err := d.Stat(f.DirName)
-		if os.IsPermission(err) {
-			gplog.Warn("Ignoring filesystem %s on host %s when checking disk space. Unable to stat filesystem due to %v.", f.DirName, hostname, err)
-			continue
-		}
-
-		if err != nil {
-			return nil, xerrors.Errorf("stat'ing %s: %w", f.DirName, err)
-		}
-
-		fsByID[uint64(stat.Dev)] = f.DirName
+		return nil, err
 	}
 
 	for _, path := range paths {
@@ -136,6 +122,32 @@ func CheckUsage(streams step.OutStreams, d Disk, diskFreeRatio float64, paths ..
 	return usage, nil
 }
 
+// filesystemsByID finds the device ID for every filesystem. These are used to
+// map data directories to filesystems.
+func filesystemsByID(d Disk, hostname string) (map[uint64]string, error) {
+	fs, err := d.Filesystems()
+	if err != nil {
+		return nil, xerrors.Errorf("enumerating filesystems: %w", err)
+	}
+
+	fsByID := make(map[uint64]string)
+	for _, f := range fs.List {
+		stat, err := d.Stat(f.DirName)
+		if os.IsPermission(err) {
+			gplog.Warn("Ignoring filesystem %s on host %s when checking disk space. Unable to stat filesystem due to %v.", f.DirName, hostname, err)
+			continue
+		}
+
+		if err != nil {
+			return nil, xerrors.Errorf("stat'ing %s: %w", f.DirName, err)
+		}
+
+		fsByID[uint64(stat.Dev)] = f.DirName
+	}
+
+	return fsByID, nil
+}
+
 // Local is a standard implementation of the Disk interface that uses gosigar
 // and unix.Stat to obtain statistics for the local machine.
 var Local = local{}
@@ -159,3 +171,37 @@ func (_ local) Stat(path string) (*unix.Stat_t, error) {
 	err := unix.Stat(path, stat)
 	return stat, err
 }
+
+// DirSize returns the number of bytes used by the regular files under path.
+// Files with multiple hard links are only counted once.
+func (_ local) DirSize(path string) (uint64, error) {
+	type inode struct {
+		dev uint64
+		ino uint64
+	}
+
+	var size uint64
+	seen := make(map[inode]bool)
+	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Nlink > 1 {
+			key := inode{dev: uint64(stat.Dev), ino: stat.Ino}
+			if seen[key] {
+				return nil
+			}
+			seen[key] = true
+		}
+
+		size += uint64(info.Size())
+		return nil
+	})
+
+	return size, err
+}
@@ -6,6 +6,7 @@ package disk_test
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -18,6 +19,7 @@ import (
 	"github.com/greenplum-db/gpupgrade/utils"
 
 	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
 	"github.com/greenplum-db/gpupgrade/testutils/testlog"
 	"github.com/greenplum-db/gpupgrade/utils/disk"
 )
@@ -377,6 +379,30 @@ func TestLocal(t *testing.T) {
 	if (stat.Mode & unix.S_IFDIR) == 0 {
 		t.Errorf("Local.Stat(%q) did not stat a directory: %+v", dir, stat)
 	}
+
+	t.Run("DirSize counts hard linked files once", func(t *testing.T) {
+		dir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dir)
+
+		testutils.MustWriteToFile(t, filepath.Join(dir, "file"), "1234567890")
+		testutils.MustCreateDir(t, filepath.Join(dir, "subdir"))
+		testutils.MustWriteToFile(t, filepath.Join(dir, "subdir", "other"), "12345")
+
+		err := os.Link(filepath.Join(dir, "file"), filepath.Join(dir, "subdir", "link"))
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		size, err := disk.Local.DirSize(dir)
+		if err != nil {
+			t.Errorf("Local.DirSize(%q) returned error %#v", dir, err)
+		}
+
+		var expected uint64 = 15
+		if size != expected {
+			t.Errorf("Local.DirSize(%q) returned %d want %d", dir, size, expected)
+		}
+	})
 }
 
 // testDisk is a stub implementation of disk.Disk.
@@ -386,6 +412,7 @@ type testDisk struct {
 	filesystems func() (sigar.FileSystemList, error)
 	usage       func(string) (sigar.FileSystemUsage, error)
 	stat        func(string) (*unix.Stat_t, error)
+	dirSize     func(string) (uint64, error)
 }
 
 func (t testDisk) Filesystems() (sigar.FileSystemList, error) {
@@ -409,6 +436,13 @@ func (t testDisk) Stat(path string) (*unix.Stat_t, error) {
 	return t.stat(path)
 }
 
+func (t testDisk) DirSize(path string) (uint64, error) {
+	if t.dirSize == nil {
+		return 0, t.err
+	}
+	return t.dirSize(path)
+}
+
 func scale(n uint64, f float64) uint64 {
 	return uint64(float64(n) * f)
 }
@@ -0,0 +1,135 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package disk
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+type EstimateUsageType func(streams step.OutStreams, d Disk, safetyMargin float64, estimates ...*idl.DiskSpaceEstimate) (FileSystemDiskUsage, error)
+
+// EstimateUsage totals the space each estimate will consume per filesystem and
+// compares it to the space available on that filesystem. Unlike CheckUsage an
+// entry is returned for every filesystem the estimates touch, so callers can
+// report required versus available space. Use Insufficient to select the
+// filesystems that do not have enough space.
+//
+// The required space is increased by safetyMargin (e.g. 0.1 for 10%) to cover
+// the imprecision of the estimates. As with CheckUsage, required and available
+// space are reported in kilobytes.
+func EstimateUsage(streams step.OutStreams, d Disk, safetyMargin float64, estimates ...*idl.DiskSpaceEstimate) (FileSystemDiskUsage, error) {
+	hostname, err := utils.System.Hostname()
+	if err != nil {
+		return nil, xerrors.Errorf("determining hostname: %w", err)
+	}
+
+	fsByID, err := filesystemsByID(d, hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	// Directories such as the data directories are often the source of
+	// several estimates so cache their sizes rather than walk them again.
+	sizes := make(map[string]uint64)
+	requiredBytes := make(map[string]uint64)
+	available := make(map[string]uint64)
+
+	for _, estimate := range estimates {
+		var size uint64
+		for _, source := range estimate.GetSources() {
+			sourceSize, ok := sizes[source]
+			if !ok {
+				sourceSize, err = d.DirSize(source)
+				if err != nil {
+					return nil, xerrors.Errorf("determining size of %s: %w", source, err)
+				}
+
+				sizes[source] = sourceSize
+			}
+
+			size += sourceSize
+		}
+
+		bytes := uint64(estimate.GetFactor()*float64(size)) + estimate.GetExtraBytes()
+
+		// The destination such as a backup directory typically does not exist
+		// yet. Use its closest existing parent to find the filesystem.
+		path, stat, err := closestExistingPath(d, estimate.GetDestination())
+		if err != nil {
+			return nil, err
+		}
+
+		fs, ok := fsByID[uint64(stat.Dev)]
+		if !ok {
+			fs = path
+		}
+
+		if _, ok := available[fs]; !ok {
+			usage, err := d.Usage(path)
+			if err != nil {
+				return nil, xerrors.Errorf("getting fs usage for %s: %w", path, err)
+			}
+
+			available[fs] = usage.Avail
+		}
+
+		gplog.Debug("%s: %d bytes required on %s for %s", estimate.GetDestination(), bytes, fs, estimate.GetDescription())
+		requiredBytes[fs] += bytes
+	}
+
+	var usage FileSystemDiskUsage
+	for fs, bytes := range requiredBytes {
+		required := uint64(float64(bytes) * (1 + safetyMargin))
+
+		usage = append(usage, &idl.CheckDiskSpaceReply_DiskUsage{
+			Fs:        fs,
+			Host:      hostname,
+			Required:  (required + 1023) / 1024,
+			Available: available[fs],
+		})
+	}
+
+	sort.Sort(usage)
+	return usage, nil
+}
+
+// Insufficient returns the filesystems that require more space than is
+// available.
+func (f FileSystemDiskUsage) Insufficient() FileSystemDiskUsage {
+	var insufficient FileSystemDiskUsage
+	for _, usage := range f {
+		if usage.GetAvailable() < usage.GetRequired() {
+			insufficient = append(insufficient, usage)
+		}
+	}
+
+	return insufficient
+}
+
+func closestExistingPath(d Disk, path string) (string, *unix.Stat_t, error) {
+	for {
+		stat, err := d.Stat(path)
+		if err == nil {
+			return path, stat, nil
+		}
+
+		parent := filepath.Dir(path)
+		if !os.IsNotExist(err) || parent == path {
+			return "", nil, xerrors.Errorf("stat'ing %s: %w", path, err)
+		}
+
+		path = parent
+	}
+}
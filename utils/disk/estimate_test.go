@@ -0,0 +1,171 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package disk_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	sigar "github.com/cloudfoundry/gosigar"
+	"golang.org/x/sys/unix"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/step"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/disk"
+)
+
+func TestEstimateUsage(t *testing.T) {
+	testlog.SetupLogger()
+
+	host := "localhost"
+	utils.System.Hostname = func() (string, error) {
+		return host, nil
+	}
+	defer func() {
+		utils.System.Hostname = os.Hostname
+	}()
+
+	// This test disk has two mount points, / and /data, with 1 MiB and 10 MiB
+	// available respectively. Every directory is 1 MiB in size, and only
+	// directories under /data/existing and /home exist.
+	const MiB uint64 = 1024 * 1024
+	d := testDisk{
+		err: errors.New("should never happen"),
+
+		filesystems: func() (sigar.FileSystemList, error) {
+			return sigar.FileSystemList{List: []sigar.FileSystem{
+				{DirName: "/"},
+				{DirName: "/data"},
+			}}, nil
+		},
+
+		usage: func(path string) (sigar.FileSystemUsage, error) {
+			if strings.HasPrefix(path, "/data") {
+				return sigar.FileSystemUsage{Avail: 10 * 1024}, nil
+			}
+
+			return sigar.FileSystemUsage{Avail: 1024}, nil
+		},
+
+		stat: func(path string) (*unix.Stat_t, error) {
+			switch {
+			case path == "/", strings.HasPrefix(path, "/home"):
+				return &unix.Stat_t{Dev: 1}, nil
+			case path == "/data", strings.HasPrefix(path, "/data/existing"):
+				return &unix.Stat_t{Dev: 2}, nil
+			}
+
+			return nil, os.ErrNotExist
+		},
+
+		dirSize: func(path string) (uint64, error) {
+			return MiB, nil
+		},
+	}
+
+	cases := []struct {
+		name      string
+		margin    float64
+		estimates []*idl.DiskSpaceEstimate
+		expected  disk.FileSystemDiskUsage
+	}{
+		{
+			name: "sums the estimates for each filesystem",
+			estimates: []*idl.DiskSpaceEstimate{
+				{Destination: "/data/existing", Sources: []string{"/data/existing/seg1"}, Factor: 1},
+				{Destination: "/data/existing", Sources: []string{"/data/existing/seg2", "/data/existing/seg3"}, Factor: 1},
+				{Destination: "/home/gpadmin", ExtraBytes: MiB / 2},
+			},
+			expected: disk.FileSystemDiskUsage{
+				{Fs: "/", Host: host, Available: 1024, Required: 512},
+				{Fs: "/data", Host: host, Available: 10 * 1024, Required: 3 * 1024},
+			},
+		},
+		{
+			name:   "adds the safety margin to the required space",
+			margin: 0.5,
+			estimates: []*idl.DiskSpaceEstimate{
+				{Destination: "/data/existing", Sources: []string{"/data/existing/seg1"}, Factor: 2},
+			},
+			expected: disk.FileSystemDiskUsage{
+				{Fs: "/data", Host: host, Available: 10 * 1024, Required: 3 * 1024},
+			},
+		},
+		{
+			name: "uses the closest existing parent of destinations that do not exist",
+			estimates: []*idl.DiskSpaceEstimate{
+				{Destination: "/data/new/backup", ExtraBytes: MiB},
+				{Destination: "/home/gpadmin/.gpupgrade/backup", ExtraBytes: 2 * MiB},
+			},
+			expected: disk.FileSystemDiskUsage{
+				{Fs: "/", Host: host, Available: 1024, Required: 2 * 1024},
+				{Fs: "/data", Host: host, Available: 10 * 1024, Required: 1024},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := disk.EstimateUsage(step.DevNullStream, d, c.margin, c.estimates...)
+			if err != nil {
+				t.Fatalf("returned error %#v", err)
+			}
+
+			if !reflect.DeepEqual(actual, c.expected) {
+				t.Errorf("returned %v want %v", actual, c.expected)
+			}
+		})
+	}
+
+	t.Run("returns insufficient filesystems", func(t *testing.T) {
+		usage := disk.FileSystemDiskUsage{
+			{Fs: "/", Host: host, Available: 1024, Required: 2 * 1024},
+			{Fs: "/data", Host: host, Available: 10 * 1024, Required: 1024},
+		}
+
+		expected := disk.FileSystemDiskUsage{usage[0]}
+		if !reflect.DeepEqual(usage.Insufficient(), expected) {
+			t.Errorf("returned %v want %v", usage.Insufficient(), expected)
+		}
+	})
+
+	t.Run("errors when unable to determine the size of a source", func(t *testing.T) {
+		expected := errors.New("permission denied")
+		d := d
+		d.dirSize = func(path string) (uint64, error) {
+			return 0, expected
+		}
+
+		_, err := disk.EstimateUsage(step.DevNullStream, d, 0, &idl.DiskSpaceEstimate{
+			Destination: "/data/existing", Sources: []string{"/data/existing/seg1"}, Factor: 1,
+		})
+		if !errors.Is(err, expected) {
+			t.Errorf("returned error %#v want %#v", err, expected)
+		}
+	})
+
+	t.Run("errors when unable to stat a destination", func(t *testing.T) {
+		expected := os.ErrPermission
+		d := d
+		d.stat = func(path string) (*unix.Stat_t, error) {
+			if path == "/" || path == "/data" {
+				return &unix.Stat_t{Dev: 1}, nil
+			}
+
+			return nil, expected
+		}
+
+		_, err := disk.EstimateUsage(step.DevNullStream, d, 0, &idl.DiskSpaceEstimate{
+			Destination: "/data/existing", ExtraBytes: 1,
+		})
+		if !errors.Is(err, expected) {
+			t.Errorf("returned error %#v want %#v", err, expected)
+		}
+	})
+}
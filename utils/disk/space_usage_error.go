@@ -66,6 +66,32 @@ func (d SpaceUsageErr) Table() [][]string {
 	return rows
 }
 
+// Report returns a table of the required and available space for each
+// filesystem, sorted by host and filesystem.
+func Report(usage FileSystemDiskUsage) string {
+	var rows [][]string
+	for _, u := range usage {
+		rows = append(rows, []string{u.GetHost(), u.GetFs(), FormatBytes(u.GetAvailable()), FormatBytes(u.GetRequired())})
+	}
+
+	sort.Sort(tableRows(rows))
+	rows = append([][]string{{"Hostname", "Filesystem", "Available", "Required"}}, rows...)
+
+	var b strings.Builder
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+
+	for _, row := range rows {
+		for _, col := range row {
+			fmt.Fprintf(&t, "%s\t", col)
+		}
+		fmt.Fprintln(&t)
+	}
+
+	t.Flush()
+	return b.String()
+}
+
 func FormatBytes(kb uint64) string {
 	bytes := float64(kb)
 	units := []string{"KB", "MB", "GB", "TB", "PB"}
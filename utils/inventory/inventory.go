@@ -0,0 +1,337 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+)
+
+// The names of the snapshots taken during the upgrade.
+const (
+	Source              = "source"
+	TargetAfterExecute  = "target-after-execute"
+	TargetAfterFinalize = "target-after-finalize"
+)
+
+// Snapshot is an inventory of the user objects in every database of a
+// cluster at a point in time.
+type Snapshot struct {
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Taken     time.Time         `json:"taken"`
+	Roles     int64             `json:"roles"`
+	Databases map[string]Counts `json:"databases"`
+}
+
+// Counts are the number of user objects in a database by type, along with
+// the total size of its tables and materialized views.
+type Counts struct {
+	HeapTables        int64 `json:"heapTables"`
+	AOTables          int64 `json:"aoTables"`
+	AOCOTables        int64 `json:"aocoTables"`
+	ExternalTables    int64 `json:"externalTables"`
+	PartitionedTables int64 `json:"partitionedTables"`
+	Indexes           int64 `json:"indexes"`
+	Views             int64 `json:"views"`
+	Functions         int64 `json:"functions"`
+	RelationBytes     int64 `json:"relationBytes"`
+}
+
+type object struct {
+	name  string
+	count int64
+}
+
+// objects returns the object counts in report order. RelationBytes is
+// excluded since sizes are expected to change across an upgrade.
+func (c Counts) objects() []object {
+	return []object{
+		{"heap tables", c.HeapTables},
+		{"AO tables", c.AOTables},
+		{"AOCO tables", c.AOCOTables},
+		{"external tables", c.ExternalTables},
+		{"partitioned tables", c.PartitionedTables},
+		{"indexes", c.Indexes},
+		{"views", c.Views},
+		{"functions", c.Functions},
+	}
+}
+
+// UserNamespaces restricts a query over pg_namespace aliased as n to the
+// schemas containing user objects.
+const UserNamespaces = `n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast', 'pg_bitmapindex', 'pg_aoseg', 'gp_toolkit')
+	AND n.nspname NOT LIKE 'pg_temp_%' AND n.nspname NOT LIKE 'pg_toast_temp_%'`
+
+// In 7X and later the storage type is the table access method, external
+// tables are foreign tables, and partitioned tables have their own relkind.
+const relationsQuery = `SELECT
+	coalesce(sum(CASE WHEN c.relkind = 'r' AND am.amname = 'heap' THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'r' AND am.amname = 'ao_row' THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'r' AND am.amname = 'ao_column' THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'f' THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'p' AND NOT c.relispartition THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind IN ('i', 'I') THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind IN ('v', 'm') THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind IN ('r', 'm') THEN pg_total_relation_size(c.oid) ELSE 0 END), 0)::bigint
+FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN pg_am am ON am.oid = c.relam
+WHERE ` + UserNamespaces + `;`
+
+// Prior to 7X the storage type is relstorage, and partitioned tables are
+// ordinary tables listed in pg_partition. As in 7X only the leaf partitions
+// are counted as tables, and only the roots as partitioned tables, so that the
+// counts can be compared with 7X. The non-leaf partitions are the roots and
+// the intermediate partitions of multi-level partitioned tables, whose rules
+// are the parents of other rules.
+const relationsQuery6X = `SELECT
+	coalesce(sum(CASE WHEN c.relkind = 'r' AND c.relstorage = 'h' AND p.oid IS NULL THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'r' AND c.relstorage = 'a' AND p.oid IS NULL THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'r' AND c.relstorage = 'c' AND p.oid IS NULL THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'r' AND c.relstorage = 'x' THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN p.isroot THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'i' THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'v' THEN 1 ELSE 0 END), 0)::bigint,
+	coalesce(sum(CASE WHEN c.relkind = 'r' AND c.relstorage <> 'x' AND p.oid IS NULL THEN pg_total_relation_size(c.oid) ELSE 0 END), 0)::bigint
+FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	LEFT JOIN (
+		SELECT parrelid AS oid, true AS isroot FROM pg_partition WHERE parlevel = 0
+		UNION
+		SELECT parent.parchildrelid, false FROM pg_partition_rule parent
+			JOIN pg_partition_rule child ON child.parparentrule = parent.oid
+	) p ON p.oid = c.oid
+WHERE ` + UserNamespaces + `;`
+
+const functionsQuery = `SELECT count(*)
+FROM pg_proc p
+	JOIN pg_namespace n ON n.oid = p.pronamespace
+WHERE ` + UserNamespaces + `;`
+
+// QueryDatabases returns the databases of the cluster that accept
+// connections.
+func QueryDatabases(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT datname FROM pg_database WHERE datallowconn ORDER BY datname;`)
+	if err != nil {
+		return nil, xerrors.Errorf("querying databases: %w", err)
+	}
+	defer rows.Close()
+
+	var databases []string
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, xerrors.Errorf("scanning databases: %w", err)
+		}
+
+		databases = append(databases, database)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iterating databases: %w", err)
+	}
+
+	return databases, nil
+}
+
+func QueryRoles(db *sql.DB) (int64, error) {
+	var roles int64
+	if err := db.QueryRow(`SELECT count(*) FROM pg_roles;`).Scan(&roles); err != nil {
+		return 0, xerrors.Errorf("querying roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// QueryCounts returns the counts of the user objects in the connected
+// database.
+func QueryCounts(db *sql.DB, version semver.Version) (Counts, error) {
+	query := relationsQuery
+	if version.Major < 7 {
+		query = relationsQuery6X
+	}
+
+	var c Counts
+	err := db.QueryRow(query).Scan(&c.HeapTables, &c.AOTables, &c.AOCOTables, &c.ExternalTables,
+		&c.PartitionedTables, &c.Indexes, &c.Views, &c.RelationBytes)
+	if err != nil {
+		return Counts{}, xerrors.Errorf("querying relations: %w", err)
+	}
+
+	if err := db.QueryRow(functionsQuery).Scan(&c.Functions); err != nil {
+		return Counts{}, xerrors.Errorf("querying functions: %w", err)
+	}
+
+	return c, nil
+}
+
+// Path returns the path of the named snapshot in the inventory directory.
+func Path(dir string, name string) string {
+	return filepath.Join(dir, name+".json")
+}
+
+func Save(dir string, snapshot Snapshot) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("marshal inventory: %w", err)
+	}
+
+	return utils.AtomicallyWrite(Path(dir, snapshot.Name), data)
+}
+
+func Load(path string) (Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, xerrors.Errorf("parsing inventory %s: %w", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// Difference is an object count that differs between two snapshots.
+// Database is empty for cluster-wide objects such as roles.
+type Difference struct {
+	Database string
+	Object   string
+	Source   int64
+	Target   int64
+}
+
+// Diff returns the object counts that differ between the source and target
+// snapshots sorted by database. A database missing from either snapshot is
+// reported as a difference, and its objects are counted as zero.
+func Diff(source, target Snapshot) []Difference {
+	var diffs []Difference
+	if source.Roles != target.Roles {
+		diffs = append(diffs, Difference{Object: "roles", Source: source.Roles, Target: target.Roles})
+	}
+
+	for _, database := range databases(source, target) {
+		_, inSource := source.Databases[database]
+		_, inTarget := target.Databases[database]
+		if inSource != inTarget {
+			diffs = append(diffs, Difference{Database: database, Object: "database", Source: exists(inSource), Target: exists(inTarget)})
+		}
+
+		sourceObjects := source.Databases[database].objects()
+		targetObjects := target.Databases[database].objects()
+
+		for i := range sourceObjects {
+			if sourceObjects[i].count != targetObjects[i].count {
+				diffs = append(diffs, Difference{
+					Database: database,
+					Object:   sourceObjects[i].name,
+					Source:   sourceObjects[i].count,
+					Target:   targetObjects[i].count,
+				})
+			}
+		}
+	}
+
+	return diffs
+}
+
+func exists(present bool) int64 {
+	if present {
+		return 1
+	}
+	return 0
+}
+
+func databases(source, target Snapshot) []string {
+	seen := make(map[string]bool)
+	for database := range source.Databases {
+		seen[database] = true
+	}
+	for database := range target.Databases {
+		seen[database] = true
+	}
+
+	var databases []string
+	for database := range seen {
+		databases = append(databases, database)
+	}
+	sort.Strings(databases)
+
+	return databases
+}
+
+// Report returns a table comparing every object count in the source and
+// target snapshots, followed by a summary of the differences.
+func Report(source, target Snapshot) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Inventory of %s (Greenplum %s, %s) compared with %s (Greenplum %s, %s):\n\n",
+		target.Name, target.Version, target.Taken.Format(time.RFC3339),
+		source.Name, source.Version, source.Taken.Format(time.RFC3339))
+
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+
+	status := func(source, target int64) string {
+		if source != target {
+			return "DIFFERS"
+		}
+		return "ok"
+	}
+
+	fmt.Fprintln(&t, "Database\tObject\tSource\tTarget\tStatus")
+	fmt.Fprintf(&t, "%s\t%s\t%d\t%d\t%s\n", "(all)", "roles", source.Roles, target.Roles, status(source.Roles, target.Roles))
+
+	for _, database := range databases(source, target) {
+		sourceCounts, inSource := source.Databases[database]
+		targetCounts, inTarget := target.Databases[database]
+
+		if !inTarget {
+			fmt.Fprintf(&t, "%s\t%s\t%s\t%s\t%s\n", database, "database", "present", "missing", "DIFFERS")
+		}
+
+		if !inSource {
+			fmt.Fprintf(&t, "%s\t%s\t%s\t%s\t%s\n", database, "database", "missing", "present", "DIFFERS")
+		}
+
+		sourceObjects := sourceCounts.objects()
+		targetObjects := targetCounts.objects()
+		for i := range sourceObjects {
+			fmt.Fprintf(&t, "%s\t%s\t%d\t%d\t%s\n", database, sourceObjects[i].name,
+				sourceObjects[i].count, targetObjects[i].count, status(sourceObjects[i].count, targetObjects[i].count))
+		}
+
+		fmt.Fprintf(&t, "%s\t%s\t%d\t%d\t%s\n", database, "relation bytes",
+			sourceCounts.RelationBytes, targetCounts.RelationBytes, "-")
+	}
+
+	t.Flush()
+
+	diffs := Diff(source, target)
+	if len(diffs) == 0 {
+		fmt.Fprintf(&b, "\nAll object counts match.\n")
+	} else {
+		fmt.Fprintf(&b, "\n%d object counts differ.\n", len(diffs))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,272 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
+)
+
+func TestQueryDatabases(t *testing.T) {
+	t.Run("returns the databases that accept connections", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		mock.ExpectQuery(`SELECT datname FROM pg_database WHERE datallowconn`).
+			WillReturnRows(sqlmock.NewRows([]string{"datname"}).AddRow("postgres").AddRow("template1"))
+
+		databases, err := inventory.QueryDatabases(db)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := []string{"postgres", "template1"}
+		if !reflect.DeepEqual(databases, expected) {
+			t.Errorf("got %v want %v", databases, expected)
+		}
+	})
+
+	t.Run("errors when the query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		expected := errors.New("permission denied")
+		mock.ExpectQuery(`SELECT datname FROM pg_database`).WillReturnError(expected)
+
+		_, err = inventory.QueryDatabases(db)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestQueryRoles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock: %v", err)
+	}
+	defer testutils.FinishMock(mock, t)
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM pg_roles`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	roles, err := inventory.QueryRoles(db)
+	if err != nil {
+		t.Errorf("unexpected error %#v", err)
+	}
+
+	if roles != 3 {
+		t.Errorf("got %d roles want %d", roles, 3)
+	}
+}
+
+func TestQueryCounts(t *testing.T) {
+	columns := []string{"heap", "ao", "aoco", "external", "partitioned", "indexes", "views", "bytes"}
+
+	cases := []struct {
+		name    string
+		version semver.Version
+		query   string
+	}{
+		{
+			name:    "uses the table access method for 7X",
+			version: semver.MustParse("7.0.0"),
+			query:   `LEFT JOIN pg_am am ON am.oid = c.relam`,
+		},
+		{
+			name:    "uses relstorage and pg_partition for 6X",
+			version: semver.MustParse("6.20.0"),
+			query:   `SELECT parrelid AS oid, true AS isroot FROM pg_partition WHERE parlevel = 0`,
+		},
+		{
+			name:    "excludes the intermediate partitions of multi-level partitioned tables for 6X",
+			version: semver.MustParse("6.20.0"),
+			query:   `SELECT parent.parchildrelid, false FROM pg_partition_rule parent\s+JOIN pg_partition_rule child ON child.parparentrule = parent.oid`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("sqlmock: %v", err)
+			}
+			defer testutils.FinishMock(mock, t)
+
+			mock.ExpectQuery(c.query).
+				WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 2, 3, 4, 5, 6, 7, 8192))
+			mock.ExpectQuery(`FROM pg_proc p`).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(9))
+
+			counts, err := inventory.QueryCounts(db, c.version)
+			if err != nil {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			expected := inventory.Counts{
+				HeapTables:        1,
+				AOTables:          2,
+				AOCOTables:        3,
+				ExternalTables:    4,
+				PartitionedTables: 5,
+				Indexes:           6,
+				Views:             7,
+				Functions:         9,
+				RelationBytes:     8192,
+			}
+			if counts != expected {
+				t.Errorf("got %+v want %+v", counts, expected)
+			}
+		})
+	}
+
+	t.Run("errors when querying functions fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		mock.ExpectQuery(`FROM pg_class c`).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(1, 2, 3, 4, 5, 6, 7, 8192))
+
+		expected := errors.New("permission denied")
+		mock.ExpectQuery(`FROM pg_proc p`).WillReturnError(expected)
+
+		_, err = inventory.QueryCounts(db, semver.MustParse("7.0.0"))
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	snapshot := inventory.Snapshot{
+		Name:      inventory.Source,
+		Version:   "7.0.0",
+		Taken:     time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Roles:     3,
+		Databases: map[string]inventory.Counts{"postgres": {HeapTables: 1, RelationBytes: 8192}},
+	}
+
+	err := inventory.Save(dir, snapshot)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	actual, err := inventory.Load(inventory.Path(dir, inventory.Source))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if !reflect.DeepEqual(actual, snapshot) {
+		t.Errorf("got %+v want %+v", actual, snapshot)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	source := inventory.Snapshot{
+		Roles: 3,
+		Databases: map[string]inventory.Counts{
+			"postgres": {HeapTables: 2, Indexes: 1, RelationBytes: 8192},
+			"sales":    {AOTables: 1},
+		},
+	}
+
+	t.Run("returns no differences for matching snapshots", func(t *testing.T) {
+		target := source
+		target.Databases = map[string]inventory.Counts{
+			"postgres": {HeapTables: 2, Indexes: 1, RelationBytes: 16384},
+			"sales":    {AOTables: 1},
+		}
+
+		if diffs := inventory.Diff(source, target); len(diffs) != 0 {
+			t.Errorf("got differences %+v want none", diffs)
+		}
+	})
+
+	t.Run("returns the counts that differ", func(t *testing.T) {
+		target := inventory.Snapshot{
+			Roles: 2,
+			Databases: map[string]inventory.Counts{
+				"postgres": {HeapTables: 1, Indexes: 1},
+			},
+		}
+
+		expected := []inventory.Difference{
+			{Object: "roles", Source: 3, Target: 2},
+			{Database: "postgres", Object: "heap tables", Source: 2, Target: 1},
+			{Database: "sales", Object: "database", Source: 1, Target: 0},
+			{Database: "sales", Object: "AO tables", Source: 1, Target: 0},
+		}
+
+		diffs := inventory.Diff(source, target)
+		if !reflect.DeepEqual(diffs, expected) {
+			t.Errorf("got %+v want %+v", diffs, expected)
+		}
+	})
+}
+
+func TestReport(t *testing.T) {
+	source := inventory.Snapshot{
+		Name:      inventory.Source,
+		Version:   "7.0.0",
+		Taken:     time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Roles:     3,
+		Databases: map[string]inventory.Counts{"postgres": {HeapTables: 2, RelationBytes: 8192}},
+	}
+
+	target := inventory.Snapshot{
+		Name:      inventory.TargetAfterExecute,
+		Version:   "7.1.0",
+		Taken:     time.Date(2022, 1, 2, 4, 4, 5, 0, time.UTC),
+		Roles:     3,
+		Databases: map[string]inventory.Counts{"postgres": {HeapTables: 1, RelationBytes: 4096}},
+	}
+
+	report := inventory.Report(source, target)
+
+	expected := `Inventory of target-after-execute (Greenplum 7.1.0, 2022-01-02T04:04:05Z) compared with source (Greenplum 7.0.0, 2022-01-02T03:04:05Z):
+
+Database  Object              Source  Target  Status
+(all)     roles               3       3       ok
+postgres  heap tables         2       1       DIFFERS
+postgres  AO tables           0       0       ok
+postgres  AOCO tables         0       0       ok
+postgres  external tables     0       0       ok
+postgres  partitioned tables  0       0       ok
+postgres  indexes             0       0       ok
+postgres  views               0       0       ok
+postgres  functions           0       0       ok
+postgres  relation bytes      8192    4096    -
+
+1 object counts differ.
+`
+	if report != expected {
+		t.Errorf("got report %q want %q", report, expected)
+	}
+
+	report = inventory.Report(source, source)
+	if !strings.Contains(report, "All object counts match.") {
+		t.Errorf("expected report to contain %q\n%s", "All object counts match.", report)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"google.golang.org/grpc/metadata"
+)
+
+// Fields identify what the process is doing in the JSON log entries.
+type Fields struct {
+	UpgradeID string
+	Step      string
+	Substep   string
+}
+
+type fieldsKey struct{}
+
+// WithFields returns a context carrying the fields, such as of the step and
+// substep being run or of the request being served by an agent.
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, fields)
+}
+
+// ContextFields returns the fields carried by the context.
+func ContextFields(ctx context.Context) Fields {
+	fields, _ := ctx.Value(fieldsKey{}).(Fields)
+	return fields
+}
+
+// fieldsPrefix starts a message logged by Info or Debug in the JSON format with
+// the fields of its context. The JSON writer records and removes it.
+var fieldsPrefix = regexp.MustCompile(`^\[upgrade_id=(\S*) step=(\S*) substep=(\S*)\] `)
+
+func prefix(ctx context.Context) string {
+	fields := ContextFields(ctx)
+	if fields == (Fields{}) || CurrentFormat() != JSON {
+		return ""
+	}
+
+	return fmt.Sprintf("[upgrade_id=%s step=%s substep=%s] ", fields.UpgradeID, fields.Step, fields.Substep)
+}
+
+// Info logs the message with gplog.Info. The JSON log entry records the
+// fields of the context.
+func Info(ctx context.Context, format string, args ...interface{}) {
+	gplog.Info("%s%s", prefix(ctx), fmt.Sprintf(format, args...))
+}
+
+// Debug logs the message with gplog.Debug. The JSON log entry records the
+// fields of the context.
+func Debug(ctx context.Context, format string, args ...interface{}) {
+	gplog.Debug("%s%s", prefix(ctx), fmt.Sprintf(format, args...))
+}
+
+const (
+	upgradeIDKey = "gpupgrade-upgrade-id"
+	stepKey      = "gpupgrade-step"
+	substepKey   = "gpupgrade-substep"
+)
+
+// OutgoingContext sends the fields of the context with a request to an agent
+// so that the agent logs them.
+func OutgoingContext(ctx context.Context) context.Context {
+	fields := ContextFields(ctx)
+	if fields == (Fields{}) {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx,
+		upgradeIDKey, fields.UpgradeID,
+		stepKey, fields.Step,
+		substepKey, fields.Substep)
+}
+
+// IncomingFields returns the fields sent with a request by OutgoingContext.
+func IncomingFields(ctx context.Context) Fields {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Fields{}
+	}
+
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	return Fields{
+		UpgradeID: get(upgradeIDKey),
+		Step:      get(stepKey),
+		Substep:   get(substepKey),
+	}
+}
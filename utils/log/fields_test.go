@@ -0,0 +1,50 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package log_test
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+
+	"github.com/greenplum-db/gpupgrade/utils/log"
+)
+
+func TestFields(t *testing.T) {
+	t.Run("carries the fields in the context", func(t *testing.T) {
+		expected := log.Fields{UpgradeID: "ABC", Step: "EXECUTE", Substep: "UPGRADE_MASTER"}
+		ctx := log.WithFields(context.Background(), expected)
+
+		if fields := log.ContextFields(ctx); fields != expected {
+			t.Errorf("got fields %+v want %+v", fields, expected)
+		}
+
+		if fields := log.ContextFields(context.Background()); fields != (log.Fields{}) {
+			t.Errorf("got fields %+v want none", fields)
+		}
+	})
+
+	t.Run("sends the fields with requests", func(t *testing.T) {
+		expected := log.Fields{UpgradeID: "ABC", Step: "EXECUTE", Substep: "UPGRADE_PRIMARIES"}
+		ctx := log.WithFields(context.Background(), expected)
+
+		md, _ := metadata.FromOutgoingContext(log.OutgoingContext(ctx))
+		fields := log.IncomingFields(metadata.NewIncomingContext(context.Background(), md))
+		if fields != expected {
+			t.Errorf("got fields %+v want %+v", fields, expected)
+		}
+	})
+
+	t.Run("sends nothing without fields", func(t *testing.T) {
+		ctx := context.Background()
+		if log.OutgoingContext(ctx) != ctx {
+			t.Errorf("expected the context to be unchanged")
+		}
+
+		if fields := log.IncomingFields(ctx); fields != (log.Fields{}) {
+			t.Errorf("got fields %+v want none", fields)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gp-common-go-libs/operating"
+	"golang.org/x/xerrors"
+)
+
+// The log formats.
+const (
+	Text = "text"
+	JSON = "json"
+)
+
+var Formats = []string{Text, JSON}
+
+func ParseFormat(input string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(input))
+	for _, f := range Formats {
+		if format == f {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("Invalid log format %q. Please specify one of %s.", input, strings.Join(Formats, ", "))
+}
+
+var (
+	formatMu      sync.Mutex
+	currentFormat = Text
+)
+
+// Initialize logs the program to a file in logdir. The text format is that of
+// gplog. The JSON format writes an Entry per line to a .jsonl file named like
+// the text log file, while the terminal output remains text. The entries
+// record the fields of the program, such as its upgrade ID, unless they are
+// logged with the fields of a context by Info or Debug.
+func Initialize(program string, logdir string, format string, fields Fields) error {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+
+	if format != JSON {
+		gplog.InitializeLogging(program, logdir)
+		currentFormat = Text
+		return nil
+	}
+
+	if err := os.MkdirAll(logdir, 0755); err != nil {
+		return xerrors.Errorf("creating log directory: %w", err)
+	}
+
+	path := filepath.Join(logdir, fmt.Sprintf("%s_%s.jsonl", program, operating.System.Now().Format("20060102")))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return xerrors.Errorf("opening log file: %w", err)
+	}
+
+	gplog.SetLogger(gplog.NewLogger(os.Stdout, os.Stderr, NewJSONWriter(file, program, fields), path, gplog.LOGINFO, program))
+	gplog.SetExitFunc(func() { os.Exit(1) })
+	currentFormat = JSON
+	return nil
+}
+
+// CurrentFormat is the format logging was initialized with.
+func CurrentFormat() string {
+	formatMu.Lock()
+	defer formatMu.Unlock()
+
+	return currentFormat
+}
+
+// Entry is a line of the JSON log. ContentID is set for messages about a
+// segment, which start with ContentPrefix.
+type Entry struct {
+	Timestamp string `json:"timestamp"`
+	Level     string `json:"level"`
+	Service   string `json:"service"`
+	UpgradeID string `json:"upgrade_id,omitempty"`
+	Step      string `json:"step,omitempty"`
+	Substep   string `json:"substep,omitempty"`
+	Host      string `json:"host"`
+	ContentID *int32 `json:"content_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// ContentPrefix starts a message about the segment with the content ID.
+func ContentPrefix(contentID int32) string {
+	return fmt.Sprintf("content %d: ", contentID)
+}
+
+var (
+	// gplogHeader matches the prefix gplog writes before each message, such
+	// as "20221012:10:11:12 gpupgrade_hub:gpadmin:mdw:012345-[INFO]:-".
+	gplogHeader = regexp.MustCompile(`^\d{8}:\d{2}:\d{2}:\d{2} .*?-\[([A-Z]+)\]:-`)
+	content     = regexp.MustCompile(`^content (-?\d+): `)
+)
+
+type jsonWriter struct {
+	mu      sync.Mutex
+	writer  io.Writer
+	service string
+	host    string
+	fields  func() Fields
+	lines   bool
+	buf     []byte
+}
+
+// NewJSONWriter writes each gplog message written to it as an Entry with the
+// fields, or with those the message was logged with by Info or Debug.
+func NewJSONWriter(writer io.Writer, service string, fields Fields) io.WriteCloser {
+	return newJSONWriter(writer, service, func() Fields { return fields }, false)
+}
+
+// NewJSONLineWriter writes each non-empty line of output written to it, such
+// as the output of a substep, as an INFO Entry with the fields returned when
+// the line is written. Close writes any partial last line.
+func NewJSONLineWriter(writer io.Writer, service string, fields func() Fields) io.WriteCloser {
+	return newJSONWriter(writer, service, fields, true)
+}
+
+func newJSONWriter(writer io.Writer, service string, fields func() Fields, lines bool) *jsonWriter {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+
+	return &jsonWriter{writer: writer, service: service, host: host, fields: fields, lines: lines}
+}
+
+func (w *jsonWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.lines {
+		// gplog writes each message, including any newlines in it, at once.
+		return len(p), w.write(strings.TrimSuffix(string(p), "\n"))
+	}
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		line := strings.TrimSuffix(string(w.buf[:i]), "\r")
+		w.buf = w.buf[i+1:]
+		if line == "" {
+			continue
+		}
+
+		if err := w.write(line); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *jsonWriter) write(message string) error {
+	fields := w.fields()
+	entry := Entry{
+		Timestamp: operating.System.Now().Format(time.RFC3339Nano),
+		Level:     "INFO",
+		Service:   w.service,
+		UpgradeID: fields.UpgradeID,
+		Step:      fields.Step,
+		Substep:   fields.Substep,
+		Host:      w.host,
+		Message:   message,
+	}
+
+	if !w.lines {
+		if match := gplogHeader.FindStringSubmatch(entry.Message); match != nil {
+			entry.Level = match[1]
+			entry.Message = entry.Message[len(match[0]):]
+		}
+
+		if match := fieldsPrefix.FindStringSubmatch(entry.Message); match != nil {
+			entry.UpgradeID, entry.Step, entry.Substep = match[1], match[2], match[3]
+			entry.Message = entry.Message[len(match[0]):]
+		}
+	}
+
+	if match := content.FindStringSubmatch(entry.Message); match != nil {
+		if id, err := strconv.ParseInt(match[1], 10, 32); err == nil {
+			contentID := int32(id)
+			entry.ContentID = &contentID
+			entry.Message = entry.Message[len(match[0]):]
+		}
+	}
+
+	var line bytes.Buffer
+	encoder := json.NewEncoder(&line)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(entry); err != nil {
+		return err
+	}
+
+	_, err := w.writer.Write(line.Bytes())
+	return err
+}
+
+// Close writes any partial last line and closes the underlying writer when it
+// is an io.Closer.
+func (w *jsonWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	if len(w.buf) > 0 {
+		err = w.write(string(w.buf))
+		w.buf = nil
+	}
+
+	if closer, ok := w.writer.(io.Closer); ok {
+		if cErr := closer.Close(); err == nil {
+			err = cErr
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package log_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"github.com/greenplum-db/gp-common-go-libs/operating"
+
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/log"
+)
+
+func decodeEntries(t *testing.T, contents []byte) []log.Entry {
+	t.Helper()
+
+	var entries []log.Entry
+	for _, line := range strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n") {
+		var entry log.Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("unexpected error %+v decoding %q", err, line)
+		}
+
+		if _, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err != nil {
+			t.Errorf("unexpected error %+v parsing timestamp of %+v", err, entry)
+		}
+		entry.Timestamp = ""
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := log.ParseFormat(" JSON ")
+	if err != nil || format != log.JSON {
+		t.Errorf("got %q, %v want %q", format, err, log.JSON)
+	}
+
+	_, err = log.ParseFormat("xml")
+	expected := `Invalid log format "xml". Please specify one of text, json.`
+	if err == nil || err.Error() != expected {
+		t.Errorf("got error %v want %q", err, expected)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	host, _ := os.Hostname()
+	contentID := int32(2)
+
+	t.Run("writes each gplog message as an entry", func(t *testing.T) {
+		var buf bytes.Buffer
+		stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+		gplog.SetLogger(gplog.NewLogger(stdout, stderr, log.NewJSONWriter(&buf, "gpupgrade_hub", log.Fields{UpgradeID: "ABC"}), "test.jsonl", gplog.LOGINFO, "gpupgrade_hub"))
+		defer testlog.SetupLogger()
+
+		gplog.Info("%s/usr/local/gpdb6/bin/pg_upgrade --retain", log.ContentPrefix(2))
+		gplog.Error("failed <to> connect:\nconnection refused")
+
+		expected := []log.Entry{
+			{Level: "INFO", Service: "gpupgrade_hub", UpgradeID: "ABC", Host: host, ContentID: &contentID, Message: "/usr/local/gpdb6/bin/pg_upgrade --retain"},
+			{Level: "ERROR", Service: "gpupgrade_hub", UpgradeID: "ABC", Host: host, Message: "failed <to> connect:\nconnection refused"},
+		}
+		if entries := decodeEntries(t, buf.Bytes()); !reflect.DeepEqual(entries, expected) {
+			t.Errorf("got entries %+v want %+v", entries, expected)
+		}
+
+		if !strings.Contains(buf.String(), `"message":"failed <to> connect:\nconnection refused"`) {
+			t.Errorf("expected the message to not be HTML escaped in %s", buf.String())
+		}
+
+		// The terminal output remains text.
+		if !strings.Contains(stderr.String(), "-[ERROR]:-failed <to> connect:") {
+			t.Errorf("got stderr %q want the text format", stderr.String())
+		}
+	})
+
+	t.Run("writes each line of output as an entry", func(t *testing.T) {
+		var buf bytes.Buffer
+		fields := log.Fields{UpgradeID: "ABC", Step: "EXECUTE"}
+		writer := log.NewJSONLineWriter(&buf, "gpupgrade_hub", func() log.Fields { return fields })
+
+		for _, chunk := range []string{"\nExecute in progress.\n", "Performing Consistency Checks\r\n", "Checking cluster versions "} {
+			if _, err := writer.Write([]byte(chunk)); err != nil {
+				t.Fatalf("unexpected error %+v", err)
+			}
+		}
+
+		// The fields are those when each line is completed.
+		fields.Substep = "UPGRADE_MASTER"
+		if _, err := writer.Write([]byte("ok")); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if err := writer.Close(); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		var messages []string
+		var substeps []string
+		for _, entry := range decodeEntries(t, buf.Bytes()) {
+			if entry.Level != "INFO" || entry.Service != "gpupgrade_hub" || entry.UpgradeID != "ABC" || entry.Step != "EXECUTE" || entry.Host != host {
+				t.Errorf("got entry %+v", entry)
+			}
+			messages = append(messages, entry.Message)
+			substeps = append(substeps, entry.Substep)
+		}
+
+		expected := []string{"Execute in progress.", "Performing Consistency Checks", "Checking cluster versions ok"}
+		if !reflect.DeepEqual(messages, expected) {
+			t.Errorf("got messages %q want %q", messages, expected)
+		}
+
+		expected = []string{"", "", "UPGRADE_MASTER"}
+		if !reflect.DeepEqual(substeps, expected) {
+			t.Errorf("got substeps %q want %q", substeps, expected)
+		}
+	})
+}
+
+func TestInitialize(t *testing.T) {
+	defer testlog.SetupLogger()
+
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logdir := filepath.Join(dir, "gpAdminLogs", "gpupgrade")
+	if err := log.Initialize("gpupgrade_hub", logdir, log.JSON, log.Fields{UpgradeID: "ABC"}); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	if log.CurrentFormat() != log.JSON {
+		t.Errorf("got format %q want %q", log.CurrentFormat(), log.JSON)
+	}
+
+	gplog.Debug("started")
+
+	ctx := log.WithFields(context.Background(), log.Fields{UpgradeID: "ABC", Step: "EXECUTE", Substep: "UPGRADE_PRIMARIES"})
+	log.Info(ctx, "%supgrading", log.ContentPrefix(2))
+
+	path := filepath.Join(logdir, "gpupgrade_hub_"+operating.System.Now().Format("20060102")+".jsonl")
+	if gplog.GetLogFilePath() != path {
+		t.Errorf("got log file %q want %q", gplog.GetLogFilePath(), path)
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	host, _ := os.Hostname()
+	contentID := int32(2)
+	expected := []log.Entry{
+		{Level: "DEBUG", Service: "gpupgrade_hub", UpgradeID: "ABC", Host: host, Message: "started"},
+		{Level: "INFO", Service: "gpupgrade_hub", UpgradeID: "ABC", Step: "EXECUTE", Substep: "UPGRADE_PRIMARIES", Host: host, ContentID: &contentID, Message: "upgrading"},
+	}
+	if entries := decodeEntries(t, contents); !reflect.DeepEqual(entries, expected) {
+		t.Errorf("got entries %+v want %+v", entries, expected)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics exposes gauges and counters in the Prometheus text
+// exposition format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	gauge   = "gauge"
+	counter = "counter"
+)
+
+// Registry holds the metrics served by its handler.
+type Registry struct {
+	mu         sync.Mutex
+	vecs       []*Vec
+	collectors []func()
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Vec is a gauge or counter with a sample for each combination of label
+// values.
+type Vec struct {
+	mu      sync.Mutex
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	samples map[string]*sample
+}
+
+type sample struct {
+	values []string
+	value  float64
+}
+
+// NewGauge registers a gauge with the label names.
+func (r *Registry) NewGauge(name string, help string, labels ...string) *Vec {
+	return r.register(name, help, gauge, labels)
+}
+
+// NewCounter registers a counter with the label names.
+func (r *Registry) NewCounter(name string, help string, labels ...string) *Vec {
+	return r.register(name, help, counter, labels)
+}
+
+func (r *Registry) register(name string, help string, kind string, labels []string) *Vec {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v := &Vec{name: name, help: help, kind: kind, labels: labels, samples: make(map[string]*sample)}
+	r.vecs = append(r.vecs, v)
+	return v
+}
+
+// OnCollect registers a function that updates metrics just before they are
+// written, such as to report current state rather than events.
+func (r *Registry) OnCollect(collect func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.collectors = append(r.collectors, collect)
+}
+
+// Set sets the sample with the label values. It panics if the number of label
+// values does not match the label names.
+func (v *Vec) Set(value float64, labelValues ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.sample(labelValues).value = value
+}
+
+// Add adds delta to the sample with the label values. Counters only increase,
+// so negative deltas are ignored for them.
+func (v *Vec) Add(delta float64, labelValues ...string) {
+	if v.kind == counter && delta < 0 {
+		return
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.sample(labelValues).value += delta
+}
+
+// Value returns the sample with the label values, or zero if it is not set.
+func (v *Vec) Value(labelValues ...string) float64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if s, ok := v.samples[key(labelValues)]; ok {
+		return s.value
+	}
+
+	return 0
+}
+
+// Reset removes all samples.
+func (v *Vec) Reset() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.samples = make(map[string]*sample)
+}
+
+func (v *Vec) sample(labelValues []string) *sample {
+	if len(labelValues) != len(v.labels) {
+		panic(fmt.Sprintf("metric %s has labels %q but got values %q", v.name, v.labels, labelValues))
+	}
+
+	k := key(labelValues)
+	s, ok := v.samples[k]
+	if !ok {
+		s = &sample{values: append([]string(nil), labelValues...)}
+		v.samples[k] = s
+	}
+
+	return s
+}
+
+func key(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// Write writes the metrics in the Prometheus text exposition format with the
+// samples of each metric sorted by their label values.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	collectors := append([]func(){}, r.collectors...)
+	vecs := append([]*Vec{}, r.vecs...)
+	r.mu.Unlock()
+
+	for _, collect := range collectors {
+		collect()
+	}
+
+	out := bufio.NewWriter(w)
+	for _, v := range vecs {
+		v.write(out)
+	}
+
+	return out.Flush()
+}
+
+func (v *Vec) write(w *bufio.Writer) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", v.name, escape(v.help, false))
+	fmt.Fprintf(w, "# TYPE %s %s\n", v.name, v.kind)
+
+	var keys []string
+	for k := range v.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := v.samples[k]
+
+		w.WriteString(v.name)
+		if len(v.labels) > 0 {
+			var pairs []string
+			for i, label := range v.labels {
+				pairs = append(pairs, fmt.Sprintf(`%s="%s"`, label, escape(s.values[i], true)))
+			}
+			w.WriteString("{" + strings.Join(pairs, ",") + "}")
+		}
+
+		fmt.Fprintf(w, " %s\n", formatValue(s.value))
+	}
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escape escapes backslashes and newlines, and in label values double quotes.
+func escape(s string, quotes bool) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	if quotes {
+		s = strings.ReplaceAll(s, `"`, `\"`)
+	}
+
+	return s
+}
+
+// ServeHTTP serves the metrics for scraping.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := r.Write(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
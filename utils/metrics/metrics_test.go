@@ -0,0 +1,93 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/utils/metrics"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("writes the metrics in the text exposition format", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+
+		step := registry.NewGauge("gpupgrade_step", "Whether the step is running.", "step")
+		bytesSent := registry.NewCounter("gpupgrade_bytes_total", "The bytes sent\nby rsync.", "host")
+		up := registry.NewGauge("gpupgrade_up", "Whether the hub is up.")
+
+		step.Set(1, "execute")
+		step.Set(0, "initialize")
+		bytesSent.Add(1024, `sdw"1`)
+		bytesSent.Add(1024, `sdw"1`)
+		bytesSent.Add(-1, `sdw"1`)
+		registry.OnCollect(func() {
+			up.Set(1)
+		})
+
+		var buf bytes.Buffer
+		if err := registry.Write(&buf); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := `# HELP gpupgrade_step Whether the step is running.
+# TYPE gpupgrade_step gauge
+gpupgrade_step{step="execute"} 1
+gpupgrade_step{step="initialize"} 0
+# HELP gpupgrade_bytes_total The bytes sent\nby rsync.
+# TYPE gpupgrade_bytes_total counter
+gpupgrade_bytes_total{host="sdw\"1"} 2048
+# HELP gpupgrade_up Whether the hub is up.
+# TYPE gpupgrade_up gauge
+gpupgrade_up 1
+`
+		if buf.String() != expected {
+			t.Errorf("got\n%s\nwant\n%s", buf.String(), expected)
+		}
+
+		if value := bytesSent.Value(`sdw"1`); value != 2048 {
+			t.Errorf("got value %v want %v", value, 2048)
+		}
+
+		step.Reset()
+		if value := step.Value("execute"); value != 0 {
+			t.Errorf("got value %v after reset want 0", value)
+		}
+	})
+
+	t.Run("serves the metrics", func(t *testing.T) {
+		registry := metrics.NewRegistry()
+		registry.NewGauge("gpupgrade_up", "Whether the hub is up.").Set(1)
+
+		recorder := httptest.NewRecorder()
+		registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+		body, err := ioutil.ReadAll(recorder.Body)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !bytes.Contains(body, []byte("gpupgrade_up 1\n")) {
+			t.Errorf("got body %q want it to contain the gauge", body)
+		}
+
+		contentType := recorder.Header().Get("Content-Type")
+		if contentType != "text/plain; version=0.0.4; charset=utf-8" {
+			t.Errorf("got content type %q", contentType)
+		}
+	})
+
+	t.Run("panics when the label values do not match the labels", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected a panic")
+			}
+		}()
+
+		metrics.NewRegistry().NewGauge("gpupgrade_step", "", "step").Set(1)
+	})
+}
@@ -22,6 +22,10 @@ func (n NextActionErr) Error() string {
 	return n.Err.Error()
 }
 
+func (n NextActionErr) Unwrap() error {
+	return n.Err
+}
+
 func (n NextActionErr) Help() string {
 	return `
 NEXT ACTIONS
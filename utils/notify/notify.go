@@ -0,0 +1,346 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package notify sends notifications of upgrade events by posting them as
+// JSON to a webhook and by mailing them through sendmail or an SMTP relay.
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+)
+
+// The events notified.
+const (
+	StepStarted     = "step_started"
+	SubstepFailed   = "substep_failed"
+	StepFinished    = "step_finished"
+	PointOfNoReturn = "point_of_no_return"
+)
+
+// Config is where notifications are sent. Email is sent through the SMTP
+// relay at host:port, or with the local sendmail when SMTP is empty.
+type Config struct {
+	Webhook string
+	Email   []string
+	SMTP    string
+}
+
+func (c Config) Enabled() bool {
+	return c.Webhook != "" || len(c.Email) > 0
+}
+
+// ParseConfig checks the webhook URL, the comma separated email addresses,
+// and the SMTP relay.
+func ParseConfig(webhook string, email string, relay string) (Config, error) {
+	config := Config{Webhook: webhook, SMTP: relay}
+
+	if webhook != "" {
+		u, err := url.Parse(webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return Config{}, fmt.Errorf("Invalid notification webhook %q. Please specify an http or https URL.", webhook)
+		}
+	}
+
+	if email != "" {
+		addresses, err := mail.ParseAddressList(email)
+		if err != nil {
+			return Config{}, fmt.Errorf("Invalid notification email %q. Please specify comma separated email addresses: %v", email, err)
+		}
+
+		for _, address := range addresses {
+			config.Email = append(config.Email, address.Address)
+		}
+	}
+
+	if relay != "" {
+		if len(config.Email) == 0 {
+			return Config{}, errors.New("The notification SMTP relay requires a notification email.")
+		}
+
+		if _, _, err := net.SplitHostPort(relay); err != nil {
+			return Config{}, fmt.Errorf("Invalid notification SMTP relay %q. Please specify it as host:port.", relay)
+		}
+	}
+
+	return config, nil
+}
+
+// Event is the JSON posted to the webhook and the content of the email.
+type Event struct {
+	Event       string    `json:"event"`
+	UpgradeID   string    `json:"upgrade_id"`
+	Host        string    `json:"host"`
+	Step        string    `json:"step"`
+	Substep     string    `json:"substep,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Time        time.Time `json:"time"`
+	Duration    float64   `json:"duration_seconds,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	NextActions string    `json:"next_actions,omitempty"`
+}
+
+// Subject summarizes the event, such as "gpupgrade execute failed on mdw".
+func (e Event) Subject() string {
+	step := strings.ToLower(e.Step)
+
+	switch e.Event {
+	case StepStarted:
+		return fmt.Sprintf("gpupgrade %s started on %s", step, e.Host)
+	case SubstepFailed:
+		return fmt.Sprintf("gpupgrade %s failed at %s on %s", step, e.Substep, e.Host)
+	case PointOfNoReturn:
+		if e.Error != "" {
+			return fmt.Sprintf("gpupgrade %s stopped at the point of no return before %s on %s", step, e.Substep, e.Host)
+		}
+
+		return fmt.Sprintf("gpupgrade %s passed the point of no return before %s on %s", step, e.Substep, e.Host)
+	}
+
+	if e.Error != "" {
+		return fmt.Sprintf("gpupgrade %s failed on %s", step, e.Host)
+	}
+
+	return fmt.Sprintf("gpupgrade %s completed on %s", step, e.Host)
+}
+
+// Text is the email body.
+func (e Event) Text() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s\n\n", e.Subject())
+	fmt.Fprintf(&b, "Upgrade ID: %s\n", e.UpgradeID)
+	fmt.Fprintf(&b, "Host:       %s\n", e.Host)
+	fmt.Fprintf(&b, "Step:       %s\n", e.Step)
+	if e.Substep != "" {
+		fmt.Fprintf(&b, "Substep:    %s\n", e.Substep)
+	}
+	if e.Duration != 0 {
+		fmt.Fprintf(&b, "Duration:   %s\n", time.Duration(e.Duration*float64(time.Second)).Round(time.Second))
+	}
+	fmt.Fprintf(&b, "Time:       %s\n", e.Time.Format(time.RFC1123))
+	if e.Error != "" {
+		fmt.Fprintf(&b, "\nError: %s\n", e.Error)
+	}
+	if e.NextActions != "" {
+		fmt.Fprintf(&b, "\nNext actions:\n%s\n", e.NextActions)
+	}
+
+	return b.String()
+}
+
+var sendmailCommand = exec.Command
+
+// QueueSize is how many events wait to be sent before further events are
+// dropped, such as when the webhook is unreachable.
+var QueueSize = 100
+
+// SMTPTimeout is how long sending an email through the SMTP relay may take.
+var SMTPTimeout = 10 * time.Second
+
+// Notifier sends events in the background so that the upgrade does not wait
+// on the webhook or mail relay.
+type Notifier struct {
+	config Config
+	client *http.Client
+	from   string
+
+	mu     sync.Mutex
+	closed bool
+	events chan Event
+	done   chan struct{}
+}
+
+func New(config Config) *Notifier {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "localhost"
+	}
+
+	n := &Notifier{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+		from:   "gpupgrade@" + host,
+		events: make(chan Event, QueueSize),
+		done:   make(chan struct{}),
+	}
+
+	go n.run()
+	return n
+}
+
+// Notify queues the event to be sent. Events notified after Close are
+// dropped.
+func (n *Notifier) Notify(event Event) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.closed {
+		gplog.Debug("dropping notification %q since the notifier is closed", event.Subject())
+		return
+	}
+
+	select {
+	case n.events <- event:
+	default:
+		gplog.Error("dropping notification %q since %d notifications are waiting to be sent", event.Subject(), QueueSize)
+	}
+}
+
+// Close sends the queued events and stops the notifier.
+func (n *Notifier) Close() {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.events)
+	}
+	n.mu.Unlock()
+
+	<-n.done
+}
+
+func (n *Notifier) run() {
+	defer close(n.done)
+
+	for event := range n.events {
+		if err := n.Send(event); err != nil {
+			gplog.Error("sending notification %q: %v", event.Subject(), err)
+		}
+	}
+}
+
+// Send posts the event to the webhook and mails it.
+func (n *Notifier) Send(event Event) error {
+	var err error
+
+	if n.config.Webhook != "" {
+		if wErr := n.post(event); wErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("posting to webhook: %w", wErr))
+		}
+	}
+
+	if len(n.config.Email) > 0 {
+		if mErr := n.mail(event); mErr != nil {
+			err = errorlist.Append(err, xerrors.Errorf("sending email: %w", mErr))
+		}
+	}
+
+	return err
+}
+
+func (n *Notifier) post(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.config.Webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s", resp.Status)
+	}
+
+	return nil
+}
+
+func (n *Notifier) mail(event Event) error {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\n", strings.Join(n.config.Email, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\n", event.Subject())
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\n\n")
+	msg.WriteString(event.Text())
+
+	if n.config.SMTP != "" {
+		return n.sendSMTP(msg.Bytes())
+	}
+
+	var stderr bytes.Buffer
+	cmd := sendmailCommand("sendmail", "-t", "-i")
+	cmd.Stdin = &msg
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return xerrors.Errorf("sendmail: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// sendSMTP mails the message through the SMTP relay. Unlike smtp.SendMail it
+// gives up after SMTPTimeout so that a hung relay does not block Close.
+func (n *Notifier) sendSMTP(msg []byte) error {
+	conn, err := net.DialTimeout("tcp", n.config.SMTP, SMTPTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(SMTPTimeout)); err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(n.config.SMTP)
+	if err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(n.from); err != nil {
+		return err
+	}
+
+	for _, to := range n.config.Email {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package notify_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/notify"
+)
+
+// Sendmail exits successfully when it is given a message with a subject.
+func Sendmail() {
+	msg, err := ioutil.ReadAll(os.Stdin)
+	if err != nil || !strings.Contains(string(msg), "\nSubject: gpupgrade execute failed on mdw\n") {
+		os.Exit(1)
+	}
+}
+
+func SendmailFailure() {
+	fmt.Fprint(os.Stderr, "sendmail: no mail transfer agent")
+	os.Exit(1)
+}
+
+func init() {
+	exectest.RegisterMains(
+		Sendmail,
+		SendmailFailure,
+	)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(exectest.Run(m))
+}
+
+var failed = notify.Event{
+	Event:       notify.StepFinished,
+	UpgradeID:   "ABCDEFGHIJK",
+	Host:        "mdw",
+	Step:        "EXECUTE",
+	Status:      "FAILED",
+	Time:        time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC),
+	Duration:    5400,
+	Error:       `substep "UPGRADE_PRIMARIES": exit status 1`,
+	NextActions: "Please address the above issue and run \"gpupgrade execute\" again.",
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Run("parses the notification parameters", func(t *testing.T) {
+		config, err := notify.ParseConfig("https://hooks.example.com/gpupgrade", "dba@example.com, DBA Team <team@example.com>", "localhost:25")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		expected := notify.Config{
+			Webhook: "https://hooks.example.com/gpupgrade",
+			Email:   []string{"dba@example.com", "team@example.com"},
+			SMTP:    "localhost:25",
+		}
+		if !reflect.DeepEqual(config, expected) {
+			t.Errorf("got %+v want %+v", config, expected)
+		}
+
+		if !config.Enabled() {
+			t.Errorf("expected notifications to be enabled")
+		}
+	})
+
+	t.Run("notifications are disabled without a webhook or email", func(t *testing.T) {
+		config, err := notify.ParseConfig("", "", "")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if config.Enabled() {
+			t.Errorf("expected notifications to be disabled")
+		}
+	})
+
+	t.Run("errors on invalid parameters", func(t *testing.T) {
+		cases := []struct {
+			name                  string
+			webhook, email, relay string
+			expected              string
+		}{
+			{name: "webhook is not a URL", webhook: "hooks.example.com", expected: `Invalid notification webhook "hooks.example.com"`},
+			{name: "email is not an address", email: "dba", expected: `Invalid notification email "dba"`},
+			{name: "relay without email", relay: "localhost:25", expected: "The notification SMTP relay requires a notification email."},
+			{name: "relay without port", email: "dba@example.com", relay: "localhost", expected: `Invalid notification SMTP relay "localhost"`},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				_, err := notify.ParseConfig(c.webhook, c.email, c.relay)
+				if err == nil || !strings.HasPrefix(err.Error(), c.expected) {
+					t.Errorf("got error %v want %q", err, c.expected)
+				}
+			})
+		}
+	})
+}
+
+func TestEvent(t *testing.T) {
+	t.Run("summarizes the events", func(t *testing.T) {
+		cases := []struct {
+			event    notify.Event
+			expected string
+		}{
+			{notify.Event{Event: notify.StepStarted, Step: "EXECUTE", Host: "mdw"}, "gpupgrade execute started on mdw"},
+			{notify.Event{Event: notify.SubstepFailed, Step: "EXECUTE", Substep: "UPGRADE_PRIMARIES", Host: "mdw"}, "gpupgrade execute failed at UPGRADE_PRIMARIES on mdw"},
+			{notify.Event{Event: notify.PointOfNoReturn, Step: "FINALIZE", Substep: "UPGRADE_MIRRORS", Host: "mdw"}, "gpupgrade finalize passed the point of no return before UPGRADE_MIRRORS on mdw"},
+			{notify.Event{Event: notify.PointOfNoReturn, Step: "FINALIZE", Substep: "UPGRADE_MIRRORS", Host: "mdw", Error: "oops"}, "gpupgrade finalize stopped at the point of no return before UPGRADE_MIRRORS on mdw"},
+			{notify.Event{Event: notify.StepFinished, Step: "FINALIZE", Host: "mdw"}, "gpupgrade finalize completed on mdw"},
+			{failed, "gpupgrade execute failed on mdw"},
+		}
+
+		for _, c := range cases {
+			if subject := c.event.Subject(); subject != c.expected {
+				t.Errorf("got subject %q want %q", subject, c.expected)
+			}
+		}
+	})
+
+	t.Run("describes the event in the email", func(t *testing.T) {
+		expected := `gpupgrade execute failed on mdw
+
+Upgrade ID: ABCDEFGHIJK
+Host:       mdw
+Step:       EXECUTE
+Duration:   1h30m0s
+Time:       Sat, 01 Jan 2022 12:00:00 UTC
+
+Error: substep "UPGRADE_PRIMARIES": exit status 1
+
+Next actions:
+Please address the above issue and run "gpupgrade execute" again.
+`
+		if text := failed.Text(); text != expected {
+			t.Errorf("got\n%s\nwant\n%s", text, expected)
+		}
+	})
+}
+
+func TestNotifier(t *testing.T) {
+	testlog.SetupLogger()
+
+	t.Run("posts the events to the webhook", func(t *testing.T) {
+		events := make(chan notify.Event, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("got content type %q", r.Header.Get("Content-Type"))
+			}
+
+			var event notify.Event
+			if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+				t.Errorf("unexpected error %+v", err)
+			}
+			events <- event
+		}))
+		defer server.Close()
+
+		notifier := notify.New(notify.Config{Webhook: server.URL})
+		notifier.Notify(failed)
+		notifier.Close()
+
+		select {
+		case event := <-events:
+			if !reflect.DeepEqual(event, failed) {
+				t.Errorf("got event %+v want %+v", event, failed)
+			}
+		default:
+			t.Errorf("expected the event to be posted")
+		}
+	})
+
+	t.Run("errors when the webhook rejects the event", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		notifier := notify.New(notify.Config{Webhook: server.URL})
+		defer notifier.Close()
+
+		err := notifier.Send(failed)
+		if err == nil || !strings.Contains(err.Error(), "posting to webhook: 404 Not Found") {
+			t.Errorf("got error %v want the webhook status", err)
+		}
+	})
+
+	t.Run("mails the events with sendmail", func(t *testing.T) {
+		notify.SetSendmailCommand(exectest.NewCommandWithVerifier(Sendmail, func(name string, args ...string) {
+			if name != "sendmail" {
+				t.Errorf("got command %q want sendmail", name)
+			}
+
+			expected := []string{"-t", "-i"}
+			if !reflect.DeepEqual(args, expected) {
+				t.Errorf("got args %q want %q", args, expected)
+			}
+		}))
+		defer notify.ResetSendmailCommand()
+
+		notifier := notify.New(notify.Config{Email: []string{"dba@example.com"}})
+		defer notifier.Close()
+
+		if err := notifier.Send(failed); err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+	})
+
+	t.Run("errors when sendmail fails", func(t *testing.T) {
+		notify.SetSendmailCommand(exectest.NewCommand(SendmailFailure))
+		defer notify.ResetSendmailCommand()
+
+		notifier := notify.New(notify.Config{Email: []string{"dba@example.com"}})
+		defer notifier.Close()
+
+		err := notifier.Send(failed)
+		if err == nil || !strings.Contains(err.Error(), "no mail transfer agent") {
+			t.Errorf("got error %v want the sendmail error", err)
+		}
+	})
+
+	t.Run("drops events notified after closing", func(t *testing.T) {
+		posted := make(chan struct{}, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			posted <- struct{}{}
+		}))
+		defer server.Close()
+
+		notifier := notify.New(notify.Config{Webhook: server.URL})
+		notifier.Close()
+		notifier.Notify(failed)
+		notifier.Close()
+
+		select {
+		case <-posted:
+			t.Errorf("expected the event to be dropped")
+		default:
+		}
+	})
+
+	t.Run("times out when the SMTP relay does not respond", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+		defer lis.Close()
+
+		go func() {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			// Never greet the client.
+			_, _ = ioutil.ReadAll(conn)
+		}()
+
+		timeout := notify.SMTPTimeout
+		notify.SMTPTimeout = 10 * time.Millisecond
+		defer func() { notify.SMTPTimeout = timeout }()
+
+		notifier := notify.New(notify.Config{Email: []string{"dba@example.com"}, SMTP: lis.Addr().String()})
+		defer notifier.Close()
+
+		var netErr net.Error
+		err = notifier.Send(failed)
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			t.Errorf("got error %#v want a timeout", err)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"context"
+	"os/exec"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/snapshot"
+)
+
+func SetTargetVersion(versionFunc func(string) (string, error)) {
+	targetVersion = versionFunc
+}
+
+func ResetTargetVersion() {
+	targetVersion = greenplum.Version
+}
+
+func SetGpupgradeVersion(versionFunc func() (string, error)) {
+	gpupgradeVersion = versionFunc
+}
+
+func ResetGpupgradeVersion() {
+	gpupgradeVersion = upgrade.LocalVersion
+}
+
+func SetLookPath(lookPathFunc func(string) (string, error)) {
+	lookPath = lookPathFunc
+}
+
+func ResetLookPath() {
+	lookPath = exec.LookPath
+}
+
+func SetLocaleCommand(command exectest.Command) {
+	localeCommand = command
+}
+
+func ResetLocaleCommand() {
+	localeCommand = exec.Command
+}
+
+func SetGetrlimit(getrlimitFunc func(int, *unix.Rlimit) error) {
+	getrlimit = getrlimitFunc
+}
+
+func ResetGetrlimit() {
+	getrlimit = unix.Getrlimit
+}
+
+func SetCheckSnapshot(checkFunc func(context.Context, string, []string) error) {
+	checkSnapshot = checkFunc
+}
+
+func ResetCheckSnapshot() {
+	checkSnapshot = snapshot.Check
+}
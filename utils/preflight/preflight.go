@@ -0,0 +1,228 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/sys/unix"
+
+	"github.com/greenplum-db/gpupgrade/greenplum"
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/upgrade"
+	"github.com/greenplum-db/gpupgrade/utils/snapshot"
+)
+
+const (
+	TargetGPHome     = "target GPHOME"
+	GpupgradeVersion = "gpupgrade version"
+	Ports            = "temp ports"
+	Tools            = "required tools"
+	Locales          = "locales"
+	OpenFiles        = "open files limit"
+	Processes        = "max user processes limit"
+	DataDirParents   = "data directory parents"
+	Snapshots        = "snapshot provider"
+)
+
+var targetVersion = greenplum.Version
+var gpupgradeVersion = upgrade.LocalVersion
+var lookPath = exec.LookPath
+var localeCommand = exec.Command
+var getrlimit = unix.Getrlimit
+var checkSnapshot = snapshot.Check
+
+// Run runs every check in the request on the local host and returns the
+// result of each. Checks that are unable to run are reported as failed.
+// Resource limits below their recommended minimums are reported as passed
+// with a warning, since Greenplum can run with lower limits.
+func Run(ctx context.Context, req *idl.PreflightRequest) []*idl.PreflightReply_Check {
+	gplog.Debug("running preflight checks %v", req)
+
+	checks := []*idl.PreflightReply_Check{
+		checkTargetGPHome(req.GetTargetGPHome(), req.GetTargetVersion()),
+		checkGpupgradeVersion(req.GetGpupgradeVersion()),
+		checkPorts(req.GetPorts()),
+		checkTools(req.GetTools()),
+		checkLocales(req.GetLocales()),
+		checkLimit(OpenFiles, unix.RLIMIT_NOFILE, req.GetMinOpenFiles()),
+		checkLimit(Processes, unix.RLIMIT_NPROC, req.GetMinProcesses()),
+		checkDataDirParents(req.GetDataDirParents()),
+	}
+
+	if req.GetSnapshotProvider() != "" {
+		checks = append(checks, checkSnapshots(ctx, req.GetSnapshotProvider(), req.GetSnapshotDirs()))
+	}
+
+	return checks
+}
+
+func passed(name string, format string, args ...interface{}) *idl.PreflightReply_Check {
+	return &idl.PreflightReply_Check{Name: name, Passed: true, Message: fmt.Sprintf(format, args...)}
+}
+
+func warned(name string, format string, args ...interface{}) *idl.PreflightReply_Check {
+	return &idl.PreflightReply_Check{Name: name, Passed: true, Warning: true, Message: fmt.Sprintf(format, args...)}
+}
+
+func failed(name string, format string, args ...interface{}) *idl.PreflightReply_Check {
+	return &idl.PreflightReply_Check{Name: name, Passed: false, Message: fmt.Sprintf(format, args...)}
+}
+
+func checkTargetGPHome(gphome string, expected string) *idl.PreflightReply_Check {
+	info, err := os.Stat(gphome)
+	if err != nil {
+		return failed(TargetGPHome, "%v", err)
+	}
+
+	if !info.IsDir() {
+		return failed(TargetGPHome, "%s is not a directory", gphome)
+	}
+
+	version, err := targetVersion(gphome)
+	if err != nil {
+		return failed(TargetGPHome, "%v", err)
+	}
+
+	if version != expected {
+		return failed(TargetGPHome, "%s has version %s but the coordinator has %s", gphome, version, expected)
+	}
+
+	return passed(TargetGPHome, "%s has version %s", gphome, version)
+}
+
+func checkGpupgradeVersion(expected string) *idl.PreflightReply_Check {
+	version, err := gpupgradeVersion()
+	if err != nil {
+		return failed(GpupgradeVersion, "%v", err)
+	}
+
+	version = strings.TrimSpace(version)
+	if version != strings.TrimSpace(expected) {
+		return failed(GpupgradeVersion, "found %q but the hub has %q", version, strings.TrimSpace(expected))
+	}
+
+	return passed(GpupgradeVersion, "%s", version)
+}
+
+func checkPorts(ports []uint32) *idl.PreflightReply_Check {
+	var inUse []string
+	for _, port := range ports {
+		listener, err := net.Listen("tcp", ":"+strconv.Itoa(int(port)))
+		if err != nil {
+			inUse = append(inUse, strconv.Itoa(int(port)))
+			continue
+		}
+
+		if err := listener.Close(); err != nil {
+			gplog.Debug("closing listener on port %d: %v", port, err)
+		}
+	}
+
+	if len(inUse) > 0 {
+		return failed(Ports, "ports in use: %s", strings.Join(inUse, ", "))
+	}
+
+	return passed(Ports, "%d ports available", len(ports))
+}
+
+func checkTools(tools []string) *idl.PreflightReply_Check {
+	var missing []string
+	for _, tool := range tools {
+		if _, err := lookPath(tool); err != nil {
+			missing = append(missing, tool)
+		}
+	}
+
+	if len(missing) > 0 {
+		return failed(Tools, "not found in PATH: %s", strings.Join(missing, ", "))
+	}
+
+	return passed(Tools, "%d tools found", len(tools))
+}
+
+func checkLocales(locales []string) *idl.PreflightReply_Check {
+	cmd := localeCommand("locale", "-a")
+	output, err := cmd.Output()
+	if err != nil {
+		return failed(Locales, "%q failed: %v", cmd.String(), err)
+	}
+
+	available := make(map[string]bool)
+	for _, locale := range strings.Fields(string(output)) {
+		available[normalizeLocale(locale)] = true
+	}
+
+	var missing []string
+	for _, locale := range locales {
+		if !available[normalizeLocale(locale)] {
+			missing = append(missing, locale)
+		}
+	}
+
+	if len(missing) > 0 {
+		return failed(Locales, "not installed: %s", strings.Join(missing, ", "))
+	}
+
+	return passed(Locales, "%d locales available", len(locales))
+}
+
+// normalizeLocale lowercases the codeset and strips its punctuation so that,
+// for example, en_US.UTF-8 matches the en_US.utf8 listed by "locale -a".
+func normalizeLocale(locale string) string {
+	parts := strings.SplitN(locale, ".", 2)
+	if len(parts) != 2 {
+		return locale
+	}
+
+	codeset := strings.ToLower(strings.NewReplacer("-", "", "_", "").Replace(parts[1]))
+	return parts[0] + "." + codeset
+}
+
+func checkLimit(name string, resource int, recommended uint64) *idl.PreflightReply_Check {
+	var limit unix.Rlimit
+	if err := getrlimit(resource, &limit); err != nil {
+		return failed(name, "%v", err)
+	}
+
+	if limit.Cur == unix.RLIM_INFINITY {
+		return passed(name, "unlimited")
+	}
+
+	if limit.Cur < recommended {
+		return warned(name, "%d is less than the recommended %d", limit.Cur, recommended)
+	}
+
+	return passed(name, "%d", limit.Cur)
+}
+
+func checkDataDirParents(dirs []string) *idl.PreflightReply_Check {
+	var unwritable []string
+	for _, dir := range dirs {
+		if err := unix.Access(dir, unix.W_OK); err != nil {
+			unwritable = append(unwritable, fmt.Sprintf("%s (%v)", dir, err))
+		}
+	}
+
+	if len(unwritable) > 0 {
+		return failed(DataDirParents, "not writable: %s", strings.Join(unwritable, ", "))
+	}
+
+	return passed(DataDirParents, "%d directories writable", len(dirs))
+}
+
+func checkSnapshots(ctx context.Context, provider string, dirs []string) *idl.PreflightReply_Check {
+	if err := checkSnapshot(ctx, provider, dirs); err != nil {
+		return failed(Snapshots, "%v", err)
+	}
+
+	return passed(Snapshots, "%s can snapshot %d directories", provider, len(dirs))
+}
@@ -0,0 +1,325 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package preflight_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/greenplum-db/gpupgrade/idl"
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/preflight"
+)
+
+func Locales() {
+	fmt.Println("C")
+	fmt.Println("POSIX")
+	fmt.Println("en_US.utf8")
+}
+
+func LocaleFailure() {
+	os.Exit(1)
+}
+
+func init() {
+	exectest.RegisterMains(
+		Locales,
+		LocaleFailure,
+	)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(exectest.Run(m))
+}
+
+func TestRun(t *testing.T) {
+	testlog.SetupLogger()
+
+	gphome := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, gphome)
+
+	dataDirParent := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dataDirParent)
+
+	preflight.SetTargetVersion(func(string) (string, error) {
+		return "6.20.0", nil
+	})
+	defer preflight.ResetTargetVersion()
+
+	preflight.SetGpupgradeVersion(func() (string, error) {
+		return "Version: 1.0.0\nCommit: abc\n", nil
+	})
+	defer preflight.ResetGpupgradeVersion()
+
+	preflight.SetLookPath(func(file string) (string, error) {
+		return "/usr/bin/" + file, nil
+	})
+	defer preflight.ResetLookPath()
+
+	preflight.SetLocaleCommand(exectest.NewCommand(Locales))
+	defer preflight.ResetLocaleCommand()
+
+	preflight.SetGetrlimit(func(resource int, limit *unix.Rlimit) error {
+		limit.Cur = 200000
+		return nil
+	})
+	defer preflight.ResetGetrlimit()
+
+	request := func() *idl.PreflightRequest {
+		return &idl.PreflightRequest{
+			TargetGPHome:     gphome,
+			TargetVersion:    "6.20.0",
+			GpupgradeVersion: "Version: 1.0.0\nCommit: abc\n",
+			Tools:            []string{"rsync", "ssh"},
+			Locales:          []string{"en_US.UTF-8"},
+			DataDirParents:   []string{dataDirParent},
+			MinOpenFiles:     65536,
+			MinProcesses:     131072,
+		}
+	}
+
+	t.Run("passes when every check passes", func(t *testing.T) {
+		checks := preflight.Run(context.Background(), request())
+
+		expected := []string{
+			preflight.TargetGPHome,
+			preflight.GpupgradeVersion,
+			preflight.Ports,
+			preflight.Tools,
+			preflight.Locales,
+			preflight.OpenFiles,
+			preflight.Processes,
+			preflight.DataDirParents,
+		}
+
+		if len(checks) != len(expected) {
+			t.Fatalf("got %d checks want %d", len(checks), len(expected))
+		}
+
+		for i, check := range checks {
+			if check.GetName() != expected[i] {
+				t.Errorf("got check %q want %q", check.GetName(), expected[i])
+			}
+
+			if !check.GetPassed() {
+				t.Errorf("check %q failed: %s", check.GetName(), check.GetMessage())
+			}
+		}
+	})
+
+	cases := []struct {
+		name    string
+		check   string
+		setup   func(t *testing.T, req *idl.PreflightRequest) func()
+		message string
+	}{
+		{
+			name:  "fails when the target GPHOME does not exist",
+			check: preflight.TargetGPHome,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				req.TargetGPHome = filepath.Join(gphome, "does", "not", "exist")
+				return func() {}
+			},
+			message: "no such file or directory",
+		},
+		{
+			name:  "fails when the target GPHOME version does not match",
+			check: preflight.TargetGPHome,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				req.TargetVersion = "6.21.0"
+				return func() {}
+			},
+			message: "has version 6.20.0 but the coordinator has 6.21.0",
+		},
+		{
+			name:  "fails when the gpupgrade version does not match",
+			check: preflight.GpupgradeVersion,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				req.GpupgradeVersion = "Version: 2.0.0"
+				return func() {}
+			},
+			message: `but the hub has "Version: 2.0.0"`,
+		},
+		{
+			name:  "fails when a port is in use",
+			check: preflight.Ports,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				listener, err := net.Listen("tcp", ":0")
+				if err != nil {
+					t.Fatalf("listen: %v", err)
+				}
+
+				req.Ports = []uint32{uint32(listener.Addr().(*net.TCPAddr).Port)}
+				return func() {
+					listener.Close()
+				}
+			},
+			message: "ports in use",
+		},
+		{
+			name:  "fails when a tool is missing",
+			check: preflight.Tools,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				preflight.SetLookPath(func(file string) (string, error) {
+					if file == "rsync" {
+						return "", errors.New("not found")
+					}
+					return "/usr/bin/" + file, nil
+				})
+				return func() {
+					preflight.SetLookPath(func(file string) (string, error) {
+						return "/usr/bin/" + file, nil
+					})
+				}
+			},
+			message: "not found in PATH: rsync",
+		},
+		{
+			name:  "fails when a locale is not installed",
+			check: preflight.Locales,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				req.Locales = []string{"en_US.UTF-8", "de_DE.UTF-8"}
+				return func() {}
+			},
+			message: "not installed: de_DE.UTF-8",
+		},
+		{
+			name:  "fails when the locales cannot be listed",
+			check: preflight.Locales,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				preflight.SetLocaleCommand(exectest.NewCommand(LocaleFailure))
+				return func() {
+					preflight.SetLocaleCommand(exectest.NewCommand(Locales))
+				}
+			},
+			message: "exit status 1",
+		},
+		{
+			name:  "fails when a data directory parent does not exist",
+			check: preflight.DataDirParents,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				req.DataDirParents = append(req.DataDirParents, filepath.Join(dataDirParent, "missing"))
+				return func() {}
+			},
+			message: "not writable: " + filepath.Join(dataDirParent, "missing"),
+		},
+		{
+			name:  "fails when the snapshot provider cannot snapshot the directories",
+			check: preflight.Snapshots,
+			setup: func(t *testing.T, req *idl.PreflightRequest) func() {
+				req.SnapshotProvider = "lvm"
+				req.SnapshotDirs = []string{"/data/primary/gpseg0"}
+				preflight.SetCheckSnapshot(func(context.Context, string, []string) error {
+					return errors.New("sudo does not allow running lvcreate without a password")
+				})
+				return preflight.ResetCheckSnapshot
+			},
+			message: "sudo does not allow running lvcreate without a password",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := request()
+			cleanup := c.setup(t, req)
+			defer cleanup()
+
+			checks := preflight.Run(context.Background(), req)
+
+			for _, check := range checks {
+				if check.GetName() != c.check {
+					if !check.GetPassed() {
+						t.Errorf("check %q failed: %s", check.GetName(), check.GetMessage())
+					}
+					continue
+				}
+
+				if check.GetPassed() {
+					t.Errorf("expected check %q to fail", check.GetName())
+				}
+
+				if !strings.Contains(check.GetMessage(), c.message) {
+					t.Errorf("got message %q want it to contain %q", check.GetMessage(), c.message)
+				}
+			}
+		})
+	}
+
+	t.Run("checks the snapshot provider when requested", func(t *testing.T) {
+		var provider string
+		var dirs []string
+		preflight.SetCheckSnapshot(func(_ context.Context, p string, d []string) error {
+			provider, dirs = p, d
+			return nil
+		})
+		defer preflight.ResetCheckSnapshot()
+
+		req := request()
+		req.SnapshotProvider = "zfs"
+		req.SnapshotDirs = []string{"/gpdata/primary/gpseg0"}
+
+		checks := preflight.Run(context.Background(), req)
+		last := checks[len(checks)-1]
+		if last.GetName() != preflight.Snapshots || !last.GetPassed() {
+			t.Errorf("got check %+v want the snapshot check to pass", last)
+		}
+
+		if provider != "zfs" || !reflect.DeepEqual(dirs, req.SnapshotDirs) {
+			t.Errorf("checked provider %q dirs %q want %q %q", provider, dirs, "zfs", req.SnapshotDirs)
+		}
+	})
+
+	t.Run("warns when the limits are below the recommended minimums", func(t *testing.T) {
+		req := request()
+		req.MinOpenFiles = 300000
+		req.MinProcesses = 300000
+
+		warnings := make(map[string]string)
+		for _, check := range preflight.Run(context.Background(), req) {
+			if !check.GetPassed() {
+				t.Errorf("check %q failed: %s", check.GetName(), check.GetMessage())
+			}
+
+			if check.GetWarning() {
+				warnings[check.GetName()] = check.GetMessage()
+			}
+		}
+
+		expected := map[string]string{
+			preflight.OpenFiles: "200000 is less than the recommended 300000",
+			preflight.Processes: "200000 is less than the recommended 300000",
+		}
+		if !reflect.DeepEqual(warnings, expected) {
+			t.Errorf("got warnings %q want %q", warnings, expected)
+		}
+	})
+
+	t.Run("passes when the limit is unlimited", func(t *testing.T) {
+		preflight.SetGetrlimit(func(resource int, limit *unix.Rlimit) error {
+			limit.Cur = unix.RLIM_INFINITY
+			return nil
+		})
+		defer preflight.SetGetrlimit(func(resource int, limit *unix.Rlimit) error {
+			limit.Cur = 200000
+			return nil
+		})
+
+		for _, check := range preflight.Run(context.Background(), request()) {
+			if check.GetName() == preflight.OpenFiles && check.GetMessage() != "unlimited" {
+				t.Errorf("got message %q want %q", check.GetMessage(), "unlimited")
+			}
+		}
+	})
+}
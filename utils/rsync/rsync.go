@@ -4,6 +4,7 @@
 package rsync
 
 import (
+	"context"
 	"os/exec"
 	"runtime"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/greenplum-db/gpupgrade/step"
 	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
 )
 
 var rsyncCommand = exec.Command
@@ -72,7 +74,7 @@ func Rsync(options ...Option) error {
 
 	gplog.Info(cmd.String())
 
-	err := cmd.Run()
+	err := trace.Run(opts.context(), "rsync", cmd)
 	if err != nil {
 		errorText := err.Error()
 
@@ -158,6 +160,14 @@ func WithStream(stream step.OutStreams) Option {
 	}
 }
 
+// WithContext runs rsync with the context, such as that of an agent call. By
+// default the context is that of the substep given the stream, if any.
+func WithContext(ctx context.Context) Option {
+	return func(options *optionList) {
+		options.ctx = ctx
+	}
+}
+
 type optionList struct {
 	sources            []string
 	hasSourceHost      bool
@@ -169,6 +179,18 @@ type optionList struct {
 	excludedFiles      []string
 	useStream          bool
 	stream             step.OutStreams
+	ctx                context.Context
+}
+
+func (o *optionList) context() context.Context {
+	switch {
+	case o.ctx != nil:
+		return o.ctx
+	case o.useStream:
+		return step.Context(o.stream)
+	default:
+		return context.Background()
+	}
 }
 
 func newOptionList(opts ...Option) *optionList {
@@ -0,0 +1,79 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package rsync
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Stats are the totals printed by rsync --stats.
+type Stats struct {
+	// TotalBytes is the size of all files in the source.
+	TotalBytes uint64
+
+	// LiteralBytes is the file data sent in full since it did not match the
+	// existing files at the destination.
+	LiteralBytes uint64
+}
+
+// AvoidedBytes returns the size of the source files that were not sent since
+// they matched the destination.
+func (s Stats) AvoidedBytes() uint64 {
+	if s.LiteralBytes > s.TotalBytes {
+		return 0
+	}
+
+	return s.TotalBytes - s.LiteralBytes
+}
+
+// ParseStats parses the output of rsync --stats. Newer versions of rsync
+// separate thousands with commas.
+func ParseStats(output string) (Stats, error) {
+	var stats Stats
+	fields := map[string]*uint64{
+		"Total file size": &stats.TotalBytes,
+		"Literal data":    &stats.LiteralBytes,
+	}
+
+	found := 0
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		field, ok := fields[strings.TrimSpace(parts[0])]
+		if !ok {
+			continue
+		}
+
+		value := strings.Fields(parts[1])
+		if len(value) == 0 {
+			return Stats{}, xerrors.Errorf("missing value for rsync statistic %q", parts[0])
+		}
+
+		n, err := strconv.ParseUint(strings.ReplaceAll(value[0], ",", ""), 10, 64)
+		if err != nil {
+			return Stats{}, xerrors.Errorf("parsing rsync statistic %q: %w", parts[0], err)
+		}
+
+		*field = n
+		found++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Stats{}, err
+	}
+
+	if found != len(fields) {
+		return Stats{}, xerrors.Errorf("rsync statistics not found in output %q", output)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package rsync_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/utils/rsync"
+)
+
+func TestParseStats(t *testing.T) {
+	t.Run("parses the totals", func(t *testing.T) {
+		output := `
+Number of files: 2,011 (reg: 1,985, dir: 26)
+Number of created files: 0
+Number of regular files transferred: 12
+Total file size: 1,073,741,824 bytes
+Total transferred file size: 5,242,880 bytes
+Literal data: 1,048,576 bytes
+Matched data: 4,194,304 bytes
+File list size: 0
+Total bytes sent: 1,105,012
+Total bytes received: 3,114
+
+sent 1,105,012 bytes  received 3,114 bytes  443,250.40 bytes/sec
+total size is 1,073,741,824  speedup is 968.98
+`
+		stats, err := rsync.ParseStats(output)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := rsync.Stats{TotalBytes: 1073741824, LiteralBytes: 1048576}
+		if stats != expected {
+			t.Errorf("got %+v want %+v", stats, expected)
+		}
+
+		if stats.AvoidedBytes() != 1072693248 {
+			t.Errorf("got %d avoided bytes want %d", stats.AvoidedBytes(), 1072693248)
+		}
+	})
+
+	t.Run("parses output without separators", func(t *testing.T) {
+		stats, err := rsync.ParseStats("Total file size: 2048 bytes\nLiteral data: 0 bytes\n")
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := rsync.Stats{TotalBytes: 2048}
+		if stats != expected {
+			t.Errorf("got %+v want %+v", stats, expected)
+		}
+	})
+
+	t.Run("errors when the statistics are missing", func(t *testing.T) {
+		_, err := rsync.ParseStats("sending incremental file list\n")
+		expected := "rsync statistics not found"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"os/exec"
+
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+)
+
+func SetCommand(cmd exectest.Command) {
+	command = cmd
+}
+
+func ResetCommand() {
+	command = exec.Command
+}
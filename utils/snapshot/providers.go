@@ -0,0 +1,324 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/rsync"
+)
+
+// The LVM, ZFS, and btrfs providers snapshot the whole filesystem containing
+// a directory, which may be shared by several segments. Rather than rolling
+// back the whole filesystem, each directory is restored by rsyncing it from
+// its read-only snapshot. Only the files modified since the snapshot are
+// copied, and the copy is local.
+var restoreOptions = []string{"--archive", "--delete"}
+
+// mount returns the filesystem containing dir.
+func mount(ctx context.Context, dir string) (Volume, error) {
+	output, err := run(ctx, "findmnt", "--noheadings", "--output", "SOURCE,TARGET,FSTYPE", "--target", dir)
+	if err != nil {
+		return Volume{}, err
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) != 3 {
+		return Volume{}, xerrors.Errorf("unexpected findmnt output %q", output)
+	}
+
+	return Volume{Device: fields[0], Mountpoint: fields[1], FSType: fields[2]}, nil
+}
+
+// restoreFrom rsyncs dir from its location within the snapshot of the volume
+// mounted at snapshotRoot.
+func restoreFrom(ctx context.Context, snapshotRoot string, volume Volume, dir string) error {
+	rel, err := filepath.Rel(volume.Mountpoint, dir)
+	if err != nil {
+		return err
+	}
+
+	return rsync.Rsync(
+		rsync.WithContext(ctx),
+		rsync.WithSources(filepath.Join(snapshotRoot, rel)+string(os.PathSeparator)),
+		rsync.WithDestination(dir),
+		rsync.WithOptions(restoreOptions...),
+	)
+}
+
+// zfs snapshots the dataset containing the directory. Snapshots are
+// accessible read-only under the .zfs directory of the dataset mountpoint.
+// The zfs commands run as the current user when "zfs allow" delegates the
+// snapshot, destroy, and mount permissions on the dataset to them, and
+// otherwise through sudo.
+type zfs struct{}
+
+// zfsPermissions are the permissions needed to create and destroy snapshots.
+var zfsPermissions = []string{"snapshot", "destroy", "mount"}
+
+// zfsDelegated returns whether "zfs allow" delegates zfsPermissions on the
+// dataset to the current user, either by name or to everyone.
+func zfsDelegated(ctx context.Context, dataset string) bool {
+	current, err := user.Current()
+	if err != nil {
+		return false
+	}
+
+	output, err := run(ctx, "zfs", "allow", dataset)
+	if err != nil {
+		return false
+	}
+
+	granted := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		var permissions string
+
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 3 && fields[0] == "user" && fields[1] == current.Username:
+			permissions = fields[2]
+		case len(fields) == 2 && fields[0] == "everyone":
+			permissions = fields[1]
+		default:
+			continue
+		}
+
+		for _, permission := range strings.Split(permissions, ",") {
+			granted[permission] = true
+		}
+	}
+
+	for _, permission := range zfsPermissions {
+		if !granted[permission] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// zfsCommand runs zfs on the dataset as the current user if it has been
+// delegated the permissions, or else through sudo.
+func zfsCommand(ctx context.Context, dataset string, args ...string) (string, error) {
+	if zfsDelegated(ctx, dataset) {
+		return run(ctx, "zfs", args...)
+	}
+
+	return sudo(ctx, "zfs", args...)
+}
+
+func (zfs) Volume(ctx context.Context, dir string) (Volume, error) {
+	volume, err := mount(ctx, dir)
+	if err != nil {
+		return Volume{}, err
+	}
+
+	if volume.FSType != "zfs" {
+		return Volume{}, xerrors.Errorf("%q is on a %s filesystem, not a ZFS dataset", dir, volume.FSType)
+	}
+
+	return volume, nil
+}
+
+func (zfs) Create(ctx context.Context, volume Volume, name string) error {
+	_, err := zfsCommand(ctx, volume.Device, "snapshot", volume.Device+"@"+name)
+	return err
+}
+
+func (zfs) Restore(ctx context.Context, volume Volume, name string, dir string) error {
+	return restoreFrom(ctx, filepath.Join(volume.Mountpoint, ".zfs", "snapshot", name), volume, dir)
+}
+
+func (zfs) Delete(ctx context.Context, volume Volume, name string) error {
+	_, err := zfsCommand(ctx, volume.Device, "destroy", volume.Device+"@"+name)
+	return err
+}
+
+func (zfs) Check(ctx context.Context, volume Volume) error {
+	if zfsDelegated(ctx, volume.Device) {
+		return nil
+	}
+
+	return checkSudo(ctx, "zfs")
+}
+
+// btrfs snapshots the subvolume mounted at the mountpoint containing the
+// directory into a hidden directory at the root of the subvolume. Nested
+// subvolumes are not included in the snapshot. Since the root of the subvolume
+// is typically owned by root, the snapshots are created and deleted through
+// sudo.
+type btrfs struct{}
+
+func (btrfs) Volume(ctx context.Context, dir string) (Volume, error) {
+	volume, err := mount(ctx, dir)
+	if err != nil {
+		return Volume{}, err
+	}
+
+	if volume.FSType != "btrfs" {
+		return Volume{}, xerrors.Errorf("%q is on a %s filesystem, not btrfs", dir, volume.FSType)
+	}
+
+	return volume, nil
+}
+
+func btrfsSnapshotPath(volume Volume, name string) string {
+	return filepath.Join(volume.Mountpoint, ".gpupgrade-snapshots", name)
+}
+
+func (btrfs) Create(ctx context.Context, volume Volume, name string) error {
+	path := btrfsSnapshotPath(volume, name)
+
+	// Allow others to only traverse the directory so that the snapshots can
+	// be restored from without exposing their names.
+	if _, err := sudo(ctx, "mkdir", "-p", "-m", "0711", filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	_, err := sudo(ctx, "btrfs", "subvolume", "snapshot", "-r", volume.Mountpoint, path)
+	return err
+}
+
+func (btrfs) Restore(ctx context.Context, volume Volume, name string, dir string) error {
+	return restoreFrom(ctx, btrfsSnapshotPath(volume, name), volume, dir)
+}
+
+func (btrfs) Delete(ctx context.Context, volume Volume, name string) error {
+	_, err := sudo(ctx, "btrfs", "subvolume", "delete", btrfsSnapshotPath(volume, name))
+	return err
+}
+
+func (btrfs) Check(ctx context.Context, volume Volume) error {
+	return checkSudo(ctx, "mkdir", "btrfs")
+}
+
+// lvm snapshots the logical volume containing the directory. The snapshot is
+// allocated the size of its origin so that it cannot overflow, which requires
+// that much free space in the volume group. To restore, the snapshot is
+// temporarily mounted read-only. The LVM commands and mounting require root
+// so they run through sudo.
+type lvm struct{}
+
+func (lvm) Volume(ctx context.Context, dir string) (Volume, error) {
+	volume, err := mount(ctx, dir)
+	if err != nil {
+		return Volume{}, err
+	}
+
+	output, err := sudo(ctx, "lvs", "--noheadings", "--options", "vg_name,lv_name", volume.Device)
+	if err != nil {
+		return Volume{}, xerrors.Errorf("%q is not on an LVM logical volume: %w", dir, err)
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return Volume{}, xerrors.Errorf("unexpected lvs output %q", output)
+	}
+
+	volume.Device = fields[0] + "/" + fields[1]
+	return volume, nil
+}
+
+// lvmSnapshotName qualifies the snapshot name with the origin since logical
+// volume names are unique within a volume group.
+func lvmSnapshotName(volume Volume, name string) string {
+	return filepath.Base(volume.Device) + "-" + name
+}
+
+func (lvm) Create(ctx context.Context, volume Volume, name string) error {
+	_, err := sudo(ctx, "lvcreate", "--snapshot", "--extents", "100%ORIGIN", "--name", lvmSnapshotName(volume, name), volume.Device)
+	return err
+}
+
+func (lvm) Restore(ctx context.Context, volume Volume, name string, dir string) (err error) {
+	mountpoint, err := ioutil.TempDir("", "gpupgrade-snapshot-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rErr := os.Remove(mountpoint); rErr != nil {
+			err = errorlist.Append(err, rErr)
+		}
+	}()
+
+	options := "ro"
+	if volume.FSType == "xfs" {
+		// XFS refuses to mount a snapshot alongside its origin since they
+		// share a filesystem UUID.
+		options += ",nouuid"
+	}
+
+	device := "/dev/" + filepath.Dir(volume.Device) + "/" + lvmSnapshotName(volume, name)
+	if _, err := sudo(ctx, "mount", "-o", options, device, mountpoint); err != nil {
+		return err
+	}
+	defer func() {
+		if _, uErr := sudo(ctx, "umount", mountpoint); uErr != nil {
+			err = errorlist.Append(err, uErr)
+		}
+	}()
+
+	return restoreFrom(ctx, mountpoint, volume, dir)
+}
+
+func (lvm) Delete(ctx context.Context, volume Volume, name string) error {
+	_, err := sudo(ctx, "lvremove", "--yes", filepath.Dir(volume.Device)+"/"+lvmSnapshotName(volume, name))
+	return err
+}
+
+func (lvm) Check(ctx context.Context, volume Volume) error {
+	return checkSudo(ctx, "lvs", "lvcreate", "mount", "umount", "lvremove")
+}
+
+// reflink is the fallback for filesystems such as XFS that support reflinks
+// but not snapshots. Each directory is its own volume, which is copied
+// alongside it sharing its extents, so the copy is fast and only consumes
+// space as the directory is modified.
+type reflink struct{}
+
+func (reflink) Volume(ctx context.Context, dir string) (Volume, error) {
+	dir = filepath.Clean(dir)
+	return Volume{Device: dir, Mountpoint: dir}, nil
+}
+
+func reflinkPath(volume Volume, name string) string {
+	return volume.Device + "." + name
+}
+
+func (reflink) Create(ctx context.Context, volume Volume, name string) error {
+	// Remove any partial copy from a failed attempt since cp would otherwise
+	// copy into it.
+	if err := os.RemoveAll(reflinkPath(volume, name)); err != nil {
+		return err
+	}
+
+	_, err := run(ctx, "cp", "--archive", "--reflink=always", volume.Device, reflinkPath(volume, name))
+	return err
+}
+
+func (reflink) Restore(ctx context.Context, volume Volume, name string, dir string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	_, err := run(ctx, "cp", "--archive", "--reflink=always", reflinkPath(volume, name), dir)
+	return err
+}
+
+func (reflink) Delete(ctx context.Context, volume Volume, name string) error {
+	return os.RemoveAll(reflinkPath(volume, name))
+}
+
+// Check returns nil since reflink copies need no privileges.
+func (reflink) Check(ctx context.Context, volume Volume) error {
+	return nil
+}
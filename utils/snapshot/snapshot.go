@@ -0,0 +1,313 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package snapshot takes filesystem snapshots of the source cluster data
+// directories and tablespaces before they are upgraded in link mode, so that
+// revert can roll them back without rsyncing from the mirrors.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/errorlist"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+)
+
+// The snapshot providers.
+const (
+	None    = "none"
+	LVM     = "lvm"
+	ZFS     = "zfs"
+	Btrfs   = "btrfs"
+	Reflink = "reflink"
+)
+
+var Providers = []string{None, LVM, ZFS, Btrfs, Reflink}
+
+// RecordsFileName is the file in the state directory recording the snapshots
+// taken on the host.
+const RecordsFileName = "snapshots.json"
+
+var command = exec.Command
+
+// Provider snapshots and restores the volumes containing directories.
+type Provider interface {
+	// Volume returns the volume containing dir. Directories on the same
+	// volume share a snapshot.
+	Volume(ctx context.Context, dir string) (Volume, error)
+
+	// Create takes the named snapshot of the volume.
+	Create(ctx context.Context, volume Volume, name string) error
+
+	// Restore returns dir to its contents in the named snapshot of the volume.
+	Restore(ctx context.Context, volume Volume, name string, dir string) error
+
+	// Delete removes the named snapshot of the volume.
+	Delete(ctx context.Context, volume Volume, name string) error
+
+	// Check returns an error if the commands to create, restore, and delete
+	// snapshots of the volume are not allowed.
+	Check(ctx context.Context, volume Volume) error
+}
+
+// Volume identifies a snapshottable filesystem. Device is the ZFS dataset,
+// the LVM logical volume as vg/lv, or for reflink the directory itself.
+type Volume struct {
+	Device     string `json:"device"`
+	Mountpoint string `json:"mountpoint"`
+	FSType     string `json:"fstype,omitempty"`
+}
+
+// Snapshot records a snapshot of a volume and the directories on it that it
+// is used to restore.
+type Snapshot struct {
+	Provider string   `json:"provider"`
+	Name     string   `json:"name"`
+	Volume   Volume   `json:"volume"`
+	Dirs     []string `json:"dirs"`
+}
+
+// ParseProvider returns the snapshot provider name or an error if it is not
+// one of Providers.
+func ParseProvider(input string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(input))
+	for _, choice := range Providers {
+		if name == choice {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("Invalid snapshot provider %q. Please specify one of %s.", input, strings.Join(Providers, ", "))
+}
+
+// New returns the named provider.
+func New(name string) (Provider, error) {
+	switch name {
+	case LVM:
+		return lvm{}, nil
+	case ZFS:
+		return zfs{}, nil
+	case Btrfs:
+		return btrfs{}, nil
+	case Reflink:
+		return reflink{}, nil
+	default:
+		return nil, xerrors.Errorf("unknown snapshot provider %q", name)
+	}
+}
+
+// Check returns an error if the volumes containing dirs cannot be snapshotted
+// by the provider, such as when a directory is not on a volume of the provider
+// or the snapshot commands are not allowed.
+func Check(ctx context.Context, providerName string, dirs []string) error {
+	provider, err := New(providerName)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	checked := make(map[Volume]bool)
+	for _, dir := range dirs {
+		volume, err := provider.Volume(ctx, dir)
+		if err != nil {
+			errs = errorlist.Append(errs, xerrors.Errorf("finding volume of %q: %w", dir, err))
+			continue
+		}
+
+		if checked[volume] {
+			continue
+		}
+		checked[volume] = true
+
+		if err := provider.Check(ctx, volume); err != nil {
+			errs = errorlist.Append(errs, xerrors.Errorf("checking volume %q: %w", volume.Device, err))
+		}
+	}
+
+	return errs
+}
+
+// Take snapshots the volumes containing dirs and records them in the state
+// directory. Any previously recorded snapshots, such as from an earlier
+// attempt, are deleted first.
+func Take(ctx context.Context, stateDir string, providerName string, name string, dirs []string) error {
+	if err := DeleteAll(ctx, stateDir); err != nil {
+		return err
+	}
+
+	provider, err := New(providerName)
+	if err != nil {
+		return err
+	}
+
+	var snapshots []Snapshot
+	index := make(map[Volume]int)
+	for _, dir := range dirs {
+		volume, err := provider.Volume(ctx, dir)
+		if err != nil {
+			return xerrors.Errorf("finding volume of %q: %w", dir, err)
+		}
+
+		if i, ok := index[volume]; ok {
+			snapshots[i].Dirs = append(snapshots[i].Dirs, dir)
+			continue
+		}
+
+		if err := provider.Create(ctx, volume, name); err != nil {
+			return xerrors.Errorf("snapshotting %q: %w", dir, err)
+		}
+
+		index[volume] = len(snapshots)
+		snapshots = append(snapshots, Snapshot{Provider: providerName, Name: name, Volume: volume, Dirs: []string{dir}})
+
+		// Record each snapshot once it exists so that it can be deleted if a
+		// later one fails.
+		if err := save(stateDir, snapshots); err != nil {
+			return err
+		}
+	}
+
+	return save(stateDir, snapshots)
+}
+
+// RestoreAll restores every directory from the snapshots recorded in the
+// state directory.
+func RestoreAll(ctx context.Context, stateDir string) error {
+	snapshots, err := Load(stateDir)
+	if err != nil {
+		return err
+	}
+
+	if len(snapshots) == 0 {
+		return xerrors.Errorf("no snapshots are recorded in %s", recordsPath(stateDir))
+	}
+
+	for _, s := range snapshots {
+		provider, err := New(s.Provider)
+		if err != nil {
+			return err
+		}
+
+		for _, dir := range s.Dirs {
+			gplog.Info("restoring %q from %s snapshot %q of %q", dir, s.Provider, s.Name, s.Volume.Device)
+			if err := provider.Restore(ctx, s.Volume, s.Name, dir); err != nil {
+				return xerrors.Errorf("restoring %q: %w", dir, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DeleteAll deletes the snapshots recorded in the state directory and then
+// the record itself. It does nothing if no snapshots are recorded.
+func DeleteAll(ctx context.Context, stateDir string) error {
+	snapshots, err := Load(stateDir)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, s := range snapshots {
+		provider, err := New(s.Provider)
+		if err != nil {
+			errs = errorlist.Append(errs, err)
+			continue
+		}
+
+		if err := provider.Delete(ctx, s.Volume, s.Name); err != nil {
+			errs = errorlist.Append(errs, xerrors.Errorf("deleting snapshot of %q: %w", s.Volume.Device, err))
+		}
+	}
+
+	if errs != nil {
+		return errs
+	}
+
+	err = os.Remove(recordsPath(stateDir))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func recordsPath(stateDir string) string {
+	return filepath.Join(stateDir, RecordsFileName)
+}
+
+func save(stateDir string, snapshots []Snapshot) error {
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("marshal snapshots: %w", err)
+	}
+
+	return utils.AtomicallyWrite(recordsPath(stateDir), data)
+}
+
+// Load returns the snapshots recorded in the state directory.
+func Load(stateDir string) ([]Snapshot, error) {
+	data, err := ioutil.ReadFile(recordsPath(stateDir))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, xerrors.Errorf("parsing snapshots %s: %w", recordsPath(stateDir), err)
+	}
+
+	return snapshots, nil
+}
+
+func run(ctx context.Context, name string, args ...string) (string, error) {
+	cmd := command(name, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	gplog.Info("running command: %q", cmd)
+	if err := trace.Run(ctx, filepath.Base(name), cmd); err != nil {
+		return "", xerrors.Errorf("%q: %w: %s", cmd.String(), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
+
+// sudo runs the command as root. The -n flag makes sudo fail rather than
+// prompt for a password, so sudo must be configured to allow the command
+// without one, which checkSudo verifies.
+func sudo(ctx context.Context, name string, args ...string) (string, error) {
+	return run(ctx, "sudo", append([]string{"-n", name}, args...)...)
+}
+
+// checkSudo returns an error for each command sudo does not allow running
+// without a password.
+func checkSudo(ctx context.Context, names ...string) error {
+	var errs error
+	for _, name := range names {
+		if _, err := run(ctx, "sudo", "-n", "-l", name); err != nil {
+			errs = errorlist.Append(errs, xerrors.Errorf("sudo does not allow running %s without a password: %w", name, err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,421 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/testutils/exectest"
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/rsync"
+	"github.com/greenplum-db/gpupgrade/utils/snapshot"
+)
+
+// tool returns the command being run, skipping sudo and its -n flag.
+func tool() string {
+	if filepath.Base(os.Args[0]) == "sudo" {
+		return os.Args[2]
+	}
+
+	return filepath.Base(os.Args[0])
+}
+
+func ZFSTools() {
+	if tool() == "findmnt" {
+		fmt.Println("tank/gpdata /gpdata zfs")
+	}
+}
+
+// ZFSDelegatedTools lists the snapshot permissions as delegated to the current
+// user.
+func ZFSDelegatedTools() {
+	switch tool() {
+	case "findmnt":
+		fmt.Println("tank/gpdata /gpdata zfs")
+	case "zfs":
+		if os.Args[1] == "allow" {
+			current, err := user.Current()
+			if err != nil {
+				os.Exit(1)
+			}
+
+			fmt.Println("---- Permissions on tank/gpdata ---------------------------------------")
+			fmt.Println("Local+Descendent permissions:")
+			fmt.Printf("\tuser %s destroy,mount,snapshot\n", current.Username)
+		}
+	}
+}
+
+func LVMTools() {
+	switch tool() {
+	case "findmnt":
+		fmt.Println("/dev/mapper/vg-data /data xfs")
+	case "lvs":
+		fmt.Println("  vg data")
+	}
+}
+
+func XFSTools() {
+	if tool() == "findmnt" {
+		fmt.Println("/dev/sdb1 /data xfs")
+	}
+}
+
+// LVMToolsWithoutSudo fails sudo as when a password is required.
+func LVMToolsWithoutSudo() {
+	if filepath.Base(os.Args[0]) == "sudo" && os.Args[2] == "-l" {
+		fmt.Fprint(os.Stderr, "sudo: a password is required")
+		os.Exit(1)
+	}
+
+	LVMTools()
+}
+
+func init() {
+	exectest.RegisterMains(
+		ZFSTools,
+		ZFSDelegatedTools,
+		LVMTools,
+		LVMToolsWithoutSudo,
+		XFSTools,
+	)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(exectest.Run(m))
+}
+
+// recorder returns a command that runs main and records each invocation.
+func recorder(main exectest.Main, calls *[]string) exectest.Command {
+	return exectest.NewCommandWithVerifier(main, func(name string, args ...string) {
+		*calls = append(*calls, strings.Join(append([]string{name}, args...), " "))
+	})
+}
+
+func TestParseProvider(t *testing.T) {
+	for _, input := range []string{"none", "lvm", "zfs", "btrfs", " Reflink "} {
+		provider, err := snapshot.ParseProvider(input)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := strings.ToLower(strings.TrimSpace(input))
+		if provider != expected {
+			t.Errorf("ParseProvider(%q) = %q want %q", input, provider, expected)
+		}
+	}
+
+	_, err := snapshot.ParseProvider("ext4")
+	expected := `Invalid snapshot provider "ext4"`
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("got error %v want it to contain %q", err, expected)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	testlog.SetupLogger()
+
+	t.Run("checks each volume once", func(t *testing.T) {
+		var calls []string
+		snapshot.SetCommand(recorder(LVMTools, &calls))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Check(context.Background(), snapshot.LVM, []string{"/data/primary/gpseg0", "/data/primary/gpseg1"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var sudoChecks []string
+		for _, call := range calls {
+			if strings.HasPrefix(call, "sudo -n -l ") {
+				sudoChecks = append(sudoChecks, strings.TrimPrefix(call, "sudo -n -l "))
+			}
+		}
+
+		expected := []string{"lvs", "lvcreate", "mount", "umount", "lvremove"}
+		if !reflect.DeepEqual(sudoChecks, expected) {
+			t.Errorf("got sudo checks %q want %q", sudoChecks, expected)
+		}
+	})
+
+	t.Run("errors when sudo requires a password", func(t *testing.T) {
+		snapshot.SetCommand(exectest.NewCommand(LVMToolsWithoutSudo))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Check(context.Background(), snapshot.LVM, []string{"/data/primary/gpseg0"})
+		expected := "sudo does not allow running lvcreate without a password"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+
+	t.Run("skips sudo when the zfs permissions are delegated", func(t *testing.T) {
+		var calls []string
+		snapshot.SetCommand(recorder(ZFSDelegatedTools, &calls))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Check(context.Background(), snapshot.ZFS, []string{"/gpdata/primary/gpseg0"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		for _, call := range calls {
+			if strings.HasPrefix(call, "sudo") {
+				t.Errorf("unexpected call %q", call)
+			}
+		}
+	})
+
+	t.Run("errors when the directory is not on the provider's filesystem", func(t *testing.T) {
+		snapshot.SetCommand(exectest.NewCommand(XFSTools))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Check(context.Background(), snapshot.Btrfs, []string{"/data/primary/gpseg0"})
+		expected := `"/data/primary/gpseg0" is on a xfs filesystem, not btrfs`
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+}
+
+func TestTake(t *testing.T) {
+	testlog.SetupLogger()
+
+	t.Run("takes one snapshot per volume and records it", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		var calls []string
+		snapshot.SetCommand(recorder(ZFSTools, &calls))
+		defer snapshot.ResetCommand()
+
+		dirs := []string{"/gpdata/primary/gpseg0", "/gpdata/primary/gpseg1"}
+		err := snapshot.Take(context.Background(), stateDir, snapshot.ZFS, "gpupgrade-abc", dirs)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expectedCalls := []string{
+			"findmnt --noheadings --output SOURCE,TARGET,FSTYPE --target /gpdata/primary/gpseg0",
+			"zfs allow tank/gpdata",
+			"sudo -n zfs snapshot tank/gpdata@gpupgrade-abc",
+			"findmnt --noheadings --output SOURCE,TARGET,FSTYPE --target /gpdata/primary/gpseg1",
+		}
+		if !reflect.DeepEqual(calls, expectedCalls) {
+			t.Errorf("got calls %q want %q", calls, expectedCalls)
+		}
+
+		snapshots, err := snapshot.Load(stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []snapshot.Snapshot{{
+			Provider: snapshot.ZFS,
+			Name:     "gpupgrade-abc",
+			Volume:   snapshot.Volume{Device: "tank/gpdata", Mountpoint: "/gpdata", FSType: "zfs"},
+			Dirs:     dirs,
+		}}
+		if !reflect.DeepEqual(snapshots, expected) {
+			t.Errorf("got snapshots %+v want %+v", snapshots, expected)
+		}
+	})
+
+	t.Run("names LVM snapshots after their origin", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		var calls []string
+		snapshot.SetCommand(recorder(LVMTools, &calls))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Take(context.Background(), stateDir, snapshot.LVM, "gpupgrade-abc", []string{"/data/primary/gpseg0"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := "sudo -n lvcreate --snapshot --extents 100%ORIGIN --name data-gpupgrade-abc vg/data"
+		if calls[len(calls)-1] != expected {
+			t.Errorf("got call %q want %q", calls[len(calls)-1], expected)
+		}
+	})
+
+	t.Run("errors when the directory is not on the provider's filesystem", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		snapshot.SetCommand(exectest.NewCommand(XFSTools))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Take(context.Background(), stateDir, snapshot.ZFS, "gpupgrade-abc", []string{"/data/primary/gpseg0"})
+		expected := `"/data/primary/gpseg0" is on a xfs filesystem, not a ZFS dataset`
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+
+	t.Run("deletes previously recorded snapshots first", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		snapshot.SetCommand(exectest.NewCommand(ZFSTools))
+		err := snapshot.Take(context.Background(), stateDir, snapshot.ZFS, "gpupgrade-abc", []string{"/gpdata/primary/gpseg0"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var calls []string
+		snapshot.SetCommand(recorder(ZFSTools, &calls))
+		defer snapshot.ResetCommand()
+
+		err = snapshot.Take(context.Background(), stateDir, snapshot.ZFS, "gpupgrade-abc", []string{"/gpdata/primary/gpseg0"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []string{"zfs allow tank/gpdata", "sudo -n zfs destroy tank/gpdata@gpupgrade-abc"}
+		if len(calls) < len(expected) || !reflect.DeepEqual(calls[:len(expected)], expected) {
+			t.Errorf("got calls %q want the first to be %q", calls, expected)
+		}
+	})
+
+	t.Run("runs zfs without sudo when the permissions are delegated", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		var calls []string
+		snapshot.SetCommand(recorder(ZFSDelegatedTools, &calls))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Take(context.Background(), stateDir, snapshot.ZFS, "gpupgrade-abc", []string{"/gpdata/primary/gpseg0"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := "zfs snapshot tank/gpdata@gpupgrade-abc"
+		if calls[len(calls)-1] != expected {
+			t.Errorf("got call %q want %q", calls[len(calls)-1], expected)
+		}
+	})
+}
+
+func TestRestoreAll(t *testing.T) {
+	testlog.SetupLogger()
+
+	t.Run("rsyncs each directory from its snapshot", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		snapshot.SetCommand(exectest.NewCommand(ZFSTools))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Take(context.Background(), stateDir, snapshot.ZFS, "gpupgrade-abc", []string{"/gpdata/primary/gpseg0"})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		var calls []string
+		rsync.SetRsyncCommand(recorder(exectest.Success, &calls))
+		defer rsync.ResetRsyncCommand()
+
+		err = snapshot.RestoreAll(context.Background(), stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []string{"rsync --archive --delete /gpdata/.zfs/snapshot/gpupgrade-abc/primary/gpseg0/ /gpdata/primary/gpseg0"}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Errorf("got calls %q want %q", calls, expected)
+		}
+	})
+
+	t.Run("replaces the directory with its reflink copy", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		dataDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, dataDir)
+
+		var calls []string
+		snapshot.SetCommand(recorder(exectest.Success, &calls))
+		defer snapshot.ResetCommand()
+
+		err := snapshot.Take(context.Background(), stateDir, snapshot.Reflink, "gpupgrade-abc", []string{dataDir})
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		err = snapshot.RestoreAll(context.Background(), stateDir)
+		if err != nil {
+			t.Fatalf("unexpected error %#v", err)
+		}
+
+		expected := []string{
+			fmt.Sprintf("cp --archive --reflink=always %[1]s %[1]s.gpupgrade-abc", dataDir),
+			fmt.Sprintf("cp --archive --reflink=always %[1]s.gpupgrade-abc %[1]s", dataDir),
+		}
+		if !reflect.DeepEqual(calls, expected) {
+			t.Errorf("got calls %q want %q", calls, expected)
+		}
+
+		if _, err := os.Stat(dataDir); !os.IsNotExist(err) {
+			t.Errorf("expected %q to be removed before restoring, got error %#v", dataDir, err)
+		}
+	})
+
+	t.Run("errors when no snapshots are recorded", func(t *testing.T) {
+		stateDir := testutils.GetTempDir(t, "")
+		defer testutils.MustRemoveAll(t, stateDir)
+
+		err := snapshot.RestoreAll(context.Background(), stateDir)
+		expected := "no snapshots are recorded"
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("got error %v want it to contain %q", err, expected)
+		}
+	})
+}
+
+func TestDeleteAll(t *testing.T) {
+	testlog.SetupLogger()
+
+	stateDir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, stateDir)
+
+	var calls []string
+	snapshot.SetCommand(recorder(LVMTools, &calls))
+	defer snapshot.ResetCommand()
+
+	err := snapshot.Take(context.Background(), stateDir, snapshot.LVM, "gpupgrade-abc", []string{"/data/primary/gpseg0"})
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	calls = nil
+	err = snapshot.DeleteAll(context.Background(), stateDir)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	expected := []string{"sudo -n lvremove --yes vg/data-gpupgrade-abc"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("got calls %q want %q", calls, expected)
+	}
+
+	snapshots, err := snapshot.Load(stateDir)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if len(snapshots) != 0 {
+		t.Errorf("got snapshots %+v want none", snapshots)
+	}
+}
@@ -111,6 +111,30 @@ func GetLogDir() (string, error) {
 	return logDir, nil
 }
 
+// GetInventoryDir returns the directory of the inventory snapshots taken
+// during the upgrade. It is within the log directory so that it is archived
+// along with the logs.
+func GetInventoryDir() (string, error) {
+	logDir, err := GetLogDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(logDir, "inventory"), nil
+}
+
+// GetValidationDir returns the directory of the data snapshots used to
+// validate the upgraded data. It is within the log directory so that it is
+// archived along with the logs.
+func GetValidationDir() (string, error) {
+	logDir, err := GetLogDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(logDir, "validation"), nil
+}
+
 func GetTablespaceDir() string {
 	return filepath.Join(GetStateDir(), "tablespaces")
 }
@@ -128,6 +152,17 @@ func GetPgUpgradeDir(role string, contentID int32) (string, error) {
 	return filepath.Join(logDir, "pg_upgrade", fmt.Sprintf(role+"%d", contentID)), nil
 }
 
+// GetCollectedPgUpgradeDir returns the directory on the coordinator that the
+// pg_upgrade logs of a failed segment are copied to.
+func GetCollectedPgUpgradeDir(hostname string, role string, contentID int32) (string, error) {
+	logDir, err := GetLogDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(logDir, "pg_upgrade_segments", hostname, fmt.Sprintf(role+"%d", contentID)), nil
+}
+
 func GetCoordinatorPreUpgradeBackupDir() string {
 	return filepath.Join(GetStateDir(), "coordinator-pre-upgrade-backup")
 }
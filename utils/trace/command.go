@@ -0,0 +1,20 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Run runs the command in a span named after the utility, such as
+// "exec pg_upgrade", under the span carried by the context.
+func Run(ctx context.Context, utility string, cmd *exec.Cmd) error {
+	_, span := StartSpan(ctx, "exec "+utility)
+	span.SetAttribute("command", cmd.String())
+
+	err := cmd.Run()
+	span.End(err)
+	return err
+}
@@ -0,0 +1,432 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/greenplum-db/gp-common-go-libs/gplog"
+	"golang.org/x/xerrors"
+)
+
+// The trace exporters.
+const (
+	None = "none"
+	File = "file"
+	OTLP = "otlp"
+)
+
+var Exporters = []string{None, File, OTLP}
+
+// Config configures how a process exports its spans.
+type Config struct {
+	Exporter string
+
+	// Endpoint is the base URL of the OTLP/HTTP collector, such as
+	// http://collector:4318, when the exporter is otlp.
+	Endpoint string
+}
+
+func (c Config) Enabled() bool {
+	return c.Exporter != "" && c.Exporter != None
+}
+
+// Args returns the flags that configure an agent to export its spans the
+// same way.
+func (c Config) Args() []string {
+	if !c.Enabled() {
+		return nil
+	}
+
+	args := []string{"--trace-exporter", c.Exporter}
+	if c.Endpoint != "" {
+		args = append(args, "--trace-endpoint", c.Endpoint)
+	}
+
+	return args
+}
+
+// ParseExporter returns the exporter name or an error if it is not one of
+// Exporters.
+func ParseExporter(input string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(input))
+	for _, choice := range Exporters {
+		if name == choice {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("Invalid trace exporter %q. Please specify one of %s.", input, strings.Join(Exporters, ", "))
+}
+
+// ParseEndpoint checks that the endpoint is an http or https URL.
+func ParseEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Invalid trace endpoint %q. Please specify an http or https URL such as http://collector:4318.", endpoint)
+	}
+
+	return nil
+}
+
+// Exporter sends ended spans to their destination.
+type Exporter interface {
+	Export(span SpanData)
+
+	// Shutdown sends any spans not yet sent and releases the exporter.
+	Shutdown() error
+}
+
+var exporterMu sync.Mutex
+var exporter Exporter
+
+// SetExporter exports the spans ended from now on with the exporter, or
+// drops them when it is nil.
+func SetExporter(e Exporter) {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+
+	exporter = e
+}
+
+func export(span SpanData) {
+	exporterMu.Lock()
+	e := exporter
+	exporterMu.Unlock()
+
+	if e != nil {
+		e.Export(span)
+	}
+}
+
+// Setup exports the spans of the named process, such as gpupgrade_hub, as
+// configured. The file exporter writes to the log directory.
+func Setup(service string, logDir string, config Config) error {
+	var e Exporter
+	var err error
+
+	switch config.Exporter {
+	case "", None:
+		return nil
+	case File:
+		e, err = NewFileExporter(service, logDir)
+	case OTLP:
+		e, err = NewOTLPExporter(service, config.Endpoint)
+	default:
+		_, err = ParseExporter(config.Exporter)
+	}
+	if err != nil {
+		return err
+	}
+
+	SetExporter(e)
+	return nil
+}
+
+// Shutdown sends the spans not yet sent and stops exporting.
+func Shutdown() error {
+	exporterMu.Lock()
+	e := exporter
+	exporter = nil
+	exporterMu.Unlock()
+
+	if e == nil {
+		return nil
+	}
+
+	return e.Shutdown()
+}
+
+func hostname() string {
+	host, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+
+	return host
+}
+
+// FileExporter writes each span as a line of JSON.
+type FileExporter struct {
+	mu      sync.Mutex
+	file    *os.File
+	service string
+	host    string
+}
+
+type fileSpan struct {
+	Service      string            `json:"service"`
+	Host         string            `json:"host"`
+	Name         string            `json:"name"`
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Duration     float64           `json:"duration_seconds"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+	Error        string            `json:"error,omitempty"`
+}
+
+// NewFileExporter appends the spans of the process to a file in the log
+// directory named like the log files, such as gpupgrade_hub_trace_20220101.jsonl.
+func NewFileExporter(service string, logDir string) (*FileExporter, error) {
+	path := filepath.Join(logDir, fmt.Sprintf("%s_trace_%s.jsonl", service, time.Now().Format("20060102")))
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, xerrors.Errorf("open trace file: %w", err)
+	}
+
+	return &FileExporter{file: file, service: service, host: hostname()}, nil
+}
+
+func (f *FileExporter) Export(span SpanData) {
+	line, err := json.Marshal(fileSpan{
+		Service:      f.service,
+		Host:         f.host,
+		Name:         span.Name,
+		TraceID:      span.TraceID,
+		SpanID:       span.SpanID,
+		ParentSpanID: span.ParentSpanID,
+		Start:        span.Start,
+		End:          span.End,
+		Duration:     span.End.Sub(span.Start).Seconds(),
+		Attributes:   span.Attributes,
+		Error:        span.Error,
+	})
+	if err != nil {
+		gplog.Debug("encoding span %q: %v", span.Name, err)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		gplog.Debug("writing span %q: %v", span.Name, err)
+	}
+}
+
+func (f *FileExporter) Shutdown() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.file.Close()
+}
+
+// OTLPBatchSize and OTLPInterval bound how many spans the OTLP exporter holds
+// and for how long before sending them.
+var (
+	OTLPBatchSize = 512
+	OTLPInterval  = 5 * time.Second
+)
+
+// OTLPExporter sends spans in batches to an OTLP/HTTP collector using the
+// JSON encoding.
+type OTLPExporter struct {
+	url      string
+	resource []otlpAttribute
+	client   *http.Client
+
+	mu    sync.Mutex
+	spans []SpanData
+
+	full chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewOTLPExporter sends the spans to the traces path of the endpoint.
+func NewOTLPExporter(service string, endpoint string) (*OTLPExporter, error) {
+	if err := ParseEndpoint(endpoint); err != nil {
+		return nil, err
+	}
+
+	tracesURL := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(tracesURL, "/v1/traces") {
+		tracesURL += "/v1/traces"
+	}
+
+	o := &OTLPExporter{
+		url: tracesURL,
+		resource: []otlpAttribute{
+			newOTLPAttribute("service.name", service),
+			newOTLPAttribute("host.name", hostname()),
+		},
+		client: &http.Client{Timeout: 10 * time.Second},
+		full:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	go o.run()
+	return o, nil
+}
+
+func (o *OTLPExporter) Export(span SpanData) {
+	o.mu.Lock()
+	o.spans = append(o.spans, span)
+	full := len(o.spans) >= OTLPBatchSize
+	o.mu.Unlock()
+
+	if full {
+		select {
+		case o.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (o *OTLPExporter) run() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(OTLPInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-o.full:
+		case <-o.stop:
+			return
+		}
+
+		if err := o.flush(); err != nil {
+			gplog.Debug("exporting spans: %v", err)
+		}
+	}
+}
+
+func (o *OTLPExporter) Shutdown() error {
+	close(o.stop)
+	<-o.done
+
+	return o.flush()
+}
+
+func (o *OTLPExporter) flush() error {
+	o.mu.Lock()
+	spans := o.spans
+	o.spans = nil
+	o.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(o.request(spans))
+	if err != nil {
+		return xerrors.Errorf("encoding spans: %w", err)
+	}
+
+	resp, err := o.client.Post(o.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return xerrors.Errorf("sending %d spans: %w", len(spans), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return xerrors.Errorf("sending %d spans to %s: %s", len(spans), o.url, resp.Status)
+	}
+
+	return nil
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// The OTLP status code of a span that failed.
+const otlpStatusError = 2
+
+func newOTLPAttribute(key string, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpValue{StringValue: value}}
+}
+
+func (o *OTLPExporter) request(spans []SpanData) otlpRequest {
+	var otlpSpans []otlpSpan
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              span.Kind,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		}
+
+		for _, key := range sortedKeys(span.Attributes) {
+			s.Attributes = append(s.Attributes, newOTLPAttribute(key, span.Attributes[key]))
+		}
+
+		if span.Error != "" {
+			s.Status = otlpStatus{Code: otlpStatusError, Message: span.Error}
+		}
+
+		otlpSpans = append(otlpSpans, s)
+	}
+
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: o.resource},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: "gpupgrade"},
+			Spans: otlpSpans,
+		}},
+	}}}
+}
@@ -0,0 +1,233 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package trace_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/greenplum-db/gpupgrade/testutils/testlog"
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+)
+
+var span = trace.SpanData{
+	Name:         "UPGRADE_PRIMARIES",
+	Kind:         trace.KindInternal,
+	TraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+	SpanID:       "00f067aa0ba902b7",
+	ParentSpanID: "b7ad6b7169203331",
+	Start:        time.Unix(1640995200, 0).UTC(),
+	End:          time.Unix(1640995260, 500).UTC(),
+	Attributes:   map[string]string{"status": "FAILED", "host": "sdw1"},
+	Error:        "oops",
+}
+
+func TestConfig(t *testing.T) {
+	t.Run("parses exporters", func(t *testing.T) {
+		exporter, err := trace.ParseExporter(" OTLP ")
+		if err != nil || exporter != trace.OTLP {
+			t.Errorf("got %q, %v want %q", exporter, err, trace.OTLP)
+		}
+
+		_, err = trace.ParseExporter("jaeger")
+		expected := `Invalid trace exporter "jaeger". Please specify one of none, file, otlp.`
+		if err == nil || err.Error() != expected {
+			t.Errorf("got error %v want %q", err, expected)
+		}
+	})
+
+	t.Run("parses endpoints", func(t *testing.T) {
+		for _, endpoint := range []string{"http://collector:4318", "https://collector/otlp"} {
+			if err := trace.ParseEndpoint(endpoint); err != nil {
+				t.Errorf("unexpected error %+v for %q", err, endpoint)
+			}
+		}
+
+		for _, endpoint := range []string{"", "collector:4318", "grpc://collector:4317", "http://"} {
+			if err := trace.ParseEndpoint(endpoint); err == nil {
+				t.Errorf("expected an error for %q", endpoint)
+			}
+		}
+	})
+
+	t.Run("returns the agent flags", func(t *testing.T) {
+		cases := []struct {
+			config   trace.Config
+			expected []string
+		}{
+			{config: trace.Config{}},
+			{config: trace.Config{Exporter: trace.None}},
+			{config: trace.Config{Exporter: trace.File}, expected: []string{"--trace-exporter", "file"}},
+			{config: trace.Config{Exporter: trace.OTLP, Endpoint: "http://collector:4318"}, expected: []string{"--trace-exporter", "otlp", "--trace-endpoint", "http://collector:4318"}},
+		}
+
+		for _, c := range cases {
+			if args := c.config.Args(); !reflect.DeepEqual(args, c.expected) {
+				t.Errorf("got %q want %q", args, c.expected)
+			}
+		}
+	})
+}
+
+func TestFileExporter(t *testing.T) {
+	dir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := trace.Setup("gpupgrade_hub", dir, trace.Config{Exporter: trace.File}); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	if err := trace.Shutdown(); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	exporter, err := trace.NewFileExporter("gpupgrade_agent", dir)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	exporter.Export(span)
+	if err := exporter.Shutdown(); err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "gpupgrade_*_trace_*.jsonl"))
+	if err != nil || len(matches) != 2 {
+		t.Fatalf("got trace files %q, %v want one each for the hub and agent", matches, err)
+	}
+
+	path := filepath.Join(dir, "gpupgrade_agent_trace_"+time.Now().Format("20060102")+".jsonl")
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error %+v", err)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(contents, &line); err != nil {
+		t.Fatalf("unexpected error %+v in %q", err, contents)
+	}
+
+	hostname, _ := os.Hostname()
+	expected := map[string]interface{}{
+		"service":          "gpupgrade_agent",
+		"host":             hostname,
+		"name":             "UPGRADE_PRIMARIES",
+		"trace_id":         "4bf92f3577b34da6a3ce929d0e0e4736",
+		"span_id":          "00f067aa0ba902b7",
+		"parent_span_id":   "b7ad6b7169203331",
+		"start":            "2022-01-01T00:00:00Z",
+		"end":              "2022-01-01T00:01:00.0000005Z",
+		"duration_seconds": 60.0000005,
+		"attributes":       map[string]interface{}{"status": "FAILED", "host": "sdw1"},
+		"error":            "oops",
+	}
+	if !reflect.DeepEqual(line, expected) {
+		t.Errorf("got %v want %v", line, expected)
+	}
+}
+
+func TestOTLPExporter(t *testing.T) {
+	testlog.SetupLogger()
+
+	t.Run("sends the spans to the collector when shut down", func(t *testing.T) {
+		var mu sync.Mutex
+		var paths []string
+		var bodies [][]byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+
+			mu.Lock()
+			defer mu.Unlock()
+			paths = append(paths, r.URL.Path)
+			bodies = append(bodies, body)
+		}))
+		defer server.Close()
+
+		exporter, err := trace.NewOTLPExporter("gpupgrade_hub", server.URL+"/")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		exporter.Export(span)
+		if err := exporter.Shutdown(); err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if !reflect.DeepEqual(paths, []string{"/v1/traces"}) {
+			t.Fatalf("got requests to %q want one to /v1/traces", paths)
+		}
+
+		body := string(bodies[0])
+		expected := []string{
+			`{"key":"service.name","value":{"stringValue":"gpupgrade_hub"}}`,
+			`"scope":{"name":"gpupgrade"}`,
+			`"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b7","parentSpanId":"b7ad6b7169203331","name":"UPGRADE_PRIMARIES","kind":1`,
+			`"startTimeUnixNano":"1640995200000000000","endTimeUnixNano":"1640995260000000500"`,
+			`"attributes":[{"key":"host","value":{"stringValue":"sdw1"}},{"key":"status","value":{"stringValue":"FAILED"}}]`,
+			`"status":{"code":2,"message":"oops"}`,
+		}
+		for _, e := range expected {
+			if !strings.Contains(body, e) {
+				t.Errorf("got body %s want it to contain %s", body, e)
+			}
+		}
+	})
+
+	t.Run("sends a batch once it is full", func(t *testing.T) {
+		requests := make(chan struct{}, 10)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests <- struct{}{}
+		}))
+		defer server.Close()
+
+		batchSize, interval := trace.OTLPBatchSize, trace.OTLPInterval
+		trace.OTLPBatchSize, trace.OTLPInterval = 2, time.Hour
+		defer func() { trace.OTLPBatchSize, trace.OTLPInterval = batchSize, interval }()
+
+		exporter, err := trace.NewOTLPExporter("gpupgrade_hub", server.URL)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+		defer exporter.Shutdown() //nolint
+
+		exporter.Export(span)
+		exporter.Export(span)
+
+		select {
+		case <-requests:
+		case <-time.After(5 * time.Second):
+			t.Errorf("expected the full batch to be sent")
+		}
+	})
+
+	t.Run("errors when the collector rejects the spans", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		exporter, err := trace.NewOTLPExporter("gpupgrade_hub", server.URL+"/v1/traces")
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		exporter.Export(span)
+		err = exporter.Shutdown()
+		if err == nil || !strings.Contains(err.Error(), "400 Bad Request") {
+			t.Errorf("got error %v want the collector status", err)
+		}
+	})
+}
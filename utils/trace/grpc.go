@@ -0,0 +1,110 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// traceparentKey is the gRPC metadata key carrying the trace context.
+const traceparentKey = "traceparent"
+
+// UnaryServerInterceptor records a span for each call, continuing the trace
+// of the caller. The context given to the handler carries the span.
+func UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startSpan(incoming(ctx), info.FullMethod, KindServer)
+
+	resp, err := handler(ctx, req)
+	span.End(err)
+	return resp, err
+}
+
+// StreamServerInterceptor is the UnaryServerInterceptor for streaming calls
+// such as the steps of the hub.
+func StreamServerInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, span := startSpan(incoming(stream.Context()), info.FullMethod, KindServer)
+
+	err := handler(srv, &serverStream{ServerStream: stream, ctx: ctx})
+	span.End(err)
+	return err
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func incoming(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+
+	values := md.Get(traceparentKey)
+	if len(values) == 0 {
+		return ctx
+	}
+
+	remote, err := ParseTraceparent(values[0])
+	if err != nil {
+		return ctx
+	}
+
+	return withRemote(ctx, remote)
+}
+
+// UnaryClientInterceptor records a span for each call and passes its trace
+// context to the server.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := outgoing(ctx, method, cc)
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	span.End(err)
+	return err
+}
+
+// StreamClientInterceptor is the UnaryClientInterceptor for streaming calls.
+// The span ends when the stream does.
+func StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, span := outgoing(ctx, method, cc)
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	return &clientStream{ClientStream: stream, span: span}, nil
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	span *Span
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		s.span.End(nil)
+	} else if err != nil {
+		s.span.End(err)
+	}
+
+	return err
+}
+
+func outgoing(ctx context.Context, method string, cc *grpc.ClientConn) (context.Context, *Span) {
+	ctx, span := startSpan(ctx, method, KindClient)
+	span.SetAttribute("rpc.target", cc.Target())
+
+	return metadata.AppendToOutgoingContext(ctx, traceparentKey, span.Context().Traceparent()), span
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package trace records spans of the work done by the CLI, hub, and agents and
+// propagates their trace context between the processes in the W3C traceparent
+// format so that the spans of an upgrade form a single trace. Spans are
+// exported as OTLP to a collector or as JSON lines to a file in the log
+// directory.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpanContext identifies a span and the trace it belongs to.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+}
+
+func (c SpanContext) IsValid() bool {
+	return c.TraceID != [16]byte{} && c.SpanID != [8]byte{}
+}
+
+// Traceparent returns the context in the W3C traceparent format.
+func (c SpanContext) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-01", hex.EncodeToString(c.TraceID[:]), hex.EncodeToString(c.SpanID[:]))
+}
+
+// ParseTraceparent parses a W3C traceparent such as
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func ParseTraceparent(traceparent string) (SpanContext, error) {
+	var c SpanContext
+
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return c, fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+
+	traceID, err := hex.DecodeString(parts[1])
+	if err != nil || len(traceID) != len(c.TraceID) {
+		return c, fmt.Errorf("invalid trace ID in traceparent %q", traceparent)
+	}
+
+	spanID, err := hex.DecodeString(parts[2])
+	if err != nil || len(spanID) != len(c.SpanID) {
+		return c, fmt.Errorf("invalid span ID in traceparent %q", traceparent)
+	}
+
+	copy(c.TraceID[:], traceID)
+	copy(c.SpanID[:], spanID)
+	if !c.IsValid() {
+		return c, fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+
+	return c, nil
+}
+
+// The span kinds numbered as in OTLP.
+const (
+	KindInternal = 1
+	KindServer   = 2
+	KindClient   = 3
+)
+
+// Span is a timed operation. It is exported when it ends.
+type Span struct {
+	mu         sync.Mutex
+	name       string
+	kind       int
+	context    SpanContext
+	parent     [8]byte
+	start      time.Time
+	attributes map[string]string
+	ended      bool
+}
+
+// SpanData is an ended span as given to exporters.
+type SpanData struct {
+	Name         string
+	Kind         int
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]string
+	Error        string
+}
+
+type spanKey struct{}
+type remoteKey struct{}
+
+// StartSpan begins a span. Its parent is the span in the context, or else the
+// remote span of an incoming call carried by the context. Without either the
+// span begins a new trace. The returned context carries the span.
+func StartSpan(ctx context.Context, name string) (context.Context, *Span) {
+	return startSpan(ctx, name, KindInternal)
+}
+
+func startSpan(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	span := &Span{
+		name:       name,
+		kind:       kind,
+		start:      time.Now(),
+		attributes: make(map[string]string),
+	}
+
+	parent := parentContext(ctx)
+	if parent.IsValid() {
+		span.context.TraceID = parent.TraceID
+		span.parent = parent.SpanID
+	} else {
+		rand.Read(span.context.TraceID[:]) //nolint
+	}
+	rand.Read(span.context.SpanID[:]) //nolint
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func parentContext(ctx context.Context) SpanContext {
+	if span, ok := ctx.Value(spanKey{}).(*Span); ok {
+		return span.Context()
+	}
+
+	if remote, ok := ctx.Value(remoteKey{}).(SpanContext); ok {
+		return remote
+	}
+
+	return SpanContext{}
+}
+
+// FromContext returns the span carried by the context or nil.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// withRemote returns a context carrying the span context of an incoming call.
+func withRemote(ctx context.Context, remote SpanContext) context.Context {
+	return context.WithValue(ctx, remoteKey{}, remote)
+}
+
+func (s *Span) Context() SpanContext {
+	return s.context
+}
+
+// SetAttribute annotates the span, such as with the host or command.
+func (s *Span) SetAttribute(key string, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.attributes[key] = value
+}
+
+// End ends the span with the error of its operation, if any, and exports it.
+// Only the first call has an effect.
+func (s *Span) End(err error) {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+
+	data := SpanData{
+		Name:       s.name,
+		Kind:       s.kind,
+		TraceID:    hex.EncodeToString(s.context.TraceID[:]),
+		SpanID:     hex.EncodeToString(s.context.SpanID[:]),
+		Start:      s.start,
+		End:        time.Now(),
+		Attributes: make(map[string]string, len(s.attributes)),
+	}
+	for key, value := range s.attributes {
+		data.Attributes[key] = value
+	}
+	if s.parent != [8]byte{} {
+		data.ParentSpanID = hex.EncodeToString(s.parent[:])
+	}
+	if err != nil {
+		data.Error = err.Error()
+	}
+	s.mu.Unlock()
+
+	export(data)
+}
+
+// sortedKeys returns the attribute keys in order so that exports are stable.
+func sortedKeys(attributes map[string]string) []string {
+	var keys []string
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,278 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package trace_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/greenplum-db/gpupgrade/utils/trace"
+)
+
+type recordingExporter struct {
+	mu    sync.Mutex
+	spans []trace.SpanData
+}
+
+func (r *recordingExporter) Export(span trace.SpanData) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spans = append(r.spans, span)
+}
+
+func (r *recordingExporter) Shutdown() error {
+	return nil
+}
+
+func record(t *testing.T) *recordingExporter {
+	t.Helper()
+
+	exporter := &recordingExporter{}
+	trace.SetExporter(exporter)
+	t.Cleanup(func() { trace.SetExporter(nil) })
+
+	return exporter
+}
+
+func TestTraceparent(t *testing.T) {
+	t.Run("round trips the span context", func(t *testing.T) {
+		traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+		c, err := trace.ParseTraceparent(traceparent)
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+
+		if c.Traceparent() != traceparent {
+			t.Errorf("got %q want %q", c.Traceparent(), traceparent)
+		}
+	})
+
+	t.Run("errors on invalid traceparents", func(t *testing.T) {
+		cases := []string{
+			"",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-01",
+			"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e47-00f067aa0ba902b7-01",
+			"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",
+			"00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		}
+
+		for _, c := range cases {
+			if _, err := trace.ParseTraceparent(c); err == nil {
+				t.Errorf("expected an error for %q", c)
+			}
+		}
+	})
+}
+
+func TestSpan(t *testing.T) {
+	t.Run("begins a new trace without a parent", func(t *testing.T) {
+		exporter := record(t)
+
+		_, span := trace.StartSpan(context.Background(), "execute")
+		span.SetAttribute("host", "sdw1")
+		span.End(errors.New("oops"))
+		span.End(nil)
+
+		if len(exporter.spans) != 1 {
+			t.Fatalf("got %d spans want 1", len(exporter.spans))
+		}
+
+		data := exporter.spans[0]
+		if data.Name != "execute" || data.ParentSpanID != "" || data.Error != "oops" || data.Kind != trace.KindInternal {
+			t.Errorf("got span %+v", data)
+		}
+
+		if !reflect.DeepEqual(data.Attributes, map[string]string{"host": "sdw1"}) {
+			t.Errorf("got attributes %v", data.Attributes)
+		}
+
+		if data.End.Before(data.Start) {
+			t.Errorf("span ended at %s before it started at %s", data.End, data.Start)
+		}
+	})
+
+	t.Run("is a child of the span in the context", func(t *testing.T) {
+		exporter := record(t)
+
+		ctx, parent := trace.StartSpan(context.Background(), "parent")
+		_, child := trace.StartSpan(ctx, "child")
+		if child.Context().TraceID != parent.Context().TraceID {
+			t.Errorf("expected the span to be in the trace of its parent")
+		}
+
+		child.End(nil)
+		parent.End(nil)
+
+		if trace.FromContext(ctx) != parent {
+			t.Errorf("expected the context to carry the parent span")
+		}
+
+		if got := exporter.span(t, "child").ParentSpanID; got != exporter.spanID(t, "parent") {
+			t.Errorf("got parent %q of the child span want %q", got, exporter.spanID(t, "parent"))
+		}
+	})
+
+	t.Run("runs commands in spans", func(t *testing.T) {
+		exporter := record(t)
+
+		ctx, parent := trace.StartSpan(context.Background(), "substep")
+		if err := trace.Run(ctx, "true", exec.Command("true")); err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		err := trace.Run(ctx, "false", exec.Command("false"))
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Errorf("got error %#v want ExitError", err)
+		}
+
+		parent.End(nil)
+
+		if span := exporter.span(t, "exec true"); span.Error != "" || !strings.HasSuffix(span.Attributes["command"], "true") || span.ParentSpanID != exporter.spanID(t, "substep") {
+			t.Errorf("got span %+v", span)
+		}
+
+		if span := exporter.span(t, "exec false"); span.Error != "exit status 1" {
+			t.Errorf("got span %+v", span)
+		}
+	})
+}
+
+func TestInterceptors(t *testing.T) {
+	t.Run("server calls continue the trace of the caller", func(t *testing.T) {
+		exporter := record(t)
+
+		traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+
+		info := &grpc.UnaryServerInfo{FullMethod: "/idl.Agent/UpgradePrimaries"}
+		_, err := trace.UnaryServerInterceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			if trace.FromContext(ctx) == nil {
+				t.Errorf("expected the context to carry the call span while serving the call")
+			}
+
+			return nil, errors.New("oops")
+		})
+		if err == nil {
+			t.Errorf("expected an error")
+		}
+
+		span := exporter.span(t, info.FullMethod)
+		if span.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" || span.ParentSpanID != "00f067aa0ba902b7" || span.Kind != trace.KindServer || span.Error != "oops" {
+			t.Errorf("got span %+v", span)
+		}
+	})
+
+	t.Run("overlapping calls parent their own spans", func(t *testing.T) {
+		exporter := record(t)
+
+		// The first call runs its command only after the second call has
+		// started, and the second call finishes only after the first has.
+		firstStarted := make(chan struct{})
+		secondStarted := make(chan struct{})
+		firstDone := make(chan struct{})
+
+		call := func(method string, traceparent string, handler grpc.UnaryHandler) <-chan error {
+			errs := make(chan error, 1)
+			go func() {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("traceparent", traceparent))
+				_, err := trace.UnaryServerInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+				errs <- err
+			}()
+			return errs
+		}
+
+		first := call("/idl.Agent/First", "00-11111111111111111111111111111111-1111111111111111-01", func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(firstStarted)
+			<-secondStarted
+			defer close(firstDone)
+			return nil, trace.Run(ctx, "first", exec.Command("true"))
+		})
+
+		<-firstStarted
+		second := call("/idl.Agent/Second", "00-22222222222222222222222222222222-2222222222222222-01", func(ctx context.Context, req interface{}) (interface{}, error) {
+			close(secondStarted)
+			<-firstDone
+			return nil, trace.Run(ctx, "second", exec.Command("true"))
+		})
+
+		for _, errs := range []<-chan error{first, second} {
+			if err := <-errs; err != nil {
+				t.Errorf("unexpected error %+v", err)
+			}
+		}
+
+		for name, method := range map[string]string{"first": "/idl.Agent/First", "second": "/idl.Agent/Second"} {
+			span := exporter.span(t, "exec "+name)
+			if span.ParentSpanID != exporter.spanID(t, method) || span.TraceID != exporter.span(t, method).TraceID {
+				t.Errorf("got span %+v want a child of the %s call", span, method)
+			}
+		}
+	})
+
+	t.Run("client calls pass their trace context", func(t *testing.T) {
+		exporter := record(t)
+
+		cc, err := grpc.Dial("sdw1:6416", grpc.WithInsecure())
+		if err != nil {
+			t.Fatalf("unexpected error %+v", err)
+		}
+		defer cc.Close()
+
+		var traceparent []string
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			traceparent = md.Get("traceparent")
+			return nil
+		}
+
+		err = trace.UnaryClientInterceptor(context.Background(), "/idl.Agent/CheckDiskSpace", nil, nil, cc, invoker)
+		if err != nil {
+			t.Errorf("unexpected error %+v", err)
+		}
+
+		span := exporter.span(t, "/idl.Agent/CheckDiskSpace")
+		expected := []string{"00-" + span.TraceID + "-" + span.SpanID + "-01"}
+		if !reflect.DeepEqual(traceparent, expected) {
+			t.Errorf("got traceparent %q want %q", traceparent, expected)
+		}
+
+		if span.Kind != trace.KindClient || span.Attributes["rpc.target"] != "sdw1:6416" {
+			t.Errorf("got span %+v", span)
+		}
+	})
+}
+
+func (r *recordingExporter) span(t *testing.T, name string) trace.SpanData {
+	t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, span := range r.spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	t.Fatalf("no span named %q in %+v", name, r.spans)
+	return trace.SpanData{}
+}
+
+func (r *recordingExporter) spanID(t *testing.T, name string) string {
+	t.Helper()
+	return r.span(t, name).SpanID
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+// Package validation compares the user data of the source cluster with the
+// upgraded target cluster. Row counts, and optionally hashes of a sample of
+// the rows, are aggregated per segment so that the work is done in parallel
+// by the segments and mismatches can be traced to a segment.
+package validation
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"golang.org/x/xerrors"
+
+	"github.com/greenplum-db/gpupgrade/utils"
+	"github.com/greenplum-db/gpupgrade/utils/inventory"
+)
+
+// The data validation modes.
+const (
+	None          = "none"
+	RowCounts     = "row-counts"
+	SampledHashes = "sampled-hashes"
+)
+
+var Modes = []string{None, RowCounts, SampledHashes}
+
+// The names of the snapshots taken during the upgrade.
+const (
+	Source = "source"
+	Target = "target"
+)
+
+// SampleModulus selects roughly one in SampleModulus rows to hash in
+// SampledHashes mode.
+const SampleModulus = 100
+
+// ParseMode returns the data validation mode or an error if it is not one of
+// Modes.
+func ParseMode(input string) (string, error) {
+	mode := strings.ToLower(strings.TrimSpace(input))
+	for _, choice := range Modes {
+		if mode == choice {
+			return mode, nil
+		}
+	}
+
+	return "", fmt.Errorf("Invalid data validation mode %q. Please specify one of %s.", input, strings.Join(Modes, ", "))
+}
+
+// Snapshot is the per segment row counts, and in SampledHashes mode the
+// sampled row hashes, of every user table of a cluster at a point in time.
+type Snapshot struct {
+	Name    string    `json:"name"`
+	Mode    string    `json:"mode"`
+	Version string    `json:"version"`
+	Taken   time.Time `json:"taken"`
+	Tables  []Table   `json:"tables"`
+}
+
+// Table is the per segment tallies of a table. Name is the quoted
+// schema-qualified table name.
+type Table struct {
+	Database string  `json:"database"`
+	Name     string  `json:"name"`
+	Segments []Tally `json:"segments"`
+}
+
+// Tally is the row count and the sum of the sampled row hashes of a table on
+// a segment.
+type Tally struct {
+	Segment int32 `json:"segment"`
+	Rows    int64 `json:"rows"`
+	Hash    int64 `json:"hash,omitempty"`
+}
+
+// Partition roots and external tables are excluded since their rows are
+// stored in other tables or outside the cluster.
+const tablesQuery = `SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname)
+FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind = 'r' AND ` + inventory.UserNamespaces + `
+ORDER BY 1;`
+
+const tablesQuery6X = `SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname)
+FROM pg_class c
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE c.relkind = 'r' AND c.relstorage IN ('h', 'a', 'c')
+	AND c.oid NOT IN (SELECT parrelid FROM pg_partition)
+	AND ` + inventory.UserNamespaces + `
+ORDER BY 1;`
+
+const rowCountsQuery = `SELECT gp_segment_id, count(*), 0::bigint
+FROM %s
+GROUP BY 1 ORDER BY 1;`
+
+// The first 32 bits of the md5 of the text representation of a row are used
+// as its hash since hashtext() is not guaranteed to be stable across major
+// versions. The sample is summed with CASE rather than an aggregate FILTER
+// clause, which 5X does not support.
+const sampledHashesQuery = `SELECT gp_segment_id, count(*), coalesce(sum(CASE WHEN h %% %d = 0 THEN h ELSE 0 END), 0)::bigint
+FROM (SELECT gp_segment_id, ('x' || substr(md5(t::text), 1, 8))::bit(32)::int::bigint AS h FROM %s t) s
+GROUP BY 1 ORDER BY 1;`
+
+// QueryTables returns the user tables in the connected database.
+func QueryTables(db *sql.DB, version semver.Version) ([]string, error) {
+	query := tablesQuery
+	if version.Major < 7 {
+		query = tablesQuery6X
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, xerrors.Errorf("querying tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, xerrors.Errorf("scanning tables: %w", err)
+		}
+
+		tables = append(tables, table)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iterating tables: %w", err)
+	}
+
+	return tables, nil
+}
+
+// QueryTallies returns the per segment tallies of the table. Segments without
+// rows are omitted.
+func QueryTallies(db *sql.DB, table string, mode string) ([]Tally, error) {
+	query := fmt.Sprintf(rowCountsQuery, table)
+	if mode == SampledHashes {
+		query = fmt.Sprintf(sampledHashesQuery, SampleModulus, table)
+	}
+
+	rows, err := db.Query(query)
+	if err != nil {
+		return nil, xerrors.Errorf("querying table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var tallies []Tally
+	for rows.Next() {
+		var tally Tally
+		if err := rows.Scan(&tally.Segment, &tally.Rows, &tally.Hash); err != nil {
+			return nil, xerrors.Errorf("scanning table %s: %w", table, err)
+		}
+
+		tallies = append(tallies, tally)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, xerrors.Errorf("iterating table %s: %w", table, err)
+	}
+
+	return tallies, nil
+}
+
+// Path returns the path of the named snapshot in the validation directory.
+func Path(dir string, name string) string {
+	return filepath.Join(dir, name+".json")
+}
+
+func Save(dir string, snapshot Snapshot) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return xerrors.Errorf("marshal data snapshot: %w", err)
+	}
+
+	return utils.AtomicallyWrite(Path(dir, snapshot.Name), data)
+}
+
+func Load(path string) (Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, xerrors.Errorf("parsing data snapshot %s: %w", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// Mismatch is a table whose data differs between the source and target
+// snapshots. Segment is -1 when the table is missing from the target.
+type Mismatch struct {
+	Database string
+	Table    string
+	Segment  int32
+	Check    string
+	Source   int64
+	Target   int64
+}
+
+// Compare returns the mismatches between the source and target snapshots in
+// the order of the source tables. Sampled hashes are only compared when the
+// source snapshot is in SampledHashes mode. Tables created on the target
+// after the upgrade are ignored.
+func Compare(source, target Snapshot) []Mismatch {
+	targetTables := make(map[string]Table)
+	for _, table := range target.Tables {
+		targetTables[table.Database+"\x00"+table.Name] = table
+	}
+
+	var mismatches []Mismatch
+	for _, sourceTable := range source.Tables {
+		targetTable, ok := targetTables[sourceTable.Database+"\x00"+sourceTable.Name]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{
+				Database: sourceTable.Database,
+				Table:    sourceTable.Name,
+				Segment:  -1,
+				Check:    "table",
+				Source:   1,
+			})
+			continue
+		}
+
+		sourceTallies := tallies(sourceTable)
+		targetTallies := tallies(targetTable)
+		for _, segment := range segments(sourceTable, targetTable) {
+			s, t := sourceTallies[segment], targetTallies[segment]
+			mismatch := Mismatch{Database: sourceTable.Database, Table: sourceTable.Name, Segment: segment}
+
+			if s.Rows != t.Rows {
+				mismatch.Check, mismatch.Source, mismatch.Target = "rows", s.Rows, t.Rows
+				mismatches = append(mismatches, mismatch)
+			}
+
+			if source.Mode == SampledHashes && s.Hash != t.Hash {
+				mismatch.Check, mismatch.Source, mismatch.Target = "sampled hash", s.Hash, t.Hash
+				mismatches = append(mismatches, mismatch)
+			}
+		}
+	}
+
+	return mismatches
+}
+
+func tallies(table Table) map[int32]Tally {
+	tallies := make(map[int32]Tally)
+	for _, tally := range table.Segments {
+		tallies[tally.Segment] = tally
+	}
+
+	return tallies
+}
+
+// segments returns the segments of the source table followed by any segments
+// only in the target table.
+func segments(source, target Table) []int32 {
+	seen := make(map[int32]bool)
+	var segments []int32
+	for _, table := range []Table{source, target} {
+		for _, tally := range table.Segments {
+			if !seen[tally.Segment] {
+				seen[tally.Segment] = true
+				segments = append(segments, tally.Segment)
+			}
+		}
+	}
+
+	return segments
+}
+
+// Report returns a summary of the validation of the target snapshot against
+// the source snapshot, followed by a table of any mismatches.
+func Report(source, target Snapshot) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Validated %d tables of the %s (Greenplum %s, %s) against the %s (Greenplum %s, %s) using %s.\n",
+		len(source.Tables), target.Name, target.Version, target.Taken.Format(time.RFC3339),
+		source.Name, source.Version, source.Taken.Format(time.RFC3339), source.Mode)
+
+	mismatches := Compare(source, target)
+	if len(mismatches) == 0 {
+		checks := "row counts"
+		if source.Mode == SampledHashes {
+			checks = "row counts and sampled hashes"
+		}
+
+		fmt.Fprintf(&b, "\nAll %s match.\n", checks)
+		return b.String()
+	}
+
+	b.WriteString("\n")
+
+	var t tabwriter.Writer
+	t.Init(&b, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(&t, "Database\tTable\tSegment\tCheck\tSource\tTarget")
+	for _, m := range mismatches {
+		if m.Segment < 0 {
+			fmt.Fprintf(&t, "%s\t%s\t%s\t%s\t%s\t%s\n", m.Database, m.Table, "-", m.Check, "present", "missing")
+			continue
+		}
+
+		fmt.Fprintf(&t, "%s\t%s\t%d\t%s\t%d\t%d\n", m.Database, m.Table, m.Segment, m.Check, m.Source, m.Target)
+	}
+
+	t.Flush()
+
+	fmt.Fprintf(&b, "\n%d mismatches found.\n", len(mismatches))
+	return b.String()
+}
@@ -0,0 +1,307 @@
+// Copyright (c) 2017-2022 VMware, Inc. or its affiliates
+// SPDX-License-Identifier: Apache-2.0
+
+package validation_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/blang/semver/v4"
+
+	"github.com/greenplum-db/gpupgrade/testutils"
+	"github.com/greenplum-db/gpupgrade/utils/validation"
+)
+
+func TestParseMode(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"none", validation.None},
+		{"row-counts", validation.RowCounts},
+		{" Sampled-Hashes ", validation.SampledHashes},
+	}
+
+	for _, c := range cases {
+		mode, err := validation.ParseMode(c.input)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if mode != c.expected {
+			t.Errorf("ParseMode(%q) = %q want %q", c.input, mode, c.expected)
+		}
+	}
+
+	_, err := validation.ParseMode("checksums")
+	expected := `Invalid data validation mode "checksums"`
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("got error %v want it to contain %q", err, expected)
+	}
+}
+
+func TestQueryTables(t *testing.T) {
+	cases := []struct {
+		name    string
+		version semver.Version
+		query   string
+	}{
+		{
+			name:    "excludes partitioned tables by relkind for 7X",
+			version: semver.MustParse("7.0.0"),
+			query:   `WHERE c.relkind = 'r' AND n.nspname NOT IN`,
+		},
+		{
+			name:    "excludes partition roots and external tables for 6X",
+			version: semver.MustParse("6.20.0"),
+			query:   `c.relstorage IN \('h', 'a', 'c'\)\s+AND c.oid NOT IN \(SELECT parrelid FROM pg_partition\)`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("sqlmock: %v", err)
+			}
+			defer testutils.FinishMock(mock, t)
+
+			mock.ExpectQuery(c.query).
+				WillReturnRows(sqlmock.NewRows([]string{"table"}).AddRow("public.orders").AddRow(`"Sales".items`))
+
+			tables, err := validation.QueryTables(db, c.version)
+			if err != nil {
+				t.Errorf("unexpected error %#v", err)
+			}
+
+			expected := []string{"public.orders", `"Sales".items`}
+			if !reflect.DeepEqual(tables, expected) {
+				t.Errorf("got %v want %v", tables, expected)
+			}
+		})
+	}
+}
+
+func TestQueryTallies(t *testing.T) {
+	columns := []string{"gp_segment_id", "count", "hash"}
+
+	t.Run("counts the rows on each segment", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		mock.ExpectQuery(`SELECT gp_segment_id, count\(\*\), 0::bigint\s+FROM public.orders\s+GROUP BY 1`).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(0, 10, 0).AddRow(1, 12, 0))
+
+		tallies, err := validation.QueryTallies(db, "public.orders", validation.RowCounts)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := []validation.Tally{{Segment: 0, Rows: 10}, {Segment: 1, Rows: 12}}
+		if !reflect.DeepEqual(tallies, expected) {
+			t.Errorf("got %+v want %+v", tallies, expected)
+		}
+	})
+
+	t.Run("hashes a sample of the rows on each segment", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		mock.ExpectQuery(`sum\(CASE WHEN h % 100 = 0 THEN h ELSE 0 END\).*md5\(t::text\).*FROM public.orders t`).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(0, 10, -42))
+
+		tallies, err := validation.QueryTallies(db, "public.orders", validation.SampledHashes)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		expected := []validation.Tally{{Segment: 0, Rows: 10, Hash: -42}}
+		if !reflect.DeepEqual(tallies, expected) {
+			t.Errorf("got %+v want %+v", tallies, expected)
+		}
+	})
+
+	t.Run("hashes without aggregate FILTER clauses which 5X does not support", func(t *testing.T) {
+		var queries []string
+		matcher := sqlmock.QueryMatcherFunc(func(_, actual string) error {
+			queries = append(queries, actual)
+			return nil
+		})
+
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(matcher))
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		mock.ExpectQuery(`FROM public.orders`).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err = validation.QueryTallies(db, "public.orders", validation.SampledHashes)
+		if err != nil {
+			t.Errorf("unexpected error %#v", err)
+		}
+
+		if len(queries) == 0 {
+			t.Fatalf("expected a query")
+		}
+
+		for _, query := range queries {
+			if strings.Contains(strings.ToUpper(query), "FILTER") {
+				t.Errorf("expected query %q to not use FILTER", query)
+			}
+		}
+	})
+
+	t.Run("errors when the query fails", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("sqlmock: %v", err)
+		}
+		defer testutils.FinishMock(mock, t)
+
+		expected := errors.New("permission denied")
+		mock.ExpectQuery(`FROM public.orders`).WillReturnError(expected)
+
+		_, err = validation.QueryTallies(db, "public.orders", validation.RowCounts)
+		if !errors.Is(err, expected) {
+			t.Errorf("got error %#v want %#v", err, expected)
+		}
+	})
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := testutils.GetTempDir(t, "")
+	defer testutils.MustRemoveAll(t, dir)
+
+	snapshot := validation.Snapshot{
+		Name:    validation.Source,
+		Mode:    validation.SampledHashes,
+		Version: "7.0.0",
+		Taken:   time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tables: []validation.Table{
+			{Database: "postgres", Name: "public.orders", Segments: []validation.Tally{{Segment: 0, Rows: 10, Hash: 7}}},
+		},
+	}
+
+	err := validation.Save(dir, snapshot)
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	actual, err := validation.Load(validation.Path(dir, validation.Source))
+	if err != nil {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	if !reflect.DeepEqual(actual, snapshot) {
+		t.Errorf("got %+v want %+v", actual, snapshot)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	source := validation.Snapshot{
+		Mode: validation.SampledHashes,
+		Tables: []validation.Table{
+			{Database: "postgres", Name: "public.orders", Segments: []validation.Tally{{Segment: 0, Rows: 10, Hash: 7}, {Segment: 1, Rows: 5, Hash: 3}}},
+			{Database: "sales", Name: "public.items", Segments: []validation.Tally{{Segment: 0, Rows: 1}}},
+		},
+	}
+
+	t.Run("returns no mismatches for matching snapshots", func(t *testing.T) {
+		if mismatches := validation.Compare(source, source); len(mismatches) != 0 {
+			t.Errorf("got mismatches %+v want none", mismatches)
+		}
+	})
+
+	t.Run("returns the mismatches per segment", func(t *testing.T) {
+		target := validation.Snapshot{
+			Tables: []validation.Table{
+				{Database: "postgres", Name: "public.orders", Segments: []validation.Tally{{Segment: 0, Rows: 10, Hash: 8}, {Segment: 2, Rows: 5, Hash: 3}}},
+				{Database: "postgres", Name: "public.created_after_upgrade", Segments: []validation.Tally{{Segment: 0, Rows: 1}}},
+			},
+		}
+
+		expected := []validation.Mismatch{
+			{Database: "postgres", Table: "public.orders", Segment: 0, Check: "sampled hash", Source: 7, Target: 8},
+			{Database: "postgres", Table: "public.orders", Segment: 1, Check: "rows", Source: 5, Target: 0},
+			{Database: "postgres", Table: "public.orders", Segment: 1, Check: "sampled hash", Source: 3, Target: 0},
+			{Database: "postgres", Table: "public.orders", Segment: 2, Check: "rows", Source: 0, Target: 5},
+			{Database: "postgres", Table: "public.orders", Segment: 2, Check: "sampled hash", Source: 0, Target: 3},
+			{Database: "sales", Table: "public.items", Segment: -1, Check: "table", Source: 1, Target: 0},
+		}
+
+		mismatches := validation.Compare(source, target)
+		if !reflect.DeepEqual(mismatches, expected) {
+			t.Errorf("got %+v want %+v", mismatches, expected)
+		}
+	})
+
+	t.Run("ignores hashes when only row counts were taken", func(t *testing.T) {
+		rowCounts := source
+		rowCounts.Mode = validation.RowCounts
+
+		target := validation.Snapshot{
+			Tables: []validation.Table{
+				{Database: "postgres", Name: "public.orders", Segments: []validation.Tally{{Segment: 0, Rows: 10}, {Segment: 1, Rows: 5}}},
+				{Database: "sales", Name: "public.items", Segments: []validation.Tally{{Segment: 0, Rows: 1}}},
+			},
+		}
+
+		if mismatches := validation.Compare(rowCounts, target); len(mismatches) != 0 {
+			t.Errorf("got mismatches %+v want none", mismatches)
+		}
+	})
+}
+
+func TestReport(t *testing.T) {
+	source := validation.Snapshot{
+		Name:    validation.Source,
+		Mode:    validation.RowCounts,
+		Version: "7.0.0",
+		Taken:   time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tables: []validation.Table{
+			{Database: "postgres", Name: "public.orders", Segments: []validation.Tally{{Segment: 0, Rows: 10}}},
+			{Database: "sales", Name: "public.items", Segments: []validation.Tally{{Segment: 0, Rows: 1}}},
+		},
+	}
+
+	target := validation.Snapshot{
+		Name:    validation.Target,
+		Version: "7.1.0",
+		Taken:   time.Date(2022, 1, 2, 4, 4, 5, 0, time.UTC),
+		Tables: []validation.Table{
+			{Database: "postgres", Name: "public.orders", Segments: []validation.Tally{{Segment: 0, Rows: 9}}},
+		},
+	}
+
+	report := validation.Report(source, target)
+
+	expected := `Validated 2 tables of the target (Greenplum 7.1.0, 2022-01-02T04:04:05Z) against the source (Greenplum 7.0.0, 2022-01-02T03:04:05Z) using row-counts.
+
+Database  Table          Segment  Check  Source   Target
+postgres  public.orders  0        rows   10       9
+sales     public.items   -        table  present  missing
+
+2 mismatches found.
+`
+	if report != expected {
+		t.Errorf("got report %q want %q", report, expected)
+	}
+
+	report = validation.Report(source, source)
+	if !strings.Contains(report, "All row counts match.") {
+		t.Errorf("expected report to contain %q\n%s", "All row counts match.", report)
+	}
+}